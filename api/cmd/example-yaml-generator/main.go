@@ -149,11 +149,21 @@ func createExampleSeed() *kubermaticv1.Seed {
 						GCP: &kubermaticv1.DatacenterSpecGCP{
 							ZoneSuffixes: []string{},
 						},
-						Kubevirt: &kubermaticv1.DatacenterSpecKubevirt{},
-						Alibaba:  &kubermaticv1.DatacenterSpecAlibaba{},
+						Kubevirt:     &kubermaticv1.DatacenterSpecKubevirt{},
+						Alibaba:      &kubermaticv1.DatacenterSpecAlibaba{},
+						BackupPolicy: &kubermaticv1.EtcdBackupPolicy{},
 					},
 				},
 			},
+			BackupDestinations: map[string]kubermaticv1.BackupDestination{
+				"<<exampledestination>>": {},
+			},
+			AddonSources: map[string]kubermaticv1.AddonSource{
+				"<<exampleaddon>>": {
+					OCI: &kubermaticv1.OCIAddonSource{},
+					Git: &kubermaticv1.GitAddonSource{},
+				},
+			},
 			ProxySettings: &proxySettings,
 		},
 	}
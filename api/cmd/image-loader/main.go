@@ -330,6 +330,8 @@ func getTemplateData(version *kubermaticversion.Version) (*resources.TemplateDat
 		resources.ApiserverEtcdClientCertificateSecretName,
 		resources.ApiserverFrontProxyClientCertificateSecretName,
 		resources.EtcdTLSCertificateSecretName,
+		resources.EtcdPeerCASecretName,
+		resources.EtcdPeerTLSCertificateSecretName,
 		resources.MachineControllerKubeconfigSecretName,
 		resources.ControllerManagerKubeconfigSecretName,
 		resources.SchedulerKubeconfigSecretName,
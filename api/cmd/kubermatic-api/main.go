@@ -56,7 +56,9 @@ import (
 	kubermaticv1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
 	"github.com/kubermatic/kubermatic/api/pkg/features"
 	"github.com/kubermatic/kubermatic/api/pkg/handler"
+	"github.com/kubermatic/kubermatic/api/pkg/handler/audit"
 	"github.com/kubermatic/kubermatic/api/pkg/handler/auth"
+	"github.com/kubermatic/kubermatic/api/pkg/handler/middleware"
 	"github.com/kubermatic/kubermatic/api/pkg/handler/v1/common"
 	kubermaticlog "github.com/kubermatic/kubermatic/api/pkg/log"
 	metricspkg "github.com/kubermatic/kubermatic/api/pkg/metrics"
@@ -76,6 +78,7 @@ import (
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/klog"
 	"k8s.io/metrics/pkg/apis/metrics/v1beta1"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 )
 
@@ -196,6 +199,19 @@ func createInitProviders(options serverRunOptions) (providers, error) {
 		return providers{}, err
 	}
 	admissionPluginProvider := kubernetesprovider.NewAdmissionPluginsProvider(context.Background(), mgr.GetClient())
+	clusterTemplateProvider := kubernetesprovider.NewClusterTemplateProvider(context.Background(), mgr.GetClient())
+	auditSink, err := createAuditSink(options, mgr.GetClient(), mgr.GetAPIReader())
+	if err != nil {
+		return providers{}, err
+	}
+	clusterCache := kubernetesprovider.NewClusterCache(seedsGetter, func(seed *kubermaticv1.Seed) (kubermaticclientset.Interface, error) {
+		cfg, err := seedKubeconfigGetter(seed)
+		if err != nil {
+			return nil, err
+		}
+		return kubermaticclientset.NewForConfig(cfg)
+	})
+	go clusterCache.Run(context.Background(), time.Minute, kubermaticlog.Logger)
 	// Warm up the restMapper cache. Log but ignore errors encountered here, maybe there are stale seeds
 	go func() {
 		seeds, err := seedsGetter()
@@ -227,6 +243,7 @@ func createInitProviders(options serverRunOptions) (providers, error) {
 
 	serviceAccountProvider := kubernetesprovider.NewServiceAccountProvider(defaultImpersonationClient.CreateImpersonatedClient, mgr.GetClient(), options.domain)
 	projectMemberProvider := kubernetesprovider.NewProjectMemberProvider(defaultImpersonationClient.CreateImpersonatedClient, mgr.GetClient(), kubernetesprovider.IsServiceAccount)
+	groupProjectBindingProvider := kubernetesprovider.NewGroupProjectBindingProvider(mgr.GetClient(), kubernetesprovider.IsServiceAccount)
 	projectProvider, err := kubernetesprovider.NewProjectProvider(defaultImpersonationClient.CreateImpersonatedClient, mgr.GetClient())
 	if err != nil {
 		return providers{}, fmt.Errorf("failed to create project provider due to %v", err)
@@ -249,7 +266,9 @@ func createInitProviders(options serverRunOptions) (providers, error) {
 
 	eventRecorderProvider := kubernetesprovider.NewEventRecorder()
 
-	addonProviderGetter := kubernetesprovider.AddonProviderFactory(mgr.GetRESTMapper(), seedKubeconfigGetter, options.accessibleAddons)
+	addonProviderGetter := kubernetesprovider.AddonProviderFactory(mgr.GetRESTMapper(), seedKubeconfigGetter, options.accessibleAddons, addonConfigProvider)
+
+	etcdRestoreProviderGetter := kubernetesprovider.EtcdRestoreProviderFactory(mgr.GetRESTMapper(), seedKubeconfigGetter)
 
 	settingsWatcher, err := kuberneteswatcher.NewSettingsWatcher(settingsProvider)
 	if err != nil {
@@ -275,15 +294,47 @@ func createInitProviders(options serverRunOptions) (providers, error) {
 		seedClientGetter:                      seedClientGetter,
 		addons:                                addonProviderGetter,
 		addonConfigProvider:                   addonConfigProvider,
+		etcdRestores:                          etcdRestoreProviderGetter,
 		userInfoGetter:                        userInfoGetter,
 		settingsProvider:                      settingsProvider,
 		adminProvider:                         adminProvider,
 		presetProvider:                        presetsProvider,
 		admissionPluginProvider:               admissionPluginProvider,
+		clusterTemplateProvider:               clusterTemplateProvider,
+		groupProjectBindingProvider:           groupProjectBindingProvider,
+		auditSink:                             auditSink,
+		projectClusterLister:                  clusterCache,
 		settingsWatcher:                       settingsWatcher,
 	}, nil
 }
 
+// createAuditSink returns the sink the audit records are written to, nil if the audit trail is disabled.
+// The records are written asynchronously, the expired records of the "crd" sink are deleted in the background.
+func createAuditSink(options serverRunOptions, client ctrlruntimeclient.Client, apiReader ctrlruntimeclient.Reader) (audit.Sink, error) {
+	var sink audit.Sink
+	switch options.auditSink {
+	case "":
+		return nil, nil
+	case audit.SinkCRD:
+		crdSink := audit.NewCRDSink(client, apiReader)
+		if options.auditRetention > 0 {
+			go crdSink.RunRetention(options.auditRetention, time.Hour, kubermaticlog.Logger, wait.NeverStop)
+		}
+		sink = crdSink
+	case audit.SinkFile:
+		fileSink, err := audit.NewFileSink(options.auditFile)
+		if err != nil {
+			return nil, err
+		}
+		sink = fileSink
+	case audit.SinkWebhook:
+		sink = audit.NewWebhookSink(options.auditWebhookURL)
+	default:
+		return nil, fmt.Errorf("unknown audit sink %q", options.auditSink)
+	}
+	return audit.NewAsyncSink(sink, options.auditQueueSize, kubermaticlog.Logger, wait.NeverStop), nil
+}
+
 func createOIDCClients(options serverRunOptions) (auth.OIDCIssuerVerifier, error) {
 	return auth.NewOpenIDClient(
 		options.oidcURL,
@@ -354,6 +405,7 @@ func createAPIHandler(options serverRunOptions, prov providers, oidcIssuerVerifi
 		prov.clusterProviderGetter,
 		prov.addons,
 		prov.addonConfigProvider,
+		prov.etcdRestores,
 		prov.sshKey,
 		prov.privilegedSSHKeyProvider,
 		prov.user,
@@ -380,6 +432,15 @@ func createAPIHandler(options serverRunOptions, prov providers, oidcIssuerVerifi
 		prov.settingsProvider,
 		prov.adminProvider,
 		prov.admissionPluginProvider,
+		prov.clusterTemplateProvider,
+		prov.groupProjectBindingProvider,
+		prov.auditSink,
+		middleware.NewRateLimiter(middleware.RateLimiterOptions{
+			Rates:                 options.rateLimits,
+			MaxConcurrentRequests: options.maxConcurrentRequestsPerUser,
+			RejectedRequests:      metrics.RateLimitedRequests,
+		}),
+		prov.projectClusterLister,
 		prov.settingsWatcher,
 	)
 
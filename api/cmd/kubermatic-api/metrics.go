@@ -46,6 +46,13 @@ var metrics = common.ServerMetrics{
 		},
 		[]string{"cluster", "datacenter"},
 	),
+	RateLimitedRequests: prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "kubermatic_api_rate_limited_requests_total",
+			Help: "The number of requests rejected because a user exceeded the rate limit or the limit of concurrent requests",
+		},
+		[]string{"group", "reason"},
+	),
 }
 
 // registerMetrics registers metrics for the API.
@@ -53,6 +60,7 @@ func registerMetrics() {
 	prometheus.MustRegister(metrics.HTTPRequestsTotal)
 	prometheus.MustRegister(metrics.HTTPRequestsDuration)
 	prometheus.MustRegister(metrics.InitNodeDeploymentFailures)
+	prometheus.MustRegister(metrics.RateLimitedRequests)
 }
 
 // RouteLookupFunc is a delegate for getting a unique identifier for the route which matches the passed request.
@@ -22,8 +22,11 @@ import (
 	"fmt"
 	"io/ioutil"
 	"strings"
+	"time"
 
 	"github.com/kubermatic/kubermatic/api/pkg/features"
+	"github.com/kubermatic/kubermatic/api/pkg/handler/audit"
+	"github.com/kubermatic/kubermatic/api/pkg/handler/middleware"
 	kubermaticlog "github.com/kubermatic/kubermatic/api/pkg/log"
 	"github.com/kubermatic/kubermatic/api/pkg/provider"
 	"github.com/kubermatic/kubermatic/api/pkg/serviceaccount"
@@ -66,6 +69,17 @@ type serverRunOptions struct {
 	//service account configuration
 	serviceAccountSigningKey string
 
+	// audit trail configuration
+	auditSink       string
+	auditFile       string
+	auditWebhookURL string
+	auditQueueSize  int
+	auditRetention  time.Duration
+
+	// rate limiting configuration
+	rateLimits                   map[string]middleware.Rate
+	maxConcurrentRequestsPerUser int
+
 	featureGates features.FeatureGate
 }
 
@@ -76,6 +90,7 @@ func newServerRunOptions() (serverRunOptions, error) {
 		rawExposeStrategy   string
 		rawAccessibleAddons string
 		oidcCAFile          string
+		rawRateLimits       string
 	)
 
 	s.log = kubermaticlog.NewDefaultOptions()
@@ -108,6 +123,13 @@ func newServerRunOptions() (serverRunOptions, error) {
 	flag.StringVar(&rawExposeStrategy, "expose-strategy", "NodePort", "The strategy to expose the controlplane with, either \"NodePort\" which creates NodePorts with a \"nodeport-proxy.k8s.io/expose: true\" annotation or \"LoadBalancer\", which creates a LoadBalancer")
 	flag.BoolVar(&s.dynamicPresets, "dynamic-presets", false, "Whether to enable dynamic presets")
 	flag.StringVar(&s.namespace, "namespace", "kubermatic", "The namespace kubermatic runs in, uses to determine where to look for datacenter custom resources")
+	flag.StringVar(&s.auditSink, "audit-sink", "", "Where to record the audit trail of the API mutations, either \"crd\", \"file\" or \"webhook\". The audit trail is disabled if empty")
+	flag.StringVar(&s.auditFile, "audit-file", "", "The path of the file the audit records are appended to, required by the \"file\" audit sink")
+	flag.StringVar(&s.auditWebhookURL, "audit-webhook-url", "", "The URL the audit records are sent to, required by the \"webhook\" audit sink")
+	flag.IntVar(&s.auditQueueSize, "audit-queue-size", 1000, "The number of audit records that are queued for the audit sink, records are dropped when the queue is full")
+	flag.DurationVar(&s.auditRetention, "audit-retention", 90*24*time.Hour, "How long the records of the \"crd\" audit sink are kept, 0 means forever")
+	flag.StringVar(&rawRateLimits, "rate-limits", "", "Comma-separated list of token bucket rates per user and endpoint group in the form group=qps:burst, e.g. \"default=20:40,providers=1:5,clusters=2:10\". The groups are \"default\", \"providers\" and \"clusters\", the groups without a rate use the default one. Requests are not rate limited if empty")
+	flag.IntVar(&s.maxConcurrentRequestsPerUser, "max-concurrent-requests-per-user", 0, "The maximum number of requests a single user or service account can have in flight, 0 means no limit")
 	addFlags(flag.CommandLine)
 	flag.Parse()
 
@@ -126,6 +148,11 @@ func newServerRunOptions() (serverRunOptions, error) {
 		return s, fmt.Errorf("--expose-strategy must be either `NodePort` or `LoadBalancer`, got %q", rawExposeStrategy)
 	}
 
+	s.rateLimits, err = middleware.ParseRates(rawRateLimits)
+	if err != nil {
+		return s, fmt.Errorf("invalid --rate-limits: %v", err)
+	}
+
 	s.accessibleAddons = sets.NewString(strings.Split(rawAccessibleAddons, ",")...)
 	s.accessibleAddons.Delete("")
 
@@ -166,6 +193,30 @@ func (o serverRunOptions) validate() error {
 		}
 	}
 
+	switch o.auditSink {
+	case "", audit.SinkCRD:
+	case audit.SinkFile:
+		if len(o.auditFile) == 0 {
+			return fmt.Errorf("the %q audit sink requires the \"audit-file\" flag", o.auditSink)
+		}
+	case audit.SinkWebhook:
+		if len(o.auditWebhookURL) == 0 {
+			return fmt.Errorf("the %q audit sink requires the \"audit-webhook-url\" flag", o.auditSink)
+		}
+	default:
+		return fmt.Errorf("--audit-sink must be either `crd`, `file` or `webhook`, got %q", o.auditSink)
+	}
+	if o.auditQueueSize < 1 {
+		return fmt.Errorf("--audit-queue-size must be positive, got %d", o.auditQueueSize)
+	}
+	if o.auditRetention < 0 {
+		return fmt.Errorf("--audit-retention must not be negative, got %v", o.auditRetention)
+	}
+
+	if o.maxConcurrentRequestsPerUser < 0 {
+		return fmt.Errorf("--max-concurrent-requests-per-user must not be negative, got %d", o.maxConcurrentRequestsPerUser)
+	}
+
 	if err := serviceaccount.ValidateKey([]byte(o.serviceAccountSigningKey)); err != nil {
 		return fmt.Errorf("the service-account-signing-key is incorrect due to error: %v", err)
 	}
@@ -192,10 +243,15 @@ type providers struct {
 	seedClientGetter                      provider.SeedClientGetter
 	addons                                provider.AddonProviderGetter
 	addonConfigProvider                   provider.AddonConfigProvider
+	etcdRestores                          provider.EtcdRestoreProviderGetter
 	userInfoGetter                        provider.UserInfoGetter
 	settingsProvider                      provider.SettingsProvider
 	adminProvider                         provider.AdminProvider
 	presetProvider                        provider.PresetProvider
 	admissionPluginProvider               provider.AdmissionPluginsProvider
+	clusterTemplateProvider               provider.ClusterTemplateProvider
+	groupProjectBindingProvider           provider.GroupProjectBindingProvider
+	auditSink                             audit.Sink
+	projectClusterLister                  provider.ProjectClusterLister
 	settingsWatcher                       watcher.SettingsWatcher
 }
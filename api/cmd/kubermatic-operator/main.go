@@ -50,6 +50,9 @@ type controllerRunOptions struct {
 	internalAddr string
 	workerCount  int
 	workerName   string
+
+	dryRun          bool
+	dryRunReportDir string
 }
 
 func main() {
@@ -68,6 +71,8 @@ func main() {
 	flag.IntVar(&opt.workerCount, "worker-count", 4, "Number of workers which process reconcilings in parallel.")
 	flag.StringVar(&opt.internalAddr, "internal-address", "127.0.0.1:8085", "The address on which the /metrics endpoint will be served")
 	flag.StringVar(&opt.workerName, "worker-name", "", "The name of the worker that will only processes resources with label=worker-name.")
+	flag.BoolVar(&opt.dryRun, "dry-run", false, "Only report the changes the reconciliation would make as events instead of applying them.")
+	flag.StringVar(&opt.dryRunReportDir, "dry-run-report-dir", "", "Directory into which a JSON report of the changes is written per KubermaticConfiguration and Seed in dry-run mode")
 	flag.Parse()
 
 	rawLog := kubermaticlog.New(logOpts.Debug, logOpts.Format).Named(opt.workerName)
@@ -88,6 +93,10 @@ func main() {
 		log.Fatal("-namespace is a mandatory flag")
 	}
 
+	if opt.dryRunReportDir != "" && !opt.dryRun {
+		log.Fatal("-dry-run-report-dir can only be used together with -dry-run")
+	}
+
 	log.With("kubermatic", common.KUBERMATICDOCKERTAG, "ui", common.UIDOCKERTAG).Infof("Moin, moin, I'm the Kubermatic %s Operator and these are the versions I work with.", resources.KubermaticEdition)
 
 	config, err := clientcmd.BuildConfigFromFlags("", opt.kubeconfig)
@@ -122,7 +131,7 @@ func main() {
 		log.Fatalw("Failed to construct seedKubeconfigGetter", zap.Error(err))
 	}
 
-	if err := masterctrl.Add(ctx, mgr, log, opt.namespace, opt.workerCount, opt.workerName); err != nil {
+	if err := masterctrl.Add(ctx, mgr, log, opt.namespace, opt.workerCount, opt.workerName, opt.dryRun, opt.dryRunReportDir); err != nil {
 		log.Fatalw("Failed to add operator-master controller", zap.Error(err))
 	}
 
@@ -136,6 +145,8 @@ func main() {
 			seedsGetter,
 			opt.workerCount,
 			opt.workerName,
+			opt.dryRun,
+			opt.dryRunReportDir,
 		)
 	}
 
@@ -67,9 +67,15 @@ func main() {
 		Value: "/backup/snapshot.db",
 		Usage: "Path to the file to store in S3",
 	}
+	objectFlag := cli.StringFlag{
+		Name:  "object, o",
+		Value: "",
+		Usage: "Name of the object to download from S3",
+	}
 	secureFlag := cli.BoolFlag{
-		Name:  "secure",
-		Usage: "Enable tls validation",
+		Name:   "secure",
+		EnvVar: "BACKUP_SECURE",
+		Usage:  "Enable tls validation",
 	}
 	createBucketFlag := cli.BoolFlag{
 		Name:  "create-bucket",
@@ -114,6 +120,20 @@ func main() {
 				createBucketFlag,
 			},
 		},
+		{
+			Name:   "download",
+			Usage:  "Downloads the given object from S3 into file",
+			Action: download,
+			Flags: []cli.Flag{
+				endpointFlag,
+				secureFlag,
+				accessKeyIDFlag,
+				secretAccessKeyFlag,
+				bucketFlag,
+				objectFlag,
+				fileFlag,
+			},
+		},
 		{
 			Name:   "delete-old-revisions",
 			Usage:  "Deletes backups which are older than max-revisions",
@@ -198,6 +218,18 @@ func store(c *cli.Context) error {
 		c.Bool("create-bucket"),
 	)
 }
+func download(c *cli.Context) error {
+	uploader, err := getUploaderFromCtx(c)
+	if err != nil {
+		return err
+	}
+
+	return uploader.Download(
+		c.String("bucket"),
+		c.String("object"),
+		c.String("file"),
+	)
+}
 func deleteOldRevisions(c *cli.Context) error {
 	uploader, err := getUploaderFromCtx(c)
 	if err != nil {
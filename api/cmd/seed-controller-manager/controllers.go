@@ -26,8 +26,11 @@ import (
 	"github.com/kubermatic/kubermatic/api/pkg/controller/seed-controller-manager/addon"
 	"github.com/kubermatic/kubermatic/api/pkg/controller/seed-controller-manager/addoninstaller"
 	backupcontroller "github.com/kubermatic/kubermatic/api/pkg/controller/seed-controller-manager/backup"
+	certificatescontroller "github.com/kubermatic/kubermatic/api/pkg/controller/seed-controller-manager/certificates"
 	cloudcontroller "github.com/kubermatic/kubermatic/api/pkg/controller/seed-controller-manager/cloud"
 	"github.com/kubermatic/kubermatic/api/pkg/controller/seed-controller-manager/clustercomponentdefaulter"
+	"github.com/kubermatic/kubermatic/api/pkg/controller/seed-controller-manager/encryptionatrest"
+	"github.com/kubermatic/kubermatic/api/pkg/controller/seed-controller-manager/etcdrestore"
 	kubernetescontroller "github.com/kubermatic/kubermatic/api/pkg/controller/seed-controller-manager/kubernetes"
 	"github.com/kubermatic/kubermatic/api/pkg/controller/seed-controller-manager/monitoring"
 	openshiftcontroller "github.com/kubermatic/kubermatic/api/pkg/controller/seed-controller-manager/openshift"
@@ -52,18 +55,29 @@ var AllControllers = map[string]controllerCreator{
 	addon.ControllerName:                          createAddonController,
 	addoninstaller.ControllerName:                 createAddonInstallerController,
 	backupcontroller.ControllerName:               createBackupController,
+	etcdrestore.ControllerName:                    createEtcdRestoreController,
 	monitoring.ControllerName:                     createMonitoringController,
 	cloudcontroller.ControllerName:                createCloudController,
 	openshiftcontroller.ControllerName:            createOpenshiftController,
 	clustercomponentdefaulter.ControllerName:      createClusterComponentDefaulter,
 	seedresourcesuptodatecondition.ControllerName: createSeedConditionUpToDateController,
 	rancher.ControllerName:                        createRancherController,
+	certificatescontroller.ControllerName:         createCertificateController,
+	encryptionatrest.ControllerName:               createEncryptionAtRestController,
 }
 
 type controllerCreator func(*controllerContext) error
 
 func createAllControllers(ctrlCtx *controllerContext) error {
-	for name, create := range AllControllers {
+	controllers := AllControllers
+	if ctrlCtx.runOptions.dryRun {
+		// Only the cluster controller supports the dry-run, all others would write
+		controllers = map[string]controllerCreator{
+			kubernetescontroller.ControllerName: createKubernetesController,
+		}
+	}
+
+	for name, create := range controllers {
 		if err := create(ctrlCtx); err != nil {
 			return fmt.Errorf("failed to create %q controller: %v", name, err)
 		}
@@ -91,6 +105,8 @@ func createClusterComponentDefaulter(ctrlCtx *controllerContext) error {
 			Replicas: utilpointer.Int32Ptr(int32(ctrlCtx.runOptions.controllerManagerDefaultReplicas))},
 		Scheduler: kubermaticv1.DeploymentSettings{
 			Replicas: utilpointer.Int32Ptr(int32(ctrlCtx.runOptions.schedulerDefaultReplicas))},
+		Etcd: kubermaticv1.EtcdStatefulSetSettings{
+			ClusterSize: utilpointer.Int32Ptr(int32(ctrlCtx.runOptions.etcdDefaultClusterSize))},
 	}
 	return clustercomponentdefaulter.Add(
 		context.Background(),
@@ -167,6 +183,8 @@ func createKubernetesController(ctrlCtx *controllerContext) error {
 		ctrlCtx.runOptions.oidcIssuerClientID,
 		ctrlCtx.runOptions.kubermaticImage,
 		ctrlCtx.runOptions.dnatControllerImage,
+		ctrlCtx.runOptions.dryRun,
+		ctrlCtx.runOptions.dryRunReportDir,
 		kubernetescontroller.Features{
 			VPA:                          ctrlCtx.runOptions.featureGates.Enabled(features.VerticalPodAutoscaler),
 			EtcdDataCorruptionChecks:     ctrlCtx.runOptions.featureGates.Enabled(features.EtcdDataCorruptionChecks),
@@ -197,6 +215,48 @@ func createBackupController(ctrlCtx *controllerContext) error {
 		*cleanupContainer,
 		backupInterval,
 		ctrlCtx.runOptions.backupContainerImage,
+		ctrlCtx.seedGetter,
+	)
+}
+
+func createCertificateController(ctrlCtx *controllerContext) error {
+	return certificatescontroller.Add(
+		ctrlCtx.log,
+		ctrlCtx.mgr,
+		ctrlCtx.runOptions.workerCount,
+		ctrlCtx.runOptions.workerName,
+		ctrlCtx.runOptions.certificateRotationThreshold,
+	)
+}
+
+func createEncryptionAtRestController(ctrlCtx *controllerContext) error {
+	return encryptionatrest.Add(
+		ctrlCtx.log,
+		ctrlCtx.mgr,
+		ctrlCtx.runOptions.workerCount,
+		ctrlCtx.runOptions.workerName,
+		ctrlCtx.clientProvider,
+		ctrlCtx.runOptions.encryptionKeyRetention,
+	)
+}
+
+func createEtcdRestoreController(ctrlCtx *controllerContext) error {
+	if ctrlCtx.runOptions.restoreContainerFile == "" {
+		ctrlCtx.log.Infof("Not starting %s, restore-container is undefined", etcdrestore.ControllerName)
+		return nil
+	}
+	restoreContainer, err := getContainerFromFile(ctrlCtx.runOptions.restoreContainerFile)
+	if err != nil {
+		return err
+	}
+	return etcdrestore.Add(
+		ctrlCtx.log,
+		ctrlCtx.mgr,
+		ctrlCtx.runOptions.workerCount,
+		ctrlCtx.runOptions.workerName,
+		*restoreContainer,
+		ctrlCtx.runOptions.backupContainerImage,
+		ctrlCtx.seedGetter,
 	)
 }
 
@@ -278,9 +338,11 @@ func createAddonController(ctrlCtx *controllerContext) error {
 		},
 		ctrlCtx.runOptions.kubernetesAddonsPath,
 		ctrlCtx.runOptions.openshiftAddonsPath,
+		ctrlCtx.runOptions.addonSourceCachePath,
 		ctrlCtx.runOptions.overwriteRegistry,
 		ctrlCtx.runOptions.nodeLocalDNSCacheEnabled(),
 		ctrlCtx.clientProvider,
+		ctrlCtx.seedGetter,
 	)
 }
 
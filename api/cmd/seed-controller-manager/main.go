@@ -211,6 +211,8 @@ Please install the VerticalPodAutoscaler according to the documentation: https:/
 	collectors.MustRegisterClusterCollector(prometheus.DefaultRegisterer, ctrlCtx.mgr.GetAPIReader())
 	log.Debug("Starting addons collector")
 	collectors.MustRegisterAddonCollector(prometheus.DefaultRegisterer, ctrlCtx.mgr.GetAPIReader())
+	log.Debug("Starting certificates collector")
+	collectors.MustRegisterCertificateCollector(prometheus.DefaultRegisterer, ctrlCtx.mgr.GetAPIReader())
 
 	var g run.Group
 	// This group is forever waiting in a goroutine for signals to stop
@@ -253,13 +255,19 @@ Please install the VerticalPodAutoscaler according to the documentation: https:/
 			if options.workerName != "" {
 				electionName += "-" + options.workerName
 			}
+			// A dry-run must not keep the regular controller-manager from running
+			if options.dryRun {
+				electionName += "-dry-run"
+			}
 
 			return leaderelection.RunAsLeader(leaderCtx, log, config, mgr.GetEventRecorderFor(controllerName), electionName, func(ctx context.Context) error {
-				log.Info("Executing migrations...")
-				if err := seedmigrations.RunAll(leaderCtx, ctrlCtx.mgr.GetConfig(), options.workerName); err != nil {
-					return fmt.Errorf("failed to run migrations: %v", err)
+				if !options.dryRun {
+					log.Info("Executing migrations...")
+					if err := seedmigrations.RunAll(leaderCtx, ctrlCtx.mgr.GetConfig(), options.workerName); err != nil {
+						return fmt.Errorf("failed to run migrations: %v", err)
+					}
+					log.Info("Migrations executed successfully")
 				}
-				log.Info("Migrations executed successfully")
 
 				log.Info("Starting the controller-manager...")
 				if err := mgr.Start(ctx.Done()); err != nil {
@@ -23,14 +23,18 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/url"
+	"os"
 	"path"
 	"strings"
+	"time"
 
 	"go.uber.org/zap"
 
 	"github.com/kubermatic/kubermatic/api/pkg/cluster/client"
 	"github.com/kubermatic/kubermatic/api/pkg/controller/operator/common"
 	backupcontroller "github.com/kubermatic/kubermatic/api/pkg/controller/seed-controller-manager/backup"
+	certificatescontroller "github.com/kubermatic/kubermatic/api/pkg/controller/seed-controller-manager/certificates"
+	"github.com/kubermatic/kubermatic/api/pkg/controller/seed-controller-manager/encryptionatrest"
 	kubermaticv1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
 	"github.com/kubermatic/kubermatic/api/pkg/features"
 	"github.com/kubermatic/kubermatic/api/pkg/provider"
@@ -61,10 +65,12 @@ type controllerRunOptions struct {
 	nodeAccessNetwork                                string
 	kubernetesAddonsPath                             string
 	openshiftAddonsPath                              string
+	addonSourceCachePath                             string
 	kubernetesAddons                                 kubermaticv1.AddonList
 	openshiftAddons                                  kubermaticv1.AddonList
 	backupContainerFile                              string
 	cleanupContainerFile                             string
+	restoreContainerFile                             string
 	backupContainerImage                             string
 	backupInterval                                   string
 	etcdDiskSize                                     resource.Quantity
@@ -81,9 +87,14 @@ type controllerRunOptions struct {
 	apiServerEndpointReconcilingDisabled             bool
 	controllerManagerDefaultReplicas                 int
 	schedulerDefaultReplicas                         int
+	etcdDefaultClusterSize                           int
 	seedValidationHook                               seedvalidation.WebhookOpts
 	concurrentClusterUpdate                          int
 	addonEnforceInterval                             int
+	certificateRotationThreshold                     time.Duration
+	encryptionKeyRetention                           time.Duration
+	dryRun                                           bool
+	dryRunReportDir                                  string
 
 	// OIDC configuration
 	oidcCAFile             string
@@ -117,12 +128,14 @@ func newControllerRunOptions() (controllerRunOptions, error) {
 	flag.StringVar(&c.nodeAccessNetwork, "node-access-network", kubermaticv1.DefaultNodeAccessNetwork, "A network which allows direct access to nodes via VPN. Uses CIDR notation.")
 	flag.StringVar(&c.kubernetesAddonsPath, "kubernetes-addons-path", "/opt/addons/kubernetes", "Path to addon manifests. Should contain sub-folders for each addon")
 	flag.StringVar(&c.openshiftAddonsPath, "openshift-addons-path", "/opt/addons/openshift", "Path to addon manifests. Should contain sub-folders for each addon")
+	flag.StringVar(&c.addonSourceCachePath, "addon-source-cache-path", "/var/cache/kubermatic/addons", "Directory the manifests of the addons whose source is pinned in the seed are cached in")
 	flag.StringVar(&defaultKubernetesAddonsList, "kubernetes-addons-list", "", "Comma separated list of Addons to install into every user-cluster. Mutually exclusive with `--kubernetes-addons-file`")
 	flag.StringVar(&defaultKubernetesAddonsFile, "kubernetes-addons-file", "", "File that contains a list of default kubernetes addons. Mutually exclusive with `--kubernetes-addons-list`")
 	flag.StringVar(&defaultOpenshiftAddonList, "openshift-addons-list", "", "Comma separated list of addons to install into every openshift user cluster. Mutually exclusive with `--openshift-addons-file`")
 	flag.StringVar(&defaultOpenshiftAddonsFile, "openshift-addons-file", "", "File that contains a list of default openshift addons. Mutually exclusive with `--openshift-addons-list`")
 	flag.StringVar(&c.backupContainerFile, "backup-container", "", fmt.Sprintf("[Required] Filepath of a backup container yaml. It must mount a volume named %s from which it reads the etcd backups", backupcontroller.SharedVolumeName))
 	flag.StringVar(&c.cleanupContainerFile, "cleanup-container", "", "[Required] Filepath of a cleanup container yaml. The container will be used to cleanup the backup directory for a cluster after it got deleted.")
+	flag.StringVar(&c.restoreContainerFile, "restore-container", "", fmt.Sprintf("Filepath of a restore container yaml. It must download the snapshot named by $BACKUP_NAME to the volume %s. The etcd restore controller only runs if this is set.", backupcontroller.SharedVolumeName))
 	flag.StringVar(&c.backupContainerImage, "backup-container-init-image", backupcontroller.DefaultBackupContainerImage, "Docker image to use for the init container in the backup job, must be an etcd v3 image. Only set this if your cluster can not use the public quay.io registry")
 	flag.StringVar(&c.backupInterval, "backup-interval", backupcontroller.DefaultBackupInterval, "Interval in which the etcd gets backed up")
 	flag.StringVar(&rawEtcdDiskSize, "etcd-disk-size", "5Gi", "Size for the etcd PV's. Only applies to new clusters.")
@@ -144,8 +157,13 @@ func newControllerRunOptions() (controllerRunOptions, error) {
 	flag.BoolVar(&c.apiServerEndpointReconcilingDisabled, "apiserver-reconciling-disabled-by-default", false, "Whether to disable reconciling for the apiserver endpoints by default")
 	flag.IntVar(&c.controllerManagerDefaultReplicas, "controller-manager-default-replicas", 1, "The default number of replicas for usercluster controller managers")
 	flag.IntVar(&c.schedulerDefaultReplicas, "scheduler-default-replicas", 1, "The default number of replicas for usercluster schedulers")
+	flag.IntVar(&c.etcdDefaultClusterSize, "etcd-default-cluster-size", resources.DefaultEtcdClusterSize, "The default number of members of new usercluster etcds, one of 1, 3, 5 or 7. Existing clusters keep their size")
 	flag.IntVar(&c.concurrentClusterUpdate, "max-parallel-reconcile", 10, "The default number of resources updates per cluster")
 	flag.IntVar(&c.addonEnforceInterval, "addon-enforce-interval", 5, "Check and ensure default usercluster addons are deployed every interval in minutes. Set to 0 to disable.")
+	flag.DurationVar(&c.certificateRotationThreshold, "certificate-rotation-threshold", certificatescontroller.DefaultRotationThreshold, "The time before their expiry at which the control plane certificates of userclusters get rotated")
+	flag.DurationVar(&c.encryptionKeyRetention, "encryption-key-retention", encryptionatrest.DefaultKeyRetention, "The time old encryption keys of userclusters are kept after a key rotation, should be longer than the etcd backup retention")
+	flag.BoolVar(&c.dryRun, "dry-run", false, "Only report the changes the reconciliation would make to the clusters as events instead of applying them. Only the cluster controller runs in this mode.")
+	flag.StringVar(&c.dryRunReportDir, "dry-run-report-dir", "", "Directory into which a JSON report of the changes is written per cluster in dry-run mode")
 	c.seedValidationHook.AddFlags(flag.CommandLine)
 	addFlags(flag.CommandLine)
 	flag.Parse()
@@ -228,6 +246,23 @@ func (o controllerRunOptions) validate() error {
 	if o.schedulerDefaultReplicas < 1 {
 		return fmt.Errorf("--scheduler-default-replicas must be > 0 (was %d)", o.schedulerDefaultReplicas)
 	}
+	if o.etcdDefaultClusterSize < 1 || o.etcdDefaultClusterSize > resources.MaxEtcdClusterSize || o.etcdDefaultClusterSize%2 == 0 {
+		return fmt.Errorf("--etcd-default-cluster-size must be one of 1, 3, 5 or 7 (was %d)", o.etcdDefaultClusterSize)
+	}
+	if o.certificateRotationThreshold <= 0 || o.certificateRotationThreshold >= 365*24*time.Hour {
+		return fmt.Errorf("--certificate-rotation-threshold must be > 0 and < 8760h (was %v)", o.certificateRotationThreshold)
+	}
+	if o.encryptionKeyRetention < 0 {
+		return fmt.Errorf("--encryption-key-retention must not be negative (was %v)", o.encryptionKeyRetention)
+	}
+	if o.dryRunReportDir != "" {
+		if !o.dryRun {
+			return errors.New("--dry-run-report-dir can only be used together with --dry-run")
+		}
+		if info, err := os.Stat(o.dryRunReportDir); err != nil || !info.IsDir() {
+			return fmt.Errorf("--dry-run-report-dir must be an existing directory (was %q)", o.dryRunReportDir)
+		}
+	}
 	if o.concurrentClusterUpdate < 1 {
 		return fmt.Errorf("--max-parallel-reconcile must be > 0 (was %d)", o.concurrentClusterUpdate)
 	}
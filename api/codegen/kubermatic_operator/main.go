@@ -33,6 +33,7 @@ import (
 func main() {
 	writeYAML(common.DefaultBackupStoreContainer, "config/kubermatic/static/store-container.yaml")
 	writeYAML(common.DefaultBackupCleanupContainer, "config/kubermatic/static/cleanup-container.yaml")
+	writeYAML(common.DefaultBackupRestoreContainer, "config/kubermatic/static/restore-container.yaml")
 	writeYAML(common.DefaultKubernetesAddons, "config/kubermatic/static/master/kubernetes-addons.yaml")
 	writeYAML(common.DefaultOpenshiftAddons, "config/kubermatic/static/master/openshift-addons.yaml")
 	writeJSON(common.DefaultUIConfig, "config/kubermatic/static/master/ui-config.json")
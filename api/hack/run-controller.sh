@@ -42,6 +42,7 @@ PPROF_PORT=${PPROF_PORT:-6600}
   -external-url=dev.kubermatic.io \
   -backup-container=../config/kubermatic/static/store-container.yaml \
   -cleanup-container=../config/kubermatic/static/cleanup-container.yaml \
+  -restore-container=../config/kubermatic/static/restore-container.yaml \
   -docker-pull-config-json-file=../../secrets/seed-clusters/dev.kubermatic.io/.dockerconfigjson \
   -oidc-ca-file=../../secrets/seed-clusters/dev.kubermatic.io/caBundle.pem \
   -oidc-issuer-url=$(vault kv get -field=oidc-issuer-url dev/seed-clusters/dev.kubermatic.io) \
@@ -0,0 +1,250 @@
+/*
+Copyright 2020 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addon
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// DefaultNamespace is the namespace of namespaced objects whose manifest doesn't specify one, like kubectl does it
+const DefaultNamespace = "default"
+
+// defaultPruneKinds are the kinds that are checked for objects to prune in addition to the kinds of the applied objects.
+// It is the default whitelist of `kubectl apply --prune` plus the RBAC kinds most addons ship.
+var defaultPruneKinds = []schema.GroupVersionKind{
+	{Version: "v1", Kind: "ConfigMap"},
+	{Version: "v1", Kind: "Endpoints"},
+	{Version: "v1", Kind: "Namespace"},
+	{Version: "v1", Kind: "PersistentVolumeClaim"},
+	{Version: "v1", Kind: "PersistentVolume"},
+	{Version: "v1", Kind: "Pod"},
+	{Version: "v1", Kind: "ReplicationController"},
+	{Version: "v1", Kind: "Secret"},
+	{Version: "v1", Kind: "Service"},
+	{Version: "v1", Kind: "ServiceAccount"},
+	{Group: "batch", Version: "v1", Kind: "Job"},
+	{Group: "batch", Version: "v1beta1", Kind: "CronJob"},
+	{Group: "extensions", Version: "v1beta1", Kind: "Ingress"},
+	{Group: "apps", Version: "v1", Kind: "DaemonSet"},
+	{Group: "apps", Version: "v1", Kind: "Deployment"},
+	{Group: "apps", Version: "v1", Kind: "ReplicaSet"},
+	{Group: "apps", Version: "v1", Kind: "StatefulSet"},
+	{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "ClusterRole"},
+	{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "ClusterRoleBinding"},
+	{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "Role"},
+	{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "RoleBinding"},
+}
+
+// ObjectError is the error of a single object that couldn't be applied, pruned or deleted
+type ObjectError struct {
+	Kind      string
+	Namespace string
+	Name      string
+	Err       error
+}
+
+func newObjectError(obj *unstructured.Unstructured, err error) ObjectError {
+	return ObjectError{Kind: obj.GetKind(), Namespace: obj.GetNamespace(), Name: obj.GetName(), Err: err}
+}
+
+func (e ObjectError) Error() string {
+	if e.Namespace == "" {
+		return fmt.Sprintf("%s %s: %v", e.Kind, e.Name, e.Err)
+	}
+	return fmt.Sprintf("%s %s/%s: %v", e.Kind, e.Namespace, e.Name, e.Err)
+}
+
+// Applier applies the objects of an addon to a user cluster using server-side apply
+type Applier struct {
+	client     ctrlruntimeclient.Client
+	fieldOwner string
+}
+
+// NewApplier returns an applier that writes to the cluster of the given client and takes
+// ownership of the applied fields as fieldOwner
+func NewApplier(client ctrlruntimeclient.Client, fieldOwner string) *Applier {
+	return &Applier{client: client, fieldOwner: fieldOwner}
+}
+
+// Apply applies the objects and deletes the objects with the pruneLabels that aren't part of them anymore,
+// which is what `kubectl apply --prune -l` does. Namespaces and CRDs are applied first, so the objects
+// depending on them can be applied in the same run. A failing object doesn't stop the others from being
+// applied, all failures are returned. Nothing is pruned when an object couldn't be applied, as it might
+// still exist under a different identity.
+func (a *Applier) Apply(ctx context.Context, objects []*unstructured.Unstructured, pruneLabels map[string]string) []ObjectError {
+	var errs []ObjectError
+
+	applied := map[types.UID]struct{}{}
+	pruneKinds := map[schema.GroupVersionKind]struct{}{}
+	for _, gvk := range defaultPruneKinds {
+		pruneKinds[gvk] = struct{}{}
+	}
+
+	for _, obj := range sortForApply(objects) {
+		obj = obj.DeepCopy()
+		if err := withDefaultNamespace(obj, a.apply(ctx)); err != nil {
+			errs = append(errs, newObjectError(obj, err))
+			continue
+		}
+		applied[obj.GetUID()] = struct{}{}
+		pruneKinds[obj.GroupVersionKind()] = struct{}{}
+	}
+
+	if len(errs) > 0 || len(pruneLabels) == 0 {
+		return errs
+	}
+	return a.prune(ctx, pruneKinds, applied, pruneLabels)
+}
+
+// Delete deletes the objects from the cluster, objects that don't exist anymore are skipped.
+// The objects are deleted in the reverse order of Apply.
+func (a *Applier) Delete(ctx context.Context, objects []*unstructured.Unstructured) []ObjectError {
+	var errs []ObjectError
+
+	sorted := sortForApply(objects)
+	for i := len(sorted) - 1; i >= 0; i-- {
+		obj := sorted[i].DeepCopy()
+		if err := withDefaultNamespace(obj, a.delete(ctx)); err != nil && !kerrors.IsNotFound(err) {
+			errs = append(errs, newObjectError(obj, err))
+		}
+	}
+	return errs
+}
+
+// apply applies the object with server-side apply, the object holds the live object afterwards.
+// Clusters that don't support server-side apply yet get the object merged into the live object.
+func (a *Applier) apply(ctx context.Context) func(obj *unstructured.Unstructured) error {
+	return func(obj *unstructured.Unstructured) error {
+		err := a.client.Patch(ctx, obj, ctrlruntimeclient.Apply, ctrlruntimeclient.FieldOwner(a.fieldOwner), ctrlruntimeclient.ForceOwnership)
+		if !kerrors.IsUnsupportedMediaType(err) {
+			return err
+		}
+
+		data, err := obj.MarshalJSON()
+		if err != nil {
+			return fmt.Errorf("failed to encode object: %v", err)
+		}
+		live := &unstructured.Unstructured{}
+		live.SetGroupVersionKind(obj.GroupVersionKind())
+		if err := a.client.Get(ctx, types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()}, live); err != nil {
+			if !kerrors.IsNotFound(err) {
+				return err
+			}
+			return a.client.Create(ctx, obj, ctrlruntimeclient.FieldOwner(a.fieldOwner))
+		}
+		return a.client.Patch(ctx, obj, ctrlruntimeclient.ConstantPatch(types.MergePatchType, data), ctrlruntimeclient.FieldOwner(a.fieldOwner))
+	}
+}
+
+func (a *Applier) delete(ctx context.Context) func(obj *unstructured.Unstructured) error {
+	return func(obj *unstructured.Unstructured) error {
+		return a.client.Delete(ctx, obj, ctrlruntimeclient.PropagationPolicy(metav1.DeletePropagationBackground))
+	}
+}
+
+// prune deletes the objects of the given kinds that have the labels but haven't been applied
+func (a *Applier) prune(ctx context.Context, kinds map[schema.GroupVersionKind]struct{}, applied map[types.UID]struct{}, pruneLabels map[string]string) []ObjectError {
+	var errs []ObjectError
+
+	selector := labels.SelectorFromSet(pruneLabels)
+	for _, gvk := range sortedKinds(kinds) {
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+		if err := a.client.List(ctx, list, ctrlruntimeclient.MatchingLabelsSelector{Selector: selector}); err != nil {
+			if meta.IsNoMatchError(err) || kerrors.IsNotFound(err) {
+				// the kind isn't served by this cluster, so there is nothing to prune
+				continue
+			}
+			errs = append(errs, ObjectError{Kind: gvk.Kind, Err: fmt.Errorf("failed to list objects to prune: %v", err)})
+			continue
+		}
+
+		for i := range list.Items {
+			obj := &list.Items[i]
+			if _, ok := applied[obj.GetUID()]; ok {
+				continue
+			}
+			// the objects of e.g. a Deployment are removed together with it
+			if metav1.GetControllerOf(obj) != nil || obj.GetDeletionTimestamp() != nil {
+				continue
+			}
+			if err := a.delete(ctx)(obj); err != nil && !kerrors.IsNotFound(err) {
+				errs = append(errs, newObjectError(obj, fmt.Errorf("failed to prune: %v", err)))
+			}
+		}
+	}
+	return errs
+}
+
+// withDefaultNamespace calls f with the object. As the scope of the kind isn't known without discovery, a namespaced
+// object without a namespace is only detected by the API server not serving its cluster-scoped path and then
+// retried in the default namespace.
+func withDefaultNamespace(obj *unstructured.Unstructured, f func(*unstructured.Unstructured) error) error {
+	err := f(obj)
+	if obj.GetNamespace() != "" || !(kerrors.IsNotFound(err) || kerrors.IsMethodNotSupported(err)) {
+		return err
+	}
+
+	obj.SetNamespace(DefaultNamespace)
+	if retryErr := f(obj); retryErr == nil || !(kerrors.IsNotFound(retryErr) || kerrors.IsMethodNotSupported(retryErr)) {
+		return retryErr
+	}
+	obj.SetNamespace("")
+	return err
+}
+
+// sortForApply returns the objects with the Namespaces and CRDs first, the order of the others is kept
+func sortForApply(objects []*unstructured.Unstructured) []*unstructured.Unstructured {
+	sorted := append([]*unstructured.Unstructured{}, objects...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return applyPriority(sorted[i]) < applyPriority(sorted[j])
+	})
+	return sorted
+}
+
+func applyPriority(obj *unstructured.Unstructured) int {
+	switch obj.GroupVersionKind().GroupKind() {
+	case schema.GroupKind{Kind: "Namespace"}:
+		return 0
+	case schema.GroupKind{Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"}:
+		return 1
+	default:
+		return 2
+	}
+}
+
+func sortedKinds(kinds map[schema.GroupVersionKind]struct{}) []schema.GroupVersionKind {
+	sorted := make([]schema.GroupVersionKind, 0, len(kinds))
+	for gvk := range kinds {
+		sorted = append(sorted, gvk)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].String() < sorted[j].String()
+	})
+	return sorted
+}
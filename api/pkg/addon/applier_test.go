@@ -0,0 +1,227 @@
+/*
+Copyright 2020 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/pointer"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlruntimefakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var testAddonLabels = map[string]string{"kubermatic-addon": "test"}
+
+// applyingClient emulates the server-side apply of an API server on top of the fake client,
+// which doesn't support apply patches
+type applyingClient struct {
+	ctrlruntimeclient.Client
+	// applied are the objects in the order they have been applied
+	applied []string
+	// rejected are the names of the objects the API server rejects
+	rejected map[string]bool
+}
+
+func (c *applyingClient) Patch(ctx context.Context, obj runtime.Object, patch ctrlruntimeclient.Patch, opts ...ctrlruntimeclient.PatchOption) error {
+	if patch.Type() != types.ApplyPatchType {
+		return c.Client.Patch(ctx, obj, patch, opts...)
+	}
+
+	u := obj.(*unstructured.Unstructured)
+	// the Namespaces are the only cluster-scoped objects used in the tests
+	if u.GetNamespace() == "" && u.GetKind() != "Namespace" {
+		return kerrors.NewNotFound(schema.GroupResource{}, "")
+	}
+	if c.rejected[u.GetName()] {
+		return errors.New("admission webhook denied the request")
+	}
+	c.applied = append(c.applied, fmt.Sprintf("%s %s/%s", u.GetKind(), u.GetNamespace(), u.GetName()))
+
+	live := &unstructured.Unstructured{}
+	live.SetGroupVersionKind(u.GroupVersionKind())
+	err := c.Client.Get(ctx, types.NamespacedName{Namespace: u.GetNamespace(), Name: u.GetName()}, live)
+	if err != nil && !kerrors.IsNotFound(err) {
+		return err
+	}
+	if err == nil {
+		u.SetUID(live.GetUID())
+		u.SetResourceVersion(live.GetResourceVersion())
+	} else {
+		u.SetUID(types.UID(u.GetNamespace() + "/" + u.GetName()))
+	}
+
+	// the fake client can only list the objects it stores as typed objects
+	typed, err := scheme.Scheme.New(u.GroupVersionKind())
+	if err != nil {
+		return err
+	}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, typed); err != nil {
+		return err
+	}
+	if live.GetUID() == "" {
+		return c.Client.Create(ctx, typed)
+	}
+	return c.Client.Update(ctx, typed)
+}
+
+func genConfigMap(namespace, name string, labels map[string]string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      name,
+			UID:       types.UID(namespace + "/" + name),
+			Labels:    labels,
+		},
+		Data: map[string]string{"foo": "old"},
+	}
+}
+
+func genUnstructured(apiVersion, kind, namespace, name string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion(apiVersion)
+	obj.SetKind(kind)
+	obj.SetNamespace(namespace)
+	obj.SetName(name)
+	obj.SetLabels(testAddonLabels)
+	if kind == "ConfigMap" {
+		obj.Object["data"] = map[string]interface{}{"foo": "bar"}
+	}
+	return obj
+}
+
+func TestApplierApply(t *testing.T) {
+	ownedConfigMap := genConfigMap("kube-system", "owned", testAddonLabels)
+	ownedConfigMap.OwnerReferences = []metav1.OwnerReference{{APIVersion: "apps/v1", Kind: "Deployment", Name: "foo", Controller: pointer.BoolPtr(true)}}
+
+	testCases := []struct {
+		name            string
+		rejected        map[string]bool
+		expectedErrors  []string
+		expectedApplied []string
+		expectedExist   []string
+		expectedMissing []string
+	}{
+		{
+			name: "applies the objects and prunes the ones that have been removed from the manifests",
+			expectedApplied: []string{
+				"Namespace /addon",
+				"ConfigMap kube-system/test",
+				"ConfigMap default/without-namespace",
+				"ConfigMap addon/in-addon-namespace",
+			},
+			expectedExist:   []string{"kube-system/test", "default/without-namespace", "addon/in-addon-namespace", "kube-system/foreign", "kube-system/owned"},
+			expectedMissing: []string{"kube-system/outdated"},
+		},
+		{
+			name:           "reports the objects that couldn't be applied and doesn't prune",
+			rejected:       map[string]bool{"test": true},
+			expectedErrors: []string{"ConfigMap kube-system/test: admission webhook denied the request"},
+			expectedApplied: []string{
+				"Namespace /addon",
+				"ConfigMap default/without-namespace",
+				"ConfigMap addon/in-addon-namespace",
+			},
+			expectedExist: []string{"default/without-namespace", "addon/in-addon-namespace", "kube-system/outdated"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := &applyingClient{
+				Client: ctrlruntimefakeclient.NewFakeClient(
+					genConfigMap("kube-system", "test", testAddonLabels),
+					genConfigMap("kube-system", "outdated", testAddonLabels),
+					genConfigMap("kube-system", "foreign", nil),
+					ownedConfigMap,
+				),
+				rejected: tc.rejected,
+			}
+
+			objects := []*unstructured.Unstructured{
+				genUnstructured("v1", "ConfigMap", "kube-system", "test"),
+				genUnstructured("v1", "ConfigMap", "", "without-namespace"),
+				genUnstructured("v1", "ConfigMap", "addon", "in-addon-namespace"),
+				genUnstructured("v1", "Namespace", "", "addon"),
+			}
+			errs := NewApplier(client, "test").Apply(context.Background(), objects, testAddonLabels)
+
+			var errMessages []string
+			for _, err := range errs {
+				errMessages = append(errMessages, err.Error())
+			}
+			if fmt.Sprint(errMessages) != fmt.Sprint(tc.expectedErrors) {
+				t.Fatalf("expected the errors %v, got %v", tc.expectedErrors, errMessages)
+			}
+			if fmt.Sprint(client.applied) != fmt.Sprint(tc.expectedApplied) {
+				t.Fatalf("expected the objects to be applied in the order %v, got %v", tc.expectedApplied, client.applied)
+			}
+
+			for _, key := range tc.expectedExist {
+				if err := getConfigMap(client, key); err != nil {
+					t.Fatalf("expected the ConfigMap %s to exist: %v", key, err)
+				}
+			}
+			for _, key := range tc.expectedMissing {
+				if err := getConfigMap(client, key); !kerrors.IsNotFound(err) {
+					t.Fatalf("expected the ConfigMap %s to be pruned, got %v", key, err)
+				}
+			}
+		})
+	}
+}
+
+func TestApplierDelete(t *testing.T) {
+	client := &applyingClient{
+		Client: ctrlruntimefakeclient.NewFakeClient(
+			genConfigMap("kube-system", "test", testAddonLabels),
+			genConfigMap("kube-system", "foreign", nil),
+		),
+	}
+
+	objects := []*unstructured.Unstructured{
+		genUnstructured("v1", "ConfigMap", "kube-system", "test"),
+		genUnstructured("v1", "ConfigMap", "kube-system", "already-deleted"),
+	}
+	if errs := NewApplier(client, "test").Delete(context.Background(), objects); len(errs) > 0 {
+		t.Fatalf("expected the objects to be deleted, got %v", errs)
+	}
+
+	if err := getConfigMap(client, "kube-system/test"); !kerrors.IsNotFound(err) {
+		t.Fatalf("expected the ConfigMap to be deleted, got %v", err)
+	}
+	if err := getConfigMap(client, "kube-system/foreign"); err != nil {
+		t.Fatalf("expected the ConfigMap that isn't part of the addon to be kept: %v", err)
+	}
+}
+
+// getConfigMap gets the ConfigMap with the given namespace/name key
+func getConfigMap(client ctrlruntimeclient.Client, key string) error {
+	parts := strings.SplitN(key, "/", 2)
+	return client.Get(context.Background(), types.NamespacedName{Namespace: parts[0], Name: parts[1]}, &corev1.ConfigMap{})
+}
@@ -0,0 +1,244 @@
+/*
+Copyright 2020 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addon
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+
+	kubermaticv1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ObjectStatuses returns the readiness and drift of the applied objects in the cluster
+func ObjectStatuses(ctx context.Context, client ctrlruntimeclient.Client, objects []*unstructured.Unstructured) []kubermaticv1.AddonObjectStatus {
+	statuses := make([]kubermaticv1.AddonObjectStatus, 0, len(objects))
+	for _, obj := range objects {
+		statuses = append(statuses, objectStatus(ctx, client, obj))
+	}
+	return statuses
+}
+
+func objectStatus(ctx context.Context, client ctrlruntimeclient.Client, obj *unstructured.Unstructured) kubermaticv1.AddonObjectStatus {
+	live := &unstructured.Unstructured{}
+	live.SetGroupVersionKind(obj.GroupVersionKind())
+	desired := obj.DeepCopy()
+	err := withDefaultNamespace(desired, func(o *unstructured.Unstructured) error {
+		return client.Get(ctx, types.NamespacedName{Namespace: o.GetNamespace(), Name: o.GetName()}, live)
+	})
+
+	status := kubermaticv1.AddonObjectStatus{
+		APIVersion: desired.GetAPIVersion(),
+		Kind:       desired.GetKind(),
+		Namespace:  desired.GetNamespace(),
+		Name:       desired.GetName(),
+	}
+	if kerrors.IsNotFound(err) {
+		status.Drifted = true
+		status.Message = "the object doesn't exist"
+		return status
+	}
+	if err != nil {
+		status.Message = fmt.Sprintf("failed to get the object: %v", err)
+		return status
+	}
+
+	status.DriftedFields = objectDrift(desired, live)
+	status.Drifted = len(status.DriftedFields) > 0
+	status.Message = notReadyReason(live)
+	status.Ready = status.Message == ""
+	return status
+}
+
+// notReadyReason returns why the object isn't ready or an empty string if it is
+func notReadyReason(obj *unstructured.Unstructured) string {
+	if obj.GetDeletionTimestamp() != nil {
+		return "the object is being deleted"
+	}
+
+	switch obj.GroupVersionKind().GroupKind() {
+	case schema.GroupKind{Group: "apps", Kind: "Deployment"}, schema.GroupKind{Group: "extensions", Kind: "Deployment"}:
+		if reason := notObservedReason(obj); reason != "" {
+			return reason
+		}
+		replicas, found, _ := unstructured.NestedInt64(obj.Object, "spec", "replicas")
+		if !found {
+			replicas = 1
+		}
+		available, _, _ := unstructured.NestedInt64(obj.Object, "status", "availableReplicas")
+		if conditionStatus(obj, "Available") != "True" || available < replicas {
+			return fmt.Sprintf("%d of %d replicas are available", available, replicas)
+		}
+
+	case schema.GroupKind{Group: "apps", Kind: "DaemonSet"}, schema.GroupKind{Group: "extensions", Kind: "DaemonSet"}:
+		if reason := notObservedReason(obj); reason != "" {
+			return reason
+		}
+		desired, _, _ := unstructured.NestedInt64(obj.Object, "status", "desiredNumberScheduled")
+		updated, _, _ := unstructured.NestedInt64(obj.Object, "status", "updatedNumberScheduled")
+		available, _, _ := unstructured.NestedInt64(obj.Object, "status", "numberAvailable")
+		if updated < desired || available < desired {
+			return fmt.Sprintf("%d of %d pods are updated and %d are available", updated, desired, available)
+		}
+
+	case schema.GroupKind{Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"}:
+		if conditionStatus(obj, "Established") != "True" {
+			return "the CustomResourceDefinition isn't established"
+		}
+	}
+
+	return ""
+}
+
+// notObservedReason returns a reason if the controller of the object hasn't observed its current generation yet
+func notObservedReason(obj *unstructured.Unstructured) string {
+	observedGeneration, _, _ := unstructured.NestedInt64(obj.Object, "status", "observedGeneration")
+	if observedGeneration < obj.GetGeneration() {
+		return "the current generation hasn't been observed yet"
+	}
+	return ""
+}
+
+func conditionStatus(obj *unstructured.Unstructured, conditionType string) string {
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if ok && condition["type"] == conditionType {
+			status, _ := condition["status"].(string)
+			return status
+		}
+	}
+	return ""
+}
+
+// objectDrift returns the sorted paths of the fields set in the desired object whose values differ in the live
+// object. Fields the API server sets or defaults aren't part of the desired object and don't count as drift. Of the
+// metadata only the labels and annotations are compared, the status isn't compared at all.
+func objectDrift(desired, live *unstructured.Unstructured) []string {
+	var drifted []string
+	for key, value := range desired.Object {
+		switch key {
+		case "apiVersion", "kind", "status":
+			continue
+		case "stringData":
+			// the API server merges the stringData of Secrets into their data
+			if desired.GetKind() == "Secret" {
+				continue
+			}
+		case "metadata":
+			metadata, _ := value.(map[string]interface{})
+			liveMetadata, _ := live.Object["metadata"].(map[string]interface{})
+			for _, metadataKey := range []string{"labels", "annotations"} {
+				drifted = append(drifted, driftedFields(metadata[metadataKey], liveMetadata[metadataKey], "metadata."+metadataKey)...)
+			}
+			continue
+		}
+		drifted = append(drifted, driftedFields(value, live.Object[key], key)...)
+	}
+
+	sort.Strings(drifted)
+	return drifted
+}
+
+func driftedFields(desired, live interface{}, path string) []string {
+	switch desiredValue := desired.(type) {
+	case nil:
+		return nil
+	case map[string]interface{}:
+		liveValue, ok := live.(map[string]interface{})
+		if !ok {
+			if len(desiredValue) == 0 && live == nil {
+				return nil
+			}
+			return []string{path}
+		}
+		var drifted []string
+		for key, value := range desiredValue {
+			drifted = append(drifted, driftedFields(value, liveValue[key], path+"."+key)...)
+		}
+		return drifted
+	case []interface{}:
+		liveValue, ok := live.([]interface{})
+		if !ok {
+			if len(desiredValue) == 0 && live == nil {
+				return nil
+			}
+			return []string{path}
+		}
+		if len(desiredValue) != len(liveValue) {
+			return []string{path}
+		}
+		var drifted []string
+		for i := range desiredValue {
+			drifted = append(drifted, driftedFields(desiredValue[i], liveValue[i], fmt.Sprintf("%s[%d]", path, i))...)
+		}
+		return drifted
+	default:
+		if !scalarsEqual(desired, live) {
+			return []string{path}
+		}
+		return nil
+	}
+}
+
+// scalarsEqual compares numbers independent of their type and quantities independent of their format,
+// as the API server normalizes them
+func scalarsEqual(desired, live interface{}) bool {
+	if reflect.DeepEqual(desired, live) {
+		return true
+	}
+	if desiredNumber, ok := toFloat(desired); ok {
+		liveNumber, ok := toFloat(live)
+		return ok && desiredNumber == liveNumber
+	}
+
+	desiredString, ok := desired.(string)
+	if !ok {
+		return false
+	}
+	liveString, ok := live.(string)
+	if !ok {
+		return false
+	}
+	desiredQuantity, err := resource.ParseQuantity(desiredString)
+	if err != nil {
+		return false
+	}
+	liveQuantity, err := resource.ParseQuantity(liveString)
+	return err == nil && desiredQuantity.Cmp(liveQuantity) == 0
+}
+
+func toFloat(value interface{}) (float64, bool) {
+	switch number := value.(type) {
+	case int64:
+		return float64(number), true
+	case int:
+		return float64(number), true
+	case float64:
+		return number, true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,351 @@
+/*
+Copyright 2020 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addon
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/ghodss/yaml"
+
+	kubermaticv1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	utilpointer "k8s.io/utils/pointer"
+	ctrlruntimefakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func parseObject(t *testing.T, manifest string) *unstructured.Unstructured {
+	data, err := yaml.YAMLToJSON([]byte(manifest))
+	if err != nil {
+		t.Fatal(err)
+	}
+	obj := &unstructured.Unstructured{}
+	if err := obj.UnmarshalJSON(data); err != nil {
+		t.Fatal(err)
+	}
+	return obj
+}
+
+func TestNotReadyReason(t *testing.T) {
+	testCases := []struct {
+		name           string
+		manifest       string
+		expectedReason string
+	}{
+		{
+			name: "available deployment",
+			manifest: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  generation: 2
+spec:
+  replicas: 2
+status:
+  observedGeneration: 2
+  availableReplicas: 2
+  conditions:
+  - type: Available
+    status: "True"
+`,
+		},
+		{
+			name: "deployment without available replicas",
+			manifest: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  generation: 2
+spec:
+  replicas: 2
+status:
+  observedGeneration: 2
+  availableReplicas: 1
+  conditions:
+  - type: Available
+    status: "True"
+`,
+			expectedReason: "1 of 2 replicas are available",
+		},
+		{
+			name: "deployment whose generation hasn't been observed",
+			manifest: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  generation: 3
+status:
+  observedGeneration: 2
+`,
+			expectedReason: "the current generation hasn't been observed yet",
+		},
+		{
+			name: "rolled out daemonset",
+			manifest: `
+apiVersion: apps/v1
+kind: DaemonSet
+status:
+  desiredNumberScheduled: 3
+  updatedNumberScheduled: 3
+  numberAvailable: 3
+`,
+		},
+		{
+			name: "daemonset that is rolling out",
+			manifest: `
+apiVersion: apps/v1
+kind: DaemonSet
+status:
+  desiredNumberScheduled: 3
+  updatedNumberScheduled: 1
+  numberAvailable: 3
+`,
+			expectedReason: "1 of 3 pods are updated and 3 are available",
+		},
+		{
+			name: "established crd",
+			manifest: `
+apiVersion: apiextensions.k8s.io/v1beta1
+kind: CustomResourceDefinition
+status:
+  conditions:
+  - type: NamesAccepted
+    status: "True"
+  - type: Established
+    status: "True"
+`,
+		},
+		{
+			name: "crd that isn't established",
+			manifest: `
+apiVersion: apiextensions.k8s.io/v1beta1
+kind: CustomResourceDefinition
+status:
+  conditions:
+  - type: Established
+    status: "False"
+`,
+			expectedReason: "the CustomResourceDefinition isn't established",
+		},
+		{
+			name: "other objects are ready when they exist",
+			manifest: `
+apiVersion: v1
+kind: ConfigMap
+`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if reason := notReadyReason(parseObject(t, tc.manifest)); reason != tc.expectedReason {
+				t.Fatalf("expected the reason %q, got %q", tc.expectedReason, reason)
+			}
+		})
+	}
+}
+
+func TestObjectDrift(t *testing.T) {
+	desired := `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: dashboard
+  labels:
+    kubermatic-addon: dashboard
+spec:
+  replicas: 1
+  template:
+    spec:
+      containers:
+      - name: dashboard
+        image: dashboard:v1
+        resources:
+          requests:
+            cpu: 1000m
+        securityContext: {}
+`
+
+	testCases := []struct {
+		name           string
+		live           string
+		expectedFields []string
+	}{
+		{
+			name: "defaulted and normalized fields aren't drift",
+			live: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: dashboard
+  uid: 1234
+  labels:
+    kubermatic-addon: dashboard
+spec:
+  replicas: 1
+  revisionHistoryLimit: 10
+  template:
+    spec:
+      containers:
+      - name: dashboard
+        image: dashboard:v1
+        imagePullPolicy: IfNotPresent
+        resources:
+          requests:
+            cpu: "1"
+status:
+  replicas: 1
+`,
+		},
+		{
+			name: "edited fields",
+			live: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: dashboard
+  labels: {}
+spec:
+  replicas: 0
+  template:
+    spec:
+      containers:
+      - name: dashboard
+        image: dashboard:v2
+        resources:
+          requests:
+            cpu: 500m
+      - name: sidecar
+        image: sidecar:v1
+`,
+			expectedFields: []string{"metadata.labels.kubermatic-addon", "spec.replicas", "spec.template.spec.containers"},
+		},
+		{
+			name: "edited nested fields",
+			live: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: dashboard
+  labels:
+    kubermatic-addon: dashboard
+spec:
+  replicas: 1
+  template:
+    spec:
+      containers:
+      - name: dashboard
+        image: dashboard:v2
+        resources:
+          requests:
+            cpu: 500m
+`,
+			expectedFields: []string{"spec.template.spec.containers[0].image", "spec.template.spec.containers[0].resources.requests.cpu"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fields := objectDrift(parseObject(t, desired), parseObject(t, tc.live))
+			if !reflect.DeepEqual(fields, tc.expectedFields) {
+				t.Fatalf("expected the drifted fields %v, got %v", tc.expectedFields, fields)
+			}
+		})
+	}
+}
+
+func TestObjectStatuses(t *testing.T) {
+	client := ctrlruntimefakeclient.NewFakeClient(
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "kube-system", Name: "dashboard"},
+			Spec:       appsv1.DeploymentSpec{Replicas: utilpointer.Int32Ptr(2)},
+			Status: appsv1.DeploymentStatus{
+				AvailableReplicas: 1,
+				Conditions:        []appsv1.DeploymentCondition{{Type: appsv1.DeploymentAvailable, Status: corev1.ConditionTrue}},
+			},
+		},
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "settings"},
+			Data:       map[string]string{"theme": "light"},
+		},
+	)
+	objects := []*unstructured.Unstructured{
+		parseObject(t, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  namespace: kube-system
+  name: dashboard
+spec:
+  replicas: 3
+`),
+		parseObject(t, `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  namespace: default
+  name: settings
+data:
+  theme: dark
+`),
+		parseObject(t, `
+apiVersion: v1
+kind: Service
+metadata:
+  namespace: kube-system
+  name: dashboard
+`),
+	}
+
+	expected := []kubermaticv1.AddonObjectStatus{
+		{
+			APIVersion:    "apps/v1",
+			Kind:          "Deployment",
+			Namespace:     "kube-system",
+			Name:          "dashboard",
+			Drifted:       true,
+			DriftedFields: []string{"spec.replicas"},
+			Message:       "1 of 2 replicas are available",
+		},
+		{
+			APIVersion:    "v1",
+			Kind:          "ConfigMap",
+			Namespace:     "default",
+			Name:          "settings",
+			Ready:         true,
+			Drifted:       true,
+			DriftedFields: []string{"data.theme"},
+		},
+		{
+			APIVersion: "v1",
+			Kind:       "Service",
+			Namespace:  "kube-system",
+			Name:       "dashboard",
+			Drifted:    true,
+			Message:    "the object doesn't exist",
+		},
+	}
+
+	if statuses := ObjectStatuses(context.Background(), client, objects); !reflect.DeepEqual(statuses, expected) {
+		t.Fatalf("expected the statuses\n%+v\ngot\n%+v", expected, statuses)
+	}
+}
@@ -0,0 +1,256 @@
+/*
+Copyright 2020 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addon
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	kubermaticv1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+const (
+	// sourceFetchTimeout bounds the time a bundle may take to be pulled
+	sourceFetchTimeout = 5 * time.Minute
+	// maxBundleSize is the maximum size of all manifests of a bundle
+	maxBundleSize = 32 << 20
+)
+
+var checksumRegexp = regexp.MustCompile(`^sha256:[a-f0-9]{64}$`)
+
+// Source provides a versioned bundle of addon manifests from outside the seed-controller-manager image
+type Source interface {
+	// Key identifies the bundle, sources with the same key provide the same bundle
+	Key() string
+	// Fetch writes the manifests of the bundle into the directory, which doesn't exist yet
+	Fetch(ctx context.Context, dir string) error
+}
+
+// ValidateSource validates the AddonSource of a seed
+func ValidateSource(source kubermaticv1.AddonSource, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	switch {
+	case source.OCI == nil && source.Git == nil:
+		allErrs = append(allErrs, field.Required(fldPath, "exactly one of oci and git must be set"))
+	case source.OCI != nil && source.Git != nil:
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("git"), "may not be set together with oci"))
+	case source.OCI != nil:
+		ociPath := fldPath.Child("oci")
+		if source.OCI.Repository == "" {
+			allErrs = append(allErrs, field.Required(ociPath.Child("repository"), ""))
+		} else if !strings.Contains(source.OCI.Repository, "/") {
+			allErrs = append(allErrs, field.Invalid(ociPath.Child("repository"), source.OCI.Repository, "must include the registry"))
+		}
+		if source.OCI.Tag == "" && source.OCI.Digest == "" {
+			allErrs = append(allErrs, field.Required(ociPath.Child("tag"), "either the tag or the digest must be set"))
+		}
+		if source.OCI.Digest != "" && !checksumRegexp.MatchString(source.OCI.Digest) {
+			allErrs = append(allErrs, field.Invalid(ociPath.Child("digest"), source.OCI.Digest, `must be of the form "sha256:<hex>"`))
+		}
+	case source.Git != nil:
+		gitPath := fldPath.Child("git")
+		if source.Git.URL == "" {
+			allErrs = append(allErrs, field.Required(gitPath.Child("url"), ""))
+		}
+		if source.Git.Ref == "" {
+			allErrs = append(allErrs, field.Required(gitPath.Child("ref"), ""))
+		}
+		if p := path.Clean(source.Git.Path); source.Git.Path != "" && (path.IsAbs(p) || p == ".." || strings.HasPrefix(p, "../")) {
+			allErrs = append(allErrs, field.Invalid(gitPath.Child("path"), source.Git.Path, "must be a relative path in the repository"))
+		}
+	}
+
+	// tags and branches can be moved, so the bundle must be pinned by a checksum or digest
+	switch {
+	case source.Checksum != "" && !checksumRegexp.MatchString(source.Checksum):
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("checksum"), source.Checksum, `must be of the form "sha256:<hex>"`))
+	case source.Checksum == "" && (source.Git != nil || source.OCI != nil && source.OCI.Digest == ""):
+		allErrs = append(allErrs, field.Required(fldPath.Child("checksum"), "must be set unless the oci artifact is pinned by its digest"))
+	}
+
+	return allErrs
+}
+
+// NewSource returns the Source of the given AddonSource
+func NewSource(source kubermaticv1.AddonSource) (Source, error) {
+	if errs := ValidateSource(source, field.NewPath("source")); len(errs) > 0 {
+		return nil, errs.ToAggregate()
+	}
+	if source.OCI != nil {
+		return newOCISource(source.OCI.Repository, source.OCI.Tag, source.OCI.Digest), nil
+	}
+	return &gitSource{url: source.Git.URL, ref: source.Git.Ref, path: source.Git.Path}, nil
+}
+
+// SourceCache keeps the bundles of the addon sources on disk, so each of them only gets pulled once
+type SourceCache struct {
+	dir string
+
+	// lock protects keyLocks, which serialize the pulls of the same bundle
+	lock     sync.Mutex
+	keyLocks map[string]*sync.Mutex
+}
+
+// NewSourceCache returns a SourceCache that stores the bundles in the given directory
+func NewSourceCache(dir string) *SourceCache {
+	return &SourceCache{dir: dir, keyLocks: map[string]*sync.Mutex{}}
+}
+
+// Get returns the directory with the manifests of the source, pulling the bundle if it isn't cached yet. If a checksum
+// is given, the manifests are verified against it. A bundle only gets pulled again if its checksum changes or the
+// cached manifests don't match it anymore. Different bundles are pulled in parallel, each within sourceFetchTimeout.
+func (c *SourceCache) Get(ctx context.Context, source Source, checksum string) (string, error) {
+	key := sha256.Sum256([]byte(source.Key() + "\n" + checksum))
+	name := hex.EncodeToString(key[:])
+	dir := filepath.Join(c.dir, name)
+
+	keyLock := c.keyLock(name)
+	keyLock.Lock()
+	defer keyLock.Unlock()
+
+	if _, err := os.Stat(dir); err == nil {
+		if err := verifyBundleChecksum(dir, checksum); err == nil {
+			return dir, nil
+		}
+		if err := os.RemoveAll(dir); err != nil {
+			return "", fmt.Errorf("failed to remove the corrupted bundle of %s: %v", source.Key(), err)
+		}
+	} else if !os.IsNotExist(err) {
+		return "", err
+	}
+
+	if err := os.MkdirAll(c.dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create the cache directory: %v", err)
+	}
+	tmpDir, err := ioutil.TempDir(c.dir, ".pull-")
+	if err != nil {
+		return "", fmt.Errorf("failed to create a temporary directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	fetchCtx, cancel := context.WithTimeout(ctx, sourceFetchTimeout)
+	defer cancel()
+
+	bundleDir := filepath.Join(tmpDir, "bundle")
+	if err := source.Fetch(fetchCtx, bundleDir); err != nil {
+		return "", fmt.Errorf("failed to pull %s: %v", source.Key(), err)
+	}
+	if err := verifyBundleChecksum(bundleDir, checksum); err != nil {
+		return "", fmt.Errorf("failed to verify %s: %v", source.Key(), err)
+	}
+	if err := os.Rename(bundleDir, dir); err != nil {
+		return "", fmt.Errorf("failed to store the bundle of %s: %v", source.Key(), err)
+	}
+	return dir, nil
+}
+
+// keyLock returns the lock of the bundle with the given name
+func (c *SourceCache) keyLock(name string) *sync.Mutex {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	keyLock, ok := c.keyLocks[name]
+	if !ok {
+		keyLock = &sync.Mutex{}
+		c.keyLocks[name] = keyLock
+	}
+	return keyLock
+}
+
+// BundleChecksum returns the checksum of the manifests in the directory, which is the SHA256 of the output
+// of `sha256sum *` in it
+func BundleChecksum(dir string) (string, error) {
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return "", err
+	}
+
+	h := sha256.New()
+	for _, info := range infos {
+		if !isBundleFile(info) {
+			continue
+		}
+		data, err := ioutil.ReadFile(filepath.Join(dir, info.Name()))
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(h, "%x  %s\n", sha256.Sum256(data), info.Name())
+	}
+	return "sha256:" + hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func verifyBundleChecksum(dir, checksum string) error {
+	if checksum == "" {
+		return nil
+	}
+	actual, err := BundleChecksum(dir)
+	if err != nil {
+		return fmt.Errorf("failed to calculate the checksum: %v", err)
+	}
+	if actual != checksum {
+		return fmt.Errorf("the checksum %s of the manifests doesn't match %s", actual, checksum)
+	}
+	return nil
+}
+
+// isBundleFile returns whether the file is part of a bundle. Like ParseFromFolder, bundles only consist of the
+// regular files at their top level. Hidden files are left out as well.
+func isBundleFile(info os.FileInfo) bool {
+	return info.Mode().IsRegular() && !strings.HasPrefix(info.Name(), ".")
+}
+
+// bundleWriter writes the files of a bundle into a directory, as long as they don't exceed maxBundleSize in total
+type bundleWriter struct {
+	dir  string
+	size int64
+}
+
+// write writes a file of the bundle
+func (w *bundleWriter) write(name string, r io.Reader) error {
+	if name == "" || strings.ContainsAny(name, `/\`) || name == ".." {
+		return fmt.Errorf("invalid file name %q", name)
+	}
+	f, err := os.OpenFile(filepath.Join(w.dir, name), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return err
+	}
+	// read one byte more than allowed to detect oversized bundles
+	n, err := io.Copy(f, io.LimitReader(r, maxBundleSize-w.size+1))
+	w.size += n
+	if err == nil && w.size > maxBundleSize {
+		err = fmt.Errorf("the bundle exceeds %d bytes", maxBundleSize)
+	}
+	if err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2020 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addon
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// gitSource clones an addon bundle from a Git repository with the git binary
+type gitSource struct {
+	url  string
+	ref  string
+	path string
+}
+
+func (s *gitSource) Key() string {
+	return fmt.Sprintf("git::%s//%s?ref=%s", s.url, s.path, s.ref)
+}
+
+func (s *gitSource) Fetch(ctx context.Context, dir string) error {
+	checkout, err := ioutil.TempDir(filepath.Dir(dir), ".checkout-")
+	if err != nil {
+		return fmt.Errorf("failed to create a temporary directory: %v", err)
+	}
+	defer os.RemoveAll(checkout)
+
+	cmd := exec.CommandContext(ctx, "git", "clone", "--quiet", "--depth=1", "--branch", s.ref, "--", s.url, checkout)
+	// never wait for credentials that can't be entered
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to clone the repository: %v: %s", err, strings.TrimSpace(string(out)))
+	}
+
+	return copyBundle(filepath.Join(checkout, filepath.FromSlash(s.path)), dir)
+}
+
+// copyBundle copies the files at the top level of the source directory into the directory
+func copyBundle(src, dir string) error {
+	infos, err := ioutil.ReadDir(src)
+	if err != nil {
+		return err
+	}
+	if err := os.Mkdir(dir, 0755); err != nil {
+		return err
+	}
+
+	writer := &bundleWriter{dir: dir}
+	for _, info := range infos {
+		if !isBundleFile(info) {
+			continue
+		}
+		f, err := os.Open(filepath.Join(src, info.Name()))
+		if err != nil {
+			return err
+		}
+		err = writer.write(info.Name(), f)
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("failed to copy %s: %v", info.Name(), err)
+		}
+	}
+	return nil
+}
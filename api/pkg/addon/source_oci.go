@@ -0,0 +1,268 @@
+/*
+Copyright 2020 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addon
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+)
+
+var (
+	ociManifestMediaTypes = []string{
+		"application/vnd.oci.image.manifest.v1+json",
+		"application/vnd.docker.distribution.manifest.v2+json",
+	}
+
+	bearerChallengeParamRegexp = regexp.MustCompile(`(\w+)="([^"]*)"`)
+)
+
+// maxManifestSize is the maximum size of the manifest of an artifact and of token responses
+const maxManifestSize = 1 << 20
+
+// ociHTTPClient is used to pull from registries, the timeout also covers reading the layers
+var ociHTTPClient = &http.Client{Timeout: sourceFetchTimeout}
+
+// ociSource pulls an addon bundle from an OCI registry. The artifact must have a single tar+gzip layer with the
+// manifests at its top level. Only public repositories and anonymous bearer tokens are supported.
+type ociSource struct {
+	registry string
+	name     string
+	tag      string
+	digest   string
+	client   *http.Client
+}
+
+func newOCISource(repository, tag, digest string) *ociSource {
+	parts := strings.SplitN(repository, "/", 2)
+	registry := parts[0]
+	if registry == "docker.io" {
+		registry = "registry-1.docker.io"
+	}
+	return &ociSource{
+		registry: registry,
+		name:     parts[1],
+		tag:      tag,
+		digest:   digest,
+		client:   ociHTTPClient,
+	}
+}
+
+type ociManifest struct {
+	Layers []ociDescriptor `json:"layers"`
+}
+
+type ociDescriptor struct {
+	MediaType string `json:"mediaType"`
+	Digest    string `json:"digest"`
+	Size      int64  `json:"size"`
+}
+
+func (s *ociSource) Key() string {
+	if s.digest != "" {
+		return fmt.Sprintf("oci://%s/%s@%s", s.registry, s.name, s.digest)
+	}
+	return fmt.Sprintf("oci://%s/%s:%s", s.registry, s.name, s.tag)
+}
+
+func (s *ociSource) Fetch(ctx context.Context, dir string) error {
+	reference := s.tag
+	if s.digest != "" {
+		reference = s.digest
+	}
+	resp, err := s.get(ctx, "manifests/"+reference, strings.Join(ociManifestMediaTypes, ", "))
+	if err != nil {
+		return fmt.Errorf("failed to get the manifest: %v", err)
+	}
+	data, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxManifestSize))
+	resp.Body.Close()
+	if err != nil {
+		return fmt.Errorf("failed to read the manifest: %v", err)
+	}
+	if digest := fmt.Sprintf("sha256:%x", sha256.Sum256(data)); s.digest != "" && digest != s.digest {
+		return fmt.Errorf("the manifest has the digest %s instead of %s", digest, s.digest)
+	}
+	manifest := &ociManifest{}
+	if err := json.Unmarshal(data, manifest); err != nil {
+		return fmt.Errorf("failed to decode the manifest: %v", err)
+	}
+
+	if len(manifest.Layers) != 1 {
+		return fmt.Errorf("the artifact has %d layers, but addon bundles must have exactly one", len(manifest.Layers))
+	}
+	layer := manifest.Layers[0]
+	if !strings.HasSuffix(layer.MediaType, "tar+gzip") && !strings.HasSuffix(layer.MediaType, "tar.gzip") {
+		return fmt.Errorf("the layer has the media type %q, but addon bundles must be tar+gzip", layer.MediaType)
+	}
+	if !strings.HasPrefix(layer.Digest, "sha256:") {
+		return fmt.Errorf("the layer has the digest %q, only sha256 digests are supported", layer.Digest)
+	}
+	if layer.Size > maxBundleSize {
+		return fmt.Errorf("the layer has %d bytes, but addon bundles may not exceed %d bytes", layer.Size, maxBundleSize)
+	}
+
+	resp, err = s.get(ctx, "blobs/"+layer.Digest, "")
+	if err != nil {
+		return fmt.Errorf("failed to get the layer: %v", err)
+	}
+	defer resp.Body.Close()
+
+	h := sha256.New()
+	// registries that lie about the size of the layer fail the digest check
+	body := io.TeeReader(io.LimitReader(resp.Body, maxBundleSize), h)
+	if err := extractBundle(body, dir); err != nil {
+		return fmt.Errorf("failed to extract the layer: %v", err)
+	}
+	if _, err := io.Copy(ioutil.Discard, body); err != nil {
+		return fmt.Errorf("failed to read the layer: %v", err)
+	}
+	if digest := "sha256:" + hex.EncodeToString(h.Sum(nil)); digest != layer.Digest {
+		return fmt.Errorf("the layer has the digest %s instead of %s", digest, layer.Digest)
+	}
+	return nil
+}
+
+// get requests the path from the repository API of the registry. It requests an anonymous bearer token if the
+// registry asks for one.
+func (s *ociSource) get(ctx context.Context, apiPath, accept string) (*http.Response, error) {
+	u := fmt.Sprintf("https://%s/v2/%s/%s", s.registry, s.name, apiPath)
+	resp, err := s.do(ctx, u, accept, "")
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		challenge := resp.Header.Get("WWW-Authenticate")
+		resp.Body.Close()
+
+		token, err := s.token(ctx, challenge)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get a token: %v", err)
+		}
+		if resp, err = s.do(ctx, u, accept, token); err != nil {
+			return nil, err
+		}
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("%s returned %s", u, resp.Status)
+	}
+	return resp, nil
+}
+
+func (s *ociSource) do(ctx context.Context, u, accept, token string) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return s.client.Do(req.WithContext(ctx))
+}
+
+// token requests an anonymous token from the realm of the bearer challenge
+func (s *ociSource) token(ctx context.Context, challenge string) (string, error) {
+	if !strings.HasPrefix(strings.ToLower(challenge), "bearer ") {
+		return "", fmt.Errorf("unsupported authentication challenge %q", challenge)
+	}
+	params := map[string]string{}
+	for _, match := range bearerChallengeParamRegexp.FindAllStringSubmatch(challenge, -1) {
+		params[strings.ToLower(match[1])] = match[2]
+	}
+	if params["realm"] == "" {
+		return "", fmt.Errorf("the authentication challenge %q has no realm", challenge)
+	}
+	if params["scope"] == "" {
+		params["scope"] = fmt.Sprintf("repository:%s:pull", s.name)
+	}
+
+	query := url.Values{}
+	query.Set("scope", params["scope"])
+	if params["service"] != "" {
+		query.Set("service", params["service"])
+	}
+	resp, err := s.do(ctx, params["realm"]+"?"+query.Encode(), "", "")
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("%s returned %s", params["realm"], resp.Status)
+	}
+
+	tokenResponse := struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}{}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxManifestSize)).Decode(&tokenResponse); err != nil {
+		return "", fmt.Errorf("failed to decode the token: %v", err)
+	}
+	if tokenResponse.Token != "" {
+		return tokenResponse.Token, nil
+	}
+	return tokenResponse.AccessToken, nil
+}
+
+// extractBundle extracts the files at the top level of the tar+gzip archive into the directory
+func extractBundle(r io.Reader, dir string) error {
+	gzipReader, err := gzip.NewReader(r)
+	if err != nil {
+		return err
+	}
+	defer gzipReader.Close()
+
+	if err := os.Mkdir(dir, 0755); err != nil {
+		return err
+	}
+
+	writer := &bundleWriter{dir: dir}
+	tarReader := tar.NewReader(gzipReader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		name := path.Clean(strings.TrimPrefix(header.Name, "/"))
+		if header.Typeflag != tar.TypeReg || strings.Contains(name, "/") || !isBundleFile(header.FileInfo()) {
+			continue
+		}
+		if err := writer.write(name, tarReader); err != nil {
+			return fmt.Errorf("failed to write %s: %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,478 @@
+/*
+Copyright 2020 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addon
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	kubermaticv1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+var testBundle = map[string]string{
+	"deployment.yaml":     "kind: Deployment\n",
+	"service.yaml":        "kind: Service\n",
+	".hidden":             "ignored",
+	"nested/ignored.yaml": "kind: ConfigMap\n",
+}
+
+func readBundle(t *testing.T, dir string) map[string]string {
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{}
+	for _, info := range infos {
+		data, err := ioutil.ReadFile(filepath.Join(dir, info.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[info.Name()] = string(data)
+	}
+	return files
+}
+
+func expectBundle(t *testing.T, dir string) {
+	expected := map[string]string{
+		"deployment.yaml": testBundle["deployment.yaml"],
+		"service.yaml":    testBundle["service.yaml"],
+	}
+	if files := readBundle(t, dir); !reflect.DeepEqual(files, expected) {
+		t.Fatalf("expected the bundle %v, got %v", expected, files)
+	}
+}
+
+func TestValidateSource(t *testing.T) {
+	testCases := []struct {
+		name         string
+		source       kubermaticv1.AddonSource
+		expectedErrs []string
+	}{
+		{
+			name: "valid oci source",
+			source: kubermaticv1.AddonSource{
+				OCI:      &kubermaticv1.OCIAddonSource{Repository: "quay.io/kubermatic/addons/csi", Tag: "v1.0.0"},
+				Checksum: "sha256:" + strings.Repeat("a", 64),
+			},
+		},
+		{
+			name: "valid git source",
+			source: kubermaticv1.AddonSource{
+				Git:      &kubermaticv1.GitAddonSource{URL: "https://github.com/kubermatic/addons", Ref: "v1.0.0", Path: "csi"},
+				Checksum: "sha256:" + strings.Repeat("a", 64),
+			},
+		},
+		{
+			name:   "oci source pinned by digest",
+			source: kubermaticv1.AddonSource{OCI: &kubermaticv1.OCIAddonSource{Repository: "quay.io/kubermatic/addons/csi", Digest: "sha256:" + strings.Repeat("a", 64)}},
+		},
+		{
+			name:         "git source without checksum",
+			source:       kubermaticv1.AddonSource{Git: &kubermaticv1.GitAddonSource{URL: "https://github.com/kubermatic/addons", Ref: "v1.0.0"}},
+			expectedErrs: []string{"spec.addon_sources[csi].checksum: Required value: must be set unless the oci artifact is pinned by its digest"},
+		},
+		{
+			name: "invalid oci digest",
+			source: kubermaticv1.AddonSource{
+				OCI:      &kubermaticv1.OCIAddonSource{Repository: "quay.io/kubermatic/addons/csi", Digest: "v1.0.0"},
+				Checksum: "sha256:" + strings.Repeat("a", 64),
+			},
+			expectedErrs: []string{`spec.addon_sources[csi].oci.digest: Invalid value: "v1.0.0": must be of the form "sha256:<hex>"`},
+		},
+		{
+			name:         "no source",
+			source:       kubermaticv1.AddonSource{},
+			expectedErrs: []string{"spec.addon_sources[csi]: Required value: exactly one of oci and git must be set"},
+		},
+		{
+			name: "both sources",
+			source: kubermaticv1.AddonSource{
+				OCI:      &kubermaticv1.OCIAddonSource{Repository: "quay.io/kubermatic/addons/csi", Tag: "v1.0.0"},
+				Git:      &kubermaticv1.GitAddonSource{URL: "https://github.com/kubermatic/addons", Ref: "v1.0.0"},
+				Checksum: "sha256:" + strings.Repeat("a", 64),
+			},
+			expectedErrs: []string{"spec.addon_sources[csi].git: Forbidden: may not be set together with oci"},
+		},
+		{
+			name:   "oci repository without registry",
+			source: kubermaticv1.AddonSource{OCI: &kubermaticv1.OCIAddonSource{Repository: "csi"}},
+			expectedErrs: []string{
+				`spec.addon_sources[csi].oci.repository: Invalid value: "csi": must include the registry`,
+				"spec.addon_sources[csi].oci.tag: Required value: either the tag or the digest must be set",
+				"spec.addon_sources[csi].checksum: Required value: must be set unless the oci artifact is pinned by its digest",
+			},
+		},
+		{
+			name: "git path outside of the repository",
+			source: kubermaticv1.AddonSource{
+				Git:      &kubermaticv1.GitAddonSource{URL: "https://github.com/kubermatic/addons", Ref: "v1.0.0", Path: "csi/../.."},
+				Checksum: "sha256:" + strings.Repeat("a", 64),
+			},
+			expectedErrs: []string{`spec.addon_sources[csi].git.path: Invalid value: "csi/../..": must be a relative path in the repository`},
+		},
+		{
+			name: "invalid checksum",
+			source: kubermaticv1.AddonSource{
+				Git:      &kubermaticv1.GitAddonSource{URL: "https://github.com/kubermatic/addons", Ref: "v1.0.0"},
+				Checksum: "md5:abc",
+			},
+			expectedErrs: []string{`spec.addon_sources[csi].checksum: Invalid value: "md5:abc": must be of the form "sha256:<hex>"`},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var errs []string
+			for _, err := range ValidateSource(tc.source, field.NewPath("spec", "addon_sources").Key("csi")) {
+				errs = append(errs, err.Error())
+			}
+			if !reflect.DeepEqual(errs, tc.expectedErrs) {
+				t.Fatalf("expected errors %q, got %q", tc.expectedErrs, errs)
+			}
+		})
+	}
+}
+
+func genBundleArchive(t *testing.T) []byte {
+	buf := &bytes.Buffer{}
+	gzipWriter := gzip.NewWriter(buf)
+	tarWriter := tar.NewWriter(gzipWriter)
+	if err := tarWriter.WriteHeader(&tar.Header{Name: "./", Typeflag: tar.TypeDir, Mode: 0755}); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range testBundle {
+		if err := tarWriter.WriteHeader(&tar.Header{Name: "./" + name, Typeflag: tar.TypeReg, Mode: 0644, Size: int64(len(content))}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tarWriter.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tarWriter.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gzipWriter.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// genManifest returns the manifest of an artifact with the layer and its digest
+func genManifest(t *testing.T, layer []byte, layerDigest string) ([]byte, string) {
+	manifest, err := json.Marshal(ociManifest{Layers: []ociDescriptor{{MediaType: "application/vnd.oci.image.layer.v1.tar+gzip", Digest: layerDigest, Size: int64(len(layer))}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return manifest, fmt.Sprintf("sha256:%x", sha256.Sum256(manifest))
+}
+
+// newTestRegistry returns a registry serving the layer as the addons/csi artifact under any reference, which requires
+// an anonymous token
+func newTestRegistry(t *testing.T, layer []byte, layerDigest string) *httptest.Server {
+	mux := http.NewServeMux()
+	var server *httptest.Server
+	server = httptest.NewTLSServer(mux)
+
+	authorized := func(w http.ResponseWriter, r *http.Request) bool {
+		if r.Header.Get("Authorization") == "Bearer test-token" {
+			return true
+		}
+		w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="registry",scope="repository:addons/csi:pull"`, server.URL))
+		w.WriteHeader(http.StatusUnauthorized)
+		return false
+	}
+
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("scope") != "repository:addons/csi:pull" || r.URL.Query().Get("service") != "registry" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"token": "test-token"})
+	})
+	manifest, _ := genManifest(t, layer, layerDigest)
+	mux.HandleFunc("/v2/addons/csi/manifests/", func(w http.ResponseWriter, r *http.Request) {
+		if !authorized(w, r) {
+			return
+		}
+		_, _ = w.Write(manifest)
+	})
+	mux.HandleFunc("/v2/addons/csi/blobs/"+layerDigest, func(w http.ResponseWriter, r *http.Request) {
+		if !authorized(w, r) {
+			return
+		}
+		_, _ = w.Write(layer)
+	})
+
+	return server
+}
+
+func TestOCISourceFetch(t *testing.T) {
+	layer := genBundleArchive(t)
+	layerDigest := fmt.Sprintf("sha256:%x", sha256.Sum256(layer))
+
+	_, manifestDigest := genManifest(t, layer, layerDigest)
+	_, changedManifestDigest := genManifest(t, layer, "sha256:"+strings.Repeat("0", 64))
+
+	testCases := []struct {
+		name        string
+		layerDigest string
+		tag         string
+		digest      string
+		expectedErr string
+	}{
+		{
+			name:        "bundle gets extracted",
+			layerDigest: layerDigest,
+			tag:         "v1",
+		},
+		{
+			name:        "bundle pinned by digest gets extracted",
+			layerDigest: layerDigest,
+			digest:      manifestDigest,
+		},
+		{
+			name:        "layer with a different digest",
+			layerDigest: "sha256:" + strings.Repeat("0", 64),
+			tag:         "v1",
+			expectedErr: fmt.Sprintf("the layer has the digest %s instead of sha256:%s", layerDigest, strings.Repeat("0", 64)),
+		},
+		{
+			name:        "manifest with a different digest",
+			layerDigest: "sha256:" + strings.Repeat("0", 64),
+			digest:      manifestDigest,
+			expectedErr: fmt.Sprintf("the manifest has the digest %s instead of %s", changedManifestDigest, manifestDigest),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := newTestRegistry(t, layer, tc.layerDigest)
+			defer server.Close()
+
+			dir, err := ioutil.TempDir("", "oci-source")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(dir)
+
+			source := newOCISource(strings.TrimPrefix(server.URL, "https://")+"/addons/csi", tc.tag, tc.digest)
+			source.client = server.Client()
+
+			err = source.Fetch(context.Background(), filepath.Join(dir, "bundle"))
+			if tc.expectedErr != "" {
+				if err == nil || err.Error() != tc.expectedErr {
+					t.Fatalf("expected the error %q, got %v", tc.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to fetch the bundle: %v", err)
+			}
+			expectBundle(t, filepath.Join(dir, "bundle"))
+		})
+	}
+}
+
+func TestGitSourceFetch(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir, err := ioutil.TempDir("", "git-source")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	repo := filepath.Join(dir, "repo")
+	for name, content := range testBundle {
+		file := filepath.Join(repo, "csi", name)
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(file, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, args := range [][]string{
+		{"init", "--quiet"},
+		{"add", "."},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "--quiet", "-m", "csi"},
+		{"tag", "v1"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repo
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("failed to run git %v: %v: %s", args, err, out)
+		}
+	}
+
+	source := &gitSource{url: repo, ref: "v1", path: "csi"}
+	if err := source.Fetch(context.Background(), filepath.Join(dir, "bundle")); err != nil {
+		t.Fatalf("failed to fetch the bundle: %v", err)
+	}
+	expectBundle(t, filepath.Join(dir, "bundle"))
+
+	missingRef := &gitSource{url: repo, ref: "v2", path: "csi"}
+	if err := missingRef.Fetch(context.Background(), filepath.Join(dir, "missing")); err == nil {
+		t.Fatal("expected an error for a ref that doesn't exist")
+	}
+}
+
+// fakeSource writes the files of the test bundle and counts how often it has been fetched
+type fakeSource struct {
+	fetches int
+}
+
+func (s *fakeSource) Key() string {
+	return "fake"
+}
+
+func (s *fakeSource) Fetch(_ context.Context, dir string) error {
+	s.fetches++
+	if err := os.Mkdir(dir, 0755); err != nil {
+		return err
+	}
+	for _, name := range []string{"deployment.yaml", "service.yaml"} {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(testBundle[name]), 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestSourceCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "source-cache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// the checksum of `sha256sum *` in the bundle
+	checksum := fmt.Sprintf("sha256:%x", sha256.Sum256([]byte(fmt.Sprintf(
+		"%x  deployment.yaml\n%x  service.yaml\n",
+		sha256.Sum256([]byte(testBundle["deployment.yaml"])),
+		sha256.Sum256([]byte(testBundle["service.yaml"])),
+	))))
+
+	cache := NewSourceCache(dir)
+	source := &fakeSource{}
+
+	bundleDir, err := cache.Get(context.Background(), source, checksum)
+	if err != nil {
+		t.Fatalf("failed to get the bundle: %v", err)
+	}
+	expectBundle(t, bundleDir)
+
+	if _, err := cache.Get(context.Background(), source, checksum); err != nil {
+		t.Fatalf("failed to get the cached bundle: %v", err)
+	}
+	if source.fetches != 1 {
+		t.Fatalf("expected the cached bundle to be used, got %d fetches", source.fetches)
+	}
+
+	// a corrupted bundle gets pulled again
+	if err := ioutil.WriteFile(filepath.Join(bundleDir, "service.yaml"), []byte("kind: Secret\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cache.Get(context.Background(), source, checksum); err != nil {
+		t.Fatalf("failed to get the bundle: %v", err)
+	}
+	if source.fetches != 2 {
+		t.Fatalf("expected the corrupted bundle to be pulled again, got %d fetches", source.fetches)
+	}
+	expectBundle(t, bundleDir)
+
+	wrongChecksum := "sha256:" + strings.Repeat("0", 64)
+	if _, err := cache.Get(context.Background(), source, wrongChecksum); err == nil || !strings.Contains(err.Error(), "doesn't match") {
+		t.Fatalf("expected a checksum mismatch, got %v", err)
+	}
+}
+
+func TestBundleWriterLimit(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bundle-writer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	writer := &bundleWriter{dir: dir}
+	if err := writer.write("deployment.yaml", bytes.NewReader(make([]byte, maxBundleSize-1))); err != nil {
+		t.Fatalf("failed to write a file within the limit: %v", err)
+	}
+	if err := writer.write("service.yaml", strings.NewReader("kind: Service\n")); err == nil || !strings.Contains(err.Error(), "exceeds") {
+		t.Fatalf("expected the bundle to exceed the limit, got %v", err)
+	}
+}
+
+// blockingSource blocks its fetch until it gets released
+type blockingSource struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func (s *blockingSource) Key() string {
+	return "blocking"
+}
+
+func (s *blockingSource) Fetch(ctx context.Context, dir string) error {
+	close(s.started)
+	<-s.release
+	return errors.New("released")
+}
+
+func TestSourceCachePullsBundlesInParallel(t *testing.T) {
+	dir, err := ioutil.TempDir("", "source-cache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	cache := NewSourceCache(dir)
+	blocking := &blockingSource{started: make(chan struct{}), release: make(chan struct{})}
+	done := make(chan error)
+	go func() {
+		_, err := cache.Get(context.Background(), blocking, "")
+		done <- err
+	}()
+	<-blocking.started
+
+	if _, err := cache.Get(context.Background(), &fakeSource{}, ""); err != nil {
+		t.Fatalf("failed to get a bundle while another one is pulled: %v", err)
+	}
+	close(blocking.release)
+	if err := <-done; err == nil {
+		t.Fatal("expected the blocked pull to fail")
+	}
+}
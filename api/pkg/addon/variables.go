@@ -0,0 +1,116 @@
+/*
+Copyright 2020 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addon
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+
+	kubermaticv1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// DefaultAndValidateVariables sets the defaults of the controls whose variables haven't been set and validates
+// the variables against the controls. The variables without a control aren't validated, as an addon doesn't
+// have to expose all of its variables in the UI.
+func DefaultAndValidateVariables(controls []kubermaticv1.AddonFormControl, variables map[string]interface{}, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	for _, control := range controls {
+		if control.InternalName == "" {
+			continue
+		}
+		path := fldPath.Child(control.InternalName)
+
+		value, ok := variables[control.InternalName]
+		if !ok || value == nil || value == "" {
+			if control.Default == "" {
+				if control.Required {
+					allErrs = append(allErrs, field.Required(path, ""))
+				}
+				continue
+			}
+
+			defaultValue, err := parseControlValue(control.Type, control.Default)
+			if err != nil {
+				allErrs = append(allErrs, field.InternalError(path, fmt.Errorf("invalid default %q: %v", control.Default, err)))
+				continue
+			}
+			variables[control.InternalName] = defaultValue
+			value = defaultValue
+		}
+
+		allErrs = append(allErrs, validateControlValue(control, value, path)...)
+	}
+
+	return allErrs
+}
+
+func validateControlValue(control kubermaticv1.AddonFormControl, value interface{}, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	switch control.Type {
+	case kubermaticv1.AddonFormControlTypeNumber:
+		switch value.(type) {
+		case float64, int64, int:
+		default:
+			allErrs = append(allErrs, field.Invalid(fldPath, value, "must be a number"))
+		}
+	case kubermaticv1.AddonFormControlTypeBoolean:
+		if _, ok := value.(bool); !ok {
+			allErrs = append(allErrs, field.Invalid(fldPath, value, "must be a boolean"))
+		}
+	case kubermaticv1.AddonFormControlTypeText, kubermaticv1.AddonFormControlTypeTextArea:
+		if _, ok := value.(string); !ok {
+			allErrs = append(allErrs, field.Invalid(fldPath, value, "must be a string"))
+		}
+	}
+	if len(allErrs) > 0 {
+		return allErrs
+	}
+
+	// the enum and the pattern apply to the value as it is rendered into the manifests
+	text := fmt.Sprint(value)
+	if len(control.Enum) > 0 && !sets.NewString(control.Enum...).Has(text) {
+		allErrs = append(allErrs, field.NotSupported(fldPath, value, control.Enum))
+	}
+	if control.Pattern != "" {
+		pattern, err := regexp.Compile(control.Pattern)
+		if err != nil {
+			allErrs = append(allErrs, field.InternalError(fldPath, fmt.Errorf("invalid pattern %q: %v", control.Pattern, err)))
+		} else if !pattern.MatchString(text) {
+			allErrs = append(allErrs, field.Invalid(fldPath, value, fmt.Sprintf("must match the regular expression %q", control.Pattern)))
+		}
+	}
+
+	return allErrs
+}
+
+// parseControlValue converts the text of a default to the type of the control
+func parseControlValue(controlType, text string) (interface{}, error) {
+	switch controlType {
+	case kubermaticv1.AddonFormControlTypeNumber:
+		return strconv.ParseFloat(text, 64)
+	case kubermaticv1.AddonFormControlTypeBoolean:
+		return strconv.ParseBool(text)
+	default:
+		return text, nil
+	}
+}
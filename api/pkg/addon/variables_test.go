@@ -0,0 +1,141 @@
+/*
+Copyright 2020 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addon
+
+import (
+	"reflect"
+	"testing"
+
+	kubermaticv1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func TestDefaultAndValidateVariables(t *testing.T) {
+	testCases := []struct {
+		name              string
+		controls          []kubermaticv1.AddonFormControl
+		variables         map[string]interface{}
+		expectedVariables map[string]interface{}
+		expectedErrs      []string
+	}{
+		{
+			name: "valid variables",
+			controls: []kubermaticv1.AddonFormControl{
+				{InternalName: "replicas", Type: kubermaticv1.AddonFormControlTypeNumber, Required: true},
+				{InternalName: "enabled", Type: kubermaticv1.AddonFormControlTypeBoolean},
+				{InternalName: "mode", Type: kubermaticv1.AddonFormControlTypeText, Enum: []string{"ipvs", "iptables"}},
+			},
+			variables:         map[string]interface{}{"replicas": float64(2), "enabled": true, "mode": "ipvs"},
+			expectedVariables: map[string]interface{}{"replicas": float64(2), "enabled": true, "mode": "ipvs"},
+		},
+		{
+			name: "missing required variable",
+			controls: []kubermaticv1.AddonFormControl{
+				{InternalName: "domain", Type: kubermaticv1.AddonFormControlTypeText, Required: true},
+			},
+			variables:         map[string]interface{}{"domain": ""},
+			expectedVariables: map[string]interface{}{"domain": ""},
+			expectedErrs:      []string{"spec.variables.domain: Required value"},
+		},
+		{
+			name: "missing optional variable",
+			controls: []kubermaticv1.AddonFormControl{
+				{InternalName: "domain", Type: kubermaticv1.AddonFormControlTypeText},
+			},
+			variables:         map[string]interface{}{},
+			expectedVariables: map[string]interface{}{},
+		},
+		{
+			name: "defaults get converted to the type of the control",
+			controls: []kubermaticv1.AddonFormControl{
+				{InternalName: "replicas", Type: kubermaticv1.AddonFormControlTypeNumber, Required: true, Default: "3"},
+				{InternalName: "enabled", Type: kubermaticv1.AddonFormControlTypeBoolean, Default: "true"},
+				{InternalName: "mode", Type: kubermaticv1.AddonFormControlTypeText, Default: "ipvs"},
+			},
+			variables:         map[string]interface{}{"enabled": nil},
+			expectedVariables: map[string]interface{}{"replicas": float64(3), "enabled": true, "mode": "ipvs"},
+		},
+		{
+			name: "invalid default",
+			controls: []kubermaticv1.AddonFormControl{
+				{InternalName: "replicas", Type: kubermaticv1.AddonFormControlTypeNumber, Default: "three"},
+			},
+			variables:         map[string]interface{}{},
+			expectedVariables: map[string]interface{}{},
+			expectedErrs:      []string{`spec.variables.replicas: Internal error: invalid default "three": strconv.ParseFloat: parsing "three": invalid syntax`},
+		},
+		{
+			name: "wrong types",
+			controls: []kubermaticv1.AddonFormControl{
+				{InternalName: "replicas", Type: kubermaticv1.AddonFormControlTypeNumber},
+				{InternalName: "enabled", Type: kubermaticv1.AddonFormControlTypeBoolean},
+				{InternalName: "domain", Type: kubermaticv1.AddonFormControlTypeTextArea},
+			},
+			variables:         map[string]interface{}{"replicas": "2", "enabled": "yes", "domain": float64(1)},
+			expectedVariables: map[string]interface{}{"replicas": "2", "enabled": "yes", "domain": float64(1)},
+			expectedErrs: []string{
+				`spec.variables.replicas: Invalid value: "2": must be a number`,
+				`spec.variables.enabled: Invalid value: "yes": must be a boolean`,
+				`spec.variables.domain: Invalid value: 1: must be a string`,
+			},
+		},
+		{
+			name: "value that isn't in the enum",
+			controls: []kubermaticv1.AddonFormControl{
+				{InternalName: "mode", Type: kubermaticv1.AddonFormControlTypeText, Enum: []string{"ipvs", "iptables"}},
+			},
+			variables:         map[string]interface{}{"mode": "nftables"},
+			expectedVariables: map[string]interface{}{"mode": "nftables"},
+			expectedErrs:      []string{`spec.variables.mode: Unsupported value: "nftables": supported values: "ipvs", "iptables"`},
+		},
+		{
+			name: "value that doesn't match the pattern",
+			controls: []kubermaticv1.AddonFormControl{
+				{InternalName: "domain", Type: kubermaticv1.AddonFormControlTypeText, Pattern: `^[a-z.]+$`},
+			},
+			variables:         map[string]interface{}{"domain": "Example.com"},
+			expectedVariables: map[string]interface{}{"domain": "Example.com"},
+			expectedErrs:      []string{`spec.variables.domain: Invalid value: "Example.com": must match the regular expression "^[a-z.]+$"`},
+		},
+		{
+			name: "variables without a control are kept",
+			controls: []kubermaticv1.AddonFormControl{
+				{InternalName: "domain", Type: kubermaticv1.AddonFormControlTypeText},
+			},
+			variables:         map[string]interface{}{"domain": "example.com", "internal": float64(1)},
+			expectedVariables: map[string]interface{}{"domain": "example.com", "internal": float64(1)},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			errs := DefaultAndValidateVariables(tc.controls, tc.variables, field.NewPath("spec", "variables"))
+
+			var errStrings []string
+			for _, err := range errs {
+				errStrings = append(errStrings, err.Error())
+			}
+			if !reflect.DeepEqual(errStrings, tc.expectedErrs) {
+				t.Fatalf("expected errors %q, got %q", tc.expectedErrs, errStrings)
+			}
+			if !reflect.DeepEqual(tc.variables, tc.expectedVariables) {
+				t.Fatalf("expected variables %v, got %v", tc.expectedVariables, tc.variables)
+			}
+		})
+	}
+}
@@ -98,12 +98,19 @@ type DatacenterSpec struct {
 	RequiredEmailDomains []string `json:"requiredEmailDomains,omitempty"`
 
 	// EnforceAuditLogging enforces audit logging on every cluster within the DC,
-	// ignoring cluster-specific settings.
+	// ignoring the cluster-specific Enabled setting.
 	EnforceAuditLogging bool `json:"enforceAuditLogging"`
 
+	// EnforcedAuditPolicyPreset is the minimum audit policy preset of the clusters within the DC,
+	// if EnforceAuditLogging is set. More detailed presets are kept, custom policies are replaced.
+	EnforcedAuditPolicyPreset kubermaticv1.AuditPolicyPreset `json:"enforcedAuditPolicyPreset,omitempty"`
+
 	// EnforcePodSecurityPolicy enforces pod security policy plugin on every clusters within the DC,
 	// ignoring cluster-specific settings
 	EnforcePodSecurityPolicy bool `json:"enforcePodSecurityPolicy"`
+
+	// BackupPolicy is the default etcd backup policy of all clusters within the DC
+	BackupPolicy *kubermaticv1.EtcdBackupPolicy `json:"backupPolicy,omitempty"`
 }
 
 // DatacenterList represents a list of datacenters
@@ -460,6 +467,12 @@ type PublicServiceAccountToken struct {
 	// Expiry is a timestamp representing the time when this token will expire.
 	// swagger:strfmt date-time
 	Expiry Time `json:"expiry,omitempty"`
+	// Clusters restricts the token to the given clusters, empty means all clusters of the project
+	Clusters []string `json:"clusters,omitempty"`
+	// ReadOnly restricts the token to read-only requests, which don't return credentials like kubeconfigs
+	ReadOnly bool `json:"readOnly,omitempty"`
+	// Revoked indicates that the token has been put on the revocation list and can no longer be used
+	Revoked bool `json:"revoked,omitempty"`
 }
 
 // ServiceAccountToken represent an API service account token
@@ -479,6 +492,10 @@ type Project struct {
 	// Owners an optional owners list for the given project
 	Owners         []User `json:"owners,omitempty"`
 	ClustersNumber int    `json:"clustersNumber,omitempty"`
+	// Quota limits the resources of the project, it can only be changed by admins
+	Quota *kubermaticv1.ProjectQuota `json:"quota,omitempty"`
+	// Usage is the resource usage of a project with a quota
+	Usage *kubermaticv1.ProjectResourceUsage `json:"usage,omitempty"`
 }
 
 // Kubeconfig is a clusters kubeconfig
@@ -605,6 +622,34 @@ type CreateClusterSpec struct {
 	NodeDeployment *NodeDeployment `json:"nodeDeployment,omitempty"`
 }
 
+// ClusterTemplate is a blueprint from which clusters can be created
+// swagger:model ClusterTemplate
+type ClusterTemplate struct {
+	ObjectMeta `json:",inline"`
+	// Scope is either user, project or global
+	Scope string `json:"scope"`
+	// ProjectID is the project the template was created in
+	ProjectID string `json:"projectID,omitempty"`
+	// Owner is the email of the user who created the template
+	Owner string `json:"owner"`
+	// Credential is the name of the preset which provides the cloud credentials of the clusters
+	Credential string `json:"credential,omitempty"`
+	// Type is the type of the clusters, either kubernetes or openshift
+	Type   string            `json:"type"`
+	Labels map[string]string `json:"labels,omitempty"`
+	// Spec is the spec of the clusters, it never contains any cloud credentials or other secrets
+	Spec ClusterSpec `json:"spec"`
+	// NodeDeployment is the initial node deployment of the clusters
+	NodeDeployment *NodeDeployment `json:"nodeDeployment,omitempty"`
+	// RequiredSecrets are the secrets which are not stored in the template and have to be provided when
+	// creating clusters from it, out of oidcClientSecret and openshiftImagePullSecret
+	RequiredSecrets []string `json:"requiredSecrets,omitempty"`
+}
+
+// ClusterTemplateList represents a list of cluster templates
+// swagger:model ClusterTemplateList
+type ClusterTemplateList []ClusterTemplate
+
 const (
 	// OpenShiftClusterType defines the OpenShift cluster type
 	OpenShiftClusterType string = "openshift"
@@ -658,6 +703,12 @@ type ClusterSpec struct {
 	// AuditLogging
 	AuditLogging *kubermaticv1.AuditLoggingSettings `json:"auditLogging,omitempty"`
 
+	// BackupPolicy overrides the etcd backup policy of the datacenter
+	BackupPolicy *kubermaticv1.EtcdBackupPolicy `json:"backupPolicy,omitempty"`
+
+	// EncryptionAtRest enables the encryption of Secrets stored in the etcd of the cluster
+	EncryptionAtRest *kubermaticv1.EncryptionAtRestSettings `json:"encryptionAtRest,omitempty"`
+
 	// Openshift holds all openshift-specific settings
 	Openshift *kubermaticv1.Openshift `json:"openshift,omitempty"`
 }
@@ -675,6 +726,8 @@ func (cs *ClusterSpec) MarshalJSON() ([]byte, error) {
 		UsePodNodeSelectorAdmissionPlugin   bool                                   `json:"usePodNodeSelectorAdmissionPlugin,omitempty"`
 		AuditLogging                        *kubermaticv1.AuditLoggingSettings     `json:"auditLogging,omitempty"`
 		AdmissionPlugins                    []string                               `json:"admissionPlugins,omitempty"`
+		BackupPolicy                        *kubermaticv1.EtcdBackupPolicy         `json:"backupPolicy,omitempty"`
+		EncryptionAtRest                    *kubermaticv1.EncryptionAtRestSettings `json:"encryptionAtRest,omitempty"`
 	}{
 		Cloud: PublicCloudSpec{
 			DatacenterName: cs.Cloud.DatacenterName,
@@ -699,6 +752,8 @@ func (cs *ClusterSpec) MarshalJSON() ([]byte, error) {
 		UsePodNodeSelectorAdmissionPlugin:   cs.UsePodNodeSelectorAdmissionPlugin,
 		AuditLogging:                        cs.AuditLogging,
 		AdmissionPlugins:                    cs.AdmissionPlugins,
+		BackupPolicy:                        cs.BackupPolicy,
+		EncryptionAtRest:                    cs.EncryptionAtRest,
 	})
 
 	return ret, err
@@ -879,6 +934,9 @@ type ClusterStatus struct {
 
 	// URL specifies the address at which the cluster is available
 	URL string `json:"url"`
+
+	// Backup contains information about the etcd backups of the cluster
+	Backup *EtcdBackupStatus `json:"backup,omitempty"`
 }
 
 // ClusterHealth stores health information about the cluster's components.
@@ -893,6 +951,21 @@ type ClusterHealth struct {
 	UserClusterControllerManager kubermaticv1.HealthStatus `json:"userClusterControllerManager"`
 }
 
+// ClusterState represents a cluster together with its health and conditions, as streamed by the websocket API
+type ClusterState struct {
+	Cluster    *Cluster                        `json:"cluster"`
+	Health     ClusterHealth                   `json:"health"`
+	Conditions []kubermaticv1.ClusterCondition `json:"conditions,omitempty"`
+}
+
+// WatchEvent represents a change of a resource streamed by the websocket API
+type WatchEvent struct {
+	// Type is one of ADDED, MODIFIED or DELETED
+	Type string `json:"type"`
+	// Object is the API representation of the resource
+	Object interface{} `json:"object"`
+}
+
 // AccessibleAddons represents an array of addons that can be configured in the user clusters.
 // swagger:model AccessibleAddons
 type AccessibleAddons []string
@@ -902,7 +975,8 @@ type AccessibleAddons []string
 type Addon struct {
 	ObjectMeta `json:",inline"`
 
-	Spec AddonSpec `json:"spec"`
+	Spec   AddonSpec   `json:"spec"`
+	Status AddonStatus `json:"status,omitempty"`
 }
 
 // AddonSpec addon specification
@@ -914,6 +988,38 @@ type AddonSpec struct {
 	IsDefault bool `json:"isDefault,omitempty"`
 }
 
+// AddonStatus contains the health of an addon installed in the cluster
+// swagger:model AddonStatus
+type AddonStatus struct {
+	// InstalledVersion is the version of the addon whose manifests have been applied
+	InstalledVersion string `json:"installedVersion,omitempty"`
+	// Ready indicates whether all objects of the addon are ready
+	Ready bool `json:"ready"`
+	// Drifted indicates whether objects of the addon differ from the rendered manifests
+	Drifted bool `json:"drifted"`
+	// Objects are the objects applied for the addon with their readiness and drift
+	Objects []AddonObjectStatus `json:"objects,omitempty"`
+}
+
+// AddonObjectStatus is the state of an object applied for an addon in the user cluster
+// swagger:model AddonObjectStatus
+type AddonObjectStatus struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name"`
+	// Ready indicates whether the object is ready. Deployments are ready when they are available,
+	// DaemonSets when they are rolled out and CustomResourceDefinitions when they are established,
+	// all other objects as soon as they exist.
+	Ready bool `json:"ready"`
+	// Drifted indicates whether the object differs from the rendered manifest
+	Drifted bool `json:"drifted"`
+	// DriftedFields are the paths of the fields whose values differ from the rendered manifest
+	DriftedFields []string `json:"driftedFields,omitempty"`
+	// Message explains why the object isn't ready
+	Message string `json:"message,omitempty"`
+}
+
 // AddonConfig represents a addon configuration
 // swagger:model AddonConfig
 type AddonConfig struct {
@@ -922,6 +1028,70 @@ type AddonConfig struct {
 	Spec kubermaticv1.AddonConfigSpec `json:"spec"`
 }
 
+// EtcdRestore represents a restore of the etcd of a cluster from a backup snapshot
+// swagger:model EtcdRestore
+type EtcdRestore struct {
+	ObjectMeta `json:",inline"`
+
+	Spec   EtcdRestoreSpec   `json:"spec"`
+	Status EtcdRestoreStatus `json:"status,omitempty"`
+}
+
+// EtcdRestoreSpec etcd restore specification
+// swagger:model EtcdRestoreSpec
+type EtcdRestoreSpec struct {
+	// BackupName is the name of the snapshot object in the backup bucket
+	BackupName string `json:"backupName"`
+}
+
+// EtcdRestoreStatus contains the progress of an etcd restore
+// swagger:model EtcdRestoreStatus
+type EtcdRestoreStatus struct {
+	// Phase is one of Started, Running, Completed or Failed
+	Phase string `json:"phase,omitempty"`
+	// Conditions contains one condition per restore step
+	Conditions []EtcdRestoreCondition `json:"conditions,omitempty"`
+}
+
+// EtcdRestoreCondition represents a single step of an etcd restore
+// swagger:model EtcdRestoreCondition
+type EtcdRestoreCondition struct {
+	// Type of the condition
+	Type string `json:"type"`
+	// Status of the condition, one of True, False, Unknown
+	Status string `json:"status"`
+	// LastTransitionTime is the last time the condition changed its status
+	LastTransitionTime Time `json:"lastTransitionTime,omitempty"`
+	// Reason is a brief reason for the condition's last transition
+	Reason string `json:"reason,omitempty"`
+	// Message is a human readable message indicating details about the last transition
+	Message string `json:"message,omitempty"`
+}
+
+// EtcdBackupStatus contains information about the etcd backups of a cluster
+// swagger:model EtcdBackupStatus
+type EtcdBackupStatus struct {
+	// LastSuccessfulBackupTime is the completion time of the last successful backup
+	LastSuccessfulBackupTime *Time `json:"lastSuccessfulBackupTime,omitempty"`
+	// LastFailedBackupTime is the time the last backup failed
+	LastFailedBackupTime *Time `json:"lastFailedBackupTime,omitempty"`
+	// LastFailureMessage is the reason the last backup failed
+	LastFailureMessage string `json:"lastFailureMessage,omitempty"`
+	// LatestSnapshot is the newest snapshot of the cluster
+	LatestSnapshot *EtcdSnapshot `json:"latestSnapshot,omitempty"`
+}
+
+// EtcdSnapshot represents an etcd snapshot in the backup bucket of a cluster
+// swagger:model EtcdSnapshot
+type EtcdSnapshot struct {
+	// Name of the snapshot, it can be used as the backupName of an etcd restore
+	Name string `json:"name"`
+	// Size of the snapshot in bytes
+	Size int64 `json:"size"`
+	// CreationTimestamp is the time the snapshot was uploaded
+	CreationTimestamp Time `json:"creationTimestamp"`
+}
+
 // ClusterList represents a list of clusters
 // swagger:model ClusterList
 type ClusterList []Cluster
@@ -1482,6 +1652,41 @@ type AdmissionPlugin struct {
 	FromVersion *ksemver.Semver `json:"fromVersion,omitempty"`
 }
 
+// GroupProjectBinding binds a group of the identity provider to a project role
+// swagger:model GroupProjectBinding
+type GroupProjectBinding struct {
+	Name string `json:"name"`
+	// Group is the name of the group as found in the "groups" claim of the ID token
+	Group     string `json:"group"`
+	ProjectID string `json:"projectID"`
+	// Role is either one of "owners", "editors" and "viewers" or the name of a custom project role.
+	// Members of custom project roles have no access to the user clusters, thus to their nodes and node deployments.
+	Role string `json:"role"`
+}
+
+// AuditRecord is an entry of the audit trail of the mutations performed through the API
+// swagger:model AuditRecord
+type AuditRecord struct {
+	Timestamp Time `json:"timestamp"`
+	// Actor is the email address of the user or the service account that sent the request
+	Actor     string `json:"actor"`
+	ProjectID string `json:"projectID,omitempty"`
+	// Verb is one of "create", "update", "patch" and "delete"
+	Verb string `json:"verb"`
+	// Resource is the kind of the resource, for example "cluster", "nodedeployment" or "serviceaccounttoken"
+	Resource string `json:"resource"`
+	Path     string `json:"path"`
+	// Request is the request as JSON with all credentials redacted
+	Request string `json:"request,omitempty"`
+	// Before is the state of the resource before the update or patch request as JSON with all credentials redacted
+	Before string `json:"before,omitempty"`
+	// After is the state of the resource after the successful create, update or patch request as JSON with all credentials redacted
+	After      string `json:"after,omitempty"`
+	Succeeded  bool   `json:"succeeded"`
+	StatusCode int    `json:"statusCode,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
 // Seed represents a seed object
 // swagger:model Seed
 type Seed struct {
@@ -0,0 +1,113 @@
+/*
+Copyright 2020 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collectors
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	kubermaticv1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
+	"github.com/kubermatic/kubermatic/api/pkg/resources/certificates"
+
+	corev1 "k8s.io/api/core/v1"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// CertificateCollector exports the expiry of the certificates stored in the secrets of the cluster namespaces
+type CertificateCollector struct {
+	client ctrlruntimeclient.Reader
+
+	certificateExpiry *prometheus.Desc
+}
+
+// MustRegisterCertificateCollector registers the certificate collector at the given prometheus registry
+func MustRegisterCertificateCollector(registry prometheus.Registerer, client ctrlruntimeclient.Reader) {
+	cc := &CertificateCollector{
+		client: client,
+		certificateExpiry: prometheus.NewDesc(
+			prefix+"certificate_expiry_days",
+			"Days until the certificate expires",
+			[]string{"cluster", "secret", "key", "common_name", "ca"},
+			nil,
+		),
+	}
+
+	registry.MustRegister(cc)
+}
+
+// Describe returns the metrics descriptors
+func (cc CertificateCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- cc.certificateExpiry
+}
+
+// Collect gets called by prometheus to collect the metrics
+func (cc CertificateCollector) Collect(ch chan<- prometheus.Metric) {
+	clusters := &kubermaticv1.ClusterList{}
+	if err := cc.client.List(context.Background(), clusters); err != nil {
+		utilruntime.HandleError(fmt.Errorf("failed to list clusters in CertificateCollector: %v", err))
+		return
+	}
+
+	for _, cluster := range clusters.Items {
+		if cluster.Status.NamespaceName == "" {
+			continue
+		}
+		secrets := &corev1.SecretList{}
+		if err := cc.client.List(context.Background(), secrets, ctrlruntimeclient.InNamespace(cluster.Status.NamespaceName)); err != nil {
+			utilruntime.HandleError(fmt.Errorf("failed to list secrets of cluster %s in CertificateCollector: %v", cluster.Name, err))
+			continue
+		}
+		for _, secret := range secrets.Items {
+			cc.collectSecret(ch, cluster.Name, &secret)
+		}
+	}
+}
+
+func (cc *CertificateCollector) collectSecret(ch chan<- prometheus.Metric, clusterName string, secret *corev1.Secret) {
+	// A key can contain several certificates with the same common name, e.g. a CA bundle.
+	// Only the one which expires first is exported.
+	expiry := map[[2]string]certificates.SecretCertificate{}
+	var order [][2]string
+	for _, c := range certificates.GetSecretCertificates(secret) {
+		id := [2]string{c.Key, c.Cert.Subject.CommonName}
+		existing, found := expiry[id]
+		if !found {
+			order = append(order, id)
+		}
+		if !found || c.Cert.NotAfter.Before(existing.Cert.NotAfter) {
+			expiry[id] = c
+		}
+	}
+
+	for _, id := range order {
+		c := expiry[id]
+		ch <- prometheus.MustNewConstMetric(
+			cc.certificateExpiry,
+			prometheus.GaugeValue,
+			time.Until(c.Cert.NotAfter).Hours()/24,
+			clusterName,
+			secret.Name,
+			c.Key,
+			c.Cert.Subject.CommonName,
+			fmt.Sprintf("%t", c.Cert.IsCA),
+		)
+	}
+}
@@ -0,0 +1,1139 @@
+/*
+Copyright 2020 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clustermigration
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	k8cuserclusterclient "github.com/kubermatic/kubermatic/api/pkg/cluster/client"
+	backupcontroller "github.com/kubermatic/kubermatic/api/pkg/controller/seed-controller-manager/backup"
+	kubermaticv1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
+	"github.com/kubermatic/kubermatic/api/pkg/provider"
+	"github.com/kubermatic/kubermatic/api/pkg/resources"
+	"github.com/kubermatic/kubermatic/api/pkg/resources/etcd"
+	"github.com/kubermatic/kubermatic/api/pkg/util/workerlabel"
+	clusterv1alpha1 "github.com/kubermatic/machine-controller/pkg/apis/cluster/v1alpha1"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	utilpointer "k8s.io/utils/pointer"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	ControllerName = "cluster_migration_controller"
+
+	// ActiveMigrationAnnotationName is set on the source cluster while a migration is in progress. It holds
+	// the name of the ClusterMigration and makes sure only one migration per cluster is running at a time.
+	ActiveMigrationAnnotationName = "kubermatic.io/active-cluster-migration"
+	// MigratedFromAnnotationName is set on the copy of the cluster in the target seed. It holds the name
+	// of the ClusterMigration that created the copy, so an abort never deletes a cluster it didn't create.
+	MigratedFromAnnotationName = "kubermatic.io/cluster-migration"
+	// NodesMigratedAnnotationName is set on the machine template of the MachineDeployments of the cluster.
+	// It holds the name of the ClusterMigration and changing it rolls out machines that join the target seed.
+	NodesMigratedAnnotationName = "kubermatic.io/cluster-migration-nodes"
+	// nodesRolloutStartedReason is the reason of the NodesUpdated condition once the first MachineDeployment
+	// is about to be rolled out. The migration can't be aborted from then on.
+	nodesRolloutStartedReason = "RolloutStarted"
+	// snapshotJobPrefix defines the prefix used for the names of the snapshot jobs
+	snapshotJobPrefix = "etcd-migration"
+	// pollInterval is used to wait for the resources in the seeds, which are not watched
+	pollInterval = 10 * time.Second
+)
+
+// snapshotLister lists the etcd snapshots of a cluster in a backup destination
+type snapshotLister func(ctx context.Context, client ctrlruntimeclient.Client, cluster *kubermaticv1.Cluster, destination *kubermaticv1.BackupDestination) ([]kubermaticv1.EtcdSnapshot, error)
+
+type userClusterConnectionProvider interface {
+	GetClient(*kubermaticv1.Cluster, ...k8cuserclusterclient.ConfigOption) (ctrlruntimeclient.Client, error)
+}
+
+// Reconciler migrates user clusters between datacenters and seeds
+type Reconciler struct {
+	ctx         context.Context
+	log         *zap.SugaredLogger
+	workerName  string
+	client      ctrlruntimeclient.Client
+	seedsGetter provider.SeedsGetter
+	seedClients map[string]ctrlruntimeclient.Client
+	recorder    record.EventRecorder
+
+	// userClusterConnectionProviders connect to the user clusters served by the seed of the same name
+	userClusterConnectionProviders map[string]userClusterConnectionProvider
+	listSnapshots                  snapshotLister
+}
+
+// Add creates a new cluster migration controller that is responsible for processing ClusterMigration objects
+func Add(
+	ctx context.Context,
+	mgr manager.Manager,
+	seedManagers map[string]manager.Manager,
+	log *zap.SugaredLogger,
+	workerName string,
+	numWorkers int,
+	seedsGetter provider.SeedsGetter,
+) error {
+	reconciler := &Reconciler{
+		ctx:                            ctx,
+		log:                            log.Named(ControllerName),
+		workerName:                     workerName,
+		client:                         mgr.GetClient(),
+		seedsGetter:                    seedsGetter,
+		seedClients:                    map[string]ctrlruntimeclient.Client{},
+		recorder:                       mgr.GetEventRecorderFor(ControllerName),
+		userClusterConnectionProviders: map[string]userClusterConnectionProvider{},
+		listSnapshots:                  backupcontroller.ListSnapshots,
+	}
+
+	// The MachineDeployments of the user clusters are rolled out by the migration
+	if err := clusterv1alpha1.AddToScheme(mgr.GetScheme()); err != nil {
+		return fmt.Errorf("failed to register scheme %s: %v", clusterv1alpha1.SchemeGroupVersion, err)
+	}
+
+	for seedName, seedManager := range seedManagers {
+		// A migration only reads a few objects per poll, so the seeds are queried directly
+		// instead of caching all secrets, jobs and workloads of every seed.
+		seedClient := &ctrlruntimeclient.DelegatingClient{
+			Reader:       seedManager.GetAPIReader(),
+			Writer:       seedManager.GetClient(),
+			StatusClient: seedManager.GetClient(),
+		}
+		reconciler.seedClients[seedName] = seedClient
+
+		// The master can't reach the user clusters through the internal addresses of the seeds
+		userClusterConnectionProvider, err := k8cuserclusterclient.NewExternal(seedClient)
+		if err != nil {
+			return fmt.Errorf("failed to create user cluster connection provider for seed %s: %v", seedName, err)
+		}
+		reconciler.userClusterConnectionProviders[seedName] = userClusterConnectionProvider
+	}
+
+	c, err := controller.New(ControllerName, mgr, controller.Options{Reconciler: reconciler, MaxConcurrentReconciles: numWorkers})
+	if err != nil {
+		return fmt.Errorf("failed to construct controller: %v", err)
+	}
+
+	if err := c.Watch(
+		&source.Kind{Type: &kubermaticv1.ClusterMigration{}},
+		&handler.EnqueueRequestForObject{},
+		workerlabel.Predicates(workerName),
+	); err != nil {
+		return fmt.Errorf("failed to watch ClusterMigrations: %v", err)
+	}
+
+	return nil
+}
+
+// migrationState holds the seeds and clients a migration works with once it has been validated
+type migrationState struct {
+	sourceSeed   *kubermaticv1.Seed
+	targetSeed   *kubermaticv1.Seed
+	sourceClient ctrlruntimeclient.Client
+	targetClient ctrlruntimeclient.Client
+}
+
+type migrationStep struct {
+	condition kubermaticv1.ClusterMigrationConditionType
+	step      func(context.Context, *zap.SugaredLogger, *kubermaticv1.ClusterMigration, *migrationState) (*reconcile.Result, error)
+}
+
+func (r *Reconciler) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	log := r.log.With("request", request)
+	log.Debug("Processing")
+
+	migration := &kubermaticv1.ClusterMigration{}
+	if err := r.client.Get(r.ctx, request.NamespacedName, migration); err != nil {
+		if kerrors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	if migration.DeletionTimestamp != nil || migration.Status.Phase == kubermaticv1.ClusterMigrationPhaseAborted {
+		return reconcile.Result{}, nil
+	}
+
+	log = log.With("cluster", migration.Spec.ClusterName)
+	var result *reconcile.Result
+	var err error
+	switch {
+	case migration.Spec.Abort && abortable(migration):
+		result, err = r.abort(r.ctx, log, migration)
+	case migration.Status.Phase == kubermaticv1.ClusterMigrationPhaseCompleted || migration.Status.Phase == kubermaticv1.ClusterMigrationPhaseFailed:
+		return reconcile.Result{}, nil
+	default:
+		result, err = r.reconcile(r.ctx, log, migration)
+	}
+	if err != nil {
+		log.Errorw("Reconciling failed", zap.Error(err))
+		r.recorder.Eventf(migration, corev1.EventTypeWarning, "ReconcilingError", "%v", err)
+	}
+	if result == nil {
+		result = &reconcile.Result{}
+	}
+	return *result, err
+}
+
+func (r *Reconciler) reconcile(ctx context.Context, log *zap.SugaredLogger, migration *kubermaticv1.ClusterMigration) (*reconcile.Result, error) {
+	if migration.Spec.Abort && !migration.Status.HasConditionValue(kubermaticv1.ClusterMigrationConditionAborted, corev1.ConditionFalse) {
+		if err := r.updateStatus(ctx, migration, func(migration *kubermaticv1.ClusterMigration) {
+			setMigrationCondition(migration, kubermaticv1.ClusterMigrationConditionAborted, corev1.ConditionFalse, nodesRolloutStartedReason, "the migration can't be aborted anymore because the nodes are being moved to the target seed")
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	if !migration.Status.HasConditionValue(kubermaticv1.ClusterMigrationConditionValidated, corev1.ConditionTrue) {
+		if err := r.validate(ctx, log, migration); err != nil || migrationFinished(migration) || migration.Status.Phase == kubermaticv1.ClusterMigrationPhaseFailed {
+			return nil, err
+		}
+	}
+
+	state, err := r.getState(migration)
+	if err != nil {
+		return nil, err
+	}
+
+	steps := []migrationStep{
+		{kubermaticv1.ClusterMigrationConditionDatacenterUpdated, r.updateDatacenter},
+	}
+	if migration.Status.SourceSeed != migration.Status.TargetSeed {
+		steps = []migrationStep{
+			{kubermaticv1.ClusterMigrationConditionSourcePaused, r.pauseSource},
+			{kubermaticv1.ClusterMigrationConditionSourceApiserverScaledDown, r.scaleDownSourceApiserver},
+			{kubermaticv1.ClusterMigrationConditionSnapshotCreated, r.createSnapshot},
+			{kubermaticv1.ClusterMigrationConditionTargetClusterCreated, r.createTargetCluster},
+			{kubermaticv1.ClusterMigrationConditionEtcdRestored, r.restoreEtcd},
+			{kubermaticv1.ClusterMigrationConditionAddressUpdated, r.waitForAddress},
+			{kubermaticv1.ClusterMigrationConditionNodesUpdated, r.updateNodes},
+			{kubermaticv1.ClusterMigrationConditionSourceCleanedUp, r.cleanupSource},
+		}
+	}
+
+	for _, s := range steps {
+		if migration.Status.HasConditionValue(s.condition, corev1.ConditionTrue) {
+			continue
+		}
+
+		log := log.With("step", s.condition)
+		log.Debug("Processing migration step")
+		result, err := s.step(ctx, log, migration, state)
+		if err != nil || result != nil || migration.Status.Phase == kubermaticv1.ClusterMigrationPhaseFailed {
+			return result, err
+		}
+
+		if err := r.updateStatus(ctx, migration, func(migration *kubermaticv1.ClusterMigration) {
+			setMigrationCondition(migration, s.condition, corev1.ConditionTrue, "", "")
+		}); err != nil {
+			return nil, err
+		}
+		r.recorder.Eventf(migration, corev1.EventTypeNormal, string(s.condition), "Migration step %s completed", s.condition)
+	}
+
+	log.Info("Migration completed")
+	return nil, r.updateStatus(ctx, migration, func(migration *kubermaticv1.ClusterMigration) {
+		migration.Status.Phase = kubermaticv1.ClusterMigrationPhaseCompleted
+	})
+}
+
+// validate checks the cluster and the target datacenter and records the seeds involved in the migration.
+// Problems that won't go away by themselves fail the migration, all other errors are returned.
+func (r *Reconciler) validate(ctx context.Context, log *zap.SugaredLogger, migration *kubermaticv1.ClusterMigration) error {
+	invalid := func(reason, message string) error {
+		return r.fail(ctx, migration, kubermaticv1.ClusterMigrationConditionValidated, reason, message)
+	}
+
+	if migration.Spec.ClusterName == "" || migration.Spec.TargetDatacenter == "" {
+		return invalid("InvalidSpec", "the cluster name and the target datacenter are required")
+	}
+
+	var cluster *kubermaticv1.Cluster
+	var sourceSeedName string
+	for seedName, seedClient := range r.seedClients {
+		candidate := &kubermaticv1.Cluster{}
+		if err := seedClient.Get(ctx, types.NamespacedName{Name: migration.Spec.ClusterName}, candidate); err != nil {
+			if kerrors.IsNotFound(err) {
+				continue
+			}
+			return fmt.Errorf("failed to get cluster from seed %s: %v", seedName, err)
+		}
+		if cluster != nil {
+			return invalid("ClusterFoundInSeveralSeeds", fmt.Sprintf("cluster exists in the seeds %s and %s", sourceSeedName, seedName))
+		}
+		cluster = candidate
+		sourceSeedName = seedName
+	}
+	if cluster == nil {
+		return invalid("ClusterNotFound", "cluster doesn't exist in any seed")
+	}
+	if cluster.DeletionTimestamp != nil {
+		return invalid("ClusterDeleted", "cluster is being deleted")
+	}
+	if activeMigration, ok := cluster.Annotations[ActiveMigrationAnnotationName]; ok && activeMigration != migration.Name {
+		return invalid("MigrationInProgress", fmt.Sprintf("cluster is being migrated by %s", activeMigration))
+	}
+	if cluster.Spec.Cloud.DatacenterName == migration.Spec.TargetDatacenter {
+		return invalid("AlreadyInDatacenter", "cluster already runs in the target datacenter")
+	}
+
+	seeds, err := r.seedsGetter()
+	if err != nil {
+		return fmt.Errorf("failed to get seeds: %v", err)
+	}
+	sourceSeed, ok := seeds[sourceSeedName]
+	if !ok {
+		return fmt.Errorf("seed %s doesn't exist", sourceSeedName)
+	}
+	var targetSeed *kubermaticv1.Seed
+	var targetDatacenter kubermaticv1.Datacenter
+	for _, seed := range seeds {
+		if datacenter, ok := seed.Spec.Datacenters[migration.Spec.TargetDatacenter]; ok {
+			targetSeed = seed
+			targetDatacenter = datacenter
+			break
+		}
+	}
+	if targetSeed == nil {
+		return invalid("DatacenterNotFound", fmt.Sprintf("datacenter %s doesn't exist", migration.Spec.TargetDatacenter))
+	}
+
+	clusterProvider, err := provider.ClusterCloudProviderName(cluster.Spec.Cloud)
+	if err != nil {
+		return invalid("InvalidCloudSpec", err.Error())
+	}
+	datacenterProvider, err := provider.DatacenterCloudProviderName(&targetDatacenter.Spec)
+	if err != nil {
+		return invalid("InvalidDatacenter", err.Error())
+	}
+	if clusterProvider != datacenterProvider {
+		return invalid("CloudProviderMismatch", fmt.Sprintf("cluster uses the cloud provider %s but datacenter %s offers %s", clusterProvider, migration.Spec.TargetDatacenter, datacenterProvider))
+	}
+
+	if targetSeed.Name != sourceSeed.Name {
+		targetClient, ok := r.seedClients[targetSeed.Name]
+		if !ok {
+			return fmt.Errorf("no client for seed %s", targetSeed.Name)
+		}
+		err := targetClient.Get(ctx, types.NamespacedName{Name: cluster.Name}, &kubermaticv1.Cluster{})
+		if err == nil {
+			return invalid("ClusterExistsInTarget", fmt.Sprintf("a cluster named %s already exists in seed %s", cluster.Name, targetSeed.Name))
+		}
+		if !kerrors.IsNotFound(err) {
+			return fmt.Errorf("failed to get cluster from seed %s: %v", targetSeed.Name, err)
+		}
+
+		// The snapshot is uploaded by the source seed and downloaded by the target seed
+		sourceDestination, err := backupcontroller.GetClusterBackupDestination(sourceSeed, cluster)
+		if err != nil {
+			return invalid("InvalidBackupDestination", err.Error())
+		}
+		if sourceDestination == nil {
+			return invalid("NoBackupDestination", "cluster has no backup destination, it is required to move etcd between seeds")
+		}
+		targetCluster := cluster.DeepCopy()
+		targetCluster.Spec.Cloud.DatacenterName = migration.Spec.TargetDatacenter
+		targetDestination, err := backupcontroller.GetClusterBackupDestination(targetSeed, targetCluster)
+		if err != nil {
+			return invalid("InvalidBackupDestination", err.Error())
+		}
+		if targetDestination == nil || targetDestination.Endpoint != sourceDestination.Endpoint || targetDestination.BucketName != sourceDestination.BucketName {
+			return invalid("BackupDestinationMismatch", fmt.Sprintf("seeds %s and %s don't share the backup destination of the cluster", sourceSeed.Name, targetSeed.Name))
+		}
+	}
+
+	log.Infow("Validated migration", "source-seed", sourceSeed.Name, "target-seed", targetSeed.Name)
+	return r.updateStatus(ctx, migration, func(migration *kubermaticv1.ClusterMigration) {
+		migration.Status.Phase = kubermaticv1.ClusterMigrationPhaseRunning
+		migration.Status.SourceSeed = sourceSeed.Name
+		migration.Status.SourceDatacenter = cluster.Spec.Cloud.DatacenterName
+		migration.Status.TargetSeed = targetSeed.Name
+		setMigrationCondition(migration, kubermaticv1.ClusterMigrationConditionValidated, corev1.ConditionTrue, "", "")
+	})
+}
+
+func (r *Reconciler) getState(migration *kubermaticv1.ClusterMigration) (*migrationState, error) {
+	seeds, err := r.seedsGetter()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get seeds: %v", err)
+	}
+	state := &migrationState{}
+	for _, s := range []struct {
+		name   string
+		seed   **kubermaticv1.Seed
+		client *ctrlruntimeclient.Client
+	}{
+		{migration.Status.SourceSeed, &state.sourceSeed, &state.sourceClient},
+		{migration.Status.TargetSeed, &state.targetSeed, &state.targetClient},
+	} {
+		seed, ok := seeds[s.name]
+		if !ok {
+			return nil, fmt.Errorf("seed %s doesn't exist", s.name)
+		}
+		client, ok := r.seedClients[s.name]
+		if !ok {
+			return nil, fmt.Errorf("no client for seed %s", s.name)
+		}
+		*s.seed = seed
+		*s.client = client
+	}
+	return state, nil
+}
+
+// updateDatacenter moves a cluster to another datacenter of its seed. The control plane stays where it is.
+func (r *Reconciler) updateDatacenter(ctx context.Context, log *zap.SugaredLogger, migration *kubermaticv1.ClusterMigration, state *migrationState) (*reconcile.Result, error) {
+	cluster, err := r.getSourceCluster(ctx, migration, state)
+	if err != nil || cluster == nil {
+		return nil, err
+	}
+	oldCluster := cluster.DeepCopy()
+	cluster.Spec.Cloud.DatacenterName = migration.Spec.TargetDatacenter
+	if err := state.sourceClient.Patch(ctx, cluster, ctrlruntimeclient.MergeFrom(oldCluster)); err != nil {
+		return nil, fmt.Errorf("failed to update datacenter of cluster: %v", err)
+	}
+	log.Infow("Moved cluster to datacenter", "datacenter", migration.Spec.TargetDatacenter)
+	return nil, nil
+}
+
+func (r *Reconciler) pauseSource(ctx context.Context, log *zap.SugaredLogger, migration *kubermaticv1.ClusterMigration, state *migrationState) (*reconcile.Result, error) {
+	cluster, err := r.getSourceCluster(ctx, migration, state)
+	if err != nil || cluster == nil {
+		return nil, err
+	}
+	if cluster.Spec.Pause && cluster.Annotations[ActiveMigrationAnnotationName] == migration.Name {
+		return nil, nil
+	}
+	if cluster.Spec.Pause {
+		return nil, r.fail(ctx, migration, kubermaticv1.ClusterMigrationConditionSourcePaused, "ClusterAlreadyPaused", "cluster has been paused by someone else")
+	}
+
+	oldCluster := cluster.DeepCopy()
+	if cluster.Annotations == nil {
+		cluster.Annotations = map[string]string{}
+	}
+	cluster.Annotations[ActiveMigrationAnnotationName] = migration.Name
+	cluster.Spec.Pause = true
+	cluster.Spec.PauseReason = fmt.Sprintf("migration %s to datacenter %s in progress", migration.Name, migration.Spec.TargetDatacenter)
+	if err := state.sourceClient.Patch(ctx, cluster, ctrlruntimeclient.MergeFrom(oldCluster)); err != nil {
+		return nil, fmt.Errorf("failed to pause cluster: %v", err)
+	}
+	log.Info("Paused source cluster")
+	return nil, nil
+}
+
+// scaleDownSourceApiserver stops all writes to etcd, so the snapshot contains the final state of the cluster
+func (r *Reconciler) scaleDownSourceApiserver(ctx context.Context, log *zap.SugaredLogger, migration *kubermaticv1.ClusterMigration, state *migrationState) (*reconcile.Result, error) {
+	cluster, err := r.getSourceCluster(ctx, migration, state)
+	if err != nil || cluster == nil {
+		return nil, err
+	}
+
+	deployment := &appsv1.Deployment{}
+	name := types.NamespacedName{Namespace: cluster.Status.NamespaceName, Name: resources.ApiserverDeploymentName}
+	if err := state.sourceClient.Get(ctx, name, deployment); err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get apiserver deployment: %v", err)
+	}
+
+	if deployment.Spec.Replicas == nil || *deployment.Spec.Replicas != 0 {
+		oldDeployment := deployment.DeepCopy()
+		deployment.Spec.Replicas = utilpointer.Int32Ptr(0)
+		if err := state.sourceClient.Patch(ctx, deployment, ctrlruntimeclient.MergeFrom(oldDeployment)); err != nil {
+			return nil, fmt.Errorf("failed to scale down apiserver: %v", err)
+		}
+		log.Info("Scaled down apiserver in source seed")
+	}
+
+	if deployment.Status.Replicas != 0 {
+		log.Debugw("Waiting for apiserver pods to terminate", "replicas", deployment.Status.Replicas)
+		return &reconcile.Result{RequeueAfter: pollInterval}, nil
+	}
+	return nil, nil
+}
+
+// createSnapshot runs the backup CronJob of the cluster once and records the resulting snapshot
+func (r *Reconciler) createSnapshot(ctx context.Context, log *zap.SugaredLogger, migration *kubermaticv1.ClusterMigration, state *migrationState) (*reconcile.Result, error) {
+	if migration.Status.SnapshotName != "" {
+		return nil, nil
+	}
+	cluster, err := r.getSourceCluster(ctx, migration, state)
+	if err != nil || cluster == nil {
+		return nil, err
+	}
+
+	job := &batchv1.Job{}
+	jobName := types.NamespacedName{Namespace: metav1.NamespaceSystem, Name: snapshotJobName(migration)}
+	if err := state.sourceClient.Get(ctx, jobName, job); err != nil {
+		if !kerrors.IsNotFound(err) {
+			return nil, fmt.Errorf("failed to get snapshot job: %v", err)
+		}
+
+		cronJob := &batchv1beta1.CronJob{}
+		cronJobName := types.NamespacedName{Namespace: metav1.NamespaceSystem, Name: backupcontroller.CronJobName(cluster)}
+		if err := state.sourceClient.Get(ctx, cronJobName, cronJob); err != nil {
+			if kerrors.IsNotFound(err) {
+				return nil, r.fail(ctx, migration, kubermaticv1.ClusterMigrationConditionSnapshotCreated, "NoBackupCronJob", fmt.Sprintf("backup CronJob %s doesn't exist in seed %s", cronJobName.Name, state.sourceSeed.Name))
+			}
+			return nil, fmt.Errorf("failed to get backup CronJob: %v", err)
+		}
+		job = &batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      jobName.Name,
+				Namespace: jobName.Namespace,
+				Labels:    cronJob.Spec.JobTemplate.Labels,
+				// The job goes away with the source cluster
+				OwnerReferences: []metav1.OwnerReference{
+					*metav1.NewControllerRef(cluster, kubermaticv1.SchemeGroupVersion.WithKind(kubermaticv1.ClusterKindName)),
+				},
+			},
+			Spec: *cronJob.Spec.JobTemplate.Spec.DeepCopy(),
+		}
+		if err := state.sourceClient.Create(ctx, job); err != nil {
+			return nil, fmt.Errorf("failed to create snapshot job: %v", err)
+		}
+		log.Infow("Created snapshot job", "job", job.Name)
+		return &reconcile.Result{RequeueAfter: pollInterval}, nil
+	}
+
+	if jobHasCondition(job, batchv1.JobFailed) {
+		return nil, r.fail(ctx, migration, kubermaticv1.ClusterMigrationConditionSnapshotCreated, "JobFailed", fmt.Sprintf("snapshot job %s failed", job.Name))
+	}
+	if job.Status.Succeeded == 0 {
+		log.Debugw("Waiting for snapshot job", "job", job.Name)
+		return &reconcile.Result{RequeueAfter: pollInterval}, nil
+	}
+
+	destination, err := backupcontroller.GetClusterBackupDestination(state.sourceSeed, cluster)
+	if err != nil {
+		return nil, err
+	}
+	snapshots, err := r.listSnapshots(ctx, state.sourceClient, cluster, destination)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list snapshots: %v", err)
+	}
+	startTime := job.CreationTimestamp
+	if job.Status.StartTime != nil {
+		startTime = *job.Status.StartTime
+	}
+	// The snapshots are sorted from the oldest to the newest
+	snapshotName := ""
+	for _, snapshot := range snapshots {
+		if !snapshot.CreationTimestamp.Before(&startTime) {
+			snapshotName = snapshot.Name
+		}
+	}
+	if snapshotName == "" {
+		return nil, r.fail(ctx, migration, kubermaticv1.ClusterMigrationConditionSnapshotCreated, "SnapshotNotFound", fmt.Sprintf("snapshot job %s succeeded but no new snapshot has been found", job.Name))
+	}
+
+	log.Infow("Created snapshot", "snapshot", snapshotName)
+	return nil, r.updateStatus(ctx, migration, func(migration *kubermaticv1.ClusterMigration) {
+		migration.Status.SnapshotName = snapshotName
+	})
+}
+
+// createTargetCluster recreates the cluster with its namespace and secrets in the target seed. The copy is
+// created paused, so the target seed doesn't generate new certificates before the secrets have been copied.
+func (r *Reconciler) createTargetCluster(ctx context.Context, log *zap.SugaredLogger, migration *kubermaticv1.ClusterMigration, state *migrationState) (*reconcile.Result, error) {
+	source, err := r.getSourceCluster(ctx, migration, state)
+	if err != nil || source == nil {
+		return nil, err
+	}
+
+	target := &kubermaticv1.Cluster{}
+	if err := state.targetClient.Get(ctx, types.NamespacedName{Name: source.Name}, target); err != nil {
+		if !kerrors.IsNotFound(err) {
+			return nil, fmt.Errorf("failed to get cluster from target seed: %v", err)
+		}
+		target = targetClusterFor(source, migration)
+		if err := state.targetClient.Create(ctx, target); err != nil {
+			return nil, fmt.Errorf("failed to create cluster in target seed: %v", err)
+		}
+		log.Info("Created cluster in target seed")
+		if err := state.targetClient.Get(ctx, types.NamespacedName{Name: source.Name}, target); err != nil {
+			return nil, fmt.Errorf("failed to get cluster from target seed: %v", err)
+		}
+	}
+	if target.Annotations[MigratedFromAnnotationName] != migration.Name {
+		return nil, r.fail(ctx, migration, kubermaticv1.ClusterMigrationConditionTargetClusterCreated, "ClusterExistsInTarget", fmt.Sprintf("cluster %s in seed %s hasn't been created by this migration", target.Name, state.targetSeed.Name))
+	}
+	if !target.Spec.Pause {
+		return nil, nil
+	}
+
+	namespace := &corev1.Namespace{}
+	if err := state.targetClient.Get(ctx, types.NamespacedName{Name: target.Status.NamespaceName}, namespace); err != nil {
+		if !kerrors.IsNotFound(err) {
+			return nil, fmt.Errorf("failed to get namespace %s: %v", target.Status.NamespaceName, err)
+		}
+		namespace = &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            target.Status.NamespaceName,
+				OwnerReferences: []metav1.OwnerReference{clusterOwnerRef(target)},
+			},
+		}
+		if err := state.targetClient.Create(ctx, namespace); err != nil {
+			return nil, fmt.Errorf("failed to create namespace %s: %v", namespace.Name, err)
+		}
+	}
+
+	if err := r.copySecrets(ctx, log, source, target, state); err != nil {
+		return nil, err
+	}
+
+	oldTarget := target.DeepCopy()
+	target.Spec.Pause = false
+	target.Spec.PauseReason = ""
+	if err := state.targetClient.Patch(ctx, target, ctrlruntimeclient.MergeFrom(oldTarget)); err != nil {
+		return nil, fmt.Errorf("failed to resume cluster in target seed: %v", err)
+	}
+	log.Info("Resumed cluster in target seed")
+	return nil, nil
+}
+
+// copySecrets copies the cloud credentials and the secrets of the cluster namespace, most importantly the
+// CAs and keys the nodes and kubeconfigs of the cluster trust. Service account tokens are left out, they
+// are generated for the service accounts in the target seed.
+func (r *Reconciler) copySecrets(ctx context.Context, log *zap.SugaredLogger, source, target *kubermaticv1.Cluster, state *migrationState) error {
+	sourceSecrets := []corev1.Secret{}
+
+	if credentialsName := source.GetSecretName(); credentialsName != "" {
+		credentials := &corev1.Secret{}
+		if err := state.sourceClient.Get(ctx, types.NamespacedName{Namespace: resources.KubermaticNamespace, Name: credentialsName}, credentials); err != nil {
+			if !kerrors.IsNotFound(err) {
+				return fmt.Errorf("failed to get credentials secret: %v", err)
+			}
+		} else {
+			sourceSecrets = append(sourceSecrets, *credentials)
+		}
+	}
+
+	namespaceSecrets := &corev1.SecretList{}
+	if err := state.sourceClient.List(ctx, namespaceSecrets, ctrlruntimeclient.InNamespace(source.Status.NamespaceName)); err != nil {
+		return fmt.Errorf("failed to list secrets of cluster namespace: %v", err)
+	}
+	for _, secret := range namespaceSecrets.Items {
+		if secret.Type == corev1.SecretTypeServiceAccountToken {
+			continue
+		}
+		sourceSecrets = append(sourceSecrets, secret)
+	}
+
+	for _, secret := range sourceSecrets {
+		namespace := secret.Namespace
+		if namespace == source.Status.NamespaceName {
+			namespace = target.Status.NamespaceName
+		}
+		err := state.targetClient.Get(ctx, types.NamespacedName{Namespace: namespace, Name: secret.Name}, &corev1.Secret{})
+		if err == nil {
+			continue
+		}
+		if !kerrors.IsNotFound(err) {
+			return fmt.Errorf("failed to get secret %s/%s from target seed: %v", namespace, secret.Name, err)
+		}
+
+		copied := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        secret.Name,
+				Namespace:   namespace,
+				Labels:      secret.Labels,
+				Annotations: secret.Annotations,
+			},
+			Type: secret.Type,
+			Data: secret.Data,
+		}
+		// Only the references to the cluster itself are valid in the target seed
+		for _, ownerRef := range secret.OwnerReferences {
+			if ownerRef.Kind == kubermaticv1.ClusterKindName && ownerRef.Name == source.Name {
+				ref := clusterOwnerRef(target)
+				ref.Controller = ownerRef.Controller
+				ref.BlockOwnerDeletion = ownerRef.BlockOwnerDeletion
+				copied.OwnerReferences = append(copied.OwnerReferences, ref)
+			}
+		}
+		if err := state.targetClient.Create(ctx, copied); err != nil && !kerrors.IsAlreadyExists(err) {
+			return fmt.Errorf("failed to create secret %s/%s in target seed: %v", namespace, secret.Name, err)
+		}
+		log.Debugw("Copied secret", "secret", fmt.Sprintf("%s/%s", namespace, secret.Name))
+	}
+	return nil
+}
+
+// restoreEtcd waits for the fresh etcd in the target seed and restores the snapshot into its volumes
+func (r *Reconciler) restoreEtcd(ctx context.Context, log *zap.SugaredLogger, migration *kubermaticv1.ClusterMigration, state *migrationState) (*reconcile.Result, error) {
+	target, err := r.getTargetCluster(ctx, migration, state)
+	if err != nil || target == nil {
+		return nil, err
+	}
+
+	restore := &kubermaticv1.EtcdRestore{}
+	restoreName := types.NamespacedName{Namespace: target.Status.NamespaceName, Name: migration.Name}
+	if err := state.targetClient.Get(ctx, restoreName, restore); err != nil {
+		if !kerrors.IsNotFound(err) {
+			return nil, fmt.Errorf("failed to get etcd restore: %v", err)
+		}
+
+		// The restore replaces the data in the volumes of the etcd StatefulSet, so they must exist first
+		statefulSet := &appsv1.StatefulSet{}
+		name := types.NamespacedName{Namespace: target.Status.NamespaceName, Name: resources.EtcdStatefulSetName}
+		if err := state.targetClient.Get(ctx, name, statefulSet); err != nil {
+			if kerrors.IsNotFound(err) {
+				return &reconcile.Result{RequeueAfter: pollInterval}, nil
+			}
+			return nil, fmt.Errorf("failed to get etcd StatefulSet: %v", err)
+		}
+		if statefulSet.Status.ReadyReplicas < int32(etcd.ClusterSize(target)) {
+			log.Debugw("Waiting for etcd to become ready in target seed", "ready", statefulSet.Status.ReadyReplicas)
+			return &reconcile.Result{RequeueAfter: pollInterval}, nil
+		}
+
+		restore = &kubermaticv1.EtcdRestore{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      restoreName.Name,
+				Namespace: restoreName.Namespace,
+			},
+			Spec: kubermaticv1.EtcdRestoreSpec{
+				Cluster: corev1.ObjectReference{
+					APIVersion: kubermaticv1.SchemeGroupVersion.String(),
+					Kind:       kubermaticv1.ClusterKindName,
+					Name:       target.Name,
+					UID:        target.UID,
+				},
+				BackupName: migration.Status.SnapshotName,
+			},
+		}
+		if err := state.targetClient.Create(ctx, restore); err != nil {
+			return nil, fmt.Errorf("failed to create etcd restore: %v", err)
+		}
+		log.Infow("Created etcd restore in target seed", "snapshot", migration.Status.SnapshotName)
+		return &reconcile.Result{RequeueAfter: pollInterval}, nil
+	}
+
+	switch restore.Status.Phase {
+	case kubermaticv1.EtcdRestorePhaseCompleted:
+		return nil, nil
+	case kubermaticv1.EtcdRestorePhaseFailed:
+		return nil, r.fail(ctx, migration, kubermaticv1.ClusterMigrationConditionEtcdRestored, "RestoreFailed", fmt.Sprintf("etcd restore %s/%s failed", restore.Namespace, restore.Name))
+	}
+	log.Debugw("Waiting for etcd restore", "phase", restore.Status.Phase)
+	return &reconcile.Result{RequeueAfter: pollInterval}, nil
+}
+
+// waitForAddress waits for the target seed to set the address of the cluster. It is built from the
+// DNS name of the target seed, so the kubeconfigs handed out from now on point to the target seed.
+// The nodes still use the address of the source seed until they are replaced by updateNodes.
+func (r *Reconciler) waitForAddress(ctx context.Context, log *zap.SugaredLogger, migration *kubermaticv1.ClusterMigration, state *migrationState) (*reconcile.Result, error) {
+	target, err := r.getTargetCluster(ctx, migration, state)
+	if err != nil || target == nil {
+		return nil, err
+	}
+	if target.Address.URL == "" {
+		log.Debug("Waiting for the address of the cluster in target seed")
+		return &reconcile.Result{RequeueAfter: pollInterval}, nil
+	}
+
+	r.recorder.Eventf(migration, corev1.EventTypeNormal, "AddressUpdated", "Cluster is available at %s", target.Address.URL)
+	return nil, r.updateStatus(ctx, migration, func(migration *kubermaticv1.ClusterMigration) {
+		migration.Status.Address = target.Address.URL
+	})
+}
+
+// updateNodes replaces the nodes of the cluster, so they join the apiserver in the target seed. The kubelets
+// of the existing nodes are configured with the address of the source seed, thus all MachineDeployments are
+// rolled out once the cluster-info ConfigMap hands out the address of the target seed to new machines.
+// The step completes when the rollouts are done and all nodes are ready, nodes that aren't managed by a
+// MachineDeployment have to be switched to the new address manually.
+func (r *Reconciler) updateNodes(ctx context.Context, log *zap.SugaredLogger, migration *kubermaticv1.ClusterMigration, state *migrationState) (*reconcile.Result, error) {
+	target, err := r.getTargetCluster(ctx, migration, state)
+	if err != nil || target == nil {
+		return nil, err
+	}
+	connectionProvider, ok := r.userClusterConnectionProviders[state.targetSeed.Name]
+	if !ok {
+		return nil, fmt.Errorf("no user cluster connection provider for seed %s", state.targetSeed.Name)
+	}
+	userClusterClient, err := connectionProvider.GetClient(target)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get client for the cluster in target seed: %v", err)
+	}
+
+	clusterInfo := &corev1.ConfigMap{}
+	if err := userClusterClient.Get(ctx, types.NamespacedName{Namespace: metav1.NamespacePublic, Name: resources.ClusterInfoConfigMapName}, clusterInfo); err != nil {
+		if !kerrors.IsNotFound(err) {
+			return nil, fmt.Errorf("failed to get cluster-info ConfigMap: %v", err)
+		}
+		log.Debug("Waiting for the cluster-info ConfigMap in the cluster")
+		return &reconcile.Result{RequeueAfter: pollInterval}, nil
+	}
+	if !strings.Contains(clusterInfo.Data["kubeconfig"], target.Address.URL) {
+		log.Debug("Waiting for the cluster-info ConfigMap to hand out the address of the target seed")
+		return &reconcile.Result{RequeueAfter: pollInterval}, nil
+	}
+
+	machineDeployments := &clusterv1alpha1.MachineDeploymentList{}
+	// Kubermatic only creates MachineDeployments in the kube-system namespace
+	if err := userClusterClient.List(ctx, machineDeployments, ctrlruntimeclient.InNamespace(metav1.NamespaceSystem)); err != nil {
+		return nil, fmt.Errorf("failed to list MachineDeployments: %v", err)
+	}
+	rolledOut := true
+	for i := range machineDeployments.Items {
+		machineDeployment := &machineDeployments.Items[i]
+		if machineDeployment.Spec.Template.Annotations[NodesMigratedAnnotationName] != migration.Name {
+			// Nodes that joined the target seed can't be moved back, so the migration must not be
+			// aborted anymore before the first MachineDeployment gets rolled out
+			if !nodesRolloutStarted(migration) {
+				if err := r.updateStatus(ctx, migration, func(migration *kubermaticv1.ClusterMigration) {
+					setMigrationCondition(migration, kubermaticv1.ClusterMigrationConditionNodesUpdated, corev1.ConditionFalse, nodesRolloutStartedReason, "the MachineDeployments are rolled out against the target seed")
+				}); err != nil {
+					return nil, err
+				}
+			}
+			oldMachineDeployment := machineDeployment.DeepCopy()
+			if machineDeployment.Spec.Template.Annotations == nil {
+				machineDeployment.Spec.Template.Annotations = map[string]string{}
+			}
+			machineDeployment.Spec.Template.Annotations[NodesMigratedAnnotationName] = migration.Name
+			if err := userClusterClient.Patch(ctx, machineDeployment, ctrlruntimeclient.MergeFrom(oldMachineDeployment)); err != nil {
+				return nil, fmt.Errorf("failed to roll out MachineDeployment %s: %v", machineDeployment.Name, err)
+			}
+			log.Infow("Rolling out MachineDeployment", "machinedeployment", machineDeployment.Name)
+			rolledOut = false
+			continue
+		}
+		if !machineDeploymentRolledOut(machineDeployment) {
+			log.Debugw("Waiting for MachineDeployment to be rolled out", "machinedeployment", machineDeployment.Name)
+			rolledOut = false
+		}
+	}
+	if !rolledOut {
+		return &reconcile.Result{RequeueAfter: pollInterval}, nil
+	}
+
+	nodes := &corev1.NodeList{}
+	if err := userClusterClient.List(ctx, nodes); err != nil {
+		return nil, fmt.Errorf("failed to list nodes: %v", err)
+	}
+	for _, node := range nodes.Items {
+		if !nodeReady(&node) {
+			log.Debugw("Waiting for node to become ready in target seed", "node", node.Name)
+			return &reconcile.Result{RequeueAfter: pollInterval}, nil
+		}
+	}
+	log.Infow("Nodes joined the target seed", "nodes", len(nodes.Items))
+	return nil, nil
+}
+
+// cleanupSource removes the cluster from the source seed. The nodes, the cloud resources and the backups
+// now belong to the cluster in the target seed, so the finalizers that would clean them up are dropped.
+func (r *Reconciler) cleanupSource(ctx context.Context, log *zap.SugaredLogger, migration *kubermaticv1.ClusterMigration, state *migrationState) (*reconcile.Result, error) {
+	cluster := &kubermaticv1.Cluster{}
+	if err := state.sourceClient.Get(ctx, types.NamespacedName{Name: migration.Spec.ClusterName}, cluster); err != nil {
+		if kerrors.IsNotFound(err) {
+			log.Info("Removed cluster from source seed")
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get cluster from source seed: %v", err)
+	}
+	if err := removeCluster(ctx, state.sourceClient, cluster); err != nil {
+		return nil, err
+	}
+	log.Debug("Waiting for cluster to be removed from source seed")
+	return &reconcile.Result{RequeueAfter: pollInterval}, nil
+}
+
+// abort rolls a migration back. Within the same seed the original datacenter is restored. Across seeds the
+// copy in the target seed is removed the same way the source would have been removed, so the nodes and
+// cloud resources of the cluster are left untouched.
+func (r *Reconciler) abort(ctx context.Context, log *zap.SugaredLogger, migration *kubermaticv1.ClusterMigration) (*reconcile.Result, error) {
+	if migration.Status.SourceSeed != "" && migration.Status.SourceSeed == migration.Status.TargetSeed {
+		state, err := r.getState(migration)
+		if err != nil {
+			return nil, err
+		}
+		cluster := &kubermaticv1.Cluster{}
+		if err := state.sourceClient.Get(ctx, types.NamespacedName{Name: migration.Spec.ClusterName}, cluster); err != nil {
+			if !kerrors.IsNotFound(err) {
+				return nil, fmt.Errorf("failed to get cluster from seed: %v", err)
+			}
+		} else if cluster.Spec.Cloud.DatacenterName == migration.Spec.TargetDatacenter {
+			oldCluster := cluster.DeepCopy()
+			cluster.Spec.Cloud.DatacenterName = migration.Status.SourceDatacenter
+			if err := state.sourceClient.Patch(ctx, cluster, ctrlruntimeclient.MergeFrom(oldCluster)); err != nil {
+				return nil, fmt.Errorf("failed to restore datacenter of cluster: %v", err)
+			}
+			log.Infow("Moved cluster back to datacenter", "datacenter", migration.Status.SourceDatacenter)
+		}
+	}
+
+	if migration.Status.SourceSeed != "" && migration.Status.SourceSeed != migration.Status.TargetSeed {
+		state, err := r.getState(migration)
+		if err != nil {
+			return nil, err
+		}
+
+		target := &kubermaticv1.Cluster{}
+		if err := state.targetClient.Get(ctx, types.NamespacedName{Name: migration.Spec.ClusterName}, target); err != nil {
+			if !kerrors.IsNotFound(err) {
+				return nil, fmt.Errorf("failed to get cluster from target seed: %v", err)
+			}
+		} else if target.Annotations[MigratedFromAnnotationName] == migration.Name {
+			if err := removeCluster(ctx, state.targetClient, target); err != nil {
+				return nil, err
+			}
+			log.Debug("Waiting for cluster to be removed from target seed")
+			return &reconcile.Result{RequeueAfter: pollInterval}, nil
+		}
+
+		job := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceSystem, Name: snapshotJobName(migration)}}
+		deletePropagationForeground := metav1.DeletePropagationForeground
+		if err := state.sourceClient.Delete(ctx, job, &ctrlruntimeclient.DeleteOptions{PropagationPolicy: &deletePropagationForeground}); err != nil && !kerrors.IsNotFound(err) {
+			return nil, fmt.Errorf("failed to delete snapshot job: %v", err)
+		}
+
+		source := &kubermaticv1.Cluster{}
+		if err := state.sourceClient.Get(ctx, types.NamespacedName{Name: migration.Spec.ClusterName}, source); err != nil {
+			if !kerrors.IsNotFound(err) {
+				return nil, fmt.Errorf("failed to get cluster from source seed: %v", err)
+			}
+		} else if source.Annotations[ActiveMigrationAnnotationName] == migration.Name {
+			// Resuming the cluster scales the apiserver up again
+			oldSource := source.DeepCopy()
+			delete(source.Annotations, ActiveMigrationAnnotationName)
+			source.Spec.Pause = false
+			source.Spec.PauseReason = ""
+			if err := state.sourceClient.Patch(ctx, source, ctrlruntimeclient.MergeFrom(oldSource)); err != nil {
+				return nil, fmt.Errorf("failed to resume source cluster: %v", err)
+			}
+			log.Info("Resumed source cluster")
+		}
+	}
+
+	log.Info("Migration aborted")
+	r.recorder.Event(migration, corev1.EventTypeNormal, "Aborted", "Migration has been aborted")
+	return nil, r.updateStatus(ctx, migration, func(migration *kubermaticv1.ClusterMigration) {
+		migration.Status.Phase = kubermaticv1.ClusterMigrationPhaseAborted
+		setMigrationCondition(migration, kubermaticv1.ClusterMigrationConditionAborted, corev1.ConditionTrue, "", "")
+	})
+}
+
+// removeCluster deletes a cluster without cleaning up its nodes, cloud resources and backups, only the
+// namespace and the credentials of the cluster in the given seed are removed. This doesn't depend on the
+// controllers of the seed, which ignore paused clusters.
+func removeCluster(ctx context.Context, client ctrlruntimeclient.Client, cluster *kubermaticv1.Cluster) error {
+	if credentialsName := cluster.GetSecretName(); credentialsName != "" {
+		credentials := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: resources.KubermaticNamespace, Name: credentialsName}}
+		if err := client.Delete(ctx, credentials); err != nil && !kerrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete credentials secret: %v", err)
+		}
+	}
+
+	if len(cluster.Finalizers) > 0 {
+		oldCluster := cluster.DeepCopy()
+		cluster.Finalizers = nil
+		if err := client.Patch(ctx, cluster, ctrlruntimeclient.MergeFrom(oldCluster)); err != nil {
+			return fmt.Errorf("failed to remove finalizers of cluster: %v", err)
+		}
+	}
+	if cluster.DeletionTimestamp == nil {
+		// The cluster namespace is owned by the cluster and gets garbage collected
+		if err := client.Delete(ctx, cluster); err != nil && !kerrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete cluster: %v", err)
+		}
+	}
+	return nil
+}
+
+// targetClusterFor returns the paused copy of the source cluster for the target seed. The address is
+// left out apart from the admin token, so the target seed computes it for its own DNS name.
+func targetClusterFor(source *kubermaticv1.Cluster, migration *kubermaticv1.ClusterMigration) *kubermaticv1.Cluster {
+	source = source.DeepCopy()
+	target := &kubermaticv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        source.Name,
+			Labels:      source.Labels,
+			Annotations: map[string]string{},
+			Finalizers:  source.Finalizers,
+		},
+		Spec: source.Spec,
+		Address: kubermaticv1.ClusterAddress{
+			AdminToken: source.Address.AdminToken,
+		},
+		Status: kubermaticv1.ClusterStatus{
+			NamespaceName: source.Status.NamespaceName,
+			UserName:      source.Status.UserName,
+			UserEmail:     source.Status.UserEmail,
+		},
+	}
+	for key, value := range source.Annotations {
+		if key != ActiveMigrationAnnotationName {
+			target.Annotations[key] = value
+		}
+	}
+	target.Annotations[MigratedFromAnnotationName] = migration.Name
+	target.Spec.Cloud.DatacenterName = migration.Spec.TargetDatacenter
+	target.Spec.Pause = true
+	target.Spec.PauseReason = fmt.Sprintf("migration %s from seed %s in progress", migration.Name, migration.Status.SourceSeed)
+	return target
+}
+
+func (r *Reconciler) getSourceCluster(ctx context.Context, migration *kubermaticv1.ClusterMigration, state *migrationState) (*kubermaticv1.Cluster, error) {
+	cluster := &kubermaticv1.Cluster{}
+	if err := state.sourceClient.Get(ctx, types.NamespacedName{Name: migration.Spec.ClusterName}, cluster); err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil, r.fail(ctx, migration, kubermaticv1.ClusterMigrationConditionValidated, "ClusterNotFound", fmt.Sprintf("cluster doesn't exist in seed %s anymore", state.sourceSeed.Name))
+		}
+		return nil, fmt.Errorf("failed to get cluster from source seed: %v", err)
+	}
+	return cluster, nil
+}
+
+func (r *Reconciler) getTargetCluster(ctx context.Context, migration *kubermaticv1.ClusterMigration, state *migrationState) (*kubermaticv1.Cluster, error) {
+	cluster := &kubermaticv1.Cluster{}
+	if err := state.targetClient.Get(ctx, types.NamespacedName{Name: migration.Spec.ClusterName}, cluster); err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil, r.fail(ctx, migration, kubermaticv1.ClusterMigrationConditionTargetClusterCreated, "ClusterNotFound", fmt.Sprintf("cluster doesn't exist in seed %s anymore", state.targetSeed.Name))
+		}
+		return nil, fmt.Errorf("failed to get cluster from target seed: %v", err)
+	}
+	return cluster, nil
+}
+
+// fail marks the migration as failed. The source cluster is left paused on purpose.
+func (r *Reconciler) fail(ctx context.Context, migration *kubermaticv1.ClusterMigration, conditionType kubermaticv1.ClusterMigrationConditionType, reason, message string) error {
+	r.recorder.Event(migration, corev1.EventTypeWarning, reason, message)
+	return r.updateStatus(ctx, migration, func(migration *kubermaticv1.ClusterMigration) {
+		migration.Status.Phase = kubermaticv1.ClusterMigrationPhaseFailed
+		setMigrationCondition(migration, conditionType, corev1.ConditionFalse, reason, message)
+	})
+}
+
+func (r *Reconciler) updateStatus(ctx context.Context, migration *kubermaticv1.ClusterMigration, modify func(*kubermaticv1.ClusterMigration)) error {
+	oldMigration := migration.DeepCopy()
+	modify(migration)
+	if err := r.client.Patch(ctx, migration, ctrlruntimeclient.MergeFrom(oldMigration)); err != nil {
+		return fmt.Errorf("failed to update migration status: %v", err)
+	}
+	return nil
+}
+
+func snapshotJobName(migration *kubermaticv1.ClusterMigration) string {
+	return fmt.Sprintf("%s-%s", snapshotJobPrefix, migration.Name)
+}
+
+func clusterOwnerRef(cluster *kubermaticv1.Cluster) metav1.OwnerReference {
+	return *metav1.NewControllerRef(cluster, kubermaticv1.SchemeGroupVersion.WithKind(kubermaticv1.ClusterKindName))
+}
+
+// abortable returns false once the nodes of the cluster are rolled out against the target seed, as the
+// nodes that already joined it can't be moved back. A migration within the same seed can always be reverted.
+func abortable(migration *kubermaticv1.ClusterMigration) bool {
+	if migration.Status.Phase == kubermaticv1.ClusterMigrationPhaseAborted {
+		return false
+	}
+	if migration.Status.SourceSeed != "" && migration.Status.SourceSeed == migration.Status.TargetSeed {
+		return true
+	}
+	return !nodesRolloutStarted(migration)
+}
+
+// nodesRolloutStarted returns true once the first MachineDeployment is rolled out against the target seed
+func nodesRolloutStarted(migration *kubermaticv1.ClusterMigration) bool {
+	for _, condition := range migration.Status.Conditions {
+		if condition.Type == kubermaticv1.ClusterMigrationConditionNodesUpdated {
+			return condition.Status == corev1.ConditionTrue || condition.Reason == nodesRolloutStartedReason
+		}
+	}
+	return false
+}
+
+func migrationFinished(migration *kubermaticv1.ClusterMigration) bool {
+	return migration.Status.Phase == kubermaticv1.ClusterMigrationPhaseCompleted || migration.Status.Phase == kubermaticv1.ClusterMigrationPhaseAborted
+}
+
+func machineDeploymentRolledOut(machineDeployment *clusterv1alpha1.MachineDeployment) bool {
+	replicas := int32(1)
+	if machineDeployment.Spec.Replicas != nil {
+		replicas = *machineDeployment.Spec.Replicas
+	}
+	status := machineDeployment.Status
+	return status.ObservedGeneration >= machineDeployment.Generation &&
+		status.Replicas == replicas &&
+		status.UpdatedReplicas == replicas &&
+		status.AvailableReplicas == replicas
+}
+
+func nodeReady(node *corev1.Node) bool {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+func jobHasCondition(job *batchv1.Job, conditionType batchv1.JobConditionType) bool {
+	for _, condition := range job.Status.Conditions {
+		if condition.Type == conditionType && condition.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
+func setMigrationCondition(migration *kubermaticv1.ClusterMigration, conditionType kubermaticv1.ClusterMigrationConditionType, status corev1.ConditionStatus, reason, message string) {
+	now := metav1.Now()
+	for i, condition := range migration.Status.Conditions {
+		if condition.Type != conditionType {
+			continue
+		}
+		if condition.Status != status {
+			condition.LastTransitionTime = now
+		}
+		condition.Status = status
+		condition.Reason = reason
+		condition.Message = message
+		condition.LastHeartbeatTime = now
+		migration.Status.Conditions[i] = condition
+		return
+	}
+
+	migration.Status.Conditions = append(migration.Status.Conditions, kubermaticv1.ClusterMigrationCondition{
+		Type:               conditionType,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		LastHeartbeatTime:  now,
+		LastTransitionTime: now,
+	})
+}
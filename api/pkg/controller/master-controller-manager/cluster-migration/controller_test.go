@@ -0,0 +1,598 @@
+/*
+Copyright 2020 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clustermigration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	kubermaticapiv1 "github.com/kubermatic/kubermatic/api/pkg/api/v1"
+	k8cuserclusterclient "github.com/kubermatic/kubermatic/api/pkg/cluster/client"
+	kubermaticv1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
+	kubermaticlog "github.com/kubermatic/kubermatic/api/pkg/log"
+	"github.com/kubermatic/kubermatic/api/pkg/resources"
+	clusterv1alpha1 "github.com/kubermatic/machine-controller/pkg/apis/cluster/v1alpha1"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	utilpointer "k8s.io/utils/pointer"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlruntimefakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	testClusterName   = "abcd"
+	testNamespaceName = "cluster-abcd"
+	testSnapshotName  = "abcd-storeuploader-2020-01-01T00:01:00-snapshot.db"
+)
+
+var testSnapshotJobStart = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func TestMigrationWithinSeed(t *testing.T) {
+	reconciler, clients := newTestReconciler(genMigration("eu-2"), genSourceObjects()...)
+
+	mustReconcile(t, reconciler, false)
+
+	migration := getMigration(t, reconciler)
+	if migration.Status.Phase != kubermaticv1.ClusterMigrationPhaseCompleted {
+		t.Fatalf("expected the migration to be completed, got %+v", migration.Status)
+	}
+	if !migration.Status.HasConditionValue(kubermaticv1.ClusterMigrationConditionDatacenterUpdated, corev1.ConditionTrue) {
+		t.Fatalf("expected the datacenter to be updated, got %+v", migration.Status.Conditions)
+	}
+
+	cluster := getCluster(t, clients["europe"])
+	if cluster.Spec.Cloud.DatacenterName != "eu-2" {
+		t.Fatalf("expected the cluster to be moved to eu-2, got %s", cluster.Spec.Cloud.DatacenterName)
+	}
+	if cluster.Spec.Pause {
+		t.Fatal("expected the cluster not to be paused by a migration within the seed")
+	}
+}
+
+func TestAbortMigrationWithinSeed(t *testing.T) {
+	ctx := context.Background()
+	reconciler, clients := newTestReconciler(genMigration("eu-2"), genSourceObjects()...)
+	mustReconcile(t, reconciler, false)
+
+	migration := getMigration(t, reconciler)
+	migration.Spec.Abort = true
+	if err := reconciler.client.Update(ctx, migration); err != nil {
+		t.Fatal(err)
+	}
+	mustReconcile(t, reconciler, false)
+
+	if migration := getMigration(t, reconciler); migration.Status.Phase != kubermaticv1.ClusterMigrationPhaseAborted {
+		t.Fatalf("expected the migration to be aborted, got %+v", migration.Status)
+	}
+	if cluster := getCluster(t, clients["europe"]); cluster.Spec.Cloud.DatacenterName != "eu-1" {
+		t.Fatalf("expected the cluster to be moved back to eu-1, got %s", cluster.Spec.Cloud.DatacenterName)
+	}
+}
+
+func TestMigrationAcrossSeeds(t *testing.T) {
+	ctx := context.Background()
+	reconciler, clients := newTestReconciler(genMigration("us-1"), genSourceObjects()...)
+	source, target := clients["europe"], clients["us"]
+
+	// The source gets paused and snapshotted
+	mustReconcile(t, reconciler, true)
+
+	cluster := getCluster(t, source)
+	if !cluster.Spec.Pause || cluster.Annotations[ActiveMigrationAnnotationName] != "my-migration" {
+		t.Fatalf("expected the source cluster to be paused by the migration, got %+v", cluster.Spec)
+	}
+	apiserver := &appsv1.Deployment{}
+	if err := source.Get(ctx, types.NamespacedName{Namespace: testNamespaceName, Name: resources.ApiserverDeploymentName}, apiserver); err != nil {
+		t.Fatal(err)
+	}
+	if *apiserver.Spec.Replicas != 0 {
+		t.Fatalf("expected the apiserver to be scaled down, got %d replicas", *apiserver.Spec.Replicas)
+	}
+	job := &batchv1.Job{}
+	if err := source.Get(ctx, types.NamespacedName{Namespace: metav1.NamespaceSystem, Name: "etcd-migration-my-migration"}, job); err != nil {
+		t.Fatalf("expected a snapshot job: %v", err)
+	}
+	if job.Labels[resources.AppLabelKey] != "kubermatic-etcd-backup" || job.Spec.Template.Spec.Containers[0].Name != "store-container" {
+		t.Fatalf("expected the snapshot job to be created from the backup CronJob, got %+v", job)
+	}
+
+	// The cluster gets recreated in the target seed once the snapshot has been uploaded
+	job.Status.Succeeded = 1
+	job.Status.StartTime = &metav1.Time{Time: testSnapshotJobStart}
+	if err := source.Update(ctx, job); err != nil {
+		t.Fatal(err)
+	}
+	mustReconcile(t, reconciler, true)
+
+	if migration := getMigration(t, reconciler); migration.Status.SnapshotName != testSnapshotName {
+		t.Fatalf("expected the snapshot taken by the job to be recorded, got %q", migration.Status.SnapshotName)
+	}
+	targetCluster := getCluster(t, target)
+	if targetCluster.Spec.Pause || targetCluster.Spec.Cloud.DatacenterName != "us-1" || targetCluster.Annotations[MigratedFromAnnotationName] != "my-migration" {
+		t.Fatalf("expected a resumed copy of the cluster in us-1, got %+v", targetCluster)
+	}
+	if targetCluster.Address.URL != "" || targetCluster.Address.AdminToken != "admin-token" {
+		t.Fatalf("expected only the admin token to be copied from the address, got %+v", targetCluster.Address)
+	}
+	if _, ok := targetCluster.Annotations[ActiveMigrationAnnotationName]; ok {
+		t.Fatal("expected the active migration annotation not to be copied")
+	}
+	caSecret := &corev1.Secret{}
+	if err := target.Get(ctx, types.NamespacedName{Namespace: testNamespaceName, Name: resources.CASecretName}, caSecret); err != nil {
+		t.Fatalf("expected the CA to be copied: %v", err)
+	}
+	if string(caSecret.Data[resources.CACertSecretKey]) != "ca" || len(caSecret.OwnerReferences) != 1 || caSecret.OwnerReferences[0].Name != testClusterName {
+		t.Fatalf("unexpected copy of the CA %+v", caSecret)
+	}
+	if err := target.Get(ctx, types.NamespacedName{Namespace: testNamespaceName, Name: "default-token-abcde"}, &corev1.Secret{}); !kerrors.IsNotFound(err) {
+		t.Fatalf("expected the service account tokens not to be copied, got %v", err)
+	}
+	if err := target.Get(ctx, types.NamespacedName{Namespace: resources.KubermaticNamespace, Name: "credential-hetzner-abcd"}, &corev1.Secret{}); err != nil {
+		t.Fatalf("expected the credentials to be copied: %v", err)
+	}
+
+	// The snapshot gets restored once etcd is running in the target seed
+	etcdStatefulSet := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Namespace: testNamespaceName, Name: resources.EtcdStatefulSetName},
+		Status:     appsv1.StatefulSetStatus{ReadyReplicas: 3},
+	}
+	if err := target.Create(ctx, etcdStatefulSet); err != nil {
+		t.Fatal(err)
+	}
+	mustReconcile(t, reconciler, true)
+
+	restore := &kubermaticv1.EtcdRestore{}
+	if err := target.Get(ctx, types.NamespacedName{Namespace: testNamespaceName, Name: "my-migration"}, restore); err != nil {
+		t.Fatalf("expected an etcd restore in the target seed: %v", err)
+	}
+	if restore.Spec.BackupName != testSnapshotName || restore.Spec.Cluster.Name != testClusterName {
+		t.Fatalf("unexpected etcd restore %+v", restore.Spec)
+	}
+
+	// The MachineDeployments are rolled out once the target seed serves the cluster
+	restore.Status.Phase = kubermaticv1.EtcdRestorePhaseCompleted
+	if err := target.Update(ctx, restore); err != nil {
+		t.Fatal(err)
+	}
+	targetCluster = getCluster(t, target)
+	targetCluster.Address.URL = "https://abcd.us.example.com:30000"
+	if err := target.Update(ctx, targetCluster); err != nil {
+		t.Fatal(err)
+	}
+	mustReconcile(t, reconciler, true)
+
+	userCluster, err := reconciler.userClusterConnectionProviders["us"].GetClient(targetCluster)
+	if err != nil {
+		t.Fatal(err)
+	}
+	machineDeployment := &clusterv1alpha1.MachineDeployment{}
+	if err := userCluster.Get(ctx, types.NamespacedName{Namespace: metav1.NamespaceSystem, Name: "workers"}, machineDeployment); err != nil {
+		t.Fatal(err)
+	}
+	if machineDeployment.Spec.Template.Annotations[NodesMigratedAnnotationName] != "my-migration" {
+		t.Fatalf("expected the MachineDeployment to be rolled out, got %+v", machineDeployment.Spec.Template)
+	}
+
+	// The source is kept until all nodes have joined the target seed
+	machineDeployment.Status = clusterv1alpha1.MachineDeploymentStatus{Replicas: 2, UpdatedReplicas: 2, AvailableReplicas: 2}
+	if err := userCluster.Update(ctx, machineDeployment); err != nil {
+		t.Fatal(err)
+	}
+	mustReconcile(t, reconciler, true)
+	if migration := getMigration(t, reconciler); abortable(migration) {
+		t.Fatalf("expected the migration not to be abortable once the nodes are rolled out, got %+v", migration.Status)
+	}
+	getCluster(t, source)
+
+	// The source is cleaned up once the nodes are ready
+	node := &corev1.Node{}
+	if err := userCluster.Get(ctx, types.NamespacedName{Name: "worker-1"}, node); err != nil {
+		t.Fatal(err)
+	}
+	node.Status.Conditions = []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}}
+	if err := userCluster.Update(ctx, node); err != nil {
+		t.Fatal(err)
+	}
+	mustReconcile(t, reconciler, true)
+	mustReconcile(t, reconciler, false)
+
+	migration := getMigration(t, reconciler)
+	if migration.Status.Phase != kubermaticv1.ClusterMigrationPhaseCompleted || migration.Status.Address != "https://abcd.us.example.com:30000" {
+		t.Fatalf("expected the migration to be completed, got %+v", migration.Status)
+	}
+	if err := source.Get(ctx, types.NamespacedName{Name: testClusterName}, &kubermaticv1.Cluster{}); !kerrors.IsNotFound(err) {
+		t.Fatalf("expected the cluster to be removed from the source seed, got %v", err)
+	}
+	if err := source.Get(ctx, types.NamespacedName{Namespace: resources.KubermaticNamespace, Name: "credential-hetzner-abcd"}, &corev1.Secret{}); !kerrors.IsNotFound(err) {
+		t.Fatalf("expected the credentials to be removed from the source seed, got %v", err)
+	}
+	if targetCluster := getCluster(t, target); !hasFinalizer(targetCluster, kubermaticapiv1.NodeDeletionFinalizer) {
+		t.Fatalf("expected the copy to take over the finalizers, got %v", targetCluster.Finalizers)
+	}
+}
+
+func TestAbortMigration(t *testing.T) {
+	ctx := context.Background()
+	reconciler, clients := newTestReconciler(genMigration("us-1"), genSourceObjects()...)
+	source, target := clients["europe"], clients["us"]
+
+	mustReconcile(t, reconciler, true)
+	job := &batchv1.Job{}
+	if err := source.Get(ctx, types.NamespacedName{Namespace: metav1.NamespaceSystem, Name: "etcd-migration-my-migration"}, job); err != nil {
+		t.Fatal(err)
+	}
+	job.Status.Succeeded = 1
+	job.Status.StartTime = &metav1.Time{Time: testSnapshotJobStart}
+	if err := source.Update(ctx, job); err != nil {
+		t.Fatal(err)
+	}
+	mustReconcile(t, reconciler, true)
+	getCluster(t, target)
+
+	migration := getMigration(t, reconciler)
+	migration.Spec.Abort = true
+	if err := reconciler.client.Update(ctx, migration); err != nil {
+		t.Fatal(err)
+	}
+
+	// The copy is removed first
+	mustReconcile(t, reconciler, true)
+	if err := target.Get(ctx, types.NamespacedName{Name: testClusterName}, &kubermaticv1.Cluster{}); !kerrors.IsNotFound(err) {
+		t.Fatalf("expected the copy to be removed from the target seed, got %v", err)
+	}
+	if err := target.Get(ctx, types.NamespacedName{Namespace: resources.KubermaticNamespace, Name: "credential-hetzner-abcd"}, &corev1.Secret{}); !kerrors.IsNotFound(err) {
+		t.Fatalf("expected the copied credentials to be removed from the target seed, got %v", err)
+	}
+
+	mustReconcile(t, reconciler, false)
+	migration = getMigration(t, reconciler)
+	if migration.Status.Phase != kubermaticv1.ClusterMigrationPhaseAborted {
+		t.Fatalf("expected the migration to be aborted, got %+v", migration.Status)
+	}
+	cluster := getCluster(t, source)
+	if cluster.Spec.Pause || cluster.Annotations[ActiveMigrationAnnotationName] != "" || !hasFinalizer(cluster, kubermaticapiv1.NodeDeletionFinalizer) {
+		t.Fatalf("expected the source cluster to be resumed untouched, got %+v", cluster)
+	}
+	if err := source.Get(ctx, types.NamespacedName{Name: testClusterName}, &kubermaticv1.Cluster{}); err != nil {
+		t.Fatalf("expected the source cluster to be kept: %v", err)
+	}
+	if err := source.Get(ctx, types.NamespacedName{Namespace: metav1.NamespaceSystem, Name: job.Name}, &batchv1.Job{}); !kerrors.IsNotFound(err) {
+		t.Fatalf("expected the snapshot job to be deleted, got %v", err)
+	}
+}
+
+func TestAbortMigrationDuringNodeRollout(t *testing.T) {
+	ctx := context.Background()
+	reconciler, clients := newTestReconciler(genMigration("us-1"), genSourceObjects()...)
+	source, target := clients["europe"], clients["us"]
+
+	mustReconcile(t, reconciler, true)
+	job := &batchv1.Job{}
+	if err := source.Get(ctx, types.NamespacedName{Namespace: metav1.NamespaceSystem, Name: "etcd-migration-my-migration"}, job); err != nil {
+		t.Fatal(err)
+	}
+	job.Status.Succeeded = 1
+	job.Status.StartTime = &metav1.Time{Time: testSnapshotJobStart}
+	if err := source.Update(ctx, job); err != nil {
+		t.Fatal(err)
+	}
+	mustReconcile(t, reconciler, true)
+	if err := target.Create(ctx, &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Namespace: testNamespaceName, Name: resources.EtcdStatefulSetName},
+		Status:     appsv1.StatefulSetStatus{ReadyReplicas: 3},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	mustReconcile(t, reconciler, true)
+	restore := &kubermaticv1.EtcdRestore{}
+	if err := target.Get(ctx, types.NamespacedName{Namespace: testNamespaceName, Name: "my-migration"}, restore); err != nil {
+		t.Fatal(err)
+	}
+	restore.Status.Phase = kubermaticv1.EtcdRestorePhaseCompleted
+	if err := target.Update(ctx, restore); err != nil {
+		t.Fatal(err)
+	}
+	targetCluster := getCluster(t, target)
+	targetCluster.Address.URL = "https://abcd.us.example.com:30000"
+	if err := target.Update(ctx, targetCluster); err != nil {
+		t.Fatal(err)
+	}
+
+	// The rollout of the MachineDeployments starts
+	mustReconcile(t, reconciler, true)
+	migration := getMigration(t, reconciler)
+	if !migration.Status.HasConditionValue(kubermaticv1.ClusterMigrationConditionNodesUpdated, corev1.ConditionFalse) {
+		t.Fatalf("expected the node rollout to be recorded, got %+v", migration.Status.Conditions)
+	}
+
+	// An abort requested during the rollout is refused and the migration goes on
+	migration.Spec.Abort = true
+	if err := reconciler.client.Update(ctx, migration); err != nil {
+		t.Fatal(err)
+	}
+	mustReconcile(t, reconciler, true)
+
+	migration = getMigration(t, reconciler)
+	if migration.Status.Phase == kubermaticv1.ClusterMigrationPhaseAborted {
+		t.Fatalf("expected the migration not to be aborted during the node rollout, got %+v", migration.Status)
+	}
+	if !migration.Status.HasConditionValue(kubermaticv1.ClusterMigrationConditionAborted, corev1.ConditionFalse) {
+		t.Fatalf("expected the refused abort to be reported, got %+v", migration.Status.Conditions)
+	}
+	if targetCluster := getCluster(t, target); targetCluster.Spec.Pause {
+		t.Fatalf("expected the copy in the target seed to keep running, got %+v", targetCluster.Spec)
+	}
+	if cluster := getCluster(t, source); !cluster.Spec.Pause {
+		t.Fatalf("expected the source cluster to stay paused, got %+v", cluster.Spec)
+	}
+}
+
+func TestMigrationValidation(t *testing.T) {
+	testCases := []struct {
+		name           string
+		migration      *kubermaticv1.ClusterMigration
+		sourceObjects  []runtime.Object
+		expectedReason string
+	}{
+		{
+			name:           "scenario 1: the cluster must exist",
+			migration:      genMigration("us-1"),
+			expectedReason: "ClusterNotFound",
+		},
+		{
+			name:           "scenario 2: the target datacenter must offer the cloud provider of the cluster",
+			migration:      genMigration("us-aws"),
+			sourceObjects:  genSourceObjects(),
+			expectedReason: "CloudProviderMismatch",
+		},
+		{
+			name:           "scenario 3: both seeds must share the backup destination",
+			migration:      genMigration("us-2"),
+			sourceObjects:  genSourceObjects(),
+			expectedReason: "BackupDestinationMismatch",
+		},
+		{
+			name:           "scenario 4: the target datacenter must exist",
+			migration:      genMigration("asia-1"),
+			sourceObjects:  genSourceObjects(),
+			expectedReason: "DatacenterNotFound",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			reconciler, clients := newTestReconciler(tc.migration, tc.sourceObjects...)
+			mustReconcile(t, reconciler, false)
+
+			migration := getMigration(t, reconciler)
+			if migration.Status.Phase != kubermaticv1.ClusterMigrationPhaseFailed {
+				t.Fatalf("expected the migration to fail, got %+v", migration.Status)
+			}
+			if len(migration.Status.Conditions) != 1 || migration.Status.Conditions[0].Reason != tc.expectedReason {
+				t.Fatalf("expected the validation to fail with %s, got %+v", tc.expectedReason, migration.Status.Conditions)
+			}
+			if tc.sourceObjects != nil {
+				if cluster := getCluster(t, clients["europe"]); cluster.Spec.Pause {
+					t.Fatal("expected the cluster not to be paused by an invalid migration")
+				}
+			}
+		})
+	}
+}
+
+type fakeUserClusterConnectionProvider struct {
+	client ctrlruntimeclient.Client
+}
+
+func (p *fakeUserClusterConnectionProvider) GetClient(*kubermaticv1.Cluster, ...k8cuserclusterclient.ConfigOption) (ctrlruntimeclient.Client, error) {
+	return p.client, nil
+}
+
+func newTestReconciler(migration *kubermaticv1.ClusterMigration, sourceObjects ...runtime.Object) (*Reconciler, map[string]ctrlruntimeclient.Client) {
+	clients := map[string]ctrlruntimeclient.Client{
+		"europe": ctrlruntimefakeclient.NewFakeClient(sourceObjects...),
+		"us":     ctrlruntimefakeclient.NewFakeClient(),
+	}
+	hetzner := kubermaticv1.DatacenterSpec{Hetzner: &kubermaticv1.DatacenterSpecHetzner{}}
+	sharedDestination := map[string]kubermaticv1.BackupDestination{
+		"s3": {Endpoint: "s3.example.com", BucketName: "etcd-backups"},
+	}
+	backupPolicy := &kubermaticv1.EtcdBackupPolicy{Destination: "s3"}
+	seeds := map[string]*kubermaticv1.Seed{
+		"europe": {
+			ObjectMeta: metav1.ObjectMeta{Name: "europe"},
+			Spec: kubermaticv1.SeedSpec{
+				Datacenters: map[string]kubermaticv1.Datacenter{
+					"eu-1": {Spec: withBackupPolicy(hetzner, backupPolicy)},
+					"eu-2": {Spec: withBackupPolicy(hetzner, backupPolicy)},
+				},
+				BackupDestinations: sharedDestination,
+			},
+		},
+		"us": {
+			ObjectMeta: metav1.ObjectMeta{Name: "us"},
+			Spec: kubermaticv1.SeedSpec{
+				Datacenters: map[string]kubermaticv1.Datacenter{
+					"us-1":   {Spec: withBackupPolicy(hetzner, backupPolicy)},
+					"us-2":   {Spec: hetzner},
+					"us-aws": {Spec: kubermaticv1.DatacenterSpec{AWS: &kubermaticv1.DatacenterSpecAWS{}}},
+				},
+				BackupDestinations: sharedDestination,
+			},
+		},
+	}
+
+	userClusterScheme := runtime.NewScheme()
+	if err := scheme.AddToScheme(userClusterScheme); err != nil {
+		panic(err)
+	}
+	if err := clusterv1alpha1.AddToScheme(userClusterScheme); err != nil {
+		panic(err)
+	}
+	userCluster := ctrlruntimefakeclient.NewFakeClientWithScheme(userClusterScheme,
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespacePublic, Name: resources.ClusterInfoConfigMapName},
+			Data:       map[string]string{"kubeconfig": "server: https://abcd.us.example.com:30000"},
+		},
+		&clusterv1alpha1.MachineDeployment{
+			ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceSystem, Name: "workers"},
+			Spec:       clusterv1alpha1.MachineDeploymentSpec{Replicas: utilpointer.Int32Ptr(2)},
+		},
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "worker-1"}},
+	)
+
+	return &Reconciler{
+		ctx:         context.Background(),
+		log:         kubermaticlog.New(true, kubermaticlog.FormatConsole).Sugar(),
+		client:      ctrlruntimefakeclient.NewFakeClient(migration),
+		seedsGetter: func() (map[string]*kubermaticv1.Seed, error) { return seeds, nil },
+		seedClients: clients,
+		recorder:    record.NewFakeRecorder(100),
+		userClusterConnectionProviders: map[string]userClusterConnectionProvider{
+			"us": &fakeUserClusterConnectionProvider{client: userCluster},
+		},
+		listSnapshots: func(ctx context.Context, client ctrlruntimeclient.Client, cluster *kubermaticv1.Cluster, destination *kubermaticv1.BackupDestination) ([]kubermaticv1.EtcdSnapshot, error) {
+			return []kubermaticv1.EtcdSnapshot{
+				{Name: "abcd-storeuploader-2019-12-31T23:40:00-snapshot.db", CreationTimestamp: metav1.NewTime(testSnapshotJobStart.Add(-20 * time.Minute))},
+				{Name: testSnapshotName, CreationTimestamp: metav1.NewTime(testSnapshotJobStart.Add(time.Minute))},
+			}, nil
+		},
+	}, clients
+}
+
+func withBackupPolicy(spec kubermaticv1.DatacenterSpec, policy *kubermaticv1.EtcdBackupPolicy) kubermaticv1.DatacenterSpec {
+	spec.BackupPolicy = policy
+	return spec
+}
+
+func genMigration(targetDatacenter string) *kubermaticv1.ClusterMigration {
+	return &kubermaticv1.ClusterMigration{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-migration"},
+		Spec: kubermaticv1.ClusterMigrationSpec{
+			ClusterName:      testClusterName,
+			TargetDatacenter: targetDatacenter,
+		},
+	}
+}
+
+func genSourceObjects() []runtime.Object {
+	cluster := &kubermaticv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       testClusterName,
+			Labels:     map[string]string{kubermaticv1.ProjectIDLabelKey: "my-project"},
+			Finalizers: []string{kubermaticapiv1.NodeDeletionFinalizer, kubermaticapiv1.CredentialsSecretsCleanupFinalizer},
+		},
+		Spec: kubermaticv1.ClusterSpec{
+			Cloud: kubermaticv1.CloudSpec{
+				DatacenterName: "eu-1",
+				Hetzner:        &kubermaticv1.HetznerCloudSpec{},
+			},
+		},
+		Address: kubermaticv1.ClusterAddress{
+			URL:        "https://abcd.europe.example.com:30000",
+			AdminToken: "admin-token",
+		},
+		Status: kubermaticv1.ClusterStatus{
+			NamespaceName: testNamespaceName,
+		},
+	}
+	clusterRef := *metav1.NewControllerRef(cluster, kubermaticv1.SchemeGroupVersion.WithKind(kubermaticv1.ClusterKindName))
+
+	return []runtime.Object{
+		cluster,
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Namespace: testNamespaceName, Name: resources.ApiserverDeploymentName},
+			Spec:       appsv1.DeploymentSpec{Replicas: utilpointer.Int32Ptr(2)},
+		},
+		&batchv1beta1.CronJob{
+			ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceSystem, Name: "etcd-backup-abcd"},
+			Spec: batchv1beta1.CronJobSpec{
+				JobTemplate: batchv1beta1.JobTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{
+						Labels: map[string]string{resources.AppLabelKey: "kubermatic-etcd-backup", resources.ClusterLabelKey: testClusterName},
+					},
+					Spec: batchv1.JobSpec{
+						Template: corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "store-container"}}},
+						},
+					},
+				},
+			},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: testNamespaceName, Name: resources.CASecretName, OwnerReferences: []metav1.OwnerReference{clusterRef}},
+			Data:       map[string][]byte{resources.CACertSecretKey: []byte("ca")},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: testNamespaceName, Name: "default-token-abcde"},
+			Type:       corev1.SecretTypeServiceAccountToken,
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: resources.KubermaticNamespace, Name: "credential-hetzner-abcd"},
+			Data:       map[string][]byte{resources.HetznerToken: []byte("token")},
+		},
+	}
+}
+
+func mustReconcile(t *testing.T, reconciler *Reconciler, expectRequeue bool) {
+	t.Helper()
+	result, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "my-migration"}})
+	if err != nil {
+		t.Fatalf("failed to reconcile: %v", err)
+	}
+	if requeue := result.RequeueAfter > 0; requeue != expectRequeue {
+		t.Fatalf("expected requeue to be %v, got %+v", expectRequeue, result)
+	}
+}
+
+func getMigration(t *testing.T, reconciler *Reconciler) *kubermaticv1.ClusterMigration {
+	t.Helper()
+	migration := &kubermaticv1.ClusterMigration{}
+	if err := reconciler.client.Get(context.Background(), types.NamespacedName{Name: "my-migration"}, migration); err != nil {
+		t.Fatal(err)
+	}
+	return migration
+}
+
+func getCluster(t *testing.T, client ctrlruntimeclient.Client) *kubermaticv1.Cluster {
+	t.Helper()
+	cluster := &kubermaticv1.Cluster{}
+	if err := client.Get(context.Background(), types.NamespacedName{Name: testClusterName}, cluster); err != nil {
+		t.Fatal(err)
+	}
+	return cluster
+}
+
+func hasFinalizer(cluster *kubermaticv1.Cluster, finalizer string) bool {
+	for _, f := range cluster.Finalizers {
+		if f == finalizer {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,38 @@
+/*
+Copyright 2020 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package clustermigration contains a controller that moves the control plane of a user cluster into
+another datacenter, for example to drain a seed before it gets retired.
+
+A migration is driven by a ClusterMigration object in the master cluster. Within the same seed only
+the datacenter of the cluster is changed. A migration to a datacenter of another seed happens in these steps:
+
+  * The source cluster gets paused so no controller of the source seed touches the control plane anymore
+  * The apiserver in the source seed is scaled down to zero, so etcd doesn't change anymore
+  * A Job created from the backup CronJob of the cluster snapshots etcd into the backup destination
+  * The cluster, its namespace and its secrets are recreated in the target seed and the copy gets resumed
+  * Once etcd is running in the target seed, an EtcdRestore restores the snapshot there
+  * The address of the cluster is recomputed for the target seed, so it points to the DNS name of the target seed
+  * All MachineDeployments of the cluster are rolled out, so the new nodes join the apiserver in the target seed
+  * The cluster is removed from the source seed without cleaning up its nodes and cloud resources
+
+Every step is reflected by a condition on the ClusterMigration. If a step fails, the source cluster stays
+paused for manual inspection. Setting spec.abort deletes the copy in the target seed and resumes the source
+cluster, as long as the rollout of the nodes hasn't started. A migration within the same seed is aborted by restoring
+the original datacenter.
+*/
+package clustermigration
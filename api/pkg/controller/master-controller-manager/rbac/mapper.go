@@ -17,13 +17,19 @@ limitations under the License.
 package rbac
 
 import (
+	"context"
 	"fmt"
+	"regexp"
+	"sort"
 	"strings"
 
 	kubermaticv1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
 
 	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 const (
@@ -45,6 +51,7 @@ const (
 )
 
 // AllGroupsPrefixes holds a list of groups with prefixes that we will generate RBAC Roles/Binding for.
+// On top of these groups RBAC Roles/Binding are generated for the custom ProjectRoles, see projectRoles type.
 //
 // Note:
 // adding a new group also requires updating generateVerbsForNamedResource method.
@@ -55,31 +62,135 @@ var AllGroupsPrefixes = []string{
 	ViewerGroupNamePrefix,
 }
 
+// projectRoleNameRegex matches the names of the ProjectRoles that can be used as a group prefix,
+// words can be separated by a single dash, for example "cluster-operator"
+var projectRoleNameRegex = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// ValidateProjectRoleName checks if the name of a ProjectRole can be used as a group prefix
+func ValidateProjectRoleName(name string) error {
+	if !projectRoleNameRegex.MatchString(name) {
+		return fmt.Errorf("the name of a project role must consist of lower case alphanumeric words separated by a dash, got %q", name)
+	}
+	for _, groupPrefix := range AllGroupsPrefixes {
+		if strings.HasPrefix(name, groupPrefix) {
+			return fmt.Errorf("the name of a project role must not start with the built-in group %q, got %q", groupPrefix, name)
+		}
+	}
+	return nil
+}
+
+// NodeDeploymentKind is the kind of the NodeDeployments a custom ProjectRole can grant verbs for,
+// the API authorizes the members of the role against them instead of the RBAC inside of the user clusters
+const NodeDeploymentKind = "NodeDeployment"
+
+// UserClusterKinds are the kinds of resources that live inside of the user clusters. A custom ProjectRole
+// must not grant verbs for them, as its members are not granted any permissions inside of the user clusters.
+var UserClusterKinds = []string{"MachineDeployment", "Node"}
+
+// ValidateProjectRole checks if the given ProjectRole can be assigned to the members of a project
+func ValidateProjectRole(role *kubermaticv1.ProjectRole) error {
+	if err := ValidateProjectRoleName(role.Name); err != nil {
+		return err
+	}
+	for _, kind := range UserClusterKinds {
+		if len(role.VerbsFor(kind)) > 0 {
+			return fmt.Errorf("the project role %q must not grant verbs for %q, custom roles have no permissions inside of the user clusters", role.Name, kind)
+		}
+	}
+	return nil
+}
+
+// projectRoles holds the custom ProjectRoles by their names which are used as group prefixes
+type projectRoles map[string]*kubermaticv1.ProjectRole
+
+// newProjectRoles creates projectRoles out of the given ProjectRoles, invalid roles are skipped
+func newProjectRoles(roles []kubermaticv1.ProjectRole) projectRoles {
+	ret := projectRoles{}
+	for i := range roles {
+		role := &roles[i]
+		if err := ValidateProjectRole(role); err != nil {
+			klog.V(4).Infof("skipping ProjectRole %q: %v", role.Name, err)
+			continue
+		}
+		ret[role.Name] = role
+	}
+	return ret
+}
+
+// listProjectRoles lists the custom ProjectRoles, no roles are returned if the client is not set
+func listProjectRoles(ctx context.Context, client ctrlruntimeclient.Client) (projectRoles, error) {
+	if client == nil {
+		return nil, nil
+	}
+	var roleList kubermaticv1.ProjectRoleList
+	if err := client.List(ctx, &roleList); err != nil {
+		return nil, fmt.Errorf("failed to list project roles: %v", err)
+	}
+	return newProjectRoles(roleList.Items), nil
+}
+
+// groupPrefixes returns the prefixes of the built-in groups followed by the names of the custom roles
+func (r projectRoles) groupPrefixes() []string {
+	customPrefixes := []string{}
+	for name := range r {
+		customPrefixes = append(customPrefixes, name)
+	}
+	sort.Strings(customPrefixes)
+	return append(append([]string{}, AllGroupsPrefixes...), customPrefixes...)
+}
+
+// roleFor returns the custom ProjectRole for the given group, nil is returned for the built-in groups
+func (r projectRoles) roleFor(groupName string) *kubermaticv1.ProjectRole {
+	return r[r.groupPrefix(groupName)]
+}
+
+// groupPrefix returns the prefix of the given group name, which is either an actual group name
+// or already a group prefix, the name of a custom role might contain a dash so it is matched as a whole
+func (r projectRoles) groupPrefix(groupName string) string {
+	if _, ok := r[groupName]; ok {
+		return groupName
+	}
+	return ExtractGroupPrefix(groupName)
+}
+
 // GenerateActualGroupNameFor generates a group name for the given project and group prefix.
 func GenerateActualGroupNameFor(projectName, groupName string) string {
 	return fmt.Sprintf("%s-%s", groupName, projectName)
 }
 
 // ExtractGroupPrefix extracts only group prefix from the given group name
+//
+// Note:
+// the prefix of the built-in groups is the first word, the name of a custom role might contain dashes
+// thus its prefix is everything before the last dash as the names of the projects don't contain any
 func ExtractGroupPrefix(groupName string) string {
 	ret := strings.Split(groupName, "-")
-	if len(ret) > 0 {
-		return ret[0]
+	for _, groupPrefix := range AllGroupsPrefixes {
+		if ret[0] == groupPrefix {
+			return groupPrefix
+		}
+	}
+	if len(ret) > 1 {
+		return strings.Join(ret[:len(ret)-1], "-")
 	}
 	return groupName
 }
 
+// ExtractGroupPrefixForProject extracts only group prefix from the given group name of the given project
+func ExtractGroupPrefixForProject(projectName, groupName string) string {
+	return strings.TrimSuffix(groupName, "-"+projectName)
+}
+
 func generateRBACRoleNameForNamedResource(kind, resourceName, groupName string) string {
 	return fmt.Sprintf("%s:%s-%s:%s", RBACResourcesNamePrefix, strings.ToLower(kind), resourceName, groupName)
 }
 
-func generateRBACRoleNameForResources(resourceName, groupName string) string {
-	groupPrefix := ExtractGroupPrefix(groupName)
+func generateRBACRoleNameForResources(resourceName, groupPrefix string) string {
 	return fmt.Sprintf("%s:%s:%s", RBACResourcesNamePrefix, resourceName, groupPrefix)
 }
 
-func generateRBACRoleNameForClusterNamespaceResource(kind, groupName string) string {
-	return fmt.Sprintf("%s:%s:%s", RBACResourcesNamePrefix, strings.ToLower(kind), ExtractGroupPrefix(groupName))
+func generateRBACRoleNameForClusterNamespaceResource(kind, groupPrefix string) string {
+	return fmt.Sprintf("%s:%s:%s", RBACResourcesNamePrefix, strings.ToLower(kind), groupPrefix)
 }
 
 // generateClusterRBACRoleNamedResource generates ClusterRole for a named resource.
@@ -92,8 +203,8 @@ func generateRBACRoleNameForClusterNamespaceResource(kind, groupName string) str
 //   verbs: ["get"]
 //
 // Note that for some kinds we don't want to generate ClusterRole in that case a nil cluster resource will be returned without an error
-func generateClusterRBACRoleNamedResource(kind, groupName, policyResource, policyAPIGroups, policyResourceName string, oRef metav1.OwnerReference) (*rbacv1.ClusterRole, error) {
-	verbs, err := generateVerbsForNamedResource(groupName, kind)
+func generateClusterRBACRoleNamedResource(kind, groupName, policyResource, policyAPIGroups, policyResourceName string, oRef metav1.OwnerReference, roles projectRoles) (*rbacv1.ClusterRole, error) {
+	verbs, err := generateVerbsForNamedResource(groupName, kind, roles)
 	if err != nil {
 		return nil, err
 	}
@@ -143,8 +254,8 @@ func generateClusterRBACRoleBindingNamedResource(kind, resourceName, groupName s
 
 // generateClusterRBACRoleForResource generates ClusterRole for the given resource
 // Note that for some groups we don't want to generate ClusterRole in that case a nil will be returned
-func generateClusterRBACRoleForResource(groupName, policyResource, policyAPIGroups, kind string) (*rbacv1.ClusterRole, error) {
-	verbs, err := generateVerbsForResource(groupName, kind)
+func generateClusterRBACRoleForResource(groupName, policyResource, policyAPIGroups, kind string, roles projectRoles) (*rbacv1.ClusterRole, error) {
+	verbs, err := generateVerbsForResource(groupName, kind, roles)
 	if err != nil {
 		return nil, err
 	}
@@ -153,7 +264,7 @@ func generateClusterRBACRoleForResource(groupName, policyResource, policyAPIGrou
 	}
 	role := &rbacv1.ClusterRole{
 		ObjectMeta: metav1.ObjectMeta{
-			Name: generateRBACRoleNameForResources(policyResource, groupName),
+			Name: generateRBACRoleNameForResources(policyResource, roles.groupPrefix(groupName)),
 		},
 		Rules: []rbacv1.PolicyRule{
 			{
@@ -169,7 +280,7 @@ func generateClusterRBACRoleForResource(groupName, policyResource, policyAPIGrou
 func generateClusterRBACRoleBindingForResource(resourceName, groupName string) *rbacv1.ClusterRoleBinding {
 	binding := &rbacv1.ClusterRoleBinding{
 		ObjectMeta: metav1.ObjectMeta{
-			Name: generateRBACRoleNameForResources(resourceName, groupName),
+			Name: generateRBACRoleNameForResources(resourceName, ExtractGroupPrefix(groupName)),
 		},
 		Subjects: []rbacv1.Subject{
 			{
@@ -181,7 +292,7 @@ func generateClusterRBACRoleBindingForResource(resourceName, groupName string) *
 		RoleRef: rbacv1.RoleRef{
 			APIGroup: rbacv1.GroupName,
 			Kind:     "ClusterRole",
-			Name:     generateRBACRoleNameForResources(resourceName, groupName),
+			Name:     generateRBACRoleNameForResources(resourceName, ExtractGroupPrefix(groupName)),
 		},
 	}
 	return binding
@@ -190,7 +301,7 @@ func generateClusterRBACRoleBindingForResource(resourceName, groupName string) *
 func generateRBACRoleBindingForResource(resourceName, groupName, namespace string) *rbacv1.RoleBinding {
 	binding := &rbacv1.RoleBinding{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      generateRBACRoleNameForResources(resourceName, groupName),
+			Name:      generateRBACRoleNameForResources(resourceName, ExtractGroupPrefix(groupName)),
 			Namespace: namespace,
 		},
 		Subjects: []rbacv1.Subject{
@@ -203,7 +314,7 @@ func generateRBACRoleBindingForResource(resourceName, groupName, namespace strin
 		RoleRef: rbacv1.RoleRef{
 			APIGroup: rbacv1.GroupName,
 			Kind:     "Role",
-			Name:     generateRBACRoleNameForResources(resourceName, groupName),
+			Name:     generateRBACRoleNameForResources(resourceName, ExtractGroupPrefix(groupName)),
 		},
 	}
 	return binding
@@ -211,8 +322,8 @@ func generateRBACRoleBindingForResource(resourceName, groupName, namespace strin
 
 // generateRBACRoleForResource generates Role for the given resource in the given namespace
 // Note that for some groups we don't want to generate Role in that case a nil will be returned
-func generateRBACRoleForResource(groupName, policyResource, policyAPIGroups, kind string, namespace string) (*rbacv1.Role, error) {
-	verbs, err := generateVerbsForNamespacedResource(groupName, kind, namespace)
+func generateRBACRoleForResource(groupName, policyResource, policyAPIGroups, kind string, namespace string, roles projectRoles) (*rbacv1.Role, error) {
+	verbs, err := generateVerbsForNamespacedResource(groupName, kind, namespace, roles)
 	if err != nil {
 		return nil, err
 	}
@@ -221,7 +332,7 @@ func generateRBACRoleForResource(groupName, policyResource, policyAPIGroups, kin
 	}
 	role := &rbacv1.Role{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      generateRBACRoleNameForResources(policyResource, groupName),
+			Name:      generateRBACRoleNameForResources(policyResource, roles.groupPrefix(groupName)),
 			Namespace: namespace,
 		},
 		Rules: []rbacv1.PolicyRule{
@@ -245,8 +356,8 @@ func generateRBACRoleForResource(groupName, policyResource, policyAPIGroups, kin
 //   verbs: ["get"]
 //
 // Note that for some kinds we don't want to generate Role in that case a nil cluster resource will be returned without an error
-func generateRBACRoleNamedResource(kind, groupName, policyResource, policyAPIGroups, policyResourceName string, namespace string, oRef metav1.OwnerReference) (*rbacv1.Role, error) {
-	verbs, err := generateVerbsForNamedResourceInNamespace(groupName, kind, namespace)
+func generateRBACRoleNamedResource(kind, groupName, policyResource, policyAPIGroups, policyResourceName string, namespace string, oRef metav1.OwnerReference, roles projectRoles) (*rbacv1.Role, error) {
+	verbs, err := generateVerbsForNamedResourceInNamespace(groupName, kind, namespace, roles)
 	if err != nil {
 		return nil, err
 	}
@@ -298,8 +409,8 @@ func generateRBACRoleBindingNamedResource(kind, resourceName, groupName, namespa
 
 // generateRBACRoleForClusterNamespaceResource generates per-cluster Role for the given cluster in the cluster namespace
 // Note that for some groups we don't want to generate Role in that case a nil will be returned
-func generateRBACRoleForClusterNamespaceResource(cluster *kubermaticv1.Cluster, groupName, policyResource, policyAPIGroups, kind string) (*rbacv1.Role, error) {
-	verbs, err := generateVerbsForClusterNamespaceResource(cluster, groupName, kind)
+func generateRBACRoleForClusterNamespaceResource(cluster *kubermaticv1.Cluster, groupName, policyResource, policyAPIGroups, kind string, roles projectRoles) (*rbacv1.Role, error) {
+	verbs, err := generateVerbsForClusterNamespaceResource(cluster, groupName, kind, roles)
 	if err != nil {
 		return nil, err
 	}
@@ -308,7 +419,7 @@ func generateRBACRoleForClusterNamespaceResource(cluster *kubermaticv1.Cluster,
 	}
 	role := &rbacv1.Role{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      generateRBACRoleNameForClusterNamespaceResource(kind, groupName),
+			Name:      generateRBACRoleNameForClusterNamespaceResource(kind, roles.groupPrefix(groupName)),
 			Namespace: cluster.Status.NamespaceName,
 		},
 		Rules: []rbacv1.PolicyRule{
@@ -327,7 +438,7 @@ func generateRBACRoleForClusterNamespaceResource(cluster *kubermaticv1.Cluster,
 func generateRBACRoleBindingForClusterNamespaceResource(cluster *kubermaticv1.Cluster, groupName, kind string) *rbacv1.RoleBinding {
 	binding := &rbacv1.RoleBinding{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      generateRBACRoleNameForClusterNamespaceResource(kind, groupName),
+			Name:      generateRBACRoleNameForClusterNamespaceResource(kind, ExtractGroupPrefix(groupName)),
 			Namespace: cluster.Status.NamespaceName,
 		},
 		Subjects: []rbacv1.Subject{
@@ -340,7 +451,7 @@ func generateRBACRoleBindingForClusterNamespaceResource(cluster *kubermaticv1.Cl
 		RoleRef: rbacv1.RoleRef{
 			APIGroup: rbacv1.GroupName,
 			Kind:     "Role",
-			Name:     generateRBACRoleNameForClusterNamespaceResource(kind, groupName),
+			Name:     generateRBACRoleNameForClusterNamespaceResource(kind, ExtractGroupPrefix(groupName)),
 		},
 	}
 	return binding
@@ -348,7 +459,12 @@ func generateRBACRoleBindingForClusterNamespaceResource(cluster *kubermaticv1.Cl
 
 // generateVerbsForNamedResource generates a set of verbs for a named resource
 // for example a "cluster" named "beefy-john"
-func generateVerbsForNamedResource(groupName, resourceKind string) ([]string, error) {
+func generateVerbsForNamedResource(groupName, resourceKind string, roles projectRoles) ([]string, error) {
+	// verbs for custom project roles
+	if role := roles.roleFor(groupName); role != nil {
+		return generateVerbsForProjectRole(role, resourceKind, "get", "update", "delete"), nil
+	}
+
 	// verbs for owners
 	//
 	// owners of a named resource
@@ -398,7 +514,12 @@ func generateVerbsForNamedResource(groupName, resourceKind string) ([]string, er
 
 // generateVerbsForResource generates verbs for a resource for example "cluster"
 // to make it even more concrete, if there is "create" verb returned for owners group, that means that the owners can create "cluster" resources.
-func generateVerbsForResource(groupName, resourceKind string) ([]string, error) {
+func generateVerbsForResource(groupName, resourceKind string, roles projectRoles) ([]string, error) {
+	// verbs for custom project roles
+	if role := roles.roleFor(groupName); role != nil {
+		return generateVerbsForProjectRole(role, resourceKind, "create"), nil
+	}
+
 	// special case - only the owners of a project can manipulate members
 	//
 	if strings.HasPrefix(groupName, OwnerGroupNamePrefix) && resourceKind == kubermaticv1.UserProjectBindingKind {
@@ -433,11 +554,14 @@ func generateVerbsForResource(groupName, resourceKind string) ([]string, error)
 	return nil, fmt.Errorf("unable to generate verbs, unknown group name passed in = %s", groupName)
 }
 
-func generateVerbsForNamespacedResource(groupName, resourceKind, namespace string) ([]string, error) {
+func generateVerbsForNamespacedResource(groupName, resourceKind, namespace string, roles projectRoles) ([]string, error) {
 	// special case - only the owners of a project can create secrets in "saSecretsNamespaceName" namespace
 	//
 	if namespace == saSecretsNamespaceName {
 		secretV1Kind := "Secret"
+		if role := roles.roleFor(groupName); role != nil && resourceKind == secretV1Kind {
+			return generateVerbsForProjectRole(role, resourceKind, "create"), nil
+		}
 		if strings.HasPrefix(groupName, OwnerGroupNamePrefix) && resourceKind == secretV1Kind {
 			return []string{"create"}, nil
 		} else if resourceKind == secretV1Kind {
@@ -451,11 +575,14 @@ func generateVerbsForNamespacedResource(groupName, resourceKind, namespace strin
 
 // generateVerbsForNamedResourceInNamespace generates a set of verbs for a named resource in a given namespace
 // for example a "cluster" named "beefy-john"
-func generateVerbsForNamedResourceInNamespace(groupName, resourceKind, namespace string) ([]string, error) {
+func generateVerbsForNamedResourceInNamespace(groupName, resourceKind, namespace string, roles projectRoles) ([]string, error) {
 	// special case - only the owners of a project can manipulate secrets in "ssaSecretsNamespaceNam" namespace
 	//
 	if namespace == saSecretsNamespaceName {
 		secretV1Kind := "Secret"
+		if role := roles.roleFor(groupName); role != nil && resourceKind == secretV1Kind {
+			return generateVerbsForProjectRole(role, resourceKind, "get", "update", "delete"), nil
+		}
 		if strings.HasPrefix(groupName, OwnerGroupNamePrefix) && resourceKind == secretV1Kind {
 			return []string{"get", "update", "delete"}, nil
 		} else if resourceKind == secretV1Kind {
@@ -467,8 +594,12 @@ func generateVerbsForNamedResourceInNamespace(groupName, resourceKind, namespace
 	return nil, fmt.Errorf("unable to generate verbs for group = %s, kind = %s, namespace = %s", groupName, resourceKind, namespace)
 }
 
-func generateVerbsForClusterNamespaceResource(cluster *kubermaticv1.Cluster, groupName, kind string) ([]string, error) {
-	if strings.HasPrefix(groupName, ViewerGroupNamePrefix) && kind == kubermaticv1.AddonKindName {
+func generateVerbsForClusterNamespaceResource(cluster *kubermaticv1.Cluster, groupName, kind string, roles projectRoles) ([]string, error) {
+	if role := roles.roleFor(groupName); role != nil {
+		return generateVerbsForProjectRole(role, kind, "get", "list", "create", "update", "delete"), nil
+	}
+
+	if strings.HasPrefix(groupName, ViewerGroupNamePrefix) && (kind == kubermaticv1.AddonKindName || kind == kubermaticv1.EtcdRestoreKindName) {
 		return []string{"get", "list"}, nil
 	}
 
@@ -479,3 +610,16 @@ func generateVerbsForClusterNamespaceResource(cluster *kubermaticv1.Cluster, gro
 	// unknown group passed
 	return nil, fmt.Errorf("unable to generate verbs for cluster namespace resource cluster = %s, group = %s, kind = %s", cluster.Name, groupName, kind)
 }
+
+// generateVerbsForProjectRole generates a set of verbs the given custom role grants for the kind,
+// only the supportedVerbs are taken into account, nil is returned if the role grants none of them
+func generateVerbsForProjectRole(role *kubermaticv1.ProjectRole, kind string, supportedVerbs ...string) []string {
+	grantedVerbs := sets.NewString(role.VerbsFor(kind)...)
+	var verbs []string
+	for _, verb := range supportedVerbs {
+		if grantedVerbs.Has(verb) {
+			verbs = append(verbs, verb)
+		}
+	}
+	return verbs
+}
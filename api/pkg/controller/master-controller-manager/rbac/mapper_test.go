@@ -19,7 +19,10 @@ package rbac
 import (
 	"testing"
 
+	kubermaticv1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
+
 	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestGenerateVerbsForNamedResources(t *testing.T) {
@@ -93,7 +96,7 @@ func TestGenerateVerbsForNamedResources(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			if returnedVerbs, err := generateVerbsForNamedResource(test.groupName, test.resourceKind); err != nil || !equality.Semantic.DeepEqual(returnedVerbs, test.expectedVerbs) {
+			if returnedVerbs, err := generateVerbsForNamedResource(test.groupName, test.resourceKind, nil); err != nil || !equality.Semantic.DeepEqual(returnedVerbs, test.expectedVerbs) {
 				t.Fatalf("incorrect verbs were returned, got: %v, want: %v, err: %v", returnedVerbs, test.expectedVerbs, err)
 			}
 		})
@@ -176,9 +179,143 @@ func TestGenerateVerbsForResources(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			if returnedVerbs, err := generateVerbsForResource(test.groupName, test.resourceKind); err != nil || !equality.Semantic.DeepEqual(returnedVerbs, test.expectedVerbs) {
+			if returnedVerbs, err := generateVerbsForResource(test.groupName, test.resourceKind, nil); err != nil || !equality.Semantic.DeepEqual(returnedVerbs, test.expectedVerbs) {
+				t.Fatalf("incorrect verbs were returned, got: %v, want: %v, err: %v", returnedVerbs, test.expectedVerbs, err)
+			}
+		})
+	}
+}
+
+func TestGenerateVerbsForProjectRole(t *testing.T) {
+	roles := newProjectRoles([]kubermaticv1.ProjectRole{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "clusteroperator"},
+			Spec: kubermaticv1.ProjectRoleSpec{
+				Rules: []kubermaticv1.ProjectRoleRule{
+					{Kinds: []string{"Project", "Cluster"}, Verbs: []string{"get"}},
+					{Kinds: []string{"Cluster", "Addon"}, Verbs: []string{"update", "list"}},
+				},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-operator"},
+			Spec: kubermaticv1.ProjectRoleSpec{
+				Rules: []kubermaticv1.ProjectRoleRule{
+					{Kinds: []string{"Cluster"}, Verbs: []string{"get"}},
+					{Kinds: []string{"NodeDeployment"}, Verbs: []string{"get", "patch"}},
+				},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "machineoperator"},
+			Spec: kubermaticv1.ProjectRoleSpec{
+				Rules: []kubermaticv1.ProjectRoleRule{
+					{Kinds: []string{"Cluster", "MachineDeployment"}, Verbs: []string{"get", "update"}},
+				},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster--operator"},
+		},
+	})
+
+	if prefixes := roles.groupPrefixes(); !equality.Semantic.DeepEqual(prefixes, []string{"owners", "editors", "viewers", "cluster-operator", "clusteroperator"}) {
+		t.Fatalf("incorrect group prefixes were returned, got: %v", prefixes)
+	}
+
+	tests := []struct {
+		name          string
+		generateVerbs func() ([]string, error)
+		expectedVerbs []string
+	}{
+		{
+			name: "scenario 1: the verbs of all rules are granted for a named resource",
+			generateVerbs: func() ([]string, error) {
+				return generateVerbsForNamedResource("clusteroperator-projectID", "Cluster", roles)
+			},
+			expectedVerbs: []string{"get", "update"},
+		},
+		{
+			name: "scenario 2: the verbs that are not supported for a named resource are skipped",
+			generateVerbs: func() ([]string, error) {
+				return generateVerbsForNamedResource("clusteroperator-projectID", "Addon", roles)
+			},
+			expectedVerbs: []string{"update"},
+		},
+		{
+			name: "scenario 3: a custom role cannot create resources it has no create verb for",
+			generateVerbs: func() ([]string, error) {
+				return generateVerbsForResource("clusteroperator", "Cluster", roles)
+			},
+			expectedVerbs: nil,
+		},
+		{
+			name: "scenario 4: the verbs of a custom role are granted for resources in the cluster namespace",
+			generateVerbs: func() ([]string, error) {
+				return generateVerbsForClusterNamespaceResource(&kubermaticv1.Cluster{}, "clusteroperator-projectID", "Addon", roles)
+			},
+			expectedVerbs: []string{"list", "update"},
+		},
+		{
+			name: "scenario 5: a custom role has no access to secrets unless granted",
+			generateVerbs: func() ([]string, error) {
+				return generateVerbsForNamedResourceInNamespace("clusteroperator-projectID", "Secret", "kubermatic", roles)
+			},
+			expectedVerbs: nil,
+		},
+		{
+			name: "scenario 6: a custom role with a dash in its name cannot delete a cluster it has no delete verb for",
+			generateVerbs: func() ([]string, error) {
+				return generateVerbsForNamedResource("cluster-operator-projectID", "Cluster", roles)
+			},
+			expectedVerbs: []string{"get"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if returnedVerbs, err := test.generateVerbs(); err != nil || !equality.Semantic.DeepEqual(returnedVerbs, test.expectedVerbs) {
 				t.Fatalf("incorrect verbs were returned, got: %v, want: %v, err: %v", returnedVerbs, test.expectedVerbs, err)
 			}
 		})
 	}
 }
+
+func TestExtractGroupPrefix(t *testing.T) {
+	roles := newProjectRoles([]kubermaticv1.ProjectRole{{ObjectMeta: metav1.ObjectMeta{Name: "cluster-operator"}}})
+
+	tests := []struct {
+		name                string
+		groupName           string
+		expectedGroupPrefix string
+	}{
+		{
+			name:                "scenario 1: the prefix of a built-in group is the first word",
+			groupName:           "owners-my-first-project-ID",
+			expectedGroupPrefix: "owners",
+		},
+		{
+			name:                "scenario 2: the prefix of a custom role is everything before the project name",
+			groupName:           "cluster-operator-projectID",
+			expectedGroupPrefix: "cluster-operator",
+		},
+		{
+			name:                "scenario 3: the prefix of a custom role without a dash is the first word",
+			groupName:           "clusteroperator-projectID",
+			expectedGroupPrefix: "clusteroperator",
+		},
+		{
+			name:                "scenario 4: the name of a custom role is already a group prefix",
+			groupName:           "cluster-operator",
+			expectedGroupPrefix: "cluster-operator",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if groupPrefix := roles.groupPrefix(test.groupName); groupPrefix != test.expectedGroupPrefix {
+				t.Fatalf("incorrect group prefix was returned, got: %s, want: %s", groupPrefix, test.expectedGroupPrefix)
+			}
+		})
+	}
+}
@@ -185,7 +185,7 @@ func New(metrics *Metrics, mgr manager.Manager, seedManagerMap map[string]manage
 		return nil, err
 	}
 
-	resourcesRBACCtrl, err := newResourcesController(metrics, masterClusterProvider, seedClusterProviders, projectResources)
+	resourcesRBACCtrl, err := newResourcesController(metrics, mgr, masterClusterProvider, seedClusterProviders, projectResources)
 	if err != nil {
 		return nil, err
 	}
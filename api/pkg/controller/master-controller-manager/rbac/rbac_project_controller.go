@@ -18,13 +18,17 @@ package rbac
 
 import (
 	"context"
+	"fmt"
 
 	kubermaticv1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
 
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/client-go/util/workqueue"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
@@ -82,6 +86,12 @@ func newProjectRBACController(metrics *Metrics, mgr manager.Manager, seedManager
 		return err
 	}
 
+	// Watch for changes to ProjectRoles, the RBAC of all projects has to be updated for them
+	err = cc.Watch(&source.Kind{Type: &kubermaticv1.ProjectRole{}}, enqueueAllProjects(c.client, workerPredicate))
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -93,3 +103,23 @@ func (c *projectController) Reconcile(req reconcile.Request) (reconcile.Result,
 
 	return reconcile.Result{}, nil
 }
+
+// enqueueAllProjects enqueues all projects that match the given predicate
+func enqueueAllProjects(client client.Client, workerPredicate predicate.Predicate) *handler.EnqueueRequestsFromMapFunc {
+	return &handler.EnqueueRequestsFromMapFunc{ToRequests: handler.ToRequestsFunc(func(a handler.MapObject) []reconcile.Request {
+		var projectList kubermaticv1.ProjectList
+		if err := client.List(context.Background(), &projectList); err != nil {
+			utilruntime.HandleError(fmt.Errorf("failed to list projects: %v", err))
+			return []reconcile.Request{}
+		}
+		requests := []reconcile.Request{}
+		for i := range projectList.Items {
+			project := &projectList.Items[i]
+			if !workerPredicate.Generic(event.GenericEvent{Meta: project, Object: project}) {
+				continue
+			}
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: project.Name}})
+		}
+		return requests
+	})}
+}
@@ -31,6 +31,8 @@ import (
 	kcache "k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
 )
 
 const projectResourcesResyncTime = 5 * time.Minute
@@ -40,6 +42,9 @@ type resourcesController struct {
 
 	metrics          *Metrics
 	projectResources []projectResource
+
+	// masterClient is used to get the custom ProjectRoles
+	masterClient ctrlruntimeclient.Client
 }
 
 type resourceToProcess struct {
@@ -63,11 +68,12 @@ func (i *resourceToProcess) String() string {
 }
 
 // newResourcesController creates a new controller for managing RBAC for named resources that belong to project
-func newResourcesController(metrics *Metrics, masterClusterProvider *ClusterProvider, seedClusterProviders []*ClusterProvider, resources []projectResource) (*resourcesController, error) {
+func newResourcesController(metrics *Metrics, mgr manager.Manager, masterClusterProvider *ClusterProvider, seedClusterProviders []*ClusterProvider, resources []projectResource) (*resourcesController, error) {
 	c := &resourcesController{
 		projectResourcesQueue: workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "rbac_generator_resources"),
 		metrics:               metrics,
 		projectResources:      resources,
+		masterClient:          mgr.GetClient(),
 	}
 
 	klog.V(4).Infof("considering %s master cluster provider for resources", masterClusterProvider.providerName)
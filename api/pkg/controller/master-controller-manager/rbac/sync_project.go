@@ -61,10 +61,14 @@ func (c *projectController) sync(key client.ObjectKey) error {
 	if err := c.ensureProjectOwner(project); err != nil {
 		return fmt.Errorf("failed to ensure that the project owner exists in the owners group: %v", err)
 	}
-	if err := ensureClusterRBACRoleForNamedResource(project.Name, kubermaticv1.ProjectResourceName, kubermaticv1.ProjectKindName, project.GetObjectMeta(), c.masterClusterProvider.kubeClient, c.masterClusterProvider.kubeInformerProvider.KubeInformerFactoryFor(metav1.NamespaceAll).Rbac().V1().ClusterRoles().Lister()); err != nil {
+	roles, err := listProjectRoles(c.ctx, c.client)
+	if err != nil {
+		return err
+	}
+	if err := ensureClusterRBACRoleForNamedResource(project.Name, kubermaticv1.ProjectResourceName, kubermaticv1.ProjectKindName, project.GetObjectMeta(), c.masterClusterProvider.kubeClient, c.masterClusterProvider.kubeInformerProvider.KubeInformerFactoryFor(metav1.NamespaceAll).Rbac().V1().ClusterRoles().Lister(), roles); err != nil {
 		return fmt.Errorf("failed to ensure that the RBAC Role for the project exists: %v", err)
 	}
-	if err := ensureClusterRBACRoleBindingForNamedResource(project.Name, kubermaticv1.ProjectResourceName, kubermaticv1.ProjectKindName, project.GetObjectMeta(), c.masterClusterProvider.kubeClient, c.masterClusterProvider.kubeInformerProvider.KubeInformerFactoryFor(metav1.NamespaceAll).Rbac().V1().ClusterRoleBindings().Lister()); err != nil {
+	if err := ensureClusterRBACRoleBindingForNamedResource(project.Name, kubermaticv1.ProjectResourceName, kubermaticv1.ProjectKindName, project.GetObjectMeta(), c.masterClusterProvider.kubeClient, c.masterClusterProvider.kubeInformerProvider.KubeInformerFactoryFor(metav1.NamespaceAll).Rbac().V1().ClusterRoleBindings().Lister(), roles); err != nil {
 		return fmt.Errorf("failed to ensure that the RBAC RoleBinding for the project exists: %v", err)
 	}
 	if err := c.ensureClusterRBACRoleForResources(); err != nil {
@@ -155,21 +159,25 @@ func (c *projectController) ensureProjectOwner(project *kubermaticv1.Project) er
 }
 
 func (c *projectController) ensureClusterRBACRoleForResources() error {
+	roles, err := listProjectRoles(c.ctx, c.client)
+	if err != nil {
+		return err
+	}
 	for _, projectResource := range c.projectResources {
 		if len(projectResource.namespace) > 0 {
 			continue
 		}
-		for _, groupPrefix := range AllGroupsPrefixes {
+		for _, groupPrefix := range roles.groupPrefixes() {
 
 			if projectResource.destination == destinationSeed {
 				for _, seedClusterRESTClient := range c.seedClientMap {
-					err := ensureClusterRBACRoleForResource(c.ctx, seedClusterRESTClient, groupPrefix, projectResource.gvr.Resource, projectResource.kind)
+					err := ensureClusterRBACRoleForResource(c.ctx, seedClusterRESTClient, groupPrefix, projectResource.gvr.Resource, projectResource.kind, roles)
 					if err != nil {
 						return err
 					}
 				}
 			} else {
-				err := ensureClusterRBACRoleForResource(c.ctx, c.client, groupPrefix, projectResource.gvr.Resource, projectResource.kind)
+				err := ensureClusterRBACRoleForResource(c.ctx, c.client, groupPrefix, projectResource.gvr.Resource, projectResource.kind, roles)
 				if err != nil {
 					return err
 				}
@@ -180,14 +188,18 @@ func (c *projectController) ensureClusterRBACRoleForResources() error {
 }
 
 func (c *projectController) ensureClusterRBACRoleBindingForResources(projectName string) error {
+	roles, err := listProjectRoles(c.ctx, c.client)
+	if err != nil {
+		return err
+	}
 	for _, projectResource := range c.projectResources {
 		if len(projectResource.namespace) > 0 {
 			continue
 		}
-		for _, groupPrefix := range AllGroupsPrefixes {
+		for _, groupPrefix := range roles.groupPrefixes() {
 			groupName := GenerateActualGroupNameFor(projectName, groupPrefix)
 
-			if skip, err := shouldSkipClusterRBACRoleBindingFor(groupName, projectResource.gvr.Resource, kubermaticv1.SchemeGroupVersion.Group, projectName, projectResource.kind); skip {
+			if skip, err := shouldSkipClusterRBACRoleBindingFor(groupName, projectResource.gvr.Resource, kubermaticv1.SchemeGroupVersion.Group, projectName, projectResource.kind, roles); skip {
 				continue
 			} else if err != nil {
 				return err
@@ -219,8 +231,8 @@ func (c *projectController) ensureClusterRBACRoleBindingForResources(projectName
 	return nil
 }
 
-func ensureClusterRBACRoleForResource(ctx context.Context, c client.Client, groupName, resource, kind string) error {
-	generatedClusterRole, err := generateClusterRBACRoleForResource(groupName, resource, kubermaticv1.SchemeGroupVersion.Group, kind)
+func ensureClusterRBACRoleForResource(ctx context.Context, c client.Client, groupName, resource, kind string, roles projectRoles) error {
+	generatedClusterRole, err := generateClusterRBACRoleForResource(groupName, resource, kubermaticv1.SchemeGroupVersion.Group, kind, roles)
 	if err != nil {
 		return err
 	}
@@ -286,11 +298,15 @@ func ensureClusterRBACRoleBindingForResource(ctx context.Context, c client.Clien
 }
 
 func (c *projectController) ensureRBACRoleForResources() error {
+	roles, err := listProjectRoles(c.ctx, c.client)
+	if err != nil {
+		return err
+	}
 	for _, projectResource := range c.projectResources {
 		if len(projectResource.namespace) == 0 {
 			continue
 		}
-		for _, groupPrefix := range AllGroupsPrefixes {
+		for _, groupPrefix := range roles.groupPrefixes() {
 
 			if projectResource.destination == destinationSeed {
 				for _, seedClusterRESTClient := range c.seedClientMap {
@@ -300,7 +316,8 @@ func (c *projectController) ensureRBACRoleForResources() error {
 						groupPrefix,
 						projectResource.gvr,
 						projectResource.kind,
-						projectResource.namespace)
+						projectResource.namespace,
+						roles)
 					if err != nil {
 						return err
 					}
@@ -312,7 +329,8 @@ func (c *projectController) ensureRBACRoleForResources() error {
 					groupPrefix,
 					projectResource.gvr,
 					projectResource.kind,
-					projectResource.namespace)
+					projectResource.namespace,
+					roles)
 				if err != nil {
 					return err
 				}
@@ -322,8 +340,8 @@ func (c *projectController) ensureRBACRoleForResources() error {
 	return nil
 }
 
-func ensureRBACRoleForResource(ctx context.Context, c client.Client, groupName string, gvr schema.GroupVersionResource, kind string, namespace string) error {
-	generatedRole, err := generateRBACRoleForResource(groupName, gvr.Resource, gvr.Group, kind, namespace)
+func ensureRBACRoleForResource(ctx context.Context, c client.Client, groupName string, gvr schema.GroupVersionResource, kind string, namespace string, roles projectRoles) error {
+	generatedRole, err := generateRBACRoleForResource(groupName, gvr.Resource, gvr.Group, kind, namespace, roles)
 	if err != nil {
 		return err
 	}
@@ -349,14 +367,18 @@ func ensureRBACRoleForResource(ctx context.Context, c client.Client, groupName s
 }
 
 func (c *projectController) ensureRBACRoleBindingForResources(projectName string) error {
+	roles, err := listProjectRoles(c.ctx, c.client)
+	if err != nil {
+		return err
+	}
 	for _, projectResource := range c.projectResources {
 		if len(projectResource.namespace) == 0 {
 			continue
 		}
-		for _, groupPrefix := range AllGroupsPrefixes {
+		for _, groupPrefix := range roles.groupPrefixes() {
 			groupName := GenerateActualGroupNameFor(projectName, groupPrefix)
 
-			if skip, err := shouldSkipRBACRoleBindingFor(groupName, projectResource.gvr.Resource, kubermaticv1.SchemeGroupVersion.Group, projectName, projectResource.kind, projectResource.namespace); skip {
+			if skip, err := shouldSkipRBACRoleBindingFor(groupName, projectResource.gvr.Resource, kubermaticv1.SchemeGroupVersion.Group, projectName, projectResource.kind, projectResource.namespace, roles); skip {
 				continue
 			} else if err != nil {
 				return err
@@ -449,14 +471,19 @@ func (c *projectController) ensureProjectCleanup(project *kubermaticv1.Project)
 		}
 	}
 
+	roles, err := listProjectRoles(c.ctx, c.client)
+	if err != nil {
+		return err
+	}
+
 	// remove subjects from Cluster RBAC Bindings for project's resources
 	for _, projectResource := range c.projectResources {
 		if len(projectResource.namespace) > 0 {
 			continue
 		}
-		for _, groupPrefix := range AllGroupsPrefixes {
+		for _, groupPrefix := range roles.groupPrefixes() {
 			groupName := GenerateActualGroupNameFor(project.Name, groupPrefix)
-			if skip, err := shouldSkipClusterRBACRoleBindingFor(groupName, projectResource.gvr.Resource, kubermaticv1.SchemeGroupVersion.Group, project.Name, projectResource.kind); skip {
+			if skip, err := shouldSkipClusterRBACRoleBindingFor(groupName, projectResource.gvr.Resource, kubermaticv1.SchemeGroupVersion.Group, project.Name, projectResource.kind, roles); skip {
 				continue
 			} else if err != nil {
 				return err
@@ -483,9 +510,9 @@ func (c *projectController) ensureProjectCleanup(project *kubermaticv1.Project)
 		if len(projectResource.namespace) == 0 {
 			continue
 		}
-		for _, groupPrefix := range AllGroupsPrefixes {
+		for _, groupPrefix := range roles.groupPrefixes() {
 			groupName := GenerateActualGroupNameFor(project.Name, groupPrefix)
-			if skip, err := shouldSkipRBACRoleBindingFor(groupName, projectResource.gvr.Resource, kubermaticv1.SchemeGroupVersion.Group, project.Name, projectResource.kind, projectResource.namespace); skip {
+			if skip, err := shouldSkipRBACRoleBindingFor(groupName, projectResource.gvr.Resource, kubermaticv1.SchemeGroupVersion.Group, project.Name, projectResource.kind, projectResource.namespace, roles); skip {
 				continue
 			} else if err != nil {
 				return err
@@ -574,8 +601,8 @@ func (c *projectController) shouldDeleteProject(project *kubermaticv1.Project) b
 // thus before doing something with ClusterRoleBinding check if the role was generated for the given resource and the group
 //
 // note: this method will add status to the log file
-func shouldSkipClusterRBACRoleBindingFor(groupName, policyResource, policyAPIGroups, projectName, kind string) (bool, error) {
-	generatedClusterRole, err := generateClusterRBACRoleForResource(groupName, policyResource, policyAPIGroups, kind)
+func shouldSkipClusterRBACRoleBindingFor(groupName, policyResource, policyAPIGroups, projectName, kind string, roles projectRoles) (bool, error) {
+	generatedClusterRole, err := generateClusterRBACRoleForResource(groupName, policyResource, policyAPIGroups, kind, roles)
 	if err != nil {
 		return false, err
 	}
@@ -590,8 +617,8 @@ func shouldSkipClusterRBACRoleBindingFor(groupName, policyResource, policyAPIGro
 // thus before doing something with RoleBinding check if the role was generated for the given resource and the group
 //
 // note: this method will add status to the log file
-func shouldSkipRBACRoleBindingFor(groupName, policyResource, policyAPIGroups, projectName, kind, namespace string) (bool, error) {
-	generatedRole, err := generateRBACRoleForResource(groupName, policyResource, policyAPIGroups, kind, namespace)
+func shouldSkipRBACRoleBindingFor(groupName, policyResource, policyAPIGroups, projectName, kind, namespace string, roles projectRoles) (bool, error) {
+	generatedRole, err := generateRBACRoleForResource(groupName, policyResource, policyAPIGroups, kind, namespace, roles)
 	if err != nil {
 		return false, err
 	}
@@ -1044,6 +1044,7 @@ func TestEnsureProjectClusterRBACRoleForResources(t *testing.T) {
 		seedClusters                  int
 		expectedActionsForSeeds       []string
 		expectedClusterRolesForSeeds  []*rbacv1.ClusterRole
+		existingProjectRoles          []runtime.Object
 	}{
 		// scenario 1
 		{
@@ -1167,13 +1168,126 @@ func TestEnsureProjectClusterRBACRoleForResources(t *testing.T) {
 				},
 			},
 		},
+
+		// scenario 3
+		{
+			name:                     "Scenario 3: RBAC Roles for project's resources are created for a custom project role that is allowed to create them",
+			expectedActionsForMaster: []string{"create", "create", "create"},
+			expectedActionsForSeeds:  []string{"create", "create"},
+			seedClusters:             1,
+			existingProjectRoles: []runtime.Object{
+				&kubermaticv1.ProjectRole{
+					ObjectMeta: metav1.ObjectMeta{Name: "clusteroperator"},
+					Spec: kubermaticv1.ProjectRoleSpec{
+						Rules: []kubermaticv1.ProjectRoleRule{
+							{Kinds: []string{kubermaticv1.ClusterKindName}, Verbs: []string{"get", "update"}},
+							{Kinds: []string{kubermaticv1.SSHKeyKind}, Verbs: []string{"get", "create"}},
+						},
+					},
+				},
+			},
+			projectResourcesToSync: []projectResource{
+				{
+					gvr: schema.GroupVersionResource{
+						Group:    kubermaticv1.GroupName,
+						Version:  kubermaticv1.GroupVersion,
+						Resource: kubermaticv1.ClusterResourceName,
+					},
+					kind:        kubermaticv1.ClusterKindName,
+					destination: destinationSeed,
+				},
+
+				{
+					gvr: schema.GroupVersionResource{
+						Group:    kubermaticv1.GroupName,
+						Version:  kubermaticv1.GroupVersion,
+						Resource: kubermaticv1.SSHKeyResourceName,
+					},
+					kind: kubermaticv1.SSHKeyKind,
+				},
+			},
+
+			expectedClusterRolesForSeeds: []*rbacv1.ClusterRole{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:            "kubermatic:clusters:owners",
+						ResourceVersion: "1",
+					},
+					Rules: []rbacv1.PolicyRule{
+						{
+							APIGroups: []string{kubermaticv1.SchemeGroupVersion.Group},
+							Resources: []string{"clusters"},
+							Verbs:     []string{"create"},
+						},
+					},
+				},
+
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:            "kubermatic:clusters:editors",
+						ResourceVersion: "1",
+					},
+					Rules: []rbacv1.PolicyRule{
+						{
+							APIGroups: []string{kubermaticv1.SchemeGroupVersion.Group},
+							Resources: []string{"clusters"},
+							Verbs:     []string{"create"},
+						},
+					},
+				},
+			},
+
+			expectedClusterRolesForMaster: []*rbacv1.ClusterRole{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:            "kubermatic:usersshkeies:owners",
+						ResourceVersion: "1",
+					},
+					Rules: []rbacv1.PolicyRule{
+						{
+							APIGroups: []string{kubermaticv1.SchemeGroupVersion.Group},
+							Resources: []string{"usersshkeies"},
+							Verbs:     []string{"create"},
+						},
+					},
+				},
+
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:            "kubermatic:usersshkeies:editors",
+						ResourceVersion: "1",
+					},
+					Rules: []rbacv1.PolicyRule{
+						{
+							APIGroups: []string{kubermaticv1.SchemeGroupVersion.Group},
+							Resources: []string{"usersshkeies"},
+							Verbs:     []string{"create"},
+						},
+					},
+				},
+
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:            "kubermatic:usersshkeies:clusteroperator",
+						ResourceVersion: "1",
+					},
+					Rules: []rbacv1.PolicyRule{
+						{
+							APIGroups: []string{kubermaticv1.SchemeGroupVersion.Group},
+							Resources: []string{"usersshkeies"},
+							Verbs:     []string{"create"},
+						},
+					},
+				},
+			},
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
 			// setup the test scenario
 			objs := []runtime.Object{}
 			fakeKubeClient := fake.NewSimpleClientset(objs...)
-			fakeMasterClient := fakeruntime.NewFakeClient(objs...)
+			fakeMasterClient := fakeruntime.NewFakeClient(append(objs, test.existingProjectRoles...)...)
 			roleIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
 
 			// manually set lister as we don't want to start informers in the tests
@@ -17,6 +17,7 @@ limitations under the License.
 package rbac
 
 import (
+	"context"
 	"fmt"
 
 	kubermaticv1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
@@ -56,32 +57,40 @@ func (c *resourcesController) syncProjectResource(item *resourceToProcess) error
 		return fmt.Errorf("unable to find owing project for the object name = %s, gvr = %s", item.metaObject.GetName(), item.gvr.String())
 	}
 
+	roles, err := listProjectRoles(context.TODO(), c.masterClient)
+	if err != nil {
+		return err
+	}
+
 	if len(item.metaObject.GetNamespace()) == 0 {
-		if err := ensureClusterRBACRoleForNamedResource(projectName, item.gvr.Resource, item.kind, item.metaObject, item.clusterProvider.kubeClient, item.clusterProvider.kubeInformerProvider.KubeInformerFactoryFor(metav1.NamespaceAll).Rbac().V1().ClusterRoles().Lister()); err != nil {
+		if err := ensureClusterRBACRoleForNamedResource(projectName, item.gvr.Resource, item.kind, item.metaObject, item.clusterProvider.kubeClient, item.clusterProvider.kubeInformerProvider.KubeInformerFactoryFor(metav1.NamespaceAll).Rbac().V1().ClusterRoles().Lister(), roles); err != nil {
 			return fmt.Errorf("failed to sync RBAC ClusterRole for %s resource for %s cluster provider, due to = %v", item.gvr.String(), item.clusterProvider.providerName, err)
 		}
-		if err := ensureClusterRBACRoleBindingForNamedResource(projectName, item.gvr.Resource, item.kind, item.metaObject, item.clusterProvider.kubeClient, item.clusterProvider.kubeInformerProvider.KubeInformerFactoryFor(metav1.NamespaceAll).Rbac().V1().ClusterRoleBindings().Lister()); err != nil {
+		if err := ensureClusterRBACRoleBindingForNamedResource(projectName, item.gvr.Resource, item.kind, item.metaObject, item.clusterProvider.kubeClient, item.clusterProvider.kubeInformerProvider.KubeInformerFactoryFor(metav1.NamespaceAll).Rbac().V1().ClusterRoleBindings().Lister(), roles); err != nil {
 			return fmt.Errorf("failed to sync RBAC ClusterRoleBinding for %s resource for %s cluster provider, due to = %v", item.gvr.String(), item.clusterProvider.providerName, err)
 		}
 		if item.kind == kubermaticv1.ClusterKindName {
-			if err := c.ensureRBACRoleForClusterAddons(projectName, item.metaObject, item.clusterProvider); err != nil {
-				return fmt.Errorf("failed to sync RBAC Role for %s resource for %s cluster provider in namespace %s, due to = %v", item.gvr.String(), item.clusterProvider.providerName, item.metaObject.GetNamespace(), err)
-			}
-			if err := c.ensureRBACRoleBindingForClusterAddons(projectName, item.metaObject, item.clusterProvider); err != nil {
-				return fmt.Errorf("failed to sync RBAC RoleBinding for %s resource for %s cluster provider in namespace %s, due to = %v", item.gvr.String(), item.clusterProvider.providerName, item.metaObject.GetNamespace(), err)
+			for _, resource := range clusterNamespaceResources {
+				if err := c.ensureRBACRoleForClusterNamespaceResource(projectName, item.metaObject, item.clusterProvider, resource.name, resource.kind, roles); err != nil {
+					return fmt.Errorf("failed to sync RBAC Role for %s resource for %s cluster provider in namespace %s, due to = %v", item.gvr.String(), item.clusterProvider.providerName, item.metaObject.GetNamespace(), err)
+				}
+				if err := c.ensureRBACRoleBindingForClusterNamespaceResource(projectName, item.metaObject, item.clusterProvider, resource.name, resource.kind, roles); err != nil {
+					return fmt.Errorf("failed to sync RBAC RoleBinding for %s resource for %s cluster provider in namespace %s, due to = %v", item.gvr.String(), item.clusterProvider.providerName, item.metaObject.GetNamespace(), err)
+				}
 			}
 		}
 
 		return nil
 	}
 
-	err := c.ensureRBACRoleForNamedResource(projectName,
+	err = c.ensureRBACRoleForNamedResource(projectName,
 		item.gvr,
 		item.kind,
 		item.metaObject.GetNamespace(),
 		item.metaObject,
 		item.clusterProvider.kubeClient,
-		item.clusterProvider.kubeInformerProvider.KubeInformerFactoryFor(item.metaObject.GetNamespace()).Rbac().V1().Roles().Lister().Roles(item.metaObject.GetNamespace()))
+		item.clusterProvider.kubeInformerProvider.KubeInformerFactoryFor(item.metaObject.GetNamespace()).Rbac().V1().Roles().Lister().Roles(item.metaObject.GetNamespace()),
+		roles)
 	if err != nil {
 		return fmt.Errorf("failed to sync RBAC Role for %s resource for %s cluster provider in namespace %s, due to = %v", item.gvr.String(), item.clusterProvider.providerName, item.metaObject.GetNamespace(), err)
 	}
@@ -92,7 +101,8 @@ func (c *resourcesController) syncProjectResource(item *resourceToProcess) error
 		item.metaObject.GetNamespace(),
 		item.metaObject,
 		item.clusterProvider.kubeClient,
-		item.clusterProvider.kubeInformerProvider.KubeInformerFactoryFor(item.metaObject.GetNamespace()).Rbac().V1().RoleBindings().Lister().RoleBindings(item.metaObject.GetNamespace()))
+		item.clusterProvider.kubeInformerProvider.KubeInformerFactoryFor(item.metaObject.GetNamespace()).Rbac().V1().RoleBindings().Lister().RoleBindings(item.metaObject.GetNamespace()),
+		roles)
 	if err != nil {
 		return fmt.Errorf("failed to sync RBAC RoleBinding for %s resource for %s cluster provider in namespace %s, due to = %v", item.gvr.String(), item.clusterProvider.providerName, item.metaObject.GetNamespace(), err)
 	}
@@ -100,9 +110,9 @@ func (c *resourcesController) syncProjectResource(item *resourceToProcess) error
 	return nil
 }
 
-func ensureClusterRBACRoleForNamedResource(projectName string, objectResource string, objectKind string, object metav1.Object, kubeClient kubernetes.Interface, rbacClusterRoleLister rbaclister.ClusterRoleLister) error {
-	for _, groupPrefix := range AllGroupsPrefixes {
-		skip, generatedRole, err := shouldSkipClusterRBACRoleBindingForNamedResource(projectName, objectResource, objectKind, groupPrefix, object)
+func ensureClusterRBACRoleForNamedResource(projectName string, objectResource string, objectKind string, object metav1.Object, kubeClient kubernetes.Interface, rbacClusterRoleLister rbaclister.ClusterRoleLister, roles projectRoles) error {
+	for _, groupPrefix := range roles.groupPrefixes() {
+		skip, generatedRole, err := shouldSkipClusterRBACRoleBindingForNamedResource(projectName, objectResource, objectKind, groupPrefix, object, roles)
 		if err != nil {
 			return err
 		}
@@ -137,10 +147,10 @@ func ensureClusterRBACRoleForNamedResource(projectName string, objectResource st
 	return nil
 }
 
-func ensureClusterRBACRoleBindingForNamedResource(projectName string, objectResource string, objectKind string, object metav1.Object, kubeClient kubernetes.Interface, rbacClusterRoleBindingLister rbaclister.ClusterRoleBindingLister) error {
-	for _, groupPrefix := range AllGroupsPrefixes {
+func ensureClusterRBACRoleBindingForNamedResource(projectName string, objectResource string, objectKind string, object metav1.Object, kubeClient kubernetes.Interface, rbacClusterRoleBindingLister rbaclister.ClusterRoleBindingLister, roles projectRoles) error {
+	for _, groupPrefix := range roles.groupPrefixes() {
 
-		skip, _, err := shouldSkipClusterRBACRoleBindingForNamedResource(projectName, objectResource, objectKind, groupPrefix, object)
+		skip, _, err := shouldSkipClusterRBACRoleBindingForNamedResource(projectName, objectResource, objectKind, groupPrefix, object, roles)
 		if err != nil {
 			return err
 		}
@@ -188,7 +198,7 @@ func ensureClusterRBACRoleBindingForNamedResource(projectName string, objectReso
 // because for some kinds we actually don't create ClusterRole
 //
 // note that this method returns generated role if is not meant to be skipped
-func shouldSkipClusterRBACRoleBindingForNamedResource(projectName string, objectResource string, objectKind string, groupPrefix string, object metav1.Object) (bool, *rbacv1.ClusterRole, error) {
+func shouldSkipClusterRBACRoleBindingForNamedResource(projectName string, objectResource string, objectKind string, groupPrefix string, object metav1.Object, roles projectRoles) (bool, *rbacv1.ClusterRole, error) {
 	generatedRole, err := generateClusterRBACRoleNamedResource(
 		objectKind,
 		GenerateActualGroupNameFor(projectName, groupPrefix),
@@ -201,6 +211,7 @@ func shouldSkipClusterRBACRoleBindingForNamedResource(projectName string, object
 			UID:        object.GetUID(),
 			Name:       object.GetName(),
 		},
+		roles,
 	)
 
 	if err != nil {
@@ -212,9 +223,9 @@ func shouldSkipClusterRBACRoleBindingForNamedResource(projectName string, object
 	return false, generatedRole, nil
 }
 
-func (c *resourcesController) ensureRBACRoleForNamedResource(projectName string, objectGVR schema.GroupVersionResource, objectKind string, namespace string, object metav1.Object, kubeClient kubernetes.Interface, rbacRoleLister rbaclister.RoleNamespaceLister) error {
-	for _, groupPrefix := range AllGroupsPrefixes {
-		skip, generatedRole, err := shouldSkipRBACRoleBindingForNamedResource(projectName, objectGVR, objectKind, groupPrefix, namespace, object)
+func (c *resourcesController) ensureRBACRoleForNamedResource(projectName string, objectGVR schema.GroupVersionResource, objectKind string, namespace string, object metav1.Object, kubeClient kubernetes.Interface, rbacRoleLister rbaclister.RoleNamespaceLister, roles projectRoles) error {
+	for _, groupPrefix := range roles.groupPrefixes() {
+		skip, generatedRole, err := shouldSkipRBACRoleBindingForNamedResource(projectName, objectGVR, objectKind, groupPrefix, namespace, object, roles)
 		if err != nil {
 			return err
 		}
@@ -249,10 +260,10 @@ func (c *resourcesController) ensureRBACRoleForNamedResource(projectName string,
 	return nil
 }
 
-func (c *resourcesController) ensureRBACRoleBindingForNamedResource(projectName string, objectGVR schema.GroupVersionResource, objectKind string, namespace string, object metav1.Object, kubeClient kubernetes.Interface, rbacRoleBindingLister rbaclister.RoleBindingNamespaceLister) error {
-	for _, groupPrefix := range AllGroupsPrefixes {
+func (c *resourcesController) ensureRBACRoleBindingForNamedResource(projectName string, objectGVR schema.GroupVersionResource, objectKind string, namespace string, object metav1.Object, kubeClient kubernetes.Interface, rbacRoleBindingLister rbaclister.RoleBindingNamespaceLister, roles projectRoles) error {
+	for _, groupPrefix := range roles.groupPrefixes() {
 
-		skip, _, err := shouldSkipRBACRoleBindingForNamedResource(projectName, objectGVR, objectKind, groupPrefix, namespace, object)
+		skip, _, err := shouldSkipRBACRoleBindingForNamedResource(projectName, objectGVR, objectKind, groupPrefix, namespace, object, roles)
 		if err != nil {
 			return err
 		}
@@ -301,7 +312,7 @@ func (c *resourcesController) ensureRBACRoleBindingForNamedResource(projectName
 // because for some kinds we actually don't create Role
 //
 // note that this method returns generated role if is not meant to be skipped
-func shouldSkipRBACRoleBindingForNamedResource(projectName string, objectGVR schema.GroupVersionResource, objectKind string, groupPrefix string, namespace string, object metav1.Object) (bool, *rbacv1.Role, error) {
+func shouldSkipRBACRoleBindingForNamedResource(projectName string, objectGVR schema.GroupVersionResource, objectKind string, groupPrefix string, namespace string, object metav1.Object, roles projectRoles) (bool, *rbacv1.Role, error) {
 	generatedRole, err := generateRBACRoleNamedResource(
 		objectKind,
 		GenerateActualGroupNameFor(projectName, groupPrefix),
@@ -315,6 +326,7 @@ func shouldSkipRBACRoleBindingForNamedResource(projectName string, objectGVR sch
 			UID:        object.GetUID(),
 			Name:       object.GetName(),
 		},
+		roles,
 	)
 
 	if err != nil {
@@ -326,27 +338,38 @@ func shouldSkipRBACRoleBindingForNamedResource(projectName string, objectGVR sch
 	return false, generatedRole, nil
 }
 
-func (c *resourcesController) ensureRBACRoleForClusterAddons(projectName string, object metav1.Object, clusterProvider *ClusterProvider) error {
+// clusterNamespaceResources are the project resources that live in the cluster namespace
+// and need per-cluster Roles and RoleBindings
+var clusterNamespaceResources = []struct {
+	name string
+	kind string
+}{
+	{name: kubermaticv1.AddonResourceName, kind: kubermaticv1.AddonKindName},
+	{name: kubermaticv1.EtcdRestoreResourceName, kind: kubermaticv1.EtcdRestoreKindName},
+}
+
+func (c *resourcesController) ensureRBACRoleForClusterNamespaceResource(projectName string, object metav1.Object, clusterProvider *ClusterProvider, policyResource, kind string, roles projectRoles) error {
 	cluster, ok := object.(*kubermaticv1.Cluster)
 	if !ok {
-		return fmt.Errorf("ensureRBACRoleForClusterAddons called with non-cluster: %+v", object)
+		return fmt.Errorf("ensureRBACRoleForClusterNamespaceResource called with non-cluster: %+v", object)
 	}
 
 	rbacRoleLister := clusterProvider.kubeClient.RbacV1().Roles(cluster.Status.NamespaceName)
 
-	for _, groupPrefix := range AllGroupsPrefixes {
+	for _, groupPrefix := range roles.groupPrefixes() {
 		skip, generatedRole, err := shouldSkipRBACRoleForClusterNamespaceResource(
 			projectName,
 			cluster,
-			kubermaticv1.AddonResourceName,
+			policyResource,
 			kubermaticv1.GroupName,
-			kubermaticv1.AddonKindName,
-			groupPrefix)
+			kind,
+			groupPrefix,
+			roles)
 		if err != nil {
 			return err
 		}
 		if skip {
-			klog.V(4).Infof("skipping Role generation for cluster addons for group %q and cluster namespace %q", groupPrefix, cluster.Status.NamespaceName)
+			klog.V(4).Infof("skipping Role generation for %s for group %q and cluster namespace %q", policyResource, groupPrefix, cluster.Status.NamespaceName)
 			continue
 		}
 		sharedExistingRole, err := rbacRoleLister.Get(generatedRole.Name, metav1.GetOptions{})
@@ -376,34 +399,35 @@ func (c *resourcesController) ensureRBACRoleForClusterAddons(projectName string,
 	return nil
 }
 
-func (c *resourcesController) ensureRBACRoleBindingForClusterAddons(projectName string, object metav1.Object, clusterProvider *ClusterProvider) error {
+func (c *resourcesController) ensureRBACRoleBindingForClusterNamespaceResource(projectName string, object metav1.Object, clusterProvider *ClusterProvider, policyResource, kind string, roles projectRoles) error {
 	cluster, ok := object.(*kubermaticv1.Cluster)
 	if !ok {
-		return fmt.Errorf("ensureRBACRoleBindingForClusterAddons called with non-cluster: %+v", object)
+		return fmt.Errorf("ensureRBACRoleBindingForClusterNamespaceResource called with non-cluster: %+v", object)
 	}
 
 	rbacRoleBindingLister := clusterProvider.kubeClient.RbacV1().RoleBindings(cluster.Status.NamespaceName)
 
-	for _, groupPrefix := range AllGroupsPrefixes {
+	for _, groupPrefix := range roles.groupPrefixes() {
 		skip, _, err := shouldSkipRBACRoleForClusterNamespaceResource(
 			projectName,
 			cluster,
-			kubermaticv1.AddonResourceName,
+			policyResource,
 			kubermaticv1.GroupName,
-			kubermaticv1.AddonKindName,
-			groupPrefix)
+			kind,
+			groupPrefix,
+			roles)
 		if err != nil {
 			return err
 		}
 		if skip {
-			klog.V(4).Infof("skipping RoleBinding generation for cluster addons for group %q and cluster namespace %q", groupPrefix, cluster.Status.NamespaceName)
+			klog.V(4).Infof("skipping RoleBinding generation for %s for group %q and cluster namespace %q", policyResource, groupPrefix, cluster.Status.NamespaceName)
 			continue
 		}
 
 		generatedRoleBinding := generateRBACRoleBindingForClusterNamespaceResource(
 			cluster,
 			GenerateActualGroupNameFor(projectName, groupPrefix),
-			kubermaticv1.AddonKindName,
+			kind,
 		)
 
 		sharedExistingRoleBinding, err := rbacRoleBindingLister.Get(generatedRoleBinding.Name, metav1.GetOptions{})
@@ -435,13 +459,14 @@ func (c *resourcesController) ensureRBACRoleBindingForClusterAddons(projectName
 // because for some groupPrefixes we actually don't create Role
 //
 // note that this method returns generated role if is not meant to be skipped
-func shouldSkipRBACRoleForClusterNamespaceResource(projectName string, cluster *kubermaticv1.Cluster, policyResource, policyAPIGroups, kind, groupPrefix string) (bool, *rbacv1.Role, error) {
+func shouldSkipRBACRoleForClusterNamespaceResource(projectName string, cluster *kubermaticv1.Cluster, policyResource, policyAPIGroups, kind, groupPrefix string, roles projectRoles) (bool, *rbacv1.Role, error) {
 	generatedRole, err := generateRBACRoleForClusterNamespaceResource(
 		cluster,
 		GenerateActualGroupNameFor(projectName, groupPrefix),
 		policyResource,
 		policyAPIGroups,
 		kind,
+		roles,
 	)
 
 	if err != nil {
@@ -351,6 +351,11 @@ func DefaultConfiguration(config *operatorv1alpha1.KubermaticConfiguration, logg
 		logger.Debugw("Defaulting field", "field", "seedController.backupCleanupContainer")
 	}
 
+	if copy.Spec.SeedController.BackupRestoreContainer == "" {
+		copy.Spec.SeedController.BackupRestoreContainer = strings.TrimSpace(DefaultBackupRestoreContainer)
+		logger.Debugw("Defaulting field", "field", "seedController.backupRestoreContainer")
+	}
+
 	if copy.Spec.SeedController.Replicas == nil {
 		copy.Spec.SeedController.Replicas = pointer.Int32Ptr(DefaultSeedControllerMgrReplicas)
 		logger.Debugw("Defaulting field", "field", "seedController.replicas", "value", *copy.Spec.SeedController.Replicas)
@@ -654,18 +659,18 @@ func defaultVersioning(settings *operatorv1alpha1.KubermaticVersioningConfigurat
 
 const DefaultBackupStoreContainer = `
 name: store-container
-image: quay.io/kubermatic/s3-storer:v0.1.4
+image: quay.io/kubermatic/s3-storer:v0.1.5
 command:
 - /bin/sh
 - -c
 - |
   set -euo pipefail
 
-  endpoint=minio.minio.svc.cluster.local:9000
-  bucket=kubermatic-etcd-backups
+  endpoint=${BACKUP_ENDPOINT:-minio.minio.svc.cluster.local:9000}
+  bucket=${BACKUP_BUCKET:-kubermatic-etcd-backups}
 
   s3-storeuploader store --file /backup/snapshot.db --endpoint "$endpoint" --bucket "$bucket" --create-bucket --prefix $CLUSTER
-  s3-storeuploader delete-old-revisions --max-revisions 20 --endpoint "$endpoint" --bucket "$bucket" --prefix $CLUSTER
+  s3-storeuploader delete-old-revisions --max-revisions "${BACKUP_KEEP:-20}" --endpoint "$endpoint" --bucket "$bucket" --prefix $CLUSTER
 env:
 - name: ACCESS_KEY_ID
   valueFrom:
@@ -684,15 +689,15 @@ volumeMounts:
 
 const DefaultBackupCleanupContainer = `
 name: cleanup-container
-image: quay.io/kubermatic/s3-storer:v0.1.4
+image: quay.io/kubermatic/s3-storer:v0.1.5
 command:
 - /bin/sh
 - -c
 - |
   set -euo pipefail
 
-  endpoint=minio.minio.svc.cluster.local:9000
-  bucket=kubermatic-etcd-backups
+  endpoint=${BACKUP_ENDPOINT:-minio.minio.svc.cluster.local:9000}
+  bucket=${BACKUP_BUCKET:-kubermatic-etcd-backups}
 
   # by default, we keep the most recent backup for every user cluster
   s3-storeuploader delete-old-revisions --max-revisions 1 --endpoint "$endpoint" --bucket "$bucket" --prefix $CLUSTER
@@ -712,6 +717,35 @@ env:
       key: SECRET_ACCESS_KEY
 `
 
+const DefaultBackupRestoreContainer = `
+name: restore-container
+image: quay.io/kubermatic/s3-storer:v0.1.5
+command:
+- /bin/sh
+- -c
+- |
+  set -euo pipefail
+
+  endpoint=${BACKUP_ENDPOINT:-minio.minio.svc.cluster.local:9000}
+  bucket=${BACKUP_BUCKET:-kubermatic-etcd-backups}
+
+  s3-storeuploader download --file /backup/snapshot.db --endpoint "$endpoint" --bucket "$bucket" --object "$BACKUP_NAME"
+env:
+- name: ACCESS_KEY_ID
+  valueFrom:
+    secretKeyRef:
+      name: s3-credentials
+      key: ACCESS_KEY_ID
+- name: SECRET_ACCESS_KEY
+  valueFrom:
+    secretKeyRef:
+      name: s3-credentials
+      key: SECRET_ACCESS_KEY
+volumeMounts:
+- name: etcd-backup
+  mountPath: /backup
+`
+
 const DefaultUIConfig = `
 {
   "share_kubeconfig": false
@@ -21,6 +21,8 @@ import (
 	"fmt"
 	"strings"
 
+	"go.uber.org/zap"
+
 	"github.com/kubermatic/kubermatic/api/pkg/controller/util/predicate"
 	kubermaticv1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
 	operatorv1alpha1 "github.com/kubermatic/kubermatic/api/pkg/crd/operator/v1alpha1"
@@ -35,6 +37,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/selection"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
@@ -228,3 +231,20 @@ func ProxyEnvironmentVars(cfg *operatorv1alpha1.KubermaticConfiguration) []corev
 
 	return result
 }
+
+// ReportDryRun publishes the changes collected during a dry-run as an event on the given object
+// and, if a report directory is configured, as a report file.
+func ReportDryRun(log *zap.SugaredLogger, recorder record.EventRecorder, obj runtime.Object, report *reconciling.DryRunReport, reportDir, reportName string) error {
+	summary := report.Summary()
+	log.Infow("Dry-run finished", "changes", summary)
+	if len(report.Changes()) > 0 {
+		recorder.Eventf(obj, corev1.EventTypeNormal, "DryRun", "Reconciling would make the following changes: %s", summary)
+	}
+
+	if reportDir != "" {
+		if err := report.WriteFile(reportDir, reportName); err != nil {
+			return fmt.Errorf("failed to write the dry-run report: %v", err)
+		}
+	}
+	return nil
+}
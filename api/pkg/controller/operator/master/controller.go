@@ -56,6 +56,8 @@ func Add(
 	namespace string,
 	numWorkers int,
 	workerName string,
+	dryRun bool,
+	dryRunReportDir string,
 ) error {
 	reconciler := &Reconciler{
 		Client:     mgr.GetClient(),
@@ -65,6 +67,9 @@ func Add(
 		workerName: workerName,
 		ctx:        ctx,
 		versions:   common.NewDefaultVersions(),
+
+		dryRun:          dryRun,
+		dryRunReportDir: dryRunReportDir,
 	}
 
 	ctrlOptions := controller.Options{Reconciler: reconciler, MaxConcurrentReconciles: numWorkers}
@@ -50,6 +50,10 @@ type Reconciler struct {
 	workerName string
 	ctx        context.Context
 	versions   common.Versions
+
+	// dryRun makes the controller only report the changes it would make
+	dryRun          bool
+	dryRunReportDir string
 }
 
 // Reconcile acts upon requests and will restore the state of resources
@@ -79,7 +83,11 @@ func (r *Reconciler) Reconcile(request reconcile.Request) (reconcile.Result, err
 		return reconcile.Result{}, fmt.Errorf("failed to apply defaults: %v", err)
 	}
 
-	err = r.reconcile(defaulted, logger)
+	if r.dryRun {
+		err = r.reconcileDryRun(config, defaulted, logger)
+	} else {
+		err = r.reconcile(defaulted, logger)
+	}
 	if err != nil {
 		r.recorder.Event(config, corev1.EventTypeWarning, "ReconcilingError", err.Error())
 	}
@@ -87,6 +95,20 @@ func (r *Reconciler) Reconcile(request reconcile.Request) (reconcile.Result, err
 	return reconcile.Result{}, err
 }
 
+// reconcileDryRun runs the reconciliation against the live objects without writing anything
+// and reports the changes it would make
+func (r *Reconciler) reconcileDryRun(config, defaulted *operatorv1alpha1.KubermaticConfiguration, logger *zap.SugaredLogger) error {
+	report := reconciling.NewDryRunReport()
+	dryRunReconciler := *r
+	dryRunReconciler.Client = reconciling.NewDryRunClient(r.Client, report)
+
+	if err := dryRunReconciler.reconcile(defaulted, logger); err != nil {
+		return err
+	}
+
+	return common.ReportDryRun(logger, r.recorder, config, report, r.dryRunReportDir, "kubermaticconfiguration-"+config.Name)
+}
+
 func (r *Reconciler) reconcile(config *operatorv1alpha1.KubermaticConfiguration, logger *zap.SugaredLogger) error {
 	logger.Debug("Reconciling Kubermatic configuration")
 
@@ -62,6 +62,8 @@ func Add(
 	seedsGetter provider.SeedsGetter,
 	numWorkers int,
 	workerName string,
+	dryRun bool,
+	dryRunReportDir string,
 ) error {
 	namespacePredicate := predicateutil.ByNamespace(namespace)
 
@@ -77,6 +79,9 @@ func Add(
 		seedsGetter:    seedsGetter,
 		workerName:     workerName,
 		versions:       common.NewDefaultVersions(),
+
+		dryRun:          dryRun,
+		dryRunReportDir: dryRunReportDir,
 	}
 
 	ctrlOpts := controller.Options{
@@ -62,6 +62,10 @@ type Reconciler struct {
 	seedsGetter    provider.SeedsGetter
 	workerName     string
 	versions       common.Versions
+
+	// dryRun makes the controller only report the changes it would make
+	dryRun          bool
+	dryRunReportDir string
 }
 
 // Reconcile acts upon requests and will restore the state of resources
@@ -153,6 +157,10 @@ func (r *Reconciler) reconcile(log *zap.SugaredLogger, seedName string) error {
 		return fmt.Errorf("failed to get Seed in seed cluster: %v", err)
 	}
 
+	if r.dryRun {
+		return r.reconcileDryRun(defaulted, seed, seedCopy, seedClient, log)
+	}
+
 	// Seed CR inside the seed cluster was deleted
 	if seedCopy.DeletionTimestamp != nil {
 		return r.cleanupDeletedSeed(defaulted, seedCopy, seedClient, log)
@@ -169,6 +177,23 @@ func (r *Reconciler) reconcile(log *zap.SugaredLogger, seedName string) error {
 	return nil
 }
 
+// reconcileDryRun runs the reconciliation of the seed against the live objects without writing anything
+// and reports the changes it would make on the Seed in the master cluster
+func (r *Reconciler) reconcileDryRun(cfg *operatorv1alpha1.KubermaticConfiguration, seed, seedCopy *kubermaticv1.Seed, client ctrlruntimeclient.Client, log *zap.SugaredLogger) error {
+	report := reconciling.NewDryRunReport()
+	dryRunClient := reconciling.NewDryRunClient(client, report)
+
+	if seedCopy.DeletionTimestamp != nil {
+		if err := r.cleanupDeletedSeed(cfg, seedCopy.DeepCopy(), dryRunClient, log); err != nil {
+			return err
+		}
+	} else if err := r.reconcileResources(cfg, seedCopy.DeepCopy(), dryRunClient, log); err != nil {
+		return err
+	}
+
+	return common.ReportDryRun(log, r.masterRecorder, seed, report, r.dryRunReportDir, "seed-"+seed.Name)
+}
+
 func (r *Reconciler) cleanupDeletedSeed(cfg *operatorv1alpha1.KubermaticConfiguration, seed *kubermaticv1.Seed, client ctrlruntimeclient.Client, log *zap.SugaredLogger) error {
 	if !kubernetes.HasAnyFinalizer(seed, common.CleanupFinalizer) {
 		return nil
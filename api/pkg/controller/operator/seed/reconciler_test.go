@@ -20,6 +20,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -236,6 +240,64 @@ func TestBasicReconciling(t *testing.T) {
 			},
 		},
 
+		{
+			name:            "dry-run reports the changes without writing them",
+			seedToReconcile: "europe",
+			configuration: &operatorv1alpha1.KubermaticConfiguration{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test",
+					Namespace: "kubermatic",
+				},
+				Spec: operatorv1alpha1.KubermaticConfigurationSpec{
+					Ingress: operatorv1alpha1.KubermaticIngressConfiguration{
+						Domain: "example.com",
+					},
+				},
+			},
+			seedsOnMaster: []string{"europe"},
+			syncedSeeds:   sets.NewString("europe"),
+			assertion: func(test *testcase, reconciler *Reconciler) error {
+				reportDir, err := ioutil.TempDir("", "dry-run-report")
+				must(t, err)
+				defer os.RemoveAll(reportDir)
+
+				reconciler.dryRun = true
+				reconciler.dryRunReportDir = reportDir
+				if err := reconciler.reconcile(reconciler.log, test.seedToReconcile); err != nil {
+					return fmt.Errorf("reconciliation failed: %v", err)
+				}
+
+				seedClient := reconciler.seedClients["europe"]
+
+				seed := kubermaticv1.Seed{}
+				must(t, seedClient.Get(reconciler.ctx, types.NamespacedName{Namespace: "kubermatic", Name: "europe"}, &seed))
+				if kubernetes.HasFinalizer(&seed, common.CleanupFinalizer) {
+					return errors.New("the dry-run must not add the cleanup finalizer")
+				}
+
+				crbs := rbacv1.ClusterRoleBindingList{}
+				must(t, seedClient.List(reconciler.ctx, &crbs))
+				if length := len(crbs.Items); length > 0 {
+					return fmt.Errorf("the dry-run must not create ClusterRoleBindings, but %d have been created", length)
+				}
+
+				select {
+				case event := <-reconciler.masterRecorder.(*record.FakeRecorder).Events:
+					if !strings.Contains(event, "DryRun") || !strings.Contains(event, "create:") {
+						return fmt.Errorf("unexpected event %q", event)
+					}
+				default:
+					return errors.New("expected an event with the changes on the Seed")
+				}
+
+				if _, err := os.Stat(filepath.Join(reportDir, "seed-europe.json")); err != nil {
+					return fmt.Errorf("expected a report file: %v", err)
+				}
+
+				return nil
+			},
+		},
+
 		{
 			name:            "seeds in other namespaces are ignored",
 			seedToReconcile: "other",
@@ -32,6 +32,7 @@ const (
 	backupContainersConfigMapName = "backup-containers"
 	storeContainerKey             = "store-container.yaml"
 	cleanupContainerKey           = "cleanup-container.yaml"
+	restoreContainerKey           = "restore-container.yaml"
 )
 
 func ClusterRoleBindingName(cfg *operatorv1alpha1.KubermaticConfiguration) string {
@@ -79,6 +80,7 @@ func BackupContainersConfigMapCreator(cfg *operatorv1alpha1.KubermaticConfigurat
 
 			c.Data[storeContainerKey] = cfg.Spec.SeedController.BackupStoreContainer
 			c.Data[cleanupContainerKey] = cfg.Spec.SeedController.BackupCleanupContainer
+			c.Data[restoreContainerKey] = cfg.Spec.SeedController.BackupRestoreContainer
 
 			return c, nil
 		}
@@ -68,9 +68,11 @@ func SeedControllerManagerDeploymentCreator(workerName string, versions common.V
 				"-internal-address=0.0.0.0:8085",
 				"-kubernetes-addons-path=/opt/addons/kubernetes",
 				"-openshift-addons-path=/opt/addons/openshift",
+				"-addon-source-cache-path=/opt/addon-source-cache",
 				"-worker-count=4",
 				fmt.Sprintf("-backup-container=/opt/backup/%s", storeContainerKey),
 				fmt.Sprintf("-cleanup-container=/opt/backup/%s", cleanupContainerKey),
+				fmt.Sprintf("-restore-container=/opt/backup/%s", restoreContainerKey),
 				fmt.Sprintf("-docker-pull-config-json-file=/opt/docker/%s", corev1.DockerConfigJsonKey),
 				fmt.Sprintf("-seed-admissionwebhook-cert-file=/opt/seed-webhook-serving-cert/%s", resources.ServingCertSecretKey),
 				fmt.Sprintf("-seed-admissionwebhook-key-file=/opt/seed-webhook-serving-cert/%s", resources.ServingCertKeySecretKey),
@@ -112,6 +114,12 @@ func SeedControllerManagerDeploymentCreator(workerName string, versions common.V
 						EmptyDir: &corev1.EmptyDirVolumeSource{},
 					},
 				},
+				{
+					Name: "addon-source-cache",
+					VolumeSource: corev1.VolumeSource{
+						EmptyDir: &corev1.EmptyDirVolumeSource{},
+					},
+				},
 				{
 					Name: "backup-container",
 					VolumeSource: corev1.VolumeSource{
@@ -146,6 +154,10 @@ func SeedControllerManagerDeploymentCreator(workerName string, versions common.V
 					MountPath: "/opt/addons/",
 					ReadOnly:  true,
 				},
+				{
+					Name:      "addon-source-cache",
+					MountPath: "/opt/addon-source-cache/",
+				},
 				{
 					Name:      "backup-container",
 					MountPath: "/opt/backup/",
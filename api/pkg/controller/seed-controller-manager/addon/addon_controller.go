@@ -17,19 +17,13 @@ limitations under the License.
 package addon
 
 import (
-	"bytes"
 	"context"
 	"fmt"
-	"io/ioutil"
-	"os"
-	"os/exec"
 	"path"
 	"reflect"
 	"strings"
 	"time"
 
-	"github.com/ghodss/yaml"
-
 	"go.uber.org/zap"
 
 	addonutils "github.com/kubermatic/kubermatic/api/pkg/addon"
@@ -37,6 +31,7 @@ import (
 	kubermaticv1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
 	kubermaticv1helper "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1/helper"
 	kuberneteshelper "github.com/kubermatic/kubermatic/api/pkg/kubernetes"
+	"github.com/kubermatic/kubermatic/api/pkg/provider"
 	"github.com/kubermatic/kubermatic/api/pkg/resources"
 	"github.com/kubermatic/kubermatic/api/pkg/resources/machinecontroller"
 
@@ -45,7 +40,6 @@ import (
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	metav1unstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
-	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/json"
@@ -66,6 +60,11 @@ const (
 	addonLabelKey        = "kubermatic-addon"
 	cleanupFinalizerName = "cleanup-manifests"
 	addonEnsureLabelKey  = "addons.kubermatic.io/ensure"
+
+	// fieldOwner is the field manager of the applied addon objects
+	fieldOwner = "kubermatic-addon-controller"
+	// applyFailedReason is the reason of the AddonResourcesCreated condition when objects couldn't be applied
+	applyFailedReason = "ApplyFailed"
 )
 
 // KubeconfigProvider provides functionality to get a clusters admin kubeconfig
@@ -83,6 +82,9 @@ type Reconciler struct {
 	kubernetesAddonDir   string
 	openshiftAddonDir    string
 	overwriteRegistry    string
+	seedGetter           provider.SeedGetter
+	// sourceCache holds the manifests of the addons whose source is pinned in the seed
+	sourceCache *addonutils.SourceCache
 	ctrlruntimeclient.Client
 	recorder                 record.EventRecorder
 	KubeconfigProvider       KubeconfigProvider
@@ -100,9 +102,11 @@ func Add(
 	addonCtxVariables map[string]interface{},
 	kubernetesAddonDir,
 	openshiftAddonDir,
+	addonSourceCacheDir,
 	overwriteRegistey string,
 	nodeLocalDNSCacheEnabled bool,
 	kubeconfigProvider KubeconfigProvider,
+	seedGetter provider.SeedGetter,
 ) error {
 	log = log.Named(ControllerName)
 	client := mgr.GetClient()
@@ -119,6 +123,8 @@ func Add(
 		recorder:                 mgr.GetEventRecorderFor(ControllerName),
 		overwriteRegistry:        overwriteRegistey,
 		nodeLocalDNSCacheEnabled: nodeLocalDNSCacheEnabled,
+		seedGetter:               seedGetter,
+		sourceCache:              addonutils.NewSourceCache(addonSourceCacheDir),
 	}
 
 	ctrlOptions := controller.Options{
@@ -164,7 +170,26 @@ func Add(
 		return err
 	}
 
-	return c.Watch(&source.Kind{Type: &kubermaticv1.Addon{}}, &handler.EnqueueRequestForObject{})
+	if err := c.Watch(&source.Kind{Type: &kubermaticv1.Addon{}}, &handler.EnqueueRequestForObject{}); err != nil {
+		return err
+	}
+
+	// The addons waiting for their dependencies get installed as soon as the dependencies are installed
+	enqueueDependentAddons := &handler.EnqueueRequestsFromMapFunc{ToRequests: handler.ToRequestsFunc(func(a handler.MapObject) []reconcile.Request {
+		addonList := &kubermaticv1.AddonList{}
+		if err := client.List(context.Background(), addonList, ctrlruntimeclient.InNamespace(a.Meta.GetNamespace())); err != nil {
+			log.Errorw("Failed to get addons depending on addon", zap.Error(err), "addon", a.Meta.GetName())
+			return nil
+		}
+		var requests []reconcile.Request
+		for _, addon := range dependentAddons(addonList.Items, a.Meta.GetName()) {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: addon.Namespace, Name: addon.Name},
+			})
+		}
+		return requests
+	})}
+	return c.Watch(&source.Kind{Type: &kubermaticv1.Addon{}}, enqueueDependentAddons)
 }
 
 func (r *Reconciler) Reconcile(request reconcile.Request) (reconcile.Result, error) {
@@ -261,24 +286,37 @@ func (r *Reconciler) reconcile(ctx context.Context, log *zap.SugaredLogger, addo
 		}
 		return nil, nil
 	}
-	// This is true when the addon: 1) is fully deployed, 2) doesn't have a `addonEnsureLabelKey` set to true.
+	// This is true when the addon: 1) is fully deployed in its current version, 2) doesn't have a `addonEnsureLabelKey` set to true.
 	// we do this to allow users to "edit/delete" resources deployed by unlabeled addons,
-	// while we enfornce the labeled ones
-	if addonResourcesCreated(addon) && !hasEnsureResourcesLabel(addon) {
-		return nil, nil
+	// while we enfornce the labeled ones. The edits are reported as drift in the status of the addon.
+	if addonResourcesCreated(addon) && addon.Status.InstalledVersion == addon.Spec.Version && !hasEnsureResourcesLabel(addon) {
+		return r.ensureHealthIsReported(ctx, log, addon, cluster)
+	}
+
+	// The addons this addon depends on are installed first
+	reqeueAfter, err = r.ensureDependenciesAreInstalled(ctx, log, addon)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check the dependencies of the addon: %v", err)
+	}
+	if reqeueAfter != nil {
+		return reqeueAfter, nil
 	}
 
 	// Reconciling
-	if err := r.ensureIsInstalled(ctx, log, addon, cluster); err != nil {
+	reqeueAfter, err = r.ensureIsInstalled(ctx, log, addon, cluster)
+	if err != nil {
 		return nil, fmt.Errorf("failed to deploy the addon manifests into the cluster: %v", err)
 	}
+	if reqeueAfter != nil {
+		return reqeueAfter, nil
+	}
 	if err := r.ensureFinalizerIsSet(ctx, addon); err != nil {
 		return nil, fmt.Errorf("failed to ensure that the cleanup finalizer existis on the addon: %v", err)
 	}
 	if err := r.ensureResourcesCreatedConditionIsSet(ctx, addon); err != nil {
 		return nil, fmt.Errorf("failed to set add ResourcesCreated Condition: %v", err)
 	}
-	return nil, nil
+	return r.ensureHealthIsReported(ctx, log, addon, cluster)
 }
 
 func (r *Reconciler) removeCleanupFinalizer(ctx context.Context, log *zap.SugaredLogger, addon *kubermaticv1.Addon) error {
@@ -293,7 +331,7 @@ func (r *Reconciler) removeCleanupFinalizer(ctx context.Context, log *zap.Sugare
 	return nil
 }
 
-func (r *Reconciler) getAddonManifests(log *zap.SugaredLogger, addon *kubermaticv1.Addon, cluster *kubermaticv1.Cluster) ([]runtime.RawExtension, error) {
+func (r *Reconciler) getAddonManifests(ctx context.Context, log *zap.SugaredLogger, addon *kubermaticv1.Addon, cluster *kubermaticv1.Cluster) ([]runtime.RawExtension, error) {
 	addonDir := r.kubernetesAddonDir
 	if cluster.IsOpenshift() {
 		addonDir = r.openshiftAddonDir
@@ -342,7 +380,10 @@ func (r *Reconciler) getAddonManifests(log *zap.SugaredLogger, addon *kubermatic
 		return nil, fmt.Errorf("failed to create template data for addon manifests: %v", err)
 	}
 
-	manifestPath := path.Join(addonDir, addon.Spec.Name)
+	manifestPath, err := r.getManifestPath(ctx, addon, addonDir)
+	if err != nil {
+		return nil, err
+	}
 	allManifests, err := addonutils.ParseFromFolder(log, r.overwriteRegistry, manifestPath, data)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse addon templates in %s: %v", manifestPath, err)
@@ -351,23 +392,47 @@ func (r *Reconciler) getAddonManifests(log *zap.SugaredLogger, addon *kubermatic
 	return allManifests, nil
 }
 
-// combineManifests returns all manifests combined into a multi document yaml
-func (r *Reconciler) combineManifests(manifests []*bytes.Buffer) *bytes.Buffer {
-	parts := make([]string, len(manifests))
-	for i, m := range manifests {
-		s := m.String()
-		s = strings.TrimSuffix(s, "\n")
-		s = strings.TrimSpace(s)
-		parts[i] = s
+// getManifestPath returns the folder with the manifests of the addon. The manifests of addons whose source is pinned
+// in the seed get pulled into the source cache, the others are read from the addon folder.
+func (r *Reconciler) getManifestPath(ctx context.Context, addon *kubermaticv1.Addon, addonDir string) (string, error) {
+	seed, err := r.seedGetter()
+	if err != nil {
+		return "", fmt.Errorf("failed to get the seed: %v", err)
+	}
+	sourceSpec, ok := seed.Spec.AddonSources[addon.Spec.Name]
+	if !ok {
+		return path.Join(addonDir, addon.Spec.Name), nil
+	}
+
+	source, err := addonutils.NewSource(sourceSpec)
+	if err != nil {
+		return "", fmt.Errorf("invalid source of addon %s: %v", addon.Spec.Name, err)
+	}
+	manifestPath, err := r.sourceCache.Get(ctx, source, sourceSpec.Checksum)
+	if err != nil {
+		return "", fmt.Errorf("failed to get the manifests of addon %s: %v", addon.Spec.Name, err)
+	}
+	return manifestPath, nil
+}
+
+// getAddonObjects returns the objects of the addon manifests with the addonLabelKey label added,
+// which is used to find the objects to prune
+func (r *Reconciler) getAddonObjects(ctx context.Context, log *zap.SugaredLogger, addon *kubermaticv1.Addon, cluster *kubermaticv1.Cluster) ([]*metav1unstructured.Unstructured, error) {
+	manifests, err := r.getAddonManifests(ctx, log, addon, cluster)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get addon manifests: %v", err)
 	}
 
-	return bytes.NewBufferString(strings.Join(parts, "\n---\n") + "\n")
+	objects, err := r.ensureAddonLabelOnManifests(addon, manifests)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add the addon specific label to all addon resources: %v", err)
+	}
+	return objects, nil
 }
 
-// ensureAddonLabelOnManifests adds the addonLabelKey label to all manifests.
-// For this to happen we need to decode all yaml files to json, parse them, add the label and finally encode to yaml again
-func (r *Reconciler) ensureAddonLabelOnManifests(addon *kubermaticv1.Addon, manifests []runtime.RawExtension) ([]*bytes.Buffer, error) {
-	var rawManifests []*bytes.Buffer
+// ensureAddonLabelOnManifests decodes the manifests and adds the addonLabelKey label to all of them
+func (r *Reconciler) ensureAddonLabelOnManifests(addon *kubermaticv1.Addon, manifests []runtime.RawExtension) ([]*metav1unstructured.Unstructured, error) {
+	var objects []*metav1unstructured.Unstructured
 
 	wantLabels := r.getAddonLabel(addon)
 	for _, m := range manifests {
@@ -387,22 +452,10 @@ func (r *Reconciler) ensureAddonLabelOnManifests(addon *kubermaticv1.Addon, mani
 		}
 		parsedUnstructuredObj.SetLabels(existingLabels)
 
-		jsonBuffer := &bytes.Buffer{}
-		if err := metav1unstructured.UnstructuredJSONScheme.Encode(parsedUnstructuredObj, jsonBuffer); err != nil {
-			return nil, fmt.Errorf("encoding json failed: %v", err)
-		}
-
-		// Must be encoding back to yaml, otherwise kubectl fails to apply because it tries to parse the whole
-		// thing as json
-		yamlBytes, err := yaml.JSONToYAML(jsonBuffer.Bytes())
-		if err != nil {
-			return nil, err
-		}
-
-		rawManifests = append(rawManifests, bytes.NewBuffer(yamlBytes))
+		objects = append(objects, parsedUnstructuredObj)
 	}
 
-	return rawManifests, nil
+	return objects, nil
 }
 
 func (r *Reconciler) getAddonLabel(addon *kubermaticv1.Addon) map[string]string {
@@ -411,106 +464,60 @@ func (r *Reconciler) getAddonLabel(addon *kubermaticv1.Addon) map[string]string
 	}
 }
 
-type fileHandlingDone func()
-
-func getFileDeleteFinalizer(log *zap.SugaredLogger, filename string) fileHandlingDone {
-	return func() {
-		if err := os.RemoveAll(filename); err != nil {
-			log.Errorw("Failed to delete file", zap.Error(err), "file", filename)
-		}
-	}
-}
-
-func (r *Reconciler) writeCombinedManifest(log *zap.SugaredLogger, manifest *bytes.Buffer, addon *kubermaticv1.Addon, cluster *kubermaticv1.Cluster) (string, fileHandlingDone, error) {
-	//Write combined Manifest to disk
-	manifestFilename := path.Join("/tmp", fmt.Sprintf("cluster-%s-%s.yaml", cluster.Name, addon.Name))
-	if err := ioutil.WriteFile(manifestFilename, manifest.Bytes(), 0644); err != nil {
-		return "", nil, fmt.Errorf("failed to write combined manifest to %s: %v", manifestFilename, err)
-	}
-	log.Debugw("Wrote combined manifest", "file", manifestFilename)
-
-	return manifestFilename, getFileDeleteFinalizer(log, manifestFilename), nil
-}
-
-func (r *Reconciler) writeAdminKubeconfig(log *zap.SugaredLogger, addon *kubermaticv1.Addon, cluster *kubermaticv1.Cluster) (string, fileHandlingDone, error) {
-	// Write kubeconfig to disk
-	kubeconfig, err := r.KubeconfigProvider.GetAdminKubeconfig(cluster)
+func (r *Reconciler) ensureIsInstalled(ctx context.Context, log *zap.SugaredLogger, addon *kubermaticv1.Addon, cluster *kubermaticv1.Cluster) (*reconcile.Result, error) {
+	objects, err := r.getAddonObjects(ctx, log, addon, cluster)
 	if err != nil {
-		return "", nil, fmt.Errorf("failed to get admin kubeconfig for cluster %s: %v", cluster.Name, err)
-	}
-	kubeconfigFilename := path.Join("/tmp", fmt.Sprintf("cluster-%s-addon-%s-kubeconfig", cluster.Name, addon.Name))
-	if err := ioutil.WriteFile(kubeconfigFilename, kubeconfig, 0644); err != nil {
-		return "", nil, fmt.Errorf("failed to write admin kubeconfig for cluster %s: %v", cluster.Name, err)
+		return nil, err
 	}
-	log.Debugw("Wrote admin kubeconfig", "file", kubeconfigFilename)
-
-	return kubeconfigFilename, getFileDeleteFinalizer(log, kubeconfigFilename), nil
-}
-
-func (r *Reconciler) setupManifestInteraction(log *zap.SugaredLogger, addon *kubermaticv1.Addon, cluster *kubermaticv1.Cluster) (string, string, fileHandlingDone, error) {
-	manifests, err := r.getAddonManifests(log, addon, cluster)
+	objects, hooks, err := splitHooks(objects)
 	if err != nil {
-		return "", "", nil, fmt.Errorf("failed to get addon manifests: %v", err)
+		return nil, err
 	}
-
-	rawManifests, err := r.ensureAddonLabelOnManifests(addon, manifests)
-	if err != nil {
-		return "", "", nil, fmt.Errorf("failed to add the addon specific label to all addon resources: %v", err)
+	if len(objects) == 0 {
+		log.Debug("Skipping addon installation as the manifest is empty after parsing")
+		return nil, nil
 	}
 
-	rawManifest := r.combineManifests(rawManifests)
-	manifestFilename, manifestDone, err := r.writeCombinedManifest(log, rawManifest, addon, cluster)
+	userClusterClient, err := r.KubeconfigProvider.GetClient(cluster)
 	if err != nil {
-		return "", "", nil, fmt.Errorf("failed to write all addon resources into a combined manifest file: %v", err)
+		return nil, fmt.Errorf("failed to get client for usercluster: %v", err)
 	}
 
-	kubeconfigFilename, kubeconfigDone, err := r.writeAdminKubeconfig(log, addon, cluster)
-	if err != nil {
-		return "", "", nil, fmt.Errorf("failed to write the admin kubeconfig to the local filesystem: %v", err)
+	upgrading := isUpgrading(addon)
+	if upgrading {
+		if result, err := r.ensureHooksCompleted(ctx, log, userClusterClient, addon, preUpgradeHook, hooks[preUpgradeHook]); result != nil || err != nil {
+			return result, err
+		}
 	}
 
-	done := func() {
-		kubeconfigDone()
-		manifestDone()
+	// We delete all resources with this label which are not in the manifests
+	log.Debugw("Applying manifests...", "objects", len(objects))
+	objectErrs := addonutils.NewApplier(userClusterClient, fieldOwner).Apply(ctx, objects, r.getAddonLabel(addon))
+	if len(objectErrs) > 0 {
+		message := objectErrorsMessage(objectErrs)
+		if err := r.setResourcesCreatedConditionFailed(ctx, addon, applyFailedReason, message); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to apply the manifests of addon %s of cluster %s: %s", addon.Name, cluster.Name, message)
 	}
-	return kubeconfigFilename, manifestFilename, done, nil
-}
-
-func (r *Reconciler) getApplyCommand(ctx context.Context, kubeconfigFilename, manifestFilename string, selector fmt.Stringer, openshift bool) *exec.Cmd {
-	// kubectl apply --prune -f manifest.yaml -l app=nginx
-	cmd := exec.CommandContext(ctx, "kubectl", "--kubeconfig", kubeconfigFilename, "apply", "--prune", "-f", manifestFilename, "-l", selector.String())
-	return cmd
-}
 
-func (r *Reconciler) ensureIsInstalled(ctx context.Context, log *zap.SugaredLogger, addon *kubermaticv1.Addon, cluster *kubermaticv1.Cluster) error {
-	kubeconfigFilename, manifestFilename, done, err := r.setupManifestInteraction(log, addon, cluster)
-	if err != nil {
-		return err
+	if upgrading {
+		return r.ensureHooksCompleted(ctx, log, userClusterClient, addon, postUpgradeHook, hooks[postUpgradeHook])
 	}
-	defer done()
+	return nil, nil
+}
 
-	d, err := ioutil.ReadFile(manifestFilename)
-	if err != nil {
-		return err
-	}
-	sd := strings.TrimSpace(string(d))
-	if len(sd) == 0 {
-		log.Debug("Skipping addon installation as the manifest is empty after parsing")
+// setResourcesCreatedConditionFailed sets the AddonResourcesCreated condition to false with the given reason and message
+func (r *Reconciler) setResourcesCreatedConditionFailed(ctx context.Context, addon *kubermaticv1.Addon, reason, message string) error {
+	if _, cond := getAddonCondition(addon, kubermaticv1.AddonResourcesCreated); cond != nil && cond.Status == corev1.ConditionFalse && cond.Reason == reason && cond.Message == message {
 		return nil
 	}
-
-	// We delete all resources with this label which are not in the combined manifest
-	selector := labels.SelectorFromSet(r.getAddonLabel(addon))
-	cmd := r.getApplyCommand(ctx, kubeconfigFilename, manifestFilename, selector, cluster.IsOpenshift())
-	cmdLog := log.With("cmd", strings.Join(cmd.Args, " "))
-
-	cmdLog.Debug("Applying manifest...")
-	out, err := cmd.CombinedOutput()
-	cmdLog.Debugw("Finished executing command", "output", string(out))
-	if err != nil {
-		return fmt.Errorf("failed to execute '%s' for addon %s of cluster %s: %v\n%s", strings.Join(cmd.Args, " "), addon.Name, cluster.Name, err, string(out))
+	oldAddon := addon.DeepCopy()
+	setAddonCodition(addon, kubermaticv1.AddonResourcesCreated, corev1.ConditionFalse, reason, message)
+	if err := r.Client.Patch(ctx, addon, ctrlruntimeclient.MergeFrom(oldAddon)); err != nil {
+		return fmt.Errorf("failed to set the %s condition: %v", kubermaticv1.AddonResourcesCreated, err)
 	}
-	return err
+	return nil
 }
 
 func (r *Reconciler) ensureFinalizerIsSet(ctx context.Context, addon *kubermaticv1.Addon) error {
@@ -523,18 +530,19 @@ func (r *Reconciler) ensureFinalizerIsSet(ctx context.Context, addon *kubermatic
 	return r.Client.Patch(ctx, addon, ctrlruntimeclient.MergeFrom(oldAddon))
 }
 
+// ensureResourcesCreatedConditionIsSet sets the AddonResourcesCreated condition and records the installed version
 func (r *Reconciler) ensureResourcesCreatedConditionIsSet(ctx context.Context, addon *kubermaticv1.Addon) error {
-	_, cond := getAddonCondition(addon, kubermaticv1.AddonResourcesCreated)
-	if cond != nil && cond.Status == corev1.ConditionTrue {
+	if addonResourcesCreated(addon) && addon.Status.InstalledVersion == addon.Spec.Version {
 		return nil
 	}
 	oldAddon := addon.DeepCopy()
-	setAddonCodition(addon, kubermaticv1.AddonResourcesCreated, corev1.ConditionTrue)
+	setAddonCodition(addon, kubermaticv1.AddonResourcesCreated, corev1.ConditionTrue, "", "")
+	addon.Status.InstalledVersion = addon.Spec.Version
 	return r.Client.Patch(ctx, addon, ctrlruntimeclient.MergeFrom(oldAddon))
 }
 
 func (r *Reconciler) cleanupManifests(ctx context.Context, log *zap.SugaredLogger, addon *kubermaticv1.Addon, cluster *kubermaticv1.Cluster) error {
-	kubeconfigFilename, manifestFilename, done, err := r.setupManifestInteraction(log, addon, cluster)
+	objects, err := r.getAddonObjects(ctx, log, addon, cluster)
 	if err != nil {
 		// FIXME: use a dedicated error type and proper error unwrapping when we have the technology to do it
 		if strings.Contains(err.Error(), "no such file or directory") { // if the manifest is already deleted, that's ok
@@ -543,16 +551,16 @@ func (r *Reconciler) cleanupManifests(ctx context.Context, log *zap.SugaredLogge
 		}
 		return err
 	}
-	defer done()
-
-	cmd := deleteCommand(ctx, kubeconfigFilename, manifestFilename)
-	cmdLog := log.With("cmd", strings.Join(cmd.Args, " "))
 
-	cmdLog.Debug("Deleting resources...")
-	out, err := cmd.CombinedOutput()
-	cmdLog.Debugw("Finished executing command", "output", string(out))
+	userClusterClient, err := r.KubeconfigProvider.GetClient(cluster)
 	if err != nil {
-		return fmt.Errorf("failed to execute '%s' for addon %s of cluster %s: %v\n%s", strings.Join(cmd.Args, " "), addon.Name, cluster.Name, err, string(out))
+		return fmt.Errorf("failed to get client for usercluster: %v", err)
+	}
+
+	// the hook Jobs are part of the objects and get deleted as well
+	log.Debugw("Deleting resources...", "objects", len(objects))
+	if objectErrs := addonutils.NewApplier(userClusterClient, fieldOwner).Delete(ctx, objects); len(objectErrs) > 0 {
+		return fmt.Errorf("failed to delete the manifests of addon %s of cluster %s: %s", addon.Name, cluster.Name, objectErrorsMessage(objectErrs))
 	}
 	return nil
 }
@@ -589,16 +597,23 @@ func (r *Reconciler) ensureRequiredResourceTypesExist(ctx context.Context, log *
 	return nil, nil
 }
 
-func deleteCommand(ctx context.Context, kubeconfigFilename, manifestFilename string) *exec.Cmd {
-	return exec.CommandContext(ctx, "kubectl", "--kubeconfig", kubeconfigFilename, "delete", "-f", manifestFilename, "--ignore-not-found")
+// objectErrorsMessage joins the errors of the objects into a message for the addon condition
+func objectErrorsMessage(objectErrs []addonutils.ObjectError) string {
+	messages := make([]string, len(objectErrs))
+	for i, err := range objectErrs {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "; ")
 }
 
-func setAddonCodition(a *kubermaticv1.Addon, condType kubermaticv1.AddonConditionType, status corev1.ConditionStatus) {
+func setAddonCodition(a *kubermaticv1.Addon, condType kubermaticv1.AddonConditionType, status corev1.ConditionStatus, reason, message string) {
 	idx, cond := getAddonCondition(a, condType)
 	if cond == nil {
 		cond = &kubermaticv1.AddonCondition{}
 		cond.Type = condType
 		cond.Status = status
+		cond.Reason = reason
+		cond.Message = message
 		cond.LastHeartbeatTime = metav1.Now()
 		cond.LastTransitionTime = metav1.Now()
 		a.Status.Conditions = append(a.Status.Conditions, *cond)
@@ -608,6 +623,8 @@ func setAddonCodition(a *kubermaticv1.Addon, condType kubermaticv1.AddonConditio
 		cond.LastTransitionTime = metav1.Now()
 		cond.Status = status
 	}
+	cond.Reason = reason
+	cond.Message = message
 	cond.LastHeartbeatTime = metav1.Now()
 	a.Status.Conditions[idx] = *cond
 }
@@ -23,21 +23,30 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"os/exec"
 	"path"
 	"strings"
 	"testing"
 
+	"github.com/ghodss/yaml"
+
+	addonutils "github.com/kubermatic/kubermatic/api/pkg/addon"
 	clusterclient "github.com/kubermatic/kubermatic/api/pkg/cluster/client"
 	kubermaticv1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
 	kubermaticlog "github.com/kubermatic/kubermatic/api/pkg/log"
+	"github.com/kubermatic/kubermatic/api/pkg/provider"
 	"github.com/kubermatic/kubermatic/api/pkg/resources"
 	"github.com/kubermatic/kubermatic/api/pkg/semver"
 
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	kyaml "k8s.io/apimachinery/pkg/util/yaml"
+	utilpointer "k8s.io/utils/pointer"
 	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlruntimefakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 var testManifests = []string{
@@ -116,34 +125,34 @@ spec:
 `
 )
 
-var (
-	// testManifest1 & testManifest3 have a linebreak at the end, testManifest2 not
-	combinedTestManifest = fmt.Sprintf("%s---\n%s\n---\n%s", testManifests[0], testManifests[1], testManifests[2])
-)
+func testSeedGetter(seed *kubermaticv1.Seed) provider.SeedGetter {
+	return func() (*kubermaticv1.Seed, error) {
+		return seed, nil
+	}
+}
 
-type fakeKubeconfigProvider struct{}
+type fakeKubeconfigProvider struct {
+	client ctrlruntimeclient.Client
+}
 
 func (f *fakeKubeconfigProvider) GetAdminKubeconfig(c *kubermaticv1.Cluster) ([]byte, error) {
 	return []byte("foo"), nil
 }
 
 func (f *fakeKubeconfigProvider) GetClient(c *kubermaticv1.Cluster, options ...clusterclient.ConfigOption) (ctrlruntimeclient.Client, error) {
-	return nil, errors.New("not implemented")
-}
-
-func TestController_combineManifests(t *testing.T) {
-	controller := &Reconciler{}
-
-	var manifests []*bytes.Buffer
-	for _, m := range testManifests {
-		manifests = append(manifests, bytes.NewBufferString(m))
+	if f.client == nil {
+		return nil, errors.New("not implemented")
 	}
+	return f.client, nil
+}
 
-	manifest := controller.combineManifests(manifests)
+// rejectingClient is a user cluster client whose API server rejects all writes
+type rejectingClient struct {
+	ctrlruntimeclient.Client
+}
 
-	if manifest.String() != combinedTestManifest {
-		t.Fatalf("invalid combined manifest returned. Expected \n%s, Got \n%s", combinedTestManifest, manifest.String())
-	}
+func (c *rejectingClient) Patch(ctx context.Context, obj runtime.Object, patch ctrlruntimeclient.Patch, opts ...ctrlruntimeclient.PatchOption) error {
+	return errors.New("admission webhook denied the request")
 }
 
 func setupTestCluster(cidrBlock string) *kubermaticv1.Cluster {
@@ -209,9 +218,10 @@ func TestController_getAddonKubeDNStManifests(t *testing.T) {
 
 	controller := &Reconciler{
 		kubernetesAddonDir: addonDir,
+		seedGetter:         testSeedGetter(&kubermaticv1.Seed{}),
 		KubeconfigProvider: &fakeKubeconfigProvider{},
 	}
-	manifests, err := controller.getAddonManifests(log, addon, cluster)
+	manifests, err := controller.getAddonManifests(context.Background(), log, addon, cluster)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -229,7 +239,7 @@ func TestController_getAddonKubeDNStManifests(t *testing.T) {
 	}
 
 	cluster = setupTestCluster("172.25.0.0/16")
-	manifests, err = controller.getAddonManifests(log, addon, cluster)
+	manifests, err = controller.getAddonManifests(context.Background(), log, addon, cluster)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -263,10 +273,11 @@ func TestController_getAddonDeploymentManifests(t *testing.T) {
 
 	controller := &Reconciler{
 		kubernetesAddonDir: addonDir,
+		seedGetter:         testSeedGetter(&kubermaticv1.Seed{}),
 		overwriteRegistry:  "bar.io",
 		KubeconfigProvider: &fakeKubeconfigProvider{},
 	}
-	manifests, err := controller.getAddonManifests(log, addon, cluster)
+	manifests, err := controller.getAddonManifests(context.Background(), log, addon, cluster)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -302,9 +313,10 @@ func TestController_getAddonDeploymentManifestsDefault(t *testing.T) {
 
 	controller := &Reconciler{
 		kubernetesAddonDir: addonDir,
+		seedGetter:         testSeedGetter(&kubermaticv1.Seed{}),
 		KubeconfigProvider: &fakeKubeconfigProvider{},
 	}
-	manifests, err := controller.getAddonManifests(log, addon, cluster)
+	manifests, err := controller.getAddonManifests(context.Background(), log, addon, cluster)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -346,9 +358,10 @@ func TestController_getAddonManifests(t *testing.T) {
 
 	controller := &Reconciler{
 		kubernetesAddonDir: addonDir,
+		seedGetter:         testSeedGetter(&kubermaticv1.Seed{}),
 		KubeconfigProvider: &fakeKubeconfigProvider{},
 	}
-	manifests, err := controller.getAddonManifests(log, addon, cluster)
+	manifests, err := controller.getAddonManifests(context.Background(), log, addon, cluster)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -394,18 +407,97 @@ func TestController_ensureAddonLabelOnManifests(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	if labeledManifests[0].String() != testManifest1WithLabel {
-		t.Fatalf("invalid labeled manifest returned. Expected \n%q, Got \n%q", testManifest1WithLabel, labeledManifests[0].String())
+	jsonManifest, err := labeledManifests[0].MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	yamlManifest, err := yaml.JSONToYAML(jsonManifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(yamlManifest) != testManifest1WithLabel {
+		t.Fatalf("invalid labeled manifest returned. Expected \n%q, Got \n%q", testManifest1WithLabel, string(yamlManifest))
 	}
 }
 
-func TestController_getApplyCommand(t *testing.T) {
-	controller := &Reconciler{}
-	cmd := controller.getApplyCommand(context.Background(), "/opt/kubeconfig", "/opt/manifest.yaml", labels.SelectorFromSet(map[string]string{"foo": "bar"}), false)
-	expected := "kubectl --kubeconfig /opt/kubeconfig apply --prune -f /opt/manifest.yaml -l foo=bar"
-	got := strings.Join(cmd.Args, " ")
-	if got != expected {
-		t.Fatalf("invalid apply command returned. Expected \n%s, Got \n%s", expected, got)
+func TestController_getAddonManifestsFromSource(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	cluster := setupTestCluster("10.240.16.0/20")
+	addon := setupTestAddon("test")
+	dir, err := ioutil.TempDir("/tmp", "kubermatic-tests-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// the manifests of the addon folder must not be used for addons with a source
+	addonDir := path.Join(dir, "addons")
+	if err := os.MkdirAll(path.Join(addonDir, addon.Spec.Name), 0777); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(path.Join(addonDir, addon.Spec.Name, "testManifest.yaml"), []byte(testManifests[1]), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	repo := path.Join(dir, "repo")
+	if err := os.Mkdir(repo, 0777); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(path.Join(repo, "testManifest.yaml"), []byte(testManifests[0]), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for _, args := range [][]string{
+		{"init", "--quiet"},
+		{"add", "."},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "--quiet", "-m", "test"},
+		{"tag", "v1"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repo
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("failed to run git %v: %v: %s", args, err, out)
+		}
+	}
+	checksum, err := addonutils.BundleChecksum(repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	seed := &kubermaticv1.Seed{
+		Spec: kubermaticv1.SeedSpec{
+			AddonSources: map[string]kubermaticv1.AddonSource{
+				addon.Spec.Name: {
+					Git:      &kubermaticv1.GitAddonSource{URL: repo, Ref: "v1"},
+					Checksum: checksum,
+				},
+			},
+		},
+	}
+	controller := &Reconciler{
+		kubernetesAddonDir: addonDir,
+		seedGetter:         testSeedGetter(seed),
+		sourceCache:        addonutils.NewSourceCache(path.Join(dir, "cache")),
+		KubeconfigProvider: &fakeKubeconfigProvider{},
+	}
+	log := kubermaticlog.New(true, kubermaticlog.FormatConsole).Sugar()
+
+	manifests, err := controller.getAddonManifests(context.Background(), log, addon, cluster)
+	if err != nil {
+		t.Fatalf("failed to get the manifests: %v", err)
+	}
+	if len(manifests) != 1 || !strings.Contains(string(manifests[0].Raw), `"name":"test1"`) {
+		t.Fatalf("expected the manifest of the source, got %v", manifests)
+	}
+
+	seed.Spec.AddonSources[addon.Spec.Name] = kubermaticv1.AddonSource{
+		Git:      &kubermaticv1.GitAddonSource{URL: repo, Ref: "v1"},
+		Checksum: "sha256:" + strings.Repeat("0", 64),
+	}
+	if _, err := controller.getAddonManifests(context.Background(), log, addon, cluster); err == nil || !strings.Contains(err.Error(), "doesn't match") {
+		t.Fatalf("expected a checksum mismatch, got %v", err)
 	}
 }
 
@@ -415,9 +507,152 @@ func TestHugeManifest(t *testing.T) {
 	addon := setupTestAddon("istio")
 	r := &Reconciler{
 		kubernetesAddonDir: "./testdata",
+		seedGetter:         testSeedGetter(&kubermaticv1.Seed{}),
 		KubeconfigProvider: &fakeKubeconfigProvider{},
 	}
-	if _, _, _, err := r.setupManifestInteraction(log, addon, cluster); err != nil {
-		t.Fatalf("failed to setup manifest interaction: %v", err)
+	if _, err := r.getAddonObjects(context.Background(), log, addon, cluster); err != nil {
+		t.Fatalf("failed to get the addon objects: %v", err)
+	}
+}
+
+func TestController_ensureIsInstalledReportsObjectErrors(t *testing.T) {
+	log := kubermaticlog.New(true, kubermaticlog.FormatConsole).Sugar()
+	cluster := setupTestCluster("10.240.16.0/20")
+	addon := setupTestAddon("test")
+	addon.Namespace = "cluster-test-cluster"
+
+	addonDir, err := ioutil.TempDir("/tmp", "kubermatic-tests-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(addonDir)
+
+	if err := os.Mkdir(path.Join(addonDir, addon.Spec.Name), 0777); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(path.Join(addonDir, addon.Spec.Name, "testManifest.yaml"), []byte(testManifests[0]), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	seedClient := ctrlruntimefakeclient.NewFakeClient(addon.DeepCopy())
+	controller := &Reconciler{
+		kubernetesAddonDir: addonDir,
+		seedGetter:         testSeedGetter(&kubermaticv1.Seed{}),
+		Client:             seedClient,
+		KubeconfigProvider: &fakeKubeconfigProvider{client: &rejectingClient{Client: ctrlruntimefakeclient.NewFakeClient()}},
+	}
+
+	if _, err := controller.ensureIsInstalled(context.Background(), log, addon, cluster); err == nil {
+		t.Fatal("expected an error as the objects couldn't be applied")
+	}
+
+	updatedAddon := &kubermaticv1.Addon{}
+	if err := seedClient.Get(context.Background(), types.NamespacedName{Namespace: addon.Namespace, Name: addon.Name}, updatedAddon); err != nil {
+		t.Fatal(err)
+	}
+	_, cond := getAddonCondition(updatedAddon, kubermaticv1.AddonResourcesCreated)
+	if cond == nil {
+		t.Fatal("expected the ResourcesCreated condition to be set")
+	}
+	expectedMessage := "ConfigMap kube-system/test1: admission webhook denied the request"
+	if cond.Status != corev1.ConditionFalse || cond.Reason != applyFailedReason || cond.Message != expectedMessage {
+		t.Fatalf("unexpected condition %+v, expected it to be false with the message %q", cond, expectedMessage)
+	}
+}
+
+func TestController_ensureHealthIsReported(t *testing.T) {
+	log := kubermaticlog.New(true, kubermaticlog.FormatConsole).Sugar()
+	cluster := setupTestCluster("10.240.16.0/20")
+	addon := setupTestAddon("test")
+	addon.Namespace = "cluster-test-cluster"
+
+	addonDir, err := ioutil.TempDir("/tmp", "kubermatic-tests-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(addonDir)
+
+	if err := os.Mkdir(path.Join(addonDir, addon.Spec.Name), 0777); err != nil {
+		t.Fatal(err)
+	}
+	deploymentManifest := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: dashboard
+  namespace: kube-system
+spec:
+  replicas: 1
+`
+	if err := ioutil.WriteFile(path.Join(addonDir, addon.Spec.Name, "configmap.yaml"), []byte(testManifests[0]), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(path.Join(addonDir, addon.Spec.Name, "deployment.yaml"), []byte(deploymentManifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	labels := map[string]string{"app": "test", addonLabelKey: addon.Spec.Name}
+	userClusterClient := ctrlruntimefakeclient.NewFakeClient(
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "kube-system", Name: "test1", Labels: labels},
+			Data:       map[string]string{"foo": "edited"},
+		},
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "kube-system", Name: "dashboard", Labels: map[string]string{addonLabelKey: addon.Spec.Name}},
+			Spec:       appsv1.DeploymentSpec{Replicas: utilpointer.Int32Ptr(1)},
+		},
+	)
+	seedClient := ctrlruntimefakeclient.NewFakeClient(addon.DeepCopy())
+	controller := &Reconciler{
+		kubernetesAddonDir: addonDir,
+		seedGetter:         testSeedGetter(&kubermaticv1.Seed{}),
+		Client:             seedClient,
+		KubeconfigProvider: &fakeKubeconfigProvider{client: userClusterClient},
+	}
+	expectConditions := func(ready, drifted corev1.ConditionStatus, readyMessage, driftedMessage string) {
+		updatedAddon := &kubermaticv1.Addon{}
+		if err := seedClient.Get(context.Background(), types.NamespacedName{Namespace: addon.Namespace, Name: addon.Name}, updatedAddon); err != nil {
+			t.Fatal(err)
+		}
+		if len(updatedAddon.Status.Objects) != 2 {
+			t.Fatalf("expected the status of both objects, got %+v", updatedAddon.Status.Objects)
+		}
+		if _, cond := getAddonCondition(updatedAddon, kubermaticv1.AddonReady); cond == nil || cond.Status != ready || cond.Message != readyMessage {
+			t.Fatalf("expected the AddonReady condition to be %s with the message %q, got %+v", ready, readyMessage, cond)
+		}
+		if _, cond := getAddonCondition(updatedAddon, kubermaticv1.AddonDrifted); cond == nil || cond.Status != drifted || cond.Message != driftedMessage {
+			t.Fatalf("expected the AddonDrifted condition to be %s with the message %q, got %+v", drifted, driftedMessage, cond)
+		}
+	}
+
+	result, err := controller.ensureHealthIsReported(context.Background(), log, addon, cluster)
+	if err != nil {
+		t.Fatalf("failed to report the health: %v", err)
+	}
+	if result == nil || result.RequeueAfter >= healthyRecheckInterval {
+		t.Fatalf("expected to check the health again soon while the Deployment isn't available, got %v", result)
+	}
+	expectConditions(corev1.ConditionFalse, corev1.ConditionTrue, "Deployment kube-system/dashboard: 0 of 1 replicas are available", "ConfigMap kube-system/test1: data.foo")
+
+	deployment := &appsv1.Deployment{}
+	if err := userClusterClient.Get(context.Background(), types.NamespacedName{Namespace: "kube-system", Name: "dashboard"}, deployment); err != nil {
+		t.Fatal(err)
+	}
+	deployment.Status.AvailableReplicas = 1
+	deployment.Status.Conditions = []appsv1.DeploymentCondition{{Type: appsv1.DeploymentAvailable, Status: corev1.ConditionTrue}}
+	if err := userClusterClient.Update(context.Background(), deployment); err != nil {
+		t.Fatal(err)
+	}
+	configMap := &corev1.ConfigMap{}
+	if err := userClusterClient.Get(context.Background(), types.NamespacedName{Namespace: "kube-system", Name: "test1"}, configMap); err != nil {
+		t.Fatal(err)
+	}
+	configMap.Data["foo"] = "bar"
+	if err := userClusterClient.Update(context.Background(), configMap); err != nil {
+		t.Fatal(err)
+	}
+
+	if result, err = controller.ensureHealthIsReported(context.Background(), log, addon, cluster); err != nil || result == nil || result.RequeueAfter != healthyRecheckInterval {
+		t.Fatalf("expected the healthy addon to be checked again in %v, got result %v and error %v", healthyRecheckInterval, result, err)
 	}
+	expectConditions(corev1.ConditionTrue, corev1.ConditionFalse, "", "")
 }
@@ -0,0 +1,116 @@
+/*
+Copyright 2020 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addon
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	kubermaticv1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
+
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// ensureDependenciesAreInstalled returns a result to requeue with as long as one of the addons the addon depends on
+// isn't installed in its current version, so the addons of a cluster get installed in the order of their dependencies.
+// A dependency cycle is returned as error as it would never resolve.
+func (r *Reconciler) ensureDependenciesAreInstalled(ctx context.Context, log *zap.SugaredLogger, addon *kubermaticv1.Addon) (*reconcile.Result, error) {
+	if len(addon.Spec.Dependencies) == 0 {
+		return nil, nil
+	}
+
+	addonList := &kubermaticv1.AddonList{}
+	if err := r.List(ctx, addonList, ctrlruntimeclient.InNamespace(addon.Namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list the addons of the cluster: %v", err)
+	}
+	addons := map[string]*kubermaticv1.Addon{}
+	for i := range addonList.Items {
+		addons[addonList.Items[i].Name] = &addonList.Items[i]
+	}
+	addons[addon.Name] = addon
+
+	if cycle := dependencyCycle(addons, addon.Name); cycle != nil {
+		return nil, fmt.Errorf("the dependencies of the addon form a cycle: %s", strings.Join(cycle, " -> "))
+	}
+
+	for _, name := range addon.Spec.Dependencies {
+		dependency, ok := addons[name]
+		if !ok {
+			log.Infow("Dependency doesn't exist, trying again in 10 seconds", "dependency", name)
+			return &reconcile.Result{RequeueAfter: 10 * time.Second}, nil
+		}
+		if !addonInstalled(dependency) {
+			log.Debugw("Dependency isn't installed yet, trying again in 10 seconds", "dependency", name)
+			return &reconcile.Result{RequeueAfter: 10 * time.Second}, nil
+		}
+	}
+	return nil, nil
+}
+
+// dependencyCycle returns the path of a dependency cycle the named addon is part of or nil if there is none
+func dependencyCycle(addons map[string]*kubermaticv1.Addon, name string) []string {
+	visited := map[string]bool{}
+
+	var visit func(path []string) []string
+	visit = func(path []string) []string {
+		current, ok := addons[path[len(path)-1]]
+		if !ok {
+			return nil
+		}
+		for _, dependency := range current.Spec.Dependencies {
+			// copy the path, so the paths of sibling dependencies don't share a backing array
+			next := append(append(make([]string, 0, len(path)+1), path...), dependency)
+			if dependency == name {
+				return next
+			}
+			if visited[dependency] {
+				continue
+			}
+			visited[dependency] = true
+			if cycle := visit(next); cycle != nil {
+				return cycle
+			}
+		}
+		return nil
+	}
+
+	return visit([]string{name})
+}
+
+// dependentAddons returns the addons that depend on the named addon
+func dependentAddons(addons []kubermaticv1.Addon, name string) []kubermaticv1.Addon {
+	var dependents []kubermaticv1.Addon
+	for _, addon := range addons {
+		for _, dependency := range addon.Spec.Dependencies {
+			if dependency == name {
+				dependents = append(dependents, addon)
+				break
+			}
+		}
+	}
+	return dependents
+}
+
+// addonInstalled returns whether the manifests of the current version of the addon have been applied
+func addonInstalled(addon *kubermaticv1.Addon) bool {
+	return addon.DeletionTimestamp == nil && addonResourcesCreated(addon) && addon.Status.InstalledVersion == addon.Spec.Version
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2020 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addon
+
+import (
+	"context"
+	"testing"
+
+	kubermaticv1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
+	kubermaticlog "github.com/kubermatic/kubermatic/api/pkg/log"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrlruntimefakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func genDependentAddon(name, version, installedVersion string, dependencies ...string) *kubermaticv1.Addon {
+	addon := setupTestAddon(name)
+	addon.Namespace = "cluster-test-cluster"
+	addon.Spec.Version = version
+	addon.Spec.Dependencies = dependencies
+	if installedVersion != "" {
+		setAddonCodition(addon, kubermaticv1.AddonResourcesCreated, corev1.ConditionTrue, "", "")
+		addon.Status.InstalledVersion = installedVersion
+	}
+	return addon
+}
+
+func TestEnsureDependenciesAreInstalled(t *testing.T) {
+	testCases := []struct {
+		name            string
+		addon           *kubermaticv1.Addon
+		otherAddons     []runtime.Object
+		expectedRequeue bool
+		expectedErr     string
+	}{
+		{
+			name:  "addon without dependencies",
+			addon: genDependentAddon("ingress", "", ""),
+		},
+		{
+			name:        "installed dependency",
+			addon:       genDependentAddon("ingress", "", "", "csi"),
+			otherAddons: []runtime.Object{genDependentAddon("csi", "v1", "v1")},
+		},
+		{
+			name:            "dependency that hasn't been installed yet",
+			addon:           genDependentAddon("ingress", "", "", "csi"),
+			otherAddons:     []runtime.Object{genDependentAddon("csi", "v1", "")},
+			expectedRequeue: true,
+		},
+		{
+			name:            "dependency that is being upgraded",
+			addon:           genDependentAddon("ingress", "", "", "csi"),
+			otherAddons:     []runtime.Object{genDependentAddon("csi", "v2", "v1")},
+			expectedRequeue: true,
+		},
+		{
+			name:            "dependency that doesn't exist",
+			addon:           genDependentAddon("ingress", "", "", "csi"),
+			expectedRequeue: true,
+		},
+		{
+			name:  "dependency cycle",
+			addon: genDependentAddon("ingress", "", "", "csi"),
+			otherAddons: []runtime.Object{
+				genDependentAddon("csi", "v1", "v1", "storage"),
+				genDependentAddon("storage", "v1", "v1", "ingress"),
+			},
+			expectedErr: "the dependencies of the addon form a cycle: ingress -> csi -> storage -> ingress",
+		},
+		{
+			name:  "dependency cycle behind a sibling dependency",
+			addon: genDependentAddon("ingress", "", "", "csi"),
+			otherAddons: []runtime.Object{
+				genDependentAddon("csi", "v1", "v1", "monitoring", "storage"),
+				genDependentAddon("monitoring", "v1", "v1", "logging", "metrics"),
+				genDependentAddon("logging", "v1", "v1"),
+				genDependentAddon("metrics", "v1", "v1"),
+				genDependentAddon("storage", "v1", "v1", "ingress"),
+			},
+			expectedErr: "the dependencies of the addon form a cycle: ingress -> csi -> storage -> ingress",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := &Reconciler{
+				Client: ctrlruntimefakeclient.NewFakeClient(append(tc.otherAddons, tc.addon)...),
+			}
+			log := kubermaticlog.New(true, kubermaticlog.FormatConsole).Sugar()
+
+			result, err := r.ensureDependenciesAreInstalled(context.Background(), log, tc.addon)
+			if tc.expectedErr != "" {
+				if err == nil || err.Error() != tc.expectedErr {
+					t.Fatalf("expected the error %q, got %v", tc.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to check the dependencies: %v", err)
+			}
+			if requeue := result != nil; requeue != tc.expectedRequeue {
+				t.Fatalf("expected requeue to be %t, got %t", tc.expectedRequeue, requeue)
+			}
+		})
+	}
+}
@@ -17,7 +17,21 @@ limitations under the License.
 /*
 Package addon contains a controller that applies addons based on a Addon CRD. It needs
 a folder per addon that contains all manifests, then adds a label to all objects and applies
-the addon via `kubectl apply --purge -l $added-label`, which result in all objects that
-do have the label but are not in the on-disk manifests being removed.
+the objects to the user cluster using server-side apply. Afterwards all objects that do have the
+label but are not in the on-disk manifests are removed, like `kubectl apply --prune -l $added-label`
+does it. Objects that couldn't be applied are reported in the AddonResourcesCreated condition.
+
+Instead of the addon folder, seeds can pin the source of an addon to a versioned bundle in an OCI
+registry or a Git repository. The bundle is pulled into an on-disk cache once and verified against
+the checksum of the source.
+
+An addon is only installed once all addons listed in its dependencies are installed. When the version
+of an installed addon changes, the Jobs in its manifests with the `addons.kubermatic.io/hook` annotation
+set to `pre-upgrade` or `post-upgrade` are run in the user cluster before and after the manifests are
+applied. The installed version is recorded in the status of the addon.
+
+On every reconciliation the applied objects are compared with the rendered manifests. Their readiness
+and drifted fields are listed in the status of the addon and summarized in the AddonReady and AddonDrifted
+conditions. Addons that aren't ready yet are checked again every 30 seconds.
 */
 package addon
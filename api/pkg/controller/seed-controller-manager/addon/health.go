@@ -0,0 +1,120 @@
+/*
+Copyright 2020 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addon
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	addonutils "github.com/kubermatic/kubermatic/api/pkg/addon"
+	kubermaticv1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// objectsNotReadyReason is the reason of the AddonReady condition when objects of the addon aren't ready
+	objectsNotReadyReason = "ObjectsNotReady"
+	// objectsDriftedReason is the reason of the AddonDrifted condition when objects differ from the manifests
+	objectsDriftedReason = "ObjectsDrifted"
+	// healthyRecheckInterval is how often the health of ready addons is checked, so objects that break or drift
+	// later are reported as well
+	healthyRecheckInterval = 5 * time.Minute
+)
+
+// ensureHealthIsReported records the readiness and drift of the objects of the addon in its status and returns
+// a result to requeue with, more often as long as not all of them are ready
+func (r *Reconciler) ensureHealthIsReported(ctx context.Context, log *zap.SugaredLogger, addon *kubermaticv1.Addon, cluster *kubermaticv1.Cluster) (*reconcile.Result, error) {
+	objects, err := r.getAddonObjects(ctx, log, addon, cluster)
+	if err != nil {
+		return nil, err
+	}
+	// the hook Jobs only run during upgrades, so they aren't part of the health of the addon
+	objects, _, err = splitHooks(objects)
+	if err != nil {
+		return nil, err
+	}
+
+	userClusterClient, err := r.KubeconfigProvider.GetClient(cluster)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get client for usercluster: %v", err)
+	}
+	statuses := addonutils.ObjectStatuses(ctx, userClusterClient, objects)
+
+	var notReady, drifted []string
+	for _, status := range statuses {
+		if !status.Ready {
+			notReady = append(notReady, fmt.Sprintf("%s: %s", objectStatusName(status), status.Message))
+		}
+		if status.Drifted {
+			details := strings.Join(status.DriftedFields, ", ")
+			if details == "" {
+				details = status.Message
+			}
+			drifted = append(drifted, fmt.Sprintf("%s: %s", objectStatusName(status), details))
+		}
+	}
+
+	oldAddon := addon.DeepCopy()
+	addon.Status.Objects = statuses
+	changed := !equality.Semantic.DeepEqual(oldAddon.Status.Objects, addon.Status.Objects)
+	if len(notReady) > 0 {
+		changed = updateAddonCondition(addon, kubermaticv1.AddonReady, corev1.ConditionFalse, objectsNotReadyReason, strings.Join(notReady, "; ")) || changed
+	} else {
+		changed = updateAddonCondition(addon, kubermaticv1.AddonReady, corev1.ConditionTrue, "", "") || changed
+	}
+	if len(drifted) > 0 {
+		changed = updateAddonCondition(addon, kubermaticv1.AddonDrifted, corev1.ConditionTrue, objectsDriftedReason, strings.Join(drifted, "; ")) || changed
+	} else {
+		changed = updateAddonCondition(addon, kubermaticv1.AddonDrifted, corev1.ConditionFalse, "", "") || changed
+	}
+
+	if changed {
+		if err := r.Client.Patch(ctx, addon, ctrlruntimeclient.MergeFrom(oldAddon)); err != nil {
+			return nil, fmt.Errorf("failed to update the health of the addon: %v", err)
+		}
+	}
+
+	if len(notReady) > 0 {
+		log.Debugw("Addon isn't ready yet, checking again in 30 seconds", "objects", len(notReady))
+		return &reconcile.Result{RequeueAfter: 30 * time.Second}, nil
+	}
+	return &reconcile.Result{RequeueAfter: healthyRecheckInterval}, nil
+}
+
+// updateAddonCondition sets the condition if its status, reason or message differ and returns whether it did
+func updateAddonCondition(a *kubermaticv1.Addon, condType kubermaticv1.AddonConditionType, status corev1.ConditionStatus, reason, message string) bool {
+	if _, cond := getAddonCondition(a, condType); cond != nil && cond.Status == status && cond.Reason == reason && cond.Message == message {
+		return false
+	}
+	setAddonCodition(a, condType, status, reason, message)
+	return true
+}
+
+func objectStatusName(status kubermaticv1.AddonObjectStatus) string {
+	if status.Namespace == "" {
+		return fmt.Sprintf("%s %s", status.Kind, status.Name)
+	}
+	return fmt.Sprintf("%s %s/%s", status.Kind, status.Namespace, status.Name)
+}
@@ -0,0 +1,176 @@
+/*
+Copyright 2020 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	addonutils "github.com/kubermatic/kubermatic/api/pkg/addon"
+	kubermaticv1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
+	kuberneteshelper "github.com/kubermatic/kubermatic/api/pkg/kubernetes"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metav1unstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// hookAnnotationKey marks a Job in the addon manifests as upgrade hook instead of an object to apply,
+	// its value is the kind of hook
+	hookAnnotationKey = "addons.kubermatic.io/hook"
+	// hookVersionAnnotationKey is set on the hook Jobs to the addon version they have been run for
+	hookVersionAnnotationKey = "addons.kubermatic.io/hook-version"
+
+	// preUpgradeHook Jobs run before the manifests of the new version are applied
+	preUpgradeHook = "pre-upgrade"
+	// postUpgradeHook Jobs run after the manifests of the new version have been applied
+	postUpgradeHook = "post-upgrade"
+
+	// hookFailedReason is the reason of the AddonResourcesCreated condition when a hook Job failed
+	hookFailedReason = "HookFailed"
+)
+
+// splitHooks separates the hook Jobs from the objects to apply and groups them by the kind of hook
+func splitHooks(objects []*metav1unstructured.Unstructured) ([]*metav1unstructured.Unstructured, map[string][]*metav1unstructured.Unstructured, error) {
+	var applied []*metav1unstructured.Unstructured
+	hooks := map[string][]*metav1unstructured.Unstructured{}
+
+	for _, obj := range objects {
+		hook, ok := obj.GetAnnotations()[hookAnnotationKey]
+		if !ok {
+			applied = append(applied, obj)
+			continue
+		}
+		if hook != preUpgradeHook && hook != postUpgradeHook {
+			return nil, nil, fmt.Errorf("%s %s has the unknown hook %q, it must be one of %q or %q", obj.GetKind(), obj.GetName(), hook, preUpgradeHook, postUpgradeHook)
+		}
+		if obj.GroupVersionKind().GroupKind() != (schema.GroupKind{Group: batchv1.GroupName, Kind: "Job"}) {
+			return nil, nil, fmt.Errorf("%s %s can't be a hook, only Jobs can", obj.GetKind(), obj.GetName())
+		}
+		hooks[hook] = append(hooks[hook], obj)
+	}
+
+	return applied, hooks, nil
+}
+
+// isUpgrading returns whether the addon has been installed in a different version before. The addons that
+// have been installed before their versions were recorded only have the cleanup finalizer.
+func isUpgrading(addon *kubermaticv1.Addon) bool {
+	if addon.Spec.Version == addon.Status.InstalledVersion {
+		return false
+	}
+	return addon.Status.InstalledVersion != "" || kuberneteshelper.HasFinalizer(addon, cleanupFinalizerName)
+}
+
+// ensureHooksCompleted runs the hook Jobs for the version of the addon in the user cluster and returns a result
+// to requeue with until all of them have completed. The Jobs of a previous version get replaced. A failed Job is
+// reported in the AddonResourcesCreated condition and blocks the upgrade until it gets deleted.
+func (r *Reconciler) ensureHooksCompleted(ctx context.Context, log *zap.SugaredLogger, userClusterClient ctrlruntimeclient.Client, addon *kubermaticv1.Addon, hookType string, hooks []*metav1unstructured.Unstructured) (*reconcile.Result, error) {
+	allCompleted := true
+	for _, hook := range hooks {
+		job, err := hookJob(hook, addon.Spec.Version)
+		if err != nil {
+			return nil, err
+		}
+
+		completed, failure, err := ensureHookJobCompleted(ctx, log, userClusterClient, job)
+		if err != nil {
+			return nil, fmt.Errorf("failed to run the %s hook Job %s/%s: %v", hookType, job.Namespace, job.Name, err)
+		}
+		if failure != "" {
+			message := fmt.Sprintf("the %s hook Job %s/%s failed, delete it to retry: %s", hookType, job.Namespace, job.Name, failure)
+			if err := r.setResourcesCreatedConditionFailed(ctx, addon, hookFailedReason, message); err != nil {
+				return nil, err
+			}
+			return nil, errors.New(message)
+		}
+		allCompleted = allCompleted && completed
+	}
+
+	if !allCompleted {
+		log.Debugw("Hooks haven't completed yet, checking again in 10 seconds", "hook", hookType)
+		return &reconcile.Result{RequeueAfter: 10 * time.Second}, nil
+	}
+	return nil, nil
+}
+
+// hookJob returns the Job of the hook for the given addon version
+func hookJob(hook *metav1unstructured.Unstructured, version string) (*batchv1.Job, error) {
+	job := &batchv1.Job{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(hook.Object, job); err != nil {
+		return nil, fmt.Errorf("failed to decode the hook Job %s: %v", hook.GetName(), err)
+	}
+	// hooks end up in the same namespace as the other objects of the addon without one
+	if job.Namespace == "" {
+		job.Namespace = addonutils.DefaultNamespace
+	}
+	// the hooks aren't applied with the other objects, so they must not be pruned with them either
+	delete(job.Labels, addonLabelKey)
+	if job.Annotations == nil {
+		job.Annotations = map[string]string{}
+	}
+	job.Annotations[hookVersionAnnotationKey] = version
+	return job, nil
+}
+
+// ensureHookJobCompleted creates the Job if it doesn't exist yet and replaces it if it has been run for a different
+// version. It returns whether the Job has completed and the reason if it has failed.
+func ensureHookJobCompleted(ctx context.Context, log *zap.SugaredLogger, client ctrlruntimeclient.Client, job *batchv1.Job) (bool, string, error) {
+	existing := &batchv1.Job{}
+	if err := client.Get(ctx, types.NamespacedName{Namespace: job.Namespace, Name: job.Name}, existing); err != nil {
+		if !kerrors.IsNotFound(err) {
+			return false, "", err
+		}
+		log.Infow("Starting hook", "job", job.Namespace+"/"+job.Name)
+		return false, "", client.Create(ctx, job)
+	}
+
+	if existing.Annotations[hookVersionAnnotationKey] != job.Annotations[hookVersionAnnotationKey] {
+		if existing.DeletionTimestamp == nil {
+			log.Infow("Deleting hook of a previous version", "job", job.Namespace+"/"+job.Name)
+			if err := client.Delete(ctx, existing, ctrlruntimeclient.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil && !kerrors.IsNotFound(err) {
+				return false, "", err
+			}
+		}
+		return false, "", nil
+	}
+
+	for _, condition := range existing.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch condition.Type {
+		case batchv1.JobComplete:
+			return true, "", nil
+		case batchv1.JobFailed:
+			return false, fmt.Sprintf("%s: %s", condition.Reason, condition.Message), nil
+		}
+	}
+	return false, "", nil
+}
@@ -0,0 +1,144 @@
+/*
+Copyright 2020 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addon
+
+import (
+	"context"
+	"testing"
+
+	addonutils "github.com/kubermatic/kubermatic/api/pkg/addon"
+	kubermaticv1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
+	kubermaticlog "github.com/kubermatic/kubermatic/api/pkg/log"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metav1unstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlruntimefakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func genHook(apiVersion, kind, name, hook string) *metav1unstructured.Unstructured {
+	obj := &metav1unstructured.Unstructured{}
+	obj.SetAPIVersion(apiVersion)
+	obj.SetKind(kind)
+	obj.SetName(name)
+	obj.SetLabels(map[string]string{addonLabelKey: "test"})
+	if hook != "" {
+		obj.SetAnnotations(map[string]string{hookAnnotationKey: hook})
+	}
+	return obj
+}
+
+func TestSplitHooks(t *testing.T) {
+	objects := []*metav1unstructured.Unstructured{
+		genHook("v1", "ConfigMap", "config", ""),
+		genHook("batch/v1", "Job", "migrate", preUpgradeHook),
+		genHook("batch/v1", "Job", "verify", postUpgradeHook),
+	}
+	applied, hooks, err := splitHooks(objects)
+	if err != nil {
+		t.Fatalf("failed to split the hooks: %v", err)
+	}
+	if len(applied) != 1 || applied[0].GetName() != "config" {
+		t.Fatalf("expected only the ConfigMap to be applied, got %v", applied)
+	}
+	if len(hooks[preUpgradeHook]) != 1 || hooks[preUpgradeHook][0].GetName() != "migrate" {
+		t.Fatalf("expected the migrate Job to be a pre-upgrade hook, got %v", hooks[preUpgradeHook])
+	}
+	if len(hooks[postUpgradeHook]) != 1 || hooks[postUpgradeHook][0].GetName() != "verify" {
+		t.Fatalf("expected the verify Job to be a post-upgrade hook, got %v", hooks[postUpgradeHook])
+	}
+
+	if _, _, err := splitHooks([]*metav1unstructured.Unstructured{genHook("batch/v1", "Job", "migrate", "pre-install")}); err == nil {
+		t.Fatal("expected an error for an unknown hook")
+	}
+	if _, _, err := splitHooks([]*metav1unstructured.Unstructured{genHook("v1", "Pod", "migrate", preUpgradeHook)}); err == nil {
+		t.Fatal("expected an error for a hook that isn't a Job")
+	}
+}
+
+func TestEnsureHooksCompleted(t *testing.T) {
+	log := kubermaticlog.New(true, kubermaticlog.FormatConsole).Sugar()
+	addon := genDependentAddon("test", "v2", "v1")
+	hooks := []*metav1unstructured.Unstructured{genHook("batch/v1", "Job", "migrate", preUpgradeHook)}
+	jobKey := types.NamespacedName{Namespace: addonutils.DefaultNamespace, Name: "migrate"}
+
+	previousJob := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   jobKey.Namespace,
+			Name:        jobKey.Name,
+			Annotations: map[string]string{hookVersionAnnotationKey: "v1"},
+		},
+	}
+	seedClient := ctrlruntimefakeclient.NewFakeClient(addon.DeepCopy())
+	userClusterClient := ctrlruntimefakeclient.NewFakeClient(previousJob)
+	r := &Reconciler{Client: seedClient}
+
+	ensureHooksCompleted := func() (bool, error) {
+		result, err := r.ensureHooksCompleted(context.Background(), log, userClusterClient, addon, preUpgradeHook, hooks)
+		return result == nil, err
+	}
+	setJobCondition := func(conditionType batchv1.JobConditionType) {
+		job := &batchv1.Job{}
+		if err := userClusterClient.Get(context.Background(), jobKey, job); err != nil {
+			t.Fatal(err)
+		}
+		job.Status.Conditions = []batchv1.JobCondition{{Type: conditionType, Status: corev1.ConditionTrue, Reason: "BackoffLimitExceeded"}}
+		if err := userClusterClient.Update(context.Background(), job); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the Job of the previous upgrade gets replaced
+	if completed, err := ensureHooksCompleted(); err != nil || completed {
+		t.Fatalf("expected to wait for the hook, got completed: %t, error: %v", completed, err)
+	}
+	if err := userClusterClient.Get(context.Background(), jobKey, &batchv1.Job{}); !kerrors.IsNotFound(err) {
+		t.Fatalf("expected the Job of the previous version to be deleted, got %v", err)
+	}
+
+	if completed, err := ensureHooksCompleted(); err != nil || completed {
+		t.Fatalf("expected to wait for the hook, got completed: %t, error: %v", completed, err)
+	}
+	job := &batchv1.Job{}
+	if err := userClusterClient.Get(context.Background(), jobKey, job); err != nil {
+		t.Fatalf("expected the Job to be created: %v", err)
+	}
+	if job.Annotations[hookVersionAnnotationKey] != "v2" || job.Labels[addonLabelKey] != "" {
+		t.Fatalf("expected the Job to be created for v2 without the addon label, got annotations %v and labels %v", job.Annotations, job.Labels)
+	}
+
+	setJobCondition(batchv1.JobComplete)
+	if completed, err := ensureHooksCompleted(); err != nil || !completed {
+		t.Fatalf("expected the hook to be completed, got completed: %t, error: %v", completed, err)
+	}
+
+	setJobCondition(batchv1.JobFailed)
+	if _, err := ensureHooksCompleted(); err == nil {
+		t.Fatal("expected an error for the failed hook")
+	}
+	updatedAddon := &kubermaticv1.Addon{}
+	if err := seedClient.Get(context.Background(), ctrlruntimeclient.ObjectKey{Namespace: addon.Namespace, Name: addon.Name}, updatedAddon); err != nil {
+		t.Fatal(err)
+	}
+	if _, cond := getAddonCondition(updatedAddon, kubermaticv1.AddonResourcesCreated); cond == nil || cond.Status != corev1.ConditionFalse || cond.Reason != hookFailedReason {
+		t.Fatalf("expected the failed hook to be reported in the condition, got %+v", cond)
+	}
+}
@@ -27,6 +27,7 @@ import (
 	kubermaticv1helper "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1/helper"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -168,9 +169,13 @@ func (r *Reconciler) ensureAddons(ctx context.Context, log *zap.SugaredLogger, c
 		ensuredAddonsMap[addon.Name] = struct{}{}
 		name := types.NamespacedName{Namespace: cluster.Status.NamespaceName, Name: addon.Name}
 		addonLog := log.With("addon", name)
-		err := r.Get(ctx, name, &kubermaticv1.Addon{})
+		existingAddon := &kubermaticv1.Addon{}
+		err := r.Get(ctx, name, existingAddon)
 		if err == nil {
 			addonLog.Debug("Addon already exists")
+			if err := r.ensureAddonVersion(ctx, addonLog, existingAddon, addon); err != nil {
+				return fmt.Errorf("failed to update addon %q: %v", addon.Name, err)
+			}
 			continue
 		}
 		if !kerrors.IsNotFound(err) {
@@ -239,6 +244,23 @@ func (r *Reconciler) createAddon(ctx context.Context, log *zap.SugaredLogger, ad
 	return nil
 }
 
+// ensureAddonVersion updates the version and the dependencies of an existing addon, so changing them in the
+// default addons upgrades the addon in all clusters
+func (r *Reconciler) ensureAddonVersion(ctx context.Context, log *zap.SugaredLogger, existingAddon *kubermaticv1.Addon, addon kubermaticv1.Addon) error {
+	if existingAddon.Spec.Version == addon.Spec.Version && equality.Semantic.DeepEqual(existingAddon.Spec.Dependencies, addon.Spec.Dependencies) {
+		return nil
+	}
+
+	oldAddon := existingAddon.DeepCopy()
+	existingAddon.Spec.Version = addon.Spec.Version
+	existingAddon.Spec.Dependencies = addon.Spec.Dependencies
+	if err := r.Patch(ctx, existingAddon, ctrlruntimeclient.MergeFrom(oldAddon)); err != nil {
+		return err
+	}
+	log.Infow("Updated addon", "version", addon.Spec.Version, "dependencies", addon.Spec.Dependencies)
+	return nil
+}
+
 func (r *Reconciler) deleteAddon(ctx context.Context, log *zap.SugaredLogger, addon kubermaticv1.Addon) error {
 	log.Infof("deleting addon %s from cluster %s", addon.Name, addon.Namespace)
 	err := r.Delete(ctx, &addon)
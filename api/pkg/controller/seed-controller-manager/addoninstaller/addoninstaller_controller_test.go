@@ -158,3 +158,45 @@ func TestCreateAddon(t *testing.T) {
 		})
 	}
 }
+
+func TestUpdateAddonVersion(t *testing.T) {
+	cluster := &kubermaticv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-cluster"},
+		Status: kubermaticv1.ClusterStatus{
+			ExtendedHealth: kubermaticv1.ExtendedClusterHealth{Apiserver: kubermaticv1.HealthStatusUp},
+			NamespaceName:  "cluster-test-cluster",
+		},
+	}
+	existingAddon := &kubermaticv1.Addon{
+		ObjectMeta: metav1.ObjectMeta{Name: "Foo", Namespace: cluster.Status.NamespaceName},
+		Spec:       kubermaticv1.AddonSpec{Name: "Foo", Version: "v1"},
+	}
+	client := ctrlruntimefakeclient.NewFakeClient(cluster, existingAddon)
+
+	wantedAddons := kubermaticv1.AddonList{Items: []kubermaticv1.Addon{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "Foo"},
+			Spec:       kubermaticv1.AddonSpec{Version: "v2", Dependencies: []string{"Bar"}},
+		},
+		{ObjectMeta: metav1.ObjectMeta{Name: "Bar"}},
+	}}
+	reconciler := Reconciler{
+		log:              kubermaticlog.New(true, kubermaticlog.FormatConsole).Sugar(),
+		Client:           client,
+		kubernetesAddons: wantedAddons,
+	}
+	if _, err := reconciler.reconcile(context.Background(), reconciler.log, cluster); err != nil {
+		t.Fatalf("Reconciliation failed: %v", err)
+	}
+
+	addonFromClient := &kubermaticv1.Addon{}
+	if err := client.Get(context.Background(), types.NamespacedName{Namespace: cluster.Status.NamespaceName, Name: "Foo"}, addonFromClient); err != nil {
+		t.Fatal(err)
+	}
+	if addonFromClient.Spec.Version != "v2" {
+		t.Errorf("expected the addon to be updated to v2, got %q", addonFromClient.Spec.Version)
+	}
+	if diff := deep.Equal(addonFromClient.Spec.Dependencies, []string{"Bar"}); diff != nil {
+		t.Errorf("unexpected dependencies, diff: %v", diff)
+	}
+}
@@ -19,6 +19,7 @@ package backup
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -29,15 +30,18 @@ import (
 	kubermaticv1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
 	kubermaticv1helper "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1/helper"
 	kuberneteshelper "github.com/kubermatic/kubermatic/api/pkg/kubernetes"
+	"github.com/kubermatic/kubermatic/api/pkg/provider"
 	"github.com/kubermatic/kubermatic/api/pkg/resources"
 	"github.com/kubermatic/kubermatic/api/pkg/resources/certificates"
 	"github.com/kubermatic/kubermatic/api/pkg/resources/certificates/triple"
 	"github.com/kubermatic/kubermatic/api/pkg/resources/etcd"
 	"github.com/kubermatic/kubermatic/api/pkg/resources/reconciling"
+	"github.com/kubermatic/kubermatic/api/pkg/storeuploader"
 
 	batchv1 "k8s.io/api/batch/v1"
 	batchv1beta1 "k8s.io/api/batch/v1beta1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
@@ -63,14 +67,40 @@ const (
 	DefaultBackupContainerImage = "gcr.io/etcd-development/etcd"
 	// DefaultBackupInterval defines the default interval used to create backups
 	DefaultBackupInterval = "20m"
+	// DefaultBackupKeep defines the default number of snapshots to keep per cluster
+	DefaultBackupKeep = 20
 	// cronJobPrefix defines the prefix used for all backup cronjob names
 	cronJobPrefix = "etcd-backup"
 	// cleanupFinalizer defines the name for the finalizer to ensure we cleanup after we deleted a cluster
 	cleanupFinalizer = "kubermatic.io/cleanup-backups"
+	// backupJobLabel defines the label we use on all backup jobs
+	backupJobLabel = "kubermatic-etcd-backup"
 	// backupCleanupJobLabel defines the label we use on all cleanup jobs
 	backupCleanupJobLabel = "kubermatic-etcd-backup-cleaner"
 	// clusterEnvVarKey defines the environment variable key for the cluster name
 	clusterEnvVarKey = "CLUSTER"
+	// keepEnvVarKey defines the environment variable key for the number of snapshots to keep
+	keepEnvVarKey = "BACKUP_KEEP"
+	// endpointEnvVarKey defines the environment variable key for the S3 endpoint of the backup destination
+	endpointEnvVarKey = "BACKUP_ENDPOINT"
+	// bucketEnvVarKey defines the environment variable key for the bucket of the backup destination
+	bucketEnvVarKey = "BACKUP_BUCKET"
+	// secureEnvVarKey defines the environment variable key for using TLS to connect to the backup destination
+	secureEnvVarKey = "BACKUP_SECURE"
+	// defaultBackupEndpoint is the endpoint the default backup containers use without a backup destination
+	defaultBackupEndpoint = "minio.minio.svc.cluster.local:9000"
+	// defaultBackupBucket is the bucket the default backup containers use without a backup destination
+	defaultBackupBucket = "kubermatic-etcd-backups"
+	// accessKeyIDEnvVarKey is the environment variable and secret key for the S3 access key ID
+	accessKeyIDEnvVarKey = "ACCESS_KEY_ID"
+	// secretAccessKeyEnvVarKey is the environment variable and secret key for the S3 secret access key
+	secretAccessKeyEnvVarKey = "SECRET_ACCESS_KEY"
+	// DefaultBackupCredentialsSecretName is the name of the Secret in the kube-system namespace
+	// with the credentials for backup destinations without their own credentials
+	DefaultBackupCredentialsSecretName = "s3-credentials"
+	// ListSnapshotsTimeout bounds the time listing the snapshots of a cluster may take, so an unreachable
+	// backup destination doesn't block the caller
+	ListSnapshotsTimeout = 30 * time.Second
 
 	ControllerName = "kubermatic_backup_controller"
 )
@@ -81,11 +111,13 @@ type Reconciler struct {
 	storeContainer   corev1.Container
 	cleanupContainer corev1.Container
 	// backupScheduleString is the cron string representing
-	// the backupSchedule
+	// the backupSchedule. It is used for all clusters without
+	// a schedule in their backup policy.
 	backupScheduleString string
 	// backupContainerImage holds the image used for creating the etcd backup
 	// It must be configurable to cover offline use cases
 	backupContainerImage string
+	seedGetter           provider.SeedGetter
 
 	ctrlruntimeclient.Client
 	recorder record.EventRecorder
@@ -102,6 +134,7 @@ func Add(
 	cleanupContainer corev1.Container,
 	backupSchedule time.Duration,
 	backupContainerImage string,
+	seedGetter provider.SeedGetter,
 ) error {
 	log = log.Named(ControllerName)
 	if err := validateStoreContainer(storeContainer); err != nil {
@@ -122,6 +155,7 @@ func Add(
 		cleanupContainer:     cleanupContainer,
 		backupScheduleString: backupScheduleString,
 		backupContainerImage: backupContainerImage,
+		seedGetter:           seedGetter,
 		Client:               mgr.GetClient(),
 		recorder:             mgr.GetEventRecorderFor(ControllerName),
 	}
@@ -152,6 +186,21 @@ func Add(
 		return fmt.Errorf("failed to watch CronJobs: %v", err)
 	}
 
+	jobMapFn := &handler.EnqueueRequestsFromMapFunc{ToRequests: handler.ToRequestsFunc(func(a handler.MapObject) []reconcile.Request {
+		// The backup jobs are labeled with the name of their cluster
+		if a.Meta.GetNamespace() != metav1.NamespaceSystem || a.Meta.GetLabels()[resources.AppLabelKey] != backupJobLabel {
+			return nil
+		}
+
+		if clusterName := a.Meta.GetLabels()[resources.ClusterLabelKey]; clusterName != "" {
+			return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: clusterName}}}
+		}
+		return nil
+	})}
+	if err := c.Watch(&source.Kind{Type: &batchv1.Job{}}, jobMapFn); err != nil {
+		return fmt.Errorf("failed to watch Jobs: %v", err)
+	}
+
 	// Cleanup cleanup jobs...
 	if err := mgr.Add(&runnableWrapper{
 		f: func(stopCh <-chan struct{}) {
@@ -250,7 +299,14 @@ func (r *Reconciler) reconcile(ctx context.Context, log *zap.SugaredLogger, clus
 	if cluster.DeletionTimestamp != nil {
 		// Need to cleanup
 		if sets.NewString(cluster.Finalizers...).Has(cleanupFinalizer) {
-			if err := r.Create(ctx, r.cleanupJob(cluster)); err != nil {
+			policy, err := r.getBackupPolicy(cluster)
+			if err != nil {
+				// An invalid or removed policy must not block the deletion of the cluster
+				log.Warnw("Cleaning up backups with the default policy", zap.Error(err))
+				r.recorder.Eventf(cluster, corev1.EventTypeWarning, "InvalidBackupPolicy", "Cleaning up backups with the default policy: %v", err)
+				policy = r.defaultBackupPolicy()
+			}
+			if err := r.Create(ctx, r.cleanupJob(cluster, policy)); err != nil {
 				// Otherwise we end up in a loop when we are able to create the job but not
 				// remove the finalizer.
 				if !kerrors.IsAlreadyExists(err) {
@@ -281,11 +337,312 @@ func (r *Reconciler) reconcile(ctx context.Context, log *zap.SugaredLogger, clus
 		}
 	}
 
+	policy, err := r.getBackupPolicy(cluster)
+	if err != nil {
+		return err
+	}
+
 	if err := r.ensureCronJobSecret(ctx, cluster); err != nil {
 		return fmt.Errorf("failed to create backup secret: %v", err)
 	}
 
-	return reconciling.ReconcileCronJobs(ctx, []reconciling.NamedCronJobCreatorGetter{r.cronjob(cluster)}, metav1.NamespaceSystem, r.Client)
+	etcdClusterSize, err := etcd.GetCurrentClusterSize(ctx, r.Client, cluster)
+	if err != nil {
+		return err
+	}
+
+	if err := reconciling.ReconcileCronJobs(ctx, []reconciling.NamedCronJobCreatorGetter{r.cronjob(cluster, policy, etcdClusterSize)}, metav1.NamespaceSystem, r.Client); err != nil {
+		return err
+	}
+
+	return r.updateBackupStatus(ctx, log, cluster, policy)
+}
+
+// updateBackupStatus records the results of the backup jobs of the cluster in its status. If the
+// cluster has a backup destination, the bucket gets queried for the latest snapshot after every
+// successful backup.
+func (r *Reconciler) updateBackupStatus(ctx context.Context, log *zap.SugaredLogger, cluster *kubermaticv1.Cluster, policy *backupPolicy) error {
+	jobs := &batchv1.JobList{}
+	listOpts := []ctrlruntimeclient.ListOption{
+		ctrlruntimeclient.InNamespace(metav1.NamespaceSystem),
+		ctrlruntimeclient.MatchingLabels{
+			resources.AppLabelKey:     backupJobLabel,
+			resources.ClusterLabelKey: cluster.Name,
+		},
+	}
+	if err := r.List(ctx, jobs, listOpts...); err != nil {
+		return fmt.Errorf("failed to list backup jobs: %v", err)
+	}
+
+	status := &kubermaticv1.EtcdBackupStatus{}
+	if cluster.Status.Backup != nil {
+		status = cluster.Status.Backup.DeepCopy()
+	}
+
+	newBackup := false
+	for _, job := range jobs.Items {
+		if job.Status.Succeeded > 0 && job.Status.CompletionTime != nil {
+			if status.LastSuccessfulBackupTime == nil || status.LastSuccessfulBackupTime.Before(job.Status.CompletionTime) {
+				status.LastSuccessfulBackupTime = job.Status.CompletionTime.DeepCopy()
+				newBackup = true
+			}
+		}
+		for _, condition := range job.Status.Conditions {
+			if condition.Type != batchv1.JobFailed || condition.Status != corev1.ConditionTrue {
+				continue
+			}
+			if status.LastFailedBackupTime == nil || status.LastFailedBackupTime.Before(&condition.LastTransitionTime) {
+				status.LastFailedBackupTime = condition.LastTransitionTime.DeepCopy()
+				status.LastFailureMessage = condition.Message
+			}
+		}
+	}
+
+	if newBackup {
+		destination := policy.destination
+		if destination == nil {
+			destination = DefaultBackupDestination(&r.storeContainer)
+		}
+		snapshots, err := ListSnapshots(ctx, r.Client, cluster, destination)
+		if err != nil {
+			// The backup itself succeeded, failing to get its details should not block the reconciling
+			log.Errorw("Failed to list snapshots", zap.Error(err))
+			r.recorder.Eventf(cluster, corev1.EventTypeWarning, "ListingSnapshotsFailed", "Failed to list snapshots: %v", err)
+		} else if len(snapshots) > 0 {
+			status.LatestSnapshot = &snapshots[len(snapshots)-1]
+		}
+	}
+
+	// Nothing to record before the first backup job finished
+	if status.LastSuccessfulBackupTime == nil && status.LastFailedBackupTime == nil {
+		return nil
+	}
+	if equality.Semantic.DeepEqual(status, cluster.Status.Backup) {
+		return nil
+	}
+
+	oldCluster := cluster.DeepCopy()
+	cluster.Status.Backup = status
+	if err := r.Patch(ctx, cluster, ctrlruntimeclient.MergeFrom(oldCluster)); err != nil {
+		return fmt.Errorf("failed to update backup status: %v", err)
+	}
+	return nil
+}
+
+// ListSnapshots returns the snapshots of the cluster in the given backup destination, sorted from the
+// oldest to the newest. The credentials are read from the kube-system namespace of the given seed client.
+// An unreachable destination fails the listing after ListSnapshotsTimeout.
+func ListSnapshots(ctx context.Context, client ctrlruntimeclient.Client, cluster *kubermaticv1.Cluster, destination *kubermaticv1.BackupDestination) ([]kubermaticv1.EtcdSnapshot, error) {
+	ctx, cancel := context.WithTimeout(ctx, ListSnapshotsTimeout)
+	defer cancel()
+
+	secretName := destination.CredentialsSecretName
+	if secretName == "" {
+		secretName = DefaultBackupCredentialsSecretName
+	}
+	credentials := &corev1.Secret{}
+	if err := client.Get(ctx, types.NamespacedName{Namespace: metav1.NamespaceSystem, Name: secretName}, credentials); err != nil {
+		return nil, fmt.Errorf("failed to get backup credentials: %v", err)
+	}
+
+	uploader, err := storeuploader.New(
+		destination.Endpoint,
+		destination.Secure,
+		string(credentials.Data[accessKeyIDEnvVarKey]),
+		string(credentials.Data[secretAccessKeyEnvVarKey]),
+		zap.NewNop().Sugar(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create S3 client: %v", err)
+	}
+	objects, err := uploader.ListBackups(ctx, destination.BucketName, cluster.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots := make([]kubermaticv1.EtcdSnapshot, 0, len(objects))
+	for _, object := range objects {
+		snapshots = append(snapshots, kubermaticv1.EtcdSnapshot{
+			Name:              object.Key,
+			Size:              object.Size,
+			CreationTimestamp: metav1.NewTime(object.LastModified),
+		})
+	}
+	return snapshots, nil
+}
+
+// backupPolicy is the effective backup policy of a cluster
+type backupPolicy struct {
+	schedule    string
+	keep        int
+	destination *kubermaticv1.BackupDestination
+}
+
+// getBackupPolicy merges the backup policy of the cluster with the one of its datacenter
+// and the defaults of the controller
+func (r *Reconciler) getBackupPolicy(cluster *kubermaticv1.Cluster) (*backupPolicy, error) {
+	seed, err := r.seedGetter()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get seed: %v", err)
+	}
+	datacenter, found := seed.Spec.Datacenters[cluster.Spec.Cloud.DatacenterName]
+	if !found {
+		return nil, fmt.Errorf("couldn't find datacenter %q for cluster %q", cluster.Spec.Cloud.DatacenterName, cluster.Name)
+	}
+
+	policy := r.defaultBackupPolicy()
+	var destinationName string
+	for _, override := range []*kubermaticv1.EtcdBackupPolicy{datacenter.Spec.BackupPolicy, cluster.Spec.BackupPolicy} {
+		if override == nil {
+			continue
+		}
+		if override.Schedule != "" {
+			policy.schedule = override.Schedule
+		}
+		if override.Keep != nil {
+			policy.keep = int(*override.Keep)
+		}
+		if override.Destination != "" {
+			destinationName = override.Destination
+		}
+	}
+
+	// Verify the schedule here, the cronjob controller only does that inside its sync loop
+	if _, err := cron.ParseStandard(policy.schedule); err != nil {
+		return nil, fmt.Errorf("invalid backup schedule %q: %v", policy.schedule, err)
+	}
+	if policy.keep < 1 {
+		return nil, fmt.Errorf("invalid number of backups to keep %d, must be at least 1", policy.keep)
+	}
+	if destinationName != "" {
+		policy.destination, err = GetBackupDestination(seed, destinationName)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return policy, nil
+}
+
+// defaultBackupPolicy returns the policy of clusters whose datacenter and cluster don't override it,
+// it backs up to the default destination
+func (r *Reconciler) defaultBackupPolicy() *backupPolicy {
+	return &backupPolicy{
+		schedule: r.backupScheduleString,
+		keep:     DefaultBackupKeep,
+	}
+}
+
+// GetBackupDestination returns the backup destination with the given name from the seed
+func GetBackupDestination(seed *kubermaticv1.Seed, name string) (*kubermaticv1.BackupDestination, error) {
+	destination, found := seed.Spec.BackupDestinations[name]
+	if !found {
+		return nil, fmt.Errorf("backup destination %q does not exist in seed %q", name, seed.Name)
+	}
+	return &destination, nil
+}
+
+// GetClusterBackupDestination returns the backup destination of the cluster or nil
+// if the cluster uses the destination configured in the backup containers
+func GetClusterBackupDestination(seed *kubermaticv1.Seed, cluster *kubermaticv1.Cluster) (*kubermaticv1.BackupDestination, error) {
+	var name string
+	if datacenter, found := seed.Spec.Datacenters[cluster.Spec.Cloud.DatacenterName]; found && datacenter.Spec.BackupPolicy != nil {
+		name = datacenter.Spec.BackupPolicy.Destination
+	}
+	if cluster.Spec.BackupPolicy != nil && cluster.Spec.BackupPolicy.Destination != "" {
+		name = cluster.Spec.BackupPolicy.Destination
+	}
+	if name == "" {
+		return nil, nil
+	}
+	return GetBackupDestination(seed, name)
+}
+
+// GetCronJobBackupDestination returns the destination the backup CronJob of the cluster stores the snapshots in.
+// Like the controller, it falls back to the destination configured in the store container, so it should only be
+// used for clusters without backup destination. Nil is returned if the cluster has no backup CronJob.
+func GetCronJobBackupDestination(ctx context.Context, client ctrlruntimeclient.Client, cluster *kubermaticv1.Cluster) (*kubermaticv1.BackupDestination, error) {
+	cronJob := &batchv1beta1.CronJob{}
+	if err := client.Get(ctx, types.NamespacedName{Namespace: metav1.NamespaceSystem, Name: CronJobName(cluster)}, cronJob); err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get backup CronJob: %v", err)
+	}
+	containers := cronJob.Spec.JobTemplate.Spec.Template.Spec.Containers
+	if len(containers) == 0 {
+		return nil, fmt.Errorf("backup CronJob %s has no store container", cronJob.Name)
+	}
+	return DefaultBackupDestination(&containers[0]), nil
+}
+
+// DefaultBackupDestination returns the destination the given backup container stores the snapshots of
+// clusters without a backup destination in. It is read from the literal BACKUP_ENDPOINT, BACKUP_BUCKET and
+// BACKUP_SECURE environment variables of the container and defaults to the destination of the default
+// backup containers. The credentials are the default ones.
+func DefaultBackupDestination(container *corev1.Container) *kubermaticv1.BackupDestination {
+	destination := &kubermaticv1.BackupDestination{
+		Endpoint:   defaultBackupEndpoint,
+		BucketName: defaultBackupBucket,
+	}
+	if container == nil {
+		return destination
+	}
+	for _, env := range container.Env {
+		if env.Value == "" {
+			continue
+		}
+		switch env.Name {
+		case endpointEnvVarKey:
+			destination.Endpoint = env.Value
+		case bucketEnvVarKey:
+			destination.BucketName = env.Value
+		case secureEnvVarKey:
+			destination.Secure, _ = strconv.ParseBool(env.Value)
+		}
+	}
+	return destination
+}
+
+// SetBackupDestinationEnv configures the container to use the given backup destination
+// by setting the BACKUP_ENDPOINT, BACKUP_BUCKET and BACKUP_SECURE environment variables. If the destination
+// has its own credentials, they replace the ones configured in the container.
+func SetBackupDestinationEnv(container *corev1.Container, destination *kubermaticv1.BackupDestination) {
+	if destination == nil {
+		return
+	}
+	env := []corev1.EnvVar{
+		{Name: endpointEnvVarKey, Value: destination.Endpoint},
+		{Name: bucketEnvVarKey, Value: destination.BucketName},
+		{Name: secureEnvVarKey, Value: strconv.FormatBool(destination.Secure)},
+	}
+	if destination.CredentialsSecretName != "" {
+		for _, key := range []string{accessKeyIDEnvVarKey, secretAccessKeyEnvVarKey} {
+			env = append(env, corev1.EnvVar{
+				Name: key,
+				ValueFrom: &corev1.EnvVarSource{
+					SecretKeyRef: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: destination.CredentialsSecretName},
+						Key:                  key,
+					},
+				},
+			})
+		}
+	}
+
+	for _, envVar := range env {
+		setEnvVar(container, envVar)
+	}
+}
+
+func setEnvVar(container *corev1.Container, envVar corev1.EnvVar) {
+	for i := range container.Env {
+		if container.Env[i].Name == envVar.Name {
+			container.Env[i] = envVar
+			return
+		}
+	}
+	container.Env = append(container.Env, envVar)
 }
 
 func (r *Reconciler) getEtcdSecretName(cluster *kubermaticv1.Cluster) string {
@@ -322,12 +679,13 @@ func (r *Reconciler) ensureCronJobSecret(ctx context.Context, cluster *kubermati
 	return nil
 }
 
-func (r *Reconciler) cleanupJob(cluster *kubermaticv1.Cluster) *batchv1.Job {
+func (r *Reconciler) cleanupJob(cluster *kubermaticv1.Cluster, policy *backupPolicy) *batchv1.Job {
 	cleanupContainer := r.cleanupContainer.DeepCopy()
 	cleanupContainer.Env = append(cleanupContainer.Env, corev1.EnvVar{
 		Name:  clusterEnvVarKey,
 		Value: cluster.Name,
 	})
+	SetBackupDestinationEnv(cleanupContainer, policy.destination)
 
 	return &batchv1.Job{
 		ObjectMeta: metav1.ObjectMeta{
@@ -362,21 +720,26 @@ func (r *Reconciler) cleanupJob(cluster *kubermaticv1.Cluster) *batchv1.Job {
 	}
 }
 
-func (r *Reconciler) cronjob(cluster *kubermaticv1.Cluster) reconciling.NamedCronJobCreatorGetter {
+func (r *Reconciler) cronjob(cluster *kubermaticv1.Cluster, policy *backupPolicy, etcdClusterSize int) reconciling.NamedCronJobCreatorGetter {
 	return func() (string, reconciling.CronJobCreator) {
-		return fmt.Sprintf("%s-%s", cronJobPrefix, cluster.Name), func(cronJob *batchv1beta1.CronJob) (*batchv1beta1.CronJob, error) {
+		return CronJobName(cluster), func(cronJob *batchv1beta1.CronJob) (*batchv1beta1.CronJob, error) {
 			gv := kubermaticv1.SchemeGroupVersion
 			cronJob.OwnerReferences = []metav1.OwnerReference{
 				*metav1.NewControllerRef(cluster, gv.WithKind(kubermaticv1.ClusterKindName)),
 			}
 
 			// Spec
-			cronJob.Spec.Schedule = r.backupScheduleString
+			cronJob.Spec.Schedule = policy.schedule
 			cronJob.Spec.ConcurrencyPolicy = batchv1beta1.ForbidConcurrent
 			cronJob.Spec.Suspend = utilpointer.BoolPtr(false)
-			cronJob.Spec.SuccessfulJobsHistoryLimit = utilpointer.Int32Ptr(0)
+			// Keep the last successful job, its completion time is recorded in the cluster status
+			cronJob.Spec.SuccessfulJobsHistoryLimit = utilpointer.Int32Ptr(1)
+			cronJob.Spec.JobTemplate.Labels = map[string]string{
+				resources.AppLabelKey:     backupJobLabel,
+				resources.ClusterLabelKey: cluster.Name,
+			}
 
-			endpoints := etcd.GetClientEndpoints(cluster.Status.NamespaceName)
+			endpoints := etcd.GetClientEndpoints(cluster, etcdClusterSize)
 			image := r.backupContainerImage
 			if !strings.Contains(image, ":") {
 				image = image + ":" + etcd.ImageTag(cluster)
@@ -425,7 +788,11 @@ func (r *Reconciler) cronjob(cluster *kubermaticv1.Cluster) reconciling.NamedCro
 			storeContainer.Env = append(storeContainer.Env, corev1.EnvVar{
 				Name:  clusterEnvVarKey,
 				Value: cluster.Name,
+			}, corev1.EnvVar{
+				Name:  keepEnvVarKey,
+				Value: strconv.Itoa(policy.keep),
 			})
+			SetBackupDestinationEnv(storeContainer, policy.destination)
 
 			cronJob.Spec.JobTemplate.Spec.Template.Spec.RestartPolicy = corev1.RestartPolicyOnFailure
 			cronJob.Spec.JobTemplate.Spec.Template.Spec.Containers = []corev1.Container{*storeContainer}
@@ -452,6 +819,11 @@ func (r *Reconciler) cronjob(cluster *kubermaticv1.Cluster) reconciling.NamedCro
 
 }
 
+// CronJobName returns the name of the backup CronJob of the cluster in the kube-system namespace
+func CronJobName(cluster *kubermaticv1.Cluster) string {
+	return fmt.Sprintf("%s-%s", cronJobPrefix, cluster.Name)
+}
+
 func parseDuration(interval time.Duration) (string, error) {
 	scheduleString := fmt.Sprintf("@every %vm", interval.Round(time.Minute).Minutes())
 	// We verify the validity of the scheduleString here, because the cronjob controller
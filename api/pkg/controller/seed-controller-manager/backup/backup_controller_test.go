@@ -18,7 +18,14 @@ package backup
 
 import (
 	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/go-test/deep"
 
 	kubermaticv1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
 	kubermaticlog "github.com/kubermatic/kubermatic/api/pkg/log"
@@ -26,11 +33,14 @@ import (
 	"github.com/kubermatic/kubermatic/api/pkg/resources/certificates/triple"
 	"github.com/kubermatic/kubermatic/api/pkg/semver"
 
+	batchv1 "k8s.io/api/batch/v1"
 	batchv1beta1 "k8s.io/api/batch/v1beta1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	certutil "k8s.io/client-go/util/cert"
+	utilpointer "k8s.io/utils/pointer"
 	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 	ctrlruntimefakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -43,8 +53,22 @@ var (
 	testCleanupContainer = corev1.Container{Name: "kubermatic-cleanup",
 		Image: "busybox",
 	}
+	testSeed = &kubermaticv1.Seed{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-seed"},
+		Spec: kubermaticv1.SeedSpec{
+			Datacenters: map[string]kubermaticv1.Datacenter{
+				"test-dc": {},
+			},
+		},
+	}
 )
 
+func testSeedGetter(seed *kubermaticv1.Seed) func() (*kubermaticv1.Seed, error) {
+	return func() (*kubermaticv1.Seed, error) {
+		return seed, nil
+	}
+}
+
 func TestEnsureBackupCronJob(t *testing.T) {
 	cluster := &kubermaticv1.Cluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -52,6 +76,7 @@ func TestEnsureBackupCronJob(t *testing.T) {
 		},
 		Spec: kubermaticv1.ClusterSpec{
 			Version: *semver.NewSemverOrDie("1.16.3"),
+			Cloud:   kubermaticv1.CloudSpec{DatacenterName: "test-dc"},
 		},
 		Status: kubermaticv1.ClusterStatus{
 			NamespaceName: "testnamespace",
@@ -86,7 +111,9 @@ func TestEnsureBackupCronJob(t *testing.T) {
 		log:                  kubermaticlog.New(true, kubermaticlog.FormatConsole).Sugar(),
 		storeContainer:       testStoreContainer,
 		cleanupContainer:     testCleanupContainer,
+		backupScheduleString: "@every 20m",
 		backupContainerImage: DefaultBackupContainerImage,
+		seedGetter:           testSeedGetter(testSeed),
 		Client:               ctrlruntimefakeclient.NewFakeClient(caSecret, cluster),
 	}
 
@@ -103,8 +130,8 @@ func TestEnsureBackupCronJob(t *testing.T) {
 		t.Fatalf("Expected exactly one cronjob, got %v", len(cronJobs.Items))
 	}
 
-	if *cronJobs.Items[0].Spec.SuccessfulJobsHistoryLimit != 0 {
-		t.Errorf("Expected spec.SuccessfulJobsHistoryLimit to be 0 but was %v",
+	if *cronJobs.Items[0].Spec.SuccessfulJobsHistoryLimit != 1 {
+		t.Errorf("Expected spec.SuccessfulJobsHistoryLimit to be 1 but was %v",
 			*cronJobs.Items[0].Spec.SuccessfulJobsHistoryLimit)
 	}
 
@@ -168,7 +195,7 @@ func TestCleanupJobSpec(t *testing.T) {
 		cleanupContainer: testCleanupContainer,
 	}
 
-	cleanupJob := reconciler.cleanupJob(&kubermaticv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "test-cluster"}})
+	cleanupJob := reconciler.cleanupJob(&kubermaticv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "test-cluster"}}, &backupPolicy{})
 
 	if cleanupJob.Namespace != metav1.NamespaceSystem {
 		t.Errorf("expected cleanup jobs Namespace to be %q but was %q", metav1.NamespaceSystem, cleanupJob.Namespace)
@@ -178,3 +205,341 @@ func TestCleanupJobSpec(t *testing.T) {
 		t.Errorf("expected cleanup job to have exactly one container, got %d", containerLen)
 	}
 }
+
+func TestCleanupWithInvalidBackupPolicy(t *testing.T) {
+	deletionTimestamp := metav1.Now()
+	cluster := &kubermaticv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "test-cluster",
+			DeletionTimestamp: &deletionTimestamp,
+			Finalizers:        []string{cleanupFinalizer},
+		},
+		Spec: kubermaticv1.ClusterSpec{
+			Cloud:        kubermaticv1.CloudSpec{DatacenterName: "test-dc"},
+			BackupPolicy: &kubermaticv1.EtcdBackupPolicy{Destination: "removed"},
+		},
+	}
+	reconciler := &Reconciler{
+		log:                  kubermaticlog.New(true, kubermaticlog.FormatConsole).Sugar(),
+		cleanupContainer:     testCleanupContainer,
+		backupScheduleString: "@every 20m",
+		seedGetter:           testSeedGetter(testSeed),
+		Client:               ctrlruntimefakeclient.NewFakeClient(cluster),
+		recorder:             record.NewFakeRecorder(10),
+	}
+
+	if err := reconciler.reconcile(context.Background(), reconciler.log, cluster); err != nil {
+		t.Fatalf("expected the cleanup to fall back to the default policy, got: %v", err)
+	}
+	jobs := &batchv1.JobList{}
+	if err := reconciler.List(context.Background(), jobs); err != nil {
+		t.Fatalf("failed to list jobs: %v", err)
+	}
+	if len(jobs.Items) != 1 {
+		t.Fatalf("expected exactly one cleanup job, got %d", len(jobs.Items))
+	}
+	if len(cluster.Finalizers) != 0 {
+		t.Errorf("expected the cleanup finalizer to be removed, got %v", cluster.Finalizers)
+	}
+}
+
+func TestGetBackupPolicy(t *testing.T) {
+	seed := &kubermaticv1.Seed{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-seed"},
+		Spec: kubermaticv1.SeedSpec{
+			Datacenters: map[string]kubermaticv1.Datacenter{
+				"default-dc": {},
+				"dev-dc": {
+					Spec: kubermaticv1.DatacenterSpec{
+						BackupPolicy: &kubermaticv1.EtcdBackupPolicy{
+							Schedule:    "@every 6h",
+							Keep:        utilpointer.Int32Ptr(2),
+							Destination: "cheap",
+						},
+					},
+				},
+			},
+			BackupDestinations: map[string]kubermaticv1.BackupDestination{
+				"cheap": {Endpoint: "s3.example.com", BucketName: "dev-backups"},
+				"safe":  {Endpoint: "s3.example.com", BucketName: "prod-backups", CredentialsSecretName: "prod-credentials"},
+			},
+		},
+	}
+
+	testCases := []struct {
+		name           string
+		datacenter     string
+		clusterPolicy  *kubermaticv1.EtcdBackupPolicy
+		expectedPolicy *backupPolicy
+		expectError    bool
+	}{
+		{
+			name:       "defaults of the controller",
+			datacenter: "default-dc",
+			expectedPolicy: &backupPolicy{
+				schedule: "@every 20m",
+				keep:     DefaultBackupKeep,
+			},
+		},
+		{
+			name:       "policy of the datacenter",
+			datacenter: "dev-dc",
+			expectedPolicy: &backupPolicy{
+				schedule:    "@every 6h",
+				keep:        2,
+				destination: &kubermaticv1.BackupDestination{Endpoint: "s3.example.com", BucketName: "dev-backups"},
+			},
+		},
+		{
+			name:       "cluster overrides the datacenter",
+			datacenter: "dev-dc",
+			clusterPolicy: &kubermaticv1.EtcdBackupPolicy{
+				Schedule:    "0 */2 * * *",
+				Destination: "safe",
+			},
+			expectedPolicy: &backupPolicy{
+				schedule:    "0 */2 * * *",
+				keep:        2,
+				destination: &kubermaticv1.BackupDestination{Endpoint: "s3.example.com", BucketName: "prod-backups", CredentialsSecretName: "prod-credentials"},
+			},
+		},
+		{
+			name:          "unknown destination",
+			datacenter:    "default-dc",
+			clusterPolicy: &kubermaticv1.EtcdBackupPolicy{Destination: "unknown"},
+			expectError:   true,
+		},
+		{
+			name:          "invalid schedule",
+			datacenter:    "default-dc",
+			clusterPolicy: &kubermaticv1.EtcdBackupPolicy{Schedule: "every now and then"},
+			expectError:   true,
+		},
+		{
+			name:          "nothing to keep",
+			datacenter:    "default-dc",
+			clusterPolicy: &kubermaticv1.EtcdBackupPolicy{Keep: utilpointer.Int32Ptr(0)},
+			expectError:   true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			reconciler := &Reconciler{
+				backupScheduleString: "@every 20m",
+				seedGetter:           testSeedGetter(seed),
+			}
+			cluster := &kubermaticv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-cluster"},
+				Spec: kubermaticv1.ClusterSpec{
+					Cloud:        kubermaticv1.CloudSpec{DatacenterName: tc.datacenter},
+					BackupPolicy: tc.clusterPolicy,
+				},
+			}
+
+			policy, err := reconciler.getBackupPolicy(cluster)
+			if tc.expectError {
+				if err == nil {
+					t.Fatal("expected an error but got nothing")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := deep.Equal(policy, tc.expectedPolicy); diff != nil {
+				t.Errorf("unexpected backup policy, diff: %v", diff)
+			}
+		})
+	}
+}
+
+func TestSetBackupDestinationEnv(t *testing.T) {
+	container := testStoreContainer.DeepCopy()
+	container.Env = []corev1.EnvVar{
+		{Name: "ACCESS_KEY_ID", ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "s3-credentials"}, Key: "ACCESS_KEY_ID"}}},
+	}
+
+	SetBackupDestinationEnv(container, &kubermaticv1.BackupDestination{
+		Endpoint:              "s3.example.com",
+		BucketName:            "prod-backups",
+		CredentialsSecretName: "prod-credentials",
+		Secure:                true,
+	})
+
+	expectedEnv := []corev1.EnvVar{
+		{Name: "ACCESS_KEY_ID", ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "prod-credentials"}, Key: "ACCESS_KEY_ID"}}},
+		{Name: "BACKUP_ENDPOINT", Value: "s3.example.com"},
+		{Name: "BACKUP_BUCKET", Value: "prod-backups"},
+		{Name: "BACKUP_SECURE", Value: "true"},
+		{Name: "SECRET_ACCESS_KEY", ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "prod-credentials"}, Key: "SECRET_ACCESS_KEY"}}},
+	}
+	if diff := deep.Equal(container.Env, expectedEnv); diff != nil {
+		t.Errorf("unexpected env, diff: %v", diff)
+	}
+}
+
+func TestUpdateBackupStatus(t *testing.T) {
+	lastSuccess := metav1.NewTime(time.Date(2020, time.January, 2, 10, 0, 0, 0, time.UTC))
+	lastFailure := metav1.NewTime(time.Date(2020, time.January, 2, 9, 0, 0, 0, time.UTC))
+	cluster := &kubermaticv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-cluster"},
+		Status: kubermaticv1.ClusterStatus{
+			Backup: &kubermaticv1.EtcdBackupStatus{
+				LastSuccessfulBackupTime: &metav1.Time{Time: lastSuccess.Add(-time.Hour)},
+			},
+		},
+	}
+	backupJob := func(name, clusterName string) *batchv1.Job {
+		return &batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: metav1.NamespaceSystem,
+				Labels: map[string]string{
+					resources.AppLabelKey:     backupJobLabel,
+					resources.ClusterLabelKey: clusterName,
+				},
+			},
+		}
+	}
+	succeededJob := backupJob("succeeded", cluster.Name)
+	succeededJob.Status.Succeeded = 1
+	succeededJob.Status.CompletionTime = &lastSuccess
+	failedJob := backupJob("failed", cluster.Name)
+	failedJob.Status.Conditions = []batchv1.JobCondition{{
+		Type:               batchv1.JobFailed,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: lastFailure,
+		Message:            "Job has reached the specified backoff limit",
+	}}
+	otherClusterJob := backupJob("other", "other-cluster")
+	otherClusterJob.Status.Succeeded = 1
+	otherClusterJob.Status.CompletionTime = &metav1.Time{Time: lastSuccess.Add(time.Hour)}
+
+	reconciler := &Reconciler{
+		log:      kubermaticlog.New(true, kubermaticlog.FormatConsole).Sugar(),
+		Client:   ctrlruntimefakeclient.NewFakeClient(cluster, succeededJob, failedJob, otherClusterJob),
+		recorder: record.NewFakeRecorder(10),
+	}
+
+	ctx := context.Background()
+	if err := reconciler.updateBackupStatus(ctx, reconciler.log, cluster, &backupPolicy{}); err != nil {
+		t.Fatalf("failed to update backup status: %v", err)
+	}
+	if err := reconciler.Get(ctx, types.NamespacedName{Name: cluster.Name}, cluster); err != nil {
+		t.Fatalf("failed to get cluster: %v", err)
+	}
+
+	expected := &kubermaticv1.EtcdBackupStatus{
+		LastSuccessfulBackupTime: &lastSuccess,
+		LastFailedBackupTime:     &lastFailure,
+		LastFailureMessage:       "Job has reached the specified backoff limit",
+	}
+	if diff := deep.Equal(cluster.Status.Backup, expected); diff != nil {
+		t.Errorf("backup status differs from the expected one, diff: %v", diff)
+	}
+}
+
+func TestListSnapshots(t *testing.T) {
+	s3 := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.URL.Query()["location"]; ok {
+			fmt.Fprint(w, `<LocationConstraint xmlns="http://s3.amazonaws.com/doc/2006-03-01/">us-east-1</LocationConstraint>`)
+			return
+		}
+		if r.URL.Path != "/etcd-backups/" || r.URL.Query().Get("prefix") != "test-cluster-storeuploader" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, `<ListBucketResult xmlns="http://s3.amazonaws.com/doc/2006-03-01/">
+  <Name>etcd-backups</Name>
+  <IsTruncated>false</IsTruncated>
+  <Contents>
+    <Key>test-cluster-storeuploader-2020-01-02T10:00:00-snapshot.db</Key>
+    <LastModified>2020-01-02T10:00:05.000Z</LastModified>
+    <Size>2048</Size>
+  </Contents>
+  <Contents>
+    <Key>test-cluster-storeuploader-2020-01-02T09:40:00-snapshot.db</Key>
+    <LastModified>2020-01-02T09:40:05.000Z</LastModified>
+    <Size>1024</Size>
+  </Contents>
+</ListBucketResult>`)
+	}))
+	defer s3.Close()
+
+	credentials := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "offsite-credentials", Namespace: metav1.NamespaceSystem},
+		Data: map[string][]byte{
+			"ACCESS_KEY_ID":     []byte("foo"),
+			"SECRET_ACCESS_KEY": []byte("bar"),
+		},
+	}
+	destination := &kubermaticv1.BackupDestination{
+		Endpoint:              strings.TrimPrefix(s3.URL, "http://"),
+		BucketName:            "etcd-backups",
+		CredentialsSecretName: credentials.Name,
+	}
+	cluster := &kubermaticv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "test-cluster"}}
+
+	snapshots, err := ListSnapshots(context.Background(), ctrlruntimefakeclient.NewFakeClient(credentials), cluster, destination)
+	if err != nil {
+		t.Fatalf("failed to list snapshots: %v", err)
+	}
+
+	expected := []kubermaticv1.EtcdSnapshot{
+		{
+			Name:              "test-cluster-storeuploader-2020-01-02T09:40:00-snapshot.db",
+			Size:              1024,
+			CreationTimestamp: metav1.NewTime(time.Date(2020, time.January, 2, 9, 40, 5, 0, time.UTC)),
+		},
+		{
+			Name:              "test-cluster-storeuploader-2020-01-02T10:00:00-snapshot.db",
+			Size:              2048,
+			CreationTimestamp: metav1.NewTime(time.Date(2020, time.January, 2, 10, 0, 5, 0, time.UTC)),
+		},
+	}
+	if diff := deep.Equal(snapshots, expected); diff != nil {
+		t.Errorf("snapshots differ from the expected ones, diff: %v", diff)
+	}
+
+	destination.Secure = true
+	if _, err := ListSnapshots(context.Background(), ctrlruntimefakeclient.NewFakeClient(credentials), cluster, destination); err == nil {
+		t.Error("expected listing the snapshots of a secure destination to fail against a plain HTTP endpoint")
+	}
+
+	// an unresponsive destination doesn't block the caller beyond its context
+	release := make(chan struct{})
+	unresponsive := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer unresponsive.Close()
+	defer close(release)
+	destination = &kubermaticv1.BackupDestination{
+		Endpoint:              strings.TrimPrefix(unresponsive.URL, "http://"),
+		BucketName:            "etcd-backups",
+		CredentialsSecretName: credentials.Name,
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if _, err := ListSnapshots(ctx, ctrlruntimefakeclient.NewFakeClient(credentials), cluster, destination); err == nil || !strings.Contains(err.Error(), "deadline exceeded") {
+		t.Errorf("expected listing the snapshots of an unresponsive destination to time out, got %v", err)
+	}
+}
+
+func TestDefaultBackupDestination(t *testing.T) {
+	expected := &kubermaticv1.BackupDestination{Endpoint: "minio.minio.svc.cluster.local:9000", BucketName: "kubermatic-etcd-backups"}
+	if diff := deep.Equal(DefaultBackupDestination(&testStoreContainer), expected); diff != nil {
+		t.Errorf("expected the destination of the default backup containers, diff: %v", diff)
+	}
+
+	container := testStoreContainer.DeepCopy()
+	container.Env = []corev1.EnvVar{
+		{Name: "BACKUP_ENDPOINT", Value: "s3.example.com"},
+		{Name: "BACKUP_BUCKET", Value: "backups"},
+		{Name: "BACKUP_SECURE", Value: "true"},
+	}
+	expected = &kubermaticv1.BackupDestination{Endpoint: "s3.example.com", BucketName: "backups", Secure: true}
+	if diff := deep.Equal(DefaultBackupDestination(container), expected); diff != nil {
+		t.Errorf("expected the destination configured in the backup container, diff: %v", diff)
+	}
+}
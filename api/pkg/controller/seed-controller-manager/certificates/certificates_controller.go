@@ -0,0 +1,266 @@
+/*
+Copyright 2020 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificates
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	controllerutil "github.com/kubermatic/kubermatic/api/pkg/controller/util"
+	kubermaticv1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
+	kubermaticv1helper "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1/helper"
+	"github.com/kubermatic/kubermatic/api/pkg/resources"
+	"github.com/kubermatic/kubermatic/api/pkg/resources/certificates"
+	metricsserver "github.com/kubermatic/kubermatic/api/pkg/resources/metrics-server"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/record"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	// ControllerName is the name of this controller
+	ControllerName = "kubermatic_certificate_controller"
+
+	// DefaultRotationThreshold is the default time before their expiry at which leaf certificates get rotated
+	DefaultRotationThreshold = 30 * 24 * time.Hour
+
+	// rootCAExpiryWarningThreshold is the time before its expiry from which on a warning about
+	// the root CA is emitted, as rotating it requires manual steps
+	rootCAExpiryWarningThreshold = 365 * 24 * time.Hour
+)
+
+// rotatableSecrets are the secrets whose certificates get reissued by the cluster controllers once
+// they are removed. Other secrets of the cluster namespace, like user-provided or externally managed
+// ones, are never touched, as nothing would recreate their certificates.
+var rotatableSecrets = sets.NewString(
+	resources.ApiserverTLSSecretName,
+	resources.KubeletClientCertificatesSecretName,
+	resources.ApiserverEtcdClientCertificateSecretName,
+	resources.ApiserverFrontProxyClientCertificateSecretName,
+	resources.EtcdTLSCertificateSecretName,
+	resources.EtcdPeerTLSCertificateSecretName,
+	resources.OpenVPNServerCertificatesSecretName,
+	resources.OpenVPNClientCertificatesSecretName,
+	resources.MachineControllerWebhookServingCertSecretName,
+	resources.PrometheusApiserverClientCertificateSecretName,
+	metricsserver.ServingCertSecretName,
+
+	// Kubeconfigs
+	resources.SchedulerKubeconfigSecretName,
+	resources.KubeletDnatControllerKubeconfigSecretName,
+	resources.MachineControllerKubeconfigSecretName,
+	resources.ControllerManagerKubeconfigSecretName,
+	resources.KubeStateMetricsKubeconfigSecretName,
+	resources.MetricsServerKubeconfigSecretName,
+	resources.InternalUserClusterAdminKubeconfigSecretName,
+	resources.KubernetesDashboardKubeconfigSecretName,
+	resources.ClusterAutoscalerKubeconfigSecretName,
+	resources.CloudControllerManagerKubeconfigSecretName,
+)
+
+// Reconciler rotates the certificates of the cluster control planes
+type Reconciler struct {
+	ctrlruntimeclient.Client
+	log               *zap.SugaredLogger
+	workerName        string
+	rotationThreshold time.Duration
+	recorder          record.EventRecorder
+}
+
+// Add creates a new certificate controller and adds it to the given manager
+func Add(
+	log *zap.SugaredLogger,
+	mgr manager.Manager,
+	numWorkers int,
+	workerName string,
+	rotationThreshold time.Duration,
+) error {
+	log = log.Named(ControllerName)
+	reconciler := &Reconciler{
+		Client:            mgr.GetClient(),
+		log:               log,
+		workerName:        workerName,
+		rotationThreshold: rotationThreshold,
+		recorder:          mgr.GetEventRecorderFor(ControllerName),
+	}
+
+	c, err := controller.New(ControllerName, mgr, controller.Options{Reconciler: reconciler, MaxConcurrentReconciles: numWorkers})
+	if err != nil {
+		return err
+	}
+
+	typesToWatch := []runtime.Object{
+		&corev1.Secret{},
+		// Deployments and StatefulSets are watched to continue a root CA rotation once they rolled out
+		&appsv1.Deployment{},
+		&appsv1.StatefulSet{},
+	}
+
+	for _, t := range typesToWatch {
+		if err := c.Watch(&source.Kind{Type: t}, controllerutil.EnqueueClusterForNamespacedObject(mgr.GetClient())); err != nil {
+			return fmt.Errorf("failed to create watcher for %T: %v", t, err)
+		}
+	}
+
+	return c.Watch(&source.Kind{Type: &kubermaticv1.Cluster{}}, &handler.EnqueueRequestForObject{})
+}
+
+func (r *Reconciler) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	log := r.log.With("request", request)
+	log.Debug("Processing")
+
+	cluster := &kubermaticv1.Cluster{}
+	if err := r.Get(ctx, request.NamespacedName, cluster); err != nil {
+		if kerrors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+	log = log.With("cluster", cluster.Name)
+
+	if cluster.Status.NamespaceName == "" || cluster.DeletionTimestamp != nil {
+		log.Debug("Skipping because the cluster has no namespace or is being deleted")
+		return reconcile.Result{}, nil
+	}
+
+	// Add a wrapping here so we can emit an event on error
+	_, err := kubermaticv1helper.ClusterReconcileWrapper(
+		ctx,
+		r.Client,
+		r.workerName,
+		cluster,
+		kubermaticv1.ClusterConditionCertificateControllerReconcilingSuccess,
+		func() (*reconcile.Result, error) {
+			return nil, r.reconcile(ctx, log, cluster)
+		},
+	)
+	if err != nil {
+		log.Errorw("Reconciling failed", zap.Error(err))
+		r.recorder.Event(cluster, corev1.EventTypeWarning, "ReconcilingError", err.Error())
+	}
+
+	return reconcile.Result{}, err
+}
+
+func (r *Reconciler) reconcile(ctx context.Context, log *zap.SugaredLogger, cluster *kubermaticv1.Cluster) error {
+	secrets := &corev1.SecretList{}
+	if err := r.List(ctx, secrets, ctrlruntimeclient.InNamespace(cluster.Status.NamespaceName)); err != nil {
+		return fmt.Errorf("failed to list secrets: %v", err)
+	}
+
+	if err := r.rotateLeafCertificates(ctx, log, cluster, secrets.Items); err != nil {
+		return err
+	}
+
+	if err := r.reconcileRootCARotation(ctx, log, cluster); err != nil {
+		return fmt.Errorf("failed to rotate the root CA: %v", err)
+	}
+
+	r.checkRootCAExpiry(cluster, secrets.Items)
+	return nil
+}
+
+// rotateLeafCertificates removes all leaf certificates which expire within the rotation threshold
+// from the rotatable secrets, so they get reissued by the cluster controllers.
+func (r *Reconciler) rotateLeafCertificates(ctx context.Context, log *zap.SugaredLogger, cluster *kubermaticv1.Cluster, secrets []corev1.Secret) error {
+	cas := getCACertificates(secrets)
+
+	for i := range secrets {
+		secret := &secrets[i]
+		if !rotatableSecrets.Has(secret.Name) {
+			continue
+		}
+
+		var expiring []string
+		for _, c := range certificates.GetSecretCertificates(secret) {
+			if c.Cert.IsCA || time.Until(c.Cert.NotAfter) > r.rotationThreshold || !isSignedByAnyOf(c.Cert, cas) {
+				continue
+			}
+			expiring = append(expiring, c.Key)
+		}
+		if len(expiring) == 0 {
+			continue
+		}
+
+		oldSecret := secret.DeepCopy()
+		for _, key := range expiring {
+			delete(secret.Data, key)
+		}
+		if err := r.Patch(ctx, secret, ctrlruntimeclient.MergeFrom(oldSecret)); err != nil {
+			return fmt.Errorf("failed to remove expiring certificates from secret %s: %v", secret.Name, err)
+		}
+
+		log.Infow("Rotating expiring certificates", "secret", secret.Name, "keys", expiring)
+		r.recorder.Eventf(cluster, corev1.EventTypeNormal, "CertificateRotation", "Rotating expiring certificates %s of secret %s", strings.Join(expiring, ", "), secret.Name)
+	}
+
+	return nil
+}
+
+// checkRootCAExpiry emits a warning if the root CA expires soon, as its rotation needs to be
+// triggered manually.
+func (r *Reconciler) checkRootCAExpiry(cluster *kubermaticv1.Cluster, secrets []corev1.Secret) {
+	for i := range secrets {
+		if secrets[i].Name != resources.CASecretName {
+			continue
+		}
+		for _, c := range certificates.GetSecretCertificates(&secrets[i]) {
+			if c.Cert.IsCA && time.Until(c.Cert.NotAfter) < rootCAExpiryWarningThreshold {
+				r.recorder.Eventf(cluster, corev1.EventTypeWarning, "RootCAExpiring", "The root CA %s expires at %s and needs to be rotated", c.Cert.Subject.CommonName, c.Cert.NotAfter.UTC().Format(time.RFC3339))
+			}
+		}
+	}
+}
+
+// getCACertificates returns all CA certificates stored in the given secrets
+func getCACertificates(secrets []corev1.Secret) []*x509.Certificate {
+	var cas []*x509.Certificate
+	for i := range secrets {
+		for _, c := range certificates.GetSecretCertificates(&secrets[i]) {
+			if c.Cert.IsCA {
+				cas = append(cas, c.Cert)
+			}
+		}
+	}
+	return cas
+}
+
+func isSignedByAnyOf(cert *x509.Certificate, cas []*x509.Certificate) bool {
+	for _, ca := range cas {
+		if cert.CheckSignatureFrom(ca) == nil {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,352 @@
+/*
+Copyright 2020 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificates
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"testing"
+	"time"
+
+	kubermaticv1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
+	kubermaticv1helper "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1/helper"
+	kubermaticlog "github.com/kubermatic/kubermatic/api/pkg/log"
+	"github.com/kubermatic/kubermatic/api/pkg/resources"
+	"github.com/kubermatic/kubermatic/api/pkg/resources/certificates/triple"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	certutil "k8s.io/client-go/util/cert"
+	utilpointer "k8s.io/utils/pointer"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlruntimefakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const testNamespace = "cluster-test"
+
+func newTestCA(t *testing.T, commonName string) *triple.KeyPair {
+	ca, err := triple.NewCA(commonName)
+	if err != nil {
+		t.Fatalf("failed to create CA: %v", err)
+	}
+	return ca
+}
+
+func newTestCert(t *testing.T, ca *triple.KeyPair, commonName string, validFor time.Duration) []byte {
+	key, err := triple.NewPrivateKey()
+	if err != nil {
+		t.Fatalf("failed to create key: %v", err)
+	}
+	template := x509.Certificate{
+		Subject:      pkix.Name{CommonName: commonName},
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(validFor),
+		KeyUsage:     x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, &template, ca.Cert, key.Public(), ca.Key)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("failed to parse certificate: %v", err)
+	}
+	return triple.EncodeCertPEM(cert)
+}
+
+func newTestSecret(name string, data map[string][]byte) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       testNamespace,
+			Name:            name,
+			ResourceVersion: "5",
+		},
+		Data: data,
+	}
+}
+
+func newTestCluster(rotationStep, annotation string) *kubermaticv1.Cluster {
+	cluster := &kubermaticv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "test",
+			Annotations: map[string]string{},
+		},
+	}
+	cluster.Status.NamespaceName = testNamespace
+	cluster.Address.ExternalName = "test.example.com"
+	if annotation != "" {
+		cluster.Annotations[kubermaticv1.RootCARotationAnnotation] = annotation
+	}
+	if rotationStep != "" {
+		kubermaticv1helper.SetClusterCondition(cluster, kubermaticv1.ClusterConditionRootCARotation, corev1.ConditionFalse, rotationStep, "")
+	}
+	return cluster
+}
+
+func newTestDeployment(caRevision string, rolledOut bool) *appsv1.Deployment {
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: testNamespace,
+			Name:      resources.ApiserverDeploymentName,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: utilpointer.Int32Ptr(2),
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{"ca-secret-revision": caRevision},
+				},
+			},
+		},
+		Status: appsv1.DeploymentStatus{
+			Replicas:          2,
+			UpdatedReplicas:   2,
+			AvailableReplicas: 2,
+		},
+	}
+	if !rolledOut {
+		deployment.Status.UpdatedReplicas = 1
+	}
+	return deployment
+}
+
+func newTestReconciler(objects ...runtime.Object) *Reconciler {
+	return &Reconciler{
+		Client:            ctrlruntimefakeclient.NewFakeClient(objects...),
+		log:               kubermaticlog.New(true, kubermaticlog.FormatConsole).Sugar(),
+		rotationThreshold: DefaultRotationThreshold,
+		recorder:          record.NewFakeRecorder(100),
+	}
+}
+
+func getTestSecret(t *testing.T, client ctrlruntimeclient.Client, name string) *corev1.Secret {
+	secret := &corev1.Secret{}
+	if err := client.Get(context.Background(), types.NamespacedName{Namespace: testNamespace, Name: name}, secret); err != nil {
+		t.Fatalf("failed to get secret %s: %v", name, err)
+	}
+	return secret
+}
+
+func TestRotateLeafCertificates(t *testing.T) {
+	ca := newTestCA(t, "root-ca")
+	foreignCA := newTestCA(t, "foreign-ca")
+
+	caSecret := newTestSecret(resources.CASecretName, map[string][]byte{
+		resources.CACertSecretKey: triple.EncodeCertPEM(ca.Cert),
+		resources.CAKeySecretKey:  triple.EncodePrivateKeyPEM(ca.Key),
+	})
+	expiring := newTestCert(t, ca, "expiring", 7*24*time.Hour)
+	valid := newTestCert(t, ca, "valid", 300*24*time.Hour)
+	foreign := newTestCert(t, foreignCA, "foreign", 7*24*time.Hour)
+	tlsSecret := newTestSecret(resources.ApiserverTLSSecretName, map[string][]byte{
+		"expiring.crt":            expiring,
+		"valid.crt":               valid,
+		"foreign.crt":             foreign,
+		resources.CACertSecretKey: triple.EncodeCertPEM(ca.Cert),
+	})
+	userSecret := newTestSecret("user-provided", map[string][]byte{
+		"expiring.crt": expiring,
+	})
+
+	r := newTestReconciler(newTestCluster("", ""), caSecret, tlsSecret, userSecret)
+	if _, err := r.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}}); err != nil {
+		t.Fatalf("failed to reconcile: %v", err)
+	}
+
+	if secret := getTestSecret(t, r.Client, "user-provided"); len(secret.Data) != 1 {
+		t.Error("expected the certificate of a secret no cluster controller manages to be kept")
+	}
+	secret := getTestSecret(t, r.Client, resources.ApiserverTLSSecretName)
+	if _, exists := secret.Data["expiring.crt"]; exists {
+		t.Error("expected the expiring certificate to be removed")
+	}
+	for _, key := range []string{"valid.crt", "foreign.crt", resources.CACertSecretKey} {
+		if _, exists := secret.Data[key]; !exists {
+			t.Errorf("expected %s to be kept", key)
+		}
+	}
+	if secret := getTestSecret(t, r.Client, resources.CASecretName); len(secret.Data) != 2 {
+		t.Errorf("expected the CA secret to be untouched, got keys %v", secret.Data)
+	}
+}
+
+func TestRootCARotation(t *testing.T) {
+	oldCA := newTestCA(t, "root-ca-old")
+	newCA := newTestCA(t, "root-ca-new")
+
+	initialCASecret := func() *corev1.Secret {
+		return newTestSecret(resources.CASecretName, map[string][]byte{
+			resources.CACertSecretKey: triple.EncodeCertPEM(oldCA.Cert),
+			resources.CAKeySecretKey:  triple.EncodePrivateKeyPEM(oldCA.Key),
+		})
+	}
+	trustingCASecret := func() *corev1.Secret {
+		return newTestSecret(resources.CASecretName, map[string][]byte{
+			resources.CACertSecretKey:    encodeCertsPEM(oldCA.Cert, newCA.Cert),
+			resources.CAKeySecretKey:     triple.EncodePrivateKeyPEM(oldCA.Key),
+			resources.NextCAKeySecretKey: triple.EncodePrivateKeyPEM(newCA.Key),
+		})
+	}
+	signingCASecret := func() *corev1.Secret {
+		return newTestSecret(resources.CASecretName, map[string][]byte{
+			resources.CACertSecretKey: encodeCertsPEM(newCA.Cert, oldCA.Cert),
+			resources.CAKeySecretKey:  triple.EncodePrivateKeyPEM(newCA.Key),
+		})
+	}
+	leafSecret := func(ca *triple.KeyPair) *corev1.Secret {
+		return newTestSecret("tls", map[string][]byte{"tls.crt": newTestCert(t, ca, "leaf", 300*24*time.Hour)})
+	}
+
+	testCases := []struct {
+		name                string
+		cluster             *kubermaticv1.Cluster
+		objects             []runtime.Object
+		expectedStep        string
+		expectedCACerts     []*x509.Certificate
+		expectedCAKey       *triple.KeyPair
+		expectedNextCAKey   bool
+		expectedErrorString string
+	}{
+		{
+			name:              "trusts a new root CA",
+			cluster:           newTestCluster("", kubermaticv1.ReasonRootCATrustingNewCA),
+			objects:           []runtime.Object{initialCASecret()},
+			expectedStep:      kubermaticv1.ReasonRootCATrustingNewCA,
+			expectedCACerts:   []*x509.Certificate{oldCA.Cert, nil},
+			expectedCAKey:     oldCA,
+			expectedNextCAKey: true,
+		},
+		{
+			name:            "signs with the new root CA once the trust bundle got rolled out",
+			cluster:         newTestCluster(kubermaticv1.ReasonRootCATrustingNewCA, kubermaticv1.ReasonRootCASigningWithNewCA),
+			objects:         []runtime.Object{trustingCASecret(), newTestDeployment("5", true)},
+			expectedStep:    kubermaticv1.ReasonRootCASigningWithNewCA,
+			expectedCACerts: []*x509.Certificate{newCA.Cert, oldCA.Cert},
+			expectedCAKey:   newCA,
+		},
+		{
+			name:              "waits for the deployments to roll out",
+			cluster:           newTestCluster(kubermaticv1.ReasonRootCATrustingNewCA, kubermaticv1.ReasonRootCASigningWithNewCA),
+			objects:           []runtime.Object{trustingCASecret(), newTestDeployment("5", false)},
+			expectedStep:      kubermaticv1.ReasonRootCATrustingNewCA,
+			expectedCACerts:   []*x509.Certificate{oldCA.Cert, newCA.Cert},
+			expectedCAKey:     oldCA,
+			expectedNextCAKey: true,
+		},
+		{
+			name:              "waits for the deployments to mount the current root CA secret",
+			cluster:           newTestCluster(kubermaticv1.ReasonRootCATrustingNewCA, kubermaticv1.ReasonRootCASigningWithNewCA),
+			objects:           []runtime.Object{trustingCASecret(), newTestDeployment("4", true)},
+			expectedStep:      kubermaticv1.ReasonRootCATrustingNewCA,
+			expectedCACerts:   []*x509.Certificate{oldCA.Cert, newCA.Cert},
+			expectedCAKey:     oldCA,
+			expectedNextCAKey: true,
+		},
+		{
+			name:            "waits for the certificates to be reissued by the new root CA",
+			cluster:         newTestCluster(kubermaticv1.ReasonRootCASigningWithNewCA, kubermaticv1.ReasonRootCARemovedOldCA),
+			objects:         []runtime.Object{signingCASecret(), newTestDeployment("5", true), leafSecret(oldCA)},
+			expectedStep:    kubermaticv1.ReasonRootCASigningWithNewCA,
+			expectedCACerts: []*x509.Certificate{newCA.Cert, oldCA.Cert},
+			expectedCAKey:   newCA,
+		},
+		{
+			name:            "removes the old root CA",
+			cluster:         newTestCluster(kubermaticv1.ReasonRootCASigningWithNewCA, kubermaticv1.ReasonRootCARemovedOldCA),
+			objects:         []runtime.Object{signingCASecret(), newTestDeployment("5", true), leafSecret(newCA)},
+			expectedStep:    kubermaticv1.ReasonRootCARemovedOldCA,
+			expectedCACerts: []*x509.Certificate{newCA.Cert},
+			expectedCAKey:   newCA,
+		},
+		{
+			name:              "starts a new rotation after a finished one",
+			cluster:           newTestCluster(kubermaticv1.ReasonRootCARemovedOldCA, kubermaticv1.ReasonRootCATrustingNewCA),
+			objects:           []runtime.Object{initialCASecret()},
+			expectedStep:      kubermaticv1.ReasonRootCATrustingNewCA,
+			expectedCACerts:   []*x509.Certificate{oldCA.Cert, nil},
+			expectedCAKey:     oldCA,
+			expectedNextCAKey: true,
+		},
+		{
+			name:                "rejects an unknown step",
+			cluster:             newTestCluster("", "NewCA"),
+			objects:             []runtime.Object{initialCASecret()},
+			expectedCACerts:     []*x509.Certificate{oldCA.Cert},
+			expectedCAKey:       oldCA,
+			expectedErrorString: `failed to rotate the root CA: invalid value "NewCA" for annotation kubermatic.io/root-ca-rotation, must be one of TrustingNewCA, SigningWithNewCA, RemovedOldCA`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := newTestReconciler(append(tc.objects, tc.cluster)...)
+			_, err := r.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: tc.cluster.Name}})
+			if tc.expectedErrorString != "" {
+				if err == nil || err.Error() != tc.expectedErrorString {
+					t.Fatalf("expected error %q, got %v", tc.expectedErrorString, err)
+				}
+			} else if err != nil {
+				t.Fatalf("failed to reconcile: %v", err)
+			}
+
+			secret := getTestSecret(t, r.Client, resources.CASecretName)
+			certs, err := certutil.ParseCertsPEM(secret.Data[resources.CACertSecretKey])
+			if err != nil {
+				t.Fatalf("failed to parse the root CA certificates: %v", err)
+			}
+			if len(certs) != len(tc.expectedCACerts) {
+				t.Fatalf("expected %d root CA certificates, got %d", len(tc.expectedCACerts), len(certs))
+			}
+			for i, expected := range tc.expectedCACerts {
+				// nil stands for a newly created CA
+				if expected != nil && !certs[i].Equal(expected) {
+					t.Errorf("expected root CA certificate %d to be %s, got %s", i, expected.Subject.CommonName, certs[i].Subject.CommonName)
+				}
+				if expected == nil && (certs[i].Equal(oldCA.Cert) || certs[i].Equal(newCA.Cert)) {
+					t.Errorf("expected root CA certificate %d to be newly created", i)
+				}
+			}
+			if string(secret.Data[resources.CAKeySecretKey]) != string(triple.EncodePrivateKeyPEM(tc.expectedCAKey.Key)) {
+				t.Error("the root CA key does not match the expected one")
+			}
+			if _, exists := secret.Data[resources.NextCAKeySecretKey]; exists != tc.expectedNextCAKey {
+				t.Errorf("expected the key of the next root CA to exist: %t, got %t", tc.expectedNextCAKey, exists)
+			}
+
+			cluster := &kubermaticv1.Cluster{}
+			if err := r.Get(context.Background(), types.NamespacedName{Name: tc.cluster.Name}, cluster); err != nil {
+				t.Fatalf("failed to get cluster: %v", err)
+			}
+			var step string
+			if _, condition := kubermaticv1helper.GetClusterCondition(cluster, kubermaticv1.ClusterConditionRootCARotation); condition != nil {
+				step = condition.Reason
+			}
+			if step != tc.expectedStep {
+				t.Errorf("expected rotation step %q, got %q", tc.expectedStep, step)
+			}
+		})
+	}
+}
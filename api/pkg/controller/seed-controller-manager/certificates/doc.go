@@ -0,0 +1,38 @@
+/*
+Copyright 2020 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package certificates contains a controller that keeps the certificates of the cluster control planes
+from expiring.
+
+Leaf certificates which expire within the configured rotation threshold get removed from the secrets
+the cluster controllers manage, which makes them issue new ones. Only certificates that are signed by
+a CA of the cluster namespace are rotated, other secrets in the cluster namespace are never touched.
+
+The root CA of a cluster is never replaced automatically, instead a rotation is requested by setting
+the kubermatic.io/root-ca-rotation annotation on the cluster to the step the rotation should advance
+to. The steps are:
+
+  * TrustingNewCA: A new root CA gets created and is trusted in addition to the old one
+  * SigningWithNewCA: All certificates get reissued by the new root CA, the old one is still trusted
+  * RemovedOldCA: The old root CA is removed from the trust bundle
+
+A step is only applied after all control plane components rolled out the previous one. Between the
+steps, everything outside of the control plane that trusts the root CA, like the nodes and
+kubeconfigs handed out to users, needs to be updated. The progress is reflected by the
+RootCARotation condition of the cluster.
+*/
+package certificates
@@ -0,0 +1,275 @@
+/*
+Copyright 2020 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificates
+
+import (
+	"bytes"
+	"context"
+	"crypto/rsa"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	kubermaticv1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
+	kubermaticv1helper "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1/helper"
+	"github.com/kubermatic/kubermatic/api/pkg/resources"
+	"github.com/kubermatic/kubermatic/api/pkg/resources/certificates"
+	"github.com/kubermatic/kubermatic/api/pkg/resources/certificates/triple"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	certutil "k8s.io/client-go/util/cert"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// rootCARotationSteps are the steps of a root CA rotation in the order they are applied
+var rootCARotationSteps = []string{
+	kubermaticv1.ReasonRootCATrustingNewCA,
+	kubermaticv1.ReasonRootCASigningWithNewCA,
+	kubermaticv1.ReasonRootCARemovedOldCA,
+}
+
+var rootCARotationStepMessages = map[string]string{
+	kubermaticv1.ReasonRootCATrustingNewCA:    "The new root CA is trusted in addition to the old one. All nodes and kubeconfigs need to trust the new root CA before advancing to SigningWithNewCA.",
+	kubermaticv1.ReasonRootCASigningWithNewCA: "All certificates get reissued by the new root CA. All nodes and kubeconfigs need to use certificates of the new root CA before advancing to RemovedOldCA.",
+	kubermaticv1.ReasonRootCARemovedOldCA:     "The old root CA got removed.",
+}
+
+func rootCARotationStepIndex(step string) int {
+	for i, s := range rootCARotationSteps {
+		if s == step {
+			return i
+		}
+	}
+	return -1
+}
+
+// reconcileRootCARotation advances the root CA rotation by one step, if the rotation annotation
+// requests it and the previous step got rolled out.
+func (r *Reconciler) reconcileRootCARotation(ctx context.Context, log *zap.SugaredLogger, cluster *kubermaticv1.Cluster) error {
+	target, requested := cluster.Annotations[kubermaticv1.RootCARotationAnnotation]
+	if !requested {
+		return nil
+	}
+	targetIndex := rootCARotationStepIndex(target)
+	if targetIndex < 0 {
+		return fmt.Errorf("invalid value %q for annotation %s, must be one of %s", target, kubermaticv1.RootCARotationAnnotation, strings.Join(rootCARotationSteps, ", "))
+	}
+
+	currentIndex := -1
+	if _, condition := kubermaticv1helper.GetClusterCondition(cluster, kubermaticv1.ClusterConditionRootCARotation); condition != nil {
+		currentIndex = rootCARotationStepIndex(condition.Reason)
+	}
+	// A finished rotation is followed by a new one as soon as an earlier step gets requested
+	if currentIndex == len(rootCARotationSteps)-1 && targetIndex < currentIndex {
+		currentIndex = -1
+	}
+	if targetIndex <= currentIndex {
+		return nil
+	}
+
+	caSecret := &corev1.Secret{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: cluster.Status.NamespaceName, Name: resources.CASecretName}, caSecret); err != nil {
+		return fmt.Errorf("failed to get the root CA secret: %v", err)
+	}
+
+	if currentIndex >= 0 {
+		pending, err := r.pendingRollout(ctx, cluster, caSecret, rootCARotationSteps[currentIndex])
+		if err != nil {
+			return err
+		}
+		if pending != "" {
+			log.Debugw("Waiting for the previous root CA rotation step to roll out", "step", rootCARotationSteps[currentIndex], "pending", pending)
+			return nil
+		}
+	}
+
+	step := rootCARotationSteps[currentIndex+1]
+	oldCASecret := caSecret.DeepCopy()
+	if err := applyRootCARotationStep(cluster, caSecret, step); err != nil {
+		return fmt.Errorf("failed to apply step %s: %v", step, err)
+	}
+	if err := r.Patch(ctx, caSecret, ctrlruntimeclient.MergeFrom(oldCASecret)); err != nil {
+		return fmt.Errorf("failed to update the root CA secret: %v", err)
+	}
+
+	oldCluster := cluster.DeepCopy()
+	status := corev1.ConditionFalse
+	if step == kubermaticv1.ReasonRootCARemovedOldCA {
+		status = corev1.ConditionTrue
+	}
+	kubermaticv1helper.SetClusterCondition(cluster, kubermaticv1.ClusterConditionRootCARotation, status, step, rootCARotationStepMessages[step])
+	if err := r.Patch(ctx, cluster, ctrlruntimeclient.MergeFrom(oldCluster)); err != nil {
+		return fmt.Errorf("failed to update the %s condition: %v", kubermaticv1.ClusterConditionRootCARotation, err)
+	}
+
+	log.Infow("Advanced root CA rotation", "step", step)
+	r.recorder.Eventf(cluster, corev1.EventTypeNormal, "RootCARotation", "Root CA rotation advanced to step %s", step)
+	return nil
+}
+
+// pendingRollout returns a description of the first object in the cluster namespace that did not
+// roll out the given step yet or an empty string if the step got rolled out completely.
+func (r *Reconciler) pendingRollout(ctx context.Context, cluster *kubermaticv1.Cluster, caSecret *corev1.Secret, step string) (string, error) {
+	caRevisionLabel := fmt.Sprintf("%s-secret-revision", resources.CASecretName)
+
+	deployments := &appsv1.DeploymentList{}
+	if err := r.List(ctx, deployments, ctrlruntimeclient.InNamespace(cluster.Status.NamespaceName)); err != nil {
+		return "", fmt.Errorf("failed to list Deployments: %v", err)
+	}
+	for _, deployment := range deployments.Items {
+		revision, mountsCA := deployment.Spec.Template.Labels[caRevisionLabel]
+		if (mountsCA && revision != caSecret.ResourceVersion) || !isDeploymentRolledOut(&deployment) {
+			return fmt.Sprintf("Deployment %s", deployment.Name), nil
+		}
+	}
+
+	statefulSets := &appsv1.StatefulSetList{}
+	if err := r.List(ctx, statefulSets, ctrlruntimeclient.InNamespace(cluster.Status.NamespaceName)); err != nil {
+		return "", fmt.Errorf("failed to list StatefulSets: %v", err)
+	}
+	for _, set := range statefulSets.Items {
+		revision, mountsCA := set.Spec.Template.Labels[caRevisionLabel]
+		if (mountsCA && revision != caSecret.ResourceVersion) || !isStatefulSetRolledOut(&set) {
+			return fmt.Sprintf("StatefulSet %s", set.Name), nil
+		}
+	}
+
+	if step != kubermaticv1.ReasonRootCASigningWithNewCA {
+		return "", nil
+	}
+
+	// Before the old root CA gets removed, all certificates must be reissued by the new one
+	caCerts, err := certutil.ParseCertsPEM(caSecret.Data[resources.CACertSecretKey])
+	if err != nil {
+		return "", fmt.Errorf("failed to parse the root CA certificates: %v", err)
+	}
+	secrets := &corev1.SecretList{}
+	if err := r.List(ctx, secrets, ctrlruntimeclient.InNamespace(cluster.Status.NamespaceName)); err != nil {
+		return "", fmt.Errorf("failed to list secrets: %v", err)
+	}
+	for i := range secrets.Items {
+		for _, c := range certificates.GetSecretCertificates(&secrets.Items[i]) {
+			if c.Cert.IsCA {
+				continue
+			}
+			if isSignedByAnyOf(c.Cert, caCerts[1:]) {
+				return fmt.Sprintf("certificate %s of secret %s", c.Key, secrets.Items[i].Name), nil
+			}
+		}
+	}
+
+	return "", nil
+}
+
+func isDeploymentRolledOut(deployment *appsv1.Deployment) bool {
+	replicas := int32(1)
+	if deployment.Spec.Replicas != nil {
+		replicas = *deployment.Spec.Replicas
+	}
+	return deployment.Status.ObservedGeneration >= deployment.Generation &&
+		deployment.Status.UpdatedReplicas == replicas &&
+		deployment.Status.Replicas == replicas &&
+		deployment.Status.AvailableReplicas == replicas
+}
+
+func isStatefulSetRolledOut(set *appsv1.StatefulSet) bool {
+	replicas := int32(1)
+	if set.Spec.Replicas != nil {
+		replicas = *set.Spec.Replicas
+	}
+	return set.Status.ObservedGeneration >= set.Generation &&
+		set.Status.UpdatedReplicas == replicas &&
+		set.Status.ReadyReplicas == replicas &&
+		set.Status.CurrentRevision == set.Status.UpdateRevision
+}
+
+// applyRootCARotationStep changes the given root CA secret to the given rotation step. Applying
+// a step twice is a no-op.
+func applyRootCARotationStep(cluster *kubermaticv1.Cluster, secret *corev1.Secret, step string) error {
+	certs, err := certutil.ParseCertsPEM(secret.Data[resources.CACertSecretKey])
+	if err != nil {
+		return fmt.Errorf("failed to parse the root CA certificates: %v", err)
+	}
+
+	switch step {
+	case kubermaticv1.ReasonRootCATrustingNewCA:
+		if _, exists := secret.Data[resources.NextCAKeySecretKey]; exists {
+			return nil
+		}
+		newCA, err := triple.NewCA(fmt.Sprintf("root-ca-%s.%s", time.Now().UTC().Format("20060102"), cluster.Address.ExternalName))
+		if err != nil {
+			return fmt.Errorf("failed to create a new root CA: %v", err)
+		}
+		secret.Data[resources.CACertSecretKey] = encodeCertsPEM(certs[0], newCA.Cert)
+		secret.Data[resources.NextCAKeySecretKey] = triple.EncodePrivateKeyPEM(newCA.Key)
+
+	case kubermaticv1.ReasonRootCASigningWithNewCA:
+		keyPEM, exists := secret.Data[resources.NextCAKeySecretKey]
+		if !exists {
+			if len(certs) > 1 {
+				// The new root CA already signs the certificates
+				return nil
+			}
+			return errors.New("the key of the new root CA is missing")
+		}
+		key, err := triple.ParsePrivateKeyPEM(keyPEM)
+		if err != nil {
+			return fmt.Errorf("failed to parse the key of the new root CA: %v", err)
+		}
+		rsaKey, isRSAKey := key.(*rsa.PrivateKey)
+		if !isRSAKey {
+			return errors.New("the key of the new root CA is not a RSA key")
+		}
+
+		newCerts := []*x509.Certificate{}
+		for _, cert := range certs {
+			if publicKey, ok := cert.PublicKey.(*rsa.PublicKey); ok && publicKey.N.Cmp(rsaKey.N) == 0 && publicKey.E == rsaKey.E {
+				newCerts = append([]*x509.Certificate{cert}, newCerts...)
+			} else {
+				newCerts = append(newCerts, cert)
+			}
+		}
+		if newCerts[0] == certs[0] {
+			return errors.New("did not find the certificate of the new root CA")
+		}
+		secret.Data[resources.CACertSecretKey] = encodeCertsPEM(newCerts...)
+		secret.Data[resources.CAKeySecretKey] = keyPEM
+		delete(secret.Data, resources.NextCAKeySecretKey)
+
+	case kubermaticv1.ReasonRootCARemovedOldCA:
+		secret.Data[resources.CACertSecretKey] = encodeCertsPEM(certs[0])
+
+	default:
+		return fmt.Errorf("unknown step %q", step)
+	}
+
+	return nil
+}
+
+func encodeCertsPEM(certs ...*x509.Certificate) []byte {
+	var buf bytes.Buffer
+	for _, cert := range certs {
+		buf.Write(triple.EncodeCertPEM(cert))
+	}
+	return buf.Bytes()
+}
@@ -24,11 +24,15 @@ import (
 
 	kubermaticv1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
 	kubermaticv1helper "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1/helper"
+	"github.com/kubermatic/kubermatic/api/pkg/resources"
 
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
+	utilpointer "k8s.io/utils/pointer"
 	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
@@ -129,6 +133,13 @@ func (r *Reconciler) reconcile(log *zap.SugaredLogger, cluster *kubermaticv1.Clu
 	if targetComponentsOverride.Scheduler.Resources == nil {
 		targetComponentsOverride.Scheduler.Resources = r.defaults.Scheduler.Resources
 	}
+	if targetComponentsOverride.Etcd.ClusterSize == nil {
+		clusterSize, err := r.etcdClusterSize(cluster)
+		if err != nil {
+			return err
+		}
+		targetComponentsOverride.Etcd.ClusterSize = clusterSize
+	}
 	if targetComponentsOverride.Etcd.Resources == nil {
 		targetComponentsOverride.Etcd.Resources = r.defaults.Etcd.Resources
 	}
@@ -148,3 +159,23 @@ func (r *Reconciler) reconcile(log *zap.SugaredLogger, cluster *kubermaticv1.Clu
 	log.Info("Successfully defaulted componentsOverride")
 	return nil
 }
+
+// etcdClusterSize returns the etcd cluster size to default to. The configured default only
+// applies to clusters without etcd, clusters that already run etcd keep their size.
+func (r *Reconciler) etcdClusterSize(cluster *kubermaticv1.Cluster) (*int32, error) {
+	if cluster.Status.NamespaceName == "" {
+		return r.defaults.Etcd.ClusterSize, nil
+	}
+	set := &appsv1.StatefulSet{}
+	key := types.NamespacedName{Namespace: cluster.Status.NamespaceName, Name: resources.EtcdStatefulSetName}
+	if err := r.client.Get(r.ctx, key, set); err != nil {
+		if kerrors.IsNotFound(err) {
+			return r.defaults.Etcd.ClusterSize, nil
+		}
+		return nil, fmt.Errorf("failed to get etcd StatefulSet: %v", err)
+	}
+	if set.Spec.Replicas == nil {
+		return utilpointer.Int32Ptr(resources.DefaultEtcdClusterSize), nil
+	}
+	return utilpointer.Int32Ptr(*set.Spec.Replicas), nil
+}
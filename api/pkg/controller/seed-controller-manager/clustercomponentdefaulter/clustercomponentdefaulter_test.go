@@ -17,14 +17,17 @@ limitations under the License.
 package clustercomponentdefaulter
 
 import (
+	"context"
 	"testing"
 
 	kubermaticv1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
+	"github.com/kubermatic/kubermatic/api/pkg/resources"
 	"k8s.io/apimachinery/pkg/types"
 
 	"github.com/go-test/deep"
 	"go.uber.org/zap"
 
+	appsv1 "k8s.io/api/apps/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilpointer "k8s.io/utils/pointer"
@@ -111,3 +114,56 @@ func TestReconciliation(t *testing.T) {
 		})
 	}
 }
+
+func TestEtcdClusterSizeDefaulting(t *testing.T) {
+	testCases := []struct {
+		name         string
+		statefulSet  *appsv1.StatefulSet
+		expectedSize int32
+	}{
+		{
+			name:         "New cluster gets the configured size",
+			expectedSize: 5,
+		},
+		{
+			name: "Existing cluster keeps its size",
+			statefulSet: &appsv1.StatefulSet{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "cluster-" + clusterName, Name: resources.EtcdStatefulSetName},
+				Spec:       appsv1.StatefulSetSpec{Replicas: utilpointer.Int32Ptr(3)},
+			},
+			expectedSize: 3,
+		},
+		{
+			name: "Existing cluster without replicas gets the default size",
+			statefulSet: &appsv1.StatefulSet{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "cluster-" + clusterName, Name: resources.EtcdStatefulSetName},
+			},
+			expectedSize: resources.DefaultEtcdClusterSize,
+		},
+	}
+
+	logger := zap.NewExample().Sugar()
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cluster := exampleCluster(exampleSettings(nil, true))
+			cluster.Status.NamespaceName = "cluster-" + clusterName
+			objects := []runtime.Object{cluster}
+			if tc.statefulSet != nil {
+				objects = append(objects, tc.statefulSet)
+			}
+			client := fake.NewFakeClient(objects...)
+			defaults := kubermaticv1.ComponentSettings{Etcd: kubermaticv1.EtcdStatefulSetSettings{ClusterSize: utilpointer.Int32Ptr(5)}}
+			r := &Reconciler{ctx: context.Background(), client: client, log: logger, defaults: defaults}
+			if err := r.reconcile(logger, cluster); err != nil {
+				t.Fatalf("failed to reconcile cluster: %v", err)
+			}
+			reconciledCluster := &kubermaticv1.Cluster{}
+			if err := r.client.Get(r.ctx, types.NamespacedName{Name: clusterName}, reconciledCluster); err != nil {
+				t.Fatalf("failed to get reconciledCluster: %v", err)
+			}
+			if size := reconciledCluster.Spec.ComponentsOverride.Etcd.ClusterSize; size == nil || *size != tc.expectedSize {
+				t.Errorf("expected etcd cluster size %d, got %v", tc.expectedSize, size)
+			}
+		})
+	}
+}
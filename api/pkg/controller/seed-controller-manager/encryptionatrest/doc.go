@@ -0,0 +1,37 @@
+/*
+Copyright 2020 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package encryptionatrest contains a controller that manages the keys which encrypt the Secrets in
+the etcd of user clusters.
+
+The apiserver encryption configuration is created by the cluster controller with a single key once
+the encryption gets enabled. As soon as all apiservers use it, this controller rewrites all Secrets
+of the user cluster, so the ones created before get encrypted as well.
+
+A key rotation is requested with the kubermatic.io/rotate-encryption-key annotation and happens in
+these steps, each one only after all apiservers use the configuration of the previous one:
+
+  * The new key is added, so all apiservers are able to decrypt Secrets with it
+  * The new key is used to encrypt Secrets
+  * All Secrets get rewritten with the new key
+  * The old key is removed once the key retention passed
+
+The progress is reflected in the encryptionAtRest status of the cluster. Restoring an etcd backup
+which was taken before a key rotation requires the old key, so the key retention, which is set with
+the --encryption-key-retention flag, should be longer than the etcd backup retention.
+*/
+package encryptionatrest
@@ -0,0 +1,324 @@
+/*
+Copyright 2020 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package encryptionatrest
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	k8cuserclusterclient "github.com/kubermatic/kubermatic/api/pkg/cluster/client"
+	controllerutil "github.com/kubermatic/kubermatic/api/pkg/controller/util"
+	kubermaticv1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
+	kubermaticv1helper "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1/helper"
+	"github.com/kubermatic/kubermatic/api/pkg/resources"
+	"github.com/kubermatic/kubermatic/api/pkg/resources/apiserver"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	// ControllerName is the name of this controller
+	ControllerName = "kubermatic_encryption_at_rest_controller"
+
+	// DefaultKeyRetention is the default time old keys are kept after a rotation completed
+	DefaultKeyRetention = 30 * 24 * time.Hour
+)
+
+// userClusterConnectionProvider offers functions to retrieve clients for the given user clusters
+type userClusterConnectionProvider interface {
+	GetClient(*kubermaticv1.Cluster, ...k8cuserclusterclient.ConfigOption) (ctrlruntimeclient.Client, error)
+}
+
+// Reconciler manages the keys which encrypt the Secrets in the etcd of user clusters
+type Reconciler struct {
+	ctrlruntimeclient.Client
+	log                     *zap.SugaredLogger
+	workerName              string
+	userClusterConnProvider userClusterConnectionProvider
+	recorder                record.EventRecorder
+	keyRetention            time.Duration
+}
+
+// Add creates a new encryption at rest controller and adds it to the given manager
+func Add(
+	log *zap.SugaredLogger,
+	mgr manager.Manager,
+	numWorkers int,
+	workerName string,
+	userClusterConnProvider userClusterConnectionProvider,
+	keyRetention time.Duration,
+) error {
+	log = log.Named(ControllerName)
+	reconciler := &Reconciler{
+		Client:                  mgr.GetClient(),
+		log:                     log,
+		workerName:              workerName,
+		userClusterConnProvider: userClusterConnProvider,
+		recorder:                mgr.GetEventRecorderFor(ControllerName),
+		keyRetention:            keyRetention,
+	}
+
+	c, err := controller.New(ControllerName, mgr, controller.Options{Reconciler: reconciler, MaxConcurrentReconciles: numWorkers})
+	if err != nil {
+		return err
+	}
+
+	// The encryption configuration and the apiserver Deployment are watched to continue as soon as
+	// all apiservers use the current configuration
+	for _, t := range []runtime.Object{&corev1.Secret{}, &appsv1.Deployment{}} {
+		if err := c.Watch(&source.Kind{Type: t}, controllerutil.EnqueueClusterForNamespacedObject(mgr.GetClient())); err != nil {
+			return fmt.Errorf("failed to create watcher for %T: %v", t, err)
+		}
+	}
+
+	return c.Watch(&source.Kind{Type: &kubermaticv1.Cluster{}}, &handler.EnqueueRequestForObject{})
+}
+
+func (r *Reconciler) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	log := r.log.With("request", request)
+	log.Debug("Processing")
+
+	cluster := &kubermaticv1.Cluster{}
+	if err := r.Get(ctx, request.NamespacedName, cluster); err != nil {
+		if kerrors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+	log = log.With("cluster", cluster.Name)
+
+	if !cluster.IsKubernetes() || !apiserver.IsEncryptionAtRestEnabled(cluster) || cluster.DeletionTimestamp != nil {
+		log.Debug("Skipping because the cluster does not use encryption at rest or is being deleted")
+		return reconcile.Result{}, nil
+	}
+
+	// Add a wrapping here so we can emit an event on error
+	result, err := kubermaticv1helper.ClusterReconcileWrapper(
+		ctx,
+		r.Client,
+		r.workerName,
+		cluster,
+		kubermaticv1.ClusterConditionEncryptionAtRestControllerReconcilingSuccess,
+		func() (*reconcile.Result, error) {
+			return r.reconcile(ctx, log, cluster)
+		},
+	)
+	if err != nil {
+		log.Errorw("Reconciling failed", zap.Error(err))
+		r.recorder.Event(cluster, corev1.EventTypeWarning, "ReconcilingError", err.Error())
+	}
+	if result == nil {
+		result = &reconcile.Result{}
+	}
+
+	return *result, err
+}
+
+func (r *Reconciler) reconcile(ctx context.Context, log *zap.SugaredLogger, cluster *kubermaticv1.Cluster) (*reconcile.Result, error) {
+	secret := &corev1.Secret{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: cluster.Status.NamespaceName, Name: resources.EncryptionConfigurationSecretName}, secret); err != nil {
+		if kerrors.IsNotFound(err) {
+			log.Debug("Waiting for the encryption configuration to be created")
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get the encryption configuration: %v", err)
+	}
+	keys, err := apiserver.GetEncryptionKeys(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &kubermaticv1.EncryptionAtRestStatus{}
+	if cluster.Status.EncryptionAtRest != nil {
+		status = cluster.Status.EncryptionAtRest.DeepCopy()
+	}
+	_, rotationRequested := cluster.Annotations[kubermaticv1.EncryptionKeyRotationAnnotation]
+
+	switch {
+	case status.Phase == "" || (status.Phase == kubermaticv1.EncryptionAtRestPhaseActive && status.ActiveKey != keys[0].Name):
+		// The encryption just got enabled or the configuration was changed by someone else
+		status.Phase = kubermaticv1.EncryptionAtRestPhasePending
+		return nil, r.updateCluster(ctx, cluster, status, false)
+
+	case status.Phase == kubermaticv1.EncryptionAtRestPhaseActive && !rotationRequested:
+		if len(keys) == 1 {
+			return nil, nil
+		}
+		// All Secrets are encrypted with the active key, but etcd backups taken before the
+		// rotation still need the old keys. They are removed once the retention passed.
+		if status.ActiveSince == nil {
+			now := metav1.Now()
+			status.ActiveSince = &now
+			return nil, r.updateCluster(ctx, cluster, status, false)
+		}
+		if remaining := r.keyRetention - time.Since(status.ActiveSince.Time); remaining > 0 {
+			log.Debugw("Keeping old encryption keys until the retention passed", "remaining", remaining)
+			return &reconcile.Result{RequeueAfter: remaining}, nil
+		}
+		if err := r.updateKeys(ctx, secret, keys[:1]); err != nil {
+			return nil, err
+		}
+		log.Infow("Removed old encryption keys", "key", keys[0].Name)
+		return nil, nil
+	}
+
+	rolledOut, err := r.apiserverRolledOut(ctx, cluster, secret)
+	if err != nil {
+		return nil, err
+	}
+	if !rolledOut {
+		log.Debugw("Waiting for the apiservers to use the current encryption configuration", "phase", status.Phase)
+		return nil, nil
+	}
+
+	switch status.Phase {
+	case kubermaticv1.EncryptionAtRestPhaseActive:
+		// A rotation was requested, add the new key. It is only used to decrypt Secrets until
+		// every apiserver knows it.
+		key, err := apiserver.NewEncryptionKey(cluster.Spec.EncryptionAtRest.Provider)
+		if err != nil {
+			return nil, err
+		}
+		if err := r.updateKeys(ctx, secret, append(keys, key)); err != nil {
+			return nil, err
+		}
+		log.Infow("Added new encryption key", "key", key.Name)
+		r.recorder.Eventf(cluster, corev1.EventTypeNormal, "EncryptionKeyRotation", "Added encryption key %s", key.Name)
+		status.Phase = kubermaticv1.EncryptionAtRestPhaseKeyAdded
+		return nil, r.updateCluster(ctx, cluster, status, true)
+
+	case kubermaticv1.EncryptionAtRestPhaseKeyAdded:
+		// Every apiserver knows the new key, so it can be used for encryption. Key names contain
+		// their creation time, so the new key is the one with the greatest name.
+		newKey := 0
+		for i := range keys {
+			if keys[i].Name > keys[newKey].Name {
+				newKey = i
+			}
+		}
+		orderedKeys := []apiserver.EncryptionKey{keys[newKey]}
+		orderedKeys = append(orderedKeys, keys[:newKey]...)
+		orderedKeys = append(orderedKeys, keys[newKey+1:]...)
+		if err := r.updateKeys(ctx, secret, orderedKeys); err != nil {
+			return nil, err
+		}
+		log.Infow("Encrypting with new encryption key", "key", keys[newKey].Name)
+		status.Phase = kubermaticv1.EncryptionAtRestPhasePending
+		return nil, r.updateCluster(ctx, cluster, status, false)
+
+	case kubermaticv1.EncryptionAtRestPhasePending:
+		// Every apiserver encrypts with the first key, rewrite all Secrets to encrypt them with it
+		if err := r.encryptSecrets(ctx, cluster); err != nil {
+			return nil, err
+		}
+		log.Infow("Encrypted all secrets", "key", keys[0].Name)
+		r.recorder.Eventf(cluster, corev1.EventTypeNormal, "EncryptionKeyActive", "All secrets are encrypted with key %s", keys[0].Name)
+		status.Phase = kubermaticv1.EncryptionAtRestPhaseActive
+		status.ActiveKey = keys[0].Name
+		now := metav1.Now()
+		status.ActiveSince = &now
+		return nil, r.updateCluster(ctx, cluster, status, false)
+	}
+
+	return nil, fmt.Errorf("unknown encryption at rest phase %q", status.Phase)
+}
+
+// apiserverRolledOut returns whether all apiservers use the given encryption configuration
+func (r *Reconciler) apiserverRolledOut(ctx context.Context, cluster *kubermaticv1.Cluster, secret *corev1.Secret) (bool, error) {
+	deployment := &appsv1.Deployment{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: cluster.Status.NamespaceName, Name: resources.ApiserverDeploymentName}, deployment); err != nil {
+		if kerrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to get the apiserver Deployment: %v", err)
+	}
+
+	revisionLabel := fmt.Sprintf("%s-secret-revision", resources.EncryptionConfigurationSecretName)
+	if deployment.Spec.Template.Labels[revisionLabel] != secret.ResourceVersion {
+		return false, nil
+	}
+
+	replicas := int32(1)
+	if deployment.Spec.Replicas != nil {
+		replicas = *deployment.Spec.Replicas
+	}
+	return deployment.Status.ObservedGeneration >= deployment.Generation &&
+		deployment.Status.UpdatedReplicas == replicas &&
+		deployment.Status.Replicas == replicas &&
+		deployment.Status.AvailableReplicas == replicas, nil
+}
+
+// encryptSecrets rewrites all Secrets of the user cluster, which makes the apiserver encrypt
+// them with its current key
+func (r *Reconciler) encryptSecrets(ctx context.Context, cluster *kubermaticv1.Cluster) error {
+	userClusterClient, err := r.userClusterConnProvider.GetClient(cluster)
+	if err != nil {
+		return fmt.Errorf("failed to get user cluster client: %v", err)
+	}
+
+	secrets := &corev1.SecretList{}
+	if err := userClusterClient.List(ctx, secrets); err != nil {
+		return fmt.Errorf("failed to list user cluster secrets: %v", err)
+	}
+	for i := range secrets.Items {
+		// A conflict means the Secret got written in the meantime and is encrypted already
+		if err := userClusterClient.Update(ctx, &secrets.Items[i]); err != nil && !kerrors.IsNotFound(err) && !kerrors.IsConflict(err) {
+			return fmt.Errorf("failed to rewrite secret %s/%s: %v", secrets.Items[i].Namespace, secrets.Items[i].Name, err)
+		}
+	}
+	return nil
+}
+
+func (r *Reconciler) updateKeys(ctx context.Context, secret *corev1.Secret, keys []apiserver.EncryptionKey) error {
+	oldSecret := secret.DeepCopy()
+	if err := apiserver.SetEncryptionKeys(secret, keys); err != nil {
+		return err
+	}
+	if err := r.Patch(ctx, secret, ctrlruntimeclient.MergeFrom(oldSecret)); err != nil {
+		return fmt.Errorf("failed to update the encryption configuration: %v", err)
+	}
+	return nil
+}
+
+func (r *Reconciler) updateCluster(ctx context.Context, cluster *kubermaticv1.Cluster, status *kubermaticv1.EncryptionAtRestStatus, rotationStarted bool) error {
+	oldCluster := cluster.DeepCopy()
+	cluster.Status.EncryptionAtRest = status
+	if rotationStarted {
+		delete(cluster.Annotations, kubermaticv1.EncryptionKeyRotationAnnotation)
+	}
+	if err := r.Patch(ctx, cluster, ctrlruntimeclient.MergeFrom(oldCluster)); err != nil {
+		return fmt.Errorf("failed to update the encryption at rest status: %v", err)
+	}
+	return nil
+}
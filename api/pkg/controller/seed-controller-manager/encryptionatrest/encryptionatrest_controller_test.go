@@ -0,0 +1,275 @@
+/*
+Copyright 2020 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package encryptionatrest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	k8cuserclusterclient "github.com/kubermatic/kubermatic/api/pkg/cluster/client"
+	kubermaticv1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
+	kubermaticlog "github.com/kubermatic/kubermatic/api/pkg/log"
+	"github.com/kubermatic/kubermatic/api/pkg/resources"
+	"github.com/kubermatic/kubermatic/api/pkg/resources/apiserver"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	utilpointer "k8s.io/utils/pointer"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlruntimefakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const testNamespace = "cluster-test"
+
+var (
+	oldKey = apiserver.EncryptionKey{Name: "key-20200101000000", Provider: kubermaticv1.EncryptionAtRestProviderAESCBC, Secret: "b2xk"}
+	newKey = apiserver.EncryptionKey{Name: "key-20200601000000", Provider: kubermaticv1.EncryptionAtRestProviderAESCBC, Secret: "bmV3"}
+
+	recently = metav1.NewTime(time.Now().Add(-time.Hour).Truncate(time.Second))
+	longAgo  = metav1.NewTime(time.Now().Add(-2 * DefaultKeyRetention).Truncate(time.Second))
+)
+
+type fakeUserClusterConnectionProvider struct {
+	client ctrlruntimeclient.Client
+}
+
+func (f *fakeUserClusterConnectionProvider) GetClient(_ *kubermaticv1.Cluster, _ ...k8cuserclusterclient.ConfigOption) (ctrlruntimeclient.Client, error) {
+	return f.client, nil
+}
+
+func newTestCluster(status *kubermaticv1.EncryptionAtRestStatus, rotationRequested bool) *kubermaticv1.Cluster {
+	cluster := &kubermaticv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "test",
+			Annotations: map[string]string{},
+		},
+	}
+	cluster.Spec.EncryptionAtRest = &kubermaticv1.EncryptionAtRestSettings{Enabled: true}
+	cluster.Status.NamespaceName = testNamespace
+	cluster.Status.EncryptionAtRest = status
+	if rotationRequested {
+		cluster.Annotations[kubermaticv1.EncryptionKeyRotationAnnotation] = "true"
+	}
+	return cluster
+}
+
+func newTestSecret(t *testing.T, keys ...apiserver.EncryptionKey) *corev1.Secret {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       testNamespace,
+			Name:            resources.EncryptionConfigurationSecretName,
+			ResourceVersion: "5",
+		},
+	}
+	if err := apiserver.SetEncryptionKeys(secret, keys); err != nil {
+		t.Fatalf("failed to set encryption keys: %v", err)
+	}
+	return secret
+}
+
+func newTestDeployment(revision string) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: testNamespace,
+			Name:      resources.ApiserverDeploymentName,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: utilpointer.Int32Ptr(2),
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{"apiserver-encryption-configuration-secret-revision": revision},
+				},
+			},
+		},
+		Status: appsv1.DeploymentStatus{
+			Replicas:          2,
+			UpdatedReplicas:   2,
+			AvailableReplicas: 2,
+		},
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	testCases := []struct {
+		name              string
+		cluster           *kubermaticv1.Cluster
+		objects           []runtime.Object
+		expectedStatus    kubermaticv1.EncryptionAtRestStatus
+		expectedKeys      []string
+		expectedRewritten bool
+		expectedRotation  bool
+		expectedRequeue   bool
+	}{
+		{
+			name:           "marks a new configuration as pending",
+			cluster:        newTestCluster(nil, false),
+			objects:        []runtime.Object{newTestSecret(t, oldKey), newTestDeployment("4")},
+			expectedStatus: kubermaticv1.EncryptionAtRestStatus{Phase: kubermaticv1.EncryptionAtRestPhasePending},
+			expectedKeys:   []string{oldKey.Name},
+		},
+		{
+			name:           "waits for the apiservers to use the configuration",
+			cluster:        newTestCluster(&kubermaticv1.EncryptionAtRestStatus{Phase: kubermaticv1.EncryptionAtRestPhasePending}, false),
+			objects:        []runtime.Object{newTestSecret(t, oldKey), newTestDeployment("4")},
+			expectedStatus: kubermaticv1.EncryptionAtRestStatus{Phase: kubermaticv1.EncryptionAtRestPhasePending},
+			expectedKeys:   []string{oldKey.Name},
+		},
+		{
+			name:              "encrypts all secrets once the apiservers use the configuration",
+			cluster:           newTestCluster(&kubermaticv1.EncryptionAtRestStatus{Phase: kubermaticv1.EncryptionAtRestPhasePending}, false),
+			objects:           []runtime.Object{newTestSecret(t, oldKey), newTestDeployment("5")},
+			expectedStatus:    kubermaticv1.EncryptionAtRestStatus{Phase: kubermaticv1.EncryptionAtRestPhaseActive, ActiveKey: oldKey.Name, ActiveSince: &metav1.Time{}},
+			expectedKeys:      []string{oldKey.Name},
+			expectedRewritten: true,
+		},
+		{
+			name:           "adds a new key when a rotation is requested",
+			cluster:        newTestCluster(&kubermaticv1.EncryptionAtRestStatus{Phase: kubermaticv1.EncryptionAtRestPhaseActive, ActiveKey: oldKey.Name}, true),
+			objects:        []runtime.Object{newTestSecret(t, oldKey), newTestDeployment("5")},
+			expectedStatus: kubermaticv1.EncryptionAtRestStatus{Phase: kubermaticv1.EncryptionAtRestPhaseKeyAdded, ActiveKey: oldKey.Name},
+			expectedKeys:   []string{oldKey.Name, ""},
+		},
+		{
+			name:             "waits with the rotation until the apiservers use the configuration",
+			cluster:          newTestCluster(&kubermaticv1.EncryptionAtRestStatus{Phase: kubermaticv1.EncryptionAtRestPhaseActive, ActiveKey: oldKey.Name}, true),
+			objects:          []runtime.Object{newTestSecret(t, oldKey), newTestDeployment("4")},
+			expectedStatus:   kubermaticv1.EncryptionAtRestStatus{Phase: kubermaticv1.EncryptionAtRestPhaseActive, ActiveKey: oldKey.Name},
+			expectedKeys:     []string{oldKey.Name},
+			expectedRotation: true,
+		},
+		{
+			name:           "encrypts with the new key once all apiservers know it",
+			cluster:        newTestCluster(&kubermaticv1.EncryptionAtRestStatus{Phase: kubermaticv1.EncryptionAtRestPhaseKeyAdded, ActiveKey: oldKey.Name}, false),
+			objects:        []runtime.Object{newTestSecret(t, oldKey, newKey), newTestDeployment("5")},
+			expectedStatus: kubermaticv1.EncryptionAtRestStatus{Phase: kubermaticv1.EncryptionAtRestPhasePending, ActiveKey: oldKey.Name},
+			expectedKeys:   []string{newKey.Name, oldKey.Name},
+		},
+		{
+			name:              "encrypts all secrets with the new key",
+			cluster:           newTestCluster(&kubermaticv1.EncryptionAtRestStatus{Phase: kubermaticv1.EncryptionAtRestPhasePending, ActiveKey: oldKey.Name}, false),
+			objects:           []runtime.Object{newTestSecret(t, newKey, oldKey), newTestDeployment("5")},
+			expectedStatus:    kubermaticv1.EncryptionAtRestStatus{Phase: kubermaticv1.EncryptionAtRestPhaseActive, ActiveKey: newKey.Name, ActiveSince: &metav1.Time{}},
+			expectedKeys:      []string{newKey.Name, oldKey.Name},
+			expectedRewritten: true,
+		},
+		{
+			name:           "keeps the old key when the activation time is unknown",
+			cluster:        newTestCluster(&kubermaticv1.EncryptionAtRestStatus{Phase: kubermaticv1.EncryptionAtRestPhaseActive, ActiveKey: newKey.Name}, false),
+			objects:        []runtime.Object{newTestSecret(t, newKey, oldKey), newTestDeployment("5")},
+			expectedStatus: kubermaticv1.EncryptionAtRestStatus{Phase: kubermaticv1.EncryptionAtRestPhaseActive, ActiveKey: newKey.Name, ActiveSince: &metav1.Time{}},
+			expectedKeys:   []string{newKey.Name, oldKey.Name},
+		},
+		{
+			name:            "keeps the old key until the retention passed",
+			cluster:         newTestCluster(&kubermaticv1.EncryptionAtRestStatus{Phase: kubermaticv1.EncryptionAtRestPhaseActive, ActiveKey: newKey.Name, ActiveSince: &recently}, false),
+			objects:         []runtime.Object{newTestSecret(t, newKey, oldKey), newTestDeployment("5")},
+			expectedStatus:  kubermaticv1.EncryptionAtRestStatus{Phase: kubermaticv1.EncryptionAtRestPhaseActive, ActiveKey: newKey.Name, ActiveSince: &recently},
+			expectedKeys:    []string{newKey.Name, oldKey.Name},
+			expectedRequeue: true,
+		},
+		{
+			name:           "removes the old key once the retention passed",
+			cluster:        newTestCluster(&kubermaticv1.EncryptionAtRestStatus{Phase: kubermaticv1.EncryptionAtRestPhaseActive, ActiveKey: newKey.Name, ActiveSince: &longAgo}, false),
+			objects:        []runtime.Object{newTestSecret(t, newKey, oldKey), newTestDeployment("5")},
+			expectedStatus: kubermaticv1.EncryptionAtRestStatus{Phase: kubermaticv1.EncryptionAtRestPhaseActive, ActiveKey: newKey.Name, ActiveSince: &longAgo},
+			expectedKeys:   []string{newKey.Name},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			userClusterSecret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:       metav1.NamespaceDefault,
+					Name:            "credentials",
+					ResourceVersion: "1",
+				},
+			}
+			userClusterClient := ctrlruntimefakeclient.NewFakeClient(userClusterSecret)
+			r := &Reconciler{
+				Client:                  ctrlruntimefakeclient.NewFakeClient(append(tc.objects, tc.cluster)...),
+				log:                     kubermaticlog.New(true, kubermaticlog.FormatConsole).Sugar(),
+				userClusterConnProvider: &fakeUserClusterConnectionProvider{client: userClusterClient},
+				recorder:                record.NewFakeRecorder(10),
+				keyRetention:            DefaultKeyRetention,
+			}
+
+			result, err := r.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: tc.cluster.Name}})
+			if err != nil {
+				t.Fatalf("failed to reconcile: %v", err)
+			}
+			if requeue := result.RequeueAfter > 0; requeue != tc.expectedRequeue {
+				t.Errorf("expected requeue: %t, got %t", tc.expectedRequeue, requeue)
+			}
+
+			cluster := &kubermaticv1.Cluster{}
+			if err := r.Get(ctx, types.NamespacedName{Name: tc.cluster.Name}, cluster); err != nil {
+				t.Fatalf("failed to get cluster: %v", err)
+			}
+			if !statusMatches(tc.expectedStatus, cluster.Status.EncryptionAtRest) {
+				t.Errorf("expected status %+v, got %+v", tc.expectedStatus, cluster.Status.EncryptionAtRest)
+			}
+			if _, rotationRequested := cluster.Annotations[kubermaticv1.EncryptionKeyRotationAnnotation]; rotationRequested != tc.expectedRotation {
+				t.Errorf("expected rotation annotation to exist: %t, got %t", tc.expectedRotation, rotationRequested)
+			}
+
+			secret := &corev1.Secret{}
+			if err := r.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: resources.EncryptionConfigurationSecretName}, secret); err != nil {
+				t.Fatalf("failed to get encryption configuration: %v", err)
+			}
+			keys, err := apiserver.GetEncryptionKeys(secret)
+			if err != nil {
+				t.Fatalf("failed to get encryption keys: %v", err)
+			}
+			if len(keys) != len(tc.expectedKeys) {
+				t.Fatalf("expected %d keys, got %d", len(tc.expectedKeys), len(keys))
+			}
+			for i, name := range tc.expectedKeys {
+				// An empty name stands for a newly created key
+				if (name != "" && keys[i].Name != name) || (name == "" && (keys[i].Name == oldKey.Name || keys[i].Name == newKey.Name)) {
+					t.Errorf("expected key %d to be %q, got %q", i, name, keys[i].Name)
+				}
+			}
+
+			if err := userClusterClient.Get(ctx, types.NamespacedName{Namespace: metav1.NamespaceDefault, Name: "credentials"}, userClusterSecret); err != nil {
+				t.Fatalf("failed to get user cluster secret: %v", err)
+			}
+			if rewritten := userClusterSecret.ResourceVersion != "1"; rewritten != tc.expectedRewritten {
+				t.Errorf("expected user cluster secret to be rewritten: %t, got %t", tc.expectedRewritten, rewritten)
+			}
+		})
+	}
+}
+
+// statusMatches compares the given status with the expected one. An empty expected ActiveSince
+// matches any set time, as it is set to the current time by the controller.
+func statusMatches(expected kubermaticv1.EncryptionAtRestStatus, status *kubermaticv1.EncryptionAtRestStatus) bool {
+	if status == nil || status.Phase != expected.Phase || status.ActiveKey != expected.ActiveKey {
+		return false
+	}
+	if expected.ActiveSince == nil || status.ActiveSince == nil {
+		return expected.ActiveSince == nil && status.ActiveSince == nil
+	}
+	return expected.ActiveSince.IsZero() || expected.ActiveSince.Equal(status.ActiveSince)
+}
@@ -0,0 +1,32 @@
+/*
+Copyright 2020 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package etcdrestore contains a controller that restores the etcd of a user cluster from a backup snapshot.
+
+A restore is driven by an EtcdRestore object in the cluster namespace and happens in these steps:
+
+  * The cluster gets paused so no other controller touches the control plane
+  * The apiserver is scaled down to zero
+  * The etcd StatefulSet is deleted while its volumes are kept
+  * One Job per etcd member downloads the snapshot and restores it into the member's data volume
+  * The cluster gets resumed, which recreates etcd and the apiserver
+  * The restore is completed as soon as etcd and the apiserver are available again
+
+Every step is reflected by a condition on the EtcdRestore. If a step fails, the cluster stays paused
+for manual inspection. Deleting the EtcdRestore resumes a cluster it still has paused.
+*/
+package etcdrestore
@@ -0,0 +1,715 @@
+/*
+Copyright 2020 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etcdrestore
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	backupcontroller "github.com/kubermatic/kubermatic/api/pkg/controller/seed-controller-manager/backup"
+	kubermaticv1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
+	kuberneteshelper "github.com/kubermatic/kubermatic/api/pkg/kubernetes"
+	"github.com/kubermatic/kubermatic/api/pkg/provider"
+	"github.com/kubermatic/kubermatic/api/pkg/resources"
+	"github.com/kubermatic/kubermatic/api/pkg/resources/etcd"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/record"
+	utilpointer "k8s.io/utils/pointer"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	ControllerName = "kubermatic_etcd_restore_controller"
+
+	// ActiveRestoreAnnotationName is set on the cluster while a restore is in progress. It holds the
+	// name of the EtcdRestore and makes sure only one restore per cluster is running at a time.
+	ActiveRestoreAnnotationName = "kubermatic.io/active-etcd-restore"
+	// resumeClusterFinalizer makes sure the cluster gets resumed when an EtcdRestore is deleted
+	// while it still has the cluster paused
+	resumeClusterFinalizer = "kubermatic.io/resume-cluster"
+	// restoreJobLabel defines the label we use on all restore jobs
+	restoreJobLabel = "kubermatic-etcd-restore"
+	// restoreJobPrefix defines the prefix used for all restore job names
+	restoreJobPrefix = "etcd-restore"
+	// dataVolumeName is the name of the volume claim template of the etcd StatefulSet
+	dataVolumeName = "data"
+	// dataVolumeMountPath is the path the etcd StatefulSet mounts its data volume to
+	dataVolumeMountPath = "/var/run/etcd"
+	// clusterEnvVarKey defines the environment variable key for the cluster name
+	clusterEnvVarKey = "CLUSTER"
+	// backupNameEnvVarKey defines the environment variable key for the snapshot to download
+	backupNameEnvVarKey = "BACKUP_NAME"
+	// pollInterval is used to wait for resources the controller does not watch
+	pollInterval = 10 * time.Second
+)
+
+// Reconciler restores the etcd of user clusters from a backup
+type Reconciler struct {
+	log        *zap.SugaredLogger
+	workerName string
+	// restoreContainer downloads the snapshot into the shared volume
+	restoreContainer corev1.Container
+	// etcdImage is the image used to restore the snapshot into the etcd data volumes
+	etcdImage  string
+	seedGetter provider.SeedGetter
+
+	ctrlruntimeclient.Client
+	recorder record.EventRecorder
+}
+
+// Add creates a new etcd restore controller that is responsible for processing EtcdRestore objects
+func Add(
+	log *zap.SugaredLogger,
+	mgr manager.Manager,
+	numWorkers int,
+	workerName string,
+	restoreContainer corev1.Container,
+	etcdImage string,
+	seedGetter provider.SeedGetter,
+) error {
+	log = log.Named(ControllerName)
+	if err := validateRestoreContainer(restoreContainer); err != nil {
+		return err
+	}
+	if etcdImage == "" {
+		etcdImage = backupcontroller.DefaultBackupContainerImage
+	}
+
+	reconciler := &Reconciler{
+		log:              log,
+		workerName:       workerName,
+		restoreContainer: restoreContainer,
+		etcdImage:        etcdImage,
+		seedGetter:       seedGetter,
+		Client:           mgr.GetClient(),
+		recorder:         mgr.GetEventRecorderFor(ControllerName),
+	}
+	c, err := controller.New(ControllerName, mgr, controller.Options{
+		Reconciler:              reconciler,
+		MaxConcurrentReconciles: numWorkers,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create controller: %v", err)
+	}
+
+	if err := c.Watch(&source.Kind{Type: &kubermaticv1.EtcdRestore{}}, &handler.EnqueueRequestForObject{}); err != nil {
+		return fmt.Errorf("failed to watch EtcdRestores: %v", err)
+	}
+	ownerHandler := &handler.EnqueueRequestForOwner{OwnerType: &kubermaticv1.EtcdRestore{}, IsController: true}
+	if err := c.Watch(&source.Kind{Type: &batchv1.Job{}}, ownerHandler); err != nil {
+		return fmt.Errorf("failed to watch Jobs: %v", err)
+	}
+
+	return nil
+}
+
+func (r *Reconciler) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	log := r.log.With("request", request)
+	log.Debug("Processing")
+
+	restore := &kubermaticv1.EtcdRestore{}
+	if err := r.Get(ctx, request.NamespacedName, restore); err != nil {
+		if kerrors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	if restore.DeletionTimestamp == nil && restoreFinished(restore) {
+		return reconcile.Result{}, nil
+	}
+
+	cluster := &kubermaticv1.Cluster{}
+	if err := r.Get(ctx, types.NamespacedName{Name: restore.Spec.Cluster.Name}, cluster); err != nil {
+		if kerrors.IsNotFound(err) {
+			log.Debugw("Skipping restore of non-existing cluster", "cluster", restore.Spec.Cluster.Name)
+			return reconcile.Result{}, r.removeFinalizer(ctx, restore)
+		}
+		return reconcile.Result{}, err
+	}
+
+	if cluster.Labels[kubermaticv1.WorkerNameLabelKey] != r.workerName {
+		return reconcile.Result{}, nil
+	}
+
+	if restore.DeletionTimestamp != nil {
+		if err := r.cleanupDeletedRestore(ctx, log.With("cluster", cluster.Name), restore, cluster); err != nil {
+			log.Errorw("Cleaning up deleted restore failed", zap.Error(err))
+			r.recorder.Eventf(restore, corev1.EventTypeWarning, "ReconcilingError", "%v", err)
+			return reconcile.Result{}, err
+		}
+		return reconcile.Result{}, nil
+	}
+
+	result, err := r.reconcile(ctx, log.With("cluster", cluster.Name), restore, cluster)
+	if err != nil {
+		log.Errorw("Reconciling failed", zap.Error(err))
+		r.recorder.Eventf(restore, corev1.EventTypeWarning, "ReconcilingError", "%v", err)
+	}
+	if result == nil {
+		result = &reconcile.Result{}
+	}
+	return *result, err
+}
+
+func (r *Reconciler) reconcile(ctx context.Context, log *zap.SugaredLogger, restore *kubermaticv1.EtcdRestore, cluster *kubermaticv1.Cluster) (*reconcile.Result, error) {
+	if cluster.DeletionTimestamp != nil {
+		return nil, r.fail(ctx, restore, kubermaticv1.EtcdRestoreConditionClusterPaused, "ClusterDeleted", "cluster is being deleted")
+	}
+
+	if activeRestore, ok := cluster.Annotations[ActiveRestoreAnnotationName]; ok && activeRestore != restore.Name {
+		log.Infow("Waiting for other restore to finish", "active-restore", activeRestore)
+		return &reconcile.Result{RequeueAfter: pollInterval}, nil
+	}
+
+	// Always add the finalizer before the cluster gets paused
+	if !kuberneteshelper.HasFinalizer(restore, resumeClusterFinalizer) {
+		oldRestore := restore.DeepCopy()
+		kuberneteshelper.AddFinalizer(restore, resumeClusterFinalizer)
+		if err := r.Patch(ctx, restore, ctrlruntimeclient.MergeFrom(oldRestore)); err != nil {
+			return nil, fmt.Errorf("failed to add finalizer: %v", err)
+		}
+	}
+
+	steps := []struct {
+		condition kubermaticv1.EtcdRestoreConditionType
+		step      func(context.Context, *zap.SugaredLogger, *kubermaticv1.EtcdRestore, *kubermaticv1.Cluster) (*reconcile.Result, error)
+	}{
+		{kubermaticv1.EtcdRestoreConditionClusterPaused, r.pauseCluster},
+		{kubermaticv1.EtcdRestoreConditionApiserverScaledDown, r.scaleDownApiserver},
+		{kubermaticv1.EtcdRestoreConditionEtcdStatefulSetRemoved, r.removeEtcdStatefulSet},
+		{kubermaticv1.EtcdRestoreConditionSnapshotRestored, r.restoreSnapshot},
+		{kubermaticv1.EtcdRestoreConditionClusterResumed, r.resumeCluster},
+		{kubermaticv1.EtcdRestoreConditionClusterHealthy, r.waitForHealthyCluster},
+	}
+
+	for _, s := range steps {
+		if restore.Status.HasConditionValue(s.condition, corev1.ConditionTrue) {
+			continue
+		}
+
+		if restore.Status.Phase != kubermaticv1.EtcdRestorePhaseRunning {
+			if err := r.updateStatus(ctx, restore, func(restore *kubermaticv1.EtcdRestore) {
+				restore.Status.Phase = kubermaticv1.EtcdRestorePhaseRunning
+			}); err != nil {
+				return nil, err
+			}
+		}
+
+		log := log.With("step", s.condition)
+		log.Debug("Processing restore step")
+		result, err := s.step(ctx, log, restore, cluster)
+		if err != nil || result != nil || restoreFinished(restore) {
+			return result, err
+		}
+
+		if err := r.updateStatus(ctx, restore, func(restore *kubermaticv1.EtcdRestore) {
+			setRestoreCondition(restore, s.condition, corev1.ConditionTrue, "", "")
+		}); err != nil {
+			return nil, err
+		}
+		r.recorder.Eventf(restore, corev1.EventTypeNormal, string(s.condition), "Restore step %s completed", s.condition)
+	}
+
+	log.Info("Restore completed")
+	r.recorder.Eventf(cluster, corev1.EventTypeNormal, "EtcdRestoreCompleted", "Restored etcd from backup %s", restore.Spec.BackupName)
+	return nil, r.updateStatus(ctx, restore, func(restore *kubermaticv1.EtcdRestore) {
+		restore.Status.Phase = kubermaticv1.EtcdRestorePhaseCompleted
+	})
+}
+
+func (r *Reconciler) pauseCluster(ctx context.Context, log *zap.SugaredLogger, restore *kubermaticv1.EtcdRestore, cluster *kubermaticv1.Cluster) (*reconcile.Result, error) {
+	if cluster.Spec.Pause && cluster.Annotations[ActiveRestoreAnnotationName] == restore.Name {
+		return nil, nil
+	}
+	if cluster.Spec.Pause {
+		return nil, r.fail(ctx, restore, kubermaticv1.EtcdRestoreConditionClusterPaused, "ClusterAlreadyPaused", "cluster has been paused by someone else")
+	}
+
+	oldCluster := cluster.DeepCopy()
+	if cluster.Annotations == nil {
+		cluster.Annotations = map[string]string{}
+	}
+	cluster.Annotations[ActiveRestoreAnnotationName] = restore.Name
+	cluster.Spec.Pause = true
+	cluster.Spec.PauseReason = fmt.Sprintf("etcd restore %s in progress", restore.Name)
+	if err := r.Patch(ctx, cluster, ctrlruntimeclient.MergeFrom(oldCluster)); err != nil {
+		return nil, fmt.Errorf("failed to pause cluster: %v", err)
+	}
+	log.Info("Paused cluster")
+	return nil, nil
+}
+
+// cleanupDeletedRestore resumes the cluster if the deleted restore still has it paused, either because it
+// was deleted mid-restore or because it failed, and removes the finalizer afterwards.
+func (r *Reconciler) cleanupDeletedRestore(ctx context.Context, log *zap.SugaredLogger, restore *kubermaticv1.EtcdRestore, cluster *kubermaticv1.Cluster) error {
+	if !kuberneteshelper.HasFinalizer(restore, resumeClusterFinalizer) {
+		return nil
+	}
+	if cluster.Annotations[ActiveRestoreAnnotationName] == restore.Name {
+		if _, err := r.resumeCluster(ctx, log, restore, cluster); err != nil {
+			return err
+		}
+		r.recorder.Eventf(cluster, corev1.EventTypeNormal, "EtcdRestoreDeleted", "Resumed cluster after etcd restore %s was deleted", restore.Name)
+	}
+	return r.removeFinalizer(ctx, restore)
+}
+
+func (r *Reconciler) removeFinalizer(ctx context.Context, restore *kubermaticv1.EtcdRestore) error {
+	if !kuberneteshelper.HasFinalizer(restore, resumeClusterFinalizer) {
+		return nil
+	}
+	oldRestore := restore.DeepCopy()
+	kuberneteshelper.RemoveFinalizer(restore, resumeClusterFinalizer)
+	if err := r.Patch(ctx, restore, ctrlruntimeclient.MergeFrom(oldRestore)); err != nil {
+		return fmt.Errorf("failed to remove finalizer: %v", err)
+	}
+	return nil
+}
+
+func (r *Reconciler) scaleDownApiserver(ctx context.Context, log *zap.SugaredLogger, restore *kubermaticv1.EtcdRestore, cluster *kubermaticv1.Cluster) (*reconcile.Result, error) {
+	deployment := &appsv1.Deployment{}
+	name := types.NamespacedName{Namespace: cluster.Status.NamespaceName, Name: resources.ApiserverDeploymentName}
+	if err := r.Get(ctx, name, deployment); err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get apiserver deployment: %v", err)
+	}
+
+	if deployment.Spec.Replicas == nil || *deployment.Spec.Replicas != 0 {
+		oldDeployment := deployment.DeepCopy()
+		deployment.Spec.Replicas = utilpointer.Int32Ptr(0)
+		if err := r.Patch(ctx, deployment, ctrlruntimeclient.MergeFrom(oldDeployment)); err != nil {
+			return nil, fmt.Errorf("failed to scale down apiserver: %v", err)
+		}
+		log.Info("Scaled down apiserver")
+	}
+
+	if deployment.Status.Replicas != 0 {
+		log.Debugw("Waiting for apiserver pods to terminate", "replicas", deployment.Status.Replicas)
+		return &reconcile.Result{RequeueAfter: pollInterval}, nil
+	}
+	return nil, nil
+}
+
+func (r *Reconciler) removeEtcdStatefulSet(ctx context.Context, log *zap.SugaredLogger, restore *kubermaticv1.EtcdRestore, cluster *kubermaticv1.Cluster) (*reconcile.Result, error) {
+	statefulSet := &appsv1.StatefulSet{}
+	name := types.NamespacedName{Namespace: cluster.Status.NamespaceName, Name: resources.EtcdStatefulSetName}
+	if err := r.Get(ctx, name, statefulSet); err != nil {
+		if !kerrors.IsNotFound(err) {
+			return nil, fmt.Errorf("failed to get etcd StatefulSet: %v", err)
+		}
+	} else if statefulSet.DeletionTimestamp == nil {
+		// The volume claims of a StatefulSet are not deleted along with it, so the
+		// restore Jobs can mount them afterwards.
+		if err := r.Delete(ctx, statefulSet); err != nil && !kerrors.IsNotFound(err) {
+			return nil, fmt.Errorf("failed to delete etcd StatefulSet: %v", err)
+		}
+		log.Info("Deleted etcd StatefulSet")
+	}
+
+	pods := &corev1.PodList{}
+	listOpts := []ctrlruntimeclient.ListOption{
+		ctrlruntimeclient.InNamespace(cluster.Status.NamespaceName),
+		ctrlruntimeclient.MatchingLabels{resources.AppLabelKey: resources.EtcdStatefulSetName, resources.ClusterLabelKey: cluster.Name},
+	}
+	if err := r.List(ctx, pods, listOpts...); err != nil {
+		return nil, fmt.Errorf("failed to list etcd pods: %v", err)
+	}
+	if len(pods.Items) > 0 {
+		log.Debugw("Waiting for etcd pods to terminate", "pods", len(pods.Items))
+		return &reconcile.Result{RequeueAfter: pollInterval}, nil
+	}
+	return nil, nil
+}
+
+func (r *Reconciler) restoreSnapshot(ctx context.Context, log *zap.SugaredLogger, restore *kubermaticv1.EtcdRestore, cluster *kubermaticv1.Cluster) (*reconcile.Result, error) {
+	restoreContainer, err := r.restoreContainerForCluster(cluster)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.ensureSecrets(ctx, restore, cluster, restoreContainer); err != nil {
+		return nil, err
+	}
+
+	succeeded := 0
+	for member := 0; member < etcd.ClusterSize(cluster); member++ {
+		job := &batchv1.Job{}
+		desired := r.restoreJob(restore, cluster, restoreContainer, member)
+		if err := r.Get(ctx, types.NamespacedName{Namespace: desired.Namespace, Name: desired.Name}, job); err != nil {
+			if !kerrors.IsNotFound(err) {
+				return nil, fmt.Errorf("failed to get restore job %s: %v", desired.Name, err)
+			}
+			if err := r.Create(ctx, desired); err != nil {
+				return nil, fmt.Errorf("failed to create restore job %s: %v", desired.Name, err)
+			}
+			log.Infow("Created restore job", "job", desired.Name)
+			continue
+		}
+
+		if jobHasCondition(job, batchv1.JobFailed) {
+			message := fmt.Sprintf("restore job %s failed, cluster stays paused for inspection", job.Name)
+			return nil, r.fail(ctx, restore, kubermaticv1.EtcdRestoreConditionSnapshotRestored, "JobFailed", message)
+		}
+		if job.Status.Succeeded > 0 {
+			succeeded++
+		}
+	}
+
+	if succeeded < etcd.ClusterSize(cluster) {
+		log.Debugw("Waiting for restore jobs", "succeeded", succeeded)
+		// The Jobs are watched, no need to requeue.
+		return &reconcile.Result{}, nil
+	}
+
+	return nil, r.cleanup(ctx, restore, cluster, restoreContainer)
+}
+
+func (r *Reconciler) resumeCluster(ctx context.Context, log *zap.SugaredLogger, restore *kubermaticv1.EtcdRestore, cluster *kubermaticv1.Cluster) (*reconcile.Result, error) {
+	oldCluster := cluster.DeepCopy()
+	delete(cluster.Annotations, ActiveRestoreAnnotationName)
+	cluster.Spec.Pause = false
+	cluster.Spec.PauseReason = ""
+	if err := r.Patch(ctx, cluster, ctrlruntimeclient.MergeFrom(oldCluster)); err != nil {
+		return nil, fmt.Errorf("failed to resume cluster: %v", err)
+	}
+	log.Info("Resumed cluster")
+	return nil, nil
+}
+
+func (r *Reconciler) waitForHealthyCluster(ctx context.Context, log *zap.SugaredLogger, restore *kubermaticv1.EtcdRestore, cluster *kubermaticv1.Cluster) (*reconcile.Result, error) {
+	// The health in the cluster status is not updated while the cluster is paused, so
+	// we check the workloads directly.
+	statefulSet := &appsv1.StatefulSet{}
+	name := types.NamespacedName{Namespace: cluster.Status.NamespaceName, Name: resources.EtcdStatefulSetName}
+	if err := r.Get(ctx, name, statefulSet); err != nil {
+		if kerrors.IsNotFound(err) {
+			return &reconcile.Result{RequeueAfter: pollInterval}, nil
+		}
+		return nil, fmt.Errorf("failed to get etcd StatefulSet: %v", err)
+	}
+	if statefulSet.Status.ReadyReplicas < int32(etcd.ClusterSize(cluster)) {
+		log.Debugw("Waiting for etcd to become ready", "ready", statefulSet.Status.ReadyReplicas)
+		return &reconcile.Result{RequeueAfter: pollInterval}, nil
+	}
+
+	deployment := &appsv1.Deployment{}
+	name = types.NamespacedName{Namespace: cluster.Status.NamespaceName, Name: resources.ApiserverDeploymentName}
+	if err := r.Get(ctx, name, deployment); err != nil {
+		if kerrors.IsNotFound(err) {
+			return &reconcile.Result{RequeueAfter: pollInterval}, nil
+		}
+		return nil, fmt.Errorf("failed to get apiserver deployment: %v", err)
+	}
+	if deployment.Status.AvailableReplicas < 1 {
+		log.Debug("Waiting for apiserver to become available")
+		return &reconcile.Result{RequeueAfter: pollInterval}, nil
+	}
+	return nil, nil
+}
+
+// fail marks the restore as failed. The cluster is left paused on purpose.
+func (r *Reconciler) fail(ctx context.Context, restore *kubermaticv1.EtcdRestore, conditionType kubermaticv1.EtcdRestoreConditionType, reason, message string) error {
+	r.recorder.Event(restore, corev1.EventTypeWarning, reason, message)
+	return r.updateStatus(ctx, restore, func(restore *kubermaticv1.EtcdRestore) {
+		restore.Status.Phase = kubermaticv1.EtcdRestorePhaseFailed
+		setRestoreCondition(restore, conditionType, corev1.ConditionFalse, reason, message)
+	})
+}
+
+func (r *Reconciler) updateStatus(ctx context.Context, restore *kubermaticv1.EtcdRestore, modify func(*kubermaticv1.EtcdRestore)) error {
+	oldRestore := restore.DeepCopy()
+	modify(restore)
+	if err := r.Patch(ctx, restore, ctrlruntimeclient.MergeFrom(oldRestore)); err != nil {
+		return fmt.Errorf("failed to update restore status: %v", err)
+	}
+	return nil
+}
+
+// ensureSecrets copies the secrets the restore container references from kube-system, where the
+// backup containers get them from, into the cluster namespace.
+func (r *Reconciler) ensureSecrets(ctx context.Context, restore *kubermaticv1.EtcdRestore, cluster *kubermaticv1.Cluster, restoreContainer *corev1.Container) error {
+	for _, secretName := range referencedSecrets(*restoreContainer).List() {
+		existing := &corev1.Secret{}
+		err := r.Get(ctx, types.NamespacedName{Namespace: cluster.Status.NamespaceName, Name: secretName}, existing)
+		if err == nil {
+			continue
+		}
+		if !kerrors.IsNotFound(err) {
+			return fmt.Errorf("failed to get secret %s: %v", secretName, err)
+		}
+
+		source := &corev1.Secret{}
+		if err := r.Get(ctx, types.NamespacedName{Namespace: metav1.NamespaceSystem, Name: secretName}, source); err != nil {
+			return fmt.Errorf("failed to get secret %s/%s: %v", metav1.NamespaceSystem, secretName, err)
+		}
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            secretName,
+				Namespace:       cluster.Status.NamespaceName,
+				Labels:          map[string]string{resources.AppLabelKey: restoreJobLabel},
+				OwnerReferences: []metav1.OwnerReference{restoreOwnerRef(restore)},
+			},
+			Type: source.Type,
+			Data: source.Data,
+		}
+		if err := r.Create(ctx, secret); err != nil && !kerrors.IsAlreadyExists(err) {
+			return fmt.Errorf("failed to create secret %s: %v", secretName, err)
+		}
+	}
+	return nil
+}
+
+// cleanup removes the restore Jobs and the copied secrets.
+func (r *Reconciler) cleanup(ctx context.Context, restore *kubermaticv1.EtcdRestore, cluster *kubermaticv1.Cluster, restoreContainer *corev1.Container) error {
+	deletePropagationForeground := metav1.DeletePropagationForeground
+	delOpts := &ctrlruntimeclient.DeleteOptions{
+		PropagationPolicy: &deletePropagationForeground,
+	}
+	for member := 0; member < etcd.ClusterSize(cluster); member++ {
+		job := r.restoreJob(restore, cluster, restoreContainer, member)
+		if err := r.Delete(ctx, job, delOpts); err != nil && !kerrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete restore job %s: %v", job.Name, err)
+		}
+	}
+
+	for _, secretName := range referencedSecrets(*restoreContainer).List() {
+		secret := &corev1.Secret{}
+		if err := r.Get(ctx, types.NamespacedName{Namespace: cluster.Status.NamespaceName, Name: secretName}, secret); err != nil {
+			if kerrors.IsNotFound(err) {
+				continue
+			}
+			return fmt.Errorf("failed to get secret %s: %v", secretName, err)
+		}
+		// Never delete secrets we did not create
+		if !metav1.IsControlledBy(secret, restore) {
+			continue
+		}
+		if err := r.Delete(ctx, secret); err != nil && !kerrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete secret %s: %v", secretName, err)
+		}
+	}
+	return nil
+}
+
+// restoreContainerForCluster returns the restore container configured to download from
+// the backup destination of the cluster.
+func (r *Reconciler) restoreContainerForCluster(cluster *kubermaticv1.Cluster) (*corev1.Container, error) {
+	seed, err := r.seedGetter()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get seed: %v", err)
+	}
+	destination, err := backupcontroller.GetClusterBackupDestination(seed, cluster)
+	if err != nil {
+		return nil, err
+	}
+	restoreContainer := r.restoreContainer.DeepCopy()
+	backupcontroller.SetBackupDestinationEnv(restoreContainer, destination)
+	return restoreContainer, nil
+}
+
+func (r *Reconciler) restoreJob(restore *kubermaticv1.EtcdRestore, cluster *kubermaticv1.Cluster, baseContainer *corev1.Container, member int) *batchv1.Job {
+	restoreContainer := baseContainer.DeepCopy()
+	restoreContainer.Env = append(restoreContainer.Env,
+		corev1.EnvVar{
+			Name:  clusterEnvVarKey,
+			Value: cluster.Name,
+		},
+		corev1.EnvVar{
+			Name:  backupNameEnvVarKey,
+			Value: restore.Spec.BackupName,
+		},
+	)
+
+	image := r.etcdImage
+	if !strings.Contains(image, ":") {
+		image = image + ":" + etcd.ImageTag(cluster)
+	}
+	memberName := fmt.Sprintf("%s-%d", resources.EtcdStatefulSetName, member)
+
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-%s-%d", restoreJobPrefix, restore.Name, member),
+			Namespace: cluster.Status.NamespaceName,
+			Labels: map[string]string{
+				resources.AppLabelKey:     restoreJobLabel,
+				resources.ClusterLabelKey: cluster.Name,
+			},
+			OwnerReferences: []metav1.OwnerReference{restoreOwnerRef(restore)},
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: utilpointer.Int32Ptr(3),
+			Completions:  utilpointer.Int32Ptr(1),
+			Parallelism:  utilpointer.Int32Ptr(1),
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					InitContainers: []corev1.Container{*restoreContainer},
+					Containers: []corev1.Container{
+						{
+							Name:  "restore-snapshot",
+							Image: image,
+							Env: []corev1.EnvVar{
+								{
+									Name:  "ETCDCTL_API",
+									Value: "3",
+								},
+							},
+							Command: restoreCommand(cluster, memberName, member),
+							VolumeMounts: []corev1.VolumeMount{
+								{
+									Name:      backupcontroller.SharedVolumeName,
+									MountPath: "/backup",
+								},
+								{
+									Name:      dataVolumeName,
+									MountPath: dataVolumeMountPath,
+								},
+							},
+						},
+					},
+					RestartPolicy: corev1.RestartPolicyNever,
+					Volumes: []corev1.Volume{
+						{
+							Name: backupcontroller.SharedVolumeName,
+							VolumeSource: corev1.VolumeSource{
+								EmptyDir: &corev1.EmptyDirVolumeSource{},
+							},
+						},
+						{
+							Name: dataVolumeName,
+							VolumeSource: corev1.VolumeSource{
+								PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+									ClaimName: fmt.Sprintf("%s-%s", dataVolumeName, memberName),
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func restoreCommand(cluster *kubermaticv1.Cluster, memberName string, member int) []string {
+	var initialCluster []string
+	for i := 0; i < etcd.ClusterSize(cluster); i++ {
+		initialCluster = append(initialCluster, fmt.Sprintf("%s-%d=%s", resources.EtcdStatefulSetName, i, etcd.GetPeerEndpoint(cluster, i)))
+	}
+	dataDir := etcd.GetDataDir(memberName)
+
+	script := &strings.Builder{}
+	// Accordings to its godoc, this always returns a nil error
+	_, _ = script.WriteString(fmt.Sprintf("rm -rf %s\n", dataDir))
+	_, _ = script.WriteString(fmt.Sprintf(
+		"etcdctl snapshot restore /backup/snapshot.db --name %s --data-dir %s --initial-cluster %s --initial-cluster-token %s --initial-advertise-peer-urls %s",
+		memberName,
+		dataDir,
+		strings.Join(initialCluster, ","),
+		cluster.Name,
+		etcd.GetPeerEndpoint(cluster, member),
+	))
+
+	return []string{"/bin/sh", "-ec", script.String()}
+}
+
+func restoreOwnerRef(restore *kubermaticv1.EtcdRestore) metav1.OwnerReference {
+	return *metav1.NewControllerRef(restore, kubermaticv1.SchemeGroupVersion.WithKind(kubermaticv1.EtcdRestoreKindName))
+}
+
+func restoreFinished(restore *kubermaticv1.EtcdRestore) bool {
+	return restore.Status.Phase == kubermaticv1.EtcdRestorePhaseCompleted || restore.Status.Phase == kubermaticv1.EtcdRestorePhaseFailed
+}
+
+func jobHasCondition(job *batchv1.Job, conditionType batchv1.JobConditionType) bool {
+	for _, condition := range job.Status.Conditions {
+		if condition.Type == conditionType && condition.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
+// referencedSecrets returns the names of all secrets the container gets its environment from
+func referencedSecrets(container corev1.Container) sets.String {
+	secrets := sets.NewString()
+	for _, env := range container.Env {
+		if env.ValueFrom != nil && env.ValueFrom.SecretKeyRef != nil {
+			secrets.Insert(env.ValueFrom.SecretKeyRef.Name)
+		}
+	}
+	for _, envFrom := range container.EnvFrom {
+		if envFrom.SecretRef != nil {
+			secrets.Insert(envFrom.SecretRef.Name)
+		}
+	}
+	return secrets
+}
+
+func validateRestoreContainer(restoreContainer corev1.Container) error {
+	for _, volumeMount := range restoreContainer.VolumeMounts {
+		if volumeMount.Name == backupcontroller.SharedVolumeName {
+			return nil
+		}
+	}
+	return fmt.Errorf("restoreContainer does not have a mount for the shared volume %s", backupcontroller.SharedVolumeName)
+}
+
+func setRestoreCondition(restore *kubermaticv1.EtcdRestore, conditionType kubermaticv1.EtcdRestoreConditionType, status corev1.ConditionStatus, reason, message string) {
+	now := metav1.Now()
+	for i, condition := range restore.Status.Conditions {
+		if condition.Type != conditionType {
+			continue
+		}
+		if condition.Status != status {
+			condition.LastTransitionTime = now
+		}
+		condition.Status = status
+		condition.Reason = reason
+		condition.Message = message
+		condition.LastHeartbeatTime = now
+		restore.Status.Conditions[i] = condition
+		return
+	}
+
+	restore.Status.Conditions = append(restore.Status.Conditions, kubermaticv1.EtcdRestoreCondition{
+		Type:               conditionType,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		LastHeartbeatTime:  now,
+		LastTransitionTime: now,
+	})
+}
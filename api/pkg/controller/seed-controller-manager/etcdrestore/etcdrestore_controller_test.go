@@ -0,0 +1,322 @@
+/*
+Copyright 2020 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etcdrestore
+
+import (
+	"context"
+	"testing"
+
+	backupcontroller "github.com/kubermatic/kubermatic/api/pkg/controller/seed-controller-manager/backup"
+	kubermaticv1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
+	kubermaticlog "github.com/kubermatic/kubermatic/api/pkg/log"
+	"github.com/kubermatic/kubermatic/api/pkg/provider"
+	"github.com/kubermatic/kubermatic/api/pkg/resources"
+	"github.com/kubermatic/kubermatic/api/pkg/semver"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	utilpointer "k8s.io/utils/pointer"
+	ctrlruntimefakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var testRestoreContainer = corev1.Container{
+	Name:  "restore-container",
+	Image: "busybox",
+	Env: []corev1.EnvVar{
+		{
+			Name: "ACCESS_KEY_ID",
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "s3-credentials"},
+					Key:                  "ACCESS_KEY_ID",
+				},
+			},
+		},
+	},
+	VolumeMounts: []corev1.VolumeMount{{Name: backupcontroller.SharedVolumeName, MountPath: "/backup"}},
+}
+
+func TestRestore(t *testing.T) {
+	ctx := context.Background()
+	cluster := &kubermaticv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-cluster",
+		},
+		Spec: kubermaticv1.ClusterSpec{
+			Version: *semver.NewSemverOrDie("1.16.3"),
+		},
+		Status: kubermaticv1.ClusterStatus{
+			NamespaceName: "cluster-test-cluster",
+		},
+	}
+	restore := &kubermaticv1.EtcdRestore{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-restore",
+			Namespace: cluster.Status.NamespaceName,
+		},
+		Spec: kubermaticv1.EtcdRestoreSpec{
+			Cluster:    corev1.ObjectReference{Name: cluster.Name},
+			BackupName: "test-cluster-storeuploader-2020-01-01T00:00:00-snapshot.db",
+		},
+	}
+	apiserver := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      resources.ApiserverDeploymentName,
+			Namespace: cluster.Status.NamespaceName,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: utilpointer.Int32Ptr(2),
+		},
+	}
+	credentials := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "s3-credentials",
+			Namespace: metav1.NamespaceSystem,
+		},
+		Data: map[string][]byte{"ACCESS_KEY_ID": []byte("foo")},
+	}
+
+	reconciler := &Reconciler{
+		log:              kubermaticlog.New(true, kubermaticlog.FormatConsole).Sugar(),
+		restoreContainer: testRestoreContainer,
+		etcdImage:        backupcontroller.DefaultBackupContainerImage,
+		seedGetter:       testSeedGetter(&kubermaticv1.Seed{}),
+		Client:           ctrlruntimefakeclient.NewFakeClient(cluster, restore, apiserver, credentials),
+		recorder:         record.NewFakeRecorder(100),
+	}
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: restore.Namespace, Name: restore.Name}}
+
+	if _, err := reconciler.Reconcile(request); err != nil {
+		t.Fatalf("Error reconciling restore: %v", err)
+	}
+
+	if err := reconciler.Get(ctx, types.NamespacedName{Name: cluster.Name}, cluster); err != nil {
+		t.Fatalf("Failed to get cluster: %v", err)
+	}
+	if !cluster.Spec.Pause || cluster.Annotations[ActiveRestoreAnnotationName] != restore.Name {
+		t.Fatalf("Expected cluster to be paused by restore %q", restore.Name)
+	}
+	if err := reconciler.Get(ctx, types.NamespacedName{Namespace: apiserver.Namespace, Name: apiserver.Name}, apiserver); err != nil {
+		t.Fatalf("Failed to get apiserver: %v", err)
+	}
+	if *apiserver.Spec.Replicas != 0 {
+		t.Fatalf("Expected apiserver to be scaled down, has %d replicas", *apiserver.Spec.Replicas)
+	}
+
+	jobs := &batchv1.JobList{}
+	if err := reconciler.List(ctx, jobs); err != nil {
+		t.Fatalf("Failed to list jobs: %v", err)
+	}
+	if len(jobs.Items) != resources.DefaultEtcdClusterSize {
+		t.Fatalf("Expected %d restore jobs, got %d", resources.DefaultEtcdClusterSize, len(jobs.Items))
+	}
+	copiedSecret := &corev1.Secret{}
+	if err := reconciler.Get(ctx, types.NamespacedName{Namespace: cluster.Status.NamespaceName, Name: credentials.Name}, copiedSecret); err != nil {
+		t.Fatalf("Expected credentials to be copied into the cluster namespace: %v", err)
+	}
+
+	for _, job := range jobs.Items {
+		job.Status.Succeeded = 1
+		if err := reconciler.Update(ctx, &job); err != nil {
+			t.Fatalf("Failed to update job: %v", err)
+		}
+	}
+	if _, err := reconciler.Reconcile(request); err != nil {
+		t.Fatalf("Error reconciling restore: %v", err)
+	}
+
+	if err := reconciler.Get(ctx, types.NamespacedName{Name: cluster.Name}, cluster); err != nil {
+		t.Fatalf("Failed to get cluster: %v", err)
+	}
+	if cluster.Spec.Pause {
+		t.Fatal("Expected cluster to be resumed after the snapshot got restored")
+	}
+	if err := reconciler.List(ctx, jobs); err != nil {
+		t.Fatalf("Failed to list jobs: %v", err)
+	}
+	if len(jobs.Items) != 0 {
+		t.Fatalf("Expected restore jobs to be deleted, got %d", len(jobs.Items))
+	}
+
+	etcdStatefulSet := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      resources.EtcdStatefulSetName,
+			Namespace: cluster.Status.NamespaceName,
+		},
+		Status: appsv1.StatefulSetStatus{ReadyReplicas: resources.DefaultEtcdClusterSize},
+	}
+	if err := reconciler.Create(ctx, etcdStatefulSet); err != nil {
+		t.Fatalf("Failed to create etcd StatefulSet: %v", err)
+	}
+	apiserver.Status.AvailableReplicas = 1
+	if err := reconciler.Update(ctx, apiserver); err != nil {
+		t.Fatalf("Failed to update apiserver: %v", err)
+	}
+	if _, err := reconciler.Reconcile(request); err != nil {
+		t.Fatalf("Error reconciling restore: %v", err)
+	}
+
+	if err := reconciler.Get(ctx, request.NamespacedName, restore); err != nil {
+		t.Fatalf("Failed to get restore: %v", err)
+	}
+	if restore.Status.Phase != kubermaticv1.EtcdRestorePhaseCompleted {
+		t.Errorf("Expected restore to be completed, phase is %q", restore.Status.Phase)
+	}
+	if len(restore.Status.Conditions) != 6 {
+		t.Errorf("Expected all 6 restore steps to have a condition, got %d", len(restore.Status.Conditions))
+	}
+}
+
+func TestRestoreFailsOnFailedJob(t *testing.T) {
+	restore := &kubermaticv1.EtcdRestore{
+		Spec: kubermaticv1.EtcdRestoreSpec{
+			Cluster: corev1.ObjectReference{Name: "test-cluster"},
+		},
+	}
+	cluster := &kubermaticv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-cluster"},
+		Spec:       kubermaticv1.ClusterSpec{Version: *semver.NewSemverOrDie("1.16.3")},
+		Status:     kubermaticv1.ClusterStatus{NamespaceName: "cluster-test-cluster"},
+	}
+	restore.Name = "my-restore"
+	restore.Namespace = cluster.Status.NamespaceName
+
+	reconciler := &Reconciler{
+		log:              kubermaticlog.New(true, kubermaticlog.FormatConsole).Sugar(),
+		restoreContainer: corev1.Container{Name: "restore-container"},
+		etcdImage:        backupcontroller.DefaultBackupContainerImage,
+		seedGetter:       testSeedGetter(&kubermaticv1.Seed{}),
+		recorder:         record.NewFakeRecorder(10),
+	}
+	failedJob := reconciler.restoreJob(restore, cluster, &reconciler.restoreContainer, 1)
+	failedJob.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobFailed, Status: corev1.ConditionTrue}}
+	reconciler.Client = ctrlruntimefakeclient.NewFakeClient(restore, failedJob)
+
+	if _, err := reconciler.restoreSnapshot(context.Background(), reconciler.log, restore, cluster); err != nil {
+		t.Fatalf("Error restoring snapshot: %v", err)
+	}
+	if restore.Status.Phase != kubermaticv1.EtcdRestorePhaseFailed {
+		t.Errorf("Expected restore to have failed, phase is %q", restore.Status.Phase)
+	}
+	if !restore.Status.HasConditionValue(kubermaticv1.EtcdRestoreConditionSnapshotRestored, corev1.ConditionFalse) {
+		t.Errorf("Expected condition %s to be false", kubermaticv1.EtcdRestoreConditionSnapshotRestored)
+	}
+}
+
+func TestDeletedRestoreResumesCluster(t *testing.T) {
+	ctx := context.Background()
+	deletionTimestamp := metav1.Now()
+	restore := &kubermaticv1.EtcdRestore{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "my-restore",
+			Namespace:         "cluster-test-cluster",
+			DeletionTimestamp: &deletionTimestamp,
+			Finalizers:        []string{resumeClusterFinalizer},
+		},
+		Spec: kubermaticv1.EtcdRestoreSpec{
+			Cluster: corev1.ObjectReference{Name: "test-cluster"},
+		},
+	}
+	cluster := &kubermaticv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "test-cluster",
+			Annotations: map[string]string{ActiveRestoreAnnotationName: restore.Name},
+		},
+		Spec: kubermaticv1.ClusterSpec{
+			Pause:       true,
+			PauseReason: "etcd restore my-restore in progress",
+		},
+		Status: kubermaticv1.ClusterStatus{NamespaceName: "cluster-test-cluster"},
+	}
+
+	reconciler := &Reconciler{
+		log:              kubermaticlog.New(true, kubermaticlog.FormatConsole).Sugar(),
+		restoreContainer: testRestoreContainer,
+		seedGetter:       testSeedGetter(&kubermaticv1.Seed{}),
+		Client:           ctrlruntimefakeclient.NewFakeClient(cluster, restore),
+		recorder:         record.NewFakeRecorder(10),
+	}
+	if err := reconciler.cleanupDeletedRestore(ctx, reconciler.log, restore, cluster); err != nil {
+		t.Fatalf("Error cleaning up deleted restore: %v", err)
+	}
+
+	// The fake client doesn't remove map keys and list entries when applying merge patches,
+	// so the annotation and the finalizer are checked on the patched objects.
+	if _, ok := cluster.Annotations[ActiveRestoreAnnotationName]; ok {
+		t.Error("Expected the active restore annotation to be removed")
+	}
+	if len(restore.Finalizers) != 0 {
+		t.Errorf("Expected the finalizer to be removed, got %v", restore.Finalizers)
+	}
+	if err := reconciler.Get(ctx, types.NamespacedName{Name: cluster.Name}, cluster); err != nil {
+		t.Fatalf("Failed to get cluster: %v", err)
+	}
+	if cluster.Spec.Pause {
+		t.Error("Expected cluster to be resumed after the restore got deleted")
+	}
+}
+
+func TestRestoreContainerUsesClusterBackupDestination(t *testing.T) {
+	seed := &kubermaticv1.Seed{
+		Spec: kubermaticv1.SeedSpec{
+			BackupDestinations: map[string]kubermaticv1.BackupDestination{
+				"offsite": {
+					Endpoint:              "s3.example.com",
+					BucketName:            "etcd-backups",
+					CredentialsSecretName: "offsite-credentials",
+				},
+			},
+		},
+	}
+	cluster := &kubermaticv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-cluster"},
+		Spec: kubermaticv1.ClusterSpec{
+			BackupPolicy: &kubermaticv1.EtcdBackupPolicy{Destination: "offsite"},
+		},
+	}
+	reconciler := &Reconciler{
+		restoreContainer: testRestoreContainer,
+		seedGetter:       testSeedGetter(seed),
+	}
+
+	restoreContainer, err := reconciler.restoreContainerForCluster(cluster)
+	if err != nil {
+		t.Fatalf("failed to get restore container: %v", err)
+	}
+	if secrets := referencedSecrets(*restoreContainer).List(); len(secrets) != 1 || secrets[0] != "offsite-credentials" {
+		t.Errorf("expected the restore container to only reference the offsite-credentials secret, got %v", secrets)
+	}
+	if len(testRestoreContainer.Env) != 1 {
+		t.Errorf("expected the configured restore container to be left untouched")
+	}
+
+	cluster.Spec.BackupPolicy.Destination = "does-not-exist"
+	if _, err := reconciler.restoreContainerForCluster(cluster); err == nil {
+		t.Error("expected an error for a non-existing backup destination")
+	}
+}
+
+func testSeedGetter(seed *kubermaticv1.Seed) provider.SeedGetter {
+	return func() (*kubermaticv1.Seed, error) {
+		return seed, nil
+	}
+}
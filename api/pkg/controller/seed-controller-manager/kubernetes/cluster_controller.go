@@ -32,6 +32,7 @@ import (
 	"github.com/kubermatic/kubermatic/api/pkg/provider"
 
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	batchv1beta1 "k8s.io/api/batch/v1beta1"
 	corev1 "k8s.io/api/core/v1"
 	policyv1beta1 "k8s.io/api/policy/v1beta1"
@@ -96,6 +97,10 @@ type Reconciler struct {
 	oidcIssuerURL      string
 	oidcIssuerClientID string
 
+	// dryRun makes the controller only report the changes it would make to the clusters
+	dryRun          bool
+	dryRunReportDir string
+
 	features Features
 }
 
@@ -126,6 +131,8 @@ func Add(
 	oidcIssuerClientID string,
 	kubermaticImage string,
 	dnatControllerImage string,
+	dryRun bool,
+	dryRunReportDir string,
 	features Features) error {
 
 	reconciler := &Reconciler{
@@ -158,6 +165,9 @@ func Add(
 		oidcIssuerURL:      oidcIssuerURL,
 		oidcIssuerClientID: oidcIssuerClientID,
 
+		dryRun:          dryRun,
+		dryRunReportDir: dryRunReportDir,
+
 		features: features,
 	}
 
@@ -175,6 +185,8 @@ func Add(
 		&appsv1.StatefulSet{},
 		&appsv1.Deployment{},
 		&batchv1beta1.CronJob{},
+		// Jobs are used to remove etcd members
+		&batchv1.Job{},
 		&policyv1beta1.PodDisruptionBudget{},
 		&autoscalingv1beta2.VerticalPodAutoscaler{},
 		&rbacv1.Role{},
@@ -211,6 +223,14 @@ func (r *Reconciler) Reconcile(request reconcile.Request) (reconcile.Result, err
 		return reconcile.Result{}, nil
 	}
 
+	if r.dryRun {
+		if err := r.reconcileDryRun(ctx, log, cluster); err != nil {
+			log.Errorw("Dry-run failed", zap.Error(err))
+			return reconcile.Result{}, err
+		}
+		return reconcile.Result{}, nil
+	}
+
 	// Add a wrapping here so we can emit an event on error
 	result, err := kubermaticv1helper.ClusterReconcileWrapper(
 		ctx,
@@ -0,0 +1,62 @@
+/*
+Copyright 2020 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	kubermaticv1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
+	"github.com/kubermatic/kubermatic/api/pkg/resources/reconciling"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+// reconcileDryRun runs the reconciliation of the cluster against the live objects without writing anything.
+// The changes it would make are reported as an event on the cluster and, if configured, in a report file.
+func (r *Reconciler) reconcileDryRun(ctx context.Context, log *zap.SugaredLogger, cluster *kubermaticv1.Cluster) error {
+	// skip the clusters the regular reconciliation skips as well
+	if cluster.Labels[kubermaticv1.WorkerNameLabelKey] != r.workerName || cluster.Spec.Pause || cluster.DeletionTimestamp != nil {
+		return nil
+	}
+
+	report := reconciling.NewDryRunReport()
+	dryRunReconciler := *r
+	dryRunReconciler.Client = reconciling.NewDryRunClient(r.Client, report)
+	// the events emitted while reconciling would be misleading, only the report gets published
+	dryRunReconciler.recorder = &record.FakeRecorder{}
+
+	if _, err := dryRunReconciler.reconcileCluster(ctx, cluster.DeepCopy()); err != nil {
+		return fmt.Errorf("failed to reconcile: %v", err)
+	}
+
+	summary := report.Summary()
+	log.Infow("Dry-run finished", "changes", summary)
+	if len(report.Changes()) > 0 {
+		r.recorder.Eventf(cluster, corev1.EventTypeNormal, "DryRun", "Reconciling would make the following changes: %s", summary)
+	}
+
+	if r.dryRunReportDir != "" {
+		if err := report.WriteFile(r.dryRunReportDir, cluster.Name); err != nil {
+			return fmt.Errorf("failed to write the report: %v", err)
+		}
+	}
+	return nil
+}
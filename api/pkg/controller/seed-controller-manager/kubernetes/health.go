@@ -27,6 +27,7 @@ import (
 	kubermaticv1helper "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1/helper"
 	"github.com/kubermatic/kubermatic/api/pkg/provider/kubernetes"
 	"github.com/kubermatic/kubermatic/api/pkg/resources"
+	"github.com/kubermatic/kubermatic/api/pkg/resources/etcd"
 )
 
 func (r *Reconciler) clusterHealth(ctx context.Context, cluster *kubermaticv1.Cluster) (*kubermaticv1.ExtendedClusterHealth, error) {
@@ -59,7 +60,12 @@ func (r *Reconciler) clusterHealth(ctx context.Context, cluster *kubermaticv1.Cl
 	var err error
 	key := types.NamespacedName{Namespace: ns, Name: resources.EtcdStatefulSetName}
 
-	etcdHealthStatus, err := resources.HealthyStatefulSet(ctx, r, key, 2)
+	// Quorum depends on the members that currently exist, not on the requested size
+	etcdClusterSize, err := etcd.GetCurrentClusterSize(ctx, r, cluster)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get etcd cluster size: %v", err)
+	}
+	etcdHealthStatus, err := resources.HealthyStatefulSet(ctx, r, key, int32(etcdClusterSize/2+1))
 	if err != nil {
 		return nil, fmt.Errorf("failed to get etcd health: %v", err)
 	}
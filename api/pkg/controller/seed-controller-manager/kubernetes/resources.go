@@ -40,6 +40,7 @@ import (
 	"github.com/kubermatic/kubermatic/api/pkg/resources/scheduler"
 	"github.com/kubermatic/kubermatic/api/pkg/resources/usercluster"
 
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -266,6 +267,8 @@ func (r *Reconciler) GetSecretCreators(data *resources.TemplateData) []reconcili
 		resources.ImagePullSecretCreator(r.dockerPullConfigJSON),
 		apiserver.FrontProxyClientCertificateCreator(data),
 		etcd.TLSCertificateCreator(data),
+		etcd.PeerCACreator(),
+		etcd.PeerTLSCertificateCreator(data),
 		apiserver.EtcdClientCertificateCreator(data),
 		apiserver.TLSServingCertificateCreator(data),
 		apiserver.KubeletClientCertificateCreator(data),
@@ -305,6 +308,14 @@ func (r *Reconciler) GetSecretCreators(data *resources.TemplateData) []reconcili
 		creators = append(creators, resources.ServiceAccountSecretCreator(data))
 	}
 
+	if apiserver.IsEncryptionAtRestEnabled(data.Cluster()) {
+		creators = append(creators, apiserver.EncryptionConfigurationSecretCreator(data))
+	}
+
+	if apiserver.IsAuditWebhookEnabled(data.Cluster()) {
+		creators = append(creators, apiserver.AuditWebhookConfigSecretCreator(data))
+	}
+
 	return creators
 }
 
@@ -356,7 +367,7 @@ func GetConfigMapCreators(data *resources.TemplateData) []reconciling.NamedConfi
 		cloudconfig.ConfigMapCreator(data),
 		openvpn.ServerClientConfigsConfigMapCreator(data),
 		dns.ConfigMapCreator(data),
-		apiserver.AuditConfigMapCreator(),
+		apiserver.AuditConfigMapCreator(data),
 	}
 }
 
@@ -439,7 +450,36 @@ func (r *Reconciler) ensureVerticalPodAutoscalers(ctx context.Context, c *kuberm
 }
 
 func (r *Reconciler) ensureStatefulSets(ctx context.Context, c *kubermaticv1.Cluster, data *resources.TemplateData) error {
+	if err := r.syncEtcdPeerTLSPhase(ctx, c); err != nil {
+		return err
+	}
+
 	creators := GetStatefulSetCreators(data, r.features.EtcdDataCorruptionChecks)
 
-	return reconciling.ReconcileStatefulSets(ctx, creators, c.Status.NamespaceName, r.Client, reconciling.OwnerRefWrapper(resources.GetClusterRef(c)))
+	if err := reconciling.ReconcileStatefulSets(ctx, creators, c.Status.NamespaceName, r.Client, reconciling.OwnerRefWrapper(resources.GetClusterRef(c))); err != nil {
+		return err
+	}
+
+	return etcd.ReconcileClusterSize(ctx, r.Client, data)
+}
+
+// syncEtcdPeerTLSPhase moves the etcd to the next step of the peer TLS migration once the current one is rolled out
+func (r *Reconciler) syncEtcdPeerTLSPhase(ctx context.Context, cluster *kubermaticv1.Cluster) error {
+	set := &appsv1.StatefulSet{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: cluster.Status.NamespaceName, Name: resources.EtcdStatefulSetName}, set); err != nil {
+		if !errors.IsNotFound(err) {
+			return fmt.Errorf("failed to get etcd StatefulSet: %v", err)
+		}
+		set = nil
+	}
+
+	phase := etcd.NextPeerTLSPhase(cluster, set)
+	if phase == etcd.PeerTLSPhase(cluster) {
+		return nil
+	}
+
+	r.recorder.Eventf(cluster, corev1.EventTypeNormal, "EtcdPeerTLSMigration", "Moving etcd to peer TLS step %s", phase)
+	return r.updateCluster(ctx, cluster, func(c *kubermaticv1.Cluster) {
+		etcd.SetPeerTLSCondition(c, phase)
+	})
 }
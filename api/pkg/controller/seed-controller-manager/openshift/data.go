@@ -118,6 +118,15 @@ func (od *openshiftData) GetFrontProxyCAWithContext(ctx context.Context) (*tripl
 		secret.Data[kubernetesresources.CAKeySecretKey])
 }
 
+func (od *openshiftData) GetEtcdPeerCA() (*triple.KeyPair, error) {
+	secret := &corev1.Secret{}
+	if err := od.client.Get(context.Background(), nn(od.cluster.Status.NamespaceName, kubernetesresources.EtcdPeerCASecretName), secret); err != nil {
+		return nil, fmt.Errorf("failed to get etcd peer CA: %v", err)
+	}
+	return triple.ParseRSAKeyPair(secret.Data[kubernetesresources.CACertSecretKey],
+		secret.Data[kubernetesresources.CAKeySecretKey])
+}
+
 func (od *openshiftData) ImageRegistry(registry string) string {
 	if od.overwriteRegistry != "" {
 		return od.overwriteRegistry
@@ -241,6 +250,10 @@ func (od *openshiftData) HasEtcdOperatorService() (bool, error) {
 	return false, nil
 }
 
+func (od *openshiftData) EtcdStatefulSetReplicas() (*int32, error) {
+	return kubernetesresources.EtcdStatefulSetReplicas(context.Background(), od.cluster.Status.NamespaceName, od.client)
+}
+
 func (od *openshiftData) EtcdDiskSize() resource.Quantity {
 	return od.etcdDiskSize
 }
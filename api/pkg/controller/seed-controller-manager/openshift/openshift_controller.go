@@ -49,6 +49,7 @@ import (
 	"github.com/kubermatic/kubermatic/api/pkg/resources/usercluster"
 
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	batchv1beta1 "k8s.io/api/batch/v1beta1"
 	corev1 "k8s.io/api/core/v1"
 	policyv1beta1 "k8s.io/api/policy/v1beta1"
@@ -157,6 +158,8 @@ func Add(
 		&appsv1.StatefulSet{},
 		&appsv1.Deployment{},
 		&batchv1beta1.CronJob{},
+		// Jobs are used to remove etcd members
+		&batchv1.Job{},
 		&policyv1beta1.PodDisruptionBudget{},
 		&autoscalingv1beta2.VerticalPodAutoscaler{},
 		&rbacv1.Role{},
@@ -331,7 +334,11 @@ func (r *Reconciler) syncHeath(ctx context.Context, cluster *kubermaticv1.Cluste
 		*healthMapping[name].healthStatus = kubermaticv1helper.GetHealthStatus(status, cluster)
 	}
 
-	status, err := resources.HealthyStatefulSet(ctx, r.Client, nn(cluster.Status.NamespaceName, resources.EtcdStatefulSetName), 2)
+	etcdClusterSize, err := etcd.GetCurrentClusterSize(ctx, r.Client, cluster)
+	if err != nil {
+		return fmt.Errorf("failed to get etcd cluster size: %v", err)
+	}
+	status, err := resources.HealthyStatefulSet(ctx, r.Client, nn(cluster.Status.NamespaceName, resources.EtcdStatefulSetName), int32(etcdClusterSize/2+1))
 	if err != nil {
 		return fmt.Errorf("failed to get etcd health: %v", err)
 	}
@@ -379,6 +386,8 @@ func (r *Reconciler) getAllSecretCreators(ctx context.Context, osData *openshift
 		resources.ImagePullSecretCreator(r.dockerPullConfigJSON),
 		apiserver.FrontProxyClientCertificateCreator(osData),
 		etcd.TLSCertificateCreator(osData),
+		etcd.PeerCACreator(),
+		etcd.PeerTLSCertificateCreator(osData),
 		apiserver.EtcdClientCertificateCreator(osData),
 		apiserver.TLSServingCertificateCreator(osData),
 		apiserver.KubeletClientCertificateCreator(osData),
@@ -432,8 +441,37 @@ func GetStatefulSetCreators(osData *openshiftData, enableDataCorruptionChecks bo
 }
 
 func (r *Reconciler) statefulSets(ctx context.Context, osData *openshiftData) error {
+	if err := r.syncEtcdPeerTLSPhase(ctx, osData.Cluster()); err != nil {
+		return err
+	}
+
 	creators := GetStatefulSetCreators(osData, r.features.EtcdDataCorruptionChecks)
-	return reconciling.ReconcileStatefulSets(ctx, creators, osData.Cluster().Status.NamespaceName, r.Client)
+	if err := reconciling.ReconcileStatefulSets(ctx, creators, osData.Cluster().Status.NamespaceName, r.Client); err != nil {
+		return err
+	}
+
+	return etcd.ReconcileClusterSize(ctx, r.Client, osData)
+}
+
+// syncEtcdPeerTLSPhase moves the etcd to the next step of the peer TLS migration once the current one is rolled out
+func (r *Reconciler) syncEtcdPeerTLSPhase(ctx context.Context, cluster *kubermaticv1.Cluster) error {
+	set := &appsv1.StatefulSet{}
+	if err := r.Get(ctx, nn(cluster.Status.NamespaceName, resources.EtcdStatefulSetName), set); err != nil {
+		if !kerrors.IsNotFound(err) {
+			return fmt.Errorf("failed to get etcd StatefulSet: %v", err)
+		}
+		set = nil
+	}
+
+	phase := etcd.NextPeerTLSPhase(cluster, set)
+	if phase == etcd.PeerTLSPhase(cluster) {
+		return nil
+	}
+
+	r.recorder.Eventf(cluster, corev1.EventTypeNormal, "EtcdPeerTLSMigration", "Moving etcd to peer TLS step %s", phase)
+	return r.updateCluster(ctx, cluster, func(c *kubermaticv1.Cluster) {
+		etcd.SetPeerTLSCondition(c, phase)
+	})
 }
 
 func (r *Reconciler) getAllConfigmapCreators(ctx context.Context, osData *openshiftData) []reconciling.NamedConfigMapCreatorGetter {
@@ -46,10 +46,12 @@ type masterConfigData interface {
 	Cluster() *kubermaticv1.Cluster
 	GetApiserverExternalNodePort(context.Context) (int32, error)
 	GetKubernetesCloudProviderName() string
+	EtcdStatefulSetReplicas() (*int32, error)
 }
 
 type openshiftAPIServerCreatorData interface {
 	Cluster() *kubermaticv1.Cluster
+	EtcdStatefulSetReplicas() (*int32, error)
 }
 
 func OpenshiftAPIServerConfigMapCreator(data openshiftAPIServerCreatorData) reconciling.NamedConfigMapCreatorGetter {
@@ -58,11 +60,15 @@ func OpenshiftAPIServerConfigMapCreator(data openshiftAPIServerCreatorData) reco
 			if cm.Data == nil {
 				cm.Data = map[string]string{}
 			}
+			etcdReplicas, err := data.EtcdStatefulSetReplicas()
+			if err != nil {
+				return nil, err
+			}
 			apiServerConfigBuffer := bytes.Buffer{}
 			templateInput := struct {
 				ETCDEndpoints []string
 			}{
-				ETCDEndpoints: etcd.GetClientEndpoints(data.Cluster().Status.NamespaceName),
+				ETCDEndpoints: etcd.GetClientEndpoints(data.Cluster(), etcd.CurrentClusterSize(data.Cluster(), etcdReplicas)),
 			}
 			if err := openshiftAPIServerTemplate.Execute(&apiServerConfigBuffer, templateInput); err != nil {
 				return nil, fmt.Errorf("failed to execute template: %v", err)
@@ -88,6 +94,11 @@ func OpenshiftKubeAPIServerConfigMapCreator(data masterConfigData) reconciling.N
 				serviceCIDR = data.Cluster().Spec.ClusterNetwork.Services.CIDRBlocks[0]
 			}
 
+			etcdReplicas, err := data.EtcdStatefulSetReplicas()
+			if err != nil {
+				return nil, err
+			}
+
 			apiServerConfigBuffer := bytes.Buffer{}
 			templateInput := struct {
 				PodCIDR          string
@@ -100,7 +111,7 @@ func OpenshiftKubeAPIServerConfigMapCreator(data masterConfigData) reconciling.N
 				PodCIDR:          podCIDR,
 				ServiceCIDR:      serviceCIDR,
 				ListenPort:       fmt.Sprint(data.Cluster().Address.Port),
-				ETCDEndpoints:    etcd.GetClientEndpoints(data.Cluster().Status.NamespaceName),
+				ETCDEndpoints:    etcd.GetClientEndpoints(data.Cluster(), etcd.CurrentClusterSize(data.Cluster(), etcdReplicas)),
 				AdvertiseAddress: data.Cluster().Address.IP,
 				CloudProvider:    data.GetKubernetesCloudProviderName(),
 			}
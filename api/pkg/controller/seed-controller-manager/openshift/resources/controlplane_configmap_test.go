@@ -38,6 +38,10 @@ func (o *openshiftAPIServerCreatorDataFake) Cluster() *kubermaticv1.Cluster {
 	return o.cluster
 }
 
+func (o *openshiftAPIServerCreatorDataFake) EtcdStatefulSetReplicas() (*int32, error) {
+	return nil, nil
+}
+
 func TestOpenshiftAPIServerConfigMapCreator(t *testing.T) {
 	testCases := []struct {
 		name string
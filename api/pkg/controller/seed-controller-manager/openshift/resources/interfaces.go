@@ -45,6 +45,7 @@ type openshiftData interface {
 	GetRootCAWithContext(context.Context) (*triple.KeyPair, error)
 	DC() *kubermaticv1.Datacenter
 	HasEtcdOperatorService() (bool, error)
+	EtcdStatefulSetReplicas() (*int32, error)
 	EtcdDiskSize() resource.Quantity
 	NodeLocalDNSCacheEnabled() bool
 	KubermaticAPIImage() string
@@ -149,7 +149,11 @@ func APIDeploymentCreator(ctx context.Context, data openshiftData) reconciling.N
 				},
 			}
 
-			etcdEndpoints := etcd.GetClientEndpoints(data.Cluster().Status.NamespaceName)
+			etcdReplicas, err := data.EtcdStatefulSetReplicas()
+			if err != nil {
+				return nil, err
+			}
+			etcdEndpoints := etcd.GetClientEndpoints(data.Cluster(), etcd.CurrentClusterSize(data.Cluster(), etcdReplicas))
 
 			// Configure user cluster DNS resolver for this pod.
 			dep.Spec.Template.Spec.DNSPolicy, dep.Spec.Template.Spec.DNSConfig, err = resources.UserClusterDNSPolicyAndConfig(data)
@@ -18,6 +18,9 @@ limitations under the License.
 Package update contains a controller that auto applies updates to both the cluster version
 and the machine version based on a configuration file.
 
+If the cluster has an update window configured, updates are only applied while the window is open.
+Outside of the window an event is emitted and the cluster is requeued for the start of the next window.
+
 TODO: Make this controller wait for successfully convergation after an update was applied. Currently,
 it may apply an update and then instantly apply another one, which is not supported, only n+1 minor
 version updates are supported.
@@ -19,8 +19,11 @@ package update
 import (
 	"context"
 	"fmt"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/coreos/locksmith/pkg/timeutil"
 	"go.uber.org/zap"
 
 	v1 "github.com/kubermatic/kubermatic/api/pkg/api/v1"
@@ -53,8 +56,16 @@ type Reconciler struct {
 	recorder                      record.EventRecorder
 	userClusterConnectionProvider *client.Provider
 	log                           *zap.SugaredLogger
+
+	// deferrals holds the target versions of the deferred updates by cluster and updated object,
+	// so the AutoUpdateDeferred event is only emitted once per deferral
+	deferralsLock sync.Mutex
+	deferrals     map[string]string
 }
 
+// windowDelayFunc returns how long it takes until the update window of the cluster opens
+type windowDelayFunc func() (time.Duration, error)
+
 // Add creates a new update controller
 func Add(mgr manager.Manager, numWorkers int, workerName string, updateManager *version.Manager,
 	userClusterConnectionProvider *client.Provider, log *zap.SugaredLogger) error {
@@ -89,6 +100,7 @@ func (r *Reconciler) Reconcile(request reconcile.Request) (reconcile.Result, err
 	cluster := &kubermaticv1.Cluster{}
 	if err := r.Get(ctx, request.NamespacedName, cluster); err != nil {
 		if kerrors.IsNotFound(err) {
+			r.forgetDeferrals(request.Name)
 			return reconcile.Result{}, nil
 		}
 		return reconcile.Result{}, err
@@ -128,11 +140,29 @@ func (r *Reconciler) reconcile(ctx context.Context, cluster *kubermaticv1.Cluste
 		clusterType = v1.OpenShiftClusterType
 	}
 
+	now := time.Now().UTC()
+	// the update window is only evaluated when an update is due, so a malformed window doesn't fail clusters without one
+	var windowDelay time.Duration
+	var windowEvaluated bool
+	getWindowDelay := func() (time.Duration, error) {
+		if !windowEvaluated {
+			delay, err := durationToUpdateWindow(cluster.Spec.UpdateWindow, now)
+			if err != nil {
+				return 0, fmt.Errorf("failed to parse update window: %v", err)
+			}
+			windowDelay, windowEvaluated = delay, true
+		}
+		return windowDelay, nil
+	}
+
 	// NodeUpdate may need the controlplane to be updated first
-	updated, err := r.controlPlaneUpgrade(ctx, cluster, clusterType)
+	updated, deferred, err := r.controlPlaneUpgrade(ctx, cluster, clusterType, now, getWindowDelay)
 	if err != nil {
 		return nil, fmt.Errorf("failed to update the controlplane: %v", err)
 	}
+	if deferred {
+		return &reconcile.Result{RequeueAfter: windowDelay}, nil
+	}
 	// Give the controller time to do the update
 	// TODO: This is not really safe. We should add a `Version` to the status
 	// that gets incremented when the controller does this. Combined with a
@@ -141,52 +171,136 @@ func (r *Reconciler) reconcile(ctx context.Context, cluster *kubermaticv1.Cluste
 		return &reconcile.Result{RequeueAfter: time.Minute}, nil
 	}
 
-	if err := r.nodeUpdate(ctx, cluster, clusterType); err != nil {
+	deferred, err = r.nodeUpdate(ctx, cluster, clusterType, now, getWindowDelay)
+	if err != nil {
 		return nil, fmt.Errorf("failed to update machineDeployments: %v", err)
 	}
+	if deferred {
+		return &reconcile.Result{RequeueAfter: windowDelay}, nil
+	}
 
 	return nil, nil
 }
 
-func (r *Reconciler) nodeUpdate(ctx context.Context, cluster *kubermaticv1.Cluster, clusterType string) error {
+// durationToUpdateWindow returns how long it takes until the update window of the cluster opens.
+// Zero is returned if the cluster has no update window or if the window is currently open.
+// The window is evaluated in the timezone of now.
+func durationToUpdateWindow(updateWindow *kubermaticv1.UpdateWindow, now time.Time) (time.Duration, error) {
+	if updateWindow == nil || updateWindow.Start == "" || updateWindow.Length == "" {
+		return 0, nil
+	}
+	periodic, err := timeutil.ParsePeriodic(updateWindow.Start, updateWindow.Length)
+	if err != nil {
+		return 0, err
+	}
+	if delay := periodic.DurationToStart(now); delay > 0 {
+		return delay, nil
+	}
+	return 0, nil
+}
+
+// startDeferral records that the update of the object to the target version has been deferred and returns
+// whether this deferral is new, meaning it hasn't been recorded before or its target version changed
+func (r *Reconciler) startDeferral(cluster *kubermaticv1.Cluster, object, target string) bool {
+	r.deferralsLock.Lock()
+	defer r.deferralsLock.Unlock()
+
+	if r.deferrals == nil {
+		r.deferrals = map[string]string{}
+	}
+	key := cluster.Name + "/" + object
+	if r.deferrals[key] == target {
+		return false
+	}
+	r.deferrals[key] = target
+	return true
+}
+
+// endDeferral forgets the deferred update of the object once it isn't deferred anymore
+func (r *Reconciler) endDeferral(cluster *kubermaticv1.Cluster, object string) {
+	r.deferralsLock.Lock()
+	defer r.deferralsLock.Unlock()
+
+	delete(r.deferrals, cluster.Name+"/"+object)
+}
+
+// forgetDeferrals forgets the deferred updates of a deleted cluster
+func (r *Reconciler) forgetDeferrals(clusterName string) {
+	r.deferralsLock.Lock()
+	defer r.deferralsLock.Unlock()
+
+	for key := range r.deferrals {
+		if strings.HasPrefix(key, clusterName+"/") {
+			delete(r.deferrals, key)
+		}
+	}
+}
+
+func (r *Reconciler) nodeUpdate(ctx context.Context, cluster *kubermaticv1.Cluster, clusterType string, now time.Time, windowDelay windowDelayFunc) (deferred bool, err error) {
 	c, err := r.userClusterConnectionProvider.GetClient(cluster)
 	if err != nil {
-		return fmt.Errorf("failed to get usercluster client: %v", err)
+		return false, fmt.Errorf("failed to get usercluster client: %v", err)
 	}
 
 	machineDeployments := &clusterv1alpha1.MachineDeploymentList{}
 	// Kubermatic only creates MachineDeployments in the kube-system namespace, everything else is essentially unsupported
 	if err := c.List(ctx, machineDeployments, ctrlruntimeclient.InNamespace("kube-system")); err != nil {
-		return fmt.Errorf("failed to list MachineDeployments: %v", err)
+		return false, fmt.Errorf("failed to list MachineDeployments: %v", err)
 	}
 
 	for _, md := range machineDeployments.Items {
 		targetVersion, err := r.updateManager.AutomaticNodeUpdate(md.Spec.Template.Spec.Versions.Kubelet, clusterType, cluster.Spec.Version.String())
 		if err != nil {
-			return fmt.Errorf("failed to get automatic update for machinedeployment %s/%s that has version %q: %v", md.Namespace, md.Name, md.Spec.Template.Spec.Versions.Kubelet, err)
+			return false, fmt.Errorf("failed to get automatic update for machinedeployment %s/%s that has version %q: %v", md.Namespace, md.Name, md.Spec.Template.Spec.Versions.Kubelet, err)
 		}
+		object := "machinedeployment/" + md.Namespace + "/" + md.Name
 		if targetVersion == nil {
+			r.endDeferral(cluster, object)
 			continue
 		}
+		delay, err := windowDelay()
+		if err != nil {
+			return false, err
+		}
+		if delay > 0 {
+			if r.startDeferral(cluster, object, targetVersion.Version.String()) {
+				r.recorder.Eventf(cluster, corev1.EventTypeNormal, "AutoUpdateDeferred", "Automatic update of MachineDeployment %s/%s to version %q deferred until %s", md.Namespace, md.Name, targetVersion.Version.String(), now.Add(delay).Format(time.RFC3339))
+			}
+			deferred = true
+			continue
+		}
+		r.endDeferral(cluster, object)
 		md.Spec.Template.Spec.Versions.Kubelet = targetVersion.Version.String()
 		// DeepCopy it so we don't get a NPD when we return an error
 		if err := c.Update(ctx, md.DeepCopy()); err != nil {
-			return fmt.Errorf("failed to update MachineDeployment %s/%s to %q: %v", md.Namespace, md.Name, md.Spec.Template.Spec.Versions.Kubelet, err)
+			return false, fmt.Errorf("failed to update MachineDeployment %s/%s to %q: %v", md.Namespace, md.Name, md.Spec.Template.Spec.Versions.Kubelet, err)
 		}
 		r.recorder.Eventf(cluster, corev1.EventTypeNormal, "AutoUpdateMachineDeployment", "Triggered automatic update of MachineDeployment %s/%s to version %q", md.Namespace, md.Name, targetVersion.Version.String())
 	}
 
-	return nil
+	return deferred, nil
 }
 
-func (r *Reconciler) controlPlaneUpgrade(ctx context.Context, cluster *kubermaticv1.Cluster, clusterType string) (upgraded bool, err error) {
+func (r *Reconciler) controlPlaneUpgrade(ctx context.Context, cluster *kubermaticv1.Cluster, clusterType string, now time.Time, windowDelay windowDelayFunc) (upgraded, deferred bool, err error) {
 	update, err := r.updateManager.AutomaticControlplaneUpdate(cluster.Spec.Version.String(), clusterType)
 	if err != nil {
-		return false, fmt.Errorf("failed to get automatic update for cluster for version %s: %v", cluster.Spec.Version.String(), err)
+		return false, false, fmt.Errorf("failed to get automatic update for cluster for version %s: %v", cluster.Spec.Version.String(), err)
 	}
 	if update == nil {
-		return false, nil
+		r.endDeferral(cluster, "controlplane")
+		return false, false, nil
+	}
+	delay, err := windowDelay()
+	if err != nil {
+		return false, false, err
+	}
+	if delay > 0 {
+		if r.startDeferral(cluster, "controlplane", update.Version.String()) {
+			r.recorder.Eventf(cluster, corev1.EventTypeNormal, "AutoUpdateDeferred", "Automatic update of the control plane to version %q deferred until %s", update.Version.String(), now.Add(delay).Format(time.RFC3339))
+		}
+		return false, true, nil
 	}
+	r.endDeferral(cluster, "controlplane")
 	oldCluster := cluster.DeepCopy()
 
 	cluster.Spec.Version = *semver.NewSemverOrDie(update.Version.String())
@@ -195,7 +309,7 @@ func (r *Reconciler) controlPlaneUpgrade(ctx context.Context, cluster *kubermati
 	cluster.Status.ExtendedHealth.Controller = kubermaticv1.HealthStatusDown
 	cluster.Status.ExtendedHealth.Scheduler = kubermaticv1.HealthStatusDown
 	if err := r.Patch(ctx, cluster, ctrlruntimeclient.MergeFrom(oldCluster)); err != nil {
-		return false, fmt.Errorf("failed to update cluster: %v", err)
+		return false, false, fmt.Errorf("failed to update cluster: %v", err)
 	}
-	return true, nil
+	return true, false, nil
 }
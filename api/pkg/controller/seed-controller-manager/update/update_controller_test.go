@@ -0,0 +1,199 @@
+/*
+Copyright 2020 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package update
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	gosemver "github.com/Masterminds/semver"
+
+	v1 "github.com/kubermatic/kubermatic/api/pkg/api/v1"
+	kubermaticv1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
+	kubermaticlog "github.com/kubermatic/kubermatic/api/pkg/log"
+	"github.com/kubermatic/kubermatic/api/pkg/semver"
+	"github.com/kubermatic/kubermatic/api/pkg/version"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrlruntimefakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestDurationToUpdateWindow(t *testing.T) {
+	// Thursday
+	now := time.Date(2020, time.January, 2, 10, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name          string
+		updateWindow  *kubermaticv1.UpdateWindow
+		expectedDelay time.Duration
+		expectError   bool
+	}{
+		{
+			name:          "no update window",
+			expectedDelay: 0,
+		},
+		{
+			name:          "incomplete update window",
+			updateWindow:  &kubermaticv1.UpdateWindow{Start: "04:00"},
+			expectedDelay: 0,
+		},
+		{
+			name:          "inside daily window",
+			updateWindow:  &kubermaticv1.UpdateWindow{Start: "09:30", Length: "1h"},
+			expectedDelay: 0,
+		},
+		{
+			name:          "before daily window",
+			updateWindow:  &kubermaticv1.UpdateWindow{Start: "12:00", Length: "1h"},
+			expectedDelay: 2 * time.Hour,
+		},
+		{
+			name:          "after daily window",
+			updateWindow:  &kubermaticv1.UpdateWindow{Start: "04:00", Length: "1h"},
+			expectedDelay: 18 * time.Hour,
+		},
+		{
+			name:          "inside weekly window",
+			updateWindow:  &kubermaticv1.UpdateWindow{Start: "Thu 09:00", Length: "2h"},
+			expectedDelay: 0,
+		},
+		{
+			name:          "before weekly window",
+			updateWindow:  &kubermaticv1.UpdateWindow{Start: "Sat 10:00", Length: "2h"},
+			expectedDelay: 48 * time.Hour,
+		},
+		{
+			name:          "after weekly window",
+			updateWindow:  &kubermaticv1.UpdateWindow{Start: "Wed 10:00", Length: "2h"},
+			expectedDelay: 6 * 24 * time.Hour,
+		},
+		{
+			name:         "invalid update window",
+			updateWindow: &kubermaticv1.UpdateWindow{Start: "someday 10:00", Length: "2h"},
+			expectError:  true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			delay, err := durationToUpdateWindow(tc.updateWindow, now)
+			if tc.expectError {
+				if err == nil {
+					t.Fatal("expected an error but got nothing")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if delay != tc.expectedDelay {
+				t.Errorf("expected delay to be %v, got %v", tc.expectedDelay, delay)
+			}
+		})
+	}
+}
+
+func TestControlPlaneUpgradeIsDeferred(t *testing.T) {
+	cluster := &kubermaticv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-cluster"},
+		Spec: kubermaticv1.ClusterSpec{
+			Version: *semver.NewSemverOrDie("1.16.1"),
+		},
+	}
+	updateManager := version.New(
+		[]*version.Version{{Version: gosemver.MustParse("1.16.2"), Type: v1.KubernetesClusterType}},
+		[]*version.Update{{From: "1.16.1", To: "1.16.2", Automatic: true, Type: v1.KubernetesClusterType}},
+	)
+	recorder := record.NewFakeRecorder(10)
+	reconciler := &Reconciler{
+		updateManager: updateManager,
+		Client:        ctrlruntimefakeclient.NewFakeClient(cluster),
+		recorder:      recorder,
+		log:           kubermaticlog.New(true, kubermaticlog.FormatConsole).Sugar(),
+	}
+	ctx := context.Background()
+	now := time.Date(2020, time.January, 2, 10, 0, 0, 0, time.UTC)
+
+	windowDelay := func(delay time.Duration) windowDelayFunc {
+		return func() (time.Duration, error) { return delay, nil }
+	}
+
+	for i := 0; i < 2; i++ {
+		upgraded, deferred, err := reconciler.controlPlaneUpgrade(ctx, cluster, v1.KubernetesClusterType, now, windowDelay(time.Hour))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if upgraded || !deferred {
+			t.Fatalf("expected the upgrade to be deferred, upgraded=%t deferred=%t", upgraded, deferred)
+		}
+	}
+	// the deferral is only reported when it starts
+	if len(recorder.Events) != 1 {
+		t.Fatalf("expected one event, got %d", len(recorder.Events))
+	}
+	if event := <-recorder.Events; event != `Normal AutoUpdateDeferred Automatic update of the control plane to version "1.16.2" deferred until 2020-01-02T11:00:00Z` {
+		t.Errorf("unexpected event %q", event)
+	}
+
+	upgraded, deferred, err := reconciler.controlPlaneUpgrade(ctx, cluster, v1.KubernetesClusterType, now, windowDelay(0))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !upgraded || deferred {
+		t.Fatalf("expected the upgrade to be applied, upgraded=%t deferred=%t", upgraded, deferred)
+	}
+	if err := reconciler.Get(ctx, types.NamespacedName{Name: cluster.Name}, cluster); err != nil {
+		t.Fatalf("failed to get cluster: %v", err)
+	}
+	if cluster.Spec.Version.String() != "1.16.2" {
+		t.Errorf("expected cluster to be updated to 1.16.2, got %s", cluster.Spec.Version.String())
+	}
+}
+
+func TestUpdateWindowIsOnlyEvaluatedForPendingUpdates(t *testing.T) {
+	cluster := &kubermaticv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-cluster"},
+		Spec: kubermaticv1.ClusterSpec{
+			Version: *semver.NewSemverOrDie("1.16.2"),
+		},
+	}
+	updateManager := version.New(
+		[]*version.Version{{Version: gosemver.MustParse("1.16.2"), Type: v1.KubernetesClusterType}},
+		[]*version.Update{{From: "1.16.1", To: "1.16.2", Automatic: true, Type: v1.KubernetesClusterType}},
+	)
+	reconciler := &Reconciler{
+		updateManager: updateManager,
+		Client:        ctrlruntimefakeclient.NewFakeClient(cluster),
+		recorder:      record.NewFakeRecorder(10),
+		log:           kubermaticlog.New(true, kubermaticlog.FormatConsole).Sugar(),
+	}
+	malformedWindow := func() (time.Duration, error) {
+		return 0, errors.New("malformed update window")
+	}
+
+	upgraded, deferred, err := reconciler.controlPlaneUpgrade(context.Background(), cluster, v1.KubernetesClusterType, time.Now(), malformedWindow)
+	if err != nil {
+		t.Fatalf("expected the update window not to be evaluated without a pending update, got %v", err)
+	}
+	if upgraded || deferred {
+		t.Fatalf("expected no update, upgraded=%t deferred=%t", upgraded, deferred)
+	}
+}
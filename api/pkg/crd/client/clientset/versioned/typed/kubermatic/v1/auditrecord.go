@@ -0,0 +1,148 @@
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	"time"
+
+	scheme "github.com/kubermatic/kubermatic/api/pkg/crd/client/clientset/versioned/scheme"
+	v1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+)
+
+// AuditRecordsGetter has a method to return a AuditRecordInterface.
+// A group's client should implement this interface.
+type AuditRecordsGetter interface {
+	AuditRecords() AuditRecordInterface
+}
+
+// AuditRecordInterface has methods to work with AuditRecord resources.
+type AuditRecordInterface interface {
+	Create(*v1.AuditRecord) (*v1.AuditRecord, error)
+	Update(*v1.AuditRecord) (*v1.AuditRecord, error)
+	Delete(name string, options *metav1.DeleteOptions) error
+	DeleteCollection(options *metav1.DeleteOptions, listOptions metav1.ListOptions) error
+	Get(name string, options metav1.GetOptions) (*v1.AuditRecord, error)
+	List(opts metav1.ListOptions) (*v1.AuditRecordList, error)
+	Watch(opts metav1.ListOptions) (watch.Interface, error)
+	Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1.AuditRecord, err error)
+	AuditRecordExpansion
+}
+
+// auditRecords implements AuditRecordInterface
+type auditRecords struct {
+	client rest.Interface
+}
+
+// newAuditRecords returns a AuditRecords
+func newAuditRecords(c *KubermaticV1Client) *auditRecords {
+	return &auditRecords{
+		client: c.RESTClient(),
+	}
+}
+
+// Get takes name of the auditRecord, and returns the corresponding auditRecord object, and an error if there is any.
+func (c *auditRecords) Get(name string, options metav1.GetOptions) (result *v1.AuditRecord, err error) {
+	result = &v1.AuditRecord{}
+	err = c.client.Get().
+		Resource("auditrecords").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do().
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of AuditRecords that match those selectors.
+func (c *auditRecords) List(opts metav1.ListOptions) (result *v1.AuditRecordList, err error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	result = &v1.AuditRecordList{}
+	err = c.client.Get().
+		Resource("auditrecords").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Do().
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested auditRecords.
+func (c *auditRecords) Watch(opts metav1.ListOptions) (watch.Interface, error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	opts.Watch = true
+	return c.client.Get().
+		Resource("auditrecords").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Watch()
+}
+
+// Create takes the representation of a auditRecord and creates it.  Returns the server's representation of the auditRecord, and an error, if there is any.
+func (c *auditRecords) Create(auditRecord *v1.AuditRecord) (result *v1.AuditRecord, err error) {
+	result = &v1.AuditRecord{}
+	err = c.client.Post().
+		Resource("auditrecords").
+		Body(auditRecord).
+		Do().
+		Into(result)
+	return
+}
+
+// Update takes the representation of a auditRecord and updates it. Returns the server's representation of the auditRecord, and an error, if there is any.
+func (c *auditRecords) Update(auditRecord *v1.AuditRecord) (result *v1.AuditRecord, err error) {
+	result = &v1.AuditRecord{}
+	err = c.client.Put().
+		Resource("auditrecords").
+		Name(auditRecord.Name).
+		Body(auditRecord).
+		Do().
+		Into(result)
+	return
+}
+
+// Delete takes name of the auditRecord and deletes it. Returns an error if one occurs.
+func (c *auditRecords) Delete(name string, options *metav1.DeleteOptions) error {
+	return c.client.Delete().
+		Resource("auditrecords").
+		Name(name).
+		Body(options).
+		Do().
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *auditRecords) DeleteCollection(options *metav1.DeleteOptions, listOptions metav1.ListOptions) error {
+	var timeout time.Duration
+	if listOptions.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOptions.TimeoutSeconds) * time.Second
+	}
+	return c.client.Delete().
+		Resource("auditrecords").
+		VersionedParams(&listOptions, scheme.ParameterCodec).
+		Timeout(timeout).
+		Body(options).
+		Do().
+		Error()
+}
+
+// Patch applies the patch and returns the patched auditRecord.
+func (c *auditRecords) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1.AuditRecord, err error) {
+	result = &v1.AuditRecord{}
+	err = c.client.Patch(pt).
+		Resource("auditrecords").
+		SubResource(subresources...).
+		Name(name).
+		Body(data).
+		Do().
+		Into(result)
+	return
+}
@@ -0,0 +1,164 @@
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	"time"
+
+	scheme "github.com/kubermatic/kubermatic/api/pkg/crd/client/clientset/versioned/scheme"
+	v1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+)
+
+// ClusterMigrationsGetter has a method to return a ClusterMigrationInterface.
+// A group's client should implement this interface.
+type ClusterMigrationsGetter interface {
+	ClusterMigrations() ClusterMigrationInterface
+}
+
+// ClusterMigrationInterface has methods to work with ClusterMigration resources.
+type ClusterMigrationInterface interface {
+	Create(*v1.ClusterMigration) (*v1.ClusterMigration, error)
+	Update(*v1.ClusterMigration) (*v1.ClusterMigration, error)
+	UpdateStatus(*v1.ClusterMigration) (*v1.ClusterMigration, error)
+	Delete(name string, options *metav1.DeleteOptions) error
+	DeleteCollection(options *metav1.DeleteOptions, listOptions metav1.ListOptions) error
+	Get(name string, options metav1.GetOptions) (*v1.ClusterMigration, error)
+	List(opts metav1.ListOptions) (*v1.ClusterMigrationList, error)
+	Watch(opts metav1.ListOptions) (watch.Interface, error)
+	Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1.ClusterMigration, err error)
+	ClusterMigrationExpansion
+}
+
+// clusterMigrations implements ClusterMigrationInterface
+type clusterMigrations struct {
+	client rest.Interface
+}
+
+// newClusterMigrations returns a ClusterMigrations
+func newClusterMigrations(c *KubermaticV1Client) *clusterMigrations {
+	return &clusterMigrations{
+		client: c.RESTClient(),
+	}
+}
+
+// Get takes name of the clusterMigration, and returns the corresponding clusterMigration object, and an error if there is any.
+func (c *clusterMigrations) Get(name string, options metav1.GetOptions) (result *v1.ClusterMigration, err error) {
+	result = &v1.ClusterMigration{}
+	err = c.client.Get().
+		Resource("clustermigrations").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do().
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of ClusterMigrations that match those selectors.
+func (c *clusterMigrations) List(opts metav1.ListOptions) (result *v1.ClusterMigrationList, err error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	result = &v1.ClusterMigrationList{}
+	err = c.client.Get().
+		Resource("clustermigrations").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Do().
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested clusterMigrations.
+func (c *clusterMigrations) Watch(opts metav1.ListOptions) (watch.Interface, error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	opts.Watch = true
+	return c.client.Get().
+		Resource("clustermigrations").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Watch()
+}
+
+// Create takes the representation of a clusterMigration and creates it.  Returns the server's representation of the clusterMigration, and an error, if there is any.
+func (c *clusterMigrations) Create(clusterMigration *v1.ClusterMigration) (result *v1.ClusterMigration, err error) {
+	result = &v1.ClusterMigration{}
+	err = c.client.Post().
+		Resource("clustermigrations").
+		Body(clusterMigration).
+		Do().
+		Into(result)
+	return
+}
+
+// Update takes the representation of a clusterMigration and updates it. Returns the server's representation of the clusterMigration, and an error, if there is any.
+func (c *clusterMigrations) Update(clusterMigration *v1.ClusterMigration) (result *v1.ClusterMigration, err error) {
+	result = &v1.ClusterMigration{}
+	err = c.client.Put().
+		Resource("clustermigrations").
+		Name(clusterMigration.Name).
+		Body(clusterMigration).
+		Do().
+		Into(result)
+	return
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+
+func (c *clusterMigrations) UpdateStatus(clusterMigration *v1.ClusterMigration) (result *v1.ClusterMigration, err error) {
+	result = &v1.ClusterMigration{}
+	err = c.client.Put().
+		Resource("clustermigrations").
+		Name(clusterMigration.Name).
+		SubResource("status").
+		Body(clusterMigration).
+		Do().
+		Into(result)
+	return
+}
+
+// Delete takes name of the clusterMigration and deletes it. Returns an error if one occurs.
+func (c *clusterMigrations) Delete(name string, options *metav1.DeleteOptions) error {
+	return c.client.Delete().
+		Resource("clustermigrations").
+		Name(name).
+		Body(options).
+		Do().
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *clusterMigrations) DeleteCollection(options *metav1.DeleteOptions, listOptions metav1.ListOptions) error {
+	var timeout time.Duration
+	if listOptions.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOptions.TimeoutSeconds) * time.Second
+	}
+	return c.client.Delete().
+		Resource("clustermigrations").
+		VersionedParams(&listOptions, scheme.ParameterCodec).
+		Timeout(timeout).
+		Body(options).
+		Do().
+		Error()
+}
+
+// Patch applies the patch and returns the patched clusterMigration.
+func (c *clusterMigrations) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1.ClusterMigration, err error) {
+	result = &v1.ClusterMigration{}
+	err = c.client.Patch(pt).
+		Resource("clustermigrations").
+		SubResource(subresources...).
+		Name(name).
+		Body(data).
+		Do().
+		Into(result)
+	return
+}
@@ -0,0 +1,104 @@
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	kubermaticv1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeAuditRecords implements AuditRecordInterface
+type FakeAuditRecords struct {
+	Fake *FakeKubermaticV1
+}
+
+var auditrecordsResource = schema.GroupVersionResource{Group: "kubermatic.k8s.io", Version: "v1", Resource: "auditrecords"}
+
+var auditrecordsKind = schema.GroupVersionKind{Group: "kubermatic.k8s.io", Version: "v1", Kind: "AuditRecord"}
+
+// Get takes name of the auditRecord, and returns the corresponding auditRecord object, and an error if there is any.
+func (c *FakeAuditRecords) Get(name string, options v1.GetOptions) (result *kubermaticv1.AuditRecord, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootGetAction(auditrecordsResource, name), &kubermaticv1.AuditRecord{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*kubermaticv1.AuditRecord), err
+}
+
+// List takes label and field selectors, and returns the list of AuditRecords that match those selectors.
+func (c *FakeAuditRecords) List(opts v1.ListOptions) (result *kubermaticv1.AuditRecordList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootListAction(auditrecordsResource, auditrecordsKind, opts), &kubermaticv1.AuditRecordList{})
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &kubermaticv1.AuditRecordList{ListMeta: obj.(*kubermaticv1.AuditRecordList).ListMeta}
+	for _, item := range obj.(*kubermaticv1.AuditRecordList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested auditRecords.
+func (c *FakeAuditRecords) Watch(opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewRootWatchAction(auditrecordsResource, opts))
+}
+
+// Create takes the representation of a auditRecord and creates it.  Returns the server's representation of the auditRecord, and an error, if there is any.
+func (c *FakeAuditRecords) Create(auditRecord *kubermaticv1.AuditRecord) (result *kubermaticv1.AuditRecord, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootCreateAction(auditrecordsResource, auditRecord), &kubermaticv1.AuditRecord{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*kubermaticv1.AuditRecord), err
+}
+
+// Update takes the representation of a auditRecord and updates it. Returns the server's representation of the auditRecord, and an error, if there is any.
+func (c *FakeAuditRecords) Update(auditRecord *kubermaticv1.AuditRecord) (result *kubermaticv1.AuditRecord, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootUpdateAction(auditrecordsResource, auditRecord), &kubermaticv1.AuditRecord{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*kubermaticv1.AuditRecord), err
+}
+
+// Delete takes name of the auditRecord and deletes it. Returns an error if one occurs.
+func (c *FakeAuditRecords) Delete(name string, options *v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewRootDeleteAction(auditrecordsResource, name), &kubermaticv1.AuditRecord{})
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeAuditRecords) DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error {
+	action := testing.NewRootDeleteCollectionAction(auditrecordsResource, listOptions)
+
+	_, err := c.Fake.Invokes(action, &kubermaticv1.AuditRecordList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched auditRecord.
+func (c *FakeAuditRecords) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *kubermaticv1.AuditRecord, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootPatchSubresourceAction(auditrecordsResource, name, pt, data, subresources...), &kubermaticv1.AuditRecord{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*kubermaticv1.AuditRecord), err
+}
@@ -20,10 +20,26 @@ func (c *FakeKubermaticV1) AddonConfigs() v1.AddonConfigInterface {
 	return &FakeAddonConfigs{c}
 }
 
+func (c *FakeKubermaticV1) AuditRecords() v1.AuditRecordInterface {
+	return &FakeAuditRecords{c}
+}
+
 func (c *FakeKubermaticV1) Clusters() v1.ClusterInterface {
 	return &FakeClusters{c}
 }
 
+func (c *FakeKubermaticV1) ClusterMigrations() v1.ClusterMigrationInterface {
+	return &FakeClusterMigrations{c}
+}
+
+func (c *FakeKubermaticV1) EtcdRestores(namespace string) v1.EtcdRestoreInterface {
+	return &FakeEtcdRestores{c, namespace}
+}
+
+func (c *FakeKubermaticV1) GroupProjectBindings() v1.GroupProjectBindingInterface {
+	return &FakeGroupProjectBindings{c}
+}
+
 func (c *FakeKubermaticV1) KubermaticSettings() v1.KubermaticSettingInterface {
 	return &FakeKubermaticSettings{c}
 }
@@ -32,6 +48,10 @@ func (c *FakeKubermaticV1) Projects() v1.ProjectInterface {
 	return &FakeProjects{c}
 }
 
+func (c *FakeKubermaticV1) ProjectRoles() v1.ProjectRoleInterface {
+	return &FakeProjectRoles{c}
+}
+
 func (c *FakeKubermaticV1) Users() v1.UserInterface {
 	return &FakeUsers{c}
 }
@@ -6,12 +6,22 @@ type AddonExpansion interface{}
 
 type AddonConfigExpansion interface{}
 
+type AuditRecordExpansion interface{}
+
 type ClusterExpansion interface{}
 
+type ClusterMigrationExpansion interface{}
+
+type EtcdRestoreExpansion interface{}
+
+type GroupProjectBindingExpansion interface{}
+
 type KubermaticSettingExpansion interface{}
 
 type ProjectExpansion interface{}
 
+type ProjectRoleExpansion interface{}
+
 type UserExpansion interface{}
 
 type UserProjectBindingExpansion interface{}
@@ -0,0 +1,148 @@
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	"time"
+
+	scheme "github.com/kubermatic/kubermatic/api/pkg/crd/client/clientset/versioned/scheme"
+	v1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+)
+
+// GroupProjectBindingsGetter has a method to return a GroupProjectBindingInterface.
+// A group's client should implement this interface.
+type GroupProjectBindingsGetter interface {
+	GroupProjectBindings() GroupProjectBindingInterface
+}
+
+// GroupProjectBindingInterface has methods to work with GroupProjectBinding resources.
+type GroupProjectBindingInterface interface {
+	Create(*v1.GroupProjectBinding) (*v1.GroupProjectBinding, error)
+	Update(*v1.GroupProjectBinding) (*v1.GroupProjectBinding, error)
+	Delete(name string, options *metav1.DeleteOptions) error
+	DeleteCollection(options *metav1.DeleteOptions, listOptions metav1.ListOptions) error
+	Get(name string, options metav1.GetOptions) (*v1.GroupProjectBinding, error)
+	List(opts metav1.ListOptions) (*v1.GroupProjectBindingList, error)
+	Watch(opts metav1.ListOptions) (watch.Interface, error)
+	Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1.GroupProjectBinding, err error)
+	GroupProjectBindingExpansion
+}
+
+// groupProjectBindings implements GroupProjectBindingInterface
+type groupProjectBindings struct {
+	client rest.Interface
+}
+
+// newGroupProjectBindings returns a GroupProjectBindings
+func newGroupProjectBindings(c *KubermaticV1Client) *groupProjectBindings {
+	return &groupProjectBindings{
+		client: c.RESTClient(),
+	}
+}
+
+// Get takes name of the groupProjectBinding, and returns the corresponding groupProjectBinding object, and an error if there is any.
+func (c *groupProjectBindings) Get(name string, options metav1.GetOptions) (result *v1.GroupProjectBinding, err error) {
+	result = &v1.GroupProjectBinding{}
+	err = c.client.Get().
+		Resource("groupprojectbindings").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do().
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of GroupProjectBindings that match those selectors.
+func (c *groupProjectBindings) List(opts metav1.ListOptions) (result *v1.GroupProjectBindingList, err error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	result = &v1.GroupProjectBindingList{}
+	err = c.client.Get().
+		Resource("groupprojectbindings").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Do().
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested groupProjectBindings.
+func (c *groupProjectBindings) Watch(opts metav1.ListOptions) (watch.Interface, error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	opts.Watch = true
+	return c.client.Get().
+		Resource("groupprojectbindings").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Watch()
+}
+
+// Create takes the representation of a groupProjectBinding and creates it.  Returns the server's representation of the groupProjectBinding, and an error, if there is any.
+func (c *groupProjectBindings) Create(groupProjectBinding *v1.GroupProjectBinding) (result *v1.GroupProjectBinding, err error) {
+	result = &v1.GroupProjectBinding{}
+	err = c.client.Post().
+		Resource("groupprojectbindings").
+		Body(groupProjectBinding).
+		Do().
+		Into(result)
+	return
+}
+
+// Update takes the representation of a groupProjectBinding and updates it. Returns the server's representation of the groupProjectBinding, and an error, if there is any.
+func (c *groupProjectBindings) Update(groupProjectBinding *v1.GroupProjectBinding) (result *v1.GroupProjectBinding, err error) {
+	result = &v1.GroupProjectBinding{}
+	err = c.client.Put().
+		Resource("groupprojectbindings").
+		Name(groupProjectBinding.Name).
+		Body(groupProjectBinding).
+		Do().
+		Into(result)
+	return
+}
+
+// Delete takes name of the groupProjectBinding and deletes it. Returns an error if one occurs.
+func (c *groupProjectBindings) Delete(name string, options *metav1.DeleteOptions) error {
+	return c.client.Delete().
+		Resource("groupprojectbindings").
+		Name(name).
+		Body(options).
+		Do().
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *groupProjectBindings) DeleteCollection(options *metav1.DeleteOptions, listOptions metav1.ListOptions) error {
+	var timeout time.Duration
+	if listOptions.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOptions.TimeoutSeconds) * time.Second
+	}
+	return c.client.Delete().
+		Resource("groupprojectbindings").
+		VersionedParams(&listOptions, scheme.ParameterCodec).
+		Timeout(timeout).
+		Body(options).
+		Do().
+		Error()
+}
+
+// Patch applies the patch and returns the patched groupProjectBinding.
+func (c *groupProjectBindings) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1.GroupProjectBinding, err error) {
+	result = &v1.GroupProjectBinding{}
+	err = c.client.Patch(pt).
+		Resource("groupprojectbindings").
+		SubResource(subresources...).
+		Name(name).
+		Body(data).
+		Do().
+		Into(result)
+	return
+}
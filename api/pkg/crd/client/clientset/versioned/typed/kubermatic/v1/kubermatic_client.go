@@ -12,9 +12,14 @@ type KubermaticV1Interface interface {
 	RESTClient() rest.Interface
 	AddonsGetter
 	AddonConfigsGetter
+	AuditRecordsGetter
 	ClustersGetter
+	ClusterMigrationsGetter
+	EtcdRestoresGetter
+	GroupProjectBindingsGetter
 	KubermaticSettingsGetter
 	ProjectsGetter
+	ProjectRolesGetter
 	UsersGetter
 	UserProjectBindingsGetter
 	UserSSHKeysGetter
@@ -33,10 +38,26 @@ func (c *KubermaticV1Client) AddonConfigs() AddonConfigInterface {
 	return newAddonConfigs(c)
 }
 
+func (c *KubermaticV1Client) AuditRecords() AuditRecordInterface {
+	return newAuditRecords(c)
+}
+
 func (c *KubermaticV1Client) Clusters() ClusterInterface {
 	return newClusters(c)
 }
 
+func (c *KubermaticV1Client) ClusterMigrations() ClusterMigrationInterface {
+	return newClusterMigrations(c)
+}
+
+func (c *KubermaticV1Client) EtcdRestores(namespace string) EtcdRestoreInterface {
+	return newEtcdRestores(c, namespace)
+}
+
+func (c *KubermaticV1Client) GroupProjectBindings() GroupProjectBindingInterface {
+	return newGroupProjectBindings(c)
+}
+
 func (c *KubermaticV1Client) KubermaticSettings() KubermaticSettingInterface {
 	return newKubermaticSettings(c)
 }
@@ -45,6 +66,10 @@ func (c *KubermaticV1Client) Projects() ProjectInterface {
 	return newProjects(c)
 }
 
+func (c *KubermaticV1Client) ProjectRoles() ProjectRoleInterface {
+	return newProjectRoles(c)
+}
+
 func (c *KubermaticV1Client) Users() UserInterface {
 	return newUsers(c)
 }
@@ -0,0 +1,148 @@
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	"time"
+
+	scheme "github.com/kubermatic/kubermatic/api/pkg/crd/client/clientset/versioned/scheme"
+	v1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+)
+
+// ProjectRolesGetter has a method to return a ProjectRoleInterface.
+// A group's client should implement this interface.
+type ProjectRolesGetter interface {
+	ProjectRoles() ProjectRoleInterface
+}
+
+// ProjectRoleInterface has methods to work with ProjectRole resources.
+type ProjectRoleInterface interface {
+	Create(*v1.ProjectRole) (*v1.ProjectRole, error)
+	Update(*v1.ProjectRole) (*v1.ProjectRole, error)
+	Delete(name string, options *metav1.DeleteOptions) error
+	DeleteCollection(options *metav1.DeleteOptions, listOptions metav1.ListOptions) error
+	Get(name string, options metav1.GetOptions) (*v1.ProjectRole, error)
+	List(opts metav1.ListOptions) (*v1.ProjectRoleList, error)
+	Watch(opts metav1.ListOptions) (watch.Interface, error)
+	Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1.ProjectRole, err error)
+	ProjectRoleExpansion
+}
+
+// projectRoles implements ProjectRoleInterface
+type projectRoles struct {
+	client rest.Interface
+}
+
+// newProjectRoles returns a ProjectRoles
+func newProjectRoles(c *KubermaticV1Client) *projectRoles {
+	return &projectRoles{
+		client: c.RESTClient(),
+	}
+}
+
+// Get takes name of the projectRole, and returns the corresponding projectRole object, and an error if there is any.
+func (c *projectRoles) Get(name string, options metav1.GetOptions) (result *v1.ProjectRole, err error) {
+	result = &v1.ProjectRole{}
+	err = c.client.Get().
+		Resource("projectroles").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do().
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of ProjectRoles that match those selectors.
+func (c *projectRoles) List(opts metav1.ListOptions) (result *v1.ProjectRoleList, err error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	result = &v1.ProjectRoleList{}
+	err = c.client.Get().
+		Resource("projectroles").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Do().
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested projectRoles.
+func (c *projectRoles) Watch(opts metav1.ListOptions) (watch.Interface, error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	opts.Watch = true
+	return c.client.Get().
+		Resource("projectroles").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Watch()
+}
+
+// Create takes the representation of a projectRole and creates it.  Returns the server's representation of the projectRole, and an error, if there is any.
+func (c *projectRoles) Create(projectRole *v1.ProjectRole) (result *v1.ProjectRole, err error) {
+	result = &v1.ProjectRole{}
+	err = c.client.Post().
+		Resource("projectroles").
+		Body(projectRole).
+		Do().
+		Into(result)
+	return
+}
+
+// Update takes the representation of a projectRole and updates it. Returns the server's representation of the projectRole, and an error, if there is any.
+func (c *projectRoles) Update(projectRole *v1.ProjectRole) (result *v1.ProjectRole, err error) {
+	result = &v1.ProjectRole{}
+	err = c.client.Put().
+		Resource("projectroles").
+		Name(projectRole.Name).
+		Body(projectRole).
+		Do().
+		Into(result)
+	return
+}
+
+// Delete takes name of the projectRole and deletes it. Returns an error if one occurs.
+func (c *projectRoles) Delete(name string, options *metav1.DeleteOptions) error {
+	return c.client.Delete().
+		Resource("projectroles").
+		Name(name).
+		Body(options).
+		Do().
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *projectRoles) DeleteCollection(options *metav1.DeleteOptions, listOptions metav1.ListOptions) error {
+	var timeout time.Duration
+	if listOptions.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOptions.TimeoutSeconds) * time.Second
+	}
+	return c.client.Delete().
+		Resource("projectroles").
+		VersionedParams(&listOptions, scheme.ParameterCodec).
+		Timeout(timeout).
+		Body(options).
+		Do().
+		Error()
+}
+
+// Patch applies the patch and returns the patched projectRole.
+func (c *projectRoles) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1.ProjectRole, err error) {
+	result = &v1.ProjectRole{}
+	err = c.client.Patch(pt).
+		Resource("projectroles").
+		SubResource(subresources...).
+		Name(name).
+		Body(data).
+		Do().
+		Into(result)
+	return
+}
@@ -41,12 +41,22 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Kubermatic().V1().Addons().Informer()}, nil
 	case v1.SchemeGroupVersion.WithResource("addonconfigs"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Kubermatic().V1().AddonConfigs().Informer()}, nil
+	case v1.SchemeGroupVersion.WithResource("auditrecords"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Kubermatic().V1().AuditRecords().Informer()}, nil
 	case v1.SchemeGroupVersion.WithResource("clusters"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Kubermatic().V1().Clusters().Informer()}, nil
+	case v1.SchemeGroupVersion.WithResource("clustermigrations"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Kubermatic().V1().ClusterMigrations().Informer()}, nil
+	case v1.SchemeGroupVersion.WithResource("etcdrestores"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Kubermatic().V1().EtcdRestores().Informer()}, nil
+	case v1.SchemeGroupVersion.WithResource("groupprojectbindings"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Kubermatic().V1().GroupProjectBindings().Informer()}, nil
 	case v1.SchemeGroupVersion.WithResource("kubermaticsettings"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Kubermatic().V1().KubermaticSettings().Informer()}, nil
 	case v1.SchemeGroupVersion.WithResource("projects"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Kubermatic().V1().Projects().Informer()}, nil
+	case v1.SchemeGroupVersion.WithResource("projectroles"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Kubermatic().V1().ProjectRoles().Informer()}, nil
 	case v1.SchemeGroupVersion.WithResource("users"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Kubermatic().V1().Users().Informer()}, nil
 	case v1.SchemeGroupVersion.WithResource("userprojectbindings"):
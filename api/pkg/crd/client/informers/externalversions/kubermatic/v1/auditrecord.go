@@ -0,0 +1,72 @@
+// Code generated by informer-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	time "time"
+
+	versioned "github.com/kubermatic/kubermatic/api/pkg/crd/client/clientset/versioned"
+	internalinterfaces "github.com/kubermatic/kubermatic/api/pkg/crd/client/informers/externalversions/internalinterfaces"
+	v1 "github.com/kubermatic/kubermatic/api/pkg/crd/client/listers/kubermatic/v1"
+	kubermaticv1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	watch "k8s.io/apimachinery/pkg/watch"
+	cache "k8s.io/client-go/tools/cache"
+)
+
+// AuditRecordInformer provides access to a shared informer and lister for
+// AuditRecords.
+type AuditRecordInformer interface {
+	Informer() cache.SharedIndexInformer
+	Lister() v1.AuditRecordLister
+}
+
+type auditRecordInformer struct {
+	factory          internalinterfaces.SharedInformerFactory
+	tweakListOptions internalinterfaces.TweakListOptionsFunc
+}
+
+// NewAuditRecordInformer constructs a new informer for AuditRecord type.
+// Always prefer using an informer factory to get a shared informer instead of getting an independent
+// one. This reduces memory footprint and number of connections to the server.
+func NewAuditRecordInformer(client versioned.Interface, resyncPeriod time.Duration, indexers cache.Indexers) cache.SharedIndexInformer {
+	return NewFilteredAuditRecordInformer(client, resyncPeriod, indexers, nil)
+}
+
+// NewFilteredAuditRecordInformer constructs a new informer for AuditRecord type.
+// Always prefer using an informer factory to get a shared informer instead of getting an independent
+// one. This reduces memory footprint and number of connections to the server.
+func NewFilteredAuditRecordInformer(client versioned.Interface, resyncPeriod time.Duration, indexers cache.Indexers, tweakListOptions internalinterfaces.TweakListOptionsFunc) cache.SharedIndexInformer {
+	return cache.NewSharedIndexInformer(
+		&cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.KubermaticV1().AuditRecords().List(options)
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.KubermaticV1().AuditRecords().Watch(options)
+			},
+		},
+		&kubermaticv1.AuditRecord{},
+		resyncPeriod,
+		indexers,
+	)
+}
+
+func (f *auditRecordInformer) defaultInformer(client versioned.Interface, resyncPeriod time.Duration) cache.SharedIndexInformer {
+	return NewFilteredAuditRecordInformer(client, resyncPeriod, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc}, f.tweakListOptions)
+}
+
+func (f *auditRecordInformer) Informer() cache.SharedIndexInformer {
+	return f.factory.InformerFor(&kubermaticv1.AuditRecord{}, f.defaultInformer)
+}
+
+func (f *auditRecordInformer) Lister() v1.AuditRecordLister {
+	return v1.NewAuditRecordLister(f.Informer().GetIndexer())
+}
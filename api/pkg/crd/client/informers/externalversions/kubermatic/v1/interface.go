@@ -12,12 +12,22 @@ type Interface interface {
 	Addons() AddonInformer
 	// AddonConfigs returns a AddonConfigInformer.
 	AddonConfigs() AddonConfigInformer
+	// AuditRecords returns a AuditRecordInformer.
+	AuditRecords() AuditRecordInformer
 	// Clusters returns a ClusterInformer.
 	Clusters() ClusterInformer
+	// ClusterMigrations returns a ClusterMigrationInformer.
+	ClusterMigrations() ClusterMigrationInformer
+	// EtcdRestores returns a EtcdRestoreInformer.
+	EtcdRestores() EtcdRestoreInformer
+	// GroupProjectBindings returns a GroupProjectBindingInformer.
+	GroupProjectBindings() GroupProjectBindingInformer
 	// KubermaticSettings returns a KubermaticSettingInformer.
 	KubermaticSettings() KubermaticSettingInformer
 	// Projects returns a ProjectInformer.
 	Projects() ProjectInformer
+	// ProjectRoles returns a ProjectRoleInformer.
+	ProjectRoles() ProjectRoleInformer
 	// Users returns a UserInformer.
 	Users() UserInformer
 	// UserProjectBindings returns a UserProjectBindingInformer.
@@ -47,11 +57,31 @@ func (v *version) AddonConfigs() AddonConfigInformer {
 	return &addonConfigInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
 }
 
+// AuditRecords returns a AuditRecordInformer.
+func (v *version) AuditRecords() AuditRecordInformer {
+	return &auditRecordInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
 // Clusters returns a ClusterInformer.
 func (v *version) Clusters() ClusterInformer {
 	return &clusterInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
 }
 
+// ClusterMigrations returns a ClusterMigrationInformer.
+func (v *version) ClusterMigrations() ClusterMigrationInformer {
+	return &clusterMigrationInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
+// EtcdRestores returns a EtcdRestoreInformer.
+func (v *version) EtcdRestores() EtcdRestoreInformer {
+	return &etcdRestoreInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// GroupProjectBindings returns a GroupProjectBindingInformer.
+func (v *version) GroupProjectBindings() GroupProjectBindingInformer {
+	return &groupProjectBindingInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
 // KubermaticSettings returns a KubermaticSettingInformer.
 func (v *version) KubermaticSettings() KubermaticSettingInformer {
 	return &kubermaticSettingInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
@@ -62,6 +92,11 @@ func (v *version) Projects() ProjectInformer {
 	return &projectInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
 }
 
+// ProjectRoles returns a ProjectRoleInformer.
+func (v *version) ProjectRoles() ProjectRoleInformer {
+	return &projectRoleInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
 // Users returns a UserInformer.
 func (v *version) Users() UserInformer {
 	return &userInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
@@ -0,0 +1,49 @@
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	v1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// AuditRecordLister helps list AuditRecords.
+type AuditRecordLister interface {
+	// List lists all AuditRecords in the indexer.
+	List(selector labels.Selector) (ret []*v1.AuditRecord, err error)
+	// Get retrieves the AuditRecord from the index for a given name.
+	Get(name string) (*v1.AuditRecord, error)
+	AuditRecordListerExpansion
+}
+
+// auditRecordLister implements the AuditRecordLister interface.
+type auditRecordLister struct {
+	indexer cache.Indexer
+}
+
+// NewAuditRecordLister returns a new AuditRecordLister.
+func NewAuditRecordLister(indexer cache.Indexer) AuditRecordLister {
+	return &auditRecordLister{indexer: indexer}
+}
+
+// List lists all AuditRecords in the indexer.
+func (s *auditRecordLister) List(selector labels.Selector) (ret []*v1.AuditRecord, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1.AuditRecord))
+	})
+	return ret, err
+}
+
+// Get retrieves the AuditRecord from the index for a given name.
+func (s *auditRecordLister) Get(name string) (*v1.AuditRecord, error) {
+	obj, exists, err := s.indexer.GetByKey(name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1.Resource("auditrecord"), name)
+	}
+	return obj.(*v1.AuditRecord), nil
+}
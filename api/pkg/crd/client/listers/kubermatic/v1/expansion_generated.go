@@ -14,10 +14,30 @@ type AddonNamespaceListerExpansion interface{}
 // AddonConfigLister.
 type AddonConfigListerExpansion interface{}
 
+// AuditRecordListerExpansion allows custom methods to be added to
+// AuditRecordLister.
+type AuditRecordListerExpansion interface{}
+
 // ClusterListerExpansion allows custom methods to be added to
 // ClusterLister.
 type ClusterListerExpansion interface{}
 
+// ClusterMigrationListerExpansion allows custom methods to be added to
+// ClusterMigrationLister.
+type ClusterMigrationListerExpansion interface{}
+
+// EtcdRestoreListerExpansion allows custom methods to be added to
+// EtcdRestoreLister.
+type EtcdRestoreListerExpansion interface{}
+
+// EtcdRestoreNamespaceListerExpansion allows custom methods to be added to
+// EtcdRestoreNamespaceLister.
+type EtcdRestoreNamespaceListerExpansion interface{}
+
+// GroupProjectBindingListerExpansion allows custom methods to be added to
+// GroupProjectBindingLister.
+type GroupProjectBindingListerExpansion interface{}
+
 // KubermaticSettingListerExpansion allows custom methods to be added to
 // KubermaticSettingLister.
 type KubermaticSettingListerExpansion interface{}
@@ -26,6 +46,10 @@ type KubermaticSettingListerExpansion interface{}
 // ProjectLister.
 type ProjectListerExpansion interface{}
 
+// ProjectRoleListerExpansion allows custom methods to be added to
+// ProjectRoleLister.
+type ProjectRoleListerExpansion interface{}
+
 // UserListerExpansion allows custom methods to be added to
 // UserLister.
 type UserListerExpansion interface{}
@@ -0,0 +1,49 @@
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	v1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// GroupProjectBindingLister helps list GroupProjectBindings.
+type GroupProjectBindingLister interface {
+	// List lists all GroupProjectBindings in the indexer.
+	List(selector labels.Selector) (ret []*v1.GroupProjectBinding, err error)
+	// Get retrieves the GroupProjectBinding from the index for a given name.
+	Get(name string) (*v1.GroupProjectBinding, error)
+	GroupProjectBindingListerExpansion
+}
+
+// groupProjectBindingLister implements the GroupProjectBindingLister interface.
+type groupProjectBindingLister struct {
+	indexer cache.Indexer
+}
+
+// NewGroupProjectBindingLister returns a new GroupProjectBindingLister.
+func NewGroupProjectBindingLister(indexer cache.Indexer) GroupProjectBindingLister {
+	return &groupProjectBindingLister{indexer: indexer}
+}
+
+// List lists all GroupProjectBindings in the indexer.
+func (s *groupProjectBindingLister) List(selector labels.Selector) (ret []*v1.GroupProjectBinding, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1.GroupProjectBinding))
+	})
+	return ret, err
+}
+
+// Get retrieves the GroupProjectBinding from the index for a given name.
+func (s *groupProjectBindingLister) Get(name string) (*v1.GroupProjectBinding, error) {
+	obj, exists, err := s.indexer.GetByKey(name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1.Resource("groupprojectbinding"), name)
+	}
+	return obj.(*v1.GroupProjectBinding), nil
+}
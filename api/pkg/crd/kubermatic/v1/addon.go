@@ -31,6 +31,11 @@ const (
 	AddonKindName = "Addon"
 
 	AddonResourcesCreated AddonConditionType = "AddonResourcesCreatedSuccessfully"
+	// AddonReady is true when all objects of the addon are ready
+	AddonReady AddonConditionType = "AddonReady"
+	// AddonDrifted is true when objects of the addon differ from the rendered manifests,
+	// e.g. because they have been edited or deleted in the user cluster
+	AddonDrifted AddonConditionType = "AddonDrifted"
 )
 
 //+genclient
@@ -60,6 +65,13 @@ type AddonSpec struct {
 	RequiredResourceTypes []schema.GroupVersionKind `json:"requiredResourceTypes,omitempty"`
 	// IsDefault indicates whether the addon is default
 	IsDefault bool `json:"isDefault,omitempty"`
+	// Version of the addon. When it changes for an installed addon, the Jobs in the addon manifests
+	// that are annotated as pre-upgrade or post-upgrade hooks are run before and after the manifests
+	// are applied. The version is recorded in the status once the upgrade has finished.
+	Version string `json:"version,omitempty"`
+	// Dependencies are the names of the addons of the same cluster that have to be installed before
+	// this addon can be installed, e.g. a CSI driver an ingress controller depends on.
+	Dependencies []string `json:"dependencies,omitempty"`
 }
 
 // AddonList is a list of addons
@@ -73,6 +85,30 @@ type AddonList struct {
 
 type AddonStatus struct {
 	Conditions []AddonCondition `json:"conditions,omitempty"`
+	// InstalledVersion is the version of the addon whose manifests have been applied and whose
+	// upgrade hooks have completed
+	InstalledVersion string `json:"installedVersion,omitempty"`
+	// Objects are the objects applied for the addon with their readiness and drift, they are
+	// checked whenever the addon gets reconciled and at least every five minutes
+	Objects []AddonObjectStatus `json:"objects,omitempty"`
+}
+
+// AddonObjectStatus is the state of an object applied for an addon in the user cluster
+type AddonObjectStatus struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name"`
+	// Ready indicates whether the object is ready. Deployments are ready when they are available,
+	// DaemonSets when they are rolled out and CustomResourceDefinitions when they are established,
+	// all other objects as soon as they exist.
+	Ready bool `json:"ready"`
+	// Drifted indicates whether the object differs from the rendered manifest
+	Drifted bool `json:"drifted"`
+	// DriftedFields are the paths of the fields whose values differ from the rendered manifest
+	DriftedFields []string `json:"driftedFields,omitempty"`
+	// Message explains why the object isn't ready
+	Message string `json:"message,omitempty"`
 }
 
 type AddonConditionType string
@@ -88,4 +124,11 @@ type AddonCondition struct {
 	// Last time the condition transit from one status to another.
 	// +optional
 	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+	// (brief) reason for the condition's last transition.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+	// Human readable message indicating details about last transition, e.g. the objects that
+	// couldn't be applied.
+	// +optional
+	Message string `json:"message,omitempty"`
 }
@@ -55,10 +55,24 @@ type AddonFormControl struct {
 	InternalName string `json:"internalName,omitempty"`
 	// Required indicates if the control has to be set
 	Required bool `json:"required,omitempty"`
-	// Type of displayed control
+	// Type of displayed control, the values of the "number", "boolean", "text" and "text-area"
+	// controls are validated to be of the matching type
 	Type string `json:"type,omitempty"`
+	// Default is the value used when the control hasn't been set, it is converted to the type of the control
+	Default string `json:"default,omitempty"`
+	// Enum is the list of values the control accepts
+	Enum []string `json:"enum,omitempty"`
+	// Pattern is a regular expression the value of the control has to match
+	Pattern string `json:"pattern,omitempty"`
 }
 
+const (
+	AddonFormControlTypeNumber   = "number"
+	AddonFormControlTypeBoolean  = "boolean"
+	AddonFormControlTypeText     = "text"
+	AddonFormControlTypeTextArea = "text-area"
+)
+
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
 // AddonConfigList is a list of addon configs
@@ -0,0 +1,80 @@
+/*
+Copyright 2020 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// AuditRecordResourceName represents "Resource" defined in Kubernetes
+	AuditRecordResourceName = "auditrecords"
+
+	// AuditRecordKindName represents "Kind" defined in Kubernetes
+	AuditRecordKindName = "AuditRecord"
+)
+
+//+genclient
+//+genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// AuditRecord is an entry of the audit trail of the mutations performed through the Kubermatic API
+type AuditRecord struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec AuditRecordSpec `json:"spec"`
+}
+
+// AuditRecordSpec specifies who did what and how it ended
+type AuditRecordSpec struct {
+	// Timestamp is the time the request was handled at
+	Timestamp metav1.Time `json:"timestamp"`
+	// Actor is the email address of the user or the service account that sent the request
+	Actor string `json:"actor"`
+	// ProjectID is the project the request was sent for, empty for requests outside of a project
+	ProjectID string `json:"projectID,omitempty"`
+	// Verb is one of "create", "update", "patch" and "delete"
+	Verb string `json:"verb"`
+	// Resource is the kind of the resource, for example "cluster", "nodedeployment" or "serviceaccounttoken"
+	Resource string `json:"resource"`
+	// Path is the path of the request, it contains the IDs of the resources
+	Path string `json:"path"`
+	// Request is the request as JSON with all credentials redacted,
+	// for patch requests it contains the JSON merge patch sent by the actor
+	Request string `json:"request,omitempty"`
+	// Before is the state of the resource before the update or patch request as JSON with all credentials redacted
+	Before string `json:"before,omitempty"`
+	// After is the state of the resource after the successful create, update or patch request as JSON with all credentials redacted
+	After string `json:"after,omitempty"`
+	// Succeeded tells whether the request succeeded
+	Succeeded bool `json:"succeeded"`
+	// StatusCode is the HTTP status code of the failed request
+	StatusCode int `json:"statusCode,omitempty"`
+	// Error is the error message of the failed request
+	Error string `json:"error,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// AuditRecordList specifies a list of audit records
+type AuditRecordList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []AuditRecord `json:"items"`
+}
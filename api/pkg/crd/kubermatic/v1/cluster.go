@@ -124,6 +124,12 @@ type ClusterSpec struct {
 	AdmissionPlugins                    []string `json:"admissionPlugins,omitempty"`
 
 	AuditLogging *AuditLoggingSettings `json:"auditLogging,omitempty"`
+
+	// Optional: BackupPolicy overrides the etcd backup policy of the datacenter
+	BackupPolicy *EtcdBackupPolicy `json:"backupPolicy,omitempty"`
+
+	// Optional: EncryptionAtRest enables the encryption of Secrets stored in the etcd of the cluster
+	EncryptionAtRest *EncryptionAtRestSettings `json:"encryptionAtRest,omitempty"`
 }
 
 const (
@@ -137,13 +143,29 @@ const (
 	ClusterFeatureRancherIntegration = "rancherIntegration"
 )
 
+// EtcdBackupPolicy configures the etcd backups of a cluster. Unset fields of a cluster policy
+// are taken from the policy of its datacenter and then from the defaults of the seed-controller-manager.
+type EtcdBackupPolicy struct {
+	// Schedule is a cron expression for the backups, e.g. "0 */6 * * *" or "@every 20m"
+	Schedule string `json:"schedule,omitempty"`
+	// Keep is the number of snapshots to keep, older snapshots get deleted after every backup
+	Keep *int32 `json:"keep,omitempty"`
+	// Destination is the name of a backup destination configured in the Seed
+	Destination string `json:"destination,omitempty"`
+}
+
 // ClusterConditionType is used to indicate the type of a cluster condition. For all condition
 // types, the `true` value must indicate success. All condition types must be registered within
 // the `AllClusterConditionTypes` variable.
 type ClusterConditionType string
 
+// UpdateWindow restricts automatic updates of the control plane, the MachineDeployments and
+// the node operating systems to a recurring period of time.
 type UpdateWindow struct {
-	Start  string `json:"start,omitempty"`
+	// Start is the start of the window in UTC, either "15:04" for a daily window
+	// or a weekday and a time like "Thu 15:04" for a weekly window
+	Start string `json:"start,omitempty"`
+	// Length is the duration of the window like "1h", it must be shorter than its period
 	Length string `json:"length,omitempty"`
 }
 
@@ -153,22 +175,60 @@ const (
 	// the status of cloud provider resources for a given cluster.
 	ClusterConditionSeedResourcesUpToDate ClusterConditionType = "SeedResourcesUpToDate"
 
-	ClusterConditionClusterControllerReconcilingSuccess        ClusterConditionType = "ClusterControllerReconciledSuccessfully"
-	ClusterConditionAddonControllerReconcilingSuccess          ClusterConditionType = "AddonControllerReconciledSuccessfully"
-	ClusterConditionAddonInstallerControllerReconcilingSuccess ClusterConditionType = "AddonInstallerControllerReconciledSuccessfully"
-	ClusterConditionBackupControllerReconcilingSuccess         ClusterConditionType = "BackupControllerReconciledSuccessfully"
-	ClusterConditionCloudControllerReconcilingSuccess          ClusterConditionType = "CloudControllerReconcilledSuccessfully"
-	ClusterConditionComponentDefaulterReconcilingSuccess       ClusterConditionType = "ComponentDefaulterReconciledSuccessfully"
-	ClusterConditionUpdateControllerReconcilingSuccess         ClusterConditionType = "UpdateControllerReconciledSuccessfully"
-	ClusterConditionMonitoringControllerReconcilingSuccess     ClusterConditionType = "MonitoringControllerReconciledSuccessfully"
-	ClusterConditionOpenshiftControllerReconcilingSuccess      ClusterConditionType = "OpenshiftControllerReconciledSuccessfully"
-	ClusterConditionClusterInitialized                         ClusterConditionType = "ClusterInitialized"
+	ClusterConditionClusterControllerReconcilingSuccess          ClusterConditionType = "ClusterControllerReconciledSuccessfully"
+	ClusterConditionAddonControllerReconcilingSuccess            ClusterConditionType = "AddonControllerReconciledSuccessfully"
+	ClusterConditionAddonInstallerControllerReconcilingSuccess   ClusterConditionType = "AddonInstallerControllerReconciledSuccessfully"
+	ClusterConditionBackupControllerReconcilingSuccess           ClusterConditionType = "BackupControllerReconciledSuccessfully"
+	ClusterConditionCloudControllerReconcilingSuccess            ClusterConditionType = "CloudControllerReconcilledSuccessfully"
+	ClusterConditionComponentDefaulterReconcilingSuccess         ClusterConditionType = "ComponentDefaulterReconciledSuccessfully"
+	ClusterConditionUpdateControllerReconcilingSuccess           ClusterConditionType = "UpdateControllerReconciledSuccessfully"
+	ClusterConditionMonitoringControllerReconcilingSuccess       ClusterConditionType = "MonitoringControllerReconciledSuccessfully"
+	ClusterConditionOpenshiftControllerReconcilingSuccess        ClusterConditionType = "OpenshiftControllerReconciledSuccessfully"
+	ClusterConditionCertificateControllerReconcilingSuccess      ClusterConditionType = "CertificateControllerReconciledSuccessfully"
+	ClusterConditionEncryptionAtRestControllerReconcilingSuccess ClusterConditionType = "EncryptionAtRestControllerReconciledSuccessfully"
+	ClusterConditionClusterInitialized                           ClusterConditionType = "ClusterInitialized"
+
+	// ClusterConditionEtcdPeerTLSEnabled indicates that the etcd members only talk to each other
+	// using mutual TLS. While the members of an existing cluster are migrated to TLS, the reason
+	// contains the current migration step.
+	ClusterConditionEtcdPeerTLSEnabled ClusterConditionType = "EtcdPeerTLSEnabled"
+
+	// ClusterConditionRootCARotation reports the progress of a rotation of the root CA. The reason
+	// contains the last step that was applied and is true once the old root CA got removed.
+	ClusterConditionRootCARotation ClusterConditionType = "RootCARotation"
 
 	ClusterConditionRancherInitialized     ClusterConditionType = "RancherInitializedSuccessfully"
 	ClusterConditionRancherClusterImported ClusterConditionType = "RancherClusterImportedSuccessfully"
 
 	ReasonClusterUpdateSuccessful = "ClusterUpdateSuccessful"
 	ReasonClusterUpdateInProgress = "ClusterUpdateInProgress"
+
+	// ReasonEtcdPeerTLSListening means the etcd members accept TLS peer connections in addition
+	// to the plaintext ones, but still advertise their plaintext peer URLs
+	ReasonEtcdPeerTLSListening = "ListeningOnTLSPeerPort"
+	// ReasonEtcdPeerTLSUpdatingPeerURLs means the etcd members replace their advertised plaintext
+	// peer URLs with the TLS ones
+	ReasonEtcdPeerTLSUpdatingPeerURLs = "UpdatingPeerURLs"
+	// ReasonEtcdPeerTLSEnabled means the etcd members only accept TLS peer connections
+	ReasonEtcdPeerTLSEnabled = "PeerTLSEnabled"
+
+	// ReasonRootCATrustingNewCA means a new root CA was created and is trusted in addition to the
+	// old one, which still signs all certificates
+	ReasonRootCATrustingNewCA = "TrustingNewCA"
+	// ReasonRootCASigningWithNewCA means all certificates get signed by the new root CA, the old
+	// one is still trusted
+	ReasonRootCASigningWithNewCA = "SigningWithNewCA"
+	// ReasonRootCARemovedOldCA means the old root CA is not trusted anymore
+	ReasonRootCARemovedOldCA = "RemovedOldCA"
+
+	// RootCARotationAnnotation requests a rotation of the root CA of a cluster. Its value is the
+	// step the rotation should advance to, one of TrustingNewCA, SigningWithNewCA or RemovedOldCA.
+	// Steps are applied in order and only after the previous one got rolled out.
+	RootCARotationAnnotation = "kubermatic.io/root-ca-rotation"
+
+	// EncryptionKeyRotationAnnotation requests a rotation of the key which encrypts the Secrets
+	// in the etcd of a cluster. It gets removed as soon as the rotation started.
+	EncryptionKeyRotationAnnotation = "kubermatic.io/rotate-encryption-key"
 )
 
 var AllClusterConditionTypes = []ClusterConditionType{
@@ -181,6 +241,8 @@ var AllClusterConditionTypes = []ClusterConditionType{
 	ClusterConditionUpdateControllerReconcilingSuccess,
 	ClusterConditionMonitoringControllerReconcilingSuccess,
 	ClusterConditionOpenshiftControllerReconcilingSuccess,
+	ClusterConditionCertificateControllerReconcilingSuccess,
+	ClusterConditionEncryptionAtRestControllerReconcilingSuccess,
 }
 
 type ClusterCondition struct {
@@ -245,6 +307,60 @@ type ClusterStatus struct {
 
 	// InheritedLabels are labels the cluster inherited from the project. They are read-only for users.
 	InheritedLabels map[string]string `json:"inheritedLabels,omitempty"`
+
+	// Backup contains information about the etcd backups of the cluster
+	Backup *EtcdBackupStatus `json:"backup,omitempty"`
+
+	// EncryptionAtRest contains information about the encryption of Secrets in the etcd of the cluster
+	EncryptionAtRest *EncryptionAtRestStatus `json:"encryptionAtRest,omitempty"`
+}
+
+// EncryptionAtRestPhase is the state of the encryption of Secrets in the etcd of a cluster
+type EncryptionAtRestPhase string
+
+const (
+	// EncryptionAtRestPhasePending means the apiservers start to encrypt Secrets with a new key.
+	// Existing Secrets get encrypted with it as soon as all apiservers use it.
+	EncryptionAtRestPhasePending EncryptionAtRestPhase = "Pending"
+	// EncryptionAtRestPhaseKeyAdded means a new key got added during a key rotation. It is used
+	// for encryption as soon as all apiservers are able to decrypt Secrets with it.
+	EncryptionAtRestPhaseKeyAdded EncryptionAtRestPhase = "KeyAdded"
+	// EncryptionAtRestPhaseActive means all Secrets are encrypted with the active key
+	EncryptionAtRestPhaseActive EncryptionAtRestPhase = "Active"
+)
+
+// EncryptionAtRestStatus stores the state of the encryption of Secrets in the etcd of a cluster
+type EncryptionAtRestStatus struct {
+	// ActiveKey is the name of the key all Secrets are encrypted with
+	ActiveKey string `json:"activeKey,omitempty"`
+	// ActiveSince is the time all Secrets got encrypted with the active key. Older keys are kept
+	// until the key retention passed, so etcd backups taken before are still readable.
+	ActiveSince *metav1.Time `json:"activeSince,omitempty"`
+	// Phase is the state of the encryption, one of Pending, KeyAdded or Active
+	Phase EncryptionAtRestPhase `json:"phase,omitempty"`
+}
+
+// EtcdBackupStatus stores information about the etcd backups of a cluster
+type EtcdBackupStatus struct {
+	// LastSuccessfulBackupTime is the completion time of the last successful backup job
+	LastSuccessfulBackupTime *metav1.Time `json:"lastSuccessfulBackupTime,omitempty"`
+	// LastFailedBackupTime is the time the last backup job failed
+	LastFailedBackupTime *metav1.Time `json:"lastFailedBackupTime,omitempty"`
+	// LastFailureMessage is the reason the last backup job failed
+	LastFailureMessage string `json:"lastFailureMessage,omitempty"`
+	// LatestSnapshot is the newest snapshot in the backup destination. It is only
+	// known for clusters with a backup destination configured in the Seed.
+	LatestSnapshot *EtcdSnapshot `json:"latestSnapshot,omitempty"`
+}
+
+// EtcdSnapshot describes an etcd snapshot stored in a backup bucket
+type EtcdSnapshot struct {
+	// Name is the object key of the snapshot in the bucket. It can be used as the backupName of an EtcdRestore.
+	Name string `json:"name"`
+	// Size of the snapshot in bytes
+	Size int64 `json:"size"`
+	// CreationTimestamp is the time the snapshot was uploaded
+	CreationTimestamp metav1.Time `json:"creationTimestamp"`
 }
 
 // HasConditionValue returns true if the cluster status has the given condition with the given status.
@@ -284,14 +400,76 @@ type OIDCSettings struct {
 
 type AuditLoggingSettings struct {
 	Enabled bool `json:"enabled,omitempty"`
+	// PolicyPreset is the audit policy of the apiserver, defaults to metadata
+	PolicyPreset AuditPolicyPreset `json:"policyPreset,omitempty"`
+	// CustomPolicyConfigMap is the name of a ConfigMap in the cluster namespace, whose "policy.yaml"
+	// key contains the audit policy of the apiserver. The name must start with "audit-policy-".
+	// It takes precedence over the PolicyPreset.
+	CustomPolicyConfigMap string `json:"customPolicyConfigMap,omitempty"`
+	// Webhook configures a webhook backend, which receives the audit events in addition to the log
+	Webhook *AuditWebhookBackendSettings `json:"webhook,omitempty"`
+}
+
+// AuditPolicyPreset is a predefined audit policy
+type AuditPolicyPreset string
+
+const (
+	// AuditPolicyPresetMetadata logs the metadata of all requests
+	AuditPolicyPresetMetadata AuditPolicyPreset = "metadata"
+	// AuditPolicyPresetRecommended logs the metadata of all requests and the request bodies of all
+	// changes, except for Secrets, ConfigMaps and TokenReviews, which could contain sensitive data
+	AuditPolicyPresetRecommended AuditPolicyPreset = "recommended"
+	// AuditPolicyPresetAllRequests logs the request and response bodies of all requests
+	AuditPolicyPresetAllRequests AuditPolicyPreset = "all-requests"
+)
+
+// AuditPolicyPresets are all audit policy presets, ordered from the least to the most detailed one
+var AuditPolicyPresets = []AuditPolicyPreset{AuditPolicyPresetMetadata, AuditPolicyPresetRecommended, AuditPolicyPresetAllRequests}
+
+// AuditWebhookBackendSettings configures a webhook which receives the audit events
+type AuditWebhookBackendSettings struct {
+	// URL of the webhook, the audit events are sent as an audit.k8s.io/v1 EventList
+	URL string `json:"url"`
+	// CABundle contains the PEM encoded certificates to verify the serving certificate of the webhook.
+	// The system trust store is used if empty.
+	CABundle string `json:"caBundle,omitempty"`
+	// Mode is the strategy for sending the audit events, defaults to batch
+	Mode AuditWebhookMode `json:"mode,omitempty"`
+}
+
+// AuditWebhookMode is the strategy for sending audit events to a webhook
+type AuditWebhookMode string
+
+const (
+	// AuditWebhookModeBatch buffers the events and sends them asynchronously
+	AuditWebhookModeBatch AuditWebhookMode = "batch"
+	// AuditWebhookModeBlocking blocks the apiserver response on sending each event
+	AuditWebhookModeBlocking AuditWebhookMode = "blocking"
+)
+
+// EncryptionAtRestProvider is the provider which encrypts the Secrets in the etcd of a cluster
+type EncryptionAtRestProvider string
+
+const (
+	EncryptionAtRestProviderAESCBC    EncryptionAtRestProvider = "aescbc"
+	EncryptionAtRestProviderSecretbox EncryptionAtRestProvider = "secretbox"
+)
+
+// EncryptionAtRestSettings configures the encryption of Secrets in the etcd of a cluster. Once
+// enabled, the encryption can not be disabled anymore.
+type EncryptionAtRestSettings struct {
+	Enabled bool `json:"enabled,omitempty"`
+	// Provider is used to encrypt the Secrets, either aescbc or secretbox. Defaults to aescbc.
+	// A changed provider is used starting with the next key rotation.
+	Provider EncryptionAtRestProvider `json:"provider,omitempty"`
 }
 
 type ComponentSettings struct {
-	Apiserver         APIServerSettings   `json:"apiserver"`
-	ControllerManager DeploymentSettings  `json:"controllerManager"`
-	Scheduler         DeploymentSettings  `json:"scheduler"`
-	Etcd              StatefulSetSettings `json:"etcd"`
-	Prometheus        StatefulSetSettings `json:"prometheus"`
+	Apiserver         APIServerSettings       `json:"apiserver"`
+	ControllerManager DeploymentSettings      `json:"controllerManager"`
+	Scheduler         DeploymentSettings      `json:"scheduler"`
+	Etcd              EtcdStatefulSetSettings `json:"etcd"`
+	Prometheus        StatefulSetSettings     `json:"prometheus"`
 }
 
 type APIServerSettings struct {
@@ -309,6 +487,13 @@ type StatefulSetSettings struct {
 	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
 }
 
+type EtcdStatefulSetSettings struct {
+	// ClusterSize is the number of etcd members, one of 1, 3, 5 or 7. Defaults to 3.
+	// Running clusters are resized by adding or removing one member at a time.
+	ClusterSize *int32                       `json:"clusterSize,omitempty"`
+	Resources   *corev1.ResourceRequirements `json:"resources,omitempty"`
+}
+
 // ClusterNetworkingConfig specifies the different networking
 // parameters for a cluster.
 type ClusterNetworkingConfig struct {
@@ -0,0 +1,155 @@
+/*
+Copyright 2020 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ClusterMigrationResourceName represents "Resource" defined in Kubernetes
+	ClusterMigrationResourceName = "clustermigrations"
+
+	// ClusterMigrationKindName represents "Kind" defined in Kubernetes
+	ClusterMigrationKindName = "ClusterMigration"
+)
+
+// ClusterMigrationPhase is the overall phase of a migration
+type ClusterMigrationPhase string
+
+const (
+	// ClusterMigrationPhaseRunning means the migration has been validated and its steps are being processed
+	ClusterMigrationPhaseRunning ClusterMigrationPhase = "Running"
+	// ClusterMigrationPhaseCompleted means the cluster runs in the target datacenter and the source has been cleaned up
+	ClusterMigrationPhaseCompleted ClusterMigrationPhase = "Completed"
+	// ClusterMigrationPhaseFailed means a step could not be finished. The source cluster stays paused
+	// until the migration gets aborted.
+	ClusterMigrationPhaseFailed ClusterMigrationPhase = "Failed"
+	// ClusterMigrationPhaseAborted means the copy in the target seed has been removed and the source cluster resumed
+	ClusterMigrationPhaseAborted ClusterMigrationPhase = "Aborted"
+)
+
+// ClusterMigrationConditionType is used to indicate the type of a migration condition. All conditions
+// are set in the order of the migration steps.
+type ClusterMigrationConditionType string
+
+const (
+	// ClusterMigrationConditionValidated is set once the cluster and the target datacenter have been checked
+	ClusterMigrationConditionValidated ClusterMigrationConditionType = "Validated"
+	// ClusterMigrationConditionDatacenterUpdated is the only step of a migration within the same seed
+	ClusterMigrationConditionDatacenterUpdated         ClusterMigrationConditionType = "DatacenterUpdated"
+	ClusterMigrationConditionSourcePaused              ClusterMigrationConditionType = "SourcePaused"
+	ClusterMigrationConditionSourceApiserverScaledDown ClusterMigrationConditionType = "SourceApiserverScaledDown"
+	ClusterMigrationConditionSnapshotCreated           ClusterMigrationConditionType = "SnapshotCreated"
+	ClusterMigrationConditionTargetClusterCreated      ClusterMigrationConditionType = "TargetClusterCreated"
+	ClusterMigrationConditionEtcdRestored              ClusterMigrationConditionType = "EtcdRestored"
+	ClusterMigrationConditionAddressUpdated            ClusterMigrationConditionType = "AddressUpdated"
+	// ClusterMigrationConditionNodesUpdated is set once the MachineDeployments have been rolled out
+	// against the target seed and all nodes are ready. It is false with the reason RolloutStarted
+	// while the rollout is in progress.
+	ClusterMigrationConditionNodesUpdated    ClusterMigrationConditionType = "NodesUpdated"
+	ClusterMigrationConditionSourceCleanedUp ClusterMigrationConditionType = "SourceCleanedUp"
+	// ClusterMigrationConditionAborted is set while the migration is rolled back
+	ClusterMigrationConditionAborted ClusterMigrationConditionType = "Aborted"
+)
+
+//+genclient
+//+genclient:nonNamespaced
+
+// ClusterMigration moves the control plane of a user cluster into another datacenter. Within the same seed
+// only the datacenter of the cluster is changed. Across seeds the etcd of the cluster gets snapshotted,
+// the cluster and its secrets are recreated in the target seed, etcd is restored there and the cluster is
+// cleaned up in the source seed afterwards. Both seeds must share the backup destination of the cluster.
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type ClusterMigration struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ClusterMigrationSpec   `json:"spec"`
+	Status ClusterMigrationStatus `json:"status,omitempty"`
+}
+
+// ClusterMigrationSpec specifies the cluster to migrate and where to
+type ClusterMigrationSpec struct {
+	// ClusterName is the name of the cluster to migrate
+	ClusterName string `json:"clusterName"`
+	// TargetDatacenter is the name of the datacenter the cluster is migrated to. It must offer
+	// the same cloud provider as the current datacenter of the cluster.
+	TargetDatacenter string `json:"targetDatacenter"`
+	// Abort rolls the migration back. Within the same seed the original datacenter is restored, even
+	// after the migration has completed. Across seeds the copy in the target seed is deleted and the
+	// source cluster gets resumed, as long as the rollout of the nodes hasn't started.
+	Abort bool `json:"abort,omitempty"`
+}
+
+// ClusterMigrationList is a list of cluster migrations
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type ClusterMigrationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []ClusterMigration `json:"items"`
+}
+
+// ClusterMigrationStatus stores status information about a cluster migration
+type ClusterMigrationStatus struct {
+	Phase ClusterMigrationPhase `json:"phase,omitempty"`
+	// SourceSeed is the seed the cluster has been running in when the migration started
+	SourceSeed string `json:"sourceSeed,omitempty"`
+	// SourceDatacenter is the datacenter the cluster has been running in when the migration started
+	SourceDatacenter string `json:"sourceDatacenter,omitempty"`
+	// TargetSeed is the seed of the target datacenter
+	TargetSeed string `json:"targetSeed,omitempty"`
+	// SnapshotName is the etcd snapshot taken in the source seed and restored in the target seed
+	SnapshotName string `json:"snapshotName,omitempty"`
+	// Address is the URL of the apiserver in the target seed. The MachineDeployments of the cluster are
+	// rolled out to join it, other nodes and kubeconfigs that still use the address of the source seed
+	// must be switched over to it manually.
+	Address    string                      `json:"address,omitempty"`
+	Conditions []ClusterMigrationCondition `json:"conditions,omitempty"`
+}
+
+type ClusterMigrationCondition struct {
+	// Type of migration condition.
+	Type ClusterMigrationConditionType `json:"type"`
+	// Status of the condition, one of True, False, Unknown.
+	Status corev1.ConditionStatus `json:"status"`
+	// Last time we got an update on a given condition.
+	// +optional
+	LastHeartbeatTime metav1.Time `json:"lastHeartbeatTime,omitempty"`
+	// Last time the condition transit from one status to another.
+	// +optional
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+	// (brief) reason for the condition's last transition.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+	// Human readable message indicating details about last transition.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// HasConditionValue returns true if the migration status has the given condition with the given status.
+func (s *ClusterMigrationStatus) HasConditionValue(conditionType ClusterMigrationConditionType, conditionStatus corev1.ConditionStatus) bool {
+	for _, condition := range s.Conditions {
+		if condition.Type == conditionType {
+			return condition.Status == conditionStatus
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2020 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	// ClusterTemplateResourceName represents "Resource" defined in Kubernetes
+	ClusterTemplateResourceName = "clustertemplates"
+
+	// ClusterTemplateKindName represents "Kind" defined in Kubernetes
+	ClusterTemplateKindName = "ClusterTemplate"
+)
+
+// ClusterTemplateScope defines who can see and use a cluster template
+type ClusterTemplateScope string
+
+const (
+	// ClusterTemplateScopeUser templates can only be used by the user who created them
+	ClusterTemplateScopeUser ClusterTemplateScope = "user"
+	// ClusterTemplateScopeProject templates can be used by all members of the project
+	ClusterTemplateScopeProject ClusterTemplateScope = "project"
+	// ClusterTemplateScopeGlobal templates can be used by all users, only admins can create them
+	ClusterTemplateScopeGlobal ClusterTemplateScope = "global"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ClusterTemplate is a blueprint from which clusters can be created
+type ClusterTemplate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ClusterTemplateSpec `json:"spec"`
+}
+
+// ClusterTemplateSpec specifies the clusters created from a template
+type ClusterTemplateSpec struct {
+	// HumanReadableName is the name of the template, clusters created from it are named after it
+	HumanReadableName string `json:"humanReadableName"`
+
+	Scope ClusterTemplateScope `json:"scope"`
+	// ProjectID is the project the template was created in. Project templates can only be used in this project.
+	ProjectID string `json:"projectID,omitempty"`
+	// Owner is the email of the user who created the template
+	Owner string `json:"owner"`
+
+	// Credential is the name of the preset which provides the cloud credentials of the clusters,
+	// the template itself never contains any cloud credentials
+	Credential string `json:"credential,omitempty"`
+
+	// ClusterType is the type of the clusters, either kubernetes or openshift
+	ClusterType string `json:"clusterType"`
+	// ClusterLabels are the labels of the clusters
+	ClusterLabels map[string]string `json:"clusterLabels,omitempty"`
+	// Cluster is the spec of the clusters
+	Cluster ClusterSpec `json:"cluster"`
+
+	// NodeDeployment is the initial node deployment of the clusters in its API representation
+	NodeDeployment *runtime.RawExtension `json:"nodeDeployment,omitempty"`
+
+	// RequiredSecrets are the secrets of the cluster spec which are not stored in the template and have to be
+	// provided when creating clusters from it, out of oidcClientSecret and openshiftImagePullSecret
+	RequiredSecrets []string `json:"requiredSecrets,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ClusterTemplateList specifies a list of cluster templates
+type ClusterTemplateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []ClusterTemplate `json:"items"`
+}
@@ -86,6 +86,64 @@ type SeedSpec struct {
 	ProxySettings *ProxySettings `json:"proxy_settings,omitempty"`
 	// Optional: ExposeStrategy explicitly sets the expose strategy for this seed cluster, if not set, the default provided by the master is used.
 	ExposeStrategy corev1.ServiceType `json:"expose_strategy,omitempty"`
+	// Optional: BackupDestinations are the S3 compatible storages which can be referenced
+	// by name from the etcd backup policies of datacenters and clusters. Clusters without
+	// a destination use the endpoint and bucket configured in the backup containers.
+	BackupDestinations map[string]BackupDestination `json:"backup_destinations,omitempty"`
+	// Optional: AddonSources pins the sources the manifests of addons are pulled from by
+	// addon name. The manifests of addons without source are read from the addon folders
+	// of the seed-controller-manager.
+	AddonSources map[string]AddonSource `json:"addon_sources,omitempty"`
+}
+
+// BackupDestination is an S3 compatible storage for etcd backups
+type BackupDestination struct {
+	// Endpoint is the S3 endpoint, e.g. "s3.amazonaws.com"
+	Endpoint string `json:"endpoint"`
+	// BucketName is the name of the bucket the snapshots are stored in
+	BucketName string `json:"bucket_name"`
+	// Optional: CredentialsSecretName is the name of a Secret in the kube-system namespace of
+	// the seed with the keys ACCESS_KEY_ID and SECRET_ACCESS_KEY. Defaults to the credentials
+	// configured in the backup containers.
+	CredentialsSecretName string `json:"credentials_secret_name,omitempty"`
+	// Optional: Secure makes the backup containers and the snapshot listing connect to the
+	// endpoint using TLS
+	Secure bool `json:"secure,omitempty"`
+}
+
+// AddonSource is a versioned bundle of addon manifests outside the seed-controller-manager
+// image. Exactly one of OCI and Git must be set.
+type AddonSource struct {
+	// OCI pulls the bundle from an OCI registry
+	OCI *OCIAddonSource `json:"oci,omitempty"`
+	// Git clones the bundle from a Git repository
+	Git *GitAddonSource `json:"git,omitempty"`
+	// Checksum is verified against the manifests of the bundle before they are used, in the form
+	// "sha256:<hex>". It is the SHA256 of the output of `sha256sum *` in the bundle. It may only be
+	// left out if the OCI artifact is pinned by its digest.
+	Checksum string `json:"checksum,omitempty"`
+}
+
+// OCIAddonSource is an addon bundle stored as OCI artifact with a single tar+gzip layer
+type OCIAddonSource struct {
+	// Repository of the artifact including the registry, e.g. "quay.io/kubermatic/addons/csi"
+	Repository string `json:"repository"`
+	// Tag of the artifact, usually the version of the addon. It is only used if no digest is set.
+	Tag string `json:"tag,omitempty"`
+	// Optional: Digest of the manifest of the artifact in the form "sha256:<hex>". The artifact is
+	// pulled by its digest, so the bundle can't change as long as the seed doesn't.
+	Digest string `json:"digest,omitempty"`
+}
+
+// GitAddonSource is an addon bundle stored in a Git repository
+type GitAddonSource struct {
+	// URL of the repository
+	URL string `json:"url"`
+	// Ref is the tag or branch to check out, usually the version of the addon
+	Ref string `json:"ref"`
+	// Optional: Path is the folder of the addon manifests in the repository,
+	// defaults to the root of the repository
+	Path string `json:"path,omitempty"`
 }
 
 type NodeportProxyConfig struct {
@@ -157,12 +215,19 @@ type DatacenterSpec struct {
 	RequiredEmailDomains []string `json:"requiredEmailDomains,omitempty"`
 
 	// EnforceAuditLogging enforces audit logging on every cluster within the DC,
-	// ignoring cluster-specific settings.
+	// ignoring the cluster-specific Enabled setting.
 	EnforceAuditLogging bool `json:"enforceAuditLogging"`
 
+	// EnforcedAuditPolicyPreset is the minimum audit policy preset of the clusters within the DC,
+	// if EnforceAuditLogging is set. More detailed presets are kept, custom policies are replaced.
+	EnforcedAuditPolicyPreset AuditPolicyPreset `json:"enforcedAuditPolicyPreset,omitempty"`
+
 	// EnforcePodSecurityPolicy enforces pod security policy plugin on every clusters within the DC,
 	// ignoring cluster-specific settings
 	EnforcePodSecurityPolicy bool `json:"enforcePodSecurityPolicy"`
+
+	// Optional: BackupPolicy is the default etcd backup policy of all clusters within the DC
+	BackupPolicy *EtcdBackupPolicy `json:"backupPolicy,omitempty"`
 }
 
 // ImageList defines a map of operating system and the image to use
@@ -0,0 +1,123 @@
+/*
+Copyright 2020 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// EtcdRestoreResourceName represents "Resource" defined in Kubernetes
+	EtcdRestoreResourceName = "etcdrestores"
+
+	// EtcdRestoreKindName represents "Kind" defined in Kubernetes
+	EtcdRestoreKindName = "EtcdRestore"
+)
+
+// EtcdRestorePhase is the overall phase of a restore
+type EtcdRestorePhase string
+
+const (
+	// EtcdRestorePhaseStarted means the restore has been accepted but nothing happened yet
+	EtcdRestorePhaseStarted EtcdRestorePhase = "Started"
+	// EtcdRestorePhaseRunning means the cluster is paused and the etcd data is being replaced
+	EtcdRestorePhaseRunning EtcdRestorePhase = "Running"
+	// EtcdRestorePhaseCompleted means etcd and the apiserver are healthy again with the restored data
+	EtcdRestorePhaseCompleted EtcdRestorePhase = "Completed"
+	// EtcdRestorePhaseFailed means the restore could not be finished. The cluster stays paused
+	// until the EtcdRestore gets deleted.
+	EtcdRestorePhaseFailed EtcdRestorePhase = "Failed"
+)
+
+// EtcdRestoreConditionType is used to indicate the type of a restore condition. All conditions
+// are set in the order of the restore steps.
+type EtcdRestoreConditionType string
+
+const (
+	EtcdRestoreConditionClusterPaused          EtcdRestoreConditionType = "ClusterPaused"
+	EtcdRestoreConditionApiserverScaledDown    EtcdRestoreConditionType = "ApiserverScaledDown"
+	EtcdRestoreConditionEtcdStatefulSetRemoved EtcdRestoreConditionType = "EtcdStatefulSetRemoved"
+	EtcdRestoreConditionSnapshotRestored       EtcdRestoreConditionType = "SnapshotRestored"
+	EtcdRestoreConditionClusterResumed         EtcdRestoreConditionType = "ClusterResumed"
+	EtcdRestoreConditionClusterHealthy         EtcdRestoreConditionType = "ClusterHealthy"
+)
+
+//+genclient
+
+// EtcdRestore specifies a restore of the etcd of a user cluster from a backup snapshot
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type EtcdRestore struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   EtcdRestoreSpec   `json:"spec"`
+	Status EtcdRestoreStatus `json:"status,omitempty"`
+}
+
+// EtcdRestoreSpec specifies details of an etcd restore
+type EtcdRestoreSpec struct {
+	// Cluster is the reference to the cluster whose etcd will be restored
+	Cluster corev1.ObjectReference `json:"cluster"`
+	// BackupName is the name of the snapshot object in the backup bucket
+	BackupName string `json:"backupName"`
+}
+
+// EtcdRestoreList is a list of etcd restores
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type EtcdRestoreList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []EtcdRestore `json:"items"`
+}
+
+// EtcdRestoreStatus stores status information about an etcd restore
+type EtcdRestoreStatus struct {
+	Phase      EtcdRestorePhase       `json:"phase,omitempty"`
+	Conditions []EtcdRestoreCondition `json:"conditions,omitempty"`
+}
+
+type EtcdRestoreCondition struct {
+	// Type of restore condition.
+	Type EtcdRestoreConditionType `json:"type"`
+	// Status of the condition, one of True, False, Unknown.
+	Status corev1.ConditionStatus `json:"status"`
+	// Last time we got an update on a given condition.
+	// +optional
+	LastHeartbeatTime metav1.Time `json:"lastHeartbeatTime,omitempty"`
+	// Last time the condition transit from one status to another.
+	// +optional
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+	// (brief) reason for the condition's last transition.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+	// Human readable message indicating details about last transition.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// HasConditionValue returns true if the restore status has the given condition with the given status.
+func (s *EtcdRestoreStatus) HasConditionValue(conditionType EtcdRestoreConditionType, conditionStatus corev1.ConditionStatus) bool {
+	for _, condition := range s.Conditions {
+		if condition.Type == conditionType {
+			return condition.Status == conditionStatus
+		}
+	}
+
+	return false
+}
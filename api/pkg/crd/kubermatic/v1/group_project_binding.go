@@ -0,0 +1,71 @@
+/*
+Copyright 2020 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// GroupProjectBindingResourceName represents "Resource" defined in Kubernetes
+	GroupProjectBindingResourceName = "groupprojectbindings"
+
+	// GroupProjectBindingKindName represents "Kind" defined in Kubernetes
+	GroupProjectBindingKindName = "GroupProjectBinding"
+
+	// GroupProjectBindingLabelKey is the label put on the UserProjectBindings derived from a GroupProjectBinding,
+	// its value is the name of the GroupProjectBinding
+	GroupProjectBindingLabelKey = "group-project-binding"
+)
+
+//+genclient
+//+genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// GroupProjectBinding binds a group of the identity provider to a project role.
+//
+// Every user whose ID token carries the group in its "groups" claim becomes a member of the project.
+// The memberships are reconciled whenever the groups in the token of the user change and at least every
+// five minutes while the user sends requests, that is UserProjectBindings labelled with GroupProjectBindingLabelKey
+// are created, updated or removed according to the current groups of the user.
+// Bindings that were created manually for a user are never touched.
+type GroupProjectBinding struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec GroupProjectBindingSpec `json:"spec"`
+}
+
+// GroupProjectBindingSpec specifies the group, the project and the role
+type GroupProjectBindingSpec struct {
+	// Group is the name of the group as found in the "groups" claim of the ID token, for example "team-payments"
+	Group string `json:"group"`
+	// ProjectID is the ID of the project the members of the group are bound to
+	ProjectID string `json:"projectId"`
+	// Role is either one of the built-in "owners", "editors" and "viewers" roles or the name of a ProjectRole
+	Role string `json:"role"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// GroupProjectBindingList specifies a list of group project bindings
+type GroupProjectBindingList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []GroupProjectBinding `json:"items"`
+}
@@ -56,11 +56,39 @@ type Project struct {
 // ProjectSpec is a specification of a project.
 type ProjectSpec struct {
 	Name string `json:"name"`
+
+	// Quota limits the resources of the project, it can only be changed by admins
+	Quota *ProjectQuota `json:"quota,omitempty"`
+}
+
+// ProjectQuota limits the resources which can be consumed by the clusters of a project.
+// Limits which are not set are not enforced.
+type ProjectQuota struct {
+	// MaxClusters is the maximum number of clusters
+	MaxClusters *int `json:"maxClusters,omitempty"`
+	// MaxNodes is the maximum number of nodes of all clusters
+	MaxNodes *int `json:"maxNodes,omitempty"`
+	// MaxCPUs is the maximum number of vCPUs of all nodes, derived from the machine sizes
+	MaxCPUs *int `json:"maxCPUs,omitempty"`
+	// MaxMemoryMB is the maximum memory of all nodes in MB, derived from the machine sizes
+	MaxMemoryMB *int `json:"maxMemoryMB,omitempty"`
 }
 
 // ProjectStatus represents the current status of a project.
 type ProjectStatus struct {
 	Phase string `json:"phase"`
+
+	// Usage is the resource usage of a project with a quota, it is updated whenever
+	// clusters or node deployments of the project change
+	Usage *ProjectResourceUsage `json:"usage,omitempty"`
+}
+
+// ProjectResourceUsage is the amount of resources consumed by the clusters of a project
+type ProjectResourceUsage struct {
+	Clusters int `json:"clusters"`
+	Nodes    int `json:"nodes"`
+	CPUs     int `json:"cpus"`
+	MemoryMB int `json:"memoryMB"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
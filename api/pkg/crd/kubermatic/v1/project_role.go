@@ -0,0 +1,96 @@
+/*
+Copyright 2020 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ProjectRoleResourceName represents "Resource" defined in Kubernetes
+	ProjectRoleResourceName = "projectroles"
+
+	// ProjectRoleKindName represents "Kind" defined in Kubernetes
+	ProjectRoleKindName = "ProjectRole"
+)
+
+//+genclient
+//+genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ProjectRole is an admin-defined role members of a project can be assigned to,
+// in addition to the built-in owners, editors and viewers groups.
+//
+// The name of the role is used as the group prefix of the project members, thus it must
+// consist of lower case alphanumeric words separated by a dash, for example "cluster-operator".
+//
+// The role is translated into RBAC on the master and seed clusters only, members of a custom role
+// are not granted any permissions inside of the user clusters. Thus they can't get a kubeconfig
+// or open the dashboard of a cluster. The API authorizes the node deployment endpoints against the
+// verbs the role grants for "NodeDeployment" instead, for example "patch" allows to scale them.
+// A role that grants verbs for the other resources inside of the user clusters, like
+// "MachineDeployment" and "Node", is rejected.
+// Note that removing a role doesn't remove the RBAC for members that are still assigned to it.
+type ProjectRole struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ProjectRoleSpec `json:"spec"`
+}
+
+// ProjectRoleSpec specifies what members assigned to a project role are allowed to do
+type ProjectRoleSpec struct {
+	// HumanReadableName is the name of the role displayed to the users, for example "Cluster Operator"
+	HumanReadableName string `json:"humanReadableName,omitempty"`
+	// Rules lists the permissions the role grants on the project and its resources
+	Rules []ProjectRoleRule `json:"rules"`
+}
+
+// ProjectRoleRule grants the verbs on the given kinds of project resources
+type ProjectRoleRule struct {
+	// Kinds are the kinds of the project resources the rule applies to,
+	// for example "Project", "Cluster", "UserSSHKey", "Addon", "EtcdRestore" or "NodeDeployment".
+	// The other kinds of resources inside of the user clusters are not supported.
+	Kinds []string `json:"kinds"`
+	// Verbs are the verbs allowed on the resources, one of "get", "list", "create", "update" and "delete",
+	// for "NodeDeployment" "patch" is used instead of "update"
+	Verbs []string `json:"verbs"`
+}
+
+// VerbsFor returns all verbs the role grants for the given kind
+func (r *ProjectRole) VerbsFor(kind string) []string {
+	verbs := []string{}
+	for _, rule := range r.Spec.Rules {
+		for _, ruleKind := range rule.Kinds {
+			if ruleKind == kind {
+				verbs = append(verbs, rule.Verbs...)
+				break
+			}
+		}
+	}
+	return verbs
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ProjectRoleList specifies a list of project roles
+type ProjectRoleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []ProjectRole `json:"items"`
+}
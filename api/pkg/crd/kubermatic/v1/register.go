@@ -73,6 +73,18 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&PresetList{},
 		&AdmissionPlugin{},
 		&AdmissionPluginList{},
+		&EtcdRestore{},
+		&EtcdRestoreList{},
+		&ClusterTemplate{},
+		&ClusterTemplateList{},
+		&ProjectRole{},
+		&ProjectRoleList{},
+		&GroupProjectBinding{},
+		&GroupProjectBindingList{},
+		&AuditRecord{},
+		&AuditRecordList{},
+		&ClusterMigration{},
+		&ClusterMigrationList{},
 	)
 
 	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
@@ -182,7 +182,9 @@ func (in *AddonConfigSpec) DeepCopyInto(out *AddonConfigSpec) {
 	if in.Controls != nil {
 		in, out := &in.Controls, &out.Controls
 		*out = make([]AddonFormControl, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	return
 }
@@ -200,6 +202,11 @@ func (in *AddonConfigSpec) DeepCopy() *AddonConfigSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AddonFormControl) DeepCopyInto(out *AddonFormControl) {
 	*out = *in
+	if in.Enum != nil {
+		in, out := &in.Enum, &out.Enum
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -246,6 +253,53 @@ func (in *AddonList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AddonObjectStatus) DeepCopyInto(out *AddonObjectStatus) {
+	*out = *in
+	if in.DriftedFields != nil {
+		in, out := &in.DriftedFields, &out.DriftedFields
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AddonObjectStatus.
+func (in *AddonObjectStatus) DeepCopy() *AddonObjectStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AddonObjectStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AddonSource) DeepCopyInto(out *AddonSource) {
+	*out = *in
+	if in.OCI != nil {
+		in, out := &in.OCI, &out.OCI
+		*out = new(OCIAddonSource)
+		**out = **in
+	}
+	if in.Git != nil {
+		in, out := &in.Git, &out.Git
+		*out = new(GitAddonSource)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AddonSource.
+func (in *AddonSource) DeepCopy() *AddonSource {
+	if in == nil {
+		return nil
+	}
+	out := new(AddonSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AddonSpec) DeepCopyInto(out *AddonSpec) {
 	*out = *in
@@ -256,6 +310,11 @@ func (in *AddonSpec) DeepCopyInto(out *AddonSpec) {
 		*out = make([]schema.GroupVersionKind, len(*in))
 		copy(*out, *in)
 	}
+	if in.Dependencies != nil {
+		in, out := &in.Dependencies, &out.Dependencies
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -279,6 +338,13 @@ func (in *AddonStatus) DeepCopyInto(out *AddonStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Objects != nil {
+		in, out := &in.Objects, &out.Objects
+		*out = make([]AddonObjectStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -413,6 +479,11 @@ func (in *AlibabaCloudSpec) DeepCopy() *AlibabaCloudSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AuditLoggingSettings) DeepCopyInto(out *AuditLoggingSettings) {
 	*out = *in
+	if in.Webhook != nil {
+		in, out := &in.Webhook, &out.Webhook
+		*out = new(AuditWebhookBackendSettings)
+		**out = **in
+	}
 	return
 }
 
@@ -426,6 +497,99 @@ func (in *AuditLoggingSettings) DeepCopy() *AuditLoggingSettings {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuditRecord) DeepCopyInto(out *AuditRecord) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuditRecord.
+func (in *AuditRecord) DeepCopy() *AuditRecord {
+	if in == nil {
+		return nil
+	}
+	out := new(AuditRecord)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AuditRecord) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuditRecordList) DeepCopyInto(out *AuditRecordList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]AuditRecord, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuditRecordList.
+func (in *AuditRecordList) DeepCopy() *AuditRecordList {
+	if in == nil {
+		return nil
+	}
+	out := new(AuditRecordList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AuditRecordList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuditRecordSpec) DeepCopyInto(out *AuditRecordSpec) {
+	*out = *in
+	in.Timestamp.DeepCopyInto(&out.Timestamp)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuditRecordSpec.
+func (in *AuditRecordSpec) DeepCopy() *AuditRecordSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AuditRecordSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuditWebhookBackendSettings) DeepCopyInto(out *AuditWebhookBackendSettings) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuditWebhookBackendSettings.
+func (in *AuditWebhookBackendSettings) DeepCopy() *AuditWebhookBackendSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(AuditWebhookBackendSettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Azure) DeepCopyInto(out *Azure) {
 	*out = *in
@@ -463,6 +627,22 @@ func (in *AzureCloudSpec) DeepCopy() *AzureCloudSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupDestination) DeepCopyInto(out *BackupDestination) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupDestination.
+func (in *BackupDestination) DeepCopy() *BackupDestination {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupDestination)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BringYourOwnCloudSpec) DeepCopyInto(out *BringYourOwnCloudSpec) {
 	*out = *in
@@ -687,6 +867,124 @@ func (in *ClusterList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterMigration) DeepCopyInto(out *ClusterMigration) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterMigration.
+func (in *ClusterMigration) DeepCopy() *ClusterMigration {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterMigration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterMigration) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterMigrationCondition) DeepCopyInto(out *ClusterMigrationCondition) {
+	*out = *in
+	in.LastHeartbeatTime.DeepCopyInto(&out.LastHeartbeatTime)
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterMigrationCondition.
+func (in *ClusterMigrationCondition) DeepCopy() *ClusterMigrationCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterMigrationCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterMigrationList) DeepCopyInto(out *ClusterMigrationList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClusterMigration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterMigrationList.
+func (in *ClusterMigrationList) DeepCopy() *ClusterMigrationList {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterMigrationList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterMigrationList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterMigrationSpec) DeepCopyInto(out *ClusterMigrationSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterMigrationSpec.
+func (in *ClusterMigrationSpec) DeepCopy() *ClusterMigrationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterMigrationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterMigrationStatus) DeepCopyInto(out *ClusterMigrationStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]ClusterMigrationCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterMigrationStatus.
+func (in *ClusterMigrationStatus) DeepCopy() *ClusterMigrationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterMigrationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterNetworkingConfig) DeepCopyInto(out *ClusterNetworkingConfig) {
 	*out = *in
@@ -745,6 +1043,16 @@ func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 	if in.AuditLogging != nil {
 		in, out := &in.AuditLogging, &out.AuditLogging
 		*out = new(AuditLoggingSettings)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BackupPolicy != nil {
+		in, out := &in.BackupPolicy, &out.BackupPolicy
+		*out = new(EtcdBackupPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.EncryptionAtRest != nil {
+		in, out := &in.EncryptionAtRest, &out.EncryptionAtRest
+		*out = new(EncryptionAtRestSettings)
 		**out = **in
 	}
 	return
@@ -814,6 +1122,16 @@ func (in *ClusterStatus) DeepCopyInto(out *ClusterStatus) {
 			(*out)[key] = val
 		}
 	}
+	if in.Backup != nil {
+		in, out := &in.Backup, &out.Backup
+		*out = new(EtcdBackupStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.EncryptionAtRest != nil {
+		in, out := &in.EncryptionAtRest, &out.EncryptionAtRest
+		*out = new(EncryptionAtRestStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -827,6 +1145,100 @@ func (in *ClusterStatus) DeepCopy() *ClusterStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterTemplate) DeepCopyInto(out *ClusterTemplate) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplate.
+func (in *ClusterTemplate) DeepCopy() *ClusterTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterTemplate) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterTemplateList) DeepCopyInto(out *ClusterTemplateList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClusterTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateList.
+func (in *ClusterTemplateList) DeepCopy() *ClusterTemplateList {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterTemplateList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterTemplateList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterTemplateSpec) DeepCopyInto(out *ClusterTemplateSpec) {
+	*out = *in
+	if in.ClusterLabels != nil {
+		in, out := &in.ClusterLabels, &out.ClusterLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	in.Cluster.DeepCopyInto(&out.Cluster)
+	if in.NodeDeployment != nil {
+		in, out := &in.NodeDeployment, &out.NodeDeployment
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RequiredSecrets != nil {
+		in, out := &in.RequiredSecrets, &out.RequiredSecrets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateSpec.
+func (in *ClusterTemplateSpec) DeepCopy() *ClusterTemplateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterTemplateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ComponentSettings) DeepCopyInto(out *ComponentSettings) {
 	*out = *in
@@ -970,6 +1382,11 @@ func (in *DatacenterSpec) DeepCopyInto(out *DatacenterSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.BackupPolicy != nil {
+		in, out := &in.BackupPolicy, &out.BackupPolicy
+		*out = new(EtcdBackupPolicy)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -1217,75 +1634,323 @@ func (in *DatacenterSpecVSphere) DeepCopyInto(out *DatacenterSpecVSphere) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatacenterSpecVSphere.
-func (in *DatacenterSpecVSphere) DeepCopy() *DatacenterSpecVSphere {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatacenterSpecVSphere.
+func (in *DatacenterSpecVSphere) DeepCopy() *DatacenterSpecVSphere {
+	if in == nil {
+		return nil
+	}
+	out := new(DatacenterSpecVSphere)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeploymentSettings) DeepCopyInto(out *DeploymentSettings) {
+	*out = *in
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeploymentSettings.
+func (in *DeploymentSettings) DeepCopy() *DeploymentSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(DeploymentSettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Digitalocean) DeepCopyInto(out *Digitalocean) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Digitalocean.
+func (in *Digitalocean) DeepCopy() *Digitalocean {
+	if in == nil {
+		return nil
+	}
+	out := new(Digitalocean)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DigitaloceanCloudSpec) DeepCopyInto(out *DigitaloceanCloudSpec) {
+	*out = *in
+	if in.CredentialsReference != nil {
+		in, out := &in.CredentialsReference, &out.CredentialsReference
+		*out = new(types.GlobalSecretKeySelector)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DigitaloceanCloudSpec.
+func (in *DigitaloceanCloudSpec) DeepCopy() *DigitaloceanCloudSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DigitaloceanCloudSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EncryptionAtRestSettings) DeepCopyInto(out *EncryptionAtRestSettings) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EncryptionAtRestSettings.
+func (in *EncryptionAtRestSettings) DeepCopy() *EncryptionAtRestSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(EncryptionAtRestSettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EncryptionAtRestStatus) DeepCopyInto(out *EncryptionAtRestStatus) {
+	*out = *in
+	if in.ActiveSince != nil {
+		in, out := &in.ActiveSince, &out.ActiveSince
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EncryptionAtRestStatus.
+func (in *EncryptionAtRestStatus) DeepCopy() *EncryptionAtRestStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(EncryptionAtRestStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EtcdBackupPolicy) DeepCopyInto(out *EtcdBackupPolicy) {
+	*out = *in
+	if in.Keep != nil {
+		in, out := &in.Keep, &out.Keep
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdBackupPolicy.
+func (in *EtcdBackupPolicy) DeepCopy() *EtcdBackupPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(EtcdBackupPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EtcdBackupStatus) DeepCopyInto(out *EtcdBackupStatus) {
+	*out = *in
+	if in.LastSuccessfulBackupTime != nil {
+		in, out := &in.LastSuccessfulBackupTime, &out.LastSuccessfulBackupTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastFailedBackupTime != nil {
+		in, out := &in.LastFailedBackupTime, &out.LastFailedBackupTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LatestSnapshot != nil {
+		in, out := &in.LatestSnapshot, &out.LatestSnapshot
+		*out = new(EtcdSnapshot)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdBackupStatus.
+func (in *EtcdBackupStatus) DeepCopy() *EtcdBackupStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(EtcdBackupStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EtcdRestore) DeepCopyInto(out *EtcdRestore) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdRestore.
+func (in *EtcdRestore) DeepCopy() *EtcdRestore {
+	if in == nil {
+		return nil
+	}
+	out := new(EtcdRestore)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *EtcdRestore) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EtcdRestoreCondition) DeepCopyInto(out *EtcdRestoreCondition) {
+	*out = *in
+	in.LastHeartbeatTime.DeepCopyInto(&out.LastHeartbeatTime)
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdRestoreCondition.
+func (in *EtcdRestoreCondition) DeepCopy() *EtcdRestoreCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(EtcdRestoreCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EtcdRestoreList) DeepCopyInto(out *EtcdRestoreList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]EtcdRestore, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdRestoreList.
+func (in *EtcdRestoreList) DeepCopy() *EtcdRestoreList {
+	if in == nil {
+		return nil
+	}
+	out := new(EtcdRestoreList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *EtcdRestoreList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EtcdRestoreSpec) DeepCopyInto(out *EtcdRestoreSpec) {
+	*out = *in
+	out.Cluster = in.Cluster
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdRestoreSpec.
+func (in *EtcdRestoreSpec) DeepCopy() *EtcdRestoreSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(DatacenterSpecVSphere)
+	out := new(EtcdRestoreSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DeploymentSettings) DeepCopyInto(out *DeploymentSettings) {
+func (in *EtcdRestoreStatus) DeepCopyInto(out *EtcdRestoreStatus) {
 	*out = *in
-	if in.Replicas != nil {
-		in, out := &in.Replicas, &out.Replicas
-		*out = new(int32)
-		**out = **in
-	}
-	if in.Resources != nil {
-		in, out := &in.Resources, &out.Resources
-		*out = new(corev1.ResourceRequirements)
-		(*in).DeepCopyInto(*out)
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]EtcdRestoreCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeploymentSettings.
-func (in *DeploymentSettings) DeepCopy() *DeploymentSettings {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdRestoreStatus.
+func (in *EtcdRestoreStatus) DeepCopy() *EtcdRestoreStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(DeploymentSettings)
+	out := new(EtcdRestoreStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Digitalocean) DeepCopyInto(out *Digitalocean) {
+func (in *EtcdSnapshot) DeepCopyInto(out *EtcdSnapshot) {
 	*out = *in
+	in.CreationTimestamp.DeepCopyInto(&out.CreationTimestamp)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Digitalocean.
-func (in *Digitalocean) DeepCopy() *Digitalocean {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdSnapshot.
+func (in *EtcdSnapshot) DeepCopy() *EtcdSnapshot {
 	if in == nil {
 		return nil
 	}
-	out := new(Digitalocean)
+	out := new(EtcdSnapshot)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DigitaloceanCloudSpec) DeepCopyInto(out *DigitaloceanCloudSpec) {
+func (in *EtcdStatefulSetSettings) DeepCopyInto(out *EtcdStatefulSetSettings) {
 	*out = *in
-	if in.CredentialsReference != nil {
-		in, out := &in.CredentialsReference, &out.CredentialsReference
-		*out = new(types.GlobalSecretKeySelector)
+	if in.ClusterSize != nil {
+		in, out := &in.ClusterSize, &out.ClusterSize
+		*out = new(int32)
 		**out = **in
 	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DigitaloceanCloudSpec.
-func (in *DigitaloceanCloudSpec) DeepCopy() *DigitaloceanCloudSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdStatefulSetSettings.
+func (in *EtcdStatefulSetSettings) DeepCopy() *EtcdStatefulSetSettings {
 	if in == nil {
 		return nil
 	}
-	out := new(DigitaloceanCloudSpec)
+	out := new(EtcdStatefulSetSettings)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -1375,6 +2040,98 @@ func (in *GCPCloudSpec) DeepCopy() *GCPCloudSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitAddonSource) DeepCopyInto(out *GitAddonSource) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitAddonSource.
+func (in *GitAddonSource) DeepCopy() *GitAddonSource {
+	if in == nil {
+		return nil
+	}
+	out := new(GitAddonSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GroupProjectBinding) DeepCopyInto(out *GroupProjectBinding) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GroupProjectBinding.
+func (in *GroupProjectBinding) DeepCopy() *GroupProjectBinding {
+	if in == nil {
+		return nil
+	}
+	out := new(GroupProjectBinding)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GroupProjectBinding) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GroupProjectBindingList) DeepCopyInto(out *GroupProjectBindingList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]GroupProjectBinding, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GroupProjectBindingList.
+func (in *GroupProjectBindingList) DeepCopy() *GroupProjectBindingList {
+	if in == nil {
+		return nil
+	}
+	out := new(GroupProjectBindingList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GroupProjectBindingList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GroupProjectBindingSpec) DeepCopyInto(out *GroupProjectBindingSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GroupProjectBindingSpec.
+func (in *GroupProjectBindingSpec) DeepCopy() *GroupProjectBindingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GroupProjectBindingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Hetzner) DeepCopyInto(out *Hetzner) {
 	*out = *in
@@ -1664,6 +2421,22 @@ func (in *NodeportProxyConfig) DeepCopy() *NodeportProxyConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OCIAddonSource) DeepCopyInto(out *OCIAddonSource) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OCIAddonSource.
+func (in *OCIAddonSource) DeepCopy() *OCIAddonSource {
+	if in == nil {
+		return nil
+	}
+	out := new(OCIAddonSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OIDCSettings) DeepCopyInto(out *OIDCSettings) {
 	*out = *in
@@ -1922,8 +2695,8 @@ func (in *Project) DeepCopyInto(out *Project) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
-	out.Status = in.Status
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
@@ -1994,9 +2767,175 @@ func (in *ProjectList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProjectQuota) DeepCopyInto(out *ProjectQuota) {
+	*out = *in
+	if in.MaxClusters != nil {
+		in, out := &in.MaxClusters, &out.MaxClusters
+		*out = new(int)
+		**out = **in
+	}
+	if in.MaxNodes != nil {
+		in, out := &in.MaxNodes, &out.MaxNodes
+		*out = new(int)
+		**out = **in
+	}
+	if in.MaxCPUs != nil {
+		in, out := &in.MaxCPUs, &out.MaxCPUs
+		*out = new(int)
+		**out = **in
+	}
+	if in.MaxMemoryMB != nil {
+		in, out := &in.MaxMemoryMB, &out.MaxMemoryMB
+		*out = new(int)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectQuota.
+func (in *ProjectQuota) DeepCopy() *ProjectQuota {
+	if in == nil {
+		return nil
+	}
+	out := new(ProjectQuota)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProjectResourceUsage) DeepCopyInto(out *ProjectResourceUsage) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectResourceUsage.
+func (in *ProjectResourceUsage) DeepCopy() *ProjectResourceUsage {
+	if in == nil {
+		return nil
+	}
+	out := new(ProjectResourceUsage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProjectRole) DeepCopyInto(out *ProjectRole) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectRole.
+func (in *ProjectRole) DeepCopy() *ProjectRole {
+	if in == nil {
+		return nil
+	}
+	out := new(ProjectRole)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ProjectRole) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProjectRoleList) DeepCopyInto(out *ProjectRoleList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ProjectRole, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectRoleList.
+func (in *ProjectRoleList) DeepCopy() *ProjectRoleList {
+	if in == nil {
+		return nil
+	}
+	out := new(ProjectRoleList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ProjectRoleList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProjectRoleRule) DeepCopyInto(out *ProjectRoleRule) {
+	*out = *in
+	if in.Kinds != nil {
+		in, out := &in.Kinds, &out.Kinds
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Verbs != nil {
+		in, out := &in.Verbs, &out.Verbs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectRoleRule.
+func (in *ProjectRoleRule) DeepCopy() *ProjectRoleRule {
+	if in == nil {
+		return nil
+	}
+	out := new(ProjectRoleRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProjectRoleSpec) DeepCopyInto(out *ProjectRoleSpec) {
+	*out = *in
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]ProjectRoleRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectRoleSpec.
+func (in *ProjectRoleSpec) DeepCopy() *ProjectRoleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ProjectRoleSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ProjectSpec) DeepCopyInto(out *ProjectSpec) {
 	*out = *in
+	if in.Quota != nil {
+		in, out := &in.Quota, &out.Quota
+		*out = new(ProjectQuota)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -2013,6 +2952,11 @@ func (in *ProjectSpec) DeepCopy() *ProjectSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ProjectStatus) DeepCopyInto(out *ProjectStatus) {
 	*out = *in
+	if in.Usage != nil {
+		in, out := &in.Usage, &out.Usage
+		*out = new(ProjectResourceUsage)
+		**out = **in
+	}
 	return
 }
 
@@ -2176,6 +3120,20 @@ func (in *SeedSpec) DeepCopyInto(out *SeedSpec) {
 		*out = new(ProxySettings)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.BackupDestinations != nil {
+		in, out := &in.BackupDestinations, &out.BackupDestinations
+		*out = make(map[string]BackupDestination, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.AddonSources != nil {
+		in, out := &in.AddonSources, &out.AddonSources
+		*out = make(map[string]AddonSource, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
 	return
 }
 
@@ -0,0 +1,84 @@
+/*
+Copyright 2020 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"errors"
+
+	kubermaticv1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
+
+	"go.uber.org/zap"
+)
+
+// ErrQueueFull is returned by the AsyncSink when the record is dropped because the queue is full
+var ErrQueueFull = errors.New("the audit queue is full, the record has been dropped")
+
+// AsyncSink queues the records and writes them to the underlying sink in the background,
+// so that slow sinks like webhooks don't delay the requests
+type AsyncSink struct {
+	sink  Sink
+	queue chan *kubermaticv1.AuditRecordSpec
+	log   *zap.SugaredLogger
+}
+
+var _ Sink = &AsyncSink{}
+
+// asyncReader is an AsyncSink whose underlying sink can be queried
+type asyncReader struct {
+	*AsyncSink
+	Reader
+}
+
+// NewAsyncSink returns a sink that queues up to queueSize records for the given sink and writes
+// them by a single worker, the records that don't fit into the queue are dropped. The returned sink
+// implements Reader if the given sink does. The worker runs until stopCh is closed.
+func NewAsyncSink(sink Sink, queueSize int, log *zap.SugaredLogger, stopCh <-chan struct{}) Sink {
+	asyncSink := &AsyncSink{
+		sink:  sink,
+		queue: make(chan *kubermaticv1.AuditRecordSpec, queueSize),
+		log:   log,
+	}
+	go asyncSink.run(stopCh)
+
+	if reader, ok := sink.(Reader); ok {
+		return &asyncReader{AsyncSink: asyncSink, Reader: reader}
+	}
+	return asyncSink
+}
+
+// Write queues the record, ErrQueueFull is returned if the record has been dropped
+func (s *AsyncSink) Write(record *kubermaticv1.AuditRecordSpec) error {
+	select {
+	case s.queue <- record:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+func (s *AsyncSink) run(stopCh <-chan struct{}) {
+	for {
+		select {
+		case <-stopCh:
+			return
+		case record := <-s.queue:
+			if err := s.sink.Write(record); err != nil {
+				s.log.Errorw("failed to write the audit record", "actor", record.Actor, "verb", record.Verb, "path", record.Path, "error", err)
+			}
+		}
+	}
+}
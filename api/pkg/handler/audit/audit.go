@@ -0,0 +1,261 @@
+/*
+Copyright 2020 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package audit records the mutations performed through the Kubermatic API.
+//
+// The records are written to a Sink, the sinks that also implement Reader can be queried
+// by the admins through the API.
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	kubermaticv1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
+	kubermaticcontext "github.com/kubermatic/kubermatic/api/pkg/util/context"
+)
+
+const (
+	// SinkCRD stores the records as AuditRecord resources in the master cluster
+	SinkCRD = "crd"
+	// SinkFile appends the records as JSON lines to a file
+	SinkFile = "file"
+	// SinkWebhook sends the records to a webhook
+	SinkWebhook = "webhook"
+
+	// VerbCreate is recorded for the requests that create a resource
+	VerbCreate = "create"
+	// VerbUpdate is recorded for the requests that replace a resource
+	VerbUpdate = "update"
+	// VerbPatch is recorded for the requests that patch a resource
+	VerbPatch = "patch"
+	// VerbDelete is recorded for the requests that delete a resource
+	VerbDelete = "delete"
+
+	// ResourceProject is recorded for the requests that mutate a project
+	ResourceProject = "project"
+	// ResourceCluster is recorded for the requests that mutate a cluster
+	ResourceCluster = "cluster"
+	// ResourceMember is recorded for the requests that mutate the members of a project
+	ResourceMember = "member"
+	// ResourceNodeDeployment is recorded for the requests that mutate a node deployment
+	ResourceNodeDeployment = "nodedeployment"
+	// ResourceNode is recorded for the requests that mutate a node through the legacy endpoints
+	ResourceNode = "node"
+	// ResourceSSHKey is recorded for the requests that mutate an SSH key
+	ResourceSSHKey = "sshkey"
+	// ResourceClusterSSHKey is recorded for the requests that assign SSH keys to a cluster or detach them
+	ResourceClusterSSHKey = "clustersshkey"
+	// ResourceClusterToken is recorded for the requests that revoke the admin or viewer token of a cluster
+	ResourceClusterToken = "clustertoken"
+	// ResourceClusterRole is recorded for the requests that mutate a cluster role inside of a user cluster
+	ResourceClusterRole = "clusterrole"
+	// ResourceClusterRoleBinding is recorded for the requests that bind users to a cluster role or unbind them
+	ResourceClusterRoleBinding = "clusterrolebinding"
+	// ResourceRole is recorded for the requests that mutate a role inside of a user cluster
+	ResourceRole = "role"
+	// ResourceRoleBinding is recorded for the requests that bind users to a role or unbind them
+	ResourceRoleBinding = "rolebinding"
+	// ResourceClusterTemplate is recorded for the requests that mutate a cluster template
+	ResourceClusterTemplate = "clustertemplate"
+	// ResourceAddon is recorded for the requests that mutate an addon
+	ResourceAddon = "addon"
+	// ResourceEtcdRestore is recorded for the requests that restore the etcd of a cluster
+	ResourceEtcdRestore = "etcdrestore"
+	// ResourceServiceAccount is recorded for the requests that mutate a service account
+	ResourceServiceAccount = "serviceaccount"
+	// ResourceServiceAccountToken is recorded for the requests that mutate or revoke a service account token
+	ResourceServiceAccountToken = "serviceaccounttoken"
+	// ResourceUserSettings is recorded for the requests that mutate the settings of the current user
+	ResourceUserSettings = "usersettings"
+	// ResourceDatacenter is recorded for the requests that mutate a datacenter
+	ResourceDatacenter = "datacenter"
+	// ResourceAdmin is recorded for the requests that grant or revoke the admin rights
+	ResourceAdmin = "admin"
+	// ResourceSettings is recorded for the requests that mutate the global settings
+	ResourceSettings = "settings"
+	// ResourceAdmissionPlugin is recorded for the requests that mutate an admission plugin
+	ResourceAdmissionPlugin = "admissionplugin"
+	// ResourceSeed is recorded for the requests that mutate a seed
+	ResourceSeed = "seed"
+	// ResourceProjectQuota is recorded for the requests that set the quota of a project
+	ResourceProjectQuota = "projectquota"
+	// ResourceGroupProjectBinding is recorded for the requests that mutate a group project binding
+	ResourceGroupProjectBinding = "groupprojectbinding"
+
+	// redacted replaces the values of the sensitive fields
+	redacted = "[REDACTED]"
+
+	// stateContextKey is the key under which the State of the audited request is kept in the ctx
+	stateContextKey kubermaticcontext.Key = "audit-state"
+)
+
+// sensitiveFields are the parts of the field names that indicate credentials,
+// they cover the cloud provider credentials of the clusters and the presets
+var sensitiveFields = []string{
+	"password",
+	"secret",
+	"token",
+	"credential",
+	"kubeconfig",
+	"apikey",
+	"accesskey",
+	"serviceaccount",
+	"privatekey",
+}
+
+// Sink stores the audit records
+type Sink interface {
+	Write(record *kubermaticv1.AuditRecordSpec) error
+}
+
+// Reader is implemented by the sinks that can be queried
+type Reader interface {
+	// List returns the records matching the filter, the most recent record comes first
+	List(filter Filter) ([]kubermaticv1.AuditRecordSpec, error)
+}
+
+// Filter narrows down the records returned by a Reader, empty fields match all records
+type Filter struct {
+	Actor     string
+	ProjectID string
+	Resource  string
+	Verb      string
+	Since     time.Time
+	// Limit is the maximum number of records returned, zero means no limit
+	Limit int
+}
+
+// Matches tells whether the record passes the filter
+func (f Filter) Matches(record *kubermaticv1.AuditRecordSpec) bool {
+	if f.Actor != "" && !strings.EqualFold(f.Actor, record.Actor) {
+		return false
+	}
+	if f.ProjectID != "" && f.ProjectID != record.ProjectID {
+		return false
+	}
+	if f.Resource != "" && f.Resource != record.Resource {
+		return false
+	}
+	if f.Verb != "" && f.Verb != record.Verb {
+		return false
+	}
+	if !f.Since.IsZero() && record.Timestamp.Time.Before(f.Since) {
+		return false
+	}
+	return true
+}
+
+// apply returns the records matching the filter, the most recent record comes first
+func (f Filter) apply(records []kubermaticv1.AuditRecordSpec) []kubermaticv1.AuditRecordSpec {
+	result := []kubermaticv1.AuditRecordSpec{}
+	for i := range records {
+		if f.Matches(&records[i]) {
+			result = append(result, records[i])
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Timestamp.After(result[j].Timestamp.Time)
+	})
+	if f.Limit > 0 && len(result) > f.Limit {
+		result = result[:f.Limit]
+	}
+	return result
+}
+
+// Redact marshals the request to JSON and replaces the values of the fields that hold credentials
+func Redact(request interface{}) (string, error) {
+	raw, err := json.Marshal(request)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal the request: %v", err)
+	}
+	var obj interface{}
+	if err := json.Unmarshal(raw, &obj); err != nil {
+		return "", fmt.Errorf("failed to unmarshal the request: %v", err)
+	}
+	raw, err = json.Marshal(redact(obj))
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal the redacted request: %v", err)
+	}
+	return string(raw), nil
+}
+
+func redact(obj interface{}) interface{} {
+	switch v := obj.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if isSensitive(key) && value != nil && value != "" {
+				v[key] = redacted
+				continue
+			}
+			v[key] = redact(value)
+		}
+	case []interface{}:
+		for i := range v {
+			v[i] = redact(v[i])
+		}
+	}
+	return obj
+}
+
+func isSensitive(field string) bool {
+	field = strings.ToLower(field)
+	for _, sensitiveField := range sensitiveFields {
+		if strings.Contains(field, sensitiveField) {
+			return true
+		}
+	}
+	return false
+}
+
+// State holds the state of the resource before the audited request mutated it
+type State struct {
+	lock   sync.Mutex
+	before string
+	err    error
+}
+
+// WithState returns a context in which the endpoints can record the state of the resource before the mutation
+func WithState(ctx context.Context) (context.Context, *State) {
+	state := &State{}
+	return context.WithValue(ctx, stateContextKey, state), state
+}
+
+// Before returns the recorded state of the resource before the mutation as JSON with all credentials redacted,
+// the error tells why the state couldn't be recorded
+func (s *State) Before() (string, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.before, s.err
+}
+
+// RecordBefore records the state of the resource before the mutation, endpoints call it right after they fetched
+// the resource they are about to update, patch or delete. It does nothing if the request is not audited.
+func RecordBefore(ctx context.Context, before interface{}) {
+	state, ok := ctx.Value(stateContextKey).(*State)
+	if !ok {
+		return
+	}
+	rawBefore, err := Redact(before)
+	state.lock.Lock()
+	defer state.lock.Unlock()
+	state.before, state.err = rawBefore, err
+}
@@ -0,0 +1,268 @@
+/*
+Copyright 2020 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+	"go.uber.org/zap"
+
+	kubermaticv1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestRedact(t *testing.T) {
+	request := struct {
+		ProjectID string `json:"project_id"`
+		Body      struct {
+			Name  string `json:"name"`
+			Cloud struct {
+				AWS struct {
+					AccessKeyID     string `json:"accessKeyId"`
+					SecretAccessKey string `json:"secretAccessKey"`
+					VPCID           string `json:"vpcId"`
+				} `json:"aws"`
+				Openstack struct {
+					Username string `json:"username"`
+					Password string `json:"password"`
+				} `json:"openstack"`
+			} `json:"cloud"`
+		} `json:"body"`
+	}{}
+	request.ProjectID = "my-project"
+	request.Body.Name = "my-cluster"
+	request.Body.Cloud.AWS.AccessKeyID = "AKIA"
+	request.Body.Cloud.AWS.SecretAccessKey = "secret"
+	request.Body.Cloud.AWS.VPCID = "vpc-1"
+	request.Body.Cloud.Openstack.Username = "bob"
+
+	result, err := Redact(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"body":{"cloud":{"aws":{"accessKeyId":"[REDACTED]","secretAccessKey":"[REDACTED]","vpcId":"vpc-1"},"openstack":{"password":"","username":"bob"}},"name":"my-cluster"},"project_id":"my-project"}`
+	if result != expected {
+		t.Fatalf("unexpected redacted request\nexpected: %s\ngot:      %s", expected, result)
+	}
+}
+
+func TestFilter(t *testing.T) {
+	now := time.Now()
+	records := []kubermaticv1.AuditRecordSpec{
+		genRecord(now.Add(-2*time.Hour), "bob@acme.com", "my-project", VerbCreate, ResourceCluster),
+		genRecord(now.Add(-1*time.Hour), "Bob@acme.com", "my-project", VerbDelete, ResourceCluster),
+		genRecord(now, "alice@acme.com", "my-project", VerbCreate, ResourceMember),
+		genRecord(now.Add(-3*time.Hour), "bob@acme.com", "other-project", VerbCreate, ResourceProject),
+	}
+
+	testcases := []struct {
+		name     string
+		filter   Filter
+		expected []kubermaticv1.AuditRecordSpec
+	}{
+		{
+			name:     "scenario 1: an empty filter returns all records, the most recent comes first",
+			filter:   Filter{},
+			expected: []kubermaticv1.AuditRecordSpec{records[2], records[1], records[0], records[3]},
+		},
+		{
+			name:     "scenario 2: the actor is matched case insensitively",
+			filter:   Filter{Actor: "bob@acme.com", ProjectID: "my-project"},
+			expected: []kubermaticv1.AuditRecordSpec{records[1], records[0]},
+		},
+		{
+			name:     "scenario 3: the records can be narrowed down by the resource, the verb and the time",
+			filter:   Filter{Resource: ResourceCluster, Verb: VerbDelete, Since: now.Add(-90 * time.Minute)},
+			expected: []kubermaticv1.AuditRecordSpec{records[1]},
+		},
+		{
+			name:     "scenario 4: the number of records is limited",
+			filter:   Filter{Limit: 1},
+			expected: []kubermaticv1.AuditRecordSpec{records[2]},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			if diff := deep.Equal(tc.filter.apply(records), tc.expected); diff != nil {
+				t.Fatalf("unexpected records, diff = %v", diff)
+			}
+		})
+	}
+}
+
+func TestFileSink(t *testing.T) {
+	dir, err := ioutil.TempDir("", "audit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	sink, err := NewFileSink(filepath.Join(dir, "audit.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now().Truncate(time.Second)
+	first := genRecord(now.Add(-time.Minute), "bob@acme.com", "my-project", VerbCreate, ResourceCluster)
+	second := genRecord(now, "bob@acme.com", "my-project", VerbDelete, ResourceCluster)
+	for _, record := range []kubermaticv1.AuditRecordSpec{first, second} {
+		if err := sink.Write(&record); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	records, err := sink.List(Filter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 || records[0].Verb != VerbDelete || records[1].Verb != VerbCreate {
+		t.Fatalf("unexpected records %v", records)
+	}
+}
+
+func TestWebhookSink(t *testing.T) {
+	received := []kubermaticv1.AuditRecordSpec{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		record := kubermaticv1.AuditRecordSpec{}
+		if err := json.NewDecoder(r.Body).Decode(&record); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		received = append(received, record)
+	}))
+	defer server.Close()
+
+	record := genRecord(time.Now(), "bob@acme.com", "my-project", VerbCreate, ResourceCluster)
+	if err := NewWebhookSink(server.URL).Write(&record); err != nil {
+		t.Fatal(err)
+	}
+	if len(received) != 1 || received[0].Actor != "bob@acme.com" {
+		t.Fatalf("unexpected records received by the webhook %v", received)
+	}
+
+	failingServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failingServer.Close()
+	if err := NewWebhookSink(failingServer.URL).Write(&record); err == nil {
+		t.Fatal("expected an error when the webhook fails")
+	}
+}
+
+func TestAsyncSink(t *testing.T) {
+	blockCh := make(chan struct{})
+	received := make(chan *kubermaticv1.AuditRecordSpec, 2)
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+
+	sink := NewAsyncSink(sinkFunc(func(record *kubermaticv1.AuditRecordSpec) error {
+		<-blockCh
+		received <- record
+		return nil
+	}), 1, zap.NewNop().Sugar(), stopCh)
+
+	first := genRecord(time.Now(), "bob@acme.com", "my-project", VerbCreate, ResourceCluster)
+	second := genRecord(time.Now(), "bob@acme.com", "my-project", VerbDelete, ResourceCluster)
+	if err := sink.Write(&first); err != nil {
+		t.Fatal(err)
+	}
+	// the worker takes the first record from the queue and blocks on it,
+	// so the second record fills the queue and the third one is dropped
+	if err := wait.PollImmediate(10*time.Millisecond, 5*time.Second, func() (bool, error) {
+		return sink.Write(&second) == nil, nil
+	}); err != nil {
+		t.Fatalf("failed to queue the second record: %v", err)
+	}
+	if err := sink.Write(&second); err != ErrQueueFull {
+		t.Fatalf("expected %v when the queue is full, got %v", ErrQueueFull, err)
+	}
+
+	close(blockCh)
+	for _, expected := range []string{VerbCreate, VerbDelete} {
+		select {
+		case record := <-received:
+			if record.Verb != expected {
+				t.Fatalf("expected a record with the verb %q, got %q", expected, record.Verb)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for the record to be written")
+		}
+	}
+}
+
+func TestCRDSinkDeleteExpired(t *testing.T) {
+	testScheme := runtime.NewScheme()
+	if err := kubermaticv1.AddToScheme(testScheme); err != nil {
+		t.Fatal(err)
+	}
+	client := fakectrlruntimeclient.NewFakeClientWithScheme(testScheme)
+	sink := NewCRDSink(client, client)
+
+	now := time.Now().Truncate(time.Second)
+	expired := genRecord(now.Add(-48*time.Hour), "bob@acme.com", "my-project", VerbCreate, ResourceCluster)
+	recent := genRecord(now, "bob@acme.com", "my-project", VerbDelete, ResourceCluster)
+	for _, record := range []kubermaticv1.AuditRecordSpec{expired, recent} {
+		if err := sink.Write(&record); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	deleted, err := sink.DeleteExpired(context.Background(), now.Add(-24*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deleted != 1 {
+		t.Fatalf("expected one deleted record, got %d", deleted)
+	}
+	records, err := sink.List(Filter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 || records[0].Verb != VerbDelete {
+		t.Fatalf("unexpected records %v", records)
+	}
+}
+
+type sinkFunc func(record *kubermaticv1.AuditRecordSpec) error
+
+func (f sinkFunc) Write(record *kubermaticv1.AuditRecordSpec) error {
+	return f(record)
+}
+
+func genRecord(timestamp time.Time, actor, projectID, verb, resource string) kubermaticv1.AuditRecordSpec {
+	return kubermaticv1.AuditRecordSpec{
+		Timestamp: metav1.NewTime(timestamp),
+		Actor:     actor,
+		ProjectID: projectID,
+		Verb:      verb,
+		Resource:  resource,
+		Succeeded: true,
+	}
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2020 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	kubermaticv1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
+
+	"go.uber.org/zap"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/apimachinery/pkg/util/wait"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// CRDSink stores the records as AuditRecord resources
+type CRDSink struct {
+	client ctrlruntimeclient.Client
+	// apiReader reads the records directly from the API server, the records are not cached
+	apiReader ctrlruntimeclient.Reader
+}
+
+var _ Sink = &CRDSink{}
+var _ Reader = &CRDSink{}
+
+// NewCRDSink returns a sink that stores the records as AuditRecord resources in the cluster of the given client,
+// the records are read by the apiReader which must not be backed by a cache
+func NewCRDSink(client ctrlruntimeclient.Client, apiReader ctrlruntimeclient.Reader) *CRDSink {
+	return &CRDSink{client: client, apiReader: apiReader}
+}
+
+// Write creates an AuditRecord for the given record
+func (s *CRDSink) Write(record *kubermaticv1.AuditRecordSpec) error {
+	auditRecord := &kubermaticv1.AuditRecord{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: fmt.Sprintf("%d-%s", record.Timestamp.Unix(), rand.String(10)),
+		},
+		Spec: *record,
+	}
+	if err := s.client.Create(context.Background(), auditRecord); err != nil {
+		return fmt.Errorf("failed to create audit record: %v", err)
+	}
+	return nil
+}
+
+// List returns the AuditRecords matching the filter
+func (s *CRDSink) List(filter Filter) ([]kubermaticv1.AuditRecordSpec, error) {
+	auditRecords := &kubermaticv1.AuditRecordList{}
+	if err := s.apiReader.List(context.Background(), auditRecords); err != nil {
+		return nil, fmt.Errorf("failed to list audit records: %v", err)
+	}
+	records := make([]kubermaticv1.AuditRecordSpec, 0, len(auditRecords.Items))
+	for _, auditRecord := range auditRecords.Items {
+		records = append(records, auditRecord.Spec)
+	}
+	return filter.apply(records), nil
+}
+
+// DeleteExpired deletes the AuditRecords of the requests that were handled before the given time
+func (s *CRDSink) DeleteExpired(ctx context.Context, before time.Time) (int, error) {
+	auditRecords := &kubermaticv1.AuditRecordList{}
+	if err := s.apiReader.List(ctx, auditRecords); err != nil {
+		return 0, fmt.Errorf("failed to list audit records: %v", err)
+	}
+	deleted := 0
+	for i := range auditRecords.Items {
+		auditRecord := &auditRecords.Items[i]
+		if !auditRecord.Spec.Timestamp.Time.Before(before) {
+			continue
+		}
+		if err := s.client.Delete(ctx, auditRecord); err != nil && !kerrors.IsNotFound(err) {
+			return deleted, fmt.Errorf("failed to delete audit record %s: %v", auditRecord.Name, err)
+		}
+		deleted++
+	}
+	return deleted, nil
+}
+
+// RunRetention deletes the AuditRecords older than the retention every interval until stopCh is closed.
+// Running it in several replicas of the API is safe.
+func (s *CRDSink) RunRetention(retention, interval time.Duration, log *zap.SugaredLogger, stopCh <-chan struct{}) {
+	wait.Until(func() {
+		deleted, err := s.DeleteExpired(context.Background(), time.Now().Add(-retention))
+		if err != nil {
+			log.Errorw("failed to delete the expired audit records", "error", err)
+		}
+		if deleted > 0 {
+			log.Debugw("deleted the expired audit records", "count", deleted)
+		}
+	}, interval, stopCh)
+}
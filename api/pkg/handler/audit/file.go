@@ -0,0 +1,96 @@
+/*
+Copyright 2020 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	kubermaticv1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
+)
+
+// FileSink appends the records as JSON lines to a file
+type FileSink struct {
+	path string
+	lock sync.Mutex
+}
+
+var _ Sink = &FileSink{}
+var _ Reader = &FileSink{}
+
+// NewFileSink returns a sink that appends the records to the file at the given path,
+// the file is created if it doesn't exist
+func NewFileSink(path string) (*FileSink, error) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit file %s: %v", path, err)
+	}
+	if err := f.Close(); err != nil {
+		return nil, err
+	}
+	return &FileSink{path: path}, nil
+}
+
+// Write appends the record to the file
+func (s *FileSink) Write(record *kubermaticv1.AuditRecordSpec) error {
+	raw, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit record: %v", err)
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	f, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open audit file %s: %v", s.path, err)
+	}
+	defer f.Close()
+	if _, err := f.Write(append(raw, '\n')); err != nil {
+		return fmt.Errorf("failed to write audit record: %v", err)
+	}
+	return nil
+}
+
+// List reads the file and returns the records matching the filter
+func (s *FileSink) List(filter Filter) ([]kubermaticv1.AuditRecordSpec, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	f, err := os.Open(s.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit file %s: %v", s.path, err)
+	}
+	defer f.Close()
+
+	records := []kubermaticv1.AuditRecordSpec{}
+	decoder := json.NewDecoder(f)
+	for {
+		record := kubermaticv1.AuditRecordSpec{}
+		if err := decoder.Decode(&record); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, fmt.Errorf("failed to read audit file %s: %v", s.path, err)
+		}
+		records = append(records, record)
+	}
+	return filter.apply(records), nil
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2020 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	kubermaticv1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
+)
+
+// WebhookSink sends the records to a webhook, the records cannot be queried through the API
+type WebhookSink struct {
+	url    string
+	client *http.Client
+}
+
+var _ Sink = &WebhookSink{}
+
+// NewWebhookSink returns a sink that POSTs every record as JSON to the given URL
+func NewWebhookSink(url string) *WebhookSink {
+	return &WebhookSink{
+		url:    url,
+		client: &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+// Write sends the record to the webhook
+func (s *WebhookSink) Write(record *kubermaticv1.AuditRecordSpec) error {
+	raw, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit record: %v", err)
+	}
+	resp, err := s.client.Post(s.url, "application/json", bytes.NewReader(raw))
+	if err != nil {
+		return fmt.Errorf("failed to send audit record: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("failed to send audit record, the webhook responded with %s", resp.Status)
+	}
+	return nil
+}
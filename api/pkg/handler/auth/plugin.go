@@ -30,6 +30,10 @@ type TokenClaims struct {
 	Email   string
 	Subject string
 	Groups  []string
+	// Clusters restricts the token to the given clusters, empty means no restriction
+	Clusters []string
+	// ReadOnly restricts the token to read-only requests
+	ReadOnly bool
 }
 
 // TokenExtractorVerifier combines TokenVerifier and TokenExtractor interfaces
@@ -54,6 +54,14 @@ func (s *ServiceAccountAuthClient) Verify(ctx context.Context, token string) (To
 		return TokenClaims{}, err
 	}
 
+	revoked, err := s.saTokenProvider.IsRevokedUnsecured(customClaims.TokenID)
+	if err != nil {
+		return TokenClaims{}, fmt.Errorf("sa: cannot check if the token %s has been revoked: %v", customClaims.TokenID, err)
+	}
+	if revoked {
+		return TokenClaims{}, fmt.Errorf("sa: the token %s has been revoked for %s", customClaims.TokenID, customClaims.Email)
+	}
+
 	tokenList, err := s.saTokenProvider.ListUnsecured(&provider.ServiceAccountTokenListOptions{TokenID: customClaims.TokenID})
 	if kerrors.IsNotFound(err) {
 		return TokenClaims{}, fmt.Errorf("sa: the token %s has been revoked for %s", customClaims.TokenID, customClaims.Email)
@@ -71,8 +79,10 @@ func (s *ServiceAccountAuthClient) Verify(ctx context.Context, token string) (To
 	}
 
 	return TokenClaims{
-		Name:    customClaims.TokenID,
-		Email:   customClaims.Email,
-		Subject: customClaims.Email,
+		Name:     customClaims.TokenID,
+		Email:    customClaims.Email,
+		Subject:  customClaims.Email,
+		Clusters: customClaims.Clusters,
+		ReadOnly: customClaims.ReadOnly,
 	}, nil
 }
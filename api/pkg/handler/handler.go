@@ -22,6 +22,7 @@ import (
 	"net/http"
 	"reflect"
 
+	"github.com/kubermatic/kubermatic/api/pkg/handler/v1/cluster"
 	"github.com/kubermatic/kubermatic/api/pkg/log"
 	"github.com/kubermatic/kubermatic/api/pkg/util/errors"
 )
@@ -73,6 +74,11 @@ func errorEncoder(ctx context.Context, err error, w http.ResponseWriter) {
 		errorCode = h.StatusCode()
 		msg = h.Error()
 		additional = h.Details()
+		for key, values := range h.Headers() {
+			for _, value := range values {
+				w.Header().Add(key, value)
+			}
+		}
 	}
 	e := ErrorResponse{
 		Error: ErrorDetails{
@@ -116,6 +122,18 @@ func encodeJSON(c context.Context, w http.ResponseWriter, response interface{})
 	return json.NewEncoder(w).Encode(response)
 }
 
+// encodeProjectClusterList reports the seeds whose clusters couldn't be listed in the response headers
+// and writes the clusters as JSON array
+func encodeProjectClusterList(c context.Context, w http.ResponseWriter, response interface{}) error {
+	if list, ok := response.(cluster.ProjectClusterList); ok {
+		for _, seedError := range list.SeedErrors {
+			w.Header().Add(cluster.SeedErrorHeader, seedError)
+		}
+		response = list.Clusters
+	}
+	return encodeJSON(c, w, response)
+}
+
 // statusOK returns the status code 200
 func statusOK(res http.ResponseWriter, _ *http.Request) {
 	res.WriteHeader(http.StatusOK)
@@ -19,9 +19,13 @@ package handler
 import (
 	"context"
 	"io/ioutil"
+	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/kubermatic/kubermatic/api/pkg/util/errors"
 )
 
 func TestEncodeJSON(t *testing.T) {
@@ -60,3 +64,19 @@ func TestEncodeJSON(t *testing.T) {
 		}
 	}
 }
+
+func TestErrorEncoderSetsHeaders(t *testing.T) {
+	writer := httptest.NewRecorder()
+	errorEncoder(context.TODO(), errors.NewTooManyRequests("too many requests", 1500*time.Millisecond), writer)
+
+	if writer.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected HTTP status code %d, got %d", http.StatusTooManyRequests, writer.Code)
+	}
+	if retryAfter := writer.Header().Get("Retry-After"); retryAfter != "2" {
+		t.Fatalf("expected Retry-After to be rounded up to 2 seconds, got %q", retryAfter)
+	}
+	expected := `{"error":{"code":429,"message":"too many requests"}}`
+	if body := strings.TrimSpace(writer.Body.String()); body != expected {
+		t.Fatalf("expected the body %s, got %s", expected, body)
+	}
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2020 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/go-kit/kit/endpoint"
+	transporthttp "github.com/go-kit/kit/transport/http"
+
+	apiv1 "github.com/kubermatic/kubermatic/api/pkg/api/v1"
+	kubermaticapiv1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
+	"github.com/kubermatic/kubermatic/api/pkg/handler/audit"
+	kubermaticlog "github.com/kubermatic/kubermatic/api/pkg/log"
+	k8cerrors "github.com/kubermatic/kubermatic/api/pkg/util/errors"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type projectIDGetter interface {
+	GetProjectID() string
+}
+
+// Audit is a middleware that records the mutation performed by the endpoint in the audit trail,
+// it must be chained after UserSaver so that the actor is known. A nil sink disables the audit trail.
+// The state of the resource before the mutation is recorded if the endpoint passes it to audit.RecordBefore,
+// the response of a successful request is recorded as the state after the mutation.
+// Failing to write the record doesn't fail the request, the error is logged instead.
+func Audit(sink audit.Sink, verb, resource string) endpoint.Middleware {
+	return func(next endpoint.Endpoint) endpoint.Endpoint {
+		return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+			if sink == nil {
+				return next(ctx, request)
+			}
+			ctx, state := audit.WithState(ctx)
+			response, err = next(ctx, request)
+
+			record := newAuditRecord(ctx, request, response, err, verb, resource)
+			before, stateErr := state.Before()
+			if stateErr != nil {
+				kubermaticlog.Logger.Debugw("failed to redact the state before the mutation for the audit record", "path", record.Path, "error", stateErr)
+			}
+			record.Before = before
+			if writeErr := sink.Write(record); writeErr != nil {
+				kubermaticlog.Logger.Errorw("failed to write the audit record", "actor", record.Actor, "verb", verb, "path", record.Path, "error", writeErr)
+			}
+			return response, err
+		}
+	}
+}
+
+func newAuditRecord(ctx context.Context, request, response interface{}, err error, verb, resource string) *kubermaticapiv1.AuditRecordSpec {
+	record := &kubermaticapiv1.AuditRecordSpec{
+		Timestamp: metav1.NewTime(time.Now()),
+		Verb:      verb,
+		Resource:  resource,
+		Succeeded: err == nil,
+	}
+	record.Path, _ = ctx.Value(transporthttp.ContextKeyRequestPath).(string)
+	if user, ok := ctx.Value(UserCRContextKey).(*kubermaticapiv1.User); ok {
+		record.Actor = user.Spec.Email
+	}
+
+	if getter, ok := request.(projectIDGetter); ok {
+		record.ProjectID = getter.GetProjectID()
+	} else if project, ok := response.(apiv1.Project); ok {
+		// the ID of a new project is only known after it has been created
+		record.ProjectID = project.ID
+	}
+
+	if request != nil {
+		rawRequest, redactErr := audit.Redact(request)
+		if redactErr != nil {
+			kubermaticlog.Logger.Debugw("failed to redact the request for the audit record", "path", record.Path, "error", redactErr)
+		}
+		record.Request = rawRequest
+	}
+	if err == nil && response != nil && verb != audit.VerbDelete {
+		rawResponse, redactErr := audit.Redact(response)
+		if redactErr != nil {
+			kubermaticlog.Logger.Debugw("failed to redact the response for the audit record", "path", record.Path, "error", redactErr)
+		}
+		record.After = rawResponse
+	}
+
+	if err != nil {
+		record.Error = err.Error()
+		record.StatusCode = http.StatusInternalServerError
+		if httpErr, ok := err.(k8cerrors.HTTPError); ok {
+			record.StatusCode = httpErr.StatusCode()
+		}
+	}
+	return record
+}
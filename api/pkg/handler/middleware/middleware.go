@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"regexp"
 	"time"
 
 	"github.com/go-kit/kit/endpoint"
@@ -58,12 +59,21 @@ const (
 	// AuthenticatedUserContextKey key under which the current User (from OIDC provider) is kept in the ctx
 	AuthenticatedUserContextKey kubermaticcontext.Key = "authenticated-user"
 
+	// AuthenticatedUserGroupsContextKey key under which the groups of the current User (from the "groups" claim) are kept in the ctx
+	AuthenticatedUserGroupsContextKey kubermaticcontext.Key = "authenticated-user-groups"
+
 	// AddonProviderContextKey key under which the current AddonProvider is kept in the ctx
 	AddonProviderContextKey kubermaticcontext.Key = "addon-provider"
 
 	// PrivilegedAddonProviderContextKey key under which the current PrivilegedAddonProvider is kept in the ctx
 	PrivilegedAddonProviderContextKey kubermaticcontext.Key = "privileged-addon-provider"
 
+	// EtcdRestoreProviderContextKey key under which the current EtcdRestoreProvider is kept in the ctx
+	EtcdRestoreProviderContextKey kubermaticcontext.Key = "etcd-restore-provider"
+
+	// PrivilegedEtcdRestoreProviderContextKey key under which the current PrivilegedEtcdRestoreProvider is kept in the ctx
+	PrivilegedEtcdRestoreProviderContextKey kubermaticcontext.Key = "privileged-etcd-restore-provider"
+
 	UserCRContextKey = kubermaticcontext.UserCRContextKey
 )
 
@@ -72,6 +82,11 @@ type dCGetter interface {
 	GetDC() string
 }
 
+// clusterIDGetter defines functionality to retrieve a cluster ID
+type clusterIDGetter interface {
+	GetClusterID() string
+}
+
 // SetClusterProvider is a middleware that injects the current ClusterProvider into the ctx
 func SetClusterProvider(clusterProviderGetter provider.ClusterProviderGetter, seedsGetter provider.SeedsGetter) endpoint.Middleware {
 	return func(next endpoint.Endpoint) endpoint.Endpoint {
@@ -105,8 +120,9 @@ func SetPrivilegedClusterProvider(clusterProviderGetter provider.ClusterProvider
 }
 
 // UserSaver is a middleware that checks if authenticated user already exists in the database
-// next it creates/retrieve an internal object (kubermaticv1.User) and stores it the ctx under UserCRContexKey
-func UserSaver(userProvider provider.UserProvider) endpoint.Middleware {
+// next it creates/retrieve an internal object (kubermaticv1.User) and stores it the ctx under UserCRContexKey.
+// The project memberships derived from the groups of the identity provider are synchronized when the groups changed.
+func UserSaver(userProvider provider.UserProvider, groupProjectBindingProvider provider.GroupProjectBindingProvider) endpoint.Middleware {
 	return func(next endpoint.Endpoint) endpoint.Endpoint {
 		return func(ctx context.Context, request interface{}) (response interface{}, err error) {
 			rawAuthenticatesUser := ctx.Value(AuthenticatedUserContextKey)
@@ -131,6 +147,11 @@ func UserSaver(userProvider provider.UserProvider) endpoint.Middleware {
 					}
 				}
 			}
+
+			groups, _ := ctx.Value(AuthenticatedUserGroupsContextKey).([]string)
+			if err := groupProjectBindingProvider.SyncOnChangeUnsecured(user.Spec.Email, groups); err != nil {
+				return nil, common.KubernetesErrorToHTTPError(err)
+			}
 			return next(context.WithValue(ctx, kubermaticcontext.UserCRContextKey, user), request)
 		}
 	}
@@ -215,6 +236,11 @@ func TokenVerifier(tokenVerifier auth.TokenVerifier) endpoint.Middleware {
 				return nil, k8cerrors.NewNotAuthorized()
 			}
 
+			if err := CheckTokenScope(ctx, request, claims); err != nil {
+				return nil, err
+			}
+
+			ctx = context.WithValue(ctx, AuthenticatedUserGroupsContextKey, claims.Groups)
 			return next(context.WithValue(ctx, AuthenticatedUserContextKey, user), request)
 		}
 	}
@@ -265,6 +291,51 @@ func getAddonProvider(addonProviderGetter provider.AddonProviderGetter, seedsGet
 	return addonProviderGetter(seed)
 }
 
+// EtcdRestores is a middleware that injects the current EtcdRestoreProvider into the ctx
+func EtcdRestores(etcdRestoreProviderGetter provider.EtcdRestoreProviderGetter, seedsGetter provider.SeedsGetter) endpoint.Middleware {
+	return func(next endpoint.Endpoint) endpoint.Endpoint {
+		return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+			seedName := request.(dCGetter).GetDC()
+			etcdRestoreProvider, err := getEtcdRestoreProvider(etcdRestoreProviderGetter, seedsGetter, seedName)
+			if err != nil {
+				return nil, err
+			}
+			ctx = context.WithValue(ctx, EtcdRestoreProviderContextKey, etcdRestoreProvider)
+			return next(ctx, request)
+		}
+	}
+}
+
+// PrivilegedEtcdRestores is a middleware that injects the current PrivilegedEtcdRestoreProvider into the ctx
+func PrivilegedEtcdRestores(etcdRestoreProviderGetter provider.EtcdRestoreProviderGetter, seedsGetter provider.SeedsGetter) endpoint.Middleware {
+	return func(next endpoint.Endpoint) endpoint.Endpoint {
+		return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+			seedName := request.(dCGetter).GetDC()
+			etcdRestoreProvider, err := getEtcdRestoreProvider(etcdRestoreProviderGetter, seedsGetter, seedName)
+			if err != nil {
+				return nil, err
+			}
+			privilegedEtcdRestoreProvider := etcdRestoreProvider.(provider.PrivilegedEtcdRestoreProvider)
+			ctx = context.WithValue(ctx, PrivilegedEtcdRestoreProviderContextKey, privilegedEtcdRestoreProvider)
+			return next(ctx, request)
+		}
+	}
+}
+
+func getEtcdRestoreProvider(etcdRestoreProviderGetter provider.EtcdRestoreProviderGetter, seedsGetter provider.SeedsGetter, seedName string) (provider.EtcdRestoreProvider, error) {
+	seeds, err := seedsGetter()
+	if err != nil {
+		return nil, err
+	}
+
+	seed, found := seeds[seedName]
+	if !found {
+		return nil, fmt.Errorf("couldn't find seed %q", seedName)
+	}
+
+	return etcdRestoreProviderGetter(seed)
+}
+
 // TokenExtractor knows how to extract a token from the incoming request
 func TokenExtractor(o auth.TokenExtractor) transporthttp.RequestFunc {
 	return func(ctx context.Context, r *http.Request) context.Context {
@@ -276,6 +347,48 @@ func TokenExtractor(o auth.TokenExtractor) transporthttp.RequestFunc {
 	}
 }
 
+// credentialRequestPaths match the paths of GET requests which return credentials. Those credentials grant
+// write access, so read-only tokens must not be used for them.
+var credentialRequestPaths = []*regexp.Regexp{
+	regexp.MustCompile(`/clusters/[^/]+/kubeconfig/?$`),
+	regexp.MustCompile(`/clusters/[^/]+/oidckubeconfig/?$`),
+}
+
+// CheckTokenScope rejects requests that are outside of the scope the token was restricted to.
+// A read-only token may only be used for GET and HEAD requests which don't return credentials and
+// a token restricted to a set of clusters may only be used for requests that address one of them.
+// The request method and path are taken from the context, as populated by the go-kit HTTP transport.
+func CheckTokenScope(ctx context.Context, request interface{}, claims auth.TokenClaims) error {
+	if claims.ReadOnly {
+		method, _ := ctx.Value(transporthttp.ContextKeyRequestMethod).(string)
+		if method != http.MethodGet && method != http.MethodHead {
+			return k8cerrors.New(http.StatusForbidden, "forbidden: the token is restricted to read-only requests")
+		}
+		path, _ := ctx.Value(transporthttp.ContextKeyRequestPath).(string)
+		for _, credentialPath := range credentialRequestPaths {
+			if credentialPath.MatchString(path) {
+				return k8cerrors.New(http.StatusForbidden, "forbidden: the token is restricted to read-only requests, which must not return credentials")
+			}
+		}
+	}
+
+	if len(claims.Clusters) > 0 {
+		getter, ok := request.(clusterIDGetter)
+		if !ok {
+			return k8cerrors.New(http.StatusForbidden, fmt.Sprintf("forbidden: the token is restricted to the clusters %v", claims.Clusters))
+		}
+		clusterID := getter.GetClusterID()
+		for _, allowed := range claims.Clusters {
+			if allowed == clusterID {
+				return nil
+			}
+		}
+		return k8cerrors.New(http.StatusForbidden, fmt.Sprintf("forbidden: the token is not allowed to access the cluster %q", clusterID))
+	}
+
+	return nil
+}
+
 func createUserInfo(user *kubermaticapiv1.User, projectID string, userProjectMapper provider.ProjectMemberMapper) (*provider.UserInfo, error) {
 	var group string
 	if projectID != "" {
@@ -0,0 +1,248 @@
+/*
+Copyright 2020 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/time/rate"
+
+	apiv1 "github.com/kubermatic/kubermatic/api/pkg/api/v1"
+	k8cerrors "github.com/kubermatic/kubermatic/api/pkg/util/errors"
+)
+
+const (
+	// RateLimitGroupDefault is the endpoint group of all endpoints that don't belong to a more specific group
+	RateLimitGroupDefault = "default"
+	// RateLimitGroupProviders is the endpoint group of the endpoints that query the cloud providers, e.g. the size listings
+	RateLimitGroupProviders = "providers"
+	// RateLimitGroupClusters is the endpoint group of the endpoints that list the clusters in all seeds
+	RateLimitGroupClusters = "clusters"
+
+	// rateLimitReasonRate is recorded when a user ran out of tokens
+	rateLimitReasonRate = "rate"
+	// rateLimitReasonConcurrency is recorded when a user has too many requests in flight
+	rateLimitReasonConcurrency = "concurrency"
+
+	// idleLimiterTTL is the time after which the buckets of the users that didn't send any request are dropped
+	idleLimiterTTL = 10 * time.Minute
+)
+
+// RateLimitGroups are the endpoint groups that can be limited
+var RateLimitGroups = []string{RateLimitGroupDefault, RateLimitGroupProviders, RateLimitGroupClusters}
+
+// Rate is a token bucket that is refilled with QPS tokens per second and holds at most Burst tokens
+type Rate struct {
+	QPS   float64
+	Burst int
+}
+
+// ParseRates parses the rates in the form "group=qps:burst,...", e.g. "default=20:40,providers=1:5"
+func ParseRates(value string) (map[string]Rate, error) {
+	rates := map[string]Rate{}
+	if value == "" {
+		return rates, nil
+	}
+
+	for _, entry := range strings.Split(value, ",") {
+		parts := strings.SplitN(strings.TrimSpace(entry), "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid rate %q, expected group=qps:burst", entry)
+		}
+		group := parts[0]
+		if !isRateLimitGroup(group) {
+			return nil, fmt.Errorf("invalid rate %q, the group must be one of %v", entry, RateLimitGroups)
+		}
+		limits := strings.SplitN(parts[1], ":", 2)
+		if len(limits) != 2 {
+			return nil, fmt.Errorf("invalid rate %q, expected group=qps:burst", entry)
+		}
+		qps, err := strconv.ParseFloat(limits[0], 64)
+		if err != nil || qps <= 0 {
+			return nil, fmt.Errorf("invalid rate %q, the qps must be a positive number", entry)
+		}
+		burst, err := strconv.Atoi(limits[1])
+		if err != nil || burst < 1 {
+			return nil, fmt.Errorf("invalid rate %q, the burst must be a positive integer", entry)
+		}
+		rates[group] = Rate{QPS: qps, Burst: burst}
+	}
+	return rates, nil
+}
+
+func isRateLimitGroup(group string) bool {
+	for _, g := range RateLimitGroups {
+		if g == group {
+			return true
+		}
+	}
+	return false
+}
+
+// RateLimiterOptions configures a RateLimiter
+type RateLimiterOptions struct {
+	// Rates holds the rate of every endpoint group, the groups without a rate use the rate of the default group.
+	// When the default group has no rate either, the requests are not rate limited.
+	Rates map[string]Rate
+	// MaxConcurrentRequests is the maximum number of requests a single user can have in flight, zero means no limit
+	MaxConcurrentRequests int
+	// RejectedRequests counts the rejected requests by the endpoint group and the reason, it is optional
+	RejectedRequests *prometheus.CounterVec
+}
+
+// RateLimiter keeps a token bucket per user and endpoint group and counts the requests in flight of every user.
+// Users are identified by their email address, so service accounts are limited independently of their owners.
+type RateLimiter struct {
+	options RateLimiterOptions
+
+	lock      sync.Mutex
+	buckets   map[string]*bucket
+	inFlight  map[string]int
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+type bucket struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// NewRateLimiter creates a RateLimiter
+func NewRateLimiter(options RateLimiterOptions) *RateLimiter {
+	return &RateLimiter{
+		options:  options,
+		buckets:  map[string]*bucket{},
+		inFlight: map[string]int{},
+		now:      time.Now,
+	}
+}
+
+// reserve takes a token from the bucket of the user for the given group, it returns the time the user
+// has to wait for the next token when the bucket is empty
+func (l *RateLimiter) reserve(user, group string) (time.Duration, bool) {
+	limit, ok := l.options.Rates[group]
+	if !ok {
+		limit, ok = l.options.Rates[RateLimitGroupDefault]
+		if !ok {
+			return 0, true
+		}
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+	key := group + "/" + user
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{limiter: rate.NewLimiter(rate.Limit(limit.QPS), limit.Burst)}
+		l.buckets[key] = b
+	}
+	b.lastSeen = now
+
+	reservation := b.limiter.ReserveN(now, 1)
+	if delay := reservation.DelayFrom(now); delay > 0 {
+		reservation.CancelAt(now)
+		return delay, false
+	}
+	return 0, true
+}
+
+// sweep drops the buckets of the idle users, it must be called with the lock held
+func (l *RateLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < idleLimiterTTL {
+		return
+	}
+	for key, b := range l.buckets {
+		if now.Sub(b.lastSeen) > idleLimiterTTL {
+			delete(l.buckets, key)
+		}
+	}
+	l.lastSweep = now
+}
+
+// acquire counts a new request in flight for the user, it returns false when the user has too many of them
+func (l *RateLimiter) acquire(user string) bool {
+	if l.options.MaxConcurrentRequests <= 0 {
+		return true
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if l.inFlight[user] >= l.options.MaxConcurrentRequests {
+		return false
+	}
+	l.inFlight[user]++
+	return true
+}
+
+// release stops counting a request of the user that has been acquired
+func (l *RateLimiter) release(user string) {
+	if l.options.MaxConcurrentRequests <= 0 {
+		return
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.inFlight[user]--
+	if l.inFlight[user] <= 0 {
+		delete(l.inFlight, user)
+	}
+}
+
+func (l *RateLimiter) reject(group, reason string) {
+	if l.options.RejectedRequests != nil {
+		l.options.RejectedRequests.With(prometheus.Labels{"group": group, "reason": reason}).Inc()
+	}
+}
+
+// RateLimit is a middleware that limits the number of requests a user can send to the endpoints of the given group
+// and the number of requests the user can have in flight. The rejected requests get HTTP 429 with a Retry-After header.
+// It must be chained after TokenVerifier so that the user is known. A nil limiter disables the limits.
+func RateLimit(limiter *RateLimiter, group string) endpoint.Middleware {
+	return func(next endpoint.Endpoint) endpoint.Endpoint {
+		return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+			if limiter == nil {
+				return next(ctx, request)
+			}
+			user, ok := ctx.Value(AuthenticatedUserContextKey).(apiv1.User)
+			if !ok {
+				return next(ctx, request)
+			}
+
+			if delay, ok := limiter.reserve(user.Email, group); !ok {
+				limiter.reject(group, rateLimitReasonRate)
+				return nil, k8cerrors.NewTooManyRequests(fmt.Sprintf("too many requests, the rate limit of the %q endpoints has been exceeded", group), delay)
+			}
+			if !limiter.acquire(user.Email) {
+				limiter.reject(group, rateLimitReasonConcurrency)
+				return nil, k8cerrors.NewTooManyRequests("too many requests, the limit of concurrent requests has been exceeded", time.Second)
+			}
+			defer limiter.release(user.Email)
+
+			return next(ctx, request)
+		}
+	}
+}
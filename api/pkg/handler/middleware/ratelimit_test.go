@@ -0,0 +1,191 @@
+/*
+Copyright 2020 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+
+	apiv1 "github.com/kubermatic/kubermatic/api/pkg/api/v1"
+	k8cerrors "github.com/kubermatic/kubermatic/api/pkg/util/errors"
+)
+
+func TestParseRates(t *testing.T) {
+	testcases := []struct {
+		name          string
+		value         string
+		expectedRates map[string]Rate
+		expectedError bool
+	}{
+		{
+			name:          "scenario 1: an empty value disables the rate limits",
+			value:         "",
+			expectedRates: map[string]Rate{},
+		},
+		{
+			name:  "scenario 2: the rates of several groups",
+			value: "default=20:40, providers=0.5:5",
+			expectedRates: map[string]Rate{
+				RateLimitGroupDefault:   {QPS: 20, Burst: 40},
+				RateLimitGroupProviders: {QPS: 0.5, Burst: 5},
+			},
+		},
+		{
+			name:          "scenario 3: unknown groups are rejected",
+			value:         "foo=1:1",
+			expectedError: true,
+		},
+		{
+			name:          "scenario 4: the burst is required",
+			value:         "default=1",
+			expectedError: true,
+		},
+		{
+			name:          "scenario 5: the qps must be positive",
+			value:         "default=0:1",
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			rates, err := ParseRates(tc.value)
+			if tc.expectedError {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := deep.Equal(rates, tc.expectedRates); diff != nil {
+				t.Fatalf("unexpected rates, diff = %v", diff)
+			}
+		})
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	rejected := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "rejected"}, []string{"group", "reason"})
+	now := time.Now()
+	limiter := NewRateLimiter(RateLimiterOptions{
+		Rates: map[string]Rate{
+			RateLimitGroupDefault:   {QPS: 10, Burst: 2},
+			RateLimitGroupProviders: {QPS: 0.5, Burst: 1},
+		},
+		RejectedRequests: rejected,
+	})
+	limiter.now = func() time.Time { return now }
+
+	okEndpoint := func(ctx context.Context, request interface{}) (interface{}, error) {
+		return "ok", nil
+	}
+	send := func(user, group string) error {
+		ctx := context.WithValue(context.Background(), AuthenticatedUserContextKey, apiv1.User{Email: user})
+		_, err := RateLimit(limiter, group)(okEndpoint)(ctx, nil)
+		return err
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := send("bob@acme.com", RateLimitGroupDefault); err != nil {
+			t.Fatalf("request %d within the burst has been rejected: %v", i, err)
+		}
+	}
+	err := send("bob@acme.com", RateLimitGroupDefault)
+	assertTooManyRequests(t, err, "1")
+
+	// the buckets are kept per user and per group, the clusters group falls back to the default rate
+	if err := send("john@acme.com", RateLimitGroupDefault); err != nil {
+		t.Fatalf("the request of another user has been rejected: %v", err)
+	}
+	if err := send("bob@acme.com", RateLimitGroupClusters); err != nil {
+		t.Fatalf("the request to another group has been rejected: %v", err)
+	}
+	if err := send("bob@acme.com", RateLimitGroupProviders); err != nil {
+		t.Fatalf("the first request to the providers has been rejected: %v", err)
+	}
+	err = send("bob@acme.com", RateLimitGroupProviders)
+	assertTooManyRequests(t, err, "2")
+
+	// the bucket is refilled over time
+	now = now.Add(2 * time.Second)
+	if err := send("bob@acme.com", RateLimitGroupProviders); err != nil {
+		t.Fatalf("the request after the refill has been rejected: %v", err)
+	}
+
+	metric := &dto.Metric{}
+	if err := rejected.With(prometheus.Labels{"group": RateLimitGroupProviders, "reason": rateLimitReasonRate}).Write(metric); err != nil {
+		t.Fatal(err)
+	}
+	if count := metric.GetCounter().GetValue(); count != 1 {
+		t.Fatalf("expected 1 rejected request to the providers, got %v", count)
+	}
+}
+
+func TestRateLimitConcurrentRequests(t *testing.T) {
+	limiter := NewRateLimiter(RateLimiterOptions{MaxConcurrentRequests: 1})
+	ctx := context.WithValue(context.Background(), AuthenticatedUserContextKey, apiv1.User{Email: "bob@acme.com"})
+
+	started := make(chan struct{})
+	finish := make(chan struct{})
+	done := make(chan error)
+	blockingEndpoint := func(ctx context.Context, request interface{}) (interface{}, error) {
+		close(started)
+		<-finish
+		return nil, nil
+	}
+	go func() {
+		_, err := RateLimit(limiter, RateLimitGroupDefault)(blockingEndpoint)(ctx, nil)
+		done <- err
+	}()
+	<-started
+
+	okEndpoint := func(ctx context.Context, request interface{}) (interface{}, error) {
+		return nil, nil
+	}
+	_, err := RateLimit(limiter, RateLimitGroupDefault)(okEndpoint)(ctx, nil)
+	assertTooManyRequests(t, err, "1")
+
+	close(finish)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if _, err := RateLimit(limiter, RateLimitGroupDefault)(okEndpoint)(ctx, nil); err != nil {
+		t.Fatalf("the request after the previous one finished has been rejected: %v", err)
+	}
+}
+
+func assertTooManyRequests(t *testing.T, err error, expectedRetryAfter string) {
+	t.Helper()
+	httpErr, ok := err.(k8cerrors.HTTPError)
+	if !ok {
+		t.Fatalf("expected a HTTP error, got %v", err)
+	}
+	if httpErr.StatusCode() != http.StatusTooManyRequests {
+		t.Fatalf("expected HTTP status code %d, got %d", http.StatusTooManyRequests, httpErr.StatusCode())
+	}
+	if retryAfter := httpErr.Headers().Get("Retry-After"); retryAfter != expectedRetryAfter {
+		t.Fatalf("expected Retry-After %q, got %q", expectedRetryAfter, retryAfter)
+	}
+}
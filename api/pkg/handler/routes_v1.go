@@ -48,12 +48,16 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/prometheus/client_golang/prometheus"
 
+	"github.com/kubermatic/kubermatic/api/pkg/handler/audit"
 	"github.com/kubermatic/kubermatic/api/pkg/handler/middleware"
 	v1 "github.com/kubermatic/kubermatic/api/pkg/handler/v1"
 	"github.com/kubermatic/kubermatic/api/pkg/handler/v1/addon"
 	"github.com/kubermatic/kubermatic/api/pkg/handler/v1/cluster"
+	"github.com/kubermatic/kubermatic/api/pkg/handler/v1/clustertemplate"
 	"github.com/kubermatic/kubermatic/api/pkg/handler/v1/common"
 	"github.com/kubermatic/kubermatic/api/pkg/handler/v1/dc"
+	"github.com/kubermatic/kubermatic/api/pkg/handler/v1/etcdbackup"
+	"github.com/kubermatic/kubermatic/api/pkg/handler/v1/etcdrestore"
 	kubernetesdashboard "github.com/kubermatic/kubermatic/api/pkg/handler/v1/kubernetes-dashboard"
 	"github.com/kubermatic/kubermatic/api/pkg/handler/v1/label"
 	"github.com/kubermatic/kubermatic/api/pkg/handler/v1/node"
@@ -380,6 +384,28 @@ func (r Routing) RegisterV1(mux *mux.Router, metrics common.ServerMetrics) {
 		Path("/projects/{project_id}/dc/{dc}/clusters/{cluster_id}/bindings").
 		Handler(r.listRoleBinding())
 
+	//
+	// Defines a set of HTTP endpoints for cluster templates
+	mux.Methods(http.MethodPost).
+		Path("/projects/{project_id}/dc/{dc}/clusters/{cluster_id}/clustertemplates").
+		Handler(r.createClusterTemplate())
+
+	mux.Methods(http.MethodGet).
+		Path("/projects/{project_id}/clustertemplates").
+		Handler(r.listClusterTemplates())
+
+	mux.Methods(http.MethodGet).
+		Path("/projects/{project_id}/clustertemplates/{template_id}").
+		Handler(r.getClusterTemplate())
+
+	mux.Methods(http.MethodDelete).
+		Path("/projects/{project_id}/clustertemplates/{template_id}").
+		Handler(r.deleteClusterTemplate())
+
+	mux.Methods(http.MethodPost).
+		Path("/projects/{project_id}/clustertemplates/{template_id}/instances").
+		Handler(r.createClusterTemplateInstances(metrics.InitNodeDeploymentFailures))
+
 	//
 	// Defines set of HTTP endpoints for SSH Keys that belong to a cluster
 	mux.Methods(http.MethodPut).
@@ -474,6 +500,24 @@ func (r Routing) RegisterV1(mux *mux.Router, metrics common.ServerMetrics) {
 		Path("/projects/{project_id}/dc/{dc}/clusters/{cluster_id}/addons/{addon_id}").
 		Handler(r.deleteAddon())
 
+	//
+	// Defines a set of HTTP endpoints for restoring the etcd of a cluster from a backup
+	mux.Methods(http.MethodPost).
+		Path("/projects/{project_id}/dc/{dc}/clusters/{cluster_id}/restores").
+		Handler(r.createEtcdRestore())
+
+	mux.Methods(http.MethodGet).
+		Path("/projects/{project_id}/dc/{dc}/clusters/{cluster_id}/restores").
+		Handler(r.listEtcdRestores())
+
+	mux.Methods(http.MethodGet).
+		Path("/projects/{project_id}/dc/{dc}/clusters/{cluster_id}/restores/{restore_id}").
+		Handler(r.getEtcdRestore())
+
+	mux.Methods(http.MethodGet).
+		Path("/projects/{project_id}/dc/{dc}/clusters/{cluster_id}/backups").
+		Handler(r.listEtcdBackups())
+
 	//
 	// Defines a set of HTTP endpoints for various cloud providers
 	// Note that these endpoints don't require credentials as opposed to the ones defined under /providers/*
@@ -627,6 +671,9 @@ func (r Routing) RegisterV1(mux *mux.Router, metrics common.ServerMetrics) {
 	mux.Methods(http.MethodDelete).
 		Path("/projects/{project_id}/serviceaccounts/{serviceaccount_id}/tokens/{token_id}").
 		Handler(r.deleteServiceAccountToken())
+	mux.Methods(http.MethodPost).
+		Path("/projects/{project_id}/serviceaccounts/{serviceaccount_id}/tokens/{token_id}/revoke").
+		Handler(r.revokeServiceAccountToken())
 
 	//
 	// Defines set of HTTP endpoints for control plane and kubelet versions
@@ -680,7 +727,8 @@ func (r Routing) listSSHKeys() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 		)(ssh.ListEndpoint(r.sshKeyProvider, r.projectProvider, r.privilegedProjectProvider, r.userInfoGetter)),
 		ssh.DecodeListReq,
 		encodeJSON,
@@ -707,7 +755,9 @@ func (r Routing) createSSHKey() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
+			middleware.Audit(r.auditSink, audit.VerbCreate, audit.ResourceSSHKey),
 		)(ssh.CreateEndpoint(r.sshKeyProvider, r.privilegedSSHKeyProvider, r.projectProvider, r.privilegedProjectProvider, r.userInfoGetter)),
 		ssh.DecodeCreateReq,
 		setStatusCreatedHeader(encodeJSON),
@@ -731,7 +781,9 @@ func (r Routing) deleteSSHKey() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
+			middleware.Audit(r.auditSink, audit.VerbDelete, audit.ResourceSSHKey),
 		)(ssh.DeleteEndpoint(r.sshKeyProvider, r.privilegedSSHKeyProvider, r.projectProvider, r.privilegedProjectProvider, r.userInfoGetter)),
 		ssh.DecodeDeleteReq,
 		encodeJSON,
@@ -753,7 +805,8 @@ func (r Routing) listCredentials() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 		)(presets.CredentialEndpoint(r.presetsProvider, r.userInfoGetter)),
 		presets.DecodeProviderReq,
 		encodeJSON,
@@ -775,7 +828,8 @@ func (r Routing) listAWSSizes() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupProviders),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 		)(provider.AWSSizeEndpoint()),
 		provider.DecodeAWSSizesReq,
 		encodeJSON,
@@ -797,7 +851,8 @@ func (r Routing) listAWSSubnets() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupProviders),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 		)(provider.AWSSubnetEndpoint(r.presetsProvider, r.seedsGetter, r.userInfoGetter)),
 		provider.DecodeAWSSubnetReq,
 		encodeJSON,
@@ -819,7 +874,8 @@ func (r Routing) listAWSVPCS() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupProviders),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 		)(provider.AWSVPCEndpoint(r.presetsProvider, r.seedsGetter, r.userInfoGetter)),
 		provider.DecodeAWSVPCReq,
 		encodeJSON,
@@ -841,7 +897,8 @@ func (r Routing) listGCPDiskTypes() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupProviders),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 		)(provider.GCPDiskTypesEndpoint(r.presetsProvider, r.userInfoGetter)),
 		provider.DecodeGCPTypesReq,
 		encodeJSON,
@@ -863,7 +920,8 @@ func (r Routing) listGCPSizes() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupProviders),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 		)(provider.GCPSizeEndpoint(r.presetsProvider, r.userInfoGetter)),
 		provider.DecodeGCPTypesReq,
 		encodeJSON,
@@ -885,7 +943,8 @@ func (r Routing) listGCPZones() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupProviders),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 		)(provider.GCPZoneEndpoint(r.presetsProvider, r.seedsGetter, r.userInfoGetter)),
 		provider.DecodeGCPZoneReq,
 		encodeJSON,
@@ -907,7 +966,8 @@ func (r Routing) listGCPNetworks() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupProviders),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 		)(provider.GCPNetworkEndpoint(r.presetsProvider, r.userInfoGetter)),
 		provider.DecodeGCPCommonReq,
 		encodeJSON,
@@ -929,7 +989,8 @@ func (r Routing) listGCPSubnetworks() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupProviders),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 		)(provider.GCPSubnetworkEndpoint(r.presetsProvider, r.seedsGetter, r.userInfoGetter)),
 		provider.DecodeGCPSubnetworksReq,
 		encodeJSON,
@@ -951,7 +1012,8 @@ func (r Routing) listDigitaloceanSizes() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupProviders),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 		)(provider.DigitaloceanSizeEndpoint(r.presetsProvider, r.userInfoGetter)),
 		provider.DecodeDoSizesReq,
 		encodeJSON,
@@ -973,7 +1035,8 @@ func (r Routing) listAzureSizes() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupProviders),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 		)(provider.AzureSizeEndpoint(r.presetsProvider, r.userInfoGetter)),
 		provider.DecodeAzureSizesReq,
 		encodeJSON,
@@ -995,7 +1058,8 @@ func (r Routing) listAzureSKUAvailabilityZones() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupProviders),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 		)(provider.AzureAvailabilityZonesEndpoint(r.presetsProvider, r.userInfoGetter)),
 		provider.DecodeAzureAvailabilityZonesReq,
 		encodeJSON,
@@ -1017,7 +1081,8 @@ func (r Routing) listOpenstackSizes() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupProviders),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 		)(provider.OpenstackSizeEndpoint(r.seedsGetter, r.presetsProvider, r.userInfoGetter)),
 		provider.DecodeOpenstackReq,
 		encodeJSON,
@@ -1039,7 +1104,8 @@ func (r Routing) listVSphereNetworks() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupProviders),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 		)(provider.VsphereNetworksEndpoint(r.seedsGetter, r.presetsProvider, r.userInfoGetter)),
 		provider.DecodeVSphereNetworksReq,
 		encodeJSON,
@@ -1061,7 +1127,8 @@ func (r Routing) listVSphereFolders() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupProviders),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 		)(provider.VsphereFoldersEndpoint(r.seedsGetter, r.presetsProvider, r.userInfoGetter)),
 		provider.DecodeVSphereFoldersReq,
 		encodeJSON,
@@ -1083,7 +1150,8 @@ func (r Routing) listPacketSizes() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupProviders),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 		)(provider.PacketSizesEndpoint(r.presetsProvider, r.userInfoGetter)),
 		provider.DecodePacketSizesReq,
 		encodeJSON,
@@ -1105,7 +1173,8 @@ func (r Routing) listPacketSizesNoCredentials() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupProviders),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 			middleware.SetClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 			middleware.SetPrivilegedClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 		)(provider.PacketSizesWithClusterCredentialsEndpoint(r.projectProvider, r.privilegedProjectProvider, r.userInfoGetter)),
@@ -1129,7 +1198,8 @@ func (r Routing) listOpenstackTenants() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupProviders),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 		)(provider.OpenstackTenantEndpoint(r.seedsGetter, r.presetsProvider, r.userInfoGetter)),
 		provider.DecodeOpenstackTenantReq,
 		encodeJSON,
@@ -1151,7 +1221,8 @@ func (r Routing) listOpenstackNetworks() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupProviders),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 		)(provider.OpenstackNetworkEndpoint(r.seedsGetter, r.presetsProvider, r.userInfoGetter)),
 		provider.DecodeOpenstackReq,
 		encodeJSON,
@@ -1173,7 +1244,8 @@ func (r Routing) listOpenstackSubnets() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupProviders),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 		)(provider.OpenstackSubnetsEndpoint(r.seedsGetter, r.presetsProvider, r.userInfoGetter)),
 		provider.DecodeOpenstackSubnetReq,
 		encodeJSON,
@@ -1195,7 +1267,8 @@ func (r Routing) listOpenstackSecurityGroups() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupProviders),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 		)(provider.OpenstackSecurityGroupEndpoint(r.seedsGetter, r.presetsProvider, r.userInfoGetter)),
 		provider.DecodeOpenstackReq,
 		encodeJSON,
@@ -1217,7 +1290,8 @@ func (r Routing) listHetznerSizes() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupProviders),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 		)(provider.HetznerSizeEndpoint(r.presetsProvider, r.userInfoGetter)),
 		provider.DecodeHetznerSizesReq,
 		encodeJSON,
@@ -1239,7 +1313,8 @@ func (r Routing) listAlibabaInstanceTypes() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupProviders),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 		)(provider.AlibabaInstanceTypesEndpoint(r.presetsProvider, r.userInfoGetter)),
 		provider.DecodeAlibabaReq,
 		encodeJSON,
@@ -1261,7 +1336,8 @@ func (r Routing) listAlibabaZones() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupProviders),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 		)(provider.AlibabaZonesEndpoint(r.presetsProvider, r.userInfoGetter)),
 		provider.DecodeAlibabaReq,
 		encodeJSON,
@@ -1281,7 +1357,8 @@ func (r Routing) datacentersHandler() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 		)(dc.ListEndpoint(r.seedsGetter, r.userInfoGetter)),
 		decodeEmptyReq,
 		encodeJSON,
@@ -1302,7 +1379,8 @@ func (r Routing) datacenterHandler() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 		)(dc.GetEndpoint(r.seedsGetter, r.userInfoGetter)),
 		dc.DecodeLegacyDcReq,
 		encodeJSON,
@@ -1326,7 +1404,8 @@ func (r Routing) listDCForProvider() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 		)(dc.ListEndpointForProvider(r.seedsGetter, r.userInfoGetter)),
 		dc.DecodeForProviderDCListReq,
 		encodeJSON,
@@ -1350,7 +1429,8 @@ func (r Routing) getDCForProvider() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 		)(dc.GetEndpointForProvider(r.seedsGetter, r.userInfoGetter)),
 		dc.DecodeForProviderDCGetReq,
 		encodeJSON,
@@ -1374,7 +1454,8 @@ func (r Routing) listDCForSeed() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 		)(dc.ListEndpointForSeed(r.seedsGetter, r.userInfoGetter)),
 		dc.DecodeListDCForSeedReq,
 		encodeJSON,
@@ -1398,7 +1479,8 @@ func (r Routing) getDCForSeed() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 		)(dc.GetEndpointForSeed(r.seedsGetter, r.userInfoGetter)),
 		dc.DecodeGetDCForSeedReq,
 		encodeJSON,
@@ -1425,7 +1507,9 @@ func (r Routing) createDC() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
+			middleware.Audit(r.auditSink, audit.VerbCreate, audit.ResourceDatacenter),
 		)(dc.CreateEndpoint(r.seedsGetter, r.userInfoGetter, r.seedsClientGetter)),
 		dc.DecodeCreateDCReq,
 		setStatusCreatedHeader(encodeJSON),
@@ -1452,7 +1536,9 @@ func (r Routing) updateDC() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
+			middleware.Audit(r.auditSink, audit.VerbUpdate, audit.ResourceDatacenter),
 		)(dc.UpdateEndpoint(r.seedsGetter, r.userInfoGetter, r.seedsClientGetter)),
 		dc.DecodeUpdateDCReq,
 		encodeJSON,
@@ -1479,7 +1565,9 @@ func (r Routing) patchDC() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
+			middleware.Audit(r.auditSink, audit.VerbPatch, audit.ResourceDatacenter),
 		)(dc.PatchEndpoint(r.seedsGetter, r.userInfoGetter, r.seedsClientGetter)),
 		dc.DecodePatchDCReq,
 		encodeJSON,
@@ -1503,7 +1591,9 @@ func (r Routing) deleteDC() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
+			middleware.Audit(r.auditSink, audit.VerbDelete, audit.ResourceDatacenter),
 		)(dc.DeleteEndpoint(r.seedsGetter, r.userInfoGetter, r.seedsClientGetter)),
 		dc.DecodeDeleteDCReq,
 		encodeJSON,
@@ -1526,7 +1616,8 @@ func (r Routing) getMasterVersions() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 		)(cluster.GetMasterVersionsEndpoint(r.updateManager)),
 		cluster.DecodeClusterTypeReq,
 		encodeJSON,
@@ -1548,7 +1639,8 @@ func (r Routing) getKubermaticVersion() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 		)(v1.GetKubermaticVersion()),
 		decodeEmptyReq,
 		encodeJSON,
@@ -1572,7 +1664,8 @@ func (r Routing) listProjects() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 		)(project.ListEndpoint(r.userInfoGetter, r.projectProvider, r.privilegedProjectProvider, r.userProjectMapper, r.projectMemberProvider, r.userProvider, r.clusterProviderGetter, r.seedsGetter)),
 		project.DecodeList,
 		encodeJSON,
@@ -1596,7 +1689,8 @@ func (r Routing) getProject() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 		)(project.GetEndpoint(r.projectProvider, r.privilegedProjectProvider, r.projectMemberProvider, r.userProvider, r.userInfoGetter, r.clusterProviderGetter, r.seedsGetter)),
 		common.DecodeGetProject,
 		encodeJSON,
@@ -1625,7 +1719,9 @@ func (r Routing) createProject() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
+			middleware.Audit(r.auditSink, audit.VerbCreate, audit.ResourceProject),
 		)(project.CreateEndpoint(r.projectProvider)),
 		project.DecodeCreate,
 		setStatusCreatedHeader(encodeJSON),
@@ -1651,7 +1747,9 @@ func (r Routing) updateProject() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
+			middleware.Audit(r.auditSink, audit.VerbUpdate, audit.ResourceProject),
 		)(project.UpdateEndpoint(r.projectProvider, r.privilegedProjectProvider, r.projectMemberProvider, r.userProvider, r.userInfoGetter, r.clusterProviderGetter, r.seedsGetter)),
 		project.DecodeUpdateRq,
 		encodeJSON,
@@ -1676,7 +1774,9 @@ func (r Routing) deleteProject() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
+			middleware.Audit(r.auditSink, audit.VerbDelete, audit.ResourceProject),
 		)(project.DeleteEndpoint(r.projectProvider, r.privilegedProjectProvider, r.userInfoGetter)),
 		project.DecodeDelete,
 		encodeJSON,
@@ -1703,16 +1803,25 @@ func (r Routing) createCluster(initNodeDeploymentFailures *prometheus.CounterVec
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
-			middleware.SetClusterProvider(r.clusterProviderGetter, r.seedsGetter),
-			middleware.SetPrivilegedClusterProvider(r.clusterProviderGetter, r.seedsGetter),
-		)(cluster.CreateEndpoint(r.sshKeyProvider, r.projectProvider, r.privilegedProjectProvider, r.seedsGetter, initNodeDeploymentFailures, r.eventRecorderProvider, r.presetsProvider, r.exposeStrategy, r.userInfoGetter, r.settingsProvider, r.updateManager)),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
+			middleware.Audit(r.auditSink, audit.VerbCreate, audit.ResourceCluster),
+		)(r.createClusterEndpoint(initNodeDeploymentFailures)),
 		cluster.DecodeCreateReq,
 		setStatusCreatedHeader(encodeJSON),
 		r.defaultServerOptions()...,
 	)
 }
 
+// createClusterEndpoint returns the endpoint which creates a cluster in the seed of the request,
+// it is shared by createCluster and createClusterTemplateInstances
+func (r Routing) createClusterEndpoint(initNodeDeploymentFailures *prometheus.CounterVec) endpoint.Endpoint {
+	return endpoint.Chain(
+		middleware.SetClusterProvider(r.clusterProviderGetter, r.seedsGetter),
+		middleware.SetPrivilegedClusterProvider(r.clusterProviderGetter, r.seedsGetter),
+	)(cluster.CreateEndpoint(r.sshKeyProvider, r.projectProvider, r.privilegedProjectProvider, r.seedsGetter, initNodeDeploymentFailures, r.eventRecorderProvider, r.presetsProvider, r.exposeStrategy, r.userInfoGetter, r.settingsProvider, r.updateManager, r.clusterProviderGetter, provider.GetNodeSize))
+}
+
 // swagger:route GET /api/v1/projects/{project_id}/dc/{dc}/clusters project listClusters
 //
 //     Lists clusters for the specified project and data center.
@@ -1729,7 +1838,8 @@ func (r Routing) listClusters() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupClusters),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 			middleware.SetClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 		)(cluster.ListEndpoint(r.projectProvider, r.privilegedProjectProvider, r.userInfoGetter)),
 		cluster.DecodeListReq,
@@ -1740,7 +1850,8 @@ func (r Routing) listClusters() http.Handler {
 
 // swagger:route GET /api/v1/projects/{project_id}/clusters project listClustersForProject
 //
-//     Lists clusters for the specified project.
+//     Lists clusters for the specified project in all seeds. Every seed whose clusters
+//     couldn't be listed is reported in a Seed-Error header as "<seed>: <message>".
 //
 //     Produces:
 //     - application/json
@@ -1754,10 +1865,11 @@ func (r Routing) listClustersForProject() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
-		)(cluster.ListAllEndpoint(r.projectProvider, r.privilegedProjectProvider, r.seedsGetter, r.clusterProviderGetter, r.userInfoGetter)),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupClusters),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
+		)(cluster.ListAllEndpoint(r.projectProvider, r.privilegedProjectProvider, r.projectClusterLister, r.userInfoGetter)),
 		common.DecodeGetProject,
-		encodeJSON,
+		encodeProjectClusterList,
 		r.defaultServerOptions()...,
 	)
 }
@@ -1778,7 +1890,8 @@ func (r Routing) getCluster() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 			middleware.SetClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 			middleware.SetPrivilegedClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 		)(cluster.GetEndpoint(r.projectProvider, r.privilegedProjectProvider, r.userInfoGetter)),
@@ -1804,9 +1917,11 @@ func (r Routing) patchCluster() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 			middleware.SetClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 			middleware.SetPrivilegedClusterProvider(r.clusterProviderGetter, r.seedsGetter),
+			middleware.Audit(r.auditSink, audit.VerbPatch, audit.ResourceCluster),
 		)(cluster.PatchEndpoint(r.projectProvider, r.privilegedProjectProvider, r.seedsGetter, r.userInfoGetter)),
 		cluster.DecodePatchReq,
 		encodeJSON,
@@ -1831,7 +1946,8 @@ func (r Routing) getClusterEvents() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 			middleware.SetClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 			middleware.SetPrivilegedClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 		)(cluster.GetClusterEventsEndpoint(r.projectProvider, r.privilegedProjectProvider, r.userInfoGetter)),
@@ -1858,7 +1974,8 @@ func (r Routing) getClusterKubeconfig() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 			middleware.SetClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 			middleware.SetPrivilegedClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 		)(cluster.GetAdminKubeconfigEndpoint(r.projectProvider, r.privilegedProjectProvider, r.userInfoGetter)),
@@ -1885,7 +2002,8 @@ func (r Routing) getOidcClusterKubeconfig() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 			middleware.SetClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 			middleware.SetPrivilegedClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 		)(cluster.GetOidcKubeconfigEndpoint(r.projectProvider, r.privilegedProjectProvider, r.userInfoGetter)),
@@ -1912,10 +2030,12 @@ func (r Routing) deleteCluster() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 			middleware.SetClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 			middleware.SetPrivilegedClusterProvider(r.clusterProviderGetter, r.seedsGetter),
-		)(cluster.DeleteEndpoint(r.sshKeyProvider, r.privilegedSSHKeyProvider, r.projectProvider, r.privilegedProjectProvider, r.userInfoGetter)),
+			middleware.Audit(r.auditSink, audit.VerbDelete, audit.ResourceCluster),
+		)(cluster.DeleteEndpoint(r.sshKeyProvider, r.privilegedSSHKeyProvider, r.projectProvider, r.privilegedProjectProvider, r.userInfoGetter, r.seedsGetter, r.clusterProviderGetter, provider.GetNodeSize)),
 		cluster.DecodeDeleteReq,
 		encodeJSON,
 		r.defaultServerOptions()...,
@@ -1938,7 +2058,8 @@ func (r Routing) getClusterHealth() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 			middleware.SetClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 			middleware.SetPrivilegedClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 		)(cluster.HealthEndpoint(r.projectProvider, r.privilegedProjectProvider, r.userInfoGetter)),
@@ -1948,6 +2069,142 @@ func (r Routing) getClusterHealth() http.Handler {
 	)
 }
 
+// swagger:route POST /api/v1/projects/{project_id}/dc/{dc}/clusters/{cluster_id}/clustertemplates project createClusterTemplate
+//
+//     Creates a cluster template from the given cluster, the template does not contain any cloud credentials.
+//
+//     Consumes:
+//     - application/json
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       default: errorResponse
+//       201: ClusterTemplate
+//       401: empty
+//       403: empty
+func (r Routing) createClusterTemplate() http.Handler {
+	return httptransport.NewServer(
+		endpoint.Chain(
+			middleware.TokenVerifier(r.tokenVerifiers),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
+			middleware.SetClusterProvider(r.clusterProviderGetter, r.seedsGetter),
+			middleware.SetPrivilegedClusterProvider(r.clusterProviderGetter, r.seedsGetter),
+			middleware.Audit(r.auditSink, audit.VerbCreate, audit.ResourceClusterTemplate),
+		)(clustertemplate.CreateEndpoint(r.projectProvider, r.privilegedProjectProvider, r.userInfoGetter, r.presetsProvider, r.clusterTemplateProvider)),
+		clustertemplate.DecodeCreateReq,
+		setStatusCreatedHeader(encodeJSON),
+		r.defaultServerOptions()...,
+	)
+}
+
+// swagger:route GET /api/v1/projects/{project_id}/clustertemplates project listClusterTemplates
+//
+//     Lists the cluster templates which can be used in the project.
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       default: errorResponse
+//       200: ClusterTemplateList
+//       401: empty
+//       403: empty
+func (r Routing) listClusterTemplates() http.Handler {
+	return httptransport.NewServer(
+		endpoint.Chain(
+			middleware.TokenVerifier(r.tokenVerifiers),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
+		)(clustertemplate.ListEndpoint(r.projectProvider, r.privilegedProjectProvider, r.userInfoGetter, r.clusterTemplateProvider)),
+		common.DecodeGetProject,
+		encodeJSON,
+		r.defaultServerOptions()...,
+	)
+}
+
+// swagger:route GET /api/v1/projects/{project_id}/clustertemplates/{template_id} project getClusterTemplate
+//
+//     Gets the cluster template.
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       default: errorResponse
+//       200: ClusterTemplate
+//       401: empty
+//       403: empty
+func (r Routing) getClusterTemplate() http.Handler {
+	return httptransport.NewServer(
+		endpoint.Chain(
+			middleware.TokenVerifier(r.tokenVerifiers),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
+		)(clustertemplate.GetEndpoint(r.projectProvider, r.privilegedProjectProvider, r.userInfoGetter, r.clusterTemplateProvider)),
+		clustertemplate.DecodeGetReq,
+		encodeJSON,
+		r.defaultServerOptions()...,
+	)
+}
+
+// swagger:route DELETE /api/v1/projects/{project_id}/clustertemplates/{template_id} project deleteClusterTemplate
+//
+//     Deletes the cluster template.
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       default: errorResponse
+//       200: empty
+//       401: empty
+//       403: empty
+func (r Routing) deleteClusterTemplate() http.Handler {
+	return httptransport.NewServer(
+		endpoint.Chain(
+			middleware.TokenVerifier(r.tokenVerifiers),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
+			middleware.Audit(r.auditSink, audit.VerbDelete, audit.ResourceClusterTemplate),
+		)(clustertemplate.DeleteEndpoint(r.projectProvider, r.privilegedProjectProvider, r.userInfoGetter, r.clusterTemplateProvider)),
+		clustertemplate.DecodeGetReq,
+		encodeJSON,
+		r.defaultServerOptions()...,
+	)
+}
+
+// swagger:route POST /api/v1/projects/{project_id}/clustertemplates/{template_id}/instances project createClusterTemplateInstances
+//
+//     Creates clusters from the cluster template, the cloud credentials are taken from the preset of the template.
+//
+//     Consumes:
+//     - application/json
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       default: errorResponse
+//       201: ClusterList
+//       401: empty
+//       403: empty
+func (r Routing) createClusterTemplateInstances(initNodeDeploymentFailures *prometheus.CounterVec) http.Handler {
+	return httptransport.NewServer(
+		endpoint.Chain(
+			middleware.TokenVerifier(r.tokenVerifiers),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
+			middleware.Audit(r.auditSink, audit.VerbCreate, audit.ResourceCluster),
+		)(clustertemplate.CreateInstancesEndpoint(r.projectProvider, r.privilegedProjectProvider, r.userInfoGetter, r.seedsGetter, r.clusterProviderGetter, provider.GetNodeSize, r.clusterTemplateProvider, r.createClusterEndpoint(initNodeDeploymentFailures))),
+		clustertemplate.DecodeCreateInstancesReq,
+		setStatusCreatedHeader(encodeJSON),
+		r.defaultServerOptions()...,
+	)
+}
+
 // swagger:route PUT /api/v1/projects/{project_id}/dc/{dc}/clusters/{cluster_id}/sshkeys/{key_id} project assignSSHKeyToCluster
 //
 //     Assigns an existing ssh key to the given cluster
@@ -1967,9 +2224,11 @@ func (r Routing) assignSSHKeyToCluster() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 			middleware.SetClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 			middleware.SetPrivilegedClusterProvider(r.clusterProviderGetter, r.seedsGetter),
+			middleware.Audit(r.auditSink, audit.VerbCreate, audit.ResourceClusterSSHKey),
 		)(cluster.AssignSSHKeyEndpoint(r.sshKeyProvider, r.privilegedSSHKeyProvider, r.projectProvider, r.privilegedProjectProvider, r.userInfoGetter)),
 		cluster.DecodeAssignSSHKeyReq,
 		setStatusCreatedHeader(encodeJSON),
@@ -1997,7 +2256,8 @@ func (r Routing) listSSHKeysAssignedToCluster() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 			middleware.SetClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 			middleware.SetPrivilegedClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 		)(cluster.ListSSHKeysEndpoint(r.sshKeyProvider, r.projectProvider, r.privilegedProjectProvider, r.userInfoGetter)),
@@ -2026,9 +2286,11 @@ func (r Routing) detachSSHKeyFromCluster() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 			middleware.SetClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 			middleware.SetPrivilegedClusterProvider(r.clusterProviderGetter, r.seedsGetter),
+			middleware.Audit(r.auditSink, audit.VerbDelete, audit.ResourceClusterSSHKey),
 		)(cluster.DetachSSHKeyEndpoint(r.sshKeyProvider, r.privilegedSSHKeyProvider, r.projectProvider, r.privilegedProjectProvider, r.userInfoGetter)),
 		cluster.DecodeDetachSSHKeysReq,
 		encodeJSON,
@@ -2052,9 +2314,11 @@ func (r Routing) revokeClusterAdminToken() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 			middleware.SetClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 			middleware.SetPrivilegedClusterProvider(r.clusterProviderGetter, r.seedsGetter),
+			middleware.Audit(r.auditSink, audit.VerbUpdate, audit.ResourceClusterToken),
 		)(cluster.RevokeAdminTokenEndpoint(r.projectProvider, r.privilegedProjectProvider, r.userInfoGetter)),
 		cluster.DecodeAdminTokenReq,
 		encodeJSON,
@@ -2078,9 +2342,11 @@ func (r Routing) revokeClusterViewerToken() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 			middleware.SetClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 			middleware.SetPrivilegedClusterProvider(r.clusterProviderGetter, r.seedsGetter),
+			middleware.Audit(r.auditSink, audit.VerbUpdate, audit.ResourceClusterToken),
 		)(cluster.RevokeViewerTokenEndpoint(r.projectProvider, r.privilegedProjectProvider, r.userInfoGetter)),
 		cluster.DecodeAdminTokenReq,
 		encodeJSON,
@@ -2104,7 +2370,8 @@ func (r Routing) getClusterUpgrades() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 			middleware.SetClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 			middleware.SetPrivilegedClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 		)(cluster.GetUpgradesEndpoint(r.updateManager, r.projectProvider, r.privilegedProjectProvider, r.userInfoGetter)),
@@ -2130,7 +2397,8 @@ func (r Routing) getNodeUpgrades() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 		)(cluster.GetNodeUpgrades(r.updateManager)),
 		cluster.DecodeNodeUpgradesReq,
 		encodeJSON,
@@ -2154,9 +2422,11 @@ func (r Routing) upgradeClusterNodeDeployments() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 			middleware.SetClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 			middleware.SetPrivilegedClusterProvider(r.clusterProviderGetter, r.seedsGetter),
+			middleware.Audit(r.auditSink, audit.VerbUpdate, audit.ResourceNodeDeployment),
 		)(cluster.UpgradeNodeDeploymentsEndpoint(r.projectProvider, r.privilegedProjectProvider, r.userInfoGetter)),
 		cluster.DecodeUpgradeNodeDeploymentsReq,
 		encodeJSON,
@@ -2183,7 +2453,9 @@ func (r Routing) addUserToProject() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
+			middleware.Audit(r.auditSink, audit.VerbCreate, audit.ResourceMember),
 		)(user.AddEndpoint(r.projectProvider, r.privilegedProjectProvider, r.userProvider, r.projectMemberProvider, r.privilegedProjectMemberProvider, r.userInfoGetter)),
 		user.DecodeAddReq,
 		setStatusCreatedHeader(encodeJSON),
@@ -2210,7 +2482,8 @@ func (r Routing) getUsersForProject() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 		)(user.ListEndpoint(r.projectProvider, r.privilegedProjectProvider, r.userProvider, r.projectMemberProvider, r.userInfoGetter)),
 		common.DecodeGetProject,
 		encodeJSON,
@@ -2237,7 +2510,9 @@ func (r Routing) editUserInProject() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
+			middleware.Audit(r.auditSink, audit.VerbUpdate, audit.ResourceMember),
 		)(user.EditEndpoint(r.projectProvider, r.privilegedProjectProvider, r.userProvider, r.projectMemberProvider, r.privilegedProjectMemberProvider, r.userInfoGetter)),
 		user.DecodeEditReq,
 		encodeJSON,
@@ -2264,7 +2539,9 @@ func (r Routing) deleteUserFromProject() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
+			middleware.Audit(r.auditSink, audit.VerbDelete, audit.ResourceMember),
 		)(user.DeleteEndpoint(r.projectProvider, r.privilegedProjectProvider, r.userProvider, r.projectMemberProvider, r.privilegedProjectMemberProvider, r.userInfoGetter)),
 		user.DecodeDeleteReq,
 		encodeJSON,
@@ -2287,8 +2564,9 @@ func (r Routing) getCurrentUser() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
-		)(user.GetEndpoint(r.userProjectMapper)),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
+		)(user.GetEndpoint(r.userProjectMapper, r.groupProjectBindingProvider)),
 		decodeEmptyReq,
 		encodeJSON,
 		r.defaultServerOptions()...,
@@ -2310,7 +2588,8 @@ func (r Routing) getCurrentUserSettings() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 		)(user.GetSettingsEndpoint(r.userProjectMapper)),
 		decodeEmptyReq,
 		encodeJSON,
@@ -2336,7 +2615,9 @@ func (r Routing) patchCurrentUserSettings() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
+			middleware.Audit(r.auditSink, audit.VerbPatch, audit.ResourceUserSettings),
 		)(user.PatchSettingsEndpoint(r.userProvider)),
 		user.DecodePatchSettingsReq,
 		encodeJSON,
@@ -2363,7 +2644,9 @@ func (r Routing) addServiceAccountToProject() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
+			middleware.Audit(r.auditSink, audit.VerbCreate, audit.ResourceServiceAccount),
 		)(serviceaccount.CreateEndpoint(r.projectProvider, r.privilegedProjectProvider, r.serviceAccountProvider, r.privilegedServiceAccountProvider, r.userInfoGetter)),
 		serviceaccount.DecodeAddReq,
 		setStatusCreatedHeader(encodeJSON),
@@ -2387,7 +2670,8 @@ func (r Routing) listServiceAccounts() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 		)(serviceaccount.ListEndpoint(r.projectProvider, r.privilegedProjectProvider, r.serviceAccountProvider, r.privilegedServiceAccountProvider, r.userProjectMapper, r.userInfoGetter)),
 		common.DecodeGetProject,
 		encodeJSON,
@@ -2414,7 +2698,9 @@ func (r Routing) updateServiceAccount() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
+			middleware.Audit(r.auditSink, audit.VerbUpdate, audit.ResourceServiceAccount),
 		)(serviceaccount.UpdateEndpoint(r.projectProvider, r.privilegedProjectProvider, r.serviceAccountProvider, r.privilegedServiceAccountProvider, r.userProjectMapper, r.userInfoGetter)),
 		serviceaccount.DecodeUpdateReq,
 		encodeJSON,
@@ -2439,7 +2725,9 @@ func (r Routing) deleteServiceAccount() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
+			middleware.Audit(r.auditSink, audit.VerbDelete, audit.ResourceServiceAccount),
 		)(serviceaccount.DeleteEndpoint(r.serviceAccountProvider, r.privilegedServiceAccountProvider, r.projectProvider, r.privilegedProjectProvider, r.userInfoGetter)),
 		serviceaccount.DecodeDeleteReq,
 		encodeJSON,
@@ -2466,7 +2754,9 @@ func (r Routing) addTokenToServiceAccount() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
+			middleware.Audit(r.auditSink, audit.VerbCreate, audit.ResourceServiceAccountToken),
 		)(serviceaccount.CreateTokenEndpoint(r.projectProvider, r.privilegedProjectProvider, r.serviceAccountProvider, r.privilegedServiceAccountProvider, r.serviceAccountTokenProvider, r.privilegedServiceAccountTokenProvider, r.saTokenAuthenticator, r.saTokenGenerator, r.userInfoGetter)),
 		serviceaccount.DecodeAddTokenReq,
 		setStatusCreatedHeader(encodeJSON),
@@ -2490,7 +2780,8 @@ func (r Routing) listServiceAccountTokens() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 		)(serviceaccount.ListTokenEndpoint(r.projectProvider, r.privilegedProjectProvider, r.serviceAccountProvider, r.privilegedServiceAccountProvider, r.serviceAccountTokenProvider, r.privilegedServiceAccountTokenProvider, r.saTokenAuthenticator, r.userInfoGetter)),
 		serviceaccount.DecodeTokenReq,
 		encodeJSON,
@@ -2517,7 +2808,9 @@ func (r Routing) updateServiceAccountToken() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
+			middleware.Audit(r.auditSink, audit.VerbUpdate, audit.ResourceServiceAccountToken),
 		)(serviceaccount.UpdateTokenEndpoint(r.projectProvider, r.privilegedProjectProvider, r.serviceAccountProvider, r.privilegedServiceAccountProvider, r.serviceAccountTokenProvider, r.privilegedServiceAccountTokenProvider, r.saTokenAuthenticator, r.saTokenGenerator, r.userInfoGetter)),
 		serviceaccount.DecodeUpdateTokenReq,
 		encodeJSON,
@@ -2544,7 +2837,9 @@ func (r Routing) patchServiceAccountToken() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
+			middleware.Audit(r.auditSink, audit.VerbPatch, audit.ResourceServiceAccountToken),
 		)(serviceaccount.PatchTokenEndpoint(r.projectProvider, r.privilegedProjectProvider, r.serviceAccountProvider, r.privilegedServiceAccountProvider, r.serviceAccountTokenProvider, r.privilegedServiceAccountTokenProvider, r.saTokenAuthenticator, r.saTokenGenerator, r.userInfoGetter)),
 		serviceaccount.DecodePatchTokenReq,
 		encodeJSON,
@@ -2568,7 +2863,9 @@ func (r Routing) deleteServiceAccountToken() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
+			middleware.Audit(r.auditSink, audit.VerbDelete, audit.ResourceServiceAccountToken),
 		)(serviceaccount.DeleteTokenEndpoint(r.projectProvider, r.privilegedProjectProvider, r.serviceAccountProvider, r.privilegedServiceAccountProvider, r.serviceAccountTokenProvider, r.privilegedServiceAccountTokenProvider, r.userInfoGetter)),
 		serviceaccount.DecodeDeleteTokenReq,
 		encodeJSON,
@@ -2576,6 +2873,32 @@ func (r Routing) deleteServiceAccountToken() http.Handler {
 	)
 }
 
+// swagger:route POST /api/v1/projects/{project_id}/serviceaccounts/{serviceaccount_id}/tokens/{token_id}/revoke tokens revokeServiceAccountToken
+//
+//     Puts the token on the revocation list, a revoked token can no longer be used or regenerated
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       default: errorResponse
+//       200: PublicServiceAccountToken
+//       401: empty
+//       403: empty
+func (r Routing) revokeServiceAccountToken() http.Handler {
+	return httptransport.NewServer(
+		endpoint.Chain(
+			middleware.TokenVerifier(r.tokenVerifiers),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
+			middleware.Audit(r.auditSink, audit.VerbUpdate, audit.ResourceServiceAccountToken),
+		)(serviceaccount.RevokeTokenEndpoint(r.projectProvider, r.privilegedProjectProvider, r.serviceAccountProvider, r.privilegedServiceAccountProvider, r.serviceAccountTokenProvider, r.privilegedServiceAccountTokenProvider, r.saTokenAuthenticator, r.userInfoGetter)),
+		serviceaccount.DecodeDeleteTokenReq,
+		encodeJSON,
+		r.defaultServerOptions()...,
+	)
+}
+
 // swagger:route GET /api/v1/projects/{project_id}/dc/{dc}/clusters/{cluster_id}/providers/aws/sizes aws listAWSSizesNoCredentials
 //
 // Lists available AWS sizes
@@ -2590,7 +2913,8 @@ func (r Routing) listAWSSizesNoCredentials() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupProviders),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 			middleware.SetClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 			middleware.SetPrivilegedClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 		)(provider.AWSSizeNoCredentialsEndpoint(r.projectProvider, r.privilegedProjectProvider, r.seedsGetter, r.userInfoGetter)),
@@ -2614,7 +2938,8 @@ func (r Routing) listAWSSubnetsNoCredentials() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupProviders),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 			middleware.SetClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 			middleware.SetPrivilegedClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 		)(provider.AWSSubnetWithClusterCredentialsEndpoint(r.projectProvider, r.privilegedProjectProvider, r.seedsGetter, r.userInfoGetter)),
@@ -2638,7 +2963,8 @@ func (r Routing) listGCPSizesNoCredentials() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupProviders),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 			middleware.SetClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 			middleware.SetPrivilegedClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 		)(provider.GCPSizeWithClusterCredentialsEndpoint(r.projectProvider, r.privilegedProjectProvider, r.userInfoGetter)),
@@ -2662,7 +2988,8 @@ func (r Routing) listGCPDiskTypesNoCredentials() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupProviders),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 			middleware.SetClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 			middleware.SetPrivilegedClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 		)(provider.GCPDiskTypesWithClusterCredentialsEndpoint(r.projectProvider, r.privilegedProjectProvider, r.userInfoGetter)),
@@ -2686,7 +3013,8 @@ func (r Routing) listGCPZonesNoCredentials() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupProviders),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 			middleware.SetClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 			middleware.SetPrivilegedClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 		)(provider.GCPZoneWithClusterCredentialsEndpoint(r.projectProvider, r.privilegedProjectProvider, r.seedsGetter, r.userInfoGetter)),
@@ -2710,7 +3038,8 @@ func (r Routing) listGCPNetworksNoCredentials() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupProviders),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 			middleware.SetClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 			middleware.SetPrivilegedClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 		)(provider.GCPNetworkWithClusterCredentialsEndpoint(r.projectProvider, r.privilegedProjectProvider, r.userInfoGetter)),
@@ -2734,7 +3063,8 @@ func (r Routing) listGCPSubnetworksNoCredentials() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupProviders),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 			middleware.SetClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 			middleware.SetPrivilegedClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 		)(provider.GCPSubnetworkWithClusterCredentialsEndpoint(r.projectProvider, r.privilegedProjectProvider, r.seedsGetter, r.userInfoGetter)),
@@ -2758,7 +3088,8 @@ func (r Routing) listHetznerSizesNoCredentials() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupProviders),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 			middleware.SetClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 			middleware.SetPrivilegedClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 		)(provider.HetznerSizeWithClusterCredentialsEndpoint(r.projectProvider, r.privilegedProjectProvider, r.userInfoGetter)),
@@ -2782,7 +3113,8 @@ func (r Routing) listDigitaloceanSizesNoCredentials() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupProviders),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 			middleware.SetClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 			middleware.SetPrivilegedClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 		)(provider.DigitaloceanSizeWithClusterCredentialsEndpoint(r.projectProvider, r.privilegedProjectProvider, r.userInfoGetter)),
@@ -2806,7 +3138,8 @@ func (r Routing) listAzureSizesNoCredentials() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupProviders),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 			middleware.SetClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 			middleware.SetPrivilegedClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 		)(provider.AzureSizeWithClusterCredentialsEndpoint(r.projectProvider, r.privilegedProjectProvider, r.seedsGetter, r.userInfoGetter)),
@@ -2830,7 +3163,8 @@ func (r Routing) listAzureAvailabilityZonesNoCredentials() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupProviders),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 			middleware.SetClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 			middleware.SetPrivilegedClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 		)(provider.AzureAvailabilityZonesWithClusterCredentialsEndpoint(r.projectProvider, r.privilegedProjectProvider, r.seedsGetter, r.userInfoGetter)),
@@ -2854,7 +3188,8 @@ func (r Routing) listOpenstackSizesNoCredentials() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupProviders),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 			middleware.SetClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 			middleware.SetPrivilegedClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 		)(provider.OpenstackSizeWithClusterCredentialsEndpoint(r.projectProvider, r.privilegedProjectProvider, r.seedsGetter, r.userInfoGetter)),
@@ -2878,7 +3213,8 @@ func (r Routing) listOpenstackTenantsNoCredentials() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupProviders),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 			middleware.SetClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 			middleware.SetPrivilegedClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 		)(provider.OpenstackTenantWithClusterCredentialsEndpoint(r.projectProvider, r.privilegedProjectProvider, r.seedsGetter, r.userInfoGetter)),
@@ -2902,7 +3238,8 @@ func (r Routing) listOpenstackNetworksNoCredentials() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupProviders),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 			middleware.SetClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 			middleware.SetPrivilegedClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 		)(provider.OpenstackNetworkWithClusterCredentialsEndpoint(r.projectProvider, r.privilegedProjectProvider, r.seedsGetter, r.userInfoGetter)),
@@ -2926,7 +3263,8 @@ func (r Routing) listOpenstackSecurityGroupsNoCredentials() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupProviders),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 			middleware.SetClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 			middleware.SetPrivilegedClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 		)(provider.OpenstackSecurityGroupWithClusterCredentialsEndpoint(r.projectProvider, r.privilegedProjectProvider, r.seedsGetter, r.userInfoGetter)),
@@ -2950,7 +3288,8 @@ func (r Routing) listOpenstackSubnetsNoCredentials() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupProviders),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 			middleware.SetClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 			middleware.SetPrivilegedClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 		)(provider.OpenstackSubnetsWithClusterCredentialsEndpoint(r.projectProvider, r.privilegedProjectProvider, r.seedsGetter, r.userInfoGetter)),
@@ -2974,7 +3313,8 @@ func (r Routing) listVSphereNetworksNoCredentials() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupProviders),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 			middleware.SetClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 			middleware.SetPrivilegedClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 		)(provider.VsphereNetworksWithClusterCredentialsEndpoint(r.projectProvider, r.privilegedProjectProvider, r.seedsGetter, r.userInfoGetter)),
@@ -2998,7 +3338,8 @@ func (r Routing) listVSphereFoldersNoCredentials() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupProviders),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 			middleware.SetClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 			middleware.SetPrivilegedClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 		)(provider.VsphereFoldersWithClusterCredentialsEndpoint(r.projectProvider, r.privilegedProjectProvider, r.seedsGetter, r.userInfoGetter)),
@@ -3022,7 +3363,8 @@ func (r Routing) listAlibabaInstanceTypesNoCredentials() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupProviders),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 			middleware.SetClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 			middleware.SetPrivilegedClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 		)(provider.AlibabaInstanceTypesWithClusterCredentialsEndpoint(r.projectProvider, r.privilegedProjectProvider, r.seedsGetter, r.userInfoGetter)),
@@ -3046,7 +3388,8 @@ func (r Routing) listAlibabaZonesNoCredentials() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupProviders),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 			middleware.SetClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 			middleware.SetPrivilegedClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 		)(provider.AlibabaZonesWithClusterCredentialsEndpoint(r.projectProvider, r.privilegedProjectProvider, r.seedsGetter, r.userInfoGetter)),
@@ -3075,10 +3418,12 @@ func (r Routing) createNodeDeployment() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 			middleware.SetClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 			middleware.SetPrivilegedClusterProvider(r.clusterProviderGetter, r.seedsGetter),
-		)(node.CreateNodeDeployment(r.sshKeyProvider, r.projectProvider, r.privilegedProjectProvider, r.seedsGetter, r.userInfoGetter)),
+			middleware.Audit(r.auditSink, audit.VerbCreate, audit.ResourceNodeDeployment),
+		)(node.CreateNodeDeployment(r.sshKeyProvider, r.projectProvider, r.privilegedProjectProvider, r.seedsGetter, r.userInfoGetter, r.clusterProviderGetter, provider.GetNodeSize)),
 		node.DecodeCreateNodeDeployment,
 		setStatusCreatedHeader(encodeJSON),
 		r.defaultServerOptions()...,
@@ -3101,7 +3446,8 @@ func (r Routing) listNodeDeployments() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 			middleware.SetClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 			middleware.SetPrivilegedClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 		)(node.ListNodeDeployments(r.projectProvider, r.privilegedProjectProvider, r.userInfoGetter)),
@@ -3127,7 +3473,8 @@ func (r Routing) getNodeDeployment() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 			middleware.SetClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 			middleware.SetPrivilegedClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 		)(node.GetNodeDeployment(r.projectProvider, r.privilegedProjectProvider, r.userInfoGetter)),
@@ -3153,7 +3500,8 @@ func (r Routing) listNodeDeploymentNodes() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 			middleware.SetClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 			middleware.SetPrivilegedClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 		)(node.ListNodeDeploymentNodes(r.projectProvider, r.privilegedProjectProvider, r.userInfoGetter)),
@@ -3179,7 +3527,8 @@ func (r Routing) listNodeDeploymentMetrics() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 			middleware.SetClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 			middleware.SetPrivilegedClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 		)(node.ListNodeDeploymentMetrics(r.projectProvider, r.privilegedProjectProvider, r.userInfoGetter)),
@@ -3206,7 +3555,8 @@ func (r Routing) listNodeDeploymentNodesEvents() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 			middleware.SetClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 			middleware.SetPrivilegedClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 		)(node.ListNodeDeploymentNodesEvents(r.projectProvider, r.privilegedProjectProvider, r.userInfoGetter)),
@@ -3236,10 +3586,12 @@ func (r Routing) patchNodeDeployment() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 			middleware.SetClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 			middleware.SetPrivilegedClusterProvider(r.clusterProviderGetter, r.seedsGetter),
-		)(node.PatchNodeDeployment(r.sshKeyProvider, r.projectProvider, r.privilegedProjectProvider, r.seedsGetter, r.userInfoGetter)),
+			middleware.Audit(r.auditSink, audit.VerbPatch, audit.ResourceNodeDeployment),
+		)(node.PatchNodeDeployment(r.sshKeyProvider, r.projectProvider, r.privilegedProjectProvider, r.seedsGetter, r.userInfoGetter, r.clusterProviderGetter, provider.GetNodeSize)),
 		node.DecodePatchNodeDeployment,
 		encodeJSON,
 		r.defaultServerOptions()...,
@@ -3262,10 +3614,12 @@ func (r Routing) deleteNodeDeployment() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 			middleware.SetClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 			middleware.SetPrivilegedClusterProvider(r.clusterProviderGetter, r.seedsGetter),
-		)(node.DeleteNodeDeployment(r.projectProvider, r.privilegedProjectProvider, r.userInfoGetter)),
+			middleware.Audit(r.auditSink, audit.VerbDelete, audit.ResourceNodeDeployment),
+		)(node.DeleteNodeDeployment(r.projectProvider, r.privilegedProjectProvider, r.userInfoGetter, r.seedsGetter, r.clusterProviderGetter, provider.GetNodeSize)),
 		node.DecodeDeleteNodeDeployment,
 		encodeJSON,
 		r.defaultServerOptions()...,
@@ -3291,7 +3645,8 @@ func (r Routing) listAccessibleAddons() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 		)(addon.ListAccessibleAddons(r.accessibleAddons)),
 		decodeEmptyReq,
 		encodeJSON,
@@ -3315,7 +3670,8 @@ func (r Routing) listInstallableAddons() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 			middleware.SetClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 			middleware.SetPrivilegedClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 			middleware.Addons(r.addonProviderGetter, r.seedsGetter),
@@ -3346,11 +3702,13 @@ func (r Routing) createAddon() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 			middleware.SetClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 			middleware.SetPrivilegedClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 			middleware.Addons(r.addonProviderGetter, r.seedsGetter),
 			middleware.PrivilegedAddons(r.addonProviderGetter, r.seedsGetter),
+			middleware.Audit(r.auditSink, audit.VerbCreate, audit.ResourceAddon),
 		)(addon.CreateAddonEndpoint(r.projectProvider, r.privilegedProjectProvider, r.userInfoGetter)),
 		addon.DecodeCreateAddon,
 		setStatusCreatedHeader(encodeJSON),
@@ -3374,7 +3732,8 @@ func (r Routing) listAddons() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 			middleware.SetClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 			middleware.SetPrivilegedClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 			middleware.Addons(r.addonProviderGetter, r.seedsGetter),
@@ -3402,7 +3761,8 @@ func (r Routing) getAddon() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 			middleware.SetClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 			middleware.SetPrivilegedClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 			middleware.Addons(r.addonProviderGetter, r.seedsGetter),
@@ -3433,11 +3793,13 @@ func (r Routing) patchAddon() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 			middleware.SetClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 			middleware.SetPrivilegedClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 			middleware.Addons(r.addonProviderGetter, r.seedsGetter),
 			middleware.PrivilegedAddons(r.addonProviderGetter, r.seedsGetter),
+			middleware.Audit(r.auditSink, audit.VerbPatch, audit.ResourceAddon),
 		)(addon.PatchAddonEndpoint(r.projectProvider, r.privilegedProjectProvider, r.userInfoGetter)),
 		addon.DecodePatchAddon,
 		encodeJSON,
@@ -3461,11 +3823,13 @@ func (r Routing) deleteAddon() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 			middleware.SetClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 			middleware.SetPrivilegedClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 			middleware.Addons(r.addonProviderGetter, r.seedsGetter),
 			middleware.PrivilegedAddons(r.addonProviderGetter, r.seedsGetter),
+			middleware.Audit(r.auditSink, audit.VerbDelete, audit.ResourceAddon),
 		)(addon.DeleteAddonEndpoint(r.projectProvider, r.privilegedProjectProvider, r.userInfoGetter)),
 		addon.DecodeGetAddon,
 		encodeJSON,
@@ -3473,6 +3837,124 @@ func (r Routing) deleteAddon() http.Handler {
 	)
 }
 
+// swagger:route POST /api/v1/projects/{project_id}/dc/{dc}/clusters/{cluster_id}/restores project createEtcdRestore
+//
+//     Restores the etcd of the given cluster from a backup. The cluster is paused while the restore is running.
+//
+//     Consumes:
+//     - application/json
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       default: errorResponse
+//       201: EtcdRestore
+//       401: empty
+//       403: empty
+func (r Routing) createEtcdRestore() http.Handler {
+	return httptransport.NewServer(
+		endpoint.Chain(
+			middleware.TokenVerifier(r.tokenVerifiers),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
+			middleware.SetClusterProvider(r.clusterProviderGetter, r.seedsGetter),
+			middleware.SetPrivilegedClusterProvider(r.clusterProviderGetter, r.seedsGetter),
+			middleware.EtcdRestores(r.etcdRestoreProviderGetter, r.seedsGetter),
+			middleware.PrivilegedEtcdRestores(r.etcdRestoreProviderGetter, r.seedsGetter),
+			middleware.Audit(r.auditSink, audit.VerbCreate, audit.ResourceEtcdRestore),
+		)(etcdrestore.CreateEtcdRestoreEndpoint(r.projectProvider, r.privilegedProjectProvider, r.userInfoGetter)),
+		etcdrestore.DecodeCreateEtcdRestore,
+		setStatusCreatedHeader(encodeJSON),
+		r.defaultServerOptions()...,
+	)
+}
+
+// swagger:route GET /api/v1/projects/{project_id}/dc/{dc}/clusters/{cluster_id}/restores project listEtcdRestores
+//
+//     Lists etcd restores of the given cluster
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       default: errorResponse
+//       200: []EtcdRestore
+//       401: empty
+//       403: empty
+func (r Routing) listEtcdRestores() http.Handler {
+	return httptransport.NewServer(
+		endpoint.Chain(
+			middleware.TokenVerifier(r.tokenVerifiers),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
+			middleware.SetClusterProvider(r.clusterProviderGetter, r.seedsGetter),
+			middleware.SetPrivilegedClusterProvider(r.clusterProviderGetter, r.seedsGetter),
+			middleware.EtcdRestores(r.etcdRestoreProviderGetter, r.seedsGetter),
+			middleware.PrivilegedEtcdRestores(r.etcdRestoreProviderGetter, r.seedsGetter),
+		)(etcdrestore.ListEtcdRestoreEndpoint(r.projectProvider, r.privilegedProjectProvider, r.userInfoGetter)),
+		etcdrestore.DecodeListEtcdRestores,
+		encodeJSON,
+		r.defaultServerOptions()...,
+	)
+}
+
+// swagger:route GET /api/v1/projects/{project_id}/dc/{dc}/clusters/{cluster_id}/restores/{restore_id} project getEtcdRestore
+//
+//     Gets an etcd restore of the given cluster
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       default: errorResponse
+//       200: EtcdRestore
+//       401: empty
+//       403: empty
+func (r Routing) getEtcdRestore() http.Handler {
+	return httptransport.NewServer(
+		endpoint.Chain(
+			middleware.TokenVerifier(r.tokenVerifiers),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
+			middleware.SetClusterProvider(r.clusterProviderGetter, r.seedsGetter),
+			middleware.SetPrivilegedClusterProvider(r.clusterProviderGetter, r.seedsGetter),
+			middleware.EtcdRestores(r.etcdRestoreProviderGetter, r.seedsGetter),
+			middleware.PrivilegedEtcdRestores(r.etcdRestoreProviderGetter, r.seedsGetter),
+		)(etcdrestore.GetEtcdRestoreEndpoint(r.projectProvider, r.privilegedProjectProvider, r.userInfoGetter)),
+		etcdrestore.DecodeGetEtcdRestore,
+		encodeJSON,
+		r.defaultServerOptions()...,
+	)
+}
+
+// swagger:route GET /api/v1/projects/{project_id}/dc/{dc}/clusters/{cluster_id}/backups project listEtcdBackups
+//
+//     Lists the etcd snapshots of the given cluster in its backup destination
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       default: errorResponse
+//       200: []EtcdSnapshot
+//       401: empty
+//       403: empty
+func (r Routing) listEtcdBackups() http.Handler {
+	return httptransport.NewServer(
+		endpoint.Chain(
+			middleware.TokenVerifier(r.tokenVerifiers),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
+			middleware.SetClusterProvider(r.clusterProviderGetter, r.seedsGetter),
+			middleware.SetPrivilegedClusterProvider(r.clusterProviderGetter, r.seedsGetter),
+		)(etcdbackup.ListEtcdBackupsEndpoint(r.projectProvider, r.privilegedProjectProvider, r.seedsGetter, r.userInfoGetter)),
+		etcdbackup.DecodeListEtcdBackups,
+		encodeJSON,
+		r.defaultServerOptions()...,
+	)
+}
+
 // swagger:route GET /api/v1/projects/{project_id}/dc/{dc}/clusters/{cluster_id}/metrics project getClusterMetrics
 //
 //    Gets cluster metrics
@@ -3489,7 +3971,8 @@ func (r Routing) getClusterMetrics() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 			middleware.SetClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 			middleware.SetPrivilegedClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 		)(cluster.GetMetricsEndpoint(r.projectProvider, r.privilegedProjectProvider, r.userInfoGetter)),
@@ -3518,8 +4001,10 @@ func (r Routing) createClusterRole() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 			middleware.SetClusterProvider(r.clusterProviderGetter, r.seedsGetter),
+			middleware.Audit(r.auditSink, audit.VerbCreate, audit.ResourceClusterRole),
 		)(cluster.CreateClusterRoleEndpoint(r.userInfoGetter)),
 		cluster.DecodeCreateClusterRoleReq,
 		encodeJSON,
@@ -3551,7 +4036,8 @@ func (r Routing) openshiftConsoleLogin() http.Handler {
 		r.userInfoGetter,
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 			// TODO: Instead of using an admin client to talk to the seed, we should provide a seed
 			// client that allows access to the cluster namespace only
 			middleware.SetPrivilegedClusterProvider(r.clusterProviderGetter, r.seedsGetter),
@@ -3576,7 +4062,8 @@ func (r Routing) openshiftConsoleProxy() http.Handler {
 		r.userInfoGetter,
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 			// TODO: Instead of using an admin client to talk to the seed, we should provide a seed
 			// client that allows access to the cluster namespace only
 			middleware.SetPrivilegedClusterProvider(r.clusterProviderGetter, r.seedsGetter),
@@ -3602,7 +4089,8 @@ func (r Routing) kubernetesDashboardProxy() http.Handler {
 		r.settingsProvider,
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 			// TODO: Instead of using an admin client to talk to the seed, we should provide a seed
 			// client that allows access to the cluster namespace only
 			middleware.SetPrivilegedClusterProvider(r.clusterProviderGetter, r.seedsGetter),
@@ -3629,8 +4117,10 @@ func (r Routing) createRole() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 			middleware.SetClusterProvider(r.clusterProviderGetter, r.seedsGetter),
+			middleware.Audit(r.auditSink, audit.VerbCreate, audit.ResourceRole),
 		)(cluster.CreateRoleEndpoint(r.userInfoGetter)),
 		cluster.DecodeCreateRoleReq,
 		encodeJSON,
@@ -3654,7 +4144,8 @@ func (r Routing) listClusterRole() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 			middleware.SetClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 		)(cluster.ListClusterRoleEndpoint(r.userInfoGetter)),
 		cluster.DecodeListClusterRoleReq,
@@ -3679,7 +4170,8 @@ func (r Routing) listClusterRoleNames() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 			middleware.SetClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 		)(cluster.ListClusterRoleNamesEndpoint(r.userInfoGetter)),
 		cluster.DecodeListClusterRoleReq,
@@ -3704,7 +4196,8 @@ func (r Routing) listRole() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 			middleware.SetClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 		)(cluster.ListRoleEndpoint(r.userInfoGetter)),
 		cluster.DecodeListClusterRoleReq,
@@ -3729,7 +4222,8 @@ func (r Routing) listRoleNames() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 			middleware.SetClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 		)(cluster.ListRoleNamesEndpoint(r.userInfoGetter)),
 		cluster.DecodeListClusterRoleReq,
@@ -3754,7 +4248,8 @@ func (r Routing) getClusterRole() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 			middleware.SetClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 		)(cluster.GetClusterRoleEndpoint(r.userInfoGetter)),
 		cluster.DecodeGetClusterRoleReq,
@@ -3779,7 +4274,8 @@ func (r Routing) getRole() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 			middleware.SetClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 		)(cluster.GetRoleEndpoint(r.userInfoGetter)),
 		cluster.DecodeGetRoleReq,
@@ -3804,8 +4300,10 @@ func (r Routing) deleteClusterRole() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 			middleware.SetClusterProvider(r.clusterProviderGetter, r.seedsGetter),
+			middleware.Audit(r.auditSink, audit.VerbDelete, audit.ResourceClusterRole),
 		)(cluster.DeleteClusterRoleEndpoint(r.userInfoGetter)),
 		cluster.DecodeGetClusterRoleReq,
 		encodeJSON,
@@ -3829,8 +4327,10 @@ func (r Routing) deleteRole() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 			middleware.SetClusterProvider(r.clusterProviderGetter, r.seedsGetter),
+			middleware.Audit(r.auditSink, audit.VerbDelete, audit.ResourceRole),
 		)(cluster.DeleteRoleEndpoint(r.userInfoGetter)),
 		cluster.DecodeGetRoleReq,
 		encodeJSON,
@@ -3854,7 +4354,8 @@ func (r Routing) listNamespace() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 			middleware.SetClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 			middleware.SetPrivilegedClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 		)(cluster.ListNamespaceEndpoint(r.projectProvider, r.privilegedProjectProvider, r.userInfoGetter)),
@@ -3880,8 +4381,10 @@ func (r Routing) patchRole() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 			middleware.SetClusterProvider(r.clusterProviderGetter, r.seedsGetter),
+			middleware.Audit(r.auditSink, audit.VerbPatch, audit.ResourceRole),
 		)(cluster.PatchRoleEndpoint(r.userInfoGetter)),
 		cluster.DecodePatchRoleReq,
 		encodeJSON,
@@ -3905,8 +4408,10 @@ func (r Routing) patchClusterRole() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 			middleware.SetClusterProvider(r.clusterProviderGetter, r.seedsGetter),
+			middleware.Audit(r.auditSink, audit.VerbPatch, audit.ResourceClusterRole),
 		)(cluster.PatchClusterRoleEndpoint(r.userInfoGetter)),
 		cluster.DecodePatchClusterRoleReq,
 		encodeJSON,
@@ -3933,9 +4438,11 @@ func (r Routing) bindUserToRole() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 			middleware.SetClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 			middleware.SetPrivilegedClusterProvider(r.clusterProviderGetter, r.seedsGetter),
+			middleware.Audit(r.auditSink, audit.VerbCreate, audit.ResourceRoleBinding),
 		)(cluster.BindUserToRoleEndpoint(r.projectProvider, r.privilegedProjectProvider, r.userInfoGetter)),
 		cluster.DecodeRoleUserReq,
 		encodeJSON,
@@ -3962,9 +4469,11 @@ func (r Routing) unbindUserFromRoleBinding() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 			middleware.SetClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 			middleware.SetPrivilegedClusterProvider(r.clusterProviderGetter, r.seedsGetter),
+			middleware.Audit(r.auditSink, audit.VerbDelete, audit.ResourceRoleBinding),
 		)(cluster.UnbindUserFromRoleBindingEndpoint(r.projectProvider, r.privilegedProjectProvider, r.userInfoGetter)),
 		cluster.DecodeRoleUserReq,
 		encodeJSON,
@@ -3989,7 +4498,8 @@ func (r Routing) listRoleBinding() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 			middleware.SetClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 			middleware.SetPrivilegedClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 		)(cluster.ListRoleBindingEndpoint(r.projectProvider, r.privilegedProjectProvider, r.userInfoGetter)),
@@ -4018,9 +4528,11 @@ func (r Routing) bindUserToClusterRole() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 			middleware.SetClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 			middleware.SetPrivilegedClusterProvider(r.clusterProviderGetter, r.seedsGetter),
+			middleware.Audit(r.auditSink, audit.VerbCreate, audit.ResourceClusterRoleBinding),
 		)(cluster.BindUserToClusterRoleEndpoint(r.projectProvider, r.privilegedProjectProvider, r.userInfoGetter)),
 		cluster.DecodeClusterRoleUserReq,
 		encodeJSON,
@@ -4047,9 +4559,11 @@ func (r Routing) unbindUserFromClusterRoleBinding() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 			middleware.SetClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 			middleware.SetPrivilegedClusterProvider(r.clusterProviderGetter, r.seedsGetter),
+			middleware.Audit(r.auditSink, audit.VerbDelete, audit.ResourceClusterRoleBinding),
 		)(cluster.UnbindUserFromClusterRoleBindingEndpoint(r.projectProvider, r.privilegedProjectProvider, r.userInfoGetter)),
 		cluster.DecodeClusterRoleUserReq,
 		encodeJSON,
@@ -4074,7 +4588,8 @@ func (r Routing) listClusterRoleBinding() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 			middleware.SetClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 			middleware.SetPrivilegedClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 		)(cluster.ListClusterRoleBindingEndpoint(r.projectProvider, r.privilegedProjectProvider, r.userInfoGetter)),
@@ -4101,6 +4616,7 @@ func (r Routing) listSystemLabels() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
 		)(label.ListSystemLabels()),
 		decodeEmptyReq,
 		encodeJSON,
@@ -4123,7 +4639,8 @@ func (r Routing) getAddonConfig() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 		)(addon.GetAddonConfigEndpoint(r.addonConfigProvider)),
 		addon.DecodeGetConfig,
 		encodeJSON,
@@ -4146,7 +4663,8 @@ func (r Routing) listAddonConfigs() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 		)(addon.ListAddonConfigsEndpoint(r.addonConfigProvider)),
 		decodeEmptyReq,
 		encodeJSON,
@@ -4169,7 +4687,8 @@ func (r Routing) getAdmissionPlugins() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 		)(admissionplugin.GetAdmissionPluginEndpoint(r.admissionPluginProvider)),
 		admissionplugin.DecodeGetAdmissionPlugin,
 		encodeJSON,
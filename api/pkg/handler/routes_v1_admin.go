@@ -23,8 +23,10 @@ import (
 	httptransport "github.com/go-kit/kit/transport/http"
 	"github.com/gorilla/mux"
 
+	"github.com/kubermatic/kubermatic/api/pkg/handler/audit"
 	"github.com/kubermatic/kubermatic/api/pkg/handler/middleware"
 	"github.com/kubermatic/kubermatic/api/pkg/handler/v1/admin"
+	"github.com/kubermatic/kubermatic/api/pkg/handler/v1/provider"
 )
 
 //RegisterV1Admin declares all router paths for the admin users
@@ -84,6 +86,29 @@ func (r Routing) RegisterV1Admin(mux *mux.Router) {
 	mux.Methods(http.MethodDelete).
 		Path("/admin/seeds/{seed_name}").
 		Handler(r.deleteSeed())
+
+	// Defines an endpoint to manage the project quotas
+	mux.Methods(http.MethodPut).
+		Path("/admin/projects/{project_id}/quota").
+		Handler(r.setProjectQuota())
+
+	// Defines a set of HTTP endpoints for the group project bindings
+	mux.Methods(http.MethodGet).
+		Path("/admin/groupprojectbindings").
+		Handler(r.listGroupProjectBindings())
+
+	mux.Methods(http.MethodPost).
+		Path("/admin/groupprojectbindings").
+		Handler(r.createGroupProjectBinding())
+
+	mux.Methods(http.MethodDelete).
+		Path("/admin/groupprojectbindings/{binding_name}").
+		Handler(r.deleteGroupProjectBinding())
+
+	// Defines an endpoint to query the audit trail
+	mux.Methods(http.MethodGet).
+		Path("/admin/audit").
+		Handler(r.listAuditRecords())
 }
 
 // swagger:route GET /api/v1/admin/settings admin getKubermaticSettings
@@ -102,7 +127,8 @@ func (r Routing) getKubermaticSettings() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 		)(admin.KubermaticSettingsEndpoint(r.settingsProvider)),
 		decodeEmptyReq,
 		encodeJSON,
@@ -147,7 +173,9 @@ func (r Routing) patchKubermaticSettings() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
+			middleware.Audit(r.auditSink, audit.VerbPatch, audit.ResourceSettings),
 		)(admin.UpdateKubermaticSettingsEndpoint(r.userInfoGetter, r.settingsProvider)),
 		admin.DecodePatchKubermaticSettingsReq,
 		encodeJSON,
@@ -171,7 +199,8 @@ func (r Routing) getAdmins() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 		)(admin.GetAdminEndpoint(r.userInfoGetter, r.adminProvider)),
 		decodeEmptyReq,
 		encodeJSON,
@@ -199,7 +228,9 @@ func (r Routing) setAdmin() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
+			middleware.Audit(r.auditSink, audit.VerbUpdate, audit.ResourceAdmin),
 		)(admin.SetAdminEndpoint(r.userInfoGetter, r.adminProvider)),
 		admin.DecodeSetAdminReq,
 		encodeJSON,
@@ -223,7 +254,8 @@ func (r Routing) listAdmissionPlugins() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 		)(admin.ListAdmissionPluginEndpoint(r.userInfoGetter, r.admissionPluginProvider)),
 		decodeEmptyReq,
 		encodeJSON,
@@ -247,7 +279,8 @@ func (r Routing) getAdmissionPlugin() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 		)(admin.GetAdmissionPluginEndpoint(r.userInfoGetter, r.admissionPluginProvider)),
 		admin.DecodeAdmissionPluginReq,
 		encodeJSON,
@@ -271,7 +304,9 @@ func (r Routing) deleteAdmissionPlugin() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
+			middleware.Audit(r.auditSink, audit.VerbDelete, audit.ResourceAdmissionPlugin),
 		)(admin.DeleteAdmissionPluginEndpoint(r.userInfoGetter, r.admissionPluginProvider)),
 		admin.DecodeAdmissionPluginReq,
 		encodeJSON,
@@ -298,7 +333,9 @@ func (r Routing) updateAdmissionPlugin() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
+			middleware.Audit(r.auditSink, audit.VerbUpdate, audit.ResourceAdmissionPlugin),
 		)(admin.UpdateAdmissionPluginEndpoint(r.userInfoGetter, r.admissionPluginProvider)),
 		admin.DecodeUpdateAdmissionPluginReq,
 		encodeJSON,
@@ -322,7 +359,8 @@ func (r Routing) listSeeds() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 		)(admin.ListSeedEndpoint(r.userInfoGetter, r.seedsGetter)),
 		decodeEmptyReq,
 		encodeJSON,
@@ -346,7 +384,8 @@ func (r Routing) getSeed() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 		)(admin.GetSeedEndpoint(r.userInfoGetter, r.seedsGetter)),
 		admin.DecodeSeedReq,
 		encodeJSON,
@@ -373,7 +412,9 @@ func (r Routing) updateSeed() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
+			middleware.Audit(r.auditSink, audit.VerbUpdate, audit.ResourceSeed),
 		)(admin.UpdateSeedEndpoint(r.userInfoGetter, r.seedsGetter, r.seedsClientGetter)),
 		admin.DecodeUpdateSeedReq,
 		encodeJSON,
@@ -397,10 +438,148 @@ func (r Routing) deleteSeed() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
+			middleware.Audit(r.auditSink, audit.VerbDelete, audit.ResourceSeed),
 		)(admin.DeleteSeedEndpoint(r.userInfoGetter, r.seedsGetter, r.seedsClientGetter)),
 		admin.DecodeSeedReq,
 		encodeJSON,
 		r.defaultServerOptions()...,
 	)
 }
+
+// swagger:route PUT /api/v1/admin/projects/{project_id}/quota admin setProjectQuota
+//
+//     Sets the quota of the project. A quota without any limits removes the quota.
+//
+//     Consumes:
+//     - application/json
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       default: errorResponse
+//       200: Project
+//       401: empty
+//       403: empty
+func (r Routing) setProjectQuota() http.Handler {
+	return httptransport.NewServer(
+		endpoint.Chain(
+			middleware.TokenVerifier(r.tokenVerifiers),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
+			middleware.Audit(r.auditSink, audit.VerbUpdate, audit.ResourceProjectQuota),
+		)(admin.SetProjectQuotaEndpoint(r.userInfoGetter, r.privilegedProjectProvider, r.projectMemberProvider, r.userProvider, r.seedsGetter, r.clusterProviderGetter, provider.GetNodeSize)),
+		admin.DecodeSetProjectQuotaReq,
+		encodeJSON,
+		r.defaultServerOptions()...,
+	)
+}
+
+// swagger:route GET /api/v1/admin/groupprojectbindings admin listGroupProjectBindings
+//
+//     Lists the bindings of the identity provider groups to the project roles.
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       default: errorResponse
+//       200: []GroupProjectBinding
+//       401: empty
+//       403: empty
+func (r Routing) listGroupProjectBindings() http.Handler {
+	return httptransport.NewServer(
+		endpoint.Chain(
+			middleware.TokenVerifier(r.tokenVerifiers),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
+		)(admin.ListGroupProjectBindingsEndpoint(r.userInfoGetter, r.groupProjectBindingProvider)),
+		decodeEmptyReq,
+		encodeJSON,
+		r.defaultServerOptions()...,
+	)
+}
+
+// swagger:route POST /api/v1/admin/groupprojectbindings admin createGroupProjectBinding
+//
+//     Binds a group of the identity provider to a project role. The members of the group
+//     get access to the project the next time they log in.
+//
+//     Consumes:
+//     - application/json
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       default: errorResponse
+//       201: GroupProjectBinding
+//       401: empty
+//       403: empty
+func (r Routing) createGroupProjectBinding() http.Handler {
+	return httptransport.NewServer(
+		endpoint.Chain(
+			middleware.TokenVerifier(r.tokenVerifiers),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
+			middleware.Audit(r.auditSink, audit.VerbCreate, audit.ResourceGroupProjectBinding),
+		)(admin.CreateGroupProjectBindingEndpoint(r.userInfoGetter, r.groupProjectBindingProvider)),
+		admin.DecodeCreateGroupProjectBindingReq,
+		setStatusCreatedHeader(encodeJSON),
+		r.defaultServerOptions()...,
+	)
+}
+
+// swagger:route DELETE /api/v1/admin/groupprojectbindings/{binding_name} admin deleteGroupProjectBinding
+//
+//     Deletes the group project binding together with the project memberships derived from it.
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       default: errorResponse
+//       200: empty
+//       401: empty
+//       403: empty
+func (r Routing) deleteGroupProjectBinding() http.Handler {
+	return httptransport.NewServer(
+		endpoint.Chain(
+			middleware.TokenVerifier(r.tokenVerifiers),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
+			middleware.Audit(r.auditSink, audit.VerbDelete, audit.ResourceGroupProjectBinding),
+		)(admin.DeleteGroupProjectBindingEndpoint(r.userInfoGetter, r.groupProjectBindingProvider)),
+		admin.DecodeGroupProjectBindingReq,
+		encodeJSON,
+		r.defaultServerOptions()...,
+	)
+}
+
+// swagger:route GET /api/v1/admin/audit admin listAuditRecords
+//
+//     Lists the audit records of the mutations performed through the API, the most recent record comes first.
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       default: errorResponse
+//       200: []AuditRecord
+//       401: empty
+//       403: empty
+//       501: empty
+func (r Routing) listAuditRecords() http.Handler {
+	return httptransport.NewServer(
+		endpoint.Chain(
+			middleware.TokenVerifier(r.tokenVerifiers),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
+		)(admin.ListAuditRecordsEndpoint(r.userInfoGetter, r.auditSink)),
+		admin.DecodeListAuditRecordsReq,
+		encodeJSON,
+		r.defaultServerOptions()...,
+	)
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/gorilla/mux"
 
 	"github.com/go-kit/kit/endpoint"
+	"github.com/kubermatic/kubermatic/api/pkg/handler/audit"
 	"github.com/kubermatic/kubermatic/api/pkg/handler/middleware"
 	"github.com/kubermatic/kubermatic/api/pkg/handler/v1/node"
 )
@@ -71,7 +72,8 @@ func (r Routing) getNodeForClusterLegacy() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 			middleware.SetClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 			middleware.SetPrivilegedClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 		)(node.GetNodeForClusterLegacyEndpoint(r.projectProvider, r.privilegedProjectProvider, r.userInfoGetter)),
@@ -104,8 +106,10 @@ func (r Routing) createNodeForClusterLegacy() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 			middleware.SetClusterProvider(r.clusterProviderGetter, r.seedsGetter),
+			middleware.Audit(r.auditSink, audit.VerbCreate, audit.ResourceNode),
 		)(node.CreateNodeForClusterLegacyEndpoint()),
 		node.DecodeCreateNodeForClusterLegacy,
 		setStatusCreatedHeader(encodeJSON),
@@ -132,7 +136,8 @@ func (r Routing) listNodesForClusterLegacy() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 			middleware.SetClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 			middleware.SetPrivilegedClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 		)(node.ListNodesForClusterLegacyEndpoint(r.projectProvider, r.privilegedProjectProvider, r.userInfoGetter)),
@@ -161,9 +166,11 @@ func (r Routing) deleteNodeForClusterLegacy() http.Handler {
 	return httptransport.NewServer(
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers),
-			middleware.UserSaver(r.userProvider),
+			middleware.RateLimit(r.rateLimiter, middleware.RateLimitGroupDefault),
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
 			middleware.SetClusterProvider(r.clusterProviderGetter, r.seedsGetter),
 			middleware.SetPrivilegedClusterProvider(r.clusterProviderGetter, r.seedsGetter),
+			middleware.Audit(r.auditSink, audit.VerbDelete, audit.ResourceNode),
 		)(node.DeleteNodeForClusterLegacyEndpoint(r.projectProvider, r.privilegedProjectProvider, r.userInfoGetter)),
 		node.DecodeDeleteNodeForClusterLegacy,
 		encodeJSON,
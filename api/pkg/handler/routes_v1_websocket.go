@@ -24,12 +24,17 @@ import (
 
 	v1 "github.com/kubermatic/kubermatic/api/pkg/api/v1"
 	"github.com/kubermatic/kubermatic/api/pkg/handler/auth"
+	"github.com/kubermatic/kubermatic/api/pkg/handler/middleware"
+	"github.com/kubermatic/kubermatic/api/pkg/handler/v1/cluster"
+	"github.com/kubermatic/kubermatic/api/pkg/handler/v1/common"
 	wsh "github.com/kubermatic/kubermatic/api/pkg/handler/websocket"
 	"github.com/kubermatic/kubermatic/api/pkg/log"
 	"github.com/kubermatic/kubermatic/api/pkg/util/errors"
 	"github.com/kubermatic/kubermatic/api/pkg/util/hash"
 	"github.com/kubermatic/kubermatic/api/pkg/watcher"
 
+	"github.com/go-kit/kit/endpoint"
+	httptransport "github.com/go-kit/kit/transport/http"
 	"github.com/gorilla/mux"
 	"github.com/gorilla/websocket"
 )
@@ -71,6 +76,34 @@ func (r Routing) RegisterV1Websocket(mux *mux.Router) {
 	providers := getProviders(r)
 
 	mux.HandleFunc("/ws/admin/settings", getHandler(wsh.WriteSettings, providers, r))
+
+	mux.HandleFunc("/ws/me/clusters", getStreamHandler(
+		endpoint.Chain(
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
+		)(wsh.ClustersStreamEndpoint(r.seedsGetter, r.clusterProviderGetter, r.userProjectMapper)),
+		decodeEmptyReq,
+		r,
+	))
+
+	mux.HandleFunc("/ws/projects/{project_id}/dc/{dc}/clusters/{cluster_id}/nodedeployments", getStreamHandler(
+		endpoint.Chain(
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
+			middleware.SetClusterProvider(r.clusterProviderGetter, r.seedsGetter),
+			middleware.SetPrivilegedClusterProvider(r.clusterProviderGetter, r.seedsGetter),
+		)(wsh.NodeDeploymentsStreamEndpoint(r.projectProvider, r.privilegedProjectProvider, r.userInfoGetter, r.userProjectMapper)),
+		common.DecodeGetClusterReq,
+		r,
+	))
+
+	mux.HandleFunc("/ws/projects/{project_id}/dc/{dc}/clusters/{cluster_id}/events", getStreamHandler(
+		endpoint.Chain(
+			middleware.UserSaver(r.userProvider, r.groupProjectBindingProvider),
+			middleware.SetClusterProvider(r.clusterProviderGetter, r.seedsGetter),
+			middleware.SetPrivilegedClusterProvider(r.clusterProviderGetter, r.seedsGetter),
+		)(wsh.ClusterEventsStreamEndpoint(r.projectProvider, r.privilegedProjectProvider, r.userInfoGetter, r.userProjectMapper)),
+		cluster.DecodeGetClusterEvents,
+		r,
+	))
 }
 
 func getProviders(r Routing) watcher.Providers {
@@ -82,12 +115,18 @@ func getProviders(r Routing) watcher.Providers {
 
 func getHandler(writer WebsocketWriter, providers watcher.Providers, routing Routing) func(w http.ResponseWriter, req *http.Request) {
 	return func(w http.ResponseWriter, req *http.Request) {
-		_, err := verifyAuthorizationToken(req, routing.tokenVerifiers)
+		_, claims, err := verifyAuthorizationToken(req, routing.tokenVerifiers)
 		if err != nil {
 			log.Logger.Debug(err)
 			return
 		}
 
+		ctx := httptransport.PopulateRequestContext(req.Context(), req)
+		if err := middleware.CheckTokenScope(ctx, nil, *claims); err != nil {
+			log.Logger.Debug(err)
+			return
+		}
+
 		ws, err := upgrader.Upgrade(w, req, nil)
 		if err != nil {
 			log.Logger.Debug(err)
@@ -99,7 +138,50 @@ func getHandler(writer WebsocketWriter, providers watcher.Providers, routing Rou
 	}
 }
 
-func verifyAuthorizationToken(req *http.Request, tokenVerifier auth.TokenVerifier) (*v1.User, error) {
+// getStreamHandler returns a handler for streams of watched resources. Before the connection gets upgraded, the
+// request is authenticated and authorized by the given endpoint, which uses the same middlewares as the REST API.
+func getStreamHandler(e endpoint.Endpoint, decodeFunc httptransport.DecodeRequestFunc, routing Routing) func(w http.ResponseWriter, req *http.Request) {
+	return func(w http.ResponseWriter, req *http.Request) {
+		user, claims, err := verifyAuthorizationToken(req, routing.tokenVerifiers)
+		if err != nil {
+			log.Logger.Debug(err)
+			errorEncoder(req.Context(), errors.NewNotAuthorized(), w)
+			return
+		}
+
+		ctx := httptransport.PopulateRequestContext(req.Context(), req)
+		ctx = context.WithValue(ctx, middleware.AuthenticatedUserContextKey, *user)
+		request, err := decodeFunc(ctx, req)
+		if err != nil {
+			errorEncoder(ctx, err, w)
+			return
+		}
+
+		if err := middleware.CheckTokenScope(ctx, request, *claims); err != nil {
+			errorEncoder(ctx, err, w)
+			return
+		}
+
+		response, err := e(ctx, request)
+		if err != nil {
+			errorEncoder(ctx, err, w)
+			return
+		}
+
+		ws, err := upgrader.Upgrade(w, req, nil)
+		if err != nil {
+			log.Logger.Debug(err)
+			return
+		}
+
+		streamCtx, cancel := context.WithCancel(context.Background())
+		go wsh.WriteStream(streamCtx, ws, response.(*wsh.Stream))
+		requestLoggingReader(ws)
+		cancel()
+	}
+}
+
+func verifyAuthorizationToken(req *http.Request, tokenVerifier auth.TokenVerifier) (*v1.User, *auth.TokenClaims, error) {
 	tokenExtractor := auth.NewCombinedExtractor(
 		auth.NewHeaderBearerTokenExtractor("Authorization"),
 		auth.NewCookieHeaderBearerTokenExtractor("token"),
@@ -107,21 +189,21 @@ func verifyAuthorizationToken(req *http.Request, tokenVerifier auth.TokenVerifie
 	)
 	token, err := tokenExtractor.Extract(req)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	claims, err := tokenVerifier.Verify(context.TODO(), token)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	if claims.Subject == "" {
-		return nil, errors.NewNotAuthorized()
+		return nil, nil, errors.NewNotAuthorized()
 	}
 
 	id, err := hash.GetUserID(claims.Subject)
 	if err != nil {
-		return nil, errors.NewNotAuthorized()
+		return nil, nil, errors.NewNotAuthorized()
 	}
 
 	user := &v1.User{
@@ -133,10 +215,10 @@ func verifyAuthorizationToken(req *http.Request, tokenVerifier auth.TokenVerifie
 	}
 
 	if user.ID == "" {
-		return nil, errors.NewNotAuthorized()
+		return nil, nil, errors.NewNotAuthorized()
 	}
 
-	return user, nil
+	return user, &claims, nil
 }
 
 func requestLoggingReader(websocket *websocket.Conn) {
@@ -24,6 +24,7 @@ import (
 	prometheusapi "github.com/prometheus/client_golang/api"
 	"go.uber.org/zap"
 
+	"github.com/kubermatic/kubermatic/api/pkg/handler/audit"
 	"github.com/kubermatic/kubermatic/api/pkg/handler/auth"
 	"github.com/kubermatic/kubermatic/api/pkg/handler/middleware"
 	"github.com/kubermatic/kubermatic/api/pkg/handler/v1/common"
@@ -57,6 +58,7 @@ type Routing struct {
 	clusterProviderGetter                 provider.ClusterProviderGetter
 	addonProviderGetter                   provider.AddonProviderGetter
 	addonConfigProvider                   provider.AddonConfigProvider
+	etcdRestoreProviderGetter             provider.EtcdRestoreProviderGetter
 	updateManager                         common.UpdateManager
 	prometheusClient                      prometheusapi.Client
 	projectMemberProvider                 provider.ProjectMemberProvider
@@ -71,6 +73,11 @@ type Routing struct {
 	settingsProvider                      provider.SettingsProvider
 	adminProvider                         provider.AdminProvider
 	admissionPluginProvider               provider.AdmissionPluginsProvider
+	clusterTemplateProvider               provider.ClusterTemplateProvider
+	groupProjectBindingProvider           provider.GroupProjectBindingProvider
+	auditSink                             audit.Sink
+	rateLimiter                           *middleware.RateLimiter
+	projectClusterLister                  provider.ProjectClusterLister
 	settingsWatcher                       watcher.SettingsWatcher
 }
 
@@ -83,6 +90,7 @@ func NewRouting(
 	clusterProviderGetter provider.ClusterProviderGetter,
 	addonProviderGetter provider.AddonProviderGetter,
 	addonConfigProvider provider.AddonConfigProvider,
+	etcdRestoreProviderGetter provider.EtcdRestoreProviderGetter,
 	newSSHKeyProvider provider.SSHKeyProvider,
 	privilegedSSHKeyProvider provider.PrivilegedSSHKeyProvider,
 	userProvider provider.UserProvider,
@@ -109,6 +117,11 @@ func NewRouting(
 	settingsProvider provider.SettingsProvider,
 	adminProvider provider.AdminProvider,
 	admissionPluginProvider provider.AdmissionPluginsProvider,
+	clusterTemplateProvider provider.ClusterTemplateProvider,
+	groupProjectBindingProvider provider.GroupProjectBindingProvider,
+	auditSink audit.Sink,
+	rateLimiter *middleware.RateLimiter,
+	projectClusterLister provider.ProjectClusterLister,
 	settingsWatcher watcher.SettingsWatcher,
 ) Routing {
 	return Routing{
@@ -119,6 +132,7 @@ func NewRouting(
 		clusterProviderGetter:                 clusterProviderGetter,
 		addonProviderGetter:                   addonProviderGetter,
 		addonConfigProvider:                   addonConfigProvider,
+		etcdRestoreProviderGetter:             etcdRestoreProviderGetter,
 		sshKeyProvider:                        newSSHKeyProvider,
 		privilegedSSHKeyProvider:              privilegedSSHKeyProvider,
 		userProvider:                          userProvider,
@@ -146,6 +160,11 @@ func NewRouting(
 		settingsProvider:                      settingsProvider,
 		adminProvider:                         adminProvider,
 		admissionPluginProvider:               admissionPluginProvider,
+		clusterTemplateProvider:               clusterTemplateProvider,
+		groupProjectBindingProvider:           groupProjectBindingProvider,
+		auditSink:                             auditSink,
+		rateLimiter:                           rateLimiter,
+		projectClusterLister:                  projectClusterLister,
 		settingsWatcher:                       settingsWatcher,
 	}
 }
@@ -154,6 +173,7 @@ func (r Routing) defaultServerOptions() []httptransport.ServerOption {
 	return []httptransport.ServerOption{
 		httptransport.ServerErrorLogger(r.logger),
 		httptransport.ServerErrorEncoder(errorEncoder),
+		httptransport.ServerBefore(httptransport.PopulateRequestContext),
 		httptransport.ServerBefore(middleware.TokenExtractor(r.tokenExtractors)),
 	}
 }
@@ -24,6 +24,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/kubermatic/kubermatic/api/pkg/handler"
+	"github.com/kubermatic/kubermatic/api/pkg/handler/audit"
 	"github.com/kubermatic/kubermatic/api/pkg/handler/auth"
 	"github.com/kubermatic/kubermatic/api/pkg/handler/test"
 	"github.com/kubermatic/kubermatic/api/pkg/handler/v1/common"
@@ -49,6 +50,7 @@ func NewTestRouting(
 	clusterProvidersGetter provider.ClusterProviderGetter,
 	addonProviderGetter provider.AddonProviderGetter,
 	addonConfigProvider provider.AddonConfigProvider,
+	etcdRestoreProviderGetter provider.EtcdRestoreProviderGetter,
 	sshKeyProvider provider.SSHKeyProvider,
 	privilegedSSHKeyProvider provider.PrivilegedSSHKeyProvider,
 	userProvider provider.UserProvider,
@@ -71,6 +73,10 @@ func NewTestRouting(
 	eventRecorderProvider provider.EventRecorderProvider,
 	presetsProvider provider.PresetProvider,
 	admissionPluginProvider provider.AdmissionPluginsProvider,
+	clusterTemplateProvider provider.ClusterTemplateProvider,
+	groupProjectBindingProvider provider.GroupProjectBindingProvider,
+	auditSink audit.Sink,
+	projectClusterLister provider.ProjectClusterLister,
 	settingsWatcher watcher.SettingsWatcher) http.Handler {
 
 	updateManager := version.New(versions, updates)
@@ -82,6 +88,7 @@ func NewTestRouting(
 		clusterProvidersGetter,
 		addonProviderGetter,
 		addonConfigProvider,
+		etcdRestoreProviderGetter,
 		sshKeyProvider,
 		privilegedSSHKeyProvider,
 		userProvider,
@@ -108,6 +115,11 @@ func NewTestRouting(
 		settingsProvider,
 		adminProvider,
 		admissionPluginProvider,
+		clusterTemplateProvider,
+		groupProjectBindingProvider,
+		auditSink,
+		nil,
+		projectClusterLister,
 		settingsWatcher,
 	)
 
@@ -36,9 +36,11 @@ import (
 	apiv1 "github.com/kubermatic/kubermatic/api/pkg/api/v1"
 	k8cuserclusterclient "github.com/kubermatic/kubermatic/api/pkg/cluster/client"
 	"github.com/kubermatic/kubermatic/api/pkg/controller/master-controller-manager/rbac"
+	kubermaticclientset "github.com/kubermatic/kubermatic/api/pkg/crd/client/clientset/versioned"
 	kubermaticfakeclentset "github.com/kubermatic/kubermatic/api/pkg/crd/client/clientset/versioned/fake"
 	kubermaticinformers "github.com/kubermatic/kubermatic/api/pkg/crd/client/informers/externalversions"
 	kubermaticv1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
+	"github.com/kubermatic/kubermatic/api/pkg/handler/audit"
 	"github.com/kubermatic/kubermatic/api/pkg/handler/auth"
 	kubermaticlog "github.com/kubermatic/kubermatic/api/pkg/log"
 	"github.com/kubermatic/kubermatic/api/pkg/provider"
@@ -142,6 +144,7 @@ type newRoutingFunc func(
 	clusterProviderGetter provider.ClusterProviderGetter,
 	addonProviderGetter provider.AddonProviderGetter,
 	addonConfigProvider provider.AddonConfigProvider,
+	etcdRestoreProviderGetter provider.EtcdRestoreProviderGetter,
 	newSSHKeyProvider provider.SSHKeyProvider,
 	privilegedSSHKeyProvider provider.PrivilegedSSHKeyProvider,
 	userProvider provider.UserProvider,
@@ -164,6 +167,10 @@ type newRoutingFunc func(
 	eventRecorderProvider provider.EventRecorderProvider,
 	presetsProvider provider.PresetProvider,
 	admissionPluginProvider provider.AdmissionPluginsProvider,
+	clusterTemplateProvider provider.ClusterTemplateProvider,
+	groupProjectBindingProvider provider.GroupProjectBindingProvider,
+	auditSink audit.Sink,
+	projectClusterLister provider.ProjectClusterLister,
 	settingsWatcher watcher.SettingsWatcher) http.Handler
 
 func initTestEndpoint(user apiv1.User, seedsGetter provider.SeedsGetter, kubeObjects, machineObjects, kubermaticObjects []runtime.Object, versions []*version.Version, updates []*version.Update, routingFunc newRoutingFunc) (http.Handler, *ClientsSets, error) {
@@ -265,6 +272,7 @@ func initTestEndpoint(user apiv1.User, seedsGetter provider.SeedsGetter, kubeObj
 		fakeClient,
 		fakeImpersonationClient,
 		sets.NewString("addon1", "addon2"),
+		addonConfigProvider,
 	)
 	addonProviders := map[string]provider.AddonProvider{"us-central1": addonProvider}
 	addonProviderGetter := func(seed *kubermaticv1.Seed) (provider.AddonProvider, error) {
@@ -274,11 +282,29 @@ func initTestEndpoint(user apiv1.User, seedsGetter provider.SeedsGetter, kubeObj
 		return nil, fmt.Errorf("can not find addonprovider for cluster %q", seed.Name)
 	}
 
+	etcdRestoreProvider := kubernetes.NewEtcdRestoreProvider(fakeClient, fakeImpersonationClient)
+	etcdRestoreProviders := map[string]provider.EtcdRestoreProvider{"us-central1": etcdRestoreProvider}
+	etcdRestoreProviderGetter := func(seed *kubermaticv1.Seed) (provider.EtcdRestoreProvider, error) {
+		if etcdRestoreProvider, exists := etcdRestoreProviders[seed.Name]; exists {
+			return etcdRestoreProvider, nil
+		}
+		return nil, fmt.Errorf("can not find etcdrestoreprovider for cluster %q", seed.Name)
+	}
+
 	credentialsManager, err := kubernetes.NewPresetsProvider(context.Background(), fakeClient, "", true)
 	if err != nil {
 		return nil, nil, err
 	}
 	admissionPluginProvider := kubernetes.NewAdmissionPluginsProvider(context.Background(), fakeClient)
+	clusterTemplateProvider := kubernetes.NewClusterTemplateProvider(context.Background(), fakeClient)
+	groupProjectBindingProvider := kubernetes.NewGroupProjectBindingProvider(fakeClient, kubernetes.IsServiceAccount)
+	auditSink := audit.NewCRDSink(fakeClient, fakeClient)
+	projectClusterLister := kubernetes.NewClusterCache(seedsGetter, func(seed *kubermaticv1.Seed) (kubermaticclientset.Interface, error) {
+		if seed.Name != "us-central1" {
+			return nil, fmt.Errorf("can not find kubermatic client for seed %q", seed.Name)
+		}
+		return kubermaticClient, nil
+	})
 
 	seedClientGetter := func(seed *kubermaticv1.Seed) (ctrlruntimeclient.Client, error) {
 		return fakeClient, nil
@@ -309,6 +335,7 @@ func initTestEndpoint(user apiv1.User, seedsGetter provider.SeedsGetter, kubeObj
 		clusterProviderGetter,
 		addonProviderGetter,
 		addonConfigProvider,
+		etcdRestoreProviderGetter,
 		sshKeyProvider,
 		privilegedSSHKeyProvider,
 		userProvider,
@@ -331,6 +358,10 @@ func initTestEndpoint(user apiv1.User, seedsGetter provider.SeedsGetter, kubeObj
 		eventRecorderProvider,
 		credentialsManager,
 		admissionPluginProvider,
+		clusterTemplateProvider,
+		groupProjectBindingProvider,
+		auditSink,
+		projectClusterLister,
 		settingsWatcher,
 	)
 
@@ -453,6 +484,10 @@ func (f *fakeUserClusterConnection) GetClient(_ *kubermaticv1.Cluster, _ ...k8cu
 	return f.fakeDynamicClient, nil
 }
 
+func (f *fakeUserClusterConnection) GetClientConfig(_ *kubermaticv1.Cluster, _ ...k8cuserclusterclient.ConfigOption) (*restclient.Config, error) {
+	return &restclient.Config{}, nil
+}
+
 // ClientsSets a simple wrapper that holds fake client sets
 type ClientsSets struct {
 	FakeKubermaticClient *kubermaticfakeclentset.Clientset
@@ -869,7 +904,7 @@ func GenDefaultSaToken(projectID, saID, name, id string) *corev1.Secret {
 
 func GenDefaultExpiry() (apiv1.Time, error) {
 	authenticator := serviceaccount.JWTTokenAuthenticator([]byte(TestServiceAccountHashKey))
-	claim, _, err := authenticator.Authenticate(TestFakeToken)
+	claim, _, err := authenticator.Parse(TestFakeToken)
 	if err != nil {
 		return apiv1.Time{}, err
 	}
@@ -29,10 +29,12 @@ import (
 
 	apiv1 "github.com/kubermatic/kubermatic/api/pkg/api/v1"
 	kubermaticapiv1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
+	"github.com/kubermatic/kubermatic/api/pkg/handler/audit"
 	"github.com/kubermatic/kubermatic/api/pkg/handler/middleware"
 	"github.com/kubermatic/kubermatic/api/pkg/handler/v1/common"
 	"github.com/kubermatic/kubermatic/api/pkg/provider"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	k8sjson "k8s.io/apimachinery/pkg/util/json"
 	"k8s.io/apimachinery/pkg/util/sets"
@@ -325,6 +327,9 @@ func PatchAddonEndpoint(projectProvider provider.ProjectProvider, privilegedProj
 		if err != nil {
 			return nil, common.KubernetesErrorToHTTPError(err)
 		}
+		if currentAddon, err := convertInternalAddonToExternal(addon); err == nil {
+			audit.RecordBefore(ctx, currentAddon)
+		}
 		rawVars, err := convertExternalVariablesToInternal(req.Body.Spec.Variables)
 		if err != nil {
 			return nil, common.KubernetesErrorToHTTPError(err)
@@ -430,6 +435,12 @@ func convertInternalAddonToExternal(internalAddon *kubermaticapiv1.Addon) (*apiv
 		Spec: apiv1.AddonSpec{
 			IsDefault: internalAddon.Spec.IsDefault,
 		},
+		Status: apiv1.AddonStatus{
+			InstalledVersion: internalAddon.Status.InstalledVersion,
+			Ready:            addonConditionStatus(internalAddon, kubermaticapiv1.AddonReady) == corev1.ConditionTrue,
+			Drifted:          addonConditionStatus(internalAddon, kubermaticapiv1.AddonDrifted) == corev1.ConditionTrue,
+			Objects:          convertInternalAddonObjectStatusesToExternal(internalAddon.Status.Objects),
+		},
 	}
 	if len(internalAddon.Spec.Variables.Raw) > 0 {
 		if err := k8sjson.Unmarshal(internalAddon.Spec.Variables.Raw, &result.Spec.Variables); err != nil {
@@ -439,6 +450,35 @@ func convertInternalAddonToExternal(internalAddon *kubermaticapiv1.Addon) (*apiv
 	return result, nil
 }
 
+func convertInternalAddonObjectStatusesToExternal(internalStatuses []kubermaticapiv1.AddonObjectStatus) []apiv1.AddonObjectStatus {
+	if internalStatuses == nil {
+		return nil
+	}
+	statuses := make([]apiv1.AddonObjectStatus, 0, len(internalStatuses))
+	for _, status := range internalStatuses {
+		statuses = append(statuses, apiv1.AddonObjectStatus{
+			APIVersion:    status.APIVersion,
+			Kind:          status.Kind,
+			Namespace:     status.Namespace,
+			Name:          status.Name,
+			Ready:         status.Ready,
+			Drifted:       status.Drifted,
+			DriftedFields: status.DriftedFields,
+			Message:       status.Message,
+		})
+	}
+	return statuses
+}
+
+func addonConditionStatus(addon *kubermaticapiv1.Addon, conditionType kubermaticapiv1.AddonConditionType) corev1.ConditionStatus {
+	for _, condition := range addon.Status.Conditions {
+		if condition.Type == conditionType {
+			return condition.Status
+		}
+	}
+	return corev1.ConditionUnknown
+}
+
 func convertInternalAddonsToExternal(internalAddons []*kubermaticapiv1.Addon) ([]*apiv1.Addon, error) {
 	result := []*apiv1.Addon{}
 
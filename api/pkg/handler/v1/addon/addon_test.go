@@ -29,6 +29,8 @@ import (
 	"github.com/kubermatic/kubermatic/api/pkg/handler/test"
 	"github.com/kubermatic/kubermatic/api/pkg/handler/test/hack"
 
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	k8sjson "k8s.io/apimachinery/pkg/util/json"
 )
@@ -134,6 +136,47 @@ func TestGetAddon(t *testing.T) {
 				},
 			},
 		},
+		// scenario 5
+		{
+			Name:                   "scenario 5: get addon with the health reported by the controller",
+			ClusterIDToSync:        test.GenDefaultCluster().Name,
+			ProjectIDToSync:        test.GenDefaultProject().Name,
+			ExistingKubermaticObjs: test.GenDefaultKubermaticObjects(test.GenDefaultCluster()),
+			ExistingAPIUser:        test.GenDefaultAPIUser(),
+			ExistingAddons: []*kubermaticv1.Addon{
+				func() *kubermaticv1.Addon {
+					addon := test.GenTestAddon("addon1", nil, test.GenDefaultCluster(), creationTime)
+					addon.Status = kubermaticv1.AddonStatus{
+						InstalledVersion: "v1.0.0",
+						Conditions: []kubermaticv1.AddonCondition{
+							{Type: kubermaticv1.AddonReady, Status: corev1.ConditionTrue},
+							{Type: kubermaticv1.AddonDrifted, Status: corev1.ConditionTrue},
+						},
+						Objects: []kubermaticv1.AddonObjectStatus{
+							{APIVersion: "apps/v1", Kind: "Deployment", Namespace: "kube-system", Name: "addon1", Ready: true, Drifted: true, DriftedFields: []string{"spec.replicas"}},
+						},
+					}
+					return addon
+				}(),
+			},
+			AddonToGet:         "addon1",
+			ExpectedHTTPStatus: http.StatusOK,
+			ExpectedResponse: apiv1.Addon{
+				ObjectMeta: apiv1.ObjectMeta{
+					ID:                "addon1",
+					Name:              "addon1",
+					CreationTimestamp: apiv1.NewTime(creationTime),
+				},
+				Status: apiv1.AddonStatus{
+					InstalledVersion: "v1.0.0",
+					Ready:            true,
+					Drifted:          true,
+					Objects: []apiv1.AddonObjectStatus{
+						{APIVersion: "apps/v1", Kind: "Deployment", Namespace: "kube-system", Name: "addon1", Ready: true, Drifted: true, DriftedFields: []string{"spec.replicas"}},
+					},
+				},
+			},
+		},
 	}
 
 	for _, tc := range testcases {
@@ -417,6 +460,37 @@ func TestCreateAddon(t *testing.T) {
 			},
 			ExistingAPIUser: test.GenAPIUser("bob", "bob@acme.com"),
 		},
+		// scenario 4
+		{
+			Name: "scenario 4: try to create an addon without a variable its AddonConfig requires",
+			Body: `{
+				"name": "addon1",
+				"spec": {
+					"variables": {}
+				}
+			}`,
+			ExpectedHTTPStatus: http.StatusBadRequest,
+			ExistingKubermaticObjs: []runtime.Object{
+				/*add projects*/
+				test.GenProject("my-first-project", kubermaticv1.ProjectActive, test.DefaultCreationTimestamp()),
+				/*add bindings*/
+				test.GenBinding("my-first-project-ID", "john@acme.com", "owners"),
+				/*add users*/
+				test.GenUser("", "john", "john@acme.com"),
+				/*add cluster*/
+				cluster,
+				/*add addon config*/
+				&kubermaticv1.AddonConfig{
+					ObjectMeta: metav1.ObjectMeta{Name: "addon1"},
+					Spec: kubermaticv1.AddonConfigSpec{
+						Controls: []kubermaticv1.AddonFormControl{
+							{InternalName: "domain", Type: kubermaticv1.AddonFormControlTypeText, Required: true},
+						},
+					},
+				},
+			},
+			ExistingAPIUser: test.GenAPIUser("john", "john@acme.com"),
+		},
 	}
 
 	for _, tc := range testcases {
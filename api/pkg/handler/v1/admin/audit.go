@@ -0,0 +1,149 @@
+/*
+Copyright 2020 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admin
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-kit/kit/endpoint"
+
+	apiv1 "github.com/kubermatic/kubermatic/api/pkg/api/v1"
+	kubermaticv1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
+	"github.com/kubermatic/kubermatic/api/pkg/handler/audit"
+	"github.com/kubermatic/kubermatic/api/pkg/handler/v1/common"
+	"github.com/kubermatic/kubermatic/api/pkg/provider"
+	k8cerrors "github.com/kubermatic/kubermatic/api/pkg/util/errors"
+)
+
+// defaultAuditRecordsLimit is the number of records returned when the limit is not specified
+const defaultAuditRecordsLimit = 100
+
+// ListAuditRecordsEndpoint returns the audit records matching the request, the most recent record comes first
+func ListAuditRecordsEndpoint(userInfoGetter provider.UserInfoGetter, auditSink audit.Sink) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req, ok := request.(listAuditRecordsReq)
+		if !ok {
+			return nil, k8cerrors.NewBadRequest("invalid request")
+		}
+		userInfo, err := userInfoGetter(ctx, "")
+		if err != nil {
+			return nil, common.KubernetesErrorToHTTPError(err)
+		}
+		if !userInfo.IsAdmin {
+			return nil, k8cerrors.New(http.StatusForbidden, fmt.Sprintf("forbidden: \"%s\" doesn't have admin rights", userInfo.Email))
+		}
+		if auditSink == nil {
+			return nil, k8cerrors.New(http.StatusNotImplemented, "the audit trail is disabled")
+		}
+		reader, ok := auditSink.(audit.Reader)
+		if !ok {
+			return nil, k8cerrors.New(http.StatusNotImplemented, "the configured audit sink cannot be queried")
+		}
+
+		filter, err := req.filter()
+		if err != nil {
+			return nil, k8cerrors.NewBadRequest("%v", err)
+		}
+		records, err := reader.List(filter)
+		if err != nil {
+			return nil, err
+		}
+
+		result := []apiv1.AuditRecord{}
+		for _, record := range records {
+			result = append(result, convertAuditRecord(record))
+		}
+		return result, nil
+	}
+}
+
+// listAuditRecordsReq defines HTTP request for listAuditRecords
+// swagger:parameters listAuditRecords
+type listAuditRecordsReq struct {
+	// in: query
+	Actor string `json:"actor,omitempty"`
+	// in: query
+	ProjectID string `json:"project_id,omitempty"`
+	// in: query
+	Resource string `json:"resource,omitempty"`
+	// in: query
+	Verb string `json:"verb,omitempty"`
+	// Since is a RFC3339 timestamp, only the records created since then are returned
+	// in: query
+	Since string `json:"since,omitempty"`
+	// Limit is the maximum number of records returned, 100 by default
+	// in: query
+	Limit string `json:"limit,omitempty"`
+}
+
+func (r listAuditRecordsReq) filter() (audit.Filter, error) {
+	filter := audit.Filter{
+		Actor:     r.Actor,
+		ProjectID: r.ProjectID,
+		Resource:  r.Resource,
+		Verb:      r.Verb,
+		Limit:     defaultAuditRecordsLimit,
+	}
+	if r.Since != "" {
+		since, err := time.Parse(time.RFC3339, r.Since)
+		if err != nil {
+			return filter, fmt.Errorf("invalid since parameter, expected a RFC3339 timestamp: %v", err)
+		}
+		filter.Since = since
+	}
+	if r.Limit != "" {
+		limit, err := strconv.Atoi(r.Limit)
+		if err != nil || limit < 1 {
+			return filter, fmt.Errorf("invalid limit parameter %q, expected a positive number", r.Limit)
+		}
+		filter.Limit = limit
+	}
+	return filter, nil
+}
+
+func DecodeListAuditRecordsReq(c context.Context, r *http.Request) (interface{}, error) {
+	query := r.URL.Query()
+	return listAuditRecordsReq{
+		Actor:     query.Get("actor"),
+		ProjectID: query.Get("project_id"),
+		Resource:  query.Get("resource"),
+		Verb:      query.Get("verb"),
+		Since:     query.Get("since"),
+		Limit:     query.Get("limit"),
+	}, nil
+}
+
+func convertAuditRecord(record kubermaticv1.AuditRecordSpec) apiv1.AuditRecord {
+	return apiv1.AuditRecord{
+		Timestamp:  apiv1.NewTime(record.Timestamp.Time),
+		Actor:      record.Actor,
+		ProjectID:  record.ProjectID,
+		Verb:       record.Verb,
+		Resource:   record.Resource,
+		Path:       record.Path,
+		Request:    record.Request,
+		Before:     record.Before,
+		After:      record.After,
+		Succeeded:  record.Succeeded,
+		StatusCode: record.StatusCode,
+		Error:      record.Error,
+	}
+}
@@ -0,0 +1,182 @@
+/*
+Copyright 2020 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admin_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	apiv1 "github.com/kubermatic/kubermatic/api/pkg/api/v1"
+	kubermaticv1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
+	"github.com/kubermatic/kubermatic/api/pkg/handler/test"
+	"github.com/kubermatic/kubermatic/api/pkg/handler/test/hack"
+
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestListAuditRecordsEndpoint(t *testing.T) {
+	t.Parallel()
+	testcases := []struct {
+		name                   string
+		query                  string
+		expectedResponse       string
+		httpStatus             int
+		existingAPIUser        *apiv1.User
+		existingKubermaticObjs []runtime.Object
+	}{
+		{
+			name:                   "scenario 1: not authorized user gets the audit records",
+			expectedResponse:       `{"error":{"code":403,"message":"forbidden: \"bob@acme.com\" doesn't have admin rights"}}`,
+			httpStatus:             http.StatusForbidden,
+			existingKubermaticObjs: []runtime.Object{genUser("Bob", "bob@acme.com", false)},
+			existingAPIUser:        test.GenDefaultAPIUser(),
+		},
+		{
+			name:             "scenario 2: authorized user gets the audit records of the given project",
+			query:            "?project_id=my-first-project-ID",
+			expectedResponse: `[{"timestamp":"2013-02-03T19:55:00Z","actor":"john@acme.com","projectID":"my-first-project-ID","verb":"delete","resource":"cluster","path":"/api/v1/projects/my-first-project-ID/dc/us-central1/clusters/abcd","succeeded":false,"statusCode":403,"error":"forbidden"},{"timestamp":"2013-02-03T19:54:00Z","actor":"john@acme.com","projectID":"my-first-project-ID","verb":"create","resource":"cluster","path":"/api/v1/projects/my-first-project-ID/dc/us-central1/clusters","request":"{\"body\":{\"name\":\"abcd\"}}","succeeded":true}]`,
+			httpStatus:       http.StatusOK,
+			existingKubermaticObjs: []runtime.Object{
+				genUser("Bob", "bob@acme.com", true),
+				genAuditRecord("a", test.DefaultCreationTimestamp(), "create", "my-first-project-ID", `/api/v1/projects/my-first-project-ID/dc/us-central1/clusters`, `{"body":{"name":"abcd"}}`, 0),
+				genAuditRecord("b", test.DefaultCreationTimestamp().Add(time.Minute), "delete", "my-first-project-ID", `/api/v1/projects/my-first-project-ID/dc/us-central1/clusters/abcd`, "", http.StatusForbidden),
+				genAuditRecord("c", test.DefaultCreationTimestamp(), "create", "other-project-ID", `/api/v1/projects/other-project-ID/dc/us-central1/clusters`, "", 0),
+			},
+			existingAPIUser: test.GenDefaultAPIUser(),
+		},
+		{
+			name:             "scenario 3: the limit must be a positive number",
+			query:            "?limit=-1",
+			expectedResponse: `{"error":{"code":400,"message":"invalid limit parameter \"-1\", expected a positive number"}}`,
+			httpStatus:       http.StatusBadRequest,
+			existingKubermaticObjs: []runtime.Object{
+				genUser("Bob", "bob@acme.com", true),
+			},
+			existingAPIUser: test.GenDefaultAPIUser(),
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/api/v1/admin/audit"+tc.query, strings.NewReader(""))
+			res := httptest.NewRecorder()
+			ep, err := test.CreateTestEndpoint(*tc.existingAPIUser, nil, tc.existingKubermaticObjs, nil, nil, hack.NewTestRouting)
+			if err != nil {
+				t.Fatalf("failed to create test endpoint due to %v", err)
+			}
+
+			ep.ServeHTTP(res, req)
+
+			if res.Code != tc.httpStatus {
+				t.Fatalf("Expected HTTP status code %d, got %d: %s", tc.httpStatus, res.Code, res.Body.String())
+			}
+			test.CompareWithResult(t, res, tc.expectedResponse)
+		})
+	}
+}
+
+func TestAuditTrailRecordsMutations(t *testing.T) {
+	t.Parallel()
+	ep, err := test.CreateTestEndpoint(*test.GenDefaultAPIUser(), nil, []runtime.Object{genUser("Bob", "bob@acme.com", true)}, nil, nil, hack.NewTestRouting)
+	if err != nil {
+		t.Fatalf("failed to create test endpoint due to %v", err)
+	}
+
+	req := httptest.NewRequest("POST", "/api/v1/projects", strings.NewReader(`{"name":"my-second-project"}`))
+	res := httptest.NewRecorder()
+	ep.ServeHTTP(res, req)
+	if res.Code != http.StatusCreated {
+		t.Fatalf("Expected HTTP status code %d, got %d: %s", http.StatusCreated, res.Code, res.Body.String())
+	}
+	project := apiv1.Project{}
+	if err := json.Unmarshal(res.Body.Bytes(), &project); err != nil {
+		t.Fatal(err)
+	}
+
+	req = httptest.NewRequest("PUT", "/api/v1/projects/"+project.ID, strings.NewReader(`{"name":"my-renamed-project"}`))
+	res = httptest.NewRecorder()
+	ep.ServeHTTP(res, req)
+	if res.Code != http.StatusOK {
+		t.Fatalf("Expected HTTP status code %d, got %d: %s", http.StatusOK, res.Code, res.Body.String())
+	}
+
+	req = httptest.NewRequest("GET", "/api/v1/admin/audit?resource=project", strings.NewReader(""))
+	res = httptest.NewRecorder()
+	ep.ServeHTTP(res, req)
+	if res.Code != http.StatusOK {
+		t.Fatalf("Expected HTTP status code %d, got %d: %s", http.StatusOK, res.Code, res.Body.String())
+	}
+	records := []apiv1.AuditRecord{}
+	if err := json.Unmarshal(res.Body.Bytes(), &records); err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("expected exactly two audit records, got %v", records)
+	}
+	// the records may share the timestamp, thus their order is not defined
+	if records[0].Verb != "update" {
+		records[0], records[1] = records[1], records[0]
+	}
+
+	update := records[0]
+	if update.Verb != "update" || update.ProjectID != project.ID || !update.Succeeded {
+		t.Fatalf("unexpected audit record %+v", update)
+	}
+	if !strings.Contains(update.Before, `"name":"my-second-project"`) {
+		t.Fatalf("expected the state before the update to be recorded, got %s", update.Before)
+	}
+	if !strings.Contains(update.After, `"name":"my-renamed-project"`) {
+		t.Fatalf("expected the state after the update to be recorded, got %s", update.After)
+	}
+
+	record := records[1]
+	if record.Actor != "bob@acme.com" || record.Verb != "create" || record.ProjectID != project.ID || !record.Succeeded || record.Path != "/api/v1/projects" {
+		t.Fatalf("unexpected audit record %+v", record)
+	}
+	if !strings.Contains(record.Request, `"name":"my-second-project"`) {
+		t.Fatalf("expected the request to be recorded, got %s", record.Request)
+	}
+	if record.Before != "" {
+		t.Fatalf("expected no state before the creation, got %s", record.Before)
+	}
+}
+
+func genAuditRecord(name string, timestamp time.Time, verb, projectID, path, request string, statusCode int) *kubermaticv1.AuditRecord {
+	record := &kubermaticv1.AuditRecord{
+		ObjectMeta: v1.ObjectMeta{Name: name},
+		Spec: kubermaticv1.AuditRecordSpec{
+			Timestamp: v1.NewTime(timestamp),
+			Actor:     "john@acme.com",
+			ProjectID: projectID,
+			Verb:      verb,
+			Resource:  "cluster",
+			Path:      path,
+			Request:   request,
+			Succeeded: statusCode == 0,
+		},
+	}
+	if statusCode != 0 {
+		record.Spec.StatusCode = statusCode
+		record.Spec.Error = "forbidden"
+	}
+	return record
+}
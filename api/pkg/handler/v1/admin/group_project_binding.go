@@ -0,0 +1,156 @@
+/*
+Copyright 2020 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/gorilla/mux"
+
+	apiv1 "github.com/kubermatic/kubermatic/api/pkg/api/v1"
+	kubermaticv1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
+	"github.com/kubermatic/kubermatic/api/pkg/handler/v1/common"
+	"github.com/kubermatic/kubermatic/api/pkg/provider"
+	k8cerrors "github.com/kubermatic/kubermatic/api/pkg/util/errors"
+
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ListGroupProjectBindingsEndpoint returns the group project bindings
+func ListGroupProjectBindingsEndpoint(userInfoGetter provider.UserInfoGetter, groupProjectBindingProvider provider.GroupProjectBindingProvider) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		userInfo, err := userInfoGetter(ctx, "")
+		if err != nil {
+			return nil, common.KubernetesErrorToHTTPError(err)
+		}
+		bindings, err := groupProjectBindingProvider.List(userInfo)
+		if err != nil {
+			return nil, common.KubernetesErrorToHTTPError(err)
+		}
+
+		resultList := []apiv1.GroupProjectBinding{}
+		for _, binding := range bindings {
+			resultList = append(resultList, convertGroupProjectBinding(binding))
+		}
+		return resultList, nil
+	}
+}
+
+// CreateGroupProjectBindingEndpoint creates a group project binding
+func CreateGroupProjectBindingEndpoint(userInfoGetter provider.UserInfoGetter, groupProjectBindingProvider provider.GroupProjectBindingProvider) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req, ok := request.(createGroupProjectBindingReq)
+		if !ok {
+			return nil, k8cerrors.NewBadRequest("invalid request")
+		}
+		if err := req.Validate(); err != nil {
+			return nil, k8cerrors.NewBadRequest("%v", err)
+		}
+		userInfo, err := userInfoGetter(ctx, "")
+		if err != nil {
+			return nil, common.KubernetesErrorToHTTPError(err)
+		}
+		binding, err := groupProjectBindingProvider.Create(userInfo, &kubermaticv1.GroupProjectBinding{
+			ObjectMeta: v1.ObjectMeta{Name: req.Body.Name},
+			Spec: kubermaticv1.GroupProjectBindingSpec{
+				Group:     req.Body.Group,
+				ProjectID: req.Body.ProjectID,
+				Role:      req.Body.Role,
+			},
+		})
+		if err != nil {
+			return nil, common.KubernetesErrorToHTTPError(err)
+		}
+
+		return convertGroupProjectBinding(*binding), nil
+	}
+}
+
+// DeleteGroupProjectBindingEndpoint deletes the group project binding
+func DeleteGroupProjectBindingEndpoint(userInfoGetter provider.UserInfoGetter, groupProjectBindingProvider provider.GroupProjectBindingProvider) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req, ok := request.(groupProjectBindingReq)
+		if !ok {
+			return nil, k8cerrors.NewBadRequest("invalid request")
+		}
+		userInfo, err := userInfoGetter(ctx, "")
+		if err != nil {
+			return nil, common.KubernetesErrorToHTTPError(err)
+		}
+		if err := groupProjectBindingProvider.Delete(userInfo, req.BindingName); err != nil {
+			return nil, common.KubernetesErrorToHTTPError(err)
+		}
+
+		return nil, nil
+	}
+}
+
+// groupProjectBindingReq defines HTTP request for deleteGroupProjectBinding
+// swagger:parameters deleteGroupProjectBinding
+type groupProjectBindingReq struct {
+	// in: path
+	// required: true
+	BindingName string `json:"binding_name"`
+}
+
+// createGroupProjectBindingReq defines HTTP request for createGroupProjectBinding
+// swagger:parameters createGroupProjectBinding
+type createGroupProjectBindingReq struct {
+	// in: body
+	Body apiv1.GroupProjectBinding
+}
+
+// Validate validates CreateGroupProjectBindingEndpoint request
+func (r createGroupProjectBindingReq) Validate() error {
+	if r.Body.Group == "" || r.Body.ProjectID == "" || r.Body.Role == "" {
+		return fmt.Errorf("the group, projectID and role fields cannot be empty")
+	}
+	return nil
+}
+
+func DecodeGroupProjectBindingReq(c context.Context, r *http.Request) (interface{}, error) {
+	var req groupProjectBindingReq
+	name := mux.Vars(r)["binding_name"]
+	if name == "" {
+		return nil, fmt.Errorf("'binding_name' parameter is required but was not provided")
+	}
+	req.BindingName = name
+
+	return req, nil
+}
+
+func DecodeCreateGroupProjectBindingReq(c context.Context, r *http.Request) (interface{}, error) {
+	var req createGroupProjectBindingReq
+	if err := json.NewDecoder(r.Body).Decode(&req.Body); err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+func convertGroupProjectBinding(binding kubermaticv1.GroupProjectBinding) apiv1.GroupProjectBinding {
+	return apiv1.GroupProjectBinding{
+		Name:      binding.Name,
+		Group:     binding.Spec.Group,
+		ProjectID: binding.Spec.ProjectID,
+		Role:      binding.Spec.Role,
+	}
+}
@@ -0,0 +1,240 @@
+/*
+Copyright 2020 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admin_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	apiv1 "github.com/kubermatic/kubermatic/api/pkg/api/v1"
+	kubermaticv1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
+	"github.com/kubermatic/kubermatic/api/pkg/handler/test"
+	"github.com/kubermatic/kubermatic/api/pkg/handler/test/hack"
+
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestListGroupProjectBindingsEndpoint(t *testing.T) {
+	t.Parallel()
+	testcases := []struct {
+		name                   string
+		expectedResponse       string
+		httpStatus             int
+		existingAPIUser        *apiv1.User
+		existingKubermaticObjs []runtime.Object
+	}{
+		{
+			name:                   "scenario 1: not authorized user gets group project bindings",
+			expectedResponse:       `{"error":{"code":403,"message":"forbidden: \"bob@acme.com\" doesn't have admin rights"}}`,
+			httpStatus:             http.StatusForbidden,
+			existingKubermaticObjs: []runtime.Object{genUser("Bob", "bob@acme.com", false)},
+			existingAPIUser:        test.GenDefaultAPIUser(),
+		},
+		{
+			name:             "scenario 2: authorized user gets group project bindings",
+			expectedResponse: `[{"name":"payments-editors","group":"team-payments","projectID":"my-first-project-ID","role":"editors"}]`,
+			httpStatus:       http.StatusOK,
+			existingKubermaticObjs: []runtime.Object{
+				genUser("Bob", "bob@acme.com", true),
+				genGroupProjectBinding("payments-editors", "team-payments", "my-first-project-ID", "editors"),
+			},
+			existingAPIUser: test.GenDefaultAPIUser(),
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/api/v1/admin/groupprojectbindings", strings.NewReader(""))
+			res := httptest.NewRecorder()
+			ep, err := test.CreateTestEndpoint(*tc.existingAPIUser, nil, tc.existingKubermaticObjs, nil, nil, hack.NewTestRouting)
+			if err != nil {
+				t.Fatalf("failed to create test endpoint due to %v", err)
+			}
+
+			ep.ServeHTTP(res, req)
+
+			if res.Code != tc.httpStatus {
+				t.Fatalf("Expected HTTP status code %d, got %d: %s", tc.httpStatus, res.Code, res.Body.String())
+			}
+			test.CompareWithResult(t, res, tc.expectedResponse)
+		})
+	}
+}
+
+func TestCreateGroupProjectBindingEndpoint(t *testing.T) {
+	t.Parallel()
+	testcases := []struct {
+		name                   string
+		body                   string
+		expectedResponse       string
+		httpStatus             int
+		existingAPIUser        *apiv1.User
+		existingKubermaticObjs []runtime.Object
+	}{
+		{
+			name:             "scenario 1: authorized user binds a group to a project",
+			body:             `{"name":"payments-editors","group":"team-payments","projectID":"my-first-project-ID","role":"editors"}`,
+			expectedResponse: `{"name":"payments-editors","group":"team-payments","projectID":"my-first-project-ID","role":"editors"}`,
+			httpStatus:       http.StatusCreated,
+			existingKubermaticObjs: []runtime.Object{
+				genUser("Bob", "bob@acme.com", true),
+				test.GenDefaultProject(),
+			},
+			existingAPIUser: test.GenDefaultAPIUser(),
+		},
+		{
+			name:             "scenario 2: not authorized user cannot bind a group to a project",
+			body:             `{"name":"payments-editors","group":"team-payments","projectID":"my-first-project-ID","role":"editors"}`,
+			expectedResponse: `{"error":{"code":403,"message":"forbidden: \"bob@acme.com\" doesn't have admin rights"}}`,
+			httpStatus:       http.StatusForbidden,
+			existingKubermaticObjs: []runtime.Object{
+				genUser("Bob", "bob@acme.com", false),
+				test.GenDefaultProject(),
+			},
+			existingAPIUser: test.GenDefaultAPIUser(),
+		},
+		{
+			name:             "scenario 3: the role must exist",
+			body:             `{"name":"payments-billing","group":"team-payments","projectID":"my-first-project-ID","role":"billingviewer"}`,
+			expectedResponse: `{"error":{"code":400,"message":"invalid group name billingviewer"}}`,
+			httpStatus:       http.StatusBadRequest,
+			existingKubermaticObjs: []runtime.Object{
+				genUser("Bob", "bob@acme.com", true),
+				test.GenDefaultProject(),
+			},
+			existingAPIUser: test.GenDefaultAPIUser(),
+		},
+		{
+			name:             "scenario 4: the group is required",
+			body:             `{"name":"payments-editors","projectID":"my-first-project-ID","role":"editors"}`,
+			expectedResponse: `{"error":{"code":400,"message":"the group, projectID and role fields cannot be empty"}}`,
+			httpStatus:       http.StatusBadRequest,
+			existingKubermaticObjs: []runtime.Object{
+				genUser("Bob", "bob@acme.com", true),
+				test.GenDefaultProject(),
+			},
+			existingAPIUser: test.GenDefaultAPIUser(),
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/api/v1/admin/groupprojectbindings", strings.NewReader(tc.body))
+			res := httptest.NewRecorder()
+			ep, err := test.CreateTestEndpoint(*tc.existingAPIUser, nil, tc.existingKubermaticObjs, nil, nil, hack.NewTestRouting)
+			if err != nil {
+				t.Fatalf("failed to create test endpoint due to %v", err)
+			}
+
+			ep.ServeHTTP(res, req)
+
+			if res.Code != tc.httpStatus {
+				t.Fatalf("Expected HTTP status code %d, got %d: %s", tc.httpStatus, res.Code, res.Body.String())
+			}
+			test.CompareWithResult(t, res, tc.expectedResponse)
+		})
+	}
+}
+
+func TestDeleteGroupProjectBindingEndpoint(t *testing.T) {
+	t.Parallel()
+	derivedBinding := test.GenBinding("my-first-project-ID", "alice@acme.com", "editors")
+	derivedBinding.Labels = map[string]string{kubermaticv1.GroupProjectBindingLabelKey: "payments-editors"}
+
+	testcases := []struct {
+		name                   string
+		bindingName            string
+		expectedResponse       string
+		httpStatus             int
+		expectedBindings       int
+		existingAPIUser        *apiv1.User
+		existingKubermaticObjs []runtime.Object
+	}{
+		{
+			name:             "scenario 1: authorized user deletes a group project binding together with the derived memberships",
+			bindingName:      "payments-editors",
+			expectedResponse: `{}`,
+			httpStatus:       http.StatusOK,
+			expectedBindings: 1,
+			existingKubermaticObjs: []runtime.Object{
+				genUser("Bob", "bob@acme.com", true),
+				test.GenDefaultProject(),
+				genGroupProjectBinding("payments-editors", "team-payments", "my-first-project-ID", "editors"),
+				test.GenBinding("my-first-project-ID", "bob@acme.com", "owners"),
+				derivedBinding,
+			},
+			existingAPIUser: test.GenDefaultAPIUser(),
+		},
+		{
+			name:             "scenario 2: not authorized user cannot delete a group project binding",
+			bindingName:      "payments-editors",
+			expectedResponse: `{"error":{"code":403,"message":"forbidden: \"bob@acme.com\" doesn't have admin rights"}}`,
+			httpStatus:       http.StatusForbidden,
+			expectedBindings: 2,
+			existingKubermaticObjs: []runtime.Object{
+				genUser("Bob", "bob@acme.com", false),
+				test.GenDefaultProject(),
+				genGroupProjectBinding("payments-editors", "team-payments", "my-first-project-ID", "editors"),
+				test.GenBinding("my-first-project-ID", "bob@acme.com", "owners"),
+				derivedBinding,
+			},
+			existingAPIUser: test.GenDefaultAPIUser(),
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest("DELETE", fmt.Sprintf("/api/v1/admin/groupprojectbindings/%s", tc.bindingName), strings.NewReader(""))
+			res := httptest.NewRecorder()
+			ep, clients, err := test.CreateTestEndpointAndGetClients(*tc.existingAPIUser, nil, nil, nil, tc.existingKubermaticObjs, nil, nil, hack.NewTestRouting)
+			if err != nil {
+				t.Fatalf("failed to create test endpoint due to %v", err)
+			}
+
+			ep.ServeHTTP(res, req)
+
+			if res.Code != tc.httpStatus {
+				t.Fatalf("Expected HTTP status code %d, got %d: %s", tc.httpStatus, res.Code, res.Body.String())
+			}
+			test.CompareWithResult(t, res, tc.expectedResponse)
+
+			bindings := &kubermaticv1.UserProjectBindingList{}
+			if err := clients.FakeClient.List(context.Background(), bindings); err != nil {
+				t.Fatal(err)
+			}
+			if len(bindings.Items) != tc.expectedBindings {
+				t.Fatalf("expected %d bindings, got %d", tc.expectedBindings, len(bindings.Items))
+			}
+		})
+	}
+}
+
+func genGroupProjectBinding(name, group, projectID, role string) *kubermaticv1.GroupProjectBinding {
+	return &kubermaticv1.GroupProjectBinding{
+		ObjectMeta: v1.ObjectMeta{Name: name},
+		Spec: kubermaticv1.GroupProjectBindingSpec{
+			Group:     group,
+			ProjectID: projectID,
+			Role:      role,
+		},
+	}
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2020 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/go-kit/kit/endpoint"
+
+	kubermaticv1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
+	"github.com/kubermatic/kubermatic/api/pkg/handler/audit"
+	"github.com/kubermatic/kubermatic/api/pkg/handler/v1/common"
+	"github.com/kubermatic/kubermatic/api/pkg/provider"
+	k8cerrors "github.com/kubermatic/kubermatic/api/pkg/util/errors"
+)
+
+// SetProjectQuotaEndpoint sets the quota of the project, an empty quota removes all limits
+func SetProjectQuotaEndpoint(userInfoGetter provider.UserInfoGetter, privilegedProjectProvider provider.PrivilegedProjectProvider, memberProvider provider.ProjectMemberProvider,
+	userProvider provider.UserProvider, seedsGetter provider.SeedsGetter, clusterProviderGetter provider.ClusterProviderGetter, nodeSizeGetter common.NodeSizeGetter) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req, ok := request.(setProjectQuotaReq)
+		if !ok {
+			return nil, k8cerrors.NewBadRequest("invalid request")
+		}
+		if err := req.Validate(); err != nil {
+			return nil, k8cerrors.NewBadRequest("invalid quota: %v", err)
+		}
+		userInfo, err := userInfoGetter(ctx, "")
+		if err != nil {
+			return nil, common.KubernetesErrorToHTTPError(err)
+		}
+		if !userInfo.IsAdmin {
+			return nil, k8cerrors.New(http.StatusForbidden, fmt.Sprintf("forbidden: \"%s\" doesn't have admin rights", userInfo.Email))
+		}
+
+		project, err := privilegedProjectProvider.GetUnsecured(req.ProjectID, nil)
+		if err != nil {
+			return nil, common.KubernetesErrorToHTTPError(err)
+		}
+
+		usage, err := common.GetProjectResourceUsage(ctx, project, seedsGetter, clusterProviderGetter, nodeSizeGetter)
+		if err != nil {
+			return nil, err
+		}
+
+		audit.RecordBefore(ctx, common.ConvertInternalProjectToExternal(project, nil, usage.Clusters))
+		project.Spec.Quota = nil
+		project.Status.Usage = nil
+		if !isEmptyQuota(req.Body) {
+			project.Spec.Quota = &req.Body
+			project.Status.Usage = usage
+		}
+		project, err = privilegedProjectProvider.UpdateUnsecured(project)
+		if err != nil {
+			return nil, common.KubernetesErrorToHTTPError(err)
+		}
+
+		projectOwners, err := common.GetOwnersForProject(userInfo, project, memberProvider, userProvider)
+		if err != nil {
+			return nil, common.KubernetesErrorToHTTPError(err)
+		}
+		return common.ConvertInternalProjectToExternal(project, projectOwners, usage.Clusters), nil
+	}
+}
+
+func isEmptyQuota(quota kubermaticv1.ProjectQuota) bool {
+	return quota.MaxClusters == nil && quota.MaxNodes == nil && quota.MaxCPUs == nil && quota.MaxMemoryMB == nil
+}
+
+// setProjectQuotaReq defines HTTP request for setProjectQuota
+// swagger:parameters setProjectQuota
+type setProjectQuotaReq struct {
+	common.ProjectReq
+	// in: body
+	Body kubermaticv1.ProjectQuota
+}
+
+// Validate validates setProjectQuota request
+func (r setProjectQuotaReq) Validate() error {
+	limits := map[string]*int{
+		"maxClusters": r.Body.MaxClusters,
+		"maxNodes":    r.Body.MaxNodes,
+		"maxCPUs":     r.Body.MaxCPUs,
+		"maxMemoryMB": r.Body.MaxMemoryMB,
+	}
+	for name, limit := range limits {
+		if limit != nil && *limit < 0 {
+			return fmt.Errorf("%s must not be negative", name)
+		}
+	}
+	return nil
+}
+
+func DecodeSetProjectQuotaReq(c context.Context, r *http.Request) (interface{}, error) {
+	var req setProjectQuotaReq
+
+	projectReq, err := common.DecodeProjectRequest(c, r)
+	if err != nil {
+		return nil, err
+	}
+	req.ProjectReq = projectReq.(common.ProjectReq)
+
+	if err := json.NewDecoder(r.Body).Decode(&req.Body); err != nil {
+		return nil, k8cerrors.NewBadRequest("unable to parse the quota: %v", err)
+	}
+
+	return req, nil
+}
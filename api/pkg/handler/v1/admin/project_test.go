@@ -0,0 +1,102 @@
+/*
+Copyright 2020 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admin_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	apiv1 "github.com/kubermatic/kubermatic/api/pkg/api/v1"
+	"github.com/kubermatic/kubermatic/api/pkg/handler/test"
+	"github.com/kubermatic/kubermatic/api/pkg/handler/test/hack"
+
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestSetProjectQuotaEndpoint(t *testing.T) {
+	t.Parallel()
+	testcases := []struct {
+		name                   string
+		body                   string
+		expectedResponse       string
+		httpStatus             int
+		existingAPIUser        *apiv1.User
+		existingKubermaticObjs []runtime.Object
+	}{
+		// scenario 1
+		{
+			name:                   "scenario 1: not authorized user sets the project quota",
+			body:                   `{"maxClusters":1}`,
+			expectedResponse:       `{"error":{"code":403,"message":"forbidden: \"bob@acme.com\" doesn't have admin rights"}}`,
+			httpStatus:             http.StatusForbidden,
+			existingKubermaticObjs: test.GenDefaultKubermaticObjects(),
+			existingAPIUser:        test.GenDefaultAPIUser(),
+		},
+		// scenario 2
+		{
+			name:                   "scenario 2: a negative quota is rejected",
+			body:                   `{"maxNodes":-1}`,
+			expectedResponse:       `{"error":{"code":400,"message":"invalid quota: maxNodes must not be negative"}}`,
+			httpStatus:             http.StatusBadRequest,
+			existingKubermaticObjs: test.GenDefaultKubermaticObjects(genUser("John", "john@acme.com", true)),
+			existingAPIUser:        test.GenAPIUser("John", "john@acme.com"),
+		},
+		// scenario 3
+		{
+			name:             "scenario 3: authorized user sets the project quota",
+			body:             `{"maxClusters":3,"maxNodes":10}`,
+			expectedResponse: `{"id":"my-first-project-ID","name":"my-first-project","creationTimestamp":"2013-02-03T19:54:00Z","status":"Active","owners":[{"name":"Bob","creationTimestamp":"0001-01-01T00:00:00Z","email":"bob@acme.com"}],"clustersNumber":1,"quota":{"maxClusters":3,"maxNodes":10},"usage":{"clusters":1,"nodes":0,"cpus":0,"memoryMB":0}}`,
+			httpStatus:       http.StatusOK,
+			existingKubermaticObjs: test.GenDefaultKubermaticObjects(
+				genUser("John", "john@acme.com", true),
+				test.GenDefaultCluster(),
+			),
+			existingAPIUser: test.GenAPIUser("John", "john@acme.com"),
+		},
+		// scenario 4
+		{
+			name:                   "scenario 4: an empty quota removes the project quota",
+			body:                   `{}`,
+			expectedResponse:       `{"id":"my-first-project-ID","name":"my-first-project","creationTimestamp":"2013-02-03T19:54:00Z","status":"Active","owners":[{"name":"Bob","creationTimestamp":"0001-01-01T00:00:00Z","email":"bob@acme.com"}]}`,
+			httpStatus:             http.StatusOK,
+			existingKubermaticObjs: test.GenDefaultKubermaticObjects(genUser("John", "john@acme.com", true)),
+			existingAPIUser:        test.GenAPIUser("John", "john@acme.com"),
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest("PUT", fmt.Sprintf("/api/v1/admin/projects/%s/quota", test.GenDefaultProject().Name), strings.NewReader(tc.body))
+			res := httptest.NewRecorder()
+			ep, err := test.CreateTestEndpoint(*tc.existingAPIUser, []runtime.Object{}, tc.existingKubermaticObjs, nil, nil, hack.NewTestRouting)
+			if err != nil {
+				t.Fatalf("failed to create test endpoint due to %v", err)
+			}
+
+			ep.ServeHTTP(res, req)
+
+			if res.Code != tc.httpStatus {
+				t.Fatalf("Expected HTTP status code %d, got %d: %s", tc.httpStatus, res.Code, res.Body.String())
+			}
+
+			test.CompareWithResult(t, res, tc.expectedResponse)
+		})
+	}
+}
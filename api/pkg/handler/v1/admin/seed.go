@@ -31,6 +31,7 @@ import (
 
 	apiv1 "github.com/kubermatic/kubermatic/api/pkg/api/v1"
 	kubermaticv1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
+	"github.com/kubermatic/kubermatic/api/pkg/handler/audit"
 	"github.com/kubermatic/kubermatic/api/pkg/handler/v1/common"
 	"github.com/kubermatic/kubermatic/api/pkg/handler/v1/dc"
 	"github.com/kubermatic/kubermatic/api/pkg/log"
@@ -102,6 +103,10 @@ func UpdateSeedEndpoint(userInfoGetter provider.UserInfoGetter, seedsGetter prov
 		if err != nil {
 			return nil, common.KubernetesErrorToHTTPError(err)
 		}
+		audit.RecordBefore(ctx, apiv1.Seed{
+			Name:     seed.Name,
+			SeedSpec: convertSeedSpec(seed.Spec, seed.Name),
+		})
 		oldSeed := seed.DeepCopy()
 		seed.Spec = req.Body.Spec
 
@@ -27,6 +27,7 @@ import (
 
 	v1 "github.com/kubermatic/kubermatic/api/pkg/api/v1"
 	kubermaticv1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
+	"github.com/kubermatic/kubermatic/api/pkg/handler/audit"
 	"github.com/kubermatic/kubermatic/api/pkg/handler/v1/common"
 	"github.com/kubermatic/kubermatic/api/pkg/provider"
 	"github.com/kubermatic/kubermatic/api/pkg/util/errors"
@@ -69,6 +70,7 @@ func UpdateKubermaticSettingsEndpoint(userInfoGetter provider.UserInfoGetter, se
 		if err != nil {
 			return nil, common.KubernetesErrorToHTTPError(err)
 		}
+		audit.RecordBefore(ctx, existingGlobalSettings.Spec)
 		existingGlobalSettingsSpecJSON, err := json.Marshal(existingGlobalSettings.Spec)
 		if err != nil {
 			return nil, errors.NewBadRequest("cannot decode existing settings: %v", err)
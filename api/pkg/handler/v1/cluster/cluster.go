@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -34,6 +35,7 @@ import (
 
 	apiv1 "github.com/kubermatic/kubermatic/api/pkg/api/v1"
 	kubermaticv1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
+	"github.com/kubermatic/kubermatic/api/pkg/handler/audit"
 	"github.com/kubermatic/kubermatic/api/pkg/handler/middleware"
 	"github.com/kubermatic/kubermatic/api/pkg/handler/v1/common"
 	"github.com/kubermatic/kubermatic/api/pkg/handler/v1/label"
@@ -75,7 +77,8 @@ var clusterTypes = sets.NewString(apiv1.OpenShiftClusterType, apiv1.KubernetesCl
 
 func CreateEndpoint(sshKeyProvider provider.SSHKeyProvider, projectProvider provider.ProjectProvider, privilegedProjectProvider provider.PrivilegedProjectProvider, seedsGetter provider.SeedsGetter,
 	initNodeDeploymentFailures *prometheus.CounterVec, eventRecorderProvider provider.EventRecorderProvider, credentialManager provider.PresetProvider,
-	exposeStrategy corev1.ServiceType, userInfoGetter provider.UserInfoGetter, settingsProvider provider.SettingsProvider, updateManager common.UpdateManager) endpoint.Endpoint {
+	exposeStrategy corev1.ServiceType, userInfoGetter provider.UserInfoGetter, settingsProvider provider.SettingsProvider, updateManager common.UpdateManager,
+	clusterProviderGetter provider.ClusterProviderGetter, nodeSizeGetter common.NodeSizeGetter) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		req := request.(CreateReq)
 		globalSettings, err := settingsProvider.GetGlobalSettings()
@@ -138,6 +141,9 @@ func CreateEndpoint(sshKeyProvider provider.SSHKeyProvider, projectProvider prov
 		if err = validation.ValidateUpdateWindow(spec.UpdateWindow); err != nil {
 			return nil, common.KubernetesErrorToHTTPError(err)
 		}
+		if err := validation.ValidateBackupPolicy(spec.BackupPolicy, seed); err != nil {
+			return nil, errors.NewBadRequest("invalid backup policy: %v", err)
+		}
 		partialCluster := &kubermaticv1.Cluster{}
 		partialCluster.Labels = req.Body.Cluster.Labels
 		partialCluster.Spec = *spec
@@ -152,9 +158,7 @@ func CreateEndpoint(sshKeyProvider provider.SSHKeyProvider, projectProvider prov
 
 		// Enforce audit logging
 		if dc.Spec.EnforceAuditLogging {
-			partialCluster.Spec.AuditLogging = &kubermaticv1.AuditLoggingSettings{
-				Enabled: true,
-			}
+			partialCluster.Spec.AuditLogging = enforceAuditLogging(partialCluster.Spec.AuditLogging, dc)
 		}
 
 		// Enforce PodSecurityPolicy
@@ -169,6 +173,32 @@ func CreateEndpoint(sshKeyProvider provider.SSHKeyProvider, projectProvider prov
 			partialCluster.Spec.Features = map[string]bool{kubermaticv1.ClusterFeatureExternalCloudProvider: true}
 		}
 
+		// for BringYourOwn provider we don't create ND
+		createNodeDeployment := false
+		if req.Body.NodeDeployment != nil && req.Body.NodeDeployment.Spec.Replicas > 0 {
+			isBYO, err := common.IsBringYourOwnProvider(spec.Cloud)
+			if err != nil {
+				return nil, errors.NewBadRequest("failed to create an initial node deployment due to an invalid spec: %v", err)
+			}
+			createNodeDeployment = !isBYO
+		}
+
+		// Enforce the project quota, the nodes of the initial node deployment are counted as well
+		var quotaChange common.QuotaChange
+		var initialNodeSize *common.NodeSize
+		if createNodeDeployment {
+			initialNodeSize, err = nodeSizeGetter(ctx, partialCluster, dc, secretKeyGetter, &req.Body.NodeDeployment.Spec.Template)
+			if err != nil {
+				klog.V(4).Infof("failed to get the node size of the initial node deployment: %v", err)
+			}
+			quotaChange = common.NodesQuotaChange(int(req.Body.NodeDeployment.Spec.Replicas), initialNodeSize)
+		}
+		quotaChange.Clusters = 1
+		usage, err := common.CheckProjectQuota(ctx, project, quotaChange, seedsGetter, clusterProviderGetter, nodeSizeGetter)
+		if err != nil {
+			return nil, err
+		}
+
 		if err := kubernetesprovider.CreateOrUpdateCredentialSecretForCluster(ctx, privilegedClusterProvider.GetSeedClusterAdminRuntimeClient(), partialCluster); err != nil {
 			return nil, err
 		}
@@ -178,15 +208,11 @@ func CreateEndpoint(sshKeyProvider provider.SSHKeyProvider, projectProvider prov
 		if err != nil {
 			return nil, common.KubernetesErrorToHTTPError(err)
 		}
+		common.UpdateProjectResourceUsage(privilegedProjectProvider, project, usage)
 
 		// Create the initial node deployment in the background.
 		if req.Body.NodeDeployment != nil && req.Body.NodeDeployment.Spec.Replicas > 0 {
-			// for BringYourOwn provider we don't create ND
-			isBYO, err := common.IsBringYourOwnProvider(spec.Cloud)
-			if err != nil {
-				return nil, errors.NewBadRequest("failed to create an initial node deployment due to an invalid spec: %v", err)
-			}
-			if !isBYO {
+			if createNodeDeployment {
 				go func() {
 					defer utilruntime.HandleCrash()
 					ndName := getNodeDeploymentDisplayName(req.Body.NodeDeployment)
@@ -422,6 +448,7 @@ func PatchEndpoint(projectProvider provider.ProjectProvider, privilegedProjectPr
 
 		// Converting to API type as it is the type exposed externally.
 		externalCluster := convertInternalClusterToExternal(oldInternalCluster, false)
+		audit.RecordBefore(ctx, externalCluster)
 
 		// Changing the type to patchCluster as during marshalling it doesn't remove the cloud provider authentication
 		// data that is required here for validation.
@@ -462,6 +489,8 @@ func PatchEndpoint(projectProvider provider.ProjectProvider, privilegedProjectPr
 		newInternalCluster.Spec.AuditLogging = patchedCluster.Spec.AuditLogging
 		newInternalCluster.Spec.Openshift = patchedCluster.Spec.Openshift
 		newInternalCluster.Spec.UpdateWindow = patchedCluster.Spec.UpdateWindow
+		newInternalCluster.Spec.BackupPolicy = patchedCluster.Spec.BackupPolicy
+		newInternalCluster.Spec.EncryptionAtRest = patchedCluster.Spec.EncryptionAtRest
 
 		incompatibleKubelets, err := common.CheckClusterVersionSkew(ctx, userInfoGetter, clusterProvider, newInternalCluster, req.ProjectID)
 		if err != nil {
@@ -475,7 +504,7 @@ func PatchEndpoint(projectProvider provider.ProjectProvider, privilegedProjectPr
 		if err != nil {
 			return nil, errors.New(http.StatusInternalServerError, err.Error())
 		}
-		_, dc, err := provider.DatacenterFromSeedMap(userInfo, seedsGetter, newInternalCluster.Spec.Cloud.DatacenterName)
+		seed, dc, err := provider.DatacenterFromSeedMap(userInfo, seedsGetter, newInternalCluster.Spec.Cloud.DatacenterName)
 		if err != nil {
 			return nil, fmt.Errorf("error getting dc: %v", err)
 		}
@@ -486,9 +515,7 @@ func PatchEndpoint(projectProvider provider.ProjectProvider, privilegedProjectPr
 
 		// Enforce audit logging
 		if dc.Spec.EnforceAuditLogging {
-			newInternalCluster.Spec.AuditLogging = &kubermaticv1.AuditLoggingSettings{
-				Enabled: true,
-			}
+			newInternalCluster.Spec.AuditLogging = enforceAuditLogging(newInternalCluster.Spec.AuditLogging, dc)
 		}
 
 		// Enforce PodSecurityPolicy
@@ -506,6 +533,9 @@ func PatchEndpoint(projectProvider provider.ProjectProvider, privilegedProjectPr
 		if err = validation.ValidateUpdateWindow(newInternalCluster.Spec.UpdateWindow); err != nil {
 			return nil, common.KubernetesErrorToHTTPError(err)
 		}
+		if err := validation.ValidateBackupPolicy(newInternalCluster.Spec.BackupPolicy, seed); err != nil {
+			return nil, errors.NewBadRequest("invalid backup policy: %v", err)
+		}
 
 		updatedCluster, err := updateCluster(ctx, userInfoGetter, clusterProvider, privilegedClusterProvider, project, newInternalCluster)
 		if err != nil {
@@ -516,6 +546,38 @@ func PatchEndpoint(projectProvider provider.ProjectProvider, privilegedProjectPr
 	}
 }
 
+// enforceAuditLogging enables audit logging and raises the audit policy to the minimum preset of the datacenter.
+// The other settings of the cluster, like the webhook backend, are kept.
+func enforceAuditLogging(settings *kubermaticv1.AuditLoggingSettings, dc *kubermaticv1.Datacenter) *kubermaticv1.AuditLoggingSettings {
+	enforced := &kubermaticv1.AuditLoggingSettings{}
+	if settings != nil {
+		enforced = settings.DeepCopy()
+	}
+	enforced.Enabled = true
+
+	if dc.Spec.EnforcedAuditPolicyPreset == "" {
+		return enforced
+	}
+
+	// a custom policy can not be compared with the presets
+	enforced.CustomPolicyConfigMap = ""
+	if auditPolicyPresetLevel(enforced.PolicyPreset) < auditPolicyPresetLevel(dc.Spec.EnforcedAuditPolicyPreset) {
+		enforced.PolicyPreset = dc.Spec.EnforcedAuditPolicyPreset
+	}
+
+	return enforced
+}
+
+func auditPolicyPresetLevel(preset kubermaticv1.AuditPolicyPreset) int {
+	for level, p := range kubermaticv1.AuditPolicyPresets {
+		if p == preset {
+			return level
+		}
+	}
+	// an empty preset defaults to the least detailed one
+	return 0
+}
+
 func updateCluster(ctx context.Context, userInfoGetter provider.UserInfoGetter, clusterProvider provider.ClusterProvider, privilegedClusterProvider provider.PrivilegedClusterProvider, project *kubermaticv1.Project, cluster *kubermaticv1.Cluster) (*kubermaticv1.Cluster, error) {
 	adminUserInfo, err := userInfoGetter(ctx, "")
 	if err != nil {
@@ -544,36 +606,53 @@ func ListEndpoint(projectProvider provider.ProjectProvider, privilegedProjectPro
 	}
 }
 
-// ListAllEndpoint list clusters for the given project in all datacenters
-func ListAllEndpoint(projectProvider provider.ProjectProvider, privilegedProjectProvider provider.PrivilegedProjectProvider, seedsGetter provider.SeedsGetter, clusterProviderGetter provider.ClusterProviderGetter, userInfoGetter provider.UserInfoGetter) endpoint.Endpoint {
+// SeedErrorHeader is the response header of ListAllEndpoint that reports a seed whose clusters couldn't
+// be listed as "<seed>: <message>", the header is set once for every such seed. The message is the same
+// for all seeds, the actual error is logged as it might reveal details of the seed to project members.
+const SeedErrorHeader = "Seed-Error"
+
+// seedErrorMessage is reported in the SeedErrorHeader for every seed whose clusters couldn't be listed
+const seedErrorMessage = "the clusters of this seed might be missing or outdated"
+
+// ProjectClusterList is the response of ListAllEndpoint. The clusters are encoded as JSON array, while
+// the seed errors are reported in the SeedErrorHeader headers so that the response stays a cluster list
+type ProjectClusterList struct {
+	Clusters   apiv1.ClusterList
+	SeedErrors []string
+}
+
+// ListAllEndpoint list clusters for the given project in all datacenters. The clusters are served from
+// a master-side cache, the seeds that can't be reached are reported along with the clusters of the other seeds
+func ListAllEndpoint(projectProvider provider.ProjectProvider, privilegedProjectProvider provider.PrivilegedProjectProvider, projectClusterLister provider.ProjectClusterLister, userInfoGetter provider.UserInfoGetter) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		req := request.(common.GetProjectRq)
-		allClusters := make([]*apiv1.Cluster, 0)
 
-		seeds, err := seedsGetter()
+		project, err := common.GetProject(ctx, userInfoGetter, projectProvider, privilegedProjectProvider, req.ProjectID, nil)
 		if err != nil {
 			return nil, common.KubernetesErrorToHTTPError(err)
 		}
 
-		for _, seed := range seeds {
-			// if a Seed is bad, do not forward that error to the user, but only log
-			clusterProvider, err := clusterProviderGetter(seed)
-			if err != nil {
-				klog.Errorf("failed to create cluster provider for seed %s: %v", seed.Name, err)
-				continue
-			}
-			apiClusters, err := getClusters(ctx, userInfoGetter, clusterProvider, projectProvider, privilegedProjectProvider, req.ProjectID)
-			if err != nil {
-				return nil, common.KubernetesErrorToHTTPError(err)
-			}
-			allClusters = append(allClusters, apiClusters...)
+		clusters, seedErrors, err := projectClusterLister.List(project.Name)
+		if err != nil {
+			return nil, common.KubernetesErrorToHTTPError(err)
 		}
 
-		return allClusters, nil
+		result := ProjectClusterList{Clusters: apiv1.ClusterList{}}
+		for _, cluster := range clusters {
+			result.Clusters = append(result.Clusters, *convertInternalClusterToExternal(cluster, true))
+		}
+		for seedName, err := range seedErrors {
+			// if a Seed is bad, the clusters of the other seeds are still returned
+			klog.Errorf("failed to list the clusters of seed %s: %v", seedName, err)
+			result.SeedErrors = append(result.SeedErrors, fmt.Sprintf("%s: %s", seedName, seedErrorMessage))
+		}
+		sort.Strings(result.SeedErrors)
+		return result, nil
 	}
 }
 
-func DeleteEndpoint(sshKeyProvider provider.SSHKeyProvider, privilegedSSHKeyProvider provider.PrivilegedSSHKeyProvider, projectProvider provider.ProjectProvider, privilegedProjectProvider provider.PrivilegedProjectProvider, userInfoGetter provider.UserInfoGetter) endpoint.Endpoint {
+func DeleteEndpoint(sshKeyProvider provider.SSHKeyProvider, privilegedSSHKeyProvider provider.PrivilegedSSHKeyProvider, projectProvider provider.ProjectProvider, privilegedProjectProvider provider.PrivilegedProjectProvider, userInfoGetter provider.UserInfoGetter,
+	seedsGetter provider.SeedsGetter, clusterProviderGetter provider.ClusterProviderGetter, nodeSizeGetter common.NodeSizeGetter) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		req := request.(DeleteReq)
 		clusterProvider := ctx.Value(middleware.ClusterProviderContextKey).(provider.ClusterProvider)
@@ -613,7 +692,11 @@ func DeleteEndpoint(sshKeyProvider provider.SSHKeyProvider, privilegedSSHKeyProv
 			}
 		}
 
-		return nil, updateAndDeleteCluster(ctx, userInfoGetter, clusterProvider, privilegedClusterProvider, project, existingCluster)
+		if err := updateAndDeleteCluster(ctx, userInfoGetter, clusterProvider, privilegedClusterProvider, project, existingCluster); err != nil {
+			return nil, err
+		}
+		common.RefreshProjectResourceUsage(ctx, project, privilegedProjectProvider, seedsGetter, clusterProviderGetter, nodeSizeGetter)
+		return nil, nil
 	}
 }
 
@@ -732,15 +815,19 @@ func HealthEndpoint(projectProvider provider.ProjectProvider, privilegedProjectP
 		if err != nil {
 			return nil, common.KubernetesErrorToHTTPError(err)
 		}
-		return apiv1.ClusterHealth{
-			Apiserver:                    existingCluster.Status.ExtendedHealth.Apiserver,
-			Scheduler:                    existingCluster.Status.ExtendedHealth.Scheduler,
-			Controller:                   existingCluster.Status.ExtendedHealth.Controller,
-			MachineController:            existingCluster.Status.ExtendedHealth.MachineController,
-			Etcd:                         existingCluster.Status.ExtendedHealth.Etcd,
-			CloudProviderInfrastructure:  existingCluster.Status.ExtendedHealth.CloudProviderInfrastructure,
-			UserClusterControllerManager: existingCluster.Status.ExtendedHealth.UserClusterControllerManager,
-		}, nil
+		return convertInternalClusterHealthToExternal(existingCluster.Status.ExtendedHealth), nil
+	}
+}
+
+func convertInternalClusterHealthToExternal(health kubermaticv1.ExtendedClusterHealth) apiv1.ClusterHealth {
+	return apiv1.ClusterHealth{
+		Apiserver:                    health.Apiserver,
+		Scheduler:                    health.Scheduler,
+		Controller:                   health.Controller,
+		MachineController:            health.MachineController,
+		Etcd:                         health.Etcd,
+		CloudProviderInfrastructure:  health.CloudProviderInfrastructure,
+		UserClusterControllerManager: health.UserClusterControllerManager,
 	}
 }
 
@@ -870,10 +957,13 @@ func convertInternalClusterToExternal(internalCluster *kubermaticv1.Cluster, fil
 			UsePodSecurityPolicyAdmissionPlugin: internalCluster.Spec.UsePodSecurityPolicyAdmissionPlugin,
 			UsePodNodeSelectorAdmissionPlugin:   internalCluster.Spec.UsePodNodeSelectorAdmissionPlugin,
 			AdmissionPlugins:                    internalCluster.Spec.AdmissionPlugins,
+			BackupPolicy:                        internalCluster.Spec.BackupPolicy,
+			EncryptionAtRest:                    internalCluster.Spec.EncryptionAtRest,
 		},
 		Status: apiv1.ClusterStatus{
 			Version: internalCluster.Spec.Version,
 			URL:     internalCluster.Address.URL,
+			Backup:  common.ConvertInternalEtcdBackupStatusToExternal(internalCluster.Status.Backup),
 		},
 		Type: apiv1.KubernetesClusterType,
 	}
@@ -888,6 +978,15 @@ func convertInternalClusterToExternal(internalCluster *kubermaticv1.Cluster, fil
 	return cluster
 }
 
+// ConvertInternalClusterToState converts the given cluster to the state streamed by the websocket API
+func ConvertInternalClusterToState(internalCluster *kubermaticv1.Cluster) *apiv1.ClusterState {
+	return &apiv1.ClusterState{
+		Cluster:    convertInternalClusterToExternal(internalCluster, true),
+		Health:     convertInternalClusterHealthToExternal(internalCluster.Status.ExtendedHealth),
+		Conditions: internalCluster.Status.Conditions,
+	}
+}
+
 func convertInternalClustersToExternal(internalClusters []kubermaticv1.Cluster) []*apiv1.Cluster {
 	apiClusters := make([]*apiv1.Cluster, len(internalClusters))
 	for index, cluster := range internalClusters {
@@ -1060,6 +1159,11 @@ type AssignSSHKeysReq struct {
 	KeyID string `json:"key_id"`
 }
 
+// GetClusterID returns the ID of the cluster the request is about
+func (r AssignSSHKeysReq) GetClusterID() string {
+	return r.ClusterID
+}
+
 // ListSSHKeysReq defines HTTP request data for listSSHKeysAssignedToCluster endpoint
 // swagger:parameters listSSHKeysAssignedToCluster
 type ListSSHKeysReq struct {
@@ -1068,6 +1172,11 @@ type ListSSHKeysReq struct {
 	ClusterID string `json:"cluster_id"`
 }
 
+// GetClusterID returns the ID of the cluster the request is about
+func (r ListSSHKeysReq) GetClusterID() string {
+	return r.ClusterID
+}
+
 // DetachSSHKeysReq defines HTTP request for detachSSHKeyFromCluster endpoint
 // swagger:parameters detachSSHKeyFromCluster
 type DetachSSHKeysReq struct {
@@ -1078,6 +1187,11 @@ type DetachSSHKeysReq struct {
 	ClusterID string `json:"cluster_id"`
 }
 
+// GetClusterID returns the ID of the cluster the request is about
+func (r DetachSSHKeysReq) GetClusterID() string {
+	return r.ClusterID
+}
+
 // CreateReq defines HTTP request for createCluster endpoint
 // swagger:parameters createCluster
 type CreateReq struct {
@@ -1269,6 +1383,11 @@ type AdminTokenReq struct {
 	ClusterID string `json:"cluster_id"`
 }
 
+// GetClusterID returns the ID of the cluster the request is about
+func (r AdminTokenReq) GetClusterID() string {
+	return r.ClusterID
+}
+
 func DecodeAdminTokenReq(c context.Context, r *http.Request) (interface{}, error) {
 	var req AdminTokenReq
 	clusterID, err := common.DecodeClusterID(c, r)
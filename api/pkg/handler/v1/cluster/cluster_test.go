@@ -26,11 +26,14 @@ import (
 	"testing"
 	"time"
 
+	"github.com/go-test/deep"
+
 	apiv1 "github.com/kubermatic/kubermatic/api/pkg/api/v1"
 	kubermaticv1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
 	"github.com/kubermatic/kubermatic/api/pkg/handler/test"
 	"github.com/kubermatic/kubermatic/api/pkg/handler/test/hack"
 	kuberneteshelper "github.com/kubermatic/kubermatic/api/pkg/kubernetes"
+	"github.com/kubermatic/kubermatic/api/pkg/provider"
 	"github.com/kubermatic/kubermatic/api/pkg/semver"
 	clusterv1alpha1 "github.com/kubermatic/machine-controller/pkg/apis/cluster/v1alpha1"
 
@@ -918,6 +921,71 @@ func TestCreateClusterEndpoint(t *testing.T) {
 			ProjectToSync:          test.GenDefaultProject().Name,
 			ExistingAPIUser:        test.GenDefaultAPIUser(),
 		},
+		// scenario 15
+		{
+			Name:             "scenario 15: create a cluster in audit-logging-enforced datacenter, keeping the audit policy and webhook",
+			Body:             `{"cluster":{"name":"keen-snyder","spec":{"version":"1.15.0","cloud":{"fake":{"token":"dummy_token"},"dc":"audited-dc"},"auditLogging":{"policyPreset":"recommended","webhook":{"url":"https://audit.example.com"}}}}}`,
+			ExpectedResponse: `{"id":"%s","name":"keen-snyder","creationTimestamp":"0001-01-01T00:00:00Z","type":"kubernetes","spec":{"cloud":{"dc":"audited-dc","fake":{}},"version":"1.15.0","oidc":{},"auditLogging":{"enabled":true,"policyPreset":"recommended","webhook":{"url":"https://audit.example.com"}}},"status":{"version":"1.15.0","url":""}}`,
+			RewriteClusterID: true,
+			HTTPStatus:       http.StatusCreated,
+			ProjectToSync:    test.GenDefaultProject().Name,
+			ExistingKubermaticObjs: test.GenDefaultKubermaticObjects(
+				test.GenUser(test.UserID2, test.UserName2, test.UserEmail2),
+				test.GenBinding(test.GenDefaultProject().Name, test.UserEmail2, "editors"),
+			),
+			ExistingAPIUser: test.GenAPIUser(test.UserName2, test.UserEmail2),
+		},
+		// scenario 16
+		{
+			Name:                   "scenario 16: a cluster with an unknown audit policy preset",
+			Body:                   `{"cluster":{"name":"keen-snyder","spec":{"version":"1.15.0","cloud":{"fake":{"token":"dummy_token"},"dc":"fake-dc"},"auditLogging":{"enabled":true,"policyPreset":"everything"}}}}`,
+			ExpectedResponse:       `{"error":{"code":400,"message":"invalid cluster: invalid audit logging settings: unknown audit policy preset \"everything\""}}`,
+			HTTPStatus:             http.StatusBadRequest,
+			ExistingKubermaticObjs: test.GenDefaultKubermaticObjects(),
+			ProjectToSync:          test.GenDefaultProject().Name,
+			ExistingAPIUser:        test.GenDefaultAPIUser(),
+		},
+		// scenario 17
+		{
+			Name:             "scenario 17: a cluster exceeding the cluster quota of the project is rejected",
+			Body:             `{"cluster":{"name":"keen-snyder","spec":{"version":"1.15.0","cloud":{"fake":{"token":"dummy_token"},"dc":"fake-dc"}}}}`,
+			ExpectedResponse: `{"error":{"code":403,"message":"forbidden: project my-first-project-ID would use 2 clusters, but its quota allows only 1"}}`,
+			HTTPStatus:       http.StatusForbidden,
+			ExistingProject: func() *kubermaticv1.Project {
+				project := test.GenDefaultProject()
+				maxClusters := 1
+				project.Spec.Quota = &kubermaticv1.ProjectQuota{MaxClusters: &maxClusters}
+				return project
+			}(),
+			ProjectToSync: test.GenDefaultProject().Name,
+			ExistingKubermaticObjs: []runtime.Object{
+				test.GenDefaultUser(),
+				test.GenDefaultOwnerBinding(),
+				test.GenDefaultCluster(),
+			},
+			ExistingAPIUser: test.GenDefaultAPIUser(),
+		},
+		// scenario 18
+		{
+			Name:             "scenario 18: a cluster within the cluster quota of the project is created",
+			Body:             `{"cluster":{"name":"keen-snyder","spec":{"version":"1.15.0","cloud":{"fake":{"token":"dummy_token"},"dc":"fake-dc"}}}}`,
+			ExpectedResponse: `{"id":"%s","name":"keen-snyder","creationTimestamp":"0001-01-01T00:00:00Z","type":"kubernetes","spec":{"cloud":{"dc":"fake-dc","fake":{}},"version":"1.15.0","oidc":{}},"status":{"version":"1.15.0","url":""}}`,
+			RewriteClusterID: true,
+			HTTPStatus:       http.StatusCreated,
+			ExistingProject: func() *kubermaticv1.Project {
+				project := test.GenDefaultProject()
+				maxClusters := 2
+				project.Spec.Quota = &kubermaticv1.ProjectQuota{MaxClusters: &maxClusters}
+				return project
+			}(),
+			ProjectToSync: test.GenDefaultProject().Name,
+			ExistingKubermaticObjs: []runtime.Object{
+				test.GenDefaultUser(),
+				test.GenDefaultOwnerBinding(),
+				test.GenDefaultCluster(),
+			},
+			ExistingAPIUser: test.GenDefaultAPIUser(),
+		},
 	}
 
 	for _, tc := range testcases {
@@ -1542,9 +1610,11 @@ func TestListClustersForProject(t *testing.T) {
 	testcases := []struct {
 		Name                   string
 		ExpectedClusters       []apiv1.Cluster
+		ExpectedSeedErrors     []string
 		HTTPStatus             int
 		ExistingAPIUser        *apiv1.User
 		ExistingKubermaticObjs []runtime.Object
+		SeedsGetter            provider.SeedsGetter
 	}{
 		// scenario 1
 		{
@@ -1663,6 +1733,47 @@ func TestListClustersForProject(t *testing.T) {
 			),
 			ExistingAPIUser: test.GenAPIUser("John", "john@acme.com"),
 		},
+		// scenario 3
+		{
+			Name: "scenario 3: the clusters of the reachable seeds are listed along with the errors of the other seeds",
+			ExpectedClusters: []apiv1.Cluster{
+				{
+					ObjectMeta: apiv1.ObjectMeta{
+						ID:                "clusterAbcID",
+						Name:              "clusterAbc",
+						CreationTimestamp: apiv1.Date(2013, 02, 03, 19, 54, 0, 0, time.UTC),
+					},
+					Spec: apiv1.ClusterSpec{
+						Cloud: kubermaticv1.CloudSpec{
+							DatacenterName: "FakeDatacenter",
+							Fake:           &kubermaticv1.FakeCloudSpec{},
+						},
+						Version: *semver.NewSemverOrDie("9.9.9"),
+					},
+					Status: apiv1.ClusterStatus{
+						Version: *semver.NewSemverOrDie("9.9.9"),
+						URL:     "https://w225mx4z66.asia-east1-a-1.cloud.kubermatic.io:31885",
+					},
+					Type: "kubernetes",
+				},
+			},
+			ExpectedSeedErrors: []string{
+				"europe-west3: the clusters of this seed might be missing or outdated",
+			},
+			HTTPStatus: http.StatusOK,
+			ExistingKubermaticObjs: test.GenDefaultKubermaticObjects(
+				test.GenCluster("clusterAbcID", "clusterAbc", test.GenDefaultProject().Name, time.Date(2013, 02, 03, 19, 54, 0, 0, time.UTC)),
+			),
+			ExistingAPIUser: test.GenDefaultAPIUser(),
+			SeedsGetter: func() (map[string]*kubermaticv1.Seed, error) {
+				unreachableSeed := test.GenTestSeed()
+				unreachableSeed.Name = "europe-west3"
+				return map[string]*kubermaticv1.Seed{
+					"us-central1":  test.GenTestSeed(),
+					"europe-west3": unreachableSeed,
+				}, nil
+			},
+		},
 	}
 
 	for _, tc := range testcases {
@@ -1671,7 +1782,7 @@ func TestListClustersForProject(t *testing.T) {
 			res := httptest.NewRecorder()
 			var kubermaticObj []runtime.Object
 			kubermaticObj = append(kubermaticObj, tc.ExistingKubermaticObjs...)
-			ep, err := test.CreateTestEndpoint(*tc.ExistingAPIUser, []runtime.Object{}, kubermaticObj, nil, nil, hack.NewTestRouting)
+			ep, _, err := test.CreateTestEndpointAndGetClients(*tc.ExistingAPIUser, tc.SeedsGetter, []runtime.Object{}, nil, kubermaticObj, nil, nil, hack.NewTestRouting)
 			if err != nil {
 				t.Fatalf("failed to create test endpoint due to %v", err)
 			}
@@ -1682,6 +1793,10 @@ func TestListClustersForProject(t *testing.T) {
 				t.Fatalf("Expected HTTP status code %d, got %d: %s", tc.HTTPStatus, res.Code, res.Body.String())
 			}
 
+			if seedErrorsDiff := deep.Equal(res.Header()["Seed-Error"], tc.ExpectedSeedErrors); seedErrorsDiff != nil {
+				t.Fatalf("unexpected seed errors, diff = %v", seedErrorsDiff)
+			}
+
 			actualClusters := test.NewClusterV1SliceWrapper{}
 			actualClusters.DecodeOrDie(res.Body, t).Sort()
 
@@ -77,11 +77,15 @@ func GetAdminKubeconfigEndpoint(projectProvider provider.ProjectProvider, privil
 		if err != nil {
 			return nil, common.KubernetesErrorToHTTPError(err)
 		}
-		if strings.HasPrefix(userInfo.Group, "viewers") {
+		switch {
+		case strings.HasPrefix(userInfo.Group, "viewers"):
 			filePrefix = "viewer"
 			adminClientCfg, err = clusterProvider.GetViewerKubeconfigForCustomerCluster(cluster)
-		} else {
+		case strings.HasPrefix(userInfo.Group, "owners"), strings.HasPrefix(userInfo.Group, "editors"):
 			adminClientCfg, err = clusterProvider.GetAdminKubeconfigForCustomerCluster(cluster)
+		default:
+			// members of custom project roles have no permissions inside of the user clusters
+			return nil, kcerrors.New(http.StatusForbidden, fmt.Sprintf("forbidden: the group %s has no access to the cluster", userInfo.Group))
 		}
 		if err != nil {
 			return nil, common.KubernetesErrorToHTTPError(err)
@@ -28,6 +28,7 @@ import (
 	"github.com/gorilla/mux"
 
 	apiv1 "github.com/kubermatic/kubermatic/api/pkg/api/v1"
+	"github.com/kubermatic/kubermatic/api/pkg/handler/audit"
 	"github.com/kubermatic/kubermatic/api/pkg/handler/middleware"
 	"github.com/kubermatic/kubermatic/api/pkg/handler/v1/common"
 	"github.com/kubermatic/kubermatic/api/pkg/provider"
@@ -125,6 +126,11 @@ type createClusterRoleReq struct {
 	Body apiv1.ClusterRole
 }
 
+// GetClusterID returns the ID of the cluster the request is about
+func (r createClusterRoleReq) GetClusterID() string {
+	return r.ClusterID
+}
+
 // createRoleReq defines HTTP request for createRole endpoint
 // swagger:parameters createRole
 type createRoleReq struct {
@@ -136,6 +142,11 @@ type createRoleReq struct {
 	Body apiv1.Role
 }
 
+// GetClusterID returns the ID of the cluster the request is about
+func (r createRoleReq) GetClusterID() string {
+	return r.ClusterID
+}
+
 // Validate validates createRoleReq request
 func (r createRoleReq) Validate() error {
 	if len(r.ProjectID) == 0 || len(r.DC) == 0 {
@@ -331,6 +342,11 @@ type listReq struct {
 	ClusterID string `json:"cluster_id"`
 }
 
+// GetClusterID returns the ID of the cluster the request is about
+func (r listReq) GetClusterID() string {
+	return r.ClusterID
+}
+
 func DecodeListClusterRoleReq(c context.Context, r *http.Request) (interface{}, error) {
 	var req listReq
 	clusterID, err := common.DecodeClusterID(c, r)
@@ -484,6 +500,11 @@ type getRoleReq struct {
 	Namespace string `json:"namespace"`
 }
 
+// GetClusterID returns the ID of the cluster the request is about
+func (r getRoleReq) GetClusterID() string {
+	return r.ClusterID
+}
+
 func DecodeGetRoleReq(c context.Context, r *http.Request) (interface{}, error) {
 	var req getRoleReq
 	clusterID, err := common.DecodeClusterID(c, r)
@@ -524,6 +545,11 @@ type getClusterRoleReq struct {
 	RoleID string `json:"role_id"`
 }
 
+// GetClusterID returns the ID of the cluster the request is about
+func (r getClusterRoleReq) GetClusterID() string {
+	return r.ClusterID
+}
+
 func DecodeGetClusterRoleReq(c context.Context, r *http.Request) (interface{}, error) {
 	var req getClusterRoleReq
 	clusterID, err := common.DecodeClusterID(c, r)
@@ -573,6 +599,7 @@ func PatchRoleEndpoint(userInfoGetter provider.UserInfoGetter) endpoint.Endpoint
 			return nil, common.KubernetesErrorToHTTPError(err)
 		}
 
+		audit.RecordBefore(ctx, existingRole)
 		existingRoleJSON, err := json.Marshal(existingRole)
 		if err != nil {
 			return nil, errors.NewBadRequest("cannot decode existing role: %v", err)
@@ -668,6 +695,7 @@ func PatchClusterRoleEndpoint(userInfoGetter provider.UserInfoGetter) endpoint.E
 			return nil, common.KubernetesErrorToHTTPError(err)
 		}
 
+		audit.RecordBefore(ctx, existingClusterRole)
 		existingClusterRoleJSON, err := json.Marshal(existingClusterRole)
 		if err != nil {
 			return nil, errors.NewBadRequest("cannot decode existing cluster role: %v", err)
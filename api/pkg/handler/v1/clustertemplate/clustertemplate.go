@@ -0,0 +1,587 @@
+/*
+Copyright 2020 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clustertemplate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/gorilla/mux"
+
+	apiv1 "github.com/kubermatic/kubermatic/api/pkg/api/v1"
+	kubermaticv1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
+	"github.com/kubermatic/kubermatic/api/pkg/handler/middleware"
+	"github.com/kubermatic/kubermatic/api/pkg/handler/v1/cluster"
+	"github.com/kubermatic/kubermatic/api/pkg/handler/v1/common"
+	"github.com/kubermatic/kubermatic/api/pkg/handler/v1/label"
+	"github.com/kubermatic/kubermatic/api/pkg/handler/v1/node"
+	"github.com/kubermatic/kubermatic/api/pkg/provider"
+	"github.com/kubermatic/kubermatic/api/pkg/util/errors"
+	clusterv1alpha1 "github.com/kubermatic/machine-controller/pkg/apis/cluster/v1alpha1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// maxInstances limits the number of clusters which can be created from a template in one request
+const maxInstances = 50
+
+const (
+	// oidcClientSecret is the required secret for the OIDC client secret of the clusters
+	oidcClientSecret = "oidcClientSecret"
+	// openshiftImagePullSecret is the required secret for the image pull secret of OpenShift clusters
+	openshiftImagePullSecret = "openshiftImagePullSecret"
+)
+
+// CreateEndpoint creates a cluster template from an existing cluster
+func CreateEndpoint(projectProvider provider.ProjectProvider, privilegedProjectProvider provider.PrivilegedProjectProvider, userInfoGetter provider.UserInfoGetter,
+	presetsProvider provider.PresetProvider, clusterTemplateProvider provider.ClusterTemplateProvider) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(createReq)
+		if err := req.Validate(); err != nil {
+			return nil, errors.NewBadRequest("invalid cluster template: %v", err)
+		}
+		clusterProvider := ctx.Value(middleware.ClusterProviderContextKey).(provider.ClusterProvider)
+
+		internalCluster, err := cluster.GetCluster(ctx, projectProvider, privilegedProjectProvider, userInfoGetter, req.ProjectID, req.ClusterID, nil)
+		if err != nil {
+			return nil, err
+		}
+		userInfo, err := getUserInfo(ctx, userInfoGetter, req.ProjectID)
+		if err != nil {
+			return nil, err
+		}
+
+		if kubermaticv1.ClusterTemplateScope(req.Body.Scope) == kubermaticv1.ClusterTemplateScopeGlobal && !userInfo.IsAdmin {
+			return nil, errors.New(http.StatusForbidden, "forbidden: only admins can create global cluster templates")
+		}
+
+		// templates never contain cloud credentials, they are taken from a preset when the clusters are created
+		isBYO, err := common.IsBringYourOwnProvider(internalCluster.Spec.Cloud)
+		if err != nil {
+			return nil, errors.NewBadRequest("invalid cluster: %v", err)
+		}
+		if req.Body.Credential == "" && !isBYO {
+			return nil, errors.NewBadRequest("invalid cluster template: a credential preset is required")
+		}
+		if req.Body.Credential != "" {
+			if _, err := presetsProvider.GetPreset(userInfo, req.Body.Credential); err != nil {
+				return nil, errors.NewBadRequest("invalid credential preset %q: %v", req.Body.Credential, err)
+			}
+		}
+
+		template := &kubermaticv1.ClusterTemplate{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: rand.String(10),
+			},
+			Spec: kubermaticv1.ClusterTemplateSpec{
+				HumanReadableName: req.Body.Name,
+				Scope:             kubermaticv1.ClusterTemplateScope(req.Body.Scope),
+				ProjectID:         req.ProjectID,
+				Owner:             userInfo.Email,
+				Credential:        req.Body.Credential,
+				ClusterType:       apiv1.KubernetesClusterType,
+				ClusterLabels:     label.FilterLabels(label.ClusterResourceType, internalCluster.Labels),
+				Cluster:           templateClusterSpec(internalCluster),
+				RequiredSecrets:   templateRequiredSecrets(&internalCluster.Spec),
+			},
+		}
+		if internalCluster.IsOpenshift() {
+			template.Spec.ClusterType = apiv1.OpenShiftClusterType
+		}
+
+		if req.Body.NodeDeploymentID != "" {
+			client, err := common.GetClusterClient(ctx, userInfoGetter, clusterProvider, internalCluster, req.ProjectID)
+			if err != nil {
+				return nil, common.KubernetesErrorToHTTPError(err)
+			}
+			md := &clusterv1alpha1.MachineDeployment{}
+			if err := client.Get(ctx, types.NamespacedName{Namespace: metav1.NamespaceSystem, Name: req.Body.NodeDeploymentID}, md); err != nil {
+				return nil, common.KubernetesErrorToHTTPError(err)
+			}
+			nodeDeployment, err := templateNodeDeployment(md)
+			if err != nil {
+				return nil, err
+			}
+			template.Spec.NodeDeployment = nodeDeployment
+		}
+
+		template, err = clusterTemplateProvider.New(userInfo, template)
+		if err != nil {
+			return nil, common.KubernetesErrorToHTTPError(err)
+		}
+		return convertInternalClusterTemplateToExternal(template)
+	}
+}
+
+// ListEndpoint lists the cluster templates which can be used in the project
+func ListEndpoint(projectProvider provider.ProjectProvider, privilegedProjectProvider provider.PrivilegedProjectProvider, userInfoGetter provider.UserInfoGetter,
+	clusterTemplateProvider provider.ClusterTemplateProvider) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(common.GetProjectRq)
+		if _, err := common.GetProject(ctx, userInfoGetter, projectProvider, privilegedProjectProvider, req.ProjectID, nil); err != nil {
+			return nil, common.KubernetesErrorToHTTPError(err)
+		}
+		userInfo, err := getUserInfo(ctx, userInfoGetter, req.ProjectID)
+		if err != nil {
+			return nil, err
+		}
+
+		templates, err := clusterTemplateProvider.List(userInfo, req.ProjectID)
+		if err != nil {
+			return nil, common.KubernetesErrorToHTTPError(err)
+		}
+
+		result := apiv1.ClusterTemplateList{}
+		for i := range templates {
+			template, err := convertInternalClusterTemplateToExternal(&templates[i])
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, *template)
+		}
+		return result, nil
+	}
+}
+
+// GetEndpoint returns the cluster template
+func GetEndpoint(projectProvider provider.ProjectProvider, privilegedProjectProvider provider.PrivilegedProjectProvider, userInfoGetter provider.UserInfoGetter,
+	clusterTemplateProvider provider.ClusterTemplateProvider) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(getReq)
+		template, err := getClusterTemplate(ctx, projectProvider, privilegedProjectProvider, userInfoGetter, clusterTemplateProvider, req.ProjectID, req.TemplateID)
+		if err != nil {
+			return nil, err
+		}
+		return convertInternalClusterTemplateToExternal(template)
+	}
+}
+
+// DeleteEndpoint deletes the cluster template
+func DeleteEndpoint(projectProvider provider.ProjectProvider, privilegedProjectProvider provider.PrivilegedProjectProvider, userInfoGetter provider.UserInfoGetter,
+	clusterTemplateProvider provider.ClusterTemplateProvider) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(getReq)
+		if _, err := common.GetProject(ctx, userInfoGetter, projectProvider, privilegedProjectProvider, req.ProjectID, nil); err != nil {
+			return nil, common.KubernetesErrorToHTTPError(err)
+		}
+		userInfo, err := getUserInfo(ctx, userInfoGetter, req.ProjectID)
+		if err != nil {
+			return nil, err
+		}
+
+		return nil, common.KubernetesErrorToHTTPError(clusterTemplateProvider.Delete(userInfo, req.ProjectID, req.TemplateID))
+	}
+}
+
+// CreateInstancesEndpoint creates clusters from the cluster template. The clusters are created one after another by the
+// given cluster creation endpoint, which has to set the cluster providers of the seed named in the request.
+func CreateInstancesEndpoint(projectProvider provider.ProjectProvider, privilegedProjectProvider provider.PrivilegedProjectProvider, userInfoGetter provider.UserInfoGetter,
+	seedsGetter provider.SeedsGetter, clusterProviderGetter provider.ClusterProviderGetter, nodeSizeGetter common.NodeSizeGetter, clusterTemplateProvider provider.ClusterTemplateProvider, createCluster endpoint.Endpoint) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(createInstancesReq)
+		if err := req.Validate(); err != nil {
+			return nil, errors.NewBadRequest("%v", err)
+		}
+
+		template, err := getClusterTemplate(ctx, projectProvider, privilegedProjectProvider, userInfoGetter, clusterTemplateProvider, req.ProjectID, req.TemplateID)
+		if err != nil {
+			return nil, err
+		}
+		project, err := common.GetProject(ctx, userInfoGetter, projectProvider, privilegedProjectProvider, req.ProjectID, nil)
+		if err != nil {
+			return nil, common.KubernetesErrorToHTTPError(err)
+		}
+
+		adminUserInfo, err := userInfoGetter(ctx, "")
+		if err != nil {
+			return nil, common.KubernetesErrorToHTTPError(err)
+		}
+		seed, _, err := provider.DatacenterFromSeedMap(adminUserInfo, seedsGetter, template.Spec.Cluster.Cloud.DatacenterName)
+		if err != nil {
+			return nil, common.KubernetesErrorToHTTPError(err)
+		}
+
+		// secrets are not stored in the template, they have to be provided for every instantiation
+		secrets := map[string]string{
+			oidcClientSecret:         req.Body.OIDCClientSecret,
+			openshiftImagePullSecret: req.Body.OpenshiftImagePullSecret,
+		}
+		for _, secret := range requiredSecrets(template) {
+			if secrets[secret] == "" {
+				return nil, errors.NewBadRequest("the cluster template requires the %s", secret)
+			}
+		}
+
+		// check the quota for all clusters up front, so that either all or none of them are created
+		nodeDeployment, err := templateNodeDeploymentFromRaw(template.Spec.NodeDeployment)
+		if err != nil {
+			return nil, err
+		}
+		quotaChange := common.QuotaChange{}
+		quotaChange.Clusters = req.Body.Replicas
+		if nodeDeployment != nil {
+			quotaChange.Nodes = req.Body.Replicas * int(nodeDeployment.Spec.Replicas)
+		}
+		if _, err := common.CheckProjectQuota(ctx, project, quotaChange, seedsGetter, clusterProviderGetter, nodeSizeGetter); err != nil {
+			return nil, err
+		}
+
+		clusters := apiv1.ClusterList{}
+		for i := 0; i < req.Body.Replicas; i++ {
+			body, err := createClusterSpecFromTemplate(template)
+			if err != nil {
+				return nil, err
+			}
+			if req.Body.OIDCClientSecret != "" {
+				body.Cluster.Spec.OIDC.ClientSecret = req.Body.OIDCClientSecret
+			}
+			if req.Body.OpenshiftImagePullSecret != "" && body.Cluster.Spec.Openshift != nil {
+				body.Cluster.Spec.Openshift.ImagePullSecret = req.Body.OpenshiftImagePullSecret
+			}
+			createReq := cluster.CreateReq{
+				DCReq: common.DCReq{ProjectReq: common.ProjectReq{ProjectID: req.ProjectID}, DC: seed.Name},
+				Body:  *body,
+			}
+			created, err := createCluster(ctx, createReq)
+			if err != nil {
+				if httpErr, ok := err.(errors.HTTPError); ok {
+					return nil, errors.New(httpErr.StatusCode(), fmt.Sprintf("created %d of %d clusters: %s", i, req.Body.Replicas, httpErr.Error()))
+				}
+				return nil, fmt.Errorf("created %d of %d clusters: %v", i, req.Body.Replicas, err)
+			}
+			clusters = append(clusters, *created.(*apiv1.Cluster))
+		}
+		return clusters, nil
+	}
+}
+
+func getUserInfo(ctx context.Context, userInfoGetter provider.UserInfoGetter, projectID string) (*provider.UserInfo, error) {
+	adminUserInfo, err := userInfoGetter(ctx, "")
+	if err != nil {
+		return nil, common.KubernetesErrorToHTTPError(err)
+	}
+	if adminUserInfo.IsAdmin {
+		return adminUserInfo, nil
+	}
+	userInfo, err := userInfoGetter(ctx, projectID)
+	if err != nil {
+		return nil, common.KubernetesErrorToHTTPError(err)
+	}
+	return userInfo, nil
+}
+
+func getClusterTemplate(ctx context.Context, projectProvider provider.ProjectProvider, privilegedProjectProvider provider.PrivilegedProjectProvider, userInfoGetter provider.UserInfoGetter,
+	clusterTemplateProvider provider.ClusterTemplateProvider, projectID, templateID string) (*kubermaticv1.ClusterTemplate, error) {
+	if _, err := common.GetProject(ctx, userInfoGetter, projectProvider, privilegedProjectProvider, projectID, nil); err != nil {
+		return nil, common.KubernetesErrorToHTTPError(err)
+	}
+	userInfo, err := getUserInfo(ctx, userInfoGetter, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	template, err := clusterTemplateProvider.Get(userInfo, projectID, templateID)
+	if err != nil {
+		return nil, common.KubernetesErrorToHTTPError(err)
+	}
+	return template, nil
+}
+
+// templateClusterSpec returns the parts of the cluster spec which are set when creating a cluster, without
+// any secrets
+func templateClusterSpec(internalCluster *kubermaticv1.Cluster) kubermaticv1.ClusterSpec {
+	spec := internalCluster.Spec.DeepCopy()
+	stripClusterSecrets(spec)
+	return kubermaticv1.ClusterSpec{
+		Cloud:                               templateCloudSpec(spec.Cloud),
+		MachineNetworks:                     spec.MachineNetworks,
+		Version:                             spec.Version,
+		OIDC:                                spec.OIDC,
+		UpdateWindow:                        spec.UpdateWindow,
+		Openshift:                           spec.Openshift,
+		UsePodSecurityPolicyAdmissionPlugin: spec.UsePodSecurityPolicyAdmissionPlugin,
+		UsePodNodeSelectorAdmissionPlugin:   spec.UsePodNodeSelectorAdmissionPlugin,
+		AdmissionPlugins:                    spec.AdmissionPlugins,
+		AuditLogging:                        spec.AuditLogging,
+		BackupPolicy:                        spec.BackupPolicy,
+		EncryptionAtRest:                    spec.EncryptionAtRest,
+	}
+}
+
+// stripClusterSecrets removes the secrets which are not part of the cloud spec from the cluster spec
+func stripClusterSecrets(spec *kubermaticv1.ClusterSpec) {
+	spec.OIDC.ClientSecret = ""
+	if spec.Openshift != nil {
+		spec.Openshift.ImagePullSecret = ""
+	}
+}
+
+// templateRequiredSecrets returns the secrets of the cluster spec which have to be provided again when
+// creating clusters from a template of it
+func templateRequiredSecrets(spec *kubermaticv1.ClusterSpec) []string {
+	var secrets []string
+	if spec.OIDC.ClientSecret != "" {
+		secrets = append(secrets, oidcClientSecret)
+	}
+	if spec.Openshift != nil && spec.Openshift.ImagePullSecret != "" {
+		secrets = append(secrets, openshiftImagePullSecret)
+	}
+	return secrets
+}
+
+// requiredSecrets returns the secrets which have to be provided when creating clusters from the template.
+// Templates which were created before secrets got stripped still contain them in their cluster spec.
+func requiredSecrets(template *kubermaticv1.ClusterTemplate) []string {
+	secrets := sets.NewString(template.Spec.RequiredSecrets...)
+	secrets.Insert(templateRequiredSecrets(&template.Spec.Cluster)...)
+	return secrets.List()
+}
+
+// templateCloudSpec returns the cloud spec without credentials and without the cloud resources which were
+// created for the cluster, so that every cluster created from the template gets its own
+func templateCloudSpec(cloud kubermaticv1.CloudSpec) kubermaticv1.CloudSpec {
+	spec := kubermaticv1.CloudSpec{DatacenterName: cloud.DatacenterName}
+	switch {
+	case cloud.Fake != nil:
+		spec.Fake = &kubermaticv1.FakeCloudSpec{}
+	case cloud.Digitalocean != nil:
+		spec.Digitalocean = &kubermaticv1.DigitaloceanCloudSpec{}
+	case cloud.BringYourOwn != nil:
+		spec.BringYourOwn = &kubermaticv1.BringYourOwnCloudSpec{}
+	case cloud.AWS != nil:
+		spec.AWS = &kubermaticv1.AWSCloudSpec{}
+	case cloud.Azure != nil:
+		spec.Azure = &kubermaticv1.AzureCloudSpec{}
+	case cloud.Openstack != nil:
+		spec.Openstack = &kubermaticv1.OpenstackCloudSpec{FloatingIPPool: cloud.Openstack.FloatingIPPool}
+	case cloud.Packet != nil:
+		spec.Packet = &kubermaticv1.PacketCloudSpec{}
+	case cloud.Hetzner != nil:
+		spec.Hetzner = &kubermaticv1.HetznerCloudSpec{}
+	case cloud.VSphere != nil:
+		spec.VSphere = &kubermaticv1.VSphereCloudSpec{}
+	case cloud.GCP != nil:
+		spec.GCP = &kubermaticv1.GCPCloudSpec{}
+	case cloud.Kubevirt != nil:
+		spec.Kubevirt = &kubermaticv1.KubevirtCloudSpec{}
+	case cloud.Alibaba != nil:
+		spec.Alibaba = &kubermaticv1.AlibabaCloudSpec{}
+	}
+	return spec
+}
+
+// templateNodeDeployment returns the API representation of the machine deployment without its identity and status
+func templateNodeDeployment(md *clusterv1alpha1.MachineDeployment) (*runtime.RawExtension, error) {
+	nodeDeployment, err := node.OutputMachineDeployment(md)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert the machine deployment: %v", err)
+	}
+	nodeDeployment.ObjectMeta = apiv1.ObjectMeta{Name: nodeDeployment.Name}
+	nodeDeployment.Status = clusterv1alpha1.MachineDeploymentStatus{}
+
+	raw, err := json.Marshal(nodeDeployment)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode the node deployment: %v", err)
+	}
+	return &runtime.RawExtension{Raw: raw}, nil
+}
+
+func templateNodeDeploymentFromRaw(raw *runtime.RawExtension) (*apiv1.NodeDeployment, error) {
+	if raw == nil || len(raw.Raw) == 0 {
+		return nil, nil
+	}
+	nodeDeployment := &apiv1.NodeDeployment{}
+	if err := json.Unmarshal(raw.Raw, nodeDeployment); err != nil {
+		return nil, fmt.Errorf("failed to decode the node deployment of the cluster template: %v", err)
+	}
+	return nodeDeployment, nil
+}
+
+// createClusterSpecFromTemplate returns the request to create a cluster from the template without any secrets,
+// the name of every cluster gets a random suffix
+func createClusterSpecFromTemplate(template *kubermaticv1.ClusterTemplate) (*apiv1.CreateClusterSpec, error) {
+	templateSpec := template.Spec.DeepCopy()
+	stripClusterSecrets(&templateSpec.Cluster)
+	nodeDeployment, err := templateNodeDeploymentFromRaw(templateSpec.NodeDeployment)
+	if err != nil {
+		return nil, err
+	}
+
+	return &apiv1.CreateClusterSpec{
+		Cluster: apiv1.Cluster{
+			ObjectMeta: apiv1.ObjectMeta{
+				Name: fmt.Sprintf("%s-%s", templateSpec.HumanReadableName, rand.String(5)),
+			},
+			Labels:     templateSpec.ClusterLabels,
+			Type:       templateSpec.ClusterType,
+			Credential: templateSpec.Credential,
+			Spec: apiv1.ClusterSpec{
+				Cloud:                               templateSpec.Cluster.Cloud,
+				MachineNetworks:                     templateSpec.Cluster.MachineNetworks,
+				Version:                             templateSpec.Cluster.Version,
+				OIDC:                                templateSpec.Cluster.OIDC,
+				UpdateWindow:                        templateSpec.Cluster.UpdateWindow,
+				UsePodSecurityPolicyAdmissionPlugin: templateSpec.Cluster.UsePodSecurityPolicyAdmissionPlugin,
+				UsePodNodeSelectorAdmissionPlugin:   templateSpec.Cluster.UsePodNodeSelectorAdmissionPlugin,
+				AdmissionPlugins:                    templateSpec.Cluster.AdmissionPlugins,
+				AuditLogging:                        templateSpec.Cluster.AuditLogging,
+				BackupPolicy:                        templateSpec.Cluster.BackupPolicy,
+				EncryptionAtRest:                    templateSpec.Cluster.EncryptionAtRest,
+				Openshift:                           templateSpec.Cluster.Openshift,
+			},
+		},
+		NodeDeployment: nodeDeployment,
+	}, nil
+}
+
+func convertInternalClusterTemplateToExternal(template *kubermaticv1.ClusterTemplate) (*apiv1.ClusterTemplate, error) {
+	instance, err := createClusterSpecFromTemplate(template)
+	if err != nil {
+		return nil, err
+	}
+
+	return &apiv1.ClusterTemplate{
+		ObjectMeta: apiv1.ObjectMeta{
+			ID:                template.Name,
+			Name:              template.Spec.HumanReadableName,
+			CreationTimestamp: apiv1.NewTime(template.CreationTimestamp.Time),
+		},
+		Scope:           string(template.Spec.Scope),
+		ProjectID:       template.Spec.ProjectID,
+		Owner:           template.Spec.Owner,
+		Credential:      template.Spec.Credential,
+		Type:            template.Spec.ClusterType,
+		Labels:          instance.Cluster.Labels,
+		Spec:            instance.Cluster.Spec,
+		NodeDeployment:  instance.NodeDeployment,
+		RequiredSecrets: requiredSecrets(template),
+	}, nil
+}
+
+// createReq defines HTTP request for createClusterTemplate
+// swagger:parameters createClusterTemplate
+type createReq struct {
+	common.GetClusterReq
+	// in: body
+	Body struct {
+		// Name is the name of the template
+		Name string `json:"name"`
+		// Scope is either user, project or global, only admins can create global templates
+		Scope string `json:"scope"`
+		// Credential is the name of the preset which provides the cloud credentials of the clusters
+		Credential string `json:"credential,omitempty"`
+		// NodeDeploymentID is the node deployment of the cluster which is used as the initial node deployment
+		NodeDeploymentID string `json:"nodeDeploymentID,omitempty"`
+	}
+}
+
+// Validate validates createClusterTemplate request
+func (r createReq) Validate() error {
+	if r.Body.Name == "" {
+		return fmt.Errorf("the name is required")
+	}
+	switch kubermaticv1.ClusterTemplateScope(r.Body.Scope) {
+	case kubermaticv1.ClusterTemplateScopeUser, kubermaticv1.ClusterTemplateScopeProject, kubermaticv1.ClusterTemplateScopeGlobal:
+		return nil
+	}
+	return fmt.Errorf("invalid scope %q, the scope must be one of user, project or global", r.Body.Scope)
+}
+
+func DecodeCreateReq(c context.Context, r *http.Request) (interface{}, error) {
+	var req createReq
+
+	clusterReq, err := common.DecodeGetClusterReq(c, r)
+	if err != nil {
+		return nil, err
+	}
+	req.GetClusterReq = clusterReq.(common.GetClusterReq)
+
+	if err := json.NewDecoder(r.Body).Decode(&req.Body); err != nil {
+		return nil, errors.NewBadRequest("unable to parse the cluster template: %v", err)
+	}
+
+	return req, nil
+}
+
+// getReq defines HTTP request for getClusterTemplate and deleteClusterTemplate
+// swagger:parameters getClusterTemplate deleteClusterTemplate
+type getReq struct {
+	common.ProjectReq
+	// in: path
+	// required: true
+	TemplateID string `json:"template_id"`
+}
+
+func DecodeGetReq(c context.Context, r *http.Request) (interface{}, error) {
+	projectReq, err := common.DecodeProjectRequest(c, r)
+	if err != nil {
+		return nil, err
+	}
+
+	templateID := mux.Vars(r)["template_id"]
+	if templateID == "" {
+		return nil, fmt.Errorf("'template_id' parameter is required but was not provided")
+	}
+
+	return getReq{ProjectReq: projectReq.(common.ProjectReq), TemplateID: templateID}, nil
+}
+
+// createInstancesReq defines HTTP request for createClusterTemplateInstances
+// swagger:parameters createClusterTemplateInstances
+type createInstancesReq struct {
+	getReq
+	// in: body
+	Body struct {
+		// Replicas is the number of clusters to create
+		Replicas int `json:"replicas"`
+		// OIDCClientSecret is the OIDC client secret of the clusters, required if the template requires the oidcClientSecret
+		OIDCClientSecret string `json:"oidcClientSecret,omitempty"`
+		// OpenshiftImagePullSecret is the image pull secret of the clusters, required if the template requires the openshiftImagePullSecret
+		OpenshiftImagePullSecret string `json:"openshiftImagePullSecret,omitempty"`
+	}
+}
+
+// Validate validates createClusterTemplateInstances request
+func (r createInstancesReq) Validate() error {
+	if r.Body.Replicas < 1 || r.Body.Replicas > maxInstances {
+		return fmt.Errorf("the number of clusters must be between 1 and %d", maxInstances)
+	}
+	return nil
+}
+
+func DecodeCreateInstancesReq(c context.Context, r *http.Request) (interface{}, error) {
+	var req createInstancesReq
+
+	templateReq, err := DecodeGetReq(c, r)
+	if err != nil {
+		return nil, err
+	}
+	req.getReq = templateReq.(getReq)
+
+	if err := json.NewDecoder(r.Body).Decode(&req.Body); err != nil {
+		return nil, errors.NewBadRequest("unable to parse the request: %v", err)
+	}
+
+	return req, nil
+}
@@ -0,0 +1,379 @@
+/*
+Copyright 2020 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clustertemplate_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+
+	apiv1 "github.com/kubermatic/kubermatic/api/pkg/api/v1"
+	kubermaticv1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
+	"github.com/kubermatic/kubermatic/api/pkg/handler/test"
+	"github.com/kubermatic/kubermatic/api/pkg/handler/test/hack"
+	"github.com/kubermatic/kubermatic/api/pkg/semver"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func genClusterTemplate(name string, scope kubermaticv1.ClusterTemplateScope, projectID, owner string) *kubermaticv1.ClusterTemplate {
+	return &kubermaticv1.ClusterTemplate{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+		Spec: kubermaticv1.ClusterTemplateSpec{
+			HumanReadableName: name,
+			Scope:             scope,
+			ProjectID:         projectID,
+			Owner:             owner,
+			Credential:        test.TestFakeCredential,
+			ClusterType:       apiv1.KubernetesClusterType,
+			Cluster: kubermaticv1.ClusterSpec{
+				Cloud: kubermaticv1.CloudSpec{
+					DatacenterName: "fake-dc",
+					Fake:           &kubermaticv1.FakeCloudSpec{},
+				},
+				Version: *semver.NewSemverOrDie("1.15.0"),
+			},
+		},
+	}
+}
+
+func genDefaultClusterTemplates() []runtime.Object {
+	return []runtime.Object{
+		genClusterTemplate("global-template", kubermaticv1.ClusterTemplateScopeGlobal, "other-project", "admin@acme.com"),
+		genClusterTemplate("project-template", kubermaticv1.ClusterTemplateScopeProject, test.GenDefaultProject().Name, "john@acme.com"),
+		genClusterTemplate("other-project-template", kubermaticv1.ClusterTemplateScopeProject, "other-project", "john@acme.com"),
+		genClusterTemplate("bob-template", kubermaticv1.ClusterTemplateScopeUser, test.GenDefaultProject().Name, test.GenDefaultUser().Spec.Email),
+		genClusterTemplate("john-template", kubermaticv1.ClusterTemplateScopeUser, test.GenDefaultProject().Name, "john@acme.com"),
+	}
+}
+
+func TestCreateClusterTemplateEndpoint(t *testing.T) {
+	t.Parallel()
+	testcases := []struct {
+		name             string
+		body             string
+		expectedResponse string
+		httpStatus       int
+		rewriteID        bool
+		existingCluster  *kubermaticv1.Cluster
+	}{
+		// scenario 1
+		{
+			name:             "scenario 1: a template without credentials is created from the cluster",
+			body:             `{"name":"fake-template","scope":"project","credential":"fake"}`,
+			expectedResponse: `{"id":"%s","name":"fake-template","creationTimestamp":"0001-01-01T00:00:00Z","scope":"project","projectID":"my-first-project-ID","owner":"bob@acme.com","credential":"fake","type":"kubernetes","spec":{"cloud":{"dc":"FakeDatacenter","fake":{}},"version":"9.9.9","oidc":{}}}`,
+			httpStatus:       http.StatusCreated,
+			rewriteID:        true,
+		},
+		// scenario 2
+		{
+			name:             "scenario 2: only admins can create global templates",
+			body:             `{"name":"fake-template","scope":"global","credential":"fake"}`,
+			expectedResponse: `{"error":{"code":403,"message":"forbidden: only admins can create global cluster templates"}}`,
+			httpStatus:       http.StatusForbidden,
+		},
+		// scenario 3
+		{
+			name:             "scenario 3: the preset of the template must exist",
+			body:             `{"name":"fake-template","scope":"user","credential":"missing"}`,
+			expectedResponse: `{"error":{"code":400,"message":"invalid credential preset \"missing\": missing preset 'missing' for the user 'bob@acme.com'"}}`,
+			httpStatus:       http.StatusBadRequest,
+		},
+		// scenario 4
+		{
+			name:             "scenario 4: the scope must be valid",
+			body:             `{"name":"fake-template","scope":"everyone","credential":"fake"}`,
+			expectedResponse: `{"error":{"code":400,"message":"invalid cluster template: invalid scope \"everyone\", the scope must be one of user, project or global"}}`,
+			httpStatus:       http.StatusBadRequest,
+		},
+		// scenario 5
+		{
+			name:             "scenario 5: the OIDC client secret is not stored in the template",
+			body:             `{"name":"fake-template","scope":"project","credential":"fake"}`,
+			expectedResponse: `{"id":"%s","name":"fake-template","creationTimestamp":"0001-01-01T00:00:00Z","scope":"project","projectID":"my-first-project-ID","owner":"bob@acme.com","credential":"fake","type":"kubernetes","spec":{"cloud":{"dc":"FakeDatacenter","fake":{}},"version":"9.9.9","oidc":{"issuerUrl":"https://dex.example.com","clientId":"kubernetes"}},"requiredSecrets":["oidcClientSecret"]}`,
+			httpStatus:       http.StatusCreated,
+			rewriteID:        true,
+			existingCluster: func() *kubermaticv1.Cluster {
+				cluster := test.GenDefaultCluster()
+				cluster.Spec.OIDC = kubermaticv1.OIDCSettings{IssuerURL: "https://dex.example.com", ClientID: "kubernetes", ClientSecret: "secret"}
+				return cluster
+			}(),
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", fmt.Sprintf("/api/v1/projects/%s/dc/us-central1/clusters/%s/clustertemplates", test.GenDefaultProject().Name, test.GenDefaultCluster().Name), strings.NewReader(tc.body))
+			res := httptest.NewRecorder()
+			existingCluster := tc.existingCluster
+			if existingCluster == nil {
+				existingCluster = test.GenDefaultCluster()
+			}
+			ep, clients, err := test.CreateTestEndpointAndGetClients(*test.GenDefaultAPIUser(), nil, []runtime.Object{}, nil, test.GenDefaultKubermaticObjects(existingCluster), nil, nil, hack.NewTestRouting)
+			if err != nil {
+				t.Fatalf("failed to create test endpoint due to %v", err)
+			}
+
+			ep.ServeHTTP(res, req)
+
+			if res.Code != tc.httpStatus {
+				t.Fatalf("Expected HTTP status code %d, got %d: %s", tc.httpStatus, res.Code, res.Body.String())
+			}
+
+			expectedResponse := tc.expectedResponse
+			if tc.rewriteID {
+				templateList := &kubermaticv1.ClusterTemplateList{}
+				if err := clients.FakeClient.List(context.Background(), templateList); err != nil {
+					t.Fatal(err)
+				}
+				if len(templateList.Items) != 1 {
+					t.Fatalf("expected one cluster template to be stored, got %d", len(templateList.Items))
+				}
+				expectedResponse = fmt.Sprintf(tc.expectedResponse, templateList.Items[0].Name)
+				if templateList.Items[0].Spec.Cluster.OIDC.ClientSecret != "" {
+					t.Fatalf("expected the OIDC client secret not to be stored in the template")
+				}
+			}
+
+			test.CompareWithResult(t, res, expectedResponse)
+		})
+	}
+}
+
+func TestListClusterTemplatesEndpoint(t *testing.T) {
+	t.Parallel()
+	testcases := []struct {
+		name              string
+		existingAPIUser   *apiv1.User
+		expectedTemplates []string
+	}{
+		// scenario 1
+		{
+			name:              "scenario 1: the owner sees global, project and own templates",
+			existingAPIUser:   test.GenDefaultAPIUser(),
+			expectedTemplates: []string{"bob-template", "global-template", "project-template"},
+		},
+		// scenario 2
+		{
+			name:              "scenario 2: the templates of other users are not listed",
+			existingAPIUser:   test.GenAPIUser("John", "john@acme.com"),
+			expectedTemplates: []string{"global-template", "john-template", "project-template"},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", fmt.Sprintf("/api/v1/projects/%s/clustertemplates", test.GenDefaultProject().Name), nil)
+			res := httptest.NewRecorder()
+			kubermaticObjs := test.GenDefaultKubermaticObjects(
+				test.GenUser("", "John", "john@acme.com"),
+				test.GenBinding(test.GenDefaultProject().Name, "john@acme.com", "editors"),
+			)
+			kubermaticObjs = append(kubermaticObjs, genDefaultClusterTemplates()...)
+			ep, err := test.CreateTestEndpoint(*tc.existingAPIUser, []runtime.Object{}, kubermaticObjs, nil, nil, hack.NewTestRouting)
+			if err != nil {
+				t.Fatalf("failed to create test endpoint due to %v", err)
+			}
+
+			ep.ServeHTTP(res, req)
+
+			if res.Code != http.StatusOK {
+				t.Fatalf("Expected HTTP status code %d, got %d: %s", http.StatusOK, res.Code, res.Body.String())
+			}
+
+			templates := apiv1.ClusterTemplateList{}
+			if err := json.Unmarshal(res.Body.Bytes(), &templates); err != nil {
+				t.Fatal(err)
+			}
+			var names []string
+			for _, template := range templates {
+				names = append(names, template.ID)
+			}
+			sort.Strings(names)
+			if strings.Join(names, ",") != strings.Join(tc.expectedTemplates, ",") {
+				t.Fatalf("expected templates %v, got %v", tc.expectedTemplates, names)
+			}
+		})
+	}
+}
+
+func TestDeleteClusterTemplateEndpoint(t *testing.T) {
+	t.Parallel()
+	testcases := []struct {
+		name             string
+		templateID       string
+		expectedResponse string
+		httpStatus       int
+	}{
+		// scenario 1
+		{
+			name:             "scenario 1: a project member deletes a project template",
+			templateID:       "project-template",
+			expectedResponse: `{}`,
+			httpStatus:       http.StatusOK,
+		},
+		// scenario 2
+		{
+			name:             "scenario 2: only admins can delete global templates",
+			templateID:       "global-template",
+			expectedResponse: `{"error":{"code":403,"message":"clustertemplates.kubermatic.k8s.io \"global-template\" is forbidden: \"bob@acme.com\" is not allowed to delete the cluster template"}}`,
+			httpStatus:       http.StatusForbidden,
+		},
+		// scenario 3
+		{
+			name:             "scenario 3: the templates of other users can not be deleted",
+			templateID:       "john-template",
+			expectedResponse: `{"error":{"code":404,"message":"clustertemplates.kubermatic.k8s.io \"john-template\" not found"}}`,
+			httpStatus:       http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest("DELETE", fmt.Sprintf("/api/v1/projects/%s/clustertemplates/%s", test.GenDefaultProject().Name, tc.templateID), nil)
+			res := httptest.NewRecorder()
+			ep, err := test.CreateTestEndpoint(*test.GenDefaultAPIUser(), []runtime.Object{}, test.GenDefaultKubermaticObjects(genDefaultClusterTemplates()...), nil, nil, hack.NewTestRouting)
+			if err != nil {
+				t.Fatalf("failed to create test endpoint due to %v", err)
+			}
+
+			ep.ServeHTTP(res, req)
+
+			if res.Code != tc.httpStatus {
+				t.Fatalf("Expected HTTP status code %d, got %d: %s", tc.httpStatus, res.Code, res.Body.String())
+			}
+
+			test.CompareWithResult(t, res, tc.expectedResponse)
+		})
+	}
+}
+
+func TestCreateClusterTemplateInstancesEndpoint(t *testing.T) {
+	t.Parallel()
+	testcases := []struct {
+		name             string
+		body             string
+		existingProject  *kubermaticv1.Project
+		expectedClusters int
+		expectedResponse string
+		httpStatus       int
+		requiredSecrets  []string
+	}{
+		// scenario 1
+		{
+			name:             "scenario 1: clusters are created from the template",
+			body:             `{"replicas":2}`,
+			existingProject:  test.GenDefaultProject(),
+			expectedClusters: 2,
+			httpStatus:       http.StatusCreated,
+		},
+		// scenario 2
+		{
+			name: "scenario 2: no cluster is created when the project quota is exceeded",
+			body: `{"replicas":2}`,
+			existingProject: func() *kubermaticv1.Project {
+				project := test.GenDefaultProject()
+				maxClusters := 1
+				project.Spec.Quota = &kubermaticv1.ProjectQuota{MaxClusters: &maxClusters}
+				return project
+			}(),
+			expectedResponse: `{"error":{"code":403,"message":"forbidden: project my-first-project-ID would use 2 clusters, but its quota allows only 1"}}`,
+			httpStatus:       http.StatusForbidden,
+		},
+		// scenario 3
+		{
+			name:             "scenario 3: the number of clusters must be positive",
+			body:             `{"replicas":0}`,
+			existingProject:  test.GenDefaultProject(),
+			expectedResponse: `{"error":{"code":400,"message":"the number of clusters must be between 1 and 50"}}`,
+			httpStatus:       http.StatusBadRequest,
+		},
+		// scenario 4
+		{
+			name:             "scenario 4: the secrets required by the template must be provided",
+			body:             `{"replicas":1}`,
+			existingProject:  test.GenDefaultProject(),
+			requiredSecrets:  []string{"oidcClientSecret"},
+			expectedResponse: `{"error":{"code":400,"message":"the cluster template requires the oidcClientSecret"}}`,
+			httpStatus:       http.StatusBadRequest,
+		},
+		// scenario 5
+		{
+			name:             "scenario 5: clusters are created with the provided secrets",
+			body:             `{"replicas":1,"oidcClientSecret":"secret"}`,
+			existingProject:  test.GenDefaultProject(),
+			requiredSecrets:  []string{"oidcClientSecret"},
+			expectedClusters: 1,
+			httpStatus:       http.StatusCreated,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", fmt.Sprintf("/api/v1/projects/%s/clustertemplates/project-template/instances", test.GenDefaultProject().Name), strings.NewReader(tc.body))
+			res := httptest.NewRecorder()
+			kubermaticObjs := []runtime.Object{
+				tc.existingProject,
+				test.GenDefaultUser(),
+				test.GenDefaultOwnerBinding(),
+				test.GenDefaultPreset(),
+			}
+			for _, template := range genDefaultClusterTemplates() {
+				template.(*kubermaticv1.ClusterTemplate).Spec.RequiredSecrets = tc.requiredSecrets
+				kubermaticObjs = append(kubermaticObjs, template)
+			}
+			ep, err := test.CreateTestEndpoint(*test.GenDefaultAPIUser(), []runtime.Object{}, kubermaticObjs, test.GenDefaultVersions(), nil, hack.NewTestRouting)
+			if err != nil {
+				t.Fatalf("failed to create test endpoint due to %v", err)
+			}
+
+			ep.ServeHTTP(res, req)
+
+			if res.Code != tc.httpStatus {
+				t.Fatalf("Expected HTTP status code %d, got %d: %s", tc.httpStatus, res.Code, res.Body.String())
+			}
+			if tc.expectedClusters == 0 {
+				test.CompareWithResult(t, res, tc.expectedResponse)
+				return
+			}
+
+			clusters := apiv1.ClusterList{}
+			if err := json.Unmarshal(res.Body.Bytes(), &clusters); err != nil {
+				t.Fatal(err)
+			}
+			if len(clusters) != tc.expectedClusters {
+				t.Fatalf("expected %d clusters, got %d", tc.expectedClusters, len(clusters))
+			}
+			for _, cluster := range clusters {
+				if !strings.HasPrefix(cluster.Name, "project-template-") {
+					t.Fatalf("expected the cluster name to start with the template name, got %q", cluster.Name)
+				}
+				if cluster.Spec.Cloud.DatacenterName != "fake-dc" {
+					t.Fatalf("expected the cluster in datacenter fake-dc, got %q", cluster.Spec.Cloud.DatacenterName)
+				}
+			}
+		})
+	}
+}
@@ -39,6 +39,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/httpstream"
+	"k8s.io/apimachinery/pkg/util/sets"
 	corev1interface "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/portforward"
@@ -84,6 +85,7 @@ type ServerMetrics struct {
 	HTTPRequestsTotal          *prometheus.CounterVec
 	HTTPRequestsDuration       *prometheus.HistogramVec
 	InitNodeDeploymentFailures *prometheus.CounterVec
+	RateLimitedRequests        *prometheus.CounterVec
 }
 
 // IsBringYourOwnProvider determines whether the spec holds BringYourOwn provider
@@ -311,3 +313,45 @@ func GetClusterClient(ctx context.Context, userInfoGetter provider.UserInfoGette
 	}
 	return clusterProvider.GetClientForCustomerCluster(userInfo, cluster)
 }
+
+// GetNodeDeploymentClusterClient returns the client for the given cluster to access the NodeDeployments with the given verb.
+// The members of a custom ProjectRole have no permissions inside of the user cluster, instead they are authorized against
+// the verbs their role grants for NodeDeployments and get a client with admin privileges.
+func GetNodeDeploymentClusterClient(ctx context.Context, userInfoGetter provider.UserInfoGetter, clusterProvider provider.ClusterProvider, cluster *kubermaticv1.Cluster, projectID, verb string) (ctrlruntimeclient.Client, error) {
+	adminUserInfo, err := userInfoGetter(ctx, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user information: %v", err)
+	}
+	if adminUserInfo.IsAdmin {
+		return clusterProvider.GetAdminClientForCustomerCluster(cluster)
+	}
+
+	userInfo, err := userInfoGetter(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user information: %v", err)
+	}
+	if userInfo.ProjectRole == nil {
+		return clusterProvider.GetClientForCustomerCluster(userInfo, cluster)
+	}
+	if !sets.NewString(userInfo.ProjectRole.VerbsFor(rbac.NodeDeploymentKind)...).Has(verb) {
+		return nil, kubermaticerrors.New(http.StatusForbidden, fmt.Sprintf("the project role %s doesn't allow to %s node deployments", userInfo.ProjectRole.Name, verb))
+	}
+	return clusterProvider.GetAdminClientForCustomerCluster(cluster)
+}
+
+// GetClusterClientConfig returns the client config for the given cluster with the same privileges as the client of GetClusterClient
+func GetClusterClientConfig(ctx context.Context, userInfoGetter provider.UserInfoGetter, clusterProvider provider.ClusterProvider, cluster *kubermaticv1.Cluster, projectID string) (*rest.Config, error) {
+	adminUserInfo, err := userInfoGetter(ctx, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user information: %v", err)
+	}
+	if adminUserInfo.IsAdmin {
+		return clusterProvider.GetAdminClientConfigForCustomerCluster(cluster)
+	}
+
+	userInfo, err := userInfoGetter(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user information: %v", err)
+	}
+	return clusterProvider.GetClientConfigForCustomerCluster(userInfo, cluster)
+}
@@ -90,5 +90,39 @@ func ConvertInternalProjectToExternal(kubermaticProject *kubermaticapiv1.Project
 		Status:         kubermaticProject.Status.Phase,
 		Owners:         projectOwners,
 		ClustersNumber: clustersNumber,
+		Quota:          kubermaticProject.Spec.Quota,
+		Usage:          kubermaticProject.Status.Usage,
+	}
+}
+
+// ConvertInternalEtcdBackupStatusToExternal converts the backup status of a cluster to the API type
+func ConvertInternalEtcdBackupStatusToExternal(status *kubermaticapiv1.EtcdBackupStatus) *apiv1.EtcdBackupStatus {
+	if status == nil {
+		return nil
+	}
+	result := &apiv1.EtcdBackupStatus{
+		LastFailureMessage: status.LastFailureMessage,
+	}
+	if status.LastSuccessfulBackupTime != nil {
+		lastSuccess := apiv1.NewTime(status.LastSuccessfulBackupTime.Time)
+		result.LastSuccessfulBackupTime = &lastSuccess
+	}
+	if status.LastFailedBackupTime != nil {
+		lastFailure := apiv1.NewTime(status.LastFailedBackupTime.Time)
+		result.LastFailedBackupTime = &lastFailure
+	}
+	if status.LatestSnapshot != nil {
+		snapshot := ConvertInternalEtcdSnapshotToExternal(*status.LatestSnapshot)
+		result.LatestSnapshot = &snapshot
+	}
+	return result
+}
+
+// ConvertInternalEtcdSnapshotToExternal converts an etcd snapshot to the API type
+func ConvertInternalEtcdSnapshotToExternal(snapshot kubermaticapiv1.EtcdSnapshot) apiv1.EtcdSnapshot {
+	return apiv1.EtcdSnapshot{
+		Name:              snapshot.Name,
+		Size:              snapshot.Size,
+		CreationTimestamp: apiv1.NewTime(snapshot.CreationTimestamp.Time),
 	}
 }
@@ -0,0 +1,272 @@
+/*
+Copyright 2020 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	apiv1 "github.com/kubermatic/kubermatic/api/pkg/api/v1"
+	kubermaticv1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
+	"github.com/kubermatic/kubermatic/api/pkg/log"
+	"github.com/kubermatic/kubermatic/api/pkg/machine"
+	"github.com/kubermatic/kubermatic/api/pkg/provider"
+	"github.com/kubermatic/kubermatic/api/pkg/util/errors"
+	clusterv1alpha1 "github.com/kubermatic/machine-controller/pkg/apis/cluster/v1alpha1"
+	providerconfig "github.com/kubermatic/machine-controller/pkg/providerconfig/types"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// NodeSize is the number of vCPUs and the memory of a single node
+type NodeSize struct {
+	CPUs     int
+	MemoryMB int
+}
+
+// NodeSizeGetter returns the size of the nodes described by the given spec. It returns nil if the size
+// can not be derived for the cloud provider of the cluster.
+type NodeSizeGetter func(ctx context.Context, cluster *kubermaticv1.Cluster, dc *kubermaticv1.Datacenter, secretKeySelector provider.SecretKeySelectorValueFunc, spec *apiv1.NodeSpec) (*NodeSize, error)
+
+// QuotaChange describes the resources which are added to or removed from a project
+type QuotaChange struct {
+	kubermaticv1.ProjectResourceUsage
+	// SizeUnknown is set if the CPUs and memory of added nodes could not be determined
+	SizeUnknown bool
+	// NodesUnknown is set if the nodes of a cluster could not be listed
+	NodesUnknown bool
+}
+
+// NodesQuotaChange returns the change of the project resources caused by adding the given number of nodes
+func NodesQuotaChange(replicas int, size *NodeSize) QuotaChange {
+	change := QuotaChange{
+		ProjectResourceUsage: kubermaticv1.ProjectResourceUsage{Nodes: replicas},
+		SizeUnknown:          size == nil && replicas > 0,
+	}
+	if size != nil {
+		change.CPUs = replicas * size.CPUs
+		change.MemoryMB = replicas * size.MemoryMB
+	}
+	return change
+}
+
+// MachineDeploymentUsage returns the resources consumed by the given machine deployment. The size of its nodes
+// is derived from the provider spec, so that it matches the nodes which actually get created. SizeUnknown is set
+// if the size can not be determined.
+func MachineDeploymentUsage(ctx context.Context, cluster *kubermaticv1.Cluster, dc *kubermaticv1.Datacenter, secretKeySelector provider.SecretKeySelectorValueFunc, md *clusterv1alpha1.MachineDeployment, nodeSizeGetter NodeSizeGetter) QuotaChange {
+	replicas := 1
+	if md.Spec.Replicas != nil {
+		replicas = int(*md.Spec.Replicas)
+	}
+
+	size, err := machineDeploymentNodeSize(ctx, cluster, dc, secretKeySelector, md, nodeSizeGetter)
+	if err != nil {
+		log.Logger.Debugw("failed to get the node size of the machine deployment", "cluster", cluster.Name, "machinedeployment", md.Name, "error", err)
+	}
+	return NodesQuotaChange(replicas, size)
+}
+
+func machineDeploymentNodeSize(ctx context.Context, cluster *kubermaticv1.Cluster, dc *kubermaticv1.Datacenter, secretKeySelector provider.SecretKeySelectorValueFunc, md *clusterv1alpha1.MachineDeployment, nodeSizeGetter NodeSizeGetter) (*NodeSize, error) {
+	if dc == nil {
+		return nil, fmt.Errorf("datacenter %q not found", cluster.Spec.Cloud.DatacenterName)
+	}
+	cloudSpec, err := machine.GetAPIV2NodeCloudSpec(md.Spec.Template.Spec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the node cloud spec: %v", err)
+	}
+	return nodeSizeGetter(ctx, cluster, dc, secretKeySelector, &apiv1.NodeSpec{Cloud: *cloudSpec})
+}
+
+// GetProjectResourceUsage returns the resources consumed by all clusters of the given project, from all seeds.
+// Clusters which are being deleted are not counted, neither are the nodes of clusters which can not be reached.
+func GetProjectResourceUsage(ctx context.Context, project *kubermaticv1.Project, seedsGetter provider.SeedsGetter, clusterProviderGetter provider.ClusterProviderGetter, nodeSizeGetter NodeSizeGetter) (*kubermaticv1.ProjectResourceUsage, error) {
+	usage, err := getProjectResourceUsage(ctx, project, seedsGetter, clusterProviderGetter, nodeSizeGetter)
+	if err != nil {
+		return nil, err
+	}
+	return &usage.ProjectResourceUsage, nil
+}
+
+// getProjectResourceUsage returns the resources consumed by the project. SizeUnknown is set if the size of
+// the nodes of any machine deployment can not be determined, their CPUs and memory are not counted then.
+// NodesUnknown is set if the nodes of any cluster can not be listed, none of its nodes are counted then.
+func getProjectResourceUsage(ctx context.Context, project *kubermaticv1.Project, seedsGetter provider.SeedsGetter, clusterProviderGetter provider.ClusterProviderGetter, nodeSizeGetter NodeSizeGetter) (*QuotaChange, error) {
+	seeds, err := seedsGetter()
+	if err != nil {
+		return nil, errors.New(http.StatusInternalServerError, fmt.Sprintf("failed to list seeds: %v", err))
+	}
+
+	usage := &QuotaChange{}
+	for seedName, seed := range seeds {
+		clusterProvider, err := clusterProviderGetter(seed)
+		if err != nil {
+			return nil, errors.NewNotFound("cluster-provider", seedName)
+		}
+		clusters, err := clusterProvider.List(project, nil)
+		if err != nil {
+			return nil, KubernetesErrorToHTTPError(err)
+		}
+
+		for i := range clusters.Items {
+			cluster := &clusters.Items[i]
+			if cluster.DeletionTimestamp != nil {
+				continue
+			}
+			usage.Clusters++
+
+			var dc *kubermaticv1.Datacenter
+			if seedDatacenter, ok := seed.Spec.Datacenters[cluster.Spec.Cloud.DatacenterName]; ok {
+				dc = &seedDatacenter
+			}
+			nodesUsage, err := getClusterNodesUsage(ctx, clusterProvider, cluster, dc, nodeSizeGetter)
+			if err != nil {
+				log.Logger.Debugw("failed to get the nodes of the cluster for the project usage", "cluster", cluster.Name, "error", err)
+				usage.NodesUnknown = true
+				continue
+			}
+			usage.Nodes += nodesUsage.Nodes
+			usage.CPUs += nodesUsage.CPUs
+			usage.MemoryMB += nodesUsage.MemoryMB
+			usage.SizeUnknown = usage.SizeUnknown || nodesUsage.SizeUnknown
+		}
+	}
+
+	return usage, nil
+}
+
+// seedClientProvider is implemented by cluster providers which offer a client for the seed cluster
+type seedClientProvider interface {
+	GetSeedClusterAdminRuntimeClient() ctrlruntimeclient.Client
+}
+
+func getClusterNodesUsage(ctx context.Context, clusterProvider provider.ClusterProvider, cluster *kubermaticv1.Cluster, dc *kubermaticv1.Datacenter, nodeSizeGetter NodeSizeGetter) (*QuotaChange, error) {
+	client, err := clusterProvider.GetAdminClientForCustomerCluster(cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	machineDeployments := &clusterv1alpha1.MachineDeploymentList{}
+	if err := client.List(ctx, machineDeployments, ctrlruntimeclient.InNamespace(metav1.NamespaceSystem)); err != nil {
+		return nil, err
+	}
+
+	secretKeySelector := func(*providerconfig.GlobalSecretKeySelector, string) (string, error) {
+		return "", fmt.Errorf("the cloud credentials of cluster %s are not available", cluster.Name)
+	}
+	if seedClientProvider, ok := clusterProvider.(seedClientProvider); ok {
+		secretKeySelector = provider.SecretKeySelectorValueFuncFactory(ctx, seedClientProvider.GetSeedClusterAdminRuntimeClient())
+	}
+
+	usage := &QuotaChange{}
+	for i := range machineDeployments.Items {
+		md := &machineDeployments.Items[i]
+		if md.DeletionTimestamp != nil {
+			continue
+		}
+		mdUsage := MachineDeploymentUsage(ctx, cluster, dc, secretKeySelector, md, nodeSizeGetter)
+		usage.Nodes += mdUsage.Nodes
+		usage.CPUs += mdUsage.CPUs
+		usage.MemoryMB += mdUsage.MemoryMB
+		usage.SizeUnknown = usage.SizeUnknown || mdUsage.SizeUnknown
+	}
+
+	return usage, nil
+}
+
+// CheckProjectQuota checks that the project stays within its quota after the given change. Only the limits
+// of resources which are increased by the change are enforced, so that a project which exceeds a lowered
+// quota can still shrink. It returns the usage of the project after the change, or nil if the project has no quota.
+//
+// The usage is calculated from the clusters of all seeds and the resources are created after the check, without
+// any lock. Concurrent requests for the same project may therefore all pass the check and together exceed the
+// quota by the resources they add.
+func CheckProjectQuota(ctx context.Context, project *kubermaticv1.Project, change QuotaChange, seedsGetter provider.SeedsGetter, clusterProviderGetter provider.ClusterProviderGetter, nodeSizeGetter NodeSizeGetter) (*kubermaticv1.ProjectResourceUsage, error) {
+	quota := project.Spec.Quota
+	if quota == nil {
+		return nil, nil
+	}
+
+	currentUsage, err := getProjectResourceUsage(ctx, project, seedsGetter, clusterProviderGetter, nodeSizeGetter)
+	if err != nil {
+		return nil, err
+	}
+	usage := &currentUsage.ProjectResourceUsage
+	usage.Clusters += change.Clusters
+	usage.Nodes += change.Nodes
+	usage.CPUs += change.CPUs
+	usage.MemoryMB += change.MemoryMB
+
+	if change.SizeUnknown && (quota.MaxCPUs != nil || quota.MaxMemoryMB != nil) {
+		return nil, errors.New(http.StatusForbidden, fmt.Sprintf("forbidden: the size of the nodes can not be determined, but project %s has a CPU or memory quota", project.Name))
+	}
+	growing := change.CPUs > 0 || change.MemoryMB > 0
+	if currentUsage.SizeUnknown && growing && (quota.MaxCPUs != nil || quota.MaxMemoryMB != nil) {
+		return nil, errors.New(http.StatusForbidden, fmt.Sprintf("forbidden: the size of existing nodes can not be determined, but project %s has a CPU or memory quota", project.Name))
+	}
+	if currentUsage.NodesUnknown && (growing || change.Nodes > 0) && (quota.MaxNodes != nil || quota.MaxCPUs != nil || quota.MaxMemoryMB != nil) {
+		return nil, errors.New(http.StatusForbidden, fmt.Sprintf("forbidden: the nodes of a cluster can not be listed, but project %s has a node, CPU or memory quota", project.Name))
+	}
+
+	limits := []struct {
+		name   string
+		change int
+		usage  int
+		max    *int
+	}{
+		{name: "clusters", change: change.Clusters, usage: usage.Clusters, max: quota.MaxClusters},
+		{name: "nodes", change: change.Nodes, usage: usage.Nodes, max: quota.MaxNodes},
+		{name: "vCPUs", change: change.CPUs, usage: usage.CPUs, max: quota.MaxCPUs},
+		{name: "MB of memory", change: change.MemoryMB, usage: usage.MemoryMB, max: quota.MaxMemoryMB},
+	}
+	for _, limit := range limits {
+		if limit.max != nil && limit.change > 0 && limit.usage > *limit.max {
+			return nil, errors.New(http.StatusForbidden, fmt.Sprintf("forbidden: project %s would use %d %s, but its quota allows only %d", project.Name, limit.usage, limit.name, *limit.max))
+		}
+	}
+
+	return usage, nil
+}
+
+// UpdateProjectResourceUsage stores the given usage in the status of the project. The usage is informational
+// only, so errors are logged and not returned.
+func UpdateProjectResourceUsage(privilegedProjectProvider provider.PrivilegedProjectProvider, project *kubermaticv1.Project, usage *kubermaticv1.ProjectResourceUsage) {
+	if usage == nil {
+		return
+	}
+
+	project = project.DeepCopy()
+	project.Status.Usage = usage
+	if _, err := privilegedProjectProvider.UpdateUnsecured(project); err != nil {
+		log.Logger.Errorw("failed to update the resource usage of the project", "project", project.Name, "error", err)
+	}
+}
+
+// RefreshProjectResourceUsage recalculates the usage of a project with a quota and stores it in the project status
+func RefreshProjectResourceUsage(ctx context.Context, project *kubermaticv1.Project, privilegedProjectProvider provider.PrivilegedProjectProvider, seedsGetter provider.SeedsGetter, clusterProviderGetter provider.ClusterProviderGetter, nodeSizeGetter NodeSizeGetter) {
+	if project.Spec.Quota == nil {
+		return
+	}
+
+	usage, err := GetProjectResourceUsage(ctx, project, seedsGetter, clusterProviderGetter, nodeSizeGetter)
+	if err != nil {
+		log.Logger.Errorw("failed to get the resource usage of the project", "project", project.Name, "error", err)
+		return
+	}
+	UpdateProjectResourceUsage(privilegedProjectProvider, project, usage)
+}
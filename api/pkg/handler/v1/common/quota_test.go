@@ -0,0 +1,159 @@
+/*
+Copyright 2020 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	apiv1 "github.com/kubermatic/kubermatic/api/pkg/api/v1"
+	kubermaticv1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
+	"github.com/kubermatic/kubermatic/api/pkg/handler/test"
+	"github.com/kubermatic/kubermatic/api/pkg/handler/v1/common"
+	"github.com/kubermatic/kubermatic/api/pkg/provider"
+	utilerrors "github.com/kubermatic/kubermatic/api/pkg/util/errors"
+
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestMachineDeploymentUsage(t *testing.T) {
+	t.Parallel()
+	awsNodeSizes := func(_ context.Context, _ *kubermaticv1.Cluster, _ *kubermaticv1.Datacenter, _ provider.SecretKeySelectorValueFunc, spec *apiv1.NodeSpec) (*common.NodeSize, error) {
+		if spec.Cloud.AWS == nil || spec.Cloud.AWS.InstanceType != "t2.micro" {
+			return nil, errors.New("unknown node size")
+		}
+		return &common.NodeSize{CPUs: 1, MemoryMB: 1024}, nil
+	}
+
+	testcases := []struct {
+		name            string
+		rawProviderSpec string
+		annotations     map[string]string
+		dc              *kubermaticv1.Datacenter
+		expectedUsage   common.QuotaChange
+	}{
+		{
+			name:            "scenario 1: the node size is derived from the provider spec",
+			rawProviderSpec: `{"cloudProvider":"aws","cloudProviderSpec":{"instanceType":"t2.micro"},"operatingSystem":"ubuntu","operatingSystemSpec":{}}`,
+			annotations:     map[string]string{"kubermatic.io/node-cpus": "0", "kubermatic.io/node-memory-mb": "0"},
+			dc:              &kubermaticv1.Datacenter{},
+			expectedUsage:   common.QuotaChange{ProjectResourceUsage: kubermaticv1.ProjectResourceUsage{Nodes: 1, CPUs: 1, MemoryMB: 1024}},
+		},
+		{
+			name:            "scenario 2: nodes of an unknown size are counted without their CPUs and memory",
+			rawProviderSpec: `{"cloudProvider":"aws","cloudProviderSpec":{"instanceType":"x1.32xlarge"},"operatingSystem":"ubuntu","operatingSystemSpec":{}}`,
+			dc:              &kubermaticv1.Datacenter{},
+			expectedUsage:   common.QuotaChange{ProjectResourceUsage: kubermaticv1.ProjectResourceUsage{Nodes: 1}, SizeUnknown: true},
+		},
+		{
+			name:            "scenario 3: the node size is unknown without the datacenter of the cluster",
+			rawProviderSpec: `{"cloudProvider":"aws","cloudProviderSpec":{"instanceType":"t2.micro"},"operatingSystem":"ubuntu","operatingSystemSpec":{}}`,
+			expectedUsage:   common.QuotaChange{ProjectResourceUsage: kubermaticv1.ProjectResourceUsage{Nodes: 1}, SizeUnknown: true},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			md := test.GenTestMachineDeployment("venus", tc.rawProviderSpec, nil, false)
+			md.Annotations = tc.annotations
+
+			usage := common.MachineDeploymentUsage(context.Background(), test.GenDefaultCluster(), tc.dc, nil, md, awsNodeSizes)
+			if usage != tc.expectedUsage {
+				t.Errorf("expected usage %+v, got %+v", tc.expectedUsage, usage)
+			}
+		})
+	}
+}
+
+// unreachableClusterProvider lists a single cluster whose nodes can not be listed
+type unreachableClusterProvider struct {
+	provider.ClusterProvider
+	cluster *kubermaticv1.Cluster
+}
+
+func (p *unreachableClusterProvider) List(*kubermaticv1.Project, *provider.ClusterListOptions) (*kubermaticv1.ClusterList, error) {
+	return &kubermaticv1.ClusterList{Items: []kubermaticv1.Cluster{*p.cluster}}, nil
+}
+
+func (p *unreachableClusterProvider) GetAdminClientForCustomerCluster(*kubermaticv1.Cluster) (ctrlruntimeclient.Client, error) {
+	return nil, errors.New("cluster is not reachable")
+}
+
+func TestCheckProjectQuotaWithUnreachableCluster(t *testing.T) {
+	t.Parallel()
+	nodeSizes := func(context.Context, *kubermaticv1.Cluster, *kubermaticv1.Datacenter, provider.SecretKeySelectorValueFunc, *apiv1.NodeSpec) (*common.NodeSize, error) {
+		return &common.NodeSize{CPUs: 1, MemoryMB: 1024}, nil
+	}
+	seedsGetter := func() (map[string]*kubermaticv1.Seed, error) {
+		return map[string]*kubermaticv1.Seed{"us-central1": test.GenTestSeed()}, nil
+	}
+	clusterProviderGetter := func(*kubermaticv1.Seed) (provider.ClusterProvider, error) {
+		return &unreachableClusterProvider{cluster: test.GenDefaultCluster()}, nil
+	}
+	maxClusters, maxNodes, maxCPUs := 5, 5, 5
+
+	testcases := []struct {
+		name           string
+		quota          kubermaticv1.ProjectQuota
+		change         common.QuotaChange
+		expectedStatus int
+	}{
+		{
+			name:           "scenario 1: nodes can not be added with a node quota",
+			quota:          kubermaticv1.ProjectQuota{MaxNodes: &maxNodes},
+			change:         common.NodesQuotaChange(1, &common.NodeSize{CPUs: 1, MemoryMB: 1024}),
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "scenario 2: nodes can not be added with a CPU quota",
+			quota:          kubermaticv1.ProjectQuota{MaxCPUs: &maxCPUs},
+			change:         common.NodesQuotaChange(1, &common.NodeSize{CPUs: 1, MemoryMB: 1024}),
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:   "scenario 3: nodes can be added with a cluster quota only",
+			quota:  kubermaticv1.ProjectQuota{MaxClusters: &maxClusters},
+			change: common.NodesQuotaChange(1, &common.NodeSize{CPUs: 1, MemoryMB: 1024}),
+		},
+		{
+			name:   "scenario 4: clusters can be added with a node quota",
+			quota:  kubermaticv1.ProjectQuota{MaxClusters: &maxClusters, MaxNodes: &maxNodes},
+			change: common.QuotaChange{ProjectResourceUsage: kubermaticv1.ProjectResourceUsage{Clusters: 1}},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			project := test.GenDefaultProject()
+			project.Spec.Quota = &tc.quota
+
+			_, err := common.CheckProjectQuota(context.Background(), project, tc.change, seedsGetter, clusterProviderGetter, nodeSizes)
+			if tc.expectedStatus == 0 {
+				if err != nil {
+					t.Fatalf("expected the change to be allowed, got %v", err)
+				}
+				return
+			}
+			httpErr, ok := err.(utilerrors.HTTPError)
+			if !ok || httpErr.StatusCode() != tc.expectedStatus {
+				t.Fatalf("expected an error with status %d, got %v", tc.expectedStatus, err)
+			}
+		})
+	}
+}
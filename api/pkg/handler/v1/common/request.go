@@ -48,7 +48,7 @@ type ProjectIDGetter interface {
 }
 
 // GetProjectRq defines HTTP request for getProject endpoint
-// swagger:parameters getProject getUsersForProject listClustersForProject listServiceAccounts
+// swagger:parameters getProject getUsersForProject listClustersForProject listServiceAccounts listClusterTemplates
 type GetProjectRq struct {
 	ProjectReq
 }
@@ -95,6 +95,11 @@ type GetClusterReq struct {
 	ClusterID string `json:"cluster_id"`
 }
 
+// GetClusterID returns the ID of the cluster the request is about
+func (req GetClusterReq) GetClusterID() string {
+	return req.ClusterID
+}
+
 func DecodeGetClusterReq(c context.Context, r *http.Request) (interface{}, error) {
 	var req GetClusterReq
 	clusterID, err := DecodeClusterID(c, r)
@@ -31,6 +31,7 @@ import (
 
 	apiv1 "github.com/kubermatic/kubermatic/api/pkg/api/v1"
 	kubermaticv1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
+	"github.com/kubermatic/kubermatic/api/pkg/handler/audit"
 	"github.com/kubermatic/kubermatic/api/pkg/handler/v1/common"
 	"github.com/kubermatic/kubermatic/api/pkg/log"
 	"github.com/kubermatic/kubermatic/api/pkg/provider"
@@ -362,10 +363,14 @@ func UpdateEndpoint(seedsGetter provider.SeedsGetter, userInfoGetter provider.Us
 		}
 
 		// get the dc to update
-		if _, ok := seed.Spec.Datacenters[req.DCToUpdate]; !ok {
+		currentDC, ok := seed.Spec.Datacenters[req.DCToUpdate]
+		if !ok {
 			return nil, errors.New(http.StatusBadRequest,
 				fmt.Sprintf("Bad request: datacenter %q does not exists", req.DCToUpdate))
 		}
+		if currentAPIDC, err := convertInternalDCToExternal(&currentDC, req.DCToUpdate, req.Body.Spec.Seed); err == nil {
+			audit.RecordBefore(ctx, currentAPIDC)
+		}
 
 		// Do an extra check if name changed and remove old dc
 		if !strings.EqualFold(req.DCToUpdate, req.Body.Name) {
@@ -438,6 +443,7 @@ func PatchEndpoint(seedsGetter provider.SeedsGetter, userInfoGetter provider.Use
 			return nil, errors.New(http.StatusInternalServerError, fmt.Sprintf("failed to convert current dc: %v", err))
 		}
 
+		audit.RecordBefore(ctx, currentAPIDC)
 		currentDCJSON, err := json.Marshal(currentAPIDC)
 		if err != nil {
 			return nil, errors.New(http.StatusInternalServerError, fmt.Sprintf("failed to marshal current dc: %v", err))
@@ -652,27 +658,29 @@ func ConvertInternalDCToExternalSpec(dc *kubermaticv1.Datacenter, seedName strin
 		return nil, err
 	}
 	return &apiv1.DatacenterSpec{
-		Seed:                     seedName,
-		Location:                 dc.Location,
-		Country:                  dc.Country,
-		Provider:                 p,
-		Node:                     dc.Node,
-		Digitalocean:             dc.Spec.Digitalocean,
-		AWS:                      dc.Spec.AWS,
-		BringYourOwn:             dc.Spec.BringYourOwn,
-		Openstack:                dc.Spec.Openstack,
-		Hetzner:                  dc.Spec.Hetzner,
-		VSphere:                  dc.Spec.VSphere,
-		Azure:                    dc.Spec.Azure,
-		Packet:                   dc.Spec.Packet,
-		GCP:                      dc.Spec.GCP,
-		Kubevirt:                 dc.Spec.Kubevirt,
-		Alibaba:                  dc.Spec.Alibaba,
-		Fake:                     dc.Spec.Fake,
-		RequiredEmailDomain:      dc.Spec.RequiredEmailDomain,
-		RequiredEmailDomains:     dc.Spec.RequiredEmailDomains,
-		EnforceAuditLogging:      dc.Spec.EnforceAuditLogging,
-		EnforcePodSecurityPolicy: dc.Spec.EnforcePodSecurityPolicy,
+		Seed:                      seedName,
+		Location:                  dc.Location,
+		Country:                   dc.Country,
+		Provider:                  p,
+		Node:                      dc.Node,
+		Digitalocean:              dc.Spec.Digitalocean,
+		AWS:                       dc.Spec.AWS,
+		BringYourOwn:              dc.Spec.BringYourOwn,
+		Openstack:                 dc.Spec.Openstack,
+		Hetzner:                   dc.Spec.Hetzner,
+		VSphere:                   dc.Spec.VSphere,
+		Azure:                     dc.Spec.Azure,
+		Packet:                    dc.Spec.Packet,
+		GCP:                       dc.Spec.GCP,
+		Kubevirt:                  dc.Spec.Kubevirt,
+		Alibaba:                   dc.Spec.Alibaba,
+		Fake:                      dc.Spec.Fake,
+		RequiredEmailDomain:       dc.Spec.RequiredEmailDomain,
+		RequiredEmailDomains:      dc.Spec.RequiredEmailDomains,
+		EnforceAuditLogging:       dc.Spec.EnforceAuditLogging,
+		EnforcedAuditPolicyPreset: dc.Spec.EnforcedAuditPolicyPreset,
+		EnforcePodSecurityPolicy:  dc.Spec.EnforcePodSecurityPolicy,
+		BackupPolicy:              dc.Spec.BackupPolicy,
 	}, nil
 }
 
@@ -682,22 +690,24 @@ func convertExternalDCToInternal(datacenter *apiv1.DatacenterSpec) kubermaticv1.
 		Location: datacenter.Location,
 		Node:     datacenter.Node,
 		Spec: kubermaticv1.DatacenterSpec{
-			Digitalocean:             datacenter.Digitalocean,
-			BringYourOwn:             datacenter.BringYourOwn,
-			AWS:                      datacenter.AWS,
-			Azure:                    datacenter.Azure,
-			Openstack:                datacenter.Openstack,
-			Packet:                   datacenter.Packet,
-			Hetzner:                  datacenter.Hetzner,
-			VSphere:                  datacenter.VSphere,
-			GCP:                      datacenter.GCP,
-			Kubevirt:                 datacenter.Kubevirt,
-			Alibaba:                  datacenter.Alibaba,
-			Fake:                     datacenter.Fake,
-			RequiredEmailDomain:      datacenter.RequiredEmailDomain,
-			RequiredEmailDomains:     datacenter.RequiredEmailDomains,
-			EnforceAuditLogging:      datacenter.EnforceAuditLogging,
-			EnforcePodSecurityPolicy: datacenter.EnforcePodSecurityPolicy,
+			Digitalocean:              datacenter.Digitalocean,
+			BringYourOwn:              datacenter.BringYourOwn,
+			AWS:                       datacenter.AWS,
+			Azure:                     datacenter.Azure,
+			Openstack:                 datacenter.Openstack,
+			Packet:                    datacenter.Packet,
+			Hetzner:                   datacenter.Hetzner,
+			VSphere:                   datacenter.VSphere,
+			GCP:                       datacenter.GCP,
+			Kubevirt:                  datacenter.Kubevirt,
+			Alibaba:                   datacenter.Alibaba,
+			Fake:                      datacenter.Fake,
+			RequiredEmailDomain:       datacenter.RequiredEmailDomain,
+			RequiredEmailDomains:      datacenter.RequiredEmailDomains,
+			EnforceAuditLogging:       datacenter.EnforceAuditLogging,
+			EnforcedAuditPolicyPreset: datacenter.EnforcedAuditPolicyPreset,
+			EnforcePodSecurityPolicy:  datacenter.EnforcePodSecurityPolicy,
+			BackupPolicy:              datacenter.BackupPolicy,
 		},
 	}
 }
@@ -0,0 +1,100 @@
+/*
+Copyright 2020 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etcdbackup
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/go-kit/kit/endpoint"
+
+	apiv1 "github.com/kubermatic/kubermatic/api/pkg/api/v1"
+	backupcontroller "github.com/kubermatic/kubermatic/api/pkg/controller/seed-controller-manager/backup"
+	"github.com/kubermatic/kubermatic/api/pkg/handler/middleware"
+	"github.com/kubermatic/kubermatic/api/pkg/handler/v1/cluster"
+	"github.com/kubermatic/kubermatic/api/pkg/handler/v1/common"
+	"github.com/kubermatic/kubermatic/api/pkg/provider"
+	k8cerrors "github.com/kubermatic/kubermatic/api/pkg/util/errors"
+)
+
+// listReq defines HTTP request for listEtcdBackups endpoint
+// swagger:parameters listEtcdBackups
+type listReq struct {
+	common.GetClusterReq
+}
+
+func DecodeListEtcdBackups(c context.Context, r *http.Request) (interface{}, error) {
+	var req listReq
+
+	cr, err := common.DecodeGetClusterReq(c, r)
+	if err != nil {
+		return nil, err
+	}
+
+	req.GetClusterReq = cr.(common.GetClusterReq)
+
+	return req, nil
+}
+
+// ListEtcdBackupsEndpoint lists the snapshots of the cluster in its backup destination
+func ListEtcdBackupsEndpoint(projectProvider provider.ProjectProvider, privilegedProjectProvider provider.PrivilegedProjectProvider, seedsGetter provider.SeedsGetter, userInfoGetter provider.UserInfoGetter) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(listReq)
+		cluster, err := cluster.GetCluster(ctx, projectProvider, privilegedProjectProvider, userInfoGetter, req.ProjectID, req.ClusterID, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		userInfo, err := userInfoGetter(ctx, "")
+		if err != nil {
+			return nil, common.KubernetesErrorToHTTPError(err)
+		}
+		seed, _, err := provider.DatacenterFromSeedMap(userInfo, seedsGetter, cluster.Spec.Cloud.DatacenterName)
+		if err != nil {
+			return nil, err
+		}
+		destination, err := backupcontroller.GetClusterBackupDestination(seed, cluster)
+		if err != nil {
+			return nil, k8cerrors.New(http.StatusInternalServerError, err.Error())
+		}
+		privilegedClusterProvider := ctx.Value(middleware.PrivilegedClusterProviderContextKey).(provider.PrivilegedClusterProvider)
+		seedClient := privilegedClusterProvider.GetSeedClusterAdminRuntimeClient()
+		if destination == nil {
+			// The API doesn't know the configuration of the backup containers, so the destination of
+			// clusters without one is read from the backup CronJob the controller created for them.
+			destination, err = backupcontroller.GetCronJobBackupDestination(ctx, seedClient, cluster)
+			if err != nil {
+				return nil, k8cerrors.New(http.StatusInternalServerError, err.Error())
+			}
+			if destination == nil {
+				// backups aren't set up for the cluster yet
+				return []apiv1.EtcdSnapshot{}, nil
+			}
+		}
+
+		snapshots, err := backupcontroller.ListSnapshots(ctx, seedClient, cluster, destination)
+		if err != nil {
+			return nil, k8cerrors.New(http.StatusInternalServerError, err.Error())
+		}
+
+		result := []apiv1.EtcdSnapshot{}
+		for _, snapshot := range snapshots {
+			result = append(result, common.ConvertInternalEtcdSnapshotToExternal(snapshot))
+		}
+		return result, nil
+	}
+}
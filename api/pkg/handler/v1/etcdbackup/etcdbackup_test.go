@@ -0,0 +1,163 @@
+/*
+Copyright 2020 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etcdbackup_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	apiv1 "github.com/kubermatic/kubermatic/api/pkg/api/v1"
+	backupcontroller "github.com/kubermatic/kubermatic/api/pkg/controller/seed-controller-manager/backup"
+	kubermaticv1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
+	"github.com/kubermatic/kubermatic/api/pkg/handler/test"
+	"github.com/kubermatic/kubermatic/api/pkg/handler/test/hack"
+
+	batchv1 "k8s.io/api/batch/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestListEtcdBackups(t *testing.T) {
+	t.Parallel()
+	s3 := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.URL.Query()["location"]; ok {
+			fmt.Fprint(w, `<LocationConstraint xmlns="http://s3.amazonaws.com/doc/2006-03-01/">us-east-1</LocationConstraint>`)
+			return
+		}
+		fmt.Fprint(w, `<ListBucketResult xmlns="http://s3.amazonaws.com/doc/2006-03-01/">
+  <Name>etcd-backups</Name>
+  <IsTruncated>false</IsTruncated>
+  <Contents>
+    <Key>defClusterID-storeuploader-2020-01-02T10:00:00-snapshot.db</Key>
+    <LastModified>2020-01-02T10:00:05.000Z</LastModified>
+    <Size>2048</Size>
+  </Contents>
+</ListBucketResult>`)
+	}))
+	defer s3.Close()
+
+	seedsGetter := func() (map[string]*kubermaticv1.Seed, error) {
+		seed := test.GenTestSeed()
+		seed.Spec.Datacenters["FakeDatacenter"] = kubermaticv1.Datacenter{
+			Spec: kubermaticv1.DatacenterSpec{Fake: &kubermaticv1.DatacenterSpecFake{}},
+		}
+		seed.Spec.BackupDestinations = map[string]kubermaticv1.BackupDestination{
+			"offsite": {Endpoint: strings.TrimPrefix(s3.URL, "http://"), BucketName: "etcd-backups"},
+		}
+		return map[string]*kubermaticv1.Seed{seed.Name: seed}, nil
+	}
+	credentials := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "s3-credentials", Namespace: metav1.NamespaceSystem},
+		Data: map[string][]byte{
+			"ACCESS_KEY_ID":     []byte("foo"),
+			"SECRET_ACCESS_KEY": []byte("bar"),
+		},
+	}
+
+	clusterWithDestination := test.GenDefaultCluster()
+	clusterWithDestination.Spec.BackupPolicy = &kubermaticv1.EtcdBackupPolicy{Destination: "offsite"}
+	clusterWithRemovedDestination := test.GenDefaultCluster()
+	clusterWithRemovedDestination.Spec.BackupPolicy = &kubermaticv1.EtcdBackupPolicy{Destination: "removed"}
+	// the backup CronJob of clusters without destination stores the snapshots where its store container is configured to
+	backupCronJob := &batchv1beta1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{Name: backupcontroller.CronJobName(test.GenDefaultCluster()), Namespace: metav1.NamespaceSystem},
+		Spec: batchv1beta1.CronJobSpec{
+			JobTemplate: batchv1beta1.JobTemplateSpec{
+				Spec: batchv1.JobSpec{
+					Template: corev1.PodTemplateSpec{
+						Spec: corev1.PodSpec{
+							Containers: []corev1.Container{{
+								Name: "store-container",
+								Env: []corev1.EnvVar{
+									{Name: "BACKUP_ENDPOINT", Value: strings.TrimPrefix(s3.URL, "http://")},
+									{Name: "BACKUP_BUCKET", Value: "etcd-backups"},
+								},
+							}},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	testcases := []struct {
+		Name                   string
+		ExpectedResponse       string
+		ExpectedHTTPStatus     int
+		ExistingKubermaticObjs []runtime.Object
+		ExistingKubeObjs       []runtime.Object
+		ExistingAPIUser        *apiv1.User
+	}{
+		// scenario 1
+		{
+			Name:                   "scenario 1: list the snapshots of a cluster with a backup destination",
+			ExpectedResponse:       `[{"name":"defClusterID-storeuploader-2020-01-02T10:00:00-snapshot.db","size":2048,"creationTimestamp":"2020-01-02T10:00:05Z"}]`,
+			ExpectedHTTPStatus:     http.StatusOK,
+			ExistingKubermaticObjs: test.GenDefaultKubermaticObjects(clusterWithDestination),
+			ExistingAPIUser:        test.GenDefaultAPIUser(),
+		},
+		// scenario 2
+		{
+			Name:                   "scenario 2: snapshots can not be listed for a cluster whose backup destination has been removed",
+			ExpectedResponse:       `{"error":{"code":500,"message":"backup destination \"removed\" does not exist in seed \"us-central1\""}}`,
+			ExpectedHTTPStatus:     http.StatusInternalServerError,
+			ExistingKubermaticObjs: test.GenDefaultKubermaticObjects(clusterWithRemovedDestination),
+			ExistingAPIUser:        test.GenDefaultAPIUser(),
+		},
+		// scenario 3
+		{
+			Name:                   "scenario 3: list the snapshots of a cluster without backup destination where its backup CronJob stores them",
+			ExpectedResponse:       `[{"name":"defClusterID-storeuploader-2020-01-02T10:00:00-snapshot.db","size":2048,"creationTimestamp":"2020-01-02T10:00:05Z"}]`,
+			ExpectedHTTPStatus:     http.StatusOK,
+			ExistingKubermaticObjs: test.GenDefaultKubermaticObjects(test.GenDefaultCluster()),
+			ExistingKubeObjs:       []runtime.Object{backupCronJob},
+			ExistingAPIUser:        test.GenDefaultAPIUser(),
+		},
+		// scenario 4
+		{
+			Name:                   "scenario 4: a cluster without backup destination and backup CronJob has no snapshots",
+			ExpectedResponse:       `[]`,
+			ExpectedHTTPStatus:     http.StatusOK,
+			ExistingKubermaticObjs: test.GenDefaultKubermaticObjects(test.GenDefaultCluster()),
+			ExistingAPIUser:        test.GenDefaultAPIUser(),
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.Name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", fmt.Sprintf("/api/v1/projects/%s/dc/us-central1/clusters/%s/backups", test.GenDefaultProject().Name, test.GenDefaultCluster().Name), strings.NewReader(""))
+			res := httptest.NewRecorder()
+			ep, _, err := test.CreateTestEndpointAndGetClients(*tc.ExistingAPIUser, seedsGetter, append([]runtime.Object{credentials}, tc.ExistingKubeObjs...), nil, tc.ExistingKubermaticObjs, nil, nil, hack.NewTestRouting)
+			if err != nil {
+				t.Fatalf("failed to create test endpoint due to %v", err)
+			}
+
+			ep.ServeHTTP(res, req)
+
+			if res.Code != tc.ExpectedHTTPStatus {
+				t.Fatalf("Expected HTTP status code %d, got %d: %s", tc.ExpectedHTTPStatus, res.Code, res.Body.String())
+			}
+
+			test.CompareWithResult(t, res, tc.ExpectedResponse)
+		})
+	}
+}
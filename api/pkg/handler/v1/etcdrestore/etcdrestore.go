@@ -0,0 +1,252 @@
+/*
+Copyright 2020 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etcdrestore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/gorilla/mux"
+
+	apiv1 "github.com/kubermatic/kubermatic/api/pkg/api/v1"
+	kubermaticapiv1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
+	"github.com/kubermatic/kubermatic/api/pkg/handler/middleware"
+	"github.com/kubermatic/kubermatic/api/pkg/handler/v1/cluster"
+	"github.com/kubermatic/kubermatic/api/pkg/handler/v1/common"
+	"github.com/kubermatic/kubermatic/api/pkg/provider"
+	k8cerrors "github.com/kubermatic/kubermatic/api/pkg/util/errors"
+)
+
+// restoreReq defines HTTP request for getEtcdRestore
+// swagger:parameters getEtcdRestore
+type restoreReq struct {
+	common.GetClusterReq
+	// in: path
+	RestoreID string `json:"restore_id"`
+}
+
+// listReq defines HTTP request for listEtcdRestores endpoint
+// swagger:parameters listEtcdRestores
+type listReq struct {
+	common.GetClusterReq
+}
+
+// createReq defines HTTP request for createEtcdRestore endpoint
+// swagger:parameters createEtcdRestore
+type createReq struct {
+	common.GetClusterReq
+	// in: body
+	Body apiv1.EtcdRestore
+}
+
+func DecodeGetEtcdRestore(c context.Context, r *http.Request) (interface{}, error) {
+	var req restoreReq
+
+	cr, err := common.DecodeGetClusterReq(c, r)
+	if err != nil {
+		return nil, err
+	}
+
+	restoreID := mux.Vars(r)["restore_id"]
+	if restoreID == "" {
+		return nil, fmt.Errorf("'restore_id' parameter is required but was not provided")
+	}
+
+	req.GetClusterReq = cr.(common.GetClusterReq)
+	req.RestoreID = restoreID
+
+	return req, nil
+}
+
+func DecodeListEtcdRestores(c context.Context, r *http.Request) (interface{}, error) {
+	var req listReq
+
+	cr, err := common.DecodeGetClusterReq(c, r)
+	if err != nil {
+		return nil, err
+	}
+
+	req.GetClusterReq = cr.(common.GetClusterReq)
+
+	return req, nil
+}
+
+func DecodeCreateEtcdRestore(c context.Context, r *http.Request) (interface{}, error) {
+	var req createReq
+
+	cr, err := common.DecodeGetClusterReq(c, r)
+	if err != nil {
+		return nil, err
+	}
+
+	req.GetClusterReq = cr.(common.GetClusterReq)
+
+	if err := json.NewDecoder(r.Body).Decode(&req.Body); err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+func CreateEtcdRestoreEndpoint(projectProvider provider.ProjectProvider, privilegedProjectProvider provider.PrivilegedProjectProvider, userInfoGetter provider.UserInfoGetter) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(createReq)
+		if req.Body.Spec.BackupName == "" {
+			return nil, k8cerrors.NewBadRequest("spec.backupName is required")
+		}
+
+		cluster, err := cluster.GetCluster(ctx, projectProvider, privilegedProjectProvider, userInfoGetter, req.ProjectID, req.ClusterID, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		restore, err := createEtcdRestore(ctx, userInfoGetter, cluster, req.ProjectID, req.Body.Spec.BackupName)
+		if err != nil {
+			return nil, common.KubernetesErrorToHTTPError(err)
+		}
+
+		return convertInternalEtcdRestoreToExternal(restore), nil
+	}
+}
+
+func createEtcdRestore(ctx context.Context, userInfoGetter provider.UserInfoGetter, cluster *kubermaticapiv1.Cluster, projectID, backupName string) (*kubermaticapiv1.EtcdRestore, error) {
+	adminUserInfo, err := userInfoGetter(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	if adminUserInfo.IsAdmin {
+		privilegedEtcdRestoreProvider := ctx.Value(middleware.PrivilegedEtcdRestoreProviderContextKey).(provider.PrivilegedEtcdRestoreProvider)
+		return privilegedEtcdRestoreProvider.NewUnsecured(cluster, backupName)
+	}
+	userInfo, err := userInfoGetter(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	etcdRestoreProvider := ctx.Value(middleware.EtcdRestoreProviderContextKey).(provider.EtcdRestoreProvider)
+	return etcdRestoreProvider.New(userInfo, cluster, backupName)
+}
+
+func GetEtcdRestoreEndpoint(projectProvider provider.ProjectProvider, privilegedProjectProvider provider.PrivilegedProjectProvider, userInfoGetter provider.UserInfoGetter) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(restoreReq)
+		cluster, err := cluster.GetCluster(ctx, projectProvider, privilegedProjectProvider, userInfoGetter, req.ProjectID, req.ClusterID, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		restore, err := getEtcdRestore(ctx, userInfoGetter, cluster, req.ProjectID, req.RestoreID)
+		if err != nil {
+			return nil, common.KubernetesErrorToHTTPError(err)
+		}
+		if restore.Spec.Cluster.Name != cluster.Name {
+			return nil, k8cerrors.NewNotFound("EtcdRestore", req.RestoreID)
+		}
+
+		return convertInternalEtcdRestoreToExternal(restore), nil
+	}
+}
+
+func getEtcdRestore(ctx context.Context, userInfoGetter provider.UserInfoGetter, cluster *kubermaticapiv1.Cluster, projectID, restoreID string) (*kubermaticapiv1.EtcdRestore, error) {
+	adminUserInfo, err := userInfoGetter(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	if adminUserInfo.IsAdmin {
+		privilegedEtcdRestoreProvider := ctx.Value(middleware.PrivilegedEtcdRestoreProviderContextKey).(provider.PrivilegedEtcdRestoreProvider)
+		return privilegedEtcdRestoreProvider.GetUnsecured(cluster, restoreID)
+	}
+	userInfo, err := userInfoGetter(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	etcdRestoreProvider := ctx.Value(middleware.EtcdRestoreProviderContextKey).(provider.EtcdRestoreProvider)
+	return etcdRestoreProvider.Get(userInfo, cluster, restoreID)
+}
+
+func ListEtcdRestoreEndpoint(projectProvider provider.ProjectProvider, privilegedProjectProvider provider.PrivilegedProjectProvider, userInfoGetter provider.UserInfoGetter) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(listReq)
+		cluster, err := cluster.GetCluster(ctx, projectProvider, privilegedProjectProvider, userInfoGetter, req.ProjectID, req.ClusterID, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		restores, err := listEtcdRestores(ctx, userInfoGetter, cluster, req.ProjectID)
+		if err != nil {
+			return nil, common.KubernetesErrorToHTTPError(err)
+		}
+
+		result := []*apiv1.EtcdRestore{}
+		for _, restore := range restores {
+			result = append(result, convertInternalEtcdRestoreToExternal(restore))
+		}
+		return result, nil
+	}
+}
+
+func listEtcdRestores(ctx context.Context, userInfoGetter provider.UserInfoGetter, cluster *kubermaticapiv1.Cluster, projectID string) ([]*kubermaticapiv1.EtcdRestore, error) {
+	adminUserInfo, err := userInfoGetter(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	if adminUserInfo.IsAdmin {
+		privilegedEtcdRestoreProvider := ctx.Value(middleware.PrivilegedEtcdRestoreProviderContextKey).(provider.PrivilegedEtcdRestoreProvider)
+		return privilegedEtcdRestoreProvider.ListUnsecured(cluster)
+	}
+	userInfo, err := userInfoGetter(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	etcdRestoreProvider := ctx.Value(middleware.EtcdRestoreProviderContextKey).(provider.EtcdRestoreProvider)
+	return etcdRestoreProvider.List(userInfo, cluster)
+}
+
+func convertInternalEtcdRestoreToExternal(internalRestore *kubermaticapiv1.EtcdRestore) *apiv1.EtcdRestore {
+	result := &apiv1.EtcdRestore{
+		ObjectMeta: apiv1.ObjectMeta{
+			ID:                internalRestore.Name,
+			Name:              internalRestore.Name,
+			CreationTimestamp: apiv1.NewTime(internalRestore.CreationTimestamp.Time),
+			DeletionTimestamp: func() *apiv1.Time {
+				if internalRestore.DeletionTimestamp != nil {
+					deletionTimestamp := apiv1.NewTime(internalRestore.DeletionTimestamp.Time)
+					return &deletionTimestamp
+				}
+				return nil
+			}(),
+		},
+		Spec: apiv1.EtcdRestoreSpec{
+			BackupName: internalRestore.Spec.BackupName,
+		},
+		Status: apiv1.EtcdRestoreStatus{
+			Phase: string(internalRestore.Status.Phase),
+		},
+	}
+	for _, condition := range internalRestore.Status.Conditions {
+		result.Status.Conditions = append(result.Status.Conditions, apiv1.EtcdRestoreCondition{
+			Type:               string(condition.Type),
+			Status:             string(condition.Status),
+			LastTransitionTime: apiv1.NewTime(condition.LastTransitionTime.Time),
+			Reason:             condition.Reason,
+			Message:            condition.Message,
+		})
+	}
+	return result
+}
@@ -0,0 +1,226 @@
+/*
+Copyright 2020 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etcdrestore_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	apiv1 "github.com/kubermatic/kubermatic/api/pkg/api/v1"
+	kubermaticv1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
+	"github.com/kubermatic/kubermatic/api/pkg/handler/test"
+	"github.com/kubermatic/kubermatic/api/pkg/handler/test/hack"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestListEtcdRestores(t *testing.T) {
+	t.Parallel()
+	creationTime := test.DefaultCreationTimestamp()
+	cluster := test.GenDefaultCluster()
+	cluster.Status.NamespaceName = fmt.Sprintf("cluster-%s", cluster.Name)
+
+	testcases := []struct {
+		Name                   string
+		ExpectedResponse       []apiv1.EtcdRestore
+		ExpectedHTTPStatus     int
+		ExistingKubermaticObjs []runtime.Object
+		ExistingAPIUser        *apiv1.User
+	}{
+		// scenario 1
+		{
+			Name: "scenario 1: gets a list of restores of the cluster",
+			ExpectedResponse: []apiv1.EtcdRestore{
+				{
+					ObjectMeta: apiv1.ObjectMeta{
+						ID:                "restore1",
+						Name:              "restore1",
+						CreationTimestamp: apiv1.NewTime(creationTime),
+					},
+					Spec: apiv1.EtcdRestoreSpec{
+						BackupName: "snapshot1",
+					},
+					Status: apiv1.EtcdRestoreStatus{
+						Phase: string(kubermaticv1.EtcdRestorePhaseStarted),
+					},
+				},
+			},
+			ExpectedHTTPStatus: http.StatusOK,
+			ExistingKubermaticObjs: test.GenDefaultKubermaticObjects(
+				cluster,
+				genEtcdRestore("restore1", "snapshot1", cluster.Name, cluster.Status.NamespaceName, creationTime),
+				genEtcdRestore("restore2", "snapshot2", "other-cluster", cluster.Status.NamespaceName, creationTime),
+			),
+			ExistingAPIUser: test.GenDefaultAPIUser(),
+		},
+		// scenario 2
+		{
+			Name: "scenario 2: the admin John can list restores of Bob's cluster",
+			ExpectedResponse: []apiv1.EtcdRestore{
+				{
+					ObjectMeta: apiv1.ObjectMeta{
+						ID:                "restore1",
+						Name:              "restore1",
+						CreationTimestamp: apiv1.NewTime(creationTime),
+					},
+					Spec: apiv1.EtcdRestoreSpec{
+						BackupName: "snapshot1",
+					},
+					Status: apiv1.EtcdRestoreStatus{
+						Phase: string(kubermaticv1.EtcdRestorePhaseStarted),
+					},
+				},
+			},
+			ExpectedHTTPStatus: http.StatusOK,
+			ExistingKubermaticObjs: test.GenDefaultKubermaticObjects(
+				cluster,
+				genUser("John", "john@acme.com", true),
+				genEtcdRestore("restore1", "snapshot1", cluster.Name, cluster.Status.NamespaceName, creationTime),
+			),
+			ExistingAPIUser: test.GenAPIUser("John", "john@acme.com"),
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.Name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", fmt.Sprintf("/api/v1/projects/%s/dc/us-central1/clusters/%s/restores", test.GenDefaultProject().Name, cluster.Name), strings.NewReader(""))
+			res := httptest.NewRecorder()
+			ep, err := test.CreateTestEndpoint(*tc.ExistingAPIUser, []runtime.Object{}, tc.ExistingKubermaticObjs, nil, nil, hack.NewTestRouting)
+			if err != nil {
+				t.Fatalf("failed to create test endpoint due to %v", err)
+			}
+
+			ep.ServeHTTP(res, req)
+
+			if res.Code != tc.ExpectedHTTPStatus {
+				t.Fatalf("Expected HTTP status code %d, got %d: %s", tc.ExpectedHTTPStatus, res.Code, res.Body.String())
+			}
+
+			bytes, err := json.Marshal(tc.ExpectedResponse)
+			if err != nil {
+				t.Fatalf("failed to marshall expected response %v", err)
+			}
+
+			test.CompareWithResult(t, res, string(bytes))
+		})
+	}
+}
+
+func TestCreateEtcdRestore(t *testing.T) {
+	t.Parallel()
+	cluster := test.GenDefaultCluster()
+	cluster.Status.NamespaceName = fmt.Sprintf("cluster-%s", cluster.Name)
+
+	testcases := []struct {
+		Name                   string
+		Body                   string
+		ExpectedBackupName     string
+		ExpectedHTTPStatus     int
+		ExistingKubermaticObjs []runtime.Object
+		ExistingAPIUser        *apiv1.User
+	}{
+		// scenario 1
+		{
+			Name:                   "scenario 1: create a restore",
+			Body:                   `{"spec": {"backupName": "snapshot1"}}`,
+			ExpectedBackupName:     "snapshot1",
+			ExpectedHTTPStatus:     http.StatusCreated,
+			ExistingKubermaticObjs: test.GenDefaultKubermaticObjects(cluster),
+			ExistingAPIUser:        test.GenDefaultAPIUser(),
+		},
+		// scenario 2
+		{
+			Name:                   "scenario 2: a restore without a backup name is rejected",
+			Body:                   `{"spec": {}}`,
+			ExpectedHTTPStatus:     http.StatusBadRequest,
+			ExistingKubermaticObjs: test.GenDefaultKubermaticObjects(cluster),
+			ExistingAPIUser:        test.GenDefaultAPIUser(),
+		},
+		// scenario 3
+		{
+			Name:                   "scenario 3: the admin John can create a restore for Bob's cluster",
+			Body:                   `{"spec": {"backupName": "snapshot1"}}`,
+			ExpectedBackupName:     "snapshot1",
+			ExpectedHTTPStatus:     http.StatusCreated,
+			ExistingKubermaticObjs: test.GenDefaultKubermaticObjects(cluster, genUser("John", "john@acme.com", true)),
+			ExistingAPIUser:        test.GenAPIUser("John", "john@acme.com"),
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.Name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", fmt.Sprintf("/api/v1/projects/%s/dc/us-central1/clusters/%s/restores", test.GenDefaultProject().Name, cluster.Name), strings.NewReader(tc.Body))
+			res := httptest.NewRecorder()
+			ep, err := test.CreateTestEndpoint(*tc.ExistingAPIUser, []runtime.Object{}, tc.ExistingKubermaticObjs, nil, nil, hack.NewTestRouting)
+			if err != nil {
+				t.Fatalf("failed to create test endpoint due to %v", err)
+			}
+
+			ep.ServeHTTP(res, req)
+
+			if res.Code != tc.ExpectedHTTPStatus {
+				t.Fatalf("Expected HTTP status code %d, got %d: %s", tc.ExpectedHTTPStatus, res.Code, res.Body.String())
+			}
+
+			if res.Code == http.StatusCreated {
+				restore := &apiv1.EtcdRestore{}
+				if err := json.Unmarshal(res.Body.Bytes(), restore); err != nil {
+					t.Fatalf("failed to unmarshal response: %v", err)
+				}
+				if restore.Name == "" {
+					t.Error("expected the restore to have a generated name")
+				}
+				if restore.Spec.BackupName != tc.ExpectedBackupName {
+					t.Errorf("expected backup name %q, got %q", tc.ExpectedBackupName, restore.Spec.BackupName)
+				}
+				if restore.Status.Phase != string(kubermaticv1.EtcdRestorePhaseStarted) {
+					t.Errorf("expected phase %q, got %q", kubermaticv1.EtcdRestorePhaseStarted, restore.Status.Phase)
+				}
+			}
+		})
+	}
+}
+
+func genEtcdRestore(name, backupName, clusterName, namespace string, creationTime time.Time) *kubermaticv1.EtcdRestore {
+	return &kubermaticv1.EtcdRestore{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         namespace,
+			CreationTimestamp: metav1.NewTime(creationTime),
+		},
+		Spec: kubermaticv1.EtcdRestoreSpec{
+			Cluster:    corev1.ObjectReference{Name: clusterName},
+			BackupName: backupName,
+		},
+		Status: kubermaticv1.EtcdRestoreStatus{
+			Phase: kubermaticv1.EtcdRestorePhaseStarted,
+		},
+	}
+}
+
+func genUser(name, email string, isAdmin bool) *kubermaticv1.User {
+	user := test.GenUser("", name, email)
+	user.Spec.IsAdmin = isAdmin
+	return user
+}
@@ -32,10 +32,12 @@ import (
 
 	apiv1 "github.com/kubermatic/kubermatic/api/pkg/api/v1"
 	kubermaticv1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
+	"github.com/kubermatic/kubermatic/api/pkg/handler/audit"
 	"github.com/kubermatic/kubermatic/api/pkg/handler/middleware"
 	"github.com/kubermatic/kubermatic/api/pkg/handler/v1/cluster"
 	"github.com/kubermatic/kubermatic/api/pkg/handler/v1/common"
 	"github.com/kubermatic/kubermatic/api/pkg/handler/v1/label"
+	"github.com/kubermatic/kubermatic/api/pkg/log"
 	machineconversions "github.com/kubermatic/kubermatic/api/pkg/machine"
 	"github.com/kubermatic/kubermatic/api/pkg/provider"
 	kubernetesprovider "github.com/kubermatic/kubermatic/api/pkg/provider/kubernetes"
@@ -89,7 +91,8 @@ func DecodeCreateNodeDeployment(c context.Context, r *http.Request) (interface{}
 	return req, nil
 }
 
-func CreateNodeDeployment(sshKeyProvider provider.SSHKeyProvider, projectProvider provider.ProjectProvider, privilegedProjectProvider provider.PrivilegedProjectProvider, seedsGetter provider.SeedsGetter, userInfoGetter provider.UserInfoGetter) endpoint.Endpoint {
+func CreateNodeDeployment(sshKeyProvider provider.SSHKeyProvider, projectProvider provider.ProjectProvider, privilegedProjectProvider provider.PrivilegedProjectProvider, seedsGetter provider.SeedsGetter, userInfoGetter provider.UserInfoGetter,
+	clusterProviderGetter provider.ClusterProviderGetter, nodeSizeGetter common.NodeSizeGetter) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		req := request.(createNodeDeploymentReq)
 		clusterProvider := ctx.Value(middleware.ClusterProviderContextKey).(provider.ClusterProvider)
@@ -117,7 +120,7 @@ func CreateNodeDeployment(sshKeyProvider provider.SSHKeyProvider, projectProvide
 			return nil, common.KubernetesErrorToHTTPError(err)
 		}
 
-		client, err := common.GetClusterClient(ctx, userInfoGetter, clusterProvider, cluster, project.Name)
+		client, err := common.GetNodeDeploymentClusterClient(ctx, userInfoGetter, clusterProvider, cluster, project.Name, "create")
 		if err != nil {
 			return nil, err
 		}
@@ -151,15 +154,26 @@ func CreateNodeDeployment(sshKeyProvider provider.SSHKeyProvider, projectProvide
 			return nil, fmt.Errorf("failed to create machine deployment from template: %v", err)
 		}
 
+		nodeSize, err := nodeSizeGetter(ctx, cluster, dc, provider.SecretKeySelectorValueFuncFactory(ctx, data.Client), &nd.Spec.Template)
+		if err != nil {
+			log.Logger.Debugw("failed to get the node size of the node deployment", "nodedeployment", md.Name, "error", err)
+		}
+		usage, err := common.CheckProjectQuota(ctx, project, common.NodesQuotaChange(int(nd.Spec.Replicas), nodeSize), seedsGetter, clusterProviderGetter, nodeSizeGetter)
+		if err != nil {
+			return nil, err
+		}
+
 		if err := client.Create(ctx, md); err != nil {
 			return nil, fmt.Errorf("failed to create machine deployment: %v", err)
 		}
+		common.UpdateProjectResourceUsage(privilegedProjectProvider, project, usage)
 
-		return outputMachineDeployment(md)
+		return OutputMachineDeployment(md)
 	}
 }
 
-func outputMachineDeployment(md *clusterv1alpha1.MachineDeployment) (*apiv1.NodeDeployment, error) {
+// OutputMachineDeployment converts the given machine deployment to its API representation
+func OutputMachineDeployment(md *clusterv1alpha1.MachineDeployment) (*apiv1.NodeDeployment, error) {
 	nodeStatus := apiv1.NodeStatus{}
 	nodeStatus.MachineName = md.Name
 
@@ -250,7 +264,7 @@ func ListNodeDeployments(projectProvider provider.ProjectProvider, privilegedPro
 			return nil, err
 		}
 
-		client, err := common.GetClusterClient(ctx, userInfoGetter, clusterProvider, cluster, req.ProjectID)
+		client, err := common.GetNodeDeploymentClusterClient(ctx, userInfoGetter, clusterProvider, cluster, req.ProjectID, "list")
 		if err != nil {
 			return nil, common.KubernetesErrorToHTTPError(err)
 		}
@@ -262,7 +276,7 @@ func ListNodeDeployments(projectProvider provider.ProjectProvider, privilegedPro
 
 		nodeDeployments := make([]*apiv1.NodeDeployment, 0, len(machineDeployments.Items))
 		for i := range machineDeployments.Items {
-			nd, err := outputMachineDeployment(&machineDeployments.Items[i])
+			nd, err := OutputMachineDeployment(&machineDeployments.Items[i])
 			if err != nil {
 				return nil, fmt.Errorf("failed to output machine deployment %s: %v", machineDeployments.Items[i].Name, err)
 			}
@@ -325,7 +339,7 @@ func GetNodeDeployment(projectProvider provider.ProjectProvider, privilegedProje
 			return nil, err
 		}
 
-		client, err := common.GetClusterClient(ctx, userInfoGetter, clusterProvider, cluster, req.ProjectID)
+		client, err := common.GetNodeDeploymentClusterClient(ctx, userInfoGetter, clusterProvider, cluster, req.ProjectID, "get")
 		if err != nil {
 			return nil, common.KubernetesErrorToHTTPError(err)
 		}
@@ -335,7 +349,7 @@ func GetNodeDeployment(projectProvider provider.ProjectProvider, privilegedProje
 			return nil, common.KubernetesErrorToHTTPError(err)
 		}
 
-		return outputMachineDeployment(machineDeployment)
+		return OutputMachineDeployment(machineDeployment)
 	}
 }
 
@@ -376,7 +390,7 @@ func DecodeListNodeDeploymentNodes(c context.Context, r *http.Request) (interfac
 
 func getMachinesForNodeDeployment(ctx context.Context, clusterProvider provider.ClusterProvider, userInfoGetter provider.UserInfoGetter, cluster *kubermaticv1.Cluster, projectID, nodeDeploymentID string) (*clusterv1alpha1.MachineList, error) {
 
-	client, err := common.GetClusterClient(ctx, userInfoGetter, clusterProvider, cluster, projectID)
+	client, err := common.GetNodeDeploymentClusterClient(ctx, userInfoGetter, clusterProvider, cluster, projectID, "get")
 	if err != nil {
 		return nil, err
 	}
@@ -394,7 +408,7 @@ func getMachinesForNodeDeployment(ctx context.Context, clusterProvider provider.
 }
 
 func getMachineSetsForNodeDeployment(ctx context.Context, clusterProvider provider.ClusterProvider, userInfoGetter provider.UserInfoGetter, cluster *kubermaticv1.Cluster, projectID, nodeDeploymentID string) (*clusterv1alpha1.MachineSetList, error) {
-	client, err := common.GetClusterClient(ctx, userInfoGetter, clusterProvider, cluster, projectID)
+	client, err := common.GetNodeDeploymentClusterClient(ctx, userInfoGetter, clusterProvider, cluster, projectID, "get")
 	if err != nil {
 		return nil, err
 	}
@@ -413,7 +427,7 @@ func getMachineSetsForNodeDeployment(ctx context.Context, clusterProvider provid
 }
 
 func getMachineDeploymentForNodeDeployment(ctx context.Context, clusterProvider provider.ClusterProvider, userInfoGetter provider.UserInfoGetter, cluster *kubermaticv1.Cluster, projectID, nodeDeploymentID string) (*clusterv1alpha1.MachineDeployment, error) {
-	client, err := common.GetClusterClient(ctx, userInfoGetter, clusterProvider, cluster, projectID)
+	client, err := common.GetNodeDeploymentClusterClient(ctx, userInfoGetter, clusterProvider, cluster, projectID, "get")
 	if err != nil {
 		return nil, err
 	}
@@ -629,7 +643,8 @@ func DecodePatchNodeDeployment(c context.Context, r *http.Request) (interface{},
 	return req, nil
 }
 
-func PatchNodeDeployment(sshKeyProvider provider.SSHKeyProvider, projectProvider provider.ProjectProvider, privilegedProjectProvider provider.PrivilegedProjectProvider, seedsGetter provider.SeedsGetter, userInfoGetter provider.UserInfoGetter) endpoint.Endpoint {
+func PatchNodeDeployment(sshKeyProvider provider.SSHKeyProvider, projectProvider provider.ProjectProvider, privilegedProjectProvider provider.PrivilegedProjectProvider, seedsGetter provider.SeedsGetter, userInfoGetter provider.UserInfoGetter,
+	clusterProviderGetter provider.ClusterProviderGetter, nodeSizeGetter common.NodeSizeGetter) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		req := request.(patchNodeDeploymentReq)
 		clusterProvider := ctx.Value(middleware.ClusterProviderContextKey).(provider.ClusterProvider)
@@ -648,7 +663,7 @@ func PatchNodeDeployment(sshKeyProvider provider.SSHKeyProvider, projectProvider
 			return nil, err
 		}
 
-		client, err := common.GetClusterClient(ctx, userInfoGetter, clusterProvider, cluster, req.ProjectID)
+		client, err := common.GetNodeDeploymentClusterClient(ctx, userInfoGetter, clusterProvider, cluster, req.ProjectID, "patch")
 		if err != nil {
 			return nil, common.KubernetesErrorToHTTPError(err)
 		}
@@ -660,11 +675,12 @@ func PatchNodeDeployment(sshKeyProvider provider.SSHKeyProvider, projectProvider
 			return nil, common.KubernetesErrorToHTTPError(err)
 		}
 
-		nodeDeployment, err := outputMachineDeployment(machineDeployment)
+		nodeDeployment, err := OutputMachineDeployment(machineDeployment)
 		if err != nil {
 			return nil, fmt.Errorf("cannot output existing node deployment: %v", err)
 		}
 
+		audit.RecordBefore(ctx, nodeDeployment)
 		nodeDeploymentJSON, err := json.Marshal(nodeDeployment)
 		if err != nil {
 			return nil, fmt.Errorf("cannot decode existing node deployment: %v", err)
@@ -712,6 +728,22 @@ func PatchNodeDeployment(sshKeyProvider provider.SSHKeyProvider, projectProvider
 			return nil, fmt.Errorf("failed to create machine deployment from template: %v", err)
 		}
 
+		// Only the difference to the current size of the node deployment counts against the quota
+		secretKeySelector := provider.SecretKeySelectorValueFuncFactory(ctx, data.Client)
+		nodeSize, err := nodeSizeGetter(ctx, cluster, dc, secretKeySelector, &patchedNodeDeployment.Spec.Template)
+		if err != nil {
+			log.Logger.Debugw("failed to get the node size of the node deployment", "nodedeployment", machineDeployment.Name, "error", err)
+		}
+		quotaChange := common.NodesQuotaChange(int(patchedNodeDeployment.Spec.Replicas), nodeSize)
+		currentUsage := common.MachineDeploymentUsage(ctx, cluster, dc, secretKeySelector, machineDeployment, nodeSizeGetter)
+		quotaChange.Nodes -= currentUsage.Nodes
+		quotaChange.CPUs -= currentUsage.CPUs
+		quotaChange.MemoryMB -= currentUsage.MemoryMB
+		usage, err := common.CheckProjectQuota(ctx, project, quotaChange, seedsGetter, clusterProviderGetter, nodeSizeGetter)
+		if err != nil {
+			return nil, err
+		}
+
 		// Only the fields from NodeDeploymentSpec will be updated by a patch.
 		// It ensures that the name and resource version are set and the selector stays the same.
 		machineDeployment.Spec.Template.Spec = patchedMachineDeployment.Spec.Template.Spec
@@ -721,8 +753,9 @@ func PatchNodeDeployment(sshKeyProvider provider.SSHKeyProvider, projectProvider
 		if err := client.Update(ctx, machineDeployment); err != nil {
 			return nil, fmt.Errorf("failed to update machine deployment: %v", err)
 		}
+		common.UpdateProjectResourceUsage(privilegedProjectProvider, project, usage)
 
-		return outputMachineDeployment(machineDeployment)
+		return OutputMachineDeployment(machineDeployment)
 	}
 }
 
@@ -759,21 +792,31 @@ func DecodeDeleteNodeDeployment(c context.Context, r *http.Request) (interface{}
 	return req, nil
 }
 
-func DeleteNodeDeployment(projectProvider provider.ProjectProvider, privilegedProjectProvider provider.PrivilegedProjectProvider, userInfoGetter provider.UserInfoGetter) endpoint.Endpoint {
+func DeleteNodeDeployment(projectProvider provider.ProjectProvider, privilegedProjectProvider provider.PrivilegedProjectProvider, userInfoGetter provider.UserInfoGetter,
+	seedsGetter provider.SeedsGetter, clusterProviderGetter provider.ClusterProviderGetter, nodeSizeGetter common.NodeSizeGetter) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		req := request.(deleteNodeDeploymentReq)
 		clusterProvider := ctx.Value(middleware.ClusterProviderContextKey).(provider.ClusterProvider)
+		project, err := common.GetProject(ctx, userInfoGetter, projectProvider, privilegedProjectProvider, req.ProjectID, nil)
+		if err != nil {
+			return nil, common.KubernetesErrorToHTTPError(err)
+		}
+
 		cluster, err := cluster.GetCluster(ctx, projectProvider, privilegedProjectProvider, userInfoGetter, req.ProjectID, req.ClusterID, nil)
 		if err != nil {
 			return nil, err
 		}
 
-		client, err := common.GetClusterClient(ctx, userInfoGetter, clusterProvider, cluster, req.ProjectID)
+		client, err := common.GetNodeDeploymentClusterClient(ctx, userInfoGetter, clusterProvider, cluster, req.ProjectID, "delete")
 		if err != nil {
 			return nil, common.KubernetesErrorToHTTPError(err)
 		}
 
-		return nil, common.KubernetesErrorToHTTPError(client.Delete(ctx, &clusterv1alpha1.MachineDeployment{ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceSystem, Name: req.NodeDeploymentID}}))
+		if err := client.Delete(ctx, &clusterv1alpha1.MachineDeployment{ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceSystem, Name: req.NodeDeploymentID}}); err != nil {
+			return nil, common.KubernetesErrorToHTTPError(err)
+		}
+		common.RefreshProjectResourceUsage(ctx, project, privilegedProjectProvider, seedsGetter, clusterProviderGetter, nodeSizeGetter)
+		return nil, nil
 	}
 }
 
@@ -1591,6 +1591,47 @@ func TestPatchNodeDeployment(t *testing.T) {
 			ExistingMachineDeployments: []*clusterv1alpha1.MachineDeployment{genTestMachineDeployment("venus", `{"cloudProvider":"digitalocean","cloudProviderSpec":{"token":"dummy-token","region":"fra1","size":"2GB"}, "operatingSystem":"ubuntu", "operatingSystemSpec":{"distUpgradeOnBoot":true}}`, nil, false)},
 			ExistingKubermaticObjs:     test.GenDefaultKubermaticObjects(genTestCluster(true), genUser("John", "john@acme.com", false)),
 		},
+		// Scenario 8: The cluster operator John can scale the node deployment.
+		{
+			Name:                       "Scenario 8: The cluster operator John can scale the node deployment",
+			Body:                       fmt.Sprintf(`{"spec":{"replicas":%v}}`, replicasUpdated),
+			ExpectedResponse:           fmt.Sprintf(`{"id":"venus","name":"venus","creationTimestamp":"0001-01-01T00:00:00Z","spec":{"replicas":%v,"template":{"cloud":{"digitalocean":{"size":"2GB","backups":false,"ipv6":false,"monitoring":false,"tags":["kubernetes","kubernetes-cluster-defClusterID","system-cluster-defClusterID","system-project-my-first-project-ID"]}},"operatingSystem":{"ubuntu":{"distUpgradeOnBoot":true}},"versions":{"kubelet":"v9.9.9"},"labels":{"system/cluster":"defClusterID","system/project":"my-first-project-ID"}},"paused":false,"dynamicConfig":false},"status":{}}`, replicasUpdated),
+			cluster:                    "keen-snyder",
+			HTTPStatus:                 http.StatusOK,
+			project:                    test.GenDefaultProject().Name,
+			ExistingAPIUser:            test.GenAPIUser("John", "john@acme.com"),
+			NodeDeploymentID:           "venus",
+			ExistingMachineDeployments: []*clusterv1alpha1.MachineDeployment{genTestMachineDeployment("venus", `{"cloudProvider":"digitalocean","cloudProviderSpec":{"token":"dummy-token","region":"fra1","size":"2GB"}, "operatingSystem":"ubuntu", "operatingSystemSpec":{"distUpgradeOnBoot":true}}`, nil, false)},
+			ExistingKubermaticObjs: test.GenDefaultKubermaticObjects(
+				genTestCluster(true),
+				genUser("John", "john@acme.com", false),
+				test.GenBinding(test.GenDefaultProject().Name, "john@acme.com", "cluster-operator"),
+				genClusterOperatorRole(),
+			),
+		},
+		// Scenario 9: The billing viewer John can not scale the node deployment.
+		{
+			Name:                       "Scenario 9: The billing viewer John can not scale the node deployment",
+			Body:                       fmt.Sprintf(`{"spec":{"replicas":%v}}`, replicasUpdated),
+			ExpectedResponse:           `{"error":{"code":403,"message":"the project role billing-viewer doesn't allow to patch node deployments"}}`,
+			cluster:                    "keen-snyder",
+			HTTPStatus:                 http.StatusForbidden,
+			project:                    test.GenDefaultProject().Name,
+			ExistingAPIUser:            test.GenAPIUser("John", "john@acme.com"),
+			NodeDeploymentID:           "venus",
+			ExistingMachineDeployments: []*clusterv1alpha1.MachineDeployment{genTestMachineDeployment("venus", `{"cloudProvider":"digitalocean","cloudProviderSpec":{"token":"dummy-token","region":"fra1","size":"2GB"}, "operatingSystem":"ubuntu", "operatingSystemSpec":{"distUpgradeOnBoot":true}}`, nil, false)},
+			ExistingKubermaticObjs: test.GenDefaultKubermaticObjects(
+				genTestCluster(true),
+				genUser("John", "john@acme.com", false),
+				test.GenBinding(test.GenDefaultProject().Name, "john@acme.com", "billing-viewer"),
+				&kubermaticv1.ProjectRole{
+					ObjectMeta: metav1.ObjectMeta{Name: "billing-viewer"},
+					Spec: kubermaticv1.ProjectRoleSpec{
+						Rules: []kubermaticv1.ProjectRoleRule{{Kinds: []string{kubermaticv1.ProjectKindName, kubermaticv1.ClusterKindName, "NodeDeployment"}, Verbs: []string{"get", "list"}}},
+					},
+				},
+			),
+		},
 	}
 
 	for _, tc := range testcases {
@@ -1706,6 +1747,32 @@ func TestDeleteNodeDeployment(t *testing.T) {
 			ExpectedResponseOnGet:       `{"error":{"code":403,"message":"forbidden: \"john@acme.com\" doesn't belong to the given project = my-first-project-ID"}}`,
 			EpxectedNodeDeploymentCount: 2,
 		},
+		// scenario 4
+		{
+			Name:            "scenario 4: the cluster operator John can not delete the node deployment",
+			HTTPStatus:      http.StatusForbidden,
+			NodeIDToDelete:  "venus",
+			ClusterIDToSync: test.GenDefaultCluster().Name,
+			ProjectIDToSync: test.GenDefaultProject().Name,
+			ExistingKubermaticObjs: test.GenDefaultKubermaticObjects(
+				test.GenDefaultCluster(),
+				genUser("John", "john@acme.com", false),
+				test.GenBinding(test.GenDefaultProject().Name, "john@acme.com", "cluster-operator"),
+				genClusterOperatorRole(),
+			),
+			ExistingAPIUser: test.GenAPIUser("John", "john@acme.com"),
+			ExistingNodes: []*corev1.Node{
+				{ObjectMeta: metav1.ObjectMeta{Name: "venus"}},
+				{ObjectMeta: metav1.ObjectMeta{Name: "mars"}},
+			},
+			ExistingMachineDeployments: []*clusterv1alpha1.MachineDeployment{
+				genTestMachineDeployment("venus", `{"cloudProvider":"digitalocean","cloudProviderSpec":{"token":"dummy-token","region":"fra1","size":"2GB"}, "operatingSystem":"ubuntu", "operatingSystemSpec":{"distUpgradeOnBoot":true}}`, nil, false),
+				genTestMachineDeployment("mars", `{"cloudProvider":"aws","cloudProviderSpec":{"token":"dummy-token","region":"eu-central-1","availabilityZone":"eu-central-1a","vpcId":"vpc-819f62e9","subnetId":"subnet-2bff4f43","instanceType":"t2.micro","diskSize":50}, "operatingSystem":"ubuntu", "operatingSystemSpec":{"distUpgradeOnBoot":false}}`, nil, false),
+			},
+			ExpectedHTTPStatusOnGet:     http.StatusOK,
+			ExpectedResponseOnGet:       `{"id":"venus","name":"venus","creationTimestamp":"0001-01-01T00:00:00Z","spec":{"cloud":{},"operatingSystem":{},"versions":{"kubelet":""}},"status":{"machineName":"","capacity":{"cpu":"0","memory":"0"},"allocatable":{"cpu":"0","memory":"0"},"nodeInfo":{"kernelVersion":"","containerRuntime":"","containerRuntimeVersion":"","kubeletVersion":"","operatingSystem":"","architecture":""}}}`,
+			EpxectedNodeDeploymentCount: 2,
+		},
 	}
 
 	for _, tc := range testcases {
@@ -1992,3 +2059,16 @@ func genUser(name, email string, isAdmin bool) *kubermaticv1.User {
 	user.Spec.IsAdmin = isAdmin
 	return user
 }
+
+// genClusterOperatorRole generates a custom project role that can scale node deployments but not delete clusters
+func genClusterOperatorRole() *kubermaticv1.ProjectRole {
+	return &kubermaticv1.ProjectRole{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-operator"},
+		Spec: kubermaticv1.ProjectRoleSpec{
+			Rules: []kubermaticv1.ProjectRoleRule{
+				{Kinds: []string{kubermaticv1.ProjectKindName, kubermaticv1.ClusterKindName}, Verbs: []string{"get", "list"}},
+				{Kinds: []string{"NodeDeployment"}, Verbs: []string{"get", "patch"}},
+			},
+		},
+	}
+}
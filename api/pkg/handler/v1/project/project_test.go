@@ -548,6 +548,10 @@ func (f *fakeUserClusterConnection) GetClient(_ *kubermaticapiv1.Cluster, _ ...k
 	return f.fakeDynamicClient, nil
 }
 
+func (f *fakeUserClusterConnection) GetClientConfig(_ *kubermaticapiv1.Cluster, _ ...k8cuserclusterclient.ConfigOption) (*restclient.Config, error) {
+	return &restclient.Config{}, nil
+}
+
 func TestGetProjectEndpoint(t *testing.T) {
 	t.Parallel()
 	testcases := []struct {
@@ -0,0 +1,210 @@
+/*
+Copyright 2020 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	apiv1 "github.com/kubermatic/kubermatic/api/pkg/api/v1"
+	kubermaticv1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
+	"github.com/kubermatic/kubermatic/api/pkg/handler/v1/common"
+	"github.com/kubermatic/kubermatic/api/pkg/provider"
+	"github.com/kubermatic/kubermatic/api/pkg/provider/cloud/alibaba"
+	"github.com/kubermatic/kubermatic/api/pkg/provider/cloud/azure"
+	doprovider "github.com/kubermatic/kubermatic/api/pkg/provider/cloud/digitalocean"
+	"github.com/kubermatic/kubermatic/api/pkg/provider/cloud/gcp"
+	"github.com/kubermatic/kubermatic/api/pkg/provider/cloud/hetzner"
+	"github.com/kubermatic/kubermatic/api/pkg/provider/cloud/openstack"
+	"github.com/kubermatic/kubermatic/api/pkg/provider/cloud/packet"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+const bytesPerMB = 1024 * 1024
+
+// GetNodeSize is a common.NodeSizeGetter, which looks up the vCPUs and memory of the node size in
+// the same way as the size endpoints of the cloud providers
+func GetNodeSize(ctx context.Context, cluster *kubermaticv1.Cluster, dc *kubermaticv1.Datacenter, secretKeySelector provider.SecretKeySelectorValueFunc, spec *apiv1.NodeSpec) (*common.NodeSize, error) {
+	cloud := cluster.Spec.Cloud
+
+	switch {
+	case spec.Cloud.AWS != nil:
+		return awsNodeSize(spec.Cloud.AWS.InstanceType)
+
+	case spec.Cloud.Azure != nil && cloud.Azure != nil && dc.Spec.Azure != nil:
+		creds, err := azure.GetCredentialsForCluster(cloud, secretKeySelector)
+		if err != nil {
+			return nil, err
+		}
+		sizes, err := azureSize(ctx, creds.SubscriptionID, creds.ClientID, creds.ClientSecret, creds.TenantID, dc.Spec.Azure.Location)
+		if err != nil {
+			return nil, err
+		}
+		for _, size := range sizes {
+			if size.Name == spec.Cloud.Azure.Size {
+				return &common.NodeSize{CPUs: int(size.NumberOfCores), MemoryMB: int(size.MemoryInMB)}, nil
+			}
+		}
+		return nil, unknownSizeError(spec.Cloud.Azure.Size)
+
+	case spec.Cloud.Digitalocean != nil && cloud.Digitalocean != nil:
+		token, err := doprovider.GetCredentialsForCluster(cloud, secretKeySelector)
+		if err != nil {
+			return nil, err
+		}
+		sizes, err := digitaloceanSize(ctx, token)
+		if err != nil {
+			return nil, err
+		}
+		for _, size := range append(sizes.Standard, sizes.Optimized...) {
+			if size.Slug == spec.Cloud.Digitalocean.Size {
+				return &common.NodeSize{CPUs: size.VCPUs, MemoryMB: size.Memory}, nil
+			}
+		}
+		return nil, unknownSizeError(spec.Cloud.Digitalocean.Size)
+
+	case spec.Cloud.GCP != nil && cloud.GCP != nil:
+		sa, err := gcp.GetCredentialsForCluster(cloud, secretKeySelector)
+		if err != nil {
+			return nil, err
+		}
+		sizes, err := listGCPSizes(ctx, sa, spec.Cloud.GCP.Zone)
+		if err != nil {
+			return nil, err
+		}
+		for _, size := range sizes {
+			if size.Name == spec.Cloud.GCP.MachineType {
+				return &common.NodeSize{CPUs: int(size.VCPUs), MemoryMB: int(size.Memory)}, nil
+			}
+		}
+		return nil, unknownSizeError(spec.Cloud.GCP.MachineType)
+
+	case spec.Cloud.Hetzner != nil && cloud.Hetzner != nil:
+		token, err := hetzner.GetCredentialsForCluster(cloud, secretKeySelector)
+		if err != nil {
+			return nil, err
+		}
+		sizes, err := hetznerSize(ctx, token)
+		if err != nil {
+			return nil, err
+		}
+		for _, size := range append(sizes.Standard, sizes.Dedicated...) {
+			if size.Name == spec.Cloud.Hetzner.Type {
+				return &common.NodeSize{CPUs: size.Cores, MemoryMB: int(size.Memory * 1024)}, nil
+			}
+		}
+		return nil, unknownSizeError(spec.Cloud.Hetzner.Type)
+
+	case spec.Cloud.Openstack != nil && cloud.Openstack != nil && dc.Spec.Openstack != nil:
+		creds, err := openstack.GetCredentialsForCluster(cloud, secretKeySelector)
+		if err != nil {
+			return nil, err
+		}
+		sizes, err := getOpenstackSizes(creds.Username, creds.Password, creds.Tenant, creds.TenantID, creds.Domain, cloud.DatacenterName, dc)
+		if err != nil {
+			return nil, err
+		}
+		for _, size := range sizes {
+			if size.Slug == spec.Cloud.Openstack.Flavor {
+				return &common.NodeSize{CPUs: size.VCPUs, MemoryMB: size.Memory}, nil
+			}
+		}
+		return nil, unknownSizeError(spec.Cloud.Openstack.Flavor)
+
+	case spec.Cloud.Packet != nil && cloud.Packet != nil:
+		apiKey, projectID, err := packet.GetCredentialsForCluster(cloud, secretKeySelector)
+		if err != nil {
+			return nil, err
+		}
+		sizes, err := sizes(ctx, apiKey, projectID)
+		if err != nil {
+			return nil, err
+		}
+		for _, size := range sizes {
+			if size.Name == spec.Cloud.Packet.InstanceType {
+				return packetNodeSize(size)
+			}
+		}
+		return nil, unknownSizeError(spec.Cloud.Packet.InstanceType)
+
+	case spec.Cloud.Alibaba != nil && cloud.Alibaba != nil && dc.Spec.Alibaba != nil:
+		accessKeyID, accessKeySecret, err := alibaba.GetCredentialsForCluster(cloud, secretKeySelector, dc.Spec.Alibaba)
+		if err != nil {
+			return nil, err
+		}
+		instanceTypes, err := listAlibabaInstanceTypes(ctx, accessKeyID, accessKeySecret, dc.Spec.Alibaba.Region)
+		if err != nil {
+			return nil, err
+		}
+		for _, instanceType := range instanceTypes {
+			if instanceType.ID == spec.Cloud.Alibaba.InstanceType {
+				return &common.NodeSize{CPUs: instanceType.CPUCoreCount, MemoryMB: int(instanceType.MemorySize * 1024)}, nil
+			}
+		}
+		return nil, unknownSizeError(spec.Cloud.Alibaba.InstanceType)
+
+	case spec.Cloud.VSphere != nil:
+		return &common.NodeSize{CPUs: spec.Cloud.VSphere.CPUs, MemoryMB: spec.Cloud.VSphere.Memory}, nil
+
+	case spec.Cloud.Kubevirt != nil:
+		cpus, err := resource.ParseQuantity(spec.Cloud.Kubevirt.CPUs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the CPUs of the node: %v", err)
+		}
+		memory, err := resource.ParseQuantity(spec.Cloud.Kubevirt.Memory)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the memory of the node: %v", err)
+		}
+		// fractions of a CPU are counted as a whole vCPU
+		return &common.NodeSize{CPUs: int(cpus.Value()), MemoryMB: int(memory.Value() / bytesPerMB)}, nil
+	}
+
+	return nil, nil
+}
+
+func awsNodeSize(instanceType string) (*common.NodeSize, error) {
+	if data == nil {
+		return nil, fmt.Errorf("AWS instance type data not initialized")
+	}
+	for _, i := range *data {
+		if i.InstanceType == instanceType {
+			return &common.NodeSize{CPUs: i.VCPU, MemoryMB: int(i.Memory * 1024)}, nil
+		}
+	}
+	return nil, unknownSizeError(instanceType)
+}
+
+func packetNodeSize(size apiv1.PacketSize) (*common.NodeSize, error) {
+	var cpus int
+	for _, cpu := range size.CPUs {
+		cpus += cpu.Count
+	}
+
+	// Packet reports the memory like "32GB", meaning binary units
+	memory, err := resource.ParseQuantity(strings.TrimSuffix(size.Memory, "B") + "i")
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the memory %q of the node: %v", size.Memory, err)
+	}
+
+	return &common.NodeSize{CPUs: cpus, MemoryMB: int(memory.Value() / bytesPerMB)}, nil
+}
+
+func unknownSizeError(name string) error {
+	return fmt.Errorf("unknown node size %q", name)
+}
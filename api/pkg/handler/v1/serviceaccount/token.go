@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"time"
 	"unicode/utf8"
 
 	"github.com/go-kit/kit/endpoint"
@@ -29,6 +30,7 @@ import (
 
 	apiv1 "github.com/kubermatic/kubermatic/api/pkg/api/v1"
 	kubermaticapiv1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
+	"github.com/kubermatic/kubermatic/api/pkg/handler/audit"
 	"github.com/kubermatic/kubermatic/api/pkg/handler/v1/common"
 	"github.com/kubermatic/kubermatic/api/pkg/provider"
 	"github.com/kubermatic/kubermatic/api/pkg/serviceaccount"
@@ -38,6 +40,7 @@ import (
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/apimachinery/pkg/util/sets"
 )
 
 // CreateTokenEndpoint creates a token for the given service account
@@ -69,7 +72,12 @@ func CreateTokenEndpoint(projectProvider provider.ProjectProvider, privilegedPro
 
 		tokenID := rand.String(10)
 
-		token, err := tokenGenerator.Generate(serviceaccount.Claims(sa.Spec.Email, project.Name, tokenID))
+		options := serviceaccount.TokenOptions{
+			Expiry:   req.Body.Expiry.Time,
+			Clusters: req.Body.Clusters,
+			ReadOnly: req.Body.ReadOnly,
+		}
+		token, err := tokenGenerator.Generate(serviceaccount.ScopedClaims(sa.Spec.Email, project.Name, tokenID, options))
 		if err != nil {
 			return nil, errors.New(http.StatusInternalServerError, "can not generate token data")
 		}
@@ -168,6 +176,11 @@ func ListTokenEndpoint(projectProvider provider.ProjectProvider, privilegedProje
 				errorList = append(errorList, err.Error())
 				continue
 			}
+			externalToken.Revoked, err = privilegedServiceAccountTokenProvider.IsRevokedUnsecured(secret.Name)
+			if err != nil {
+				errorList = append(errorList, err.Error())
+				continue
+			}
 			resultList = append(resultList, externalToken)
 		}
 
@@ -188,7 +201,7 @@ func UpdateTokenEndpoint(projectProvider provider.ProjectProvider, privilegedPro
 			return nil, errors.NewBadRequest(err.Error())
 		}
 
-		secret, err := updateEndpoint(ctx, projectProvider, privilegedProjectProvider, serviceAccountProvider, privilegedServiceAccount, serviceAccountTokenProvider, privilegedServiceAccountTokenProvider, userInfoGetter, tokenGenerator, req.ProjectID, req.ServiceAccountID, req.TokenID, req.Body.Name, true)
+		secret, err := updateEndpoint(ctx, projectProvider, privilegedProjectProvider, serviceAccountProvider, privilegedServiceAccount, serviceAccountTokenProvider, privilegedServiceAccountTokenProvider, userInfoGetter, tokenAuthenticator, tokenGenerator, req.ProjectID, req.ServiceAccountID, req.TokenID, req.Body.Name, true)
 		if err != nil {
 			return nil, common.KubernetesErrorToHTTPError(err)
 		}
@@ -219,7 +232,7 @@ func PatchTokenEndpoint(projectProvider provider.ProjectProvider, privilegedProj
 			return nil, errors.NewBadRequest("new name can not be empty")
 		}
 
-		secret, err := updateEndpoint(ctx, projectProvider, privilegedProjectProvider, serviceAccountProvider, privilegedServiceAccount, serviceAccountTokenProvider, privilegedServiceAccountTokenProvider, userInfoGetter, tokenGenerator, req.ProjectID, req.ServiceAccountID, req.TokenID, tokenReq.Name, false)
+		secret, err := updateEndpoint(ctx, projectProvider, privilegedProjectProvider, serviceAccountProvider, privilegedServiceAccount, serviceAccountTokenProvider, privilegedServiceAccountTokenProvider, userInfoGetter, tokenAuthenticator, tokenGenerator, req.ProjectID, req.ServiceAccountID, req.TokenID, tokenReq.Name, false)
 		if err != nil {
 			return nil, common.KubernetesErrorToHTTPError(err)
 		}
@@ -228,6 +241,10 @@ func PatchTokenEndpoint(projectProvider provider.ProjectProvider, privilegedProj
 		if err != nil {
 			return nil, errors.New(http.StatusInternalServerError, err.Error())
 		}
+		externalToken.Revoked, err = privilegedServiceAccountTokenProvider.IsRevokedUnsecured(secret.Name)
+		if err != nil {
+			return nil, common.KubernetesErrorToHTTPError(err)
+		}
 
 		return externalToken, nil
 	}
@@ -259,6 +276,51 @@ func DeleteTokenEndpoint(projectProvider provider.ProjectProvider, privilegedPro
 	}
 }
 
+// RevokeTokenEndpoint puts the token of the given service account on the revocation list
+func RevokeTokenEndpoint(projectProvider provider.ProjectProvider, privilegedProjectProvider provider.PrivilegedProjectProvider, serviceAccountProvider provider.ServiceAccountProvider, privilegedServiceAccount provider.PrivilegedServiceAccountProvider, serviceAccountTokenProvider provider.ServiceAccountTokenProvider, privilegedServiceAccountTokenProvider provider.PrivilegedServiceAccountTokenProvider, tokenAuthenticator serviceaccount.TokenAuthenticator, userInfoGetter provider.UserInfoGetter) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(deleteTokenReq)
+		err := req.Validate()
+		if err != nil {
+			return nil, errors.NewBadRequest(err.Error())
+		}
+
+		project, err := common.GetProject(ctx, userInfoGetter, projectProvider, privilegedProjectProvider, req.ProjectID, nil)
+		if err != nil {
+			return nil, common.KubernetesErrorToHTTPError(err)
+		}
+
+		_, err = getSA(ctx, serviceAccountProvider, privilegedServiceAccount, userInfoGetter, project, req.ServiceAccountID, &provider.ServiceAccountGetOptions{RemovePrefix: false})
+		if err != nil {
+			return nil, common.KubernetesErrorToHTTPError(err)
+		}
+
+		existingSecret, err := getSAToken(ctx, userInfoGetter, serviceAccountTokenProvider, privilegedServiceAccountTokenProvider, req.ProjectID, req.TokenID)
+		if err != nil {
+			return nil, common.KubernetesErrorToHTTPError(err)
+		}
+
+		// the revocation list is managed with the privileged account,
+		// updating the token on behalf of the user makes sure the user is allowed to manage it
+		secret, err := updateSAToken(ctx, userInfoGetter, serviceAccountTokenProvider, privilegedServiceAccountTokenProvider, existingSecret, req.ProjectID)
+		if err != nil {
+			return nil, common.KubernetesErrorToHTTPError(err)
+		}
+
+		externalToken, err := convertInternalTokenToPublicExternal(secret, tokenAuthenticator)
+		if err != nil {
+			return nil, errors.New(http.StatusInternalServerError, err.Error())
+		}
+
+		if err := privilegedServiceAccountTokenProvider.RevokeUnsecured(secret.Name, externalToken.Expiry.Time); err != nil {
+			return nil, common.KubernetesErrorToHTTPError(err)
+		}
+		externalToken.Revoked = true
+
+		return externalToken, nil
+	}
+}
+
 func deleteSAToken(ctx context.Context, userInfoGetter provider.UserInfoGetter, serviceAccountTokenProvider provider.ServiceAccountTokenProvider, privilegedServiceAccountTokenProvider provider.PrivilegedServiceAccountTokenProvider, projectID, tokenID string) error {
 	adminUserInfo, err := userInfoGetter(ctx, "")
 	if err != nil {
@@ -292,7 +354,7 @@ func getSAToken(ctx context.Context, userInfoGetter provider.UserInfoGetter, ser
 }
 
 func updateEndpoint(ctx context.Context, projectProvider provider.ProjectProvider, privilegedProjectProvider provider.PrivilegedProjectProvider, serviceAccountProvider provider.ServiceAccountProvider,
-	privilegedServiceAccount provider.PrivilegedServiceAccountProvider, serviceAccountTokenProvider provider.ServiceAccountTokenProvider, privilegedServiceAccountTokenProvider provider.PrivilegedServiceAccountTokenProvider, userInfoGetter provider.UserInfoGetter, tokenGenerator serviceaccount.TokenGenerator,
+	privilegedServiceAccount provider.PrivilegedServiceAccountProvider, serviceAccountTokenProvider provider.ServiceAccountTokenProvider, privilegedServiceAccountTokenProvider provider.PrivilegedServiceAccountTokenProvider, userInfoGetter provider.UserInfoGetter, tokenAuthenticator serviceaccount.TokenAuthenticator, tokenGenerator serviceaccount.TokenGenerator,
 	projectID, saID, tokenID, newName string, regenerateToken bool) (*v1.Secret, error) {
 
 	project, err := common.GetProject(ctx, userInfoGetter, projectProvider, privilegedProjectProvider, projectID, nil)
@@ -313,6 +375,9 @@ func updateEndpoint(ctx context.Context, projectProvider provider.ProjectProvide
 	if !ok {
 		return nil, fmt.Errorf("can not find token name in secret %s", existingSecret.Name)
 	}
+	if existingToken, err := convertInternalTokenToPublicExternal(existingSecret, tokenAuthenticator); err == nil {
+		audit.RecordBefore(ctx, existingToken)
+	}
 
 	if newName == existingName && !regenerateToken {
 		return existingSecret, nil
@@ -331,7 +396,20 @@ func updateEndpoint(ctx context.Context, projectProvider provider.ProjectProvide
 	}
 
 	if regenerateToken {
-		token, err := tokenGenerator.Generate(serviceaccount.Claims(sa.Spec.Email, project.Name, existingSecret.Name))
+		revoked, err := privilegedServiceAccountTokenProvider.IsRevokedUnsecured(existingSecret.Name)
+		if err != nil {
+			return nil, err
+		}
+		if revoked {
+			return nil, errors.NewBadRequest("the token %s has been revoked and cannot be regenerated", existingSecret.Name)
+		}
+
+		// the new token keeps the scope and the lifetime of the existing one
+		options, err := tokenOptionsFromSecret(existingSecret, tokenAuthenticator)
+		if err != nil {
+			return nil, err
+		}
+		token, err := tokenGenerator.Generate(serviceaccount.ScopedClaims(sa.Spec.Email, project.Name, existingSecret.Name, options))
 		if err != nil {
 			return nil, fmt.Errorf("can not generate token data")
 		}
@@ -347,6 +425,28 @@ func updateEndpoint(ctx context.Context, projectProvider provider.ProjectProvide
 	return secret, nil
 }
 
+func tokenOptionsFromSecret(secret *v1.Secret, authenticator serviceaccount.TokenAuthenticator) (serviceaccount.TokenOptions, error) {
+	options := serviceaccount.TokenOptions{}
+	token, ok := secret.Data["token"]
+	if !ok {
+		return options, fmt.Errorf("can not find token data in secret %s", secret.Name)
+	}
+	publicClaim, customClaim, err := authenticator.Parse(string(token))
+	if err != nil {
+		return options, err
+	}
+
+	options.Clusters = customClaim.Clusters
+	options.ReadOnly = customClaim.ReadOnly
+	if publicClaim.IssuedAt != 0 && publicClaim.Expiry != 0 {
+		options.Expiry = serviceaccount.Now().Add(publicClaim.Expiry.Time().Sub(publicClaim.IssuedAt.Time()))
+		if options.Expiry.After(serviceaccount.MaxExpiry()) {
+			options.Expiry = serviceaccount.MaxExpiry()
+		}
+	}
+	return options, nil
+}
+
 func updateSAToken(ctx context.Context, userInfoGetter provider.UserInfoGetter, serviceAccountTokenProvider provider.ServiceAccountTokenProvider, privilegedServiceAccountTokenProvider provider.PrivilegedServiceAccountTokenProvider, token *v1.Secret, projectID string) (*v1.Secret, error) {
 	adminUserInfo, err := userInfoGetter(ctx, "")
 	if err != nil {
@@ -402,8 +502,8 @@ type patchTokenReq struct {
 	Body []byte
 }
 
-// deleteTokenReq defines HTTP request for deleteServiceAccountToken
-// swagger:parameters deleteServiceAccountToken
+// deleteTokenReq defines HTTP request for deleteServiceAccountToken and revokeServiceAccountToken
+// swagger:parameters deleteServiceAccountToken revokeServiceAccountToken
 type deleteTokenReq struct {
 	commonTokenReq
 	tokenIDReq
@@ -417,6 +517,24 @@ func (r addTokenReq) Validate() error {
 	if utf8.RuneCountInString(r.Body.Name) > 50 {
 		return fmt.Errorf("the name is too long, max 50 chars")
 	}
+	if !r.Body.Expiry.IsZero() {
+		if !r.Body.Expiry.After(serviceaccount.Now()) {
+			return fmt.Errorf("the expiry must be in the future")
+		}
+		if r.Body.Expiry.After(serviceaccount.MaxExpiry()) {
+			return fmt.Errorf("the expiry cannot be later than %s", serviceaccount.MaxExpiry().UTC().Format(time.RFC3339))
+		}
+	}
+	clusters := sets.NewString()
+	for _, cluster := range r.Body.Clusters {
+		if len(cluster) == 0 {
+			return fmt.Errorf("the cluster ID cannot be empty")
+		}
+		if clusters.Has(cluster) {
+			return fmt.Errorf("the cluster %s is listed more than once", cluster)
+		}
+		clusters.Insert(cluster)
+	}
 
 	return nil
 }
@@ -623,12 +741,15 @@ func convertInternalTokenToPublicExternal(internal *v1.Secret, authenticator ser
 		return nil, fmt.Errorf("can not find token data")
 	}
 
-	publicClaim, _, err := authenticator.Authenticate(string(token))
+	// expired tokens are still listed so the claims are parsed without checking the expiry
+	publicClaim, customClaim, err := authenticator.Parse(string(token))
 	if err != nil {
 		return nil, fmt.Errorf("unable to create a token for %s due to %v", internal.Name, err)
 	}
 
 	externalToken.Expiry = apiv1.NewTime(publicClaim.Expiry.Time())
+	externalToken.Clusters = customClaim.Clusters
+	externalToken.ReadOnly = customClaim.ReadOnly
 	externalToken.ID = internal.Name
 	name, ok := internal.Labels["name"]
 	if !ok {
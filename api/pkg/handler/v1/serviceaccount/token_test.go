@@ -22,13 +22,16 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	apiv1 "github.com/kubermatic/kubermatic/api/pkg/api/v1"
 	kubermaticapiv1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
 	"github.com/kubermatic/kubermatic/api/pkg/handler/test"
 	"github.com/kubermatic/kubermatic/api/pkg/handler/test/hack"
+	"github.com/kubermatic/kubermatic/api/pkg/serviceaccount"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -50,6 +53,8 @@ func TestCreateTokenProject(t *testing.T) {
 		saToSync               string
 		httpStatus             int
 		existingAPIUser        apiv1.User
+		expectedClusters       []string
+		expectedReadOnly       bool
 	}{
 		{
 			name:       "scenario 1: create service account token with name 'test' for serviceaccount-1",
@@ -123,6 +128,48 @@ func TestCreateTokenProject(t *testing.T) {
 			saToSync:               "1",
 			expectedName:           "test",
 		},
+		{
+			name:       "scenario 4: create read-only service account token restricted to two clusters",
+			body:       `{"name":"test","clusters":["abcd","efgh"],"readOnly":true}`,
+			httpStatus: http.StatusCreated,
+			existingKubermaticObjs: []runtime.Object{
+				/*add projects*/
+				test.GenProject("plan9", kubermaticapiv1.ProjectActive, test.DefaultCreationTimestamp()),
+				/*add bindings*/
+				test.GenBinding("plan9-ID", "john@acme.com", "owners"),
+				test.GenBinding("plan9-ID", "serviceaccount-1@sa.kubermatic.io", "editors"),
+				/*add users*/
+				test.GenUser("", "john", "john@acme.com"),
+				test.GenServiceAccount("1", "test-1", "editors", "plan9-ID"),
+			},
+			existingKubernetesObjs: []runtime.Object{},
+			existingAPIUser:        *test.GenAPIUser("john", "john@acme.com"),
+			projectToSync:          "plan9-ID",
+			saToSync:               "1",
+			expectedName:           "test",
+			expectedClusters:       []string{"abcd", "efgh"},
+			expectedReadOnly:       true,
+		},
+		{
+			name:       "scenario 5: the expiry of a service account token must be in the future",
+			body:       `{"name":"test","expiry":"2013-02-03T19:54:00Z"}`,
+			httpStatus: http.StatusBadRequest,
+			existingKubermaticObjs: []runtime.Object{
+				/*add projects*/
+				test.GenProject("plan9", kubermaticapiv1.ProjectActive, test.DefaultCreationTimestamp()),
+				/*add bindings*/
+				test.GenBinding("plan9-ID", "john@acme.com", "owners"),
+				test.GenBinding("plan9-ID", "serviceaccount-1@sa.kubermatic.io", "editors"),
+				/*add users*/
+				test.GenUser("", "john", "john@acme.com"),
+				test.GenServiceAccount("1", "test-1", "editors", "plan9-ID"),
+			},
+			existingKubernetesObjs: []runtime.Object{},
+			existingAPIUser:        *test.GenAPIUser("john", "john@acme.com"),
+			projectToSync:          "plan9-ID",
+			saToSync:               "1",
+			expectedErrorResponse:  `{"error":{"code":400,"message":"the expiry must be in the future"}}`,
+		},
 	}
 
 	for _, tc := range testcases {
@@ -167,6 +214,12 @@ func TestCreateTokenProject(t *testing.T) {
 				if saTokenClaim.Email != fmt.Sprintf("serviceaccount-%s@sa.kubermatic.io", tc.saToSync) {
 					t.Fatalf("expected email %s@sa.kubermatic.io got %s", tc.saToSync, saTokenClaim.Email)
 				}
+				if !reflect.DeepEqual(saTokenClaim.Clusters, tc.expectedClusters) || !reflect.DeepEqual(saToken.Clusters, tc.expectedClusters) {
+					t.Fatalf("expected clusters %v got %v in the claims and %v in the response", tc.expectedClusters, saTokenClaim.Clusters, saToken.Clusters)
+				}
+				if saTokenClaim.ReadOnly != tc.expectedReadOnly || saToken.ReadOnly != tc.expectedReadOnly {
+					t.Fatalf("expected read-only %v got %v in the claims and %v in the response", tc.expectedReadOnly, saTokenClaim.ReadOnly, saToken.ReadOnly)
+				}
 			}
 		})
 	}
@@ -353,6 +406,125 @@ func TestServiceAccountCanGetProject(t *testing.T) {
 	}
 }
 
+func TestServiceAccountTokenScope(t *testing.T) {
+	t.Parallel()
+	testcases := []struct {
+		name             string
+		tokenOptions     serviceaccount.TokenOptions
+		revoked          bool
+		method           string
+		path             string
+		expectedResponse string
+		httpStatus       int
+	}{
+		{
+			name:             "scenario 1: a read-only token can be used to get a project",
+			tokenOptions:     serviceaccount.TokenOptions{ReadOnly: true},
+			method:           http.MethodGet,
+			path:             "/api/v1/projects/plan9-ID",
+			httpStatus:       http.StatusOK,
+			expectedResponse: `{"id":"plan9-ID","name":"plan9","creationTimestamp":"2013-02-03T19:54:00Z","status":"Active","owners":[{"name":"john","creationTimestamp":"0001-01-01T00:00:00Z","email":"john@acme.com"}]}`,
+		},
+		{
+			name:             "scenario 2: a read-only token cannot be used to delete a cluster",
+			tokenOptions:     serviceaccount.TokenOptions{ReadOnly: true},
+			method:           http.MethodDelete,
+			path:             "/api/v1/projects/plan9-ID/dc/us-central1/clusters/abcd",
+			httpStatus:       http.StatusForbidden,
+			expectedResponse: `{"error":{"code":403,"message":"forbidden: the token is restricted to read-only requests"}}`,
+		},
+		{
+			name:             "scenario 3: a token restricted to clusters cannot be used for requests that don't address a cluster",
+			tokenOptions:     serviceaccount.TokenOptions{Clusters: []string{"abcd"}},
+			method:           http.MethodGet,
+			path:             "/api/v1/projects/plan9-ID",
+			httpStatus:       http.StatusForbidden,
+			expectedResponse: `{"error":{"code":403,"message":"forbidden: the token is restricted to the clusters [abcd]"}}`,
+		},
+		{
+			name:             "scenario 4: a token restricted to clusters cannot be used to access other clusters",
+			tokenOptions:     serviceaccount.TokenOptions{Clusters: []string{"abcd"}},
+			method:           http.MethodGet,
+			path:             "/api/v1/projects/plan9-ID/dc/us-central1/clusters/efgh",
+			httpStatus:       http.StatusForbidden,
+			expectedResponse: `{"error":{"code":403,"message":"forbidden: the token is not allowed to access the cluster \"efgh\""}}`,
+		},
+		{
+			name:             "scenario 5: a read-only token cannot be used to get the kubeconfig of a cluster",
+			tokenOptions:     serviceaccount.TokenOptions{ReadOnly: true},
+			method:           http.MethodGet,
+			path:             "/api/v1/projects/plan9-ID/dc/us-central1/clusters/abcd/kubeconfig",
+			httpStatus:       http.StatusForbidden,
+			expectedResponse: `{"error":{"code":403,"message":"forbidden: the token is restricted to read-only requests, which must not return credentials"}}`,
+		},
+		{
+			name:             "scenario 6: a read-only token cannot be used to get the OIDC kubeconfig of a cluster",
+			tokenOptions:     serviceaccount.TokenOptions{ReadOnly: true},
+			method:           http.MethodGet,
+			path:             "/api/v1/projects/plan9-ID/dc/us-central1/clusters/abcd/oidckubeconfig",
+			httpStatus:       http.StatusForbidden,
+			expectedResponse: `{"error":{"code":403,"message":"forbidden: the token is restricted to read-only requests, which must not return credentials"}}`,
+		},
+		{
+			name:       "scenario 7: a revoked token cannot be used",
+			revoked:    true,
+			method:     http.MethodGet,
+			path:       "/api/v1/projects/plan9-ID",
+			httpStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			tokenGenerator, err := serviceaccount.JWTTokenGenerator([]byte(test.TestServiceAccountHashKey))
+			if err != nil {
+				t.Fatal(err)
+			}
+			token, err := tokenGenerator.Generate(serviceaccount.ScopedClaims("serviceaccount-1@sa.kubermatic.io", "plan9-ID", "1", tc.tokenOptions))
+			if err != nil {
+				t.Fatal(err)
+			}
+			tokenSecret := test.GenDefaultSaToken("plan9-ID", "serviceaccount-1", "ci", "1")
+			tokenSecret.Data["token"] = []byte(token)
+
+			existingKubernetesObjs := []runtime.Object{tokenSecret}
+			if tc.revoked {
+				existingKubernetesObjs = append(existingKubernetesObjs, &corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Name: "sa-token-revocations", Namespace: "kubermatic"},
+					Data:       map[string]string{"1": serviceaccount.MaxExpiry().UTC().Format(time.RFC3339)},
+				})
+			}
+			existingKubermaticObjs := []runtime.Object{
+				/*add projects*/
+				test.GenProject("plan9", kubermaticapiv1.ProjectActive, test.DefaultCreationTimestamp()),
+				/*add bindings*/
+				test.GenBinding("plan9-ID", "john@acme.com", "owners"),
+				test.GenBinding("plan9-ID", "serviceaccount-1@sa.kubermatic.io", "editors"),
+				/*add users*/
+				test.GenUser("", "john", "john@acme.com"),
+				test.GenServiceAccount("1", "test-1", "editors", "plan9-ID"),
+			}
+			ep, _, err := test.CreateTestEndpointAndGetClients(*test.GenAPIUser("test-1", "serviceaccount-1@sa.kubermatic.io"), nil, existingKubernetesObjs, []runtime.Object{}, existingKubermaticObjs, nil, nil, hack.NewTestRouting)
+			if err != nil {
+				t.Fatalf("failed to create test endpoint due to %v", err)
+			}
+
+			req := httptest.NewRequest(tc.method, tc.path, strings.NewReader(""))
+			req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
+			res := httptest.NewRecorder()
+			ep.ServeHTTP(res, req)
+
+			// validate
+			if res.Code != tc.httpStatus {
+				t.Fatalf("expected HTTP status code %d, got %d: %s", tc.httpStatus, res.Code, res.Body.String())
+			}
+			if len(tc.expectedResponse) > 0 {
+				test.CompareWithResult(t, res, tc.expectedResponse)
+			}
+		})
+	}
+}
+
 func TestPatchToken(t *testing.T) {
 	t.Parallel()
 	expiry, err := test.GenDefaultExpiry()
@@ -793,6 +965,96 @@ func TestDeleteToken(t *testing.T) {
 	}
 }
 
+func TestRevokeToken(t *testing.T) {
+	t.Parallel()
+	testcases := []struct {
+		name                   string
+		existingKubermaticObjs []runtime.Object
+		existingKubernetesObjs []runtime.Object
+		tokenToRevoke          string
+		httpStatus             int
+		existingAPIUser        apiv1.User
+		expectedResponse       string
+	}{
+		{
+			name:       "scenario 1: revoke token",
+			httpStatus: http.StatusOK,
+			existingKubermaticObjs: []runtime.Object{
+				/*add projects*/
+				test.GenProject("plan9", kubermaticapiv1.ProjectActive, test.DefaultCreationTimestamp()),
+				/*add bindings*/
+				test.GenBinding("plan9-ID", "john@acme.com", "owners"),
+				test.GenBinding("plan9-ID", "serviceaccount-1@sa.kubermatic.io", "editors"),
+				/*add users*/
+				test.GenUser("", "john", "john@acme.com"),
+				test.GenServiceAccount("1", "test-1", "editors", "plan9-ID"),
+			},
+			existingKubernetesObjs: []runtime.Object{
+				test.GenDefaultSaToken("plan9-ID", "serviceaccount-1", "test-1", "1"),
+				test.GenDefaultSaToken("plan9-ID", "serviceaccount-1", "test-3", "3"),
+			},
+			existingAPIUser:  *test.GenAPIUser("john", "john@acme.com"),
+			tokenToRevoke:    "sa-token-3",
+			expectedResponse: `{"id":"3","name":"test-3","creationTimestamp":"0001-01-01T00:00:00Z","expiry":"2022-04-12T07:34:16Z","revoked":true}`,
+		},
+		{
+			name:       "scenario 2: the user that doesn't belong to the project cannot revoke its tokens",
+			httpStatus: http.StatusForbidden,
+			existingKubermaticObjs: []runtime.Object{
+				/*add projects*/
+				test.GenProject("plan9", kubermaticapiv1.ProjectActive, test.DefaultCreationTimestamp()),
+				/*add bindings*/
+				test.GenBinding("plan9-ID", "john@acme.com", "owners"),
+				test.GenBinding("plan9-ID", "serviceaccount-1@sa.kubermatic.io", "editors"),
+				/*add users*/
+				test.GenUser("", "john", "john@acme.com"),
+				test.GenUser("", "bob", "bob@acme.com"),
+				test.GenServiceAccount("1", "test-1", "editors", "plan9-ID"),
+			},
+			existingKubernetesObjs: []runtime.Object{
+				test.GenDefaultSaToken("plan9-ID", "serviceaccount-1", "test-3", "3"),
+			},
+			existingAPIUser:  *test.GenAPIUser("bob", "bob@acme.com"),
+			tokenToRevoke:    "sa-token-3",
+			expectedResponse: `{"error":{"code":403,"message":"forbidden: \"bob@acme.com\" doesn't belong to the given project = plan9-ID"}}`,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", fmt.Sprintf("/api/v1/projects/plan9-ID/serviceaccounts/1/tokens/%s/revoke", tc.tokenToRevoke), strings.NewReader(""))
+			res := httptest.NewRecorder()
+
+			ep, clientset, err := test.CreateTestEndpointAndGetClients(tc.existingAPIUser, nil, tc.existingKubernetesObjs, []runtime.Object{}, tc.existingKubermaticObjs, nil, nil, hack.NewTestRouting)
+			if err != nil {
+				t.Fatalf("failed to create test endpoint due to %v", err)
+			}
+
+			ep.ServeHTTP(res, req)
+
+			if res.Code != tc.httpStatus {
+				t.Fatalf("expected HTTP status code %d, got %d: %s", tc.httpStatus, res.Code, res.Body.String())
+			}
+			test.CompareWithResult(t, res, tc.expectedResponse)
+
+			revocationList := &corev1.ConfigMap{}
+			err = clientset.FakeClient.Get(context.Background(), ctrlruntimeclient.ObjectKey{Name: "sa-token-revocations", Namespace: "kubermatic"}, revocationList)
+			if tc.httpStatus != http.StatusOK {
+				if err == nil {
+					t.Fatalf("expected the token %s not to be revoked", tc.tokenToRevoke)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to get the revocation list %v", err)
+			}
+			if _, ok := revocationList.Data[strings.TrimPrefix(tc.tokenToRevoke, "sa-token-")]; !ok {
+				t.Fatalf("expected the token %s to be on the revocation list, got %v", tc.tokenToRevoke, revocationList.Data)
+			}
+		})
+	}
+}
+
 func genPublicServiceAccountToken(id, name string, expiry apiv1.Time) apiv1.PublicServiceAccountToken {
 	token := apiv1.PublicServiceAccountToken{}
 	token.ID = id
@@ -32,6 +32,7 @@ import (
 	apiv1 "github.com/kubermatic/kubermatic/api/pkg/api/v1"
 	"github.com/kubermatic/kubermatic/api/pkg/controller/master-controller-manager/rbac"
 	kubermaticapiv1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
+	"github.com/kubermatic/kubermatic/api/pkg/handler/audit"
 	"github.com/kubermatic/kubermatic/api/pkg/handler/middleware"
 	"github.com/kubermatic/kubermatic/api/pkg/handler/v1/common"
 	"github.com/kubermatic/kubermatic/api/pkg/provider"
@@ -170,6 +171,7 @@ func EditEndpoint(projectProvider provider.ProjectProvider, privilegedProjectPro
 		}
 
 		currentMemberBinding := memberList[0]
+		audit.RecordBefore(ctx, filterExternalUser(convertInternalUserToExternal(memberToUpdate, false, currentMemberBinding), project.Name))
 		generatedGroupName := rbac.GenerateActualGroupNameFor(project.Name, projectFromRequest.GroupPrefix)
 		currentMemberBinding.Spec.Group = generatedGroupName
 		updatedMemberBinding, err := updateBinding(ctx, userInfoGetter, memberProvider, privilegedMemberProvider, req.ProjectID, currentMemberBinding)
@@ -298,10 +300,18 @@ func createBinding(ctx context.Context, userInfoGetter provider.UserInfoGetter,
 }
 
 // GetEndpoint returns info about the current user
-func GetEndpoint(memberMapper provider.ProjectMemberMapper) endpoint.Endpoint {
+//
+// The current user is fetched by the dashboard right after logging in, thus the project memberships
+// derived from the groups of the identity provider are synchronized here
+func GetEndpoint(memberMapper provider.ProjectMemberMapper, groupProjectBindingProvider provider.GroupProjectBindingProvider) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		authenticatedUser := ctx.Value(middleware.UserCRContextKey).(*kubermaticapiv1.User)
 
+		groups, _ := ctx.Value(middleware.AuthenticatedUserGroupsContextKey).([]string)
+		if err := groupProjectBindingProvider.SyncUnsecured(authenticatedUser.Spec.Email, groups); err != nil {
+			return nil, common.KubernetesErrorToHTTPError(err)
+		}
+
 		bindings, err := memberMapper.MappingsFor(authenticatedUser.Spec.Email)
 		if err != nil {
 			return nil, common.KubernetesErrorToHTTPError(err)
@@ -330,6 +340,7 @@ func PatchSettingsEndpoint(userProvider provider.UserProvider) endpoint.Endpoint
 			existingSettings = &kubermaticapiv1.UserSettings{}
 		}
 
+		audit.RecordBefore(ctx, existingSettings)
 		existingSettingsJSON, err := json.Marshal(existingSettings)
 		if err != nil {
 			return nil, errors.NewBadRequest(fmt.Sprintf("cannot decode existing user settings: %v", err))
@@ -380,7 +391,7 @@ func convertInternalUserToExternal(internalUser *kubermaticapiv1.User, includeSe
 			}
 		}
 		if !bindingAlreadyExists {
-			groupPrefix := rbac.ExtractGroupPrefix(binding.Spec.Group)
+			groupPrefix := rbac.ExtractGroupPrefixForProject(binding.Spec.ProjectID, binding.Spec.Group)
 			apiUser.Projects = append(apiUser.Projects, apiv1.ProjectGroup{ID: binding.Spec.ProjectID, GroupPrefix: groupPrefix})
 		}
 	}
@@ -440,7 +451,8 @@ func (r AddReq) Validate(authenticatesUserInfo *provider.UserInfo) error {
 			break
 		}
 	}
-	if !isRequestedGroupPrefixValid {
+	// custom project roles are checked by the member provider
+	if !isRequestedGroupPrefixValid && rbac.ValidateProjectRoleName(projectFromRequest.GroupPrefix) != nil {
 		return k8cerrors.NewBadRequest("invalid group name %s", projectFromRequest.GroupPrefix)
 	}
 	return nil
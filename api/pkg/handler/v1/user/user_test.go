@@ -798,6 +798,42 @@ func TestAddUserToProject(t *testing.T) {
 			ExistingAPIUser:  *genAPIUser("admin", "admin@acme.com"),
 			ExpectedResponse: `{"id":"405ac8384fa984f787f9486daf34d84d98f20c4d6a12e2cc4ed89be3bcb06ad6","name":"Bob","creationTimestamp":"0001-01-01T00:00:00Z","email":"bob@acme.com","projects":[{"id":"plan9-ID","group":"editors"}]}`,
 		},
+		{
+			Name:          "scenario 10: john the owner of the plan9 project invites bob to the project as a cluster operator",
+			Body:          `{"email":"bob@acme.com", "projects":[{"id":"plan9-ID", "group":"clusteroperator"}]}`,
+			HTTPStatus:    http.StatusCreated,
+			ProjectToSync: "plan9-ID",
+			ExistingKubermaticObjs: []runtime.Object{
+				/*add projects*/
+				test.GenProject("plan9", kubermaticapiv1.ProjectActive, test.DefaultCreationTimestamp()),
+				/*add bindings*/
+				test.GenBinding("plan9-ID", "john@acme.com", "owners"),
+				/*add users*/
+				genUser("", "john", "john@acme.com"),
+				genDefaultUser(), /*bob*/
+				/*add project roles*/
+				&kubermaticapiv1.ProjectRole{ObjectMeta: metav1.ObjectMeta{Name: "clusteroperator"}},
+			},
+			ExistingAPIUser:  *genAPIUser("john", "john@acme.com"),
+			ExpectedResponse: `{"id":"405ac8384fa984f787f9486daf34d84d98f20c4d6a12e2cc4ed89be3bcb06ad6","name":"Bob","creationTimestamp":"0001-01-01T00:00:00Z","email":"bob@acme.com","projects":[{"id":"plan9-ID","group":"clusteroperator"}]}`,
+		},
+		{
+			Name:          "scenario 11: john the owner of the plan9 project cannot invite bob to a project role that doesn't exist",
+			Body:          `{"email":"bob@acme.com", "projects":[{"id":"plan9-ID", "group":"billingviewer"}]}`,
+			HTTPStatus:    http.StatusBadRequest,
+			ProjectToSync: "plan9-ID",
+			ExistingKubermaticObjs: []runtime.Object{
+				/*add projects*/
+				test.GenProject("plan9", kubermaticapiv1.ProjectActive, test.DefaultCreationTimestamp()),
+				/*add bindings*/
+				test.GenBinding("plan9-ID", "john@acme.com", "owners"),
+				/*add users*/
+				genUser("", "john", "john@acme.com"),
+				genDefaultUser(), /*bob*/
+			},
+			ExistingAPIUser:  *genAPIUser("john", "john@acme.com"),
+			ExpectedResponse: `{"error":{"code":400,"message":"invalid group name billingviewer"}}`,
+		},
 	}
 
 	for _, tc := range testcases {
@@ -855,6 +891,32 @@ func TestGetCurrentUser(t *testing.T) {
 			ExpectedStatus:   http.StatusOK,
 			ExpectedResponse: `{"id":"4b2d8785b49bad23638b17d8db76857a79bf79441241a78a97d88cc64bbf766e","name":"john","creationTimestamp":"0001-01-01T00:00:00Z","email":"john@acme.com","projects":[{"id":"plan9-ID","group":"owners"}]}`,
 		},
+
+		{
+			Name: "scenario 3: get john's profile (memberships of the groups john no longer belongs to are removed)",
+			ExistingKubermaticObjs: []runtime.Object{
+				/*add projects*/
+				test.GenProject("moby", kubermaticapiv1.ProjectActive, test.DefaultCreationTimestamp()),
+				test.GenProject("plan9", kubermaticapiv1.ProjectActive, test.DefaultCreationTimestamp()),
+				/*add group bindings*/
+				&kubermaticapiv1.GroupProjectBinding{
+					ObjectMeta: metav1.ObjectMeta{Name: "moby-editors"},
+					Spec:       kubermaticapiv1.GroupProjectBindingSpec{Group: "team-moby", ProjectID: "moby-ID", Role: "editors"},
+				},
+				/*add bindings*/
+				test.GenBinding("plan9-ID", "john@acme.com", "owners"),
+				func() *kubermaticapiv1.UserProjectBinding {
+					binding := test.GenBinding("moby-ID", "john@acme.com", "editors")
+					binding.Labels = map[string]string{kubermaticapiv1.GroupProjectBindingLabelKey: "moby-editors"}
+					return binding
+				}(),
+				/*add users*/
+				genUser("", "john", "john@acme.com"),
+			},
+			ExistingAPIUser:  *genAPIUser("john", "john@acme.com"),
+			ExpectedStatus:   http.StatusOK,
+			ExpectedResponse: `{"id":"4b2d8785b49bad23638b17d8db76857a79bf79441241a78a97d88cc64bbf766e","name":"john","creationTimestamp":"0001-01-01T00:00:00Z","email":"john@acme.com","projects":[{"id":"plan9-ID","group":"owners"}]}`,
+		},
 	}
 
 	for _, tc := range testcases {
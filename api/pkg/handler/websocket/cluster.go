@@ -0,0 +1,192 @@
+/*
+Copyright 2020 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package websocket
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+
+	"github.com/go-kit/kit/endpoint"
+
+	v1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
+	"github.com/kubermatic/kubermatic/api/pkg/handler/middleware"
+	clusterhandler "github.com/kubermatic/kubermatic/api/pkg/handler/v1/cluster"
+	"github.com/kubermatic/kubermatic/api/pkg/handler/v1/common"
+	"github.com/kubermatic/kubermatic/api/pkg/handler/v1/node"
+	"github.com/kubermatic/kubermatic/api/pkg/log"
+	"github.com/kubermatic/kubermatic/api/pkg/provider"
+	"github.com/kubermatic/kubermatic/api/pkg/util/errors"
+	"github.com/kubermatic/kubermatic/api/pkg/watcher"
+	kuberneteswatcher "github.com/kubermatic/kubermatic/api/pkg/watcher/kubernetes"
+	clusterv1alpha1 "github.com/kubermatic/machine-controller/pkg/apis/cluster/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+)
+
+// ClustersStreamEndpoint streams the clusters of all projects the user has access to, from all seeds
+func ClustersStreamEndpoint(seedsGetter provider.SeedsGetter, clusterProviderGetter provider.ClusterProviderGetter, userProjectMapper provider.ProjectMemberMapper) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		user, err := getUser(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		seeds, err := seedsGetter()
+		if err != nil {
+			return nil, common.KubernetesErrorToHTTPError(err)
+		}
+
+		stream := &Stream{User: user, Convert: ConvertCluster}
+		for _, seed := range seeds {
+			// if a Seed is bad, do not forward that error to the user, but only log
+			clusterProvider, err := clusterProviderGetter(seed)
+			if err != nil {
+				log.Logger.Errorf("failed to create cluster provider for seed %s: %v", seed.Name, err)
+				continue
+			}
+			stream.Watchers = append(stream.Watchers, kuberneteswatcher.NewResourceWatcher(clusterProvider.WatchAll, kuberneteswatcher.ProjectFromLabel, userProjectMapper))
+		}
+
+		return stream, nil
+	}
+}
+
+// NodeDeploymentsStreamEndpoint streams the node deployments of the given cluster
+func NodeDeploymentsStreamEndpoint(projectProvider provider.ProjectProvider, privilegedProjectProvider provider.PrivilegedProjectProvider, userInfoGetter provider.UserInfoGetter, userProjectMapper provider.ProjectMemberMapper) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(common.GetClusterReq)
+		clusterProvider := ctx.Value(middleware.ClusterProviderContextKey).(provider.ClusterProvider)
+
+		user, err := getUser(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		cluster, err := clusterhandler.GetCluster(ctx, projectProvider, privilegedProjectProvider, userInfoGetter, req.ProjectID, req.ClusterID, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		config, err := common.GetClusterClientConfig(ctx, userInfoGetter, clusterProvider, cluster, req.ProjectID)
+		if err != nil {
+			return nil, common.KubernetesErrorToHTTPError(err)
+		}
+		client, err := dynamic.NewForConfig(config)
+		if err != nil {
+			return nil, common.KubernetesErrorToHTTPError(err)
+		}
+
+		watchFunc := func() (watch.Interface, error) {
+			return client.Resource(clusterv1alpha1.SchemeGroupVersion.WithResource("machinedeployments")).Namespace(metav1.NamespaceSystem).Watch(metav1.ListOptions{})
+		}
+
+		return &Stream{
+			User:     user,
+			Watchers: []watcher.ResourceWatcher{kuberneteswatcher.NewResourceWatcher(watchFunc, kuberneteswatcher.FixedProject(req.ProjectID), userProjectMapper)},
+			Convert:  ConvertNodeDeployment,
+		}, nil
+	}
+}
+
+// ClusterEventsStreamEndpoint streams the events of the given cluster
+func ClusterEventsStreamEndpoint(projectProvider provider.ProjectProvider, privilegedProjectProvider provider.PrivilegedProjectProvider, userInfoGetter provider.UserInfoGetter, userProjectMapper provider.ProjectMemberMapper) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(clusterhandler.EventsReq)
+		privilegedClusterProvider := ctx.Value(middleware.PrivilegedClusterProviderContextKey).(provider.PrivilegedClusterProvider)
+		client := privilegedClusterProvider.GetSeedClusterAdminClient()
+
+		user, err := getUser(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		cluster, err := clusterhandler.GetCluster(ctx, projectProvider, privilegedProjectProvider, userInfoGetter, req.ProjectID, req.ClusterID, &provider.ClusterGetOptions{})
+		if err != nil {
+			return nil, err
+		}
+
+		selector := fields.OneTermEqualSelector("involvedObject.uid", string(cluster.UID))
+		switch req.Type {
+		case "warning":
+			selector = fields.AndSelectors(selector, fields.OneTermEqualSelector("type", corev1.EventTypeWarning))
+		case "normal":
+			selector = fields.AndSelectors(selector, fields.OneTermEqualSelector("type", corev1.EventTypeNormal))
+		}
+
+		watchFunc := func() (watch.Interface, error) {
+			return client.CoreV1().Events(metav1.NamespaceAll).Watch(metav1.ListOptions{FieldSelector: selector.String()})
+		}
+
+		return &Stream{
+			User:     user,
+			Watchers: []watcher.ResourceWatcher{kuberneteswatcher.NewResourceWatcher(watchFunc, kuberneteswatcher.FixedProject(req.ProjectID), userProjectMapper)},
+			Convert:  ConvertEvent,
+		}, nil
+	}
+}
+
+// ConvertCluster converts a cluster to the state streamed to the user
+func ConvertCluster(obj runtime.Object) (interface{}, error) {
+	cluster, ok := obj.(*v1.Cluster)
+	if !ok {
+		return nil, fmt.Errorf("expected cluster got %s", reflect.TypeOf(obj))
+	}
+
+	return clusterhandler.ConvertInternalClusterToState(cluster), nil
+}
+
+// ConvertNodeDeployment converts a machine deployment, as returned by a dynamic client, to a node deployment
+func ConvertNodeDeployment(obj runtime.Object) (interface{}, error) {
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return nil, fmt.Errorf("expected unstructured machine deployment got %s", reflect.TypeOf(obj))
+	}
+
+	machineDeployment := &clusterv1alpha1.MachineDeployment{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.UnstructuredContent(), machineDeployment); err != nil {
+		return nil, fmt.Errorf("failed to convert machine deployment %s: %v", u.GetName(), err)
+	}
+
+	return node.OutputMachineDeployment(machineDeployment)
+}
+
+// ConvertEvent converts a kubernetes event to its API representation
+func ConvertEvent(obj runtime.Object) (interface{}, error) {
+	event, ok := obj.(*corev1.Event)
+	if !ok {
+		return nil, fmt.Errorf("expected event got %s", reflect.TypeOf(obj))
+	}
+
+	return common.ConvertInternalEventToExternal(*event), nil
+}
+
+func getUser(ctx context.Context) (*v1.User, error) {
+	user, ok := ctx.Value(middleware.UserCRContextKey).(*v1.User)
+	if !ok {
+		return nil, errors.New(http.StatusInternalServerError, "no user in context found")
+	}
+
+	return user, nil
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2020 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	api "github.com/kubermatic/kubermatic/api/pkg/api/v1"
+	v1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
+	"github.com/kubermatic/kubermatic/api/pkg/log"
+	"github.com/kubermatic/kubermatic/api/pkg/watcher"
+
+	"github.com/gorilla/websocket"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// ConvertFunc converts the object of a watch event to its API representation
+type ConvertFunc func(obj runtime.Object) (interface{}, error)
+
+// Stream describes the resources which are streamed to a user
+type Stream struct {
+	User     *v1.User
+	Watchers []watcher.ResourceWatcher
+	Convert  ConvertFunc
+}
+
+// WriteStream writes the changes of the streamed resources to the websocket connection until the context is done
+func WriteStream(ctx context.Context, ws *websocket.Conn, stream *Stream) {
+	// the watchers publish concurrently, but the connection supports only one concurrent writer
+	var lock sync.Mutex
+
+	for _, w := range stream.Watchers {
+		unsubscribe := w.Subscribe(stream.User, func(data interface{}) {
+			event := data.(watch.Event)
+			object, err := stream.Convert(event.Object)
+			if err != nil {
+				log.Logger.Debug(err)
+				return
+			}
+
+			response, err := json.Marshal(api.WatchEvent{Type: string(event.Type), Object: object})
+			if err != nil {
+				log.Logger.Debug(err)
+				return
+			}
+
+			lock.Lock()
+			defer lock.Unlock()
+			if err := ws.WriteMessage(websocket.TextMessage, response); err != nil {
+				log.Logger.Debug(err)
+				return
+			}
+		})
+		defer unsubscribe()
+
+		go w.Run(ctx.Done())
+	}
+
+	<-ctx.Done()
+}
@@ -18,8 +18,10 @@ package kubernetes
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 
+	addonutils "github.com/kubermatic/kubermatic/api/pkg/addon"
 	kubermaticv1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
 	"github.com/kubermatic/kubermatic/api/pkg/provider"
 
@@ -29,6 +31,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -41,6 +44,8 @@ type AddonProvider struct {
 	accessibleAddons sets.String
 	// clientPrivileged is used for privileged operations
 	clientPrivileged ctrlruntimeclient.Client
+	// addonConfigProvider provides the AddonConfigs the variables of the addons are validated against
+	addonConfigProvider provider.AddonConfigProvider
 }
 
 // NewAddonProvider returns a new addon provider that respects RBAC policies
@@ -48,11 +53,13 @@ type AddonProvider struct {
 func NewAddonProvider(
 	clientPrivileged ctrlruntimeclient.Client,
 	createSeedImpersonatedClient impersonationClient,
-	accessibleAddons sets.String) *AddonProvider {
+	accessibleAddons sets.String,
+	addonConfigProvider provider.AddonConfigProvider) *AddonProvider {
 	return &AddonProvider{
 		createSeedImpersonatedClient: createSeedImpersonatedClient,
 		accessibleAddons:             accessibleAddons,
 		clientPrivileged:             clientPrivileged,
+		addonConfigProvider:          addonConfigProvider,
 	}
 }
 
@@ -62,6 +69,10 @@ func (p *AddonProvider) New(userInfo *provider.UserInfo, cluster *kubermaticv1.C
 		return nil, kerrors.NewUnauthorized(fmt.Sprintf("addon not accessible: %v", addonName))
 	}
 
+	if err := p.defaultAndValidateVariables(addonName, variables); err != nil {
+		return nil, err
+	}
+
 	seedImpersonatedClient, err := createImpersonationClientWrapperFromUserInfo(userInfo, p.createSeedImpersonatedClient)
 	if err != nil {
 		return nil, err
@@ -85,6 +96,10 @@ func (p *AddonProvider) NewUnsecured(cluster *kubermaticv1.Cluster, addonName st
 		return nil, kerrors.NewUnauthorized(fmt.Sprintf("addon not accessible: %v", addonName))
 	}
 
+	if err := p.defaultAndValidateVariables(addonName, variables); err != nil {
+		return nil, err
+	}
+
 	addon := genAddon(cluster, addonName, variables)
 
 	if err := p.clientPrivileged.Create(context.Background(), addon); err != nil {
@@ -117,6 +132,42 @@ func genAddon(cluster *kubermaticv1.Cluster, addonName string, variables *runtim
 	}
 }
 
+// defaultAndValidateVariables sets the defaults of the AddonConfig of the addon in the variables and validates them
+// against the controls of the AddonConfig. The variables of addons without AddonConfig aren't validated.
+func (p *AddonProvider) defaultAndValidateVariables(addonName string, variables *runtime.RawExtension) error {
+	addonConfig, err := p.addonConfigProvider.Get(addonName)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if len(addonConfig.Spec.Controls) == 0 {
+		return nil
+	}
+
+	values := map[string]interface{}{}
+	if len(variables.Raw) > 0 {
+		if err := json.Unmarshal(variables.Raw, &values); err != nil {
+			return kerrors.NewBadRequest(fmt.Sprintf("invalid variables for addon %s: %v", addonName, err))
+		}
+		if values == nil {
+			values = map[string]interface{}{}
+		}
+	}
+
+	if errs := addonutils.DefaultAndValidateVariables(addonConfig.Spec.Controls, values, field.NewPath("spec", "variables")); len(errs) > 0 {
+		return kerrors.NewBadRequest(fmt.Sprintf("invalid variables for addon %s: %v", addonName, errs.ToAggregate()))
+	}
+
+	raw, err := json.Marshal(values)
+	if err != nil {
+		return fmt.Errorf("failed to encode the variables: %v", err)
+	}
+	variables.Raw = raw
+	return nil
+}
+
 // Get returns the given addon, it uses the projectInternalName to determine the group the user belongs to
 func (p *AddonProvider) Get(userInfo *provider.UserInfo, cluster *kubermaticv1.Cluster, addonName string) (*kubermaticv1.Addon, error) {
 	if !p.accessibleAddons.Has(addonName) {
@@ -193,6 +244,10 @@ func (p *AddonProvider) Update(userInfo *provider.UserInfo, cluster *kubermaticv
 		return nil, kerrors.NewUnauthorized(fmt.Sprintf("addon not accessible: %v", addon.Name))
 	}
 
+	if err := p.defaultAndValidateVariables(addon.Name, &addon.Spec.Variables); err != nil {
+		return nil, err
+	}
+
 	seedImpersonatedClient, err := createImpersonationClientWrapperFromUserInfo(userInfo, p.createSeedImpersonatedClient)
 	if err != nil {
 		return nil, err
@@ -229,6 +284,10 @@ func (p *AddonProvider) UpdateUnsecured(cluster *kubermaticv1.Cluster, addon *ku
 		return nil, kerrors.NewUnauthorized(fmt.Sprintf("addon not accessible: %v", addon.Name))
 	}
 
+	if err := p.defaultAndValidateVariables(addon.Name, &addon.Spec.Variables); err != nil {
+		return nil, err
+	}
+
 	addon.Namespace = cluster.Status.NamespaceName
 	if err := p.clientPrivileged.Update(context.Background(), addon); err != nil {
 		return nil, err
@@ -249,7 +308,7 @@ func (p *AddonProvider) DeleteUnsecured(cluster *kubermaticv1.Cluster, addonName
 	return p.clientPrivileged.Delete(context.Background(), &kubermaticv1.Addon{ObjectMeta: metav1.ObjectMeta{Name: addonName, Namespace: cluster.Status.NamespaceName}})
 }
 
-func AddonProviderFactory(mapper meta.RESTMapper, seedKubeconfigGetter provider.SeedKubeconfigGetter, accessibleAddons sets.String) provider.AddonProviderGetter {
+func AddonProviderFactory(mapper meta.RESTMapper, seedKubeconfigGetter provider.SeedKubeconfigGetter, accessibleAddons sets.String, addonConfigProvider provider.AddonConfigProvider) provider.AddonProviderGetter {
 	return func(seed *kubermaticv1.Seed) (provider.AddonProvider, error) {
 		cfg, err := seedKubeconfigGetter(seed)
 		if err != nil {
@@ -264,6 +323,7 @@ func AddonProviderFactory(mapper meta.RESTMapper, seedKubeconfigGetter provider.
 			clientPrivileged,
 			defaultImpersonationClientForSeed.CreateImpersonatedClient,
 			accessibleAddons,
+			addonConfigProvider,
 		), nil
 	}
 }
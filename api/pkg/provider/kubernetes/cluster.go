@@ -28,6 +28,7 @@ import (
 	k8cuserclusterclient "github.com/kubermatic/kubermatic/api/pkg/cluster/client"
 	"github.com/kubermatic/kubermatic/api/pkg/controller/seed-controller-manager/cloud"
 	openshiftuserclusterresources "github.com/kubermatic/kubermatic/api/pkg/controller/user-cluster-controller-manager/resources/resources/openshift"
+	kubermaticclientset "github.com/kubermatic/kubermatic/api/pkg/crd/client/clientset/versioned"
 	kubermaticv1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
 	kuberneteshelper "github.com/kubermatic/kubermatic/api/pkg/kubernetes"
 	"github.com/kubermatic/kubermatic/api/pkg/provider"
@@ -39,6 +40,7 @@ import (
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/kubernetes"
 	restclient "k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
@@ -50,6 +52,7 @@ import (
 // UserClusterConnectionProvider offers functions to interact with an user cluster
 type UserClusterConnectionProvider interface {
 	GetClient(*kubermaticv1.Cluster, ...k8cuserclusterclient.ConfigOption) (ctrlruntimeclient.Client, error)
+	GetClientConfig(*kubermaticv1.Cluster, ...k8cuserclusterclient.ConfigOption) (*restclient.Config, error)
 }
 
 // extractGroupPrefixFunc is a function that knows how to extract a prefix (owners, editors) from "projectID-owners" group,
@@ -375,6 +378,21 @@ func (p *ClusterProvider) GetClientForCustomerCluster(userInfo *provider.UserInf
 	return p.userClusterConnProvider.GetClient(c, p.withImpersonation(userInfo))
 }
 
+// GetAdminClientConfigForCustomerCluster returns the client config for the given cluster
+//
+// Note that the client config you will get has admin privileges
+func (p *ClusterProvider) GetAdminClientConfigForCustomerCluster(c *kubermaticv1.Cluster) (*restclient.Config, error) {
+	return p.userClusterConnProvider.GetClientConfig(c)
+}
+
+// GetClientConfigForCustomerCluster returns the client config for the given cluster
+//
+// Note that the client config doesn't use admin account instead it authn/authz as userInfo(email, group)
+// This implies that you have to make sure the user has the appropriate permissions inside the user cluster
+func (p *ClusterProvider) GetClientConfigForCustomerCluster(userInfo *provider.UserInfo, c *kubermaticv1.Cluster) (*restclient.Config, error) {
+	return p.userClusterConnProvider.GetClientConfig(c, p.withImpersonation(userInfo))
+}
+
 func (p *ClusterProvider) GetTokenForCustomerCluster(userInfo *provider.UserInfo, cluster *kubermaticv1.Cluster) (string, error) {
 	parts := strings.Split(userInfo.Group, "-")
 	switch parts[0] {
@@ -392,7 +410,8 @@ func (p *ClusterProvider) GetTokenForCustomerCluster(userInfo *provider.UserInfo
 
 		return string(s.Data[resources.ViewerTokenSecretKey]), nil
 	default:
-		return "", fmt.Errorf("user group %s not supported", userInfo.Group)
+		// members of custom project roles have no permissions inside of the user clusters
+		return "", kerrors.NewForbidden(schema.GroupResource{}, cluster.Name, fmt.Errorf("user group %s has no access to the cluster", userInfo.Group))
 	}
 }
 
@@ -494,3 +513,15 @@ func (p *ClusterProvider) ListAll() (*kubermaticv1.ClusterList, error) {
 
 	return projectClusters, nil
 }
+
+// WatchAll watches all clusters
+//
+// Note that the admin privileges are used to watch all clusters
+func (p *ClusterProvider) WatchAll() (watch.Interface, error) {
+	client, err := kubermaticclientset.NewForConfig(p.seedKubeconfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kubermatic client: %v", err)
+	}
+
+	return client.KubermaticV1().Clusters().Watch(metav1.ListOptions{})
+}
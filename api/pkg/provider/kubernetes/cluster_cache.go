@@ -0,0 +1,298 @@
+/*
+Copyright 2020 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	kubermaticclientset "github.com/kubermatic/kubermatic/api/pkg/crd/client/clientset/versioned"
+	kubermaticv1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
+	"github.com/kubermatic/kubermatic/api/pkg/provider"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+)
+
+const (
+	// clusterProjectIndex is the name of the index of the clusters by their project
+	clusterProjectIndex = "project"
+
+	// defaultClusterCacheSyncTimeout is how long a request waits for the clusters of a seed that haven't been synced yet
+	defaultClusterCacheSyncTimeout = 5 * time.Second
+)
+
+// SeedKubermaticClientGetter is used to get a Kubermatic clientset for a given seed
+type SeedKubermaticClientGetter = func(seed *kubermaticv1.Seed) (kubermaticclientset.Interface, error)
+
+// ClusterCache is a master-side cache of the clusters of all seeds indexed by their project.
+// It runs a cluster informer per seed, so listing the clusters of a project doesn't hit the seeds
+// and a seed that can't be reached only leaves out its own clusters.
+type ClusterCache struct {
+	seedsGetter  provider.SeedsGetter
+	clientGetter SeedKubermaticClientGetter
+	syncTimeout  time.Duration
+
+	lock      sync.Mutex
+	informers map[string]*seedClusterInformer
+}
+
+type seedClusterInformer struct {
+	// seedKubeconfig is the kubeconfig reference of the seed the informer has been created for,
+	// the informer is recreated when it changes. Other changes of the seed, like updates of its
+	// status, don't affect the connection and keep the informer running.
+	seedKubeconfig corev1.ObjectReference
+	informer       cache.SharedIndexInformer
+	stop           chan struct{}
+	// health tracks the list and watch calls of the informer, so a seed that becomes
+	// unreachable after the informer has synced is still reported
+	health *listWatchHealth
+}
+
+// listWatchHealth records the outcome of the latest list and watch calls of an informer
+type listWatchHealth struct {
+	lock               sync.Mutex
+	lastError          error
+	lastSuccessfulList time.Time
+}
+
+func (h *listWatchHealth) observe(err error, isList bool) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.lastError = err
+	if err == nil && isList {
+		h.lastSuccessfulList = time.Now()
+	}
+}
+
+// err returns why the cached clusters might be outdated, nil is returned if the latest call succeeded
+func (h *listWatchHealth) err() error {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	if h.lastError == nil {
+		return nil
+	}
+	if h.lastSuccessfulList.IsZero() {
+		return fmt.Errorf("the clusters couldn't be listed: %v", h.lastError)
+	}
+	return fmt.Errorf("the clusters might be outdated, they haven't been listed since %s: %v", h.lastSuccessfulList.UTC().Format(time.RFC3339), h.lastError)
+}
+
+var _ provider.ProjectClusterLister = &ClusterCache{}
+
+// NewClusterCache returns a cache of the clusters in the seeds returned by the seedsGetter
+func NewClusterCache(seedsGetter provider.SeedsGetter, clientGetter SeedKubermaticClientGetter) *ClusterCache {
+	return &ClusterCache{
+		seedsGetter:  seedsGetter,
+		clientGetter: clientGetter,
+		syncTimeout:  defaultClusterCacheSyncTimeout,
+		informers:    map[string]*seedClusterInformer{},
+	}
+}
+
+// Run keeps the informers in line with the seeds until the context is done, starting the informers
+// ahead of the first request. The informers are stopped when the context is done.
+func (c *ClusterCache) Run(ctx context.Context, period time.Duration, log *zap.SugaredLogger) {
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+	for {
+		if _, err := c.sync(); err != nil {
+			log.Errorw("failed to sync the cluster cache with the seeds", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			c.stop()
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// List returns the clusters of the given project in all seeds. The clusters of the seeds whose
+// informers can't be started or haven't synced in time are left out and the reason is returned by seed name.
+// A seed that becomes unreachable after its informer has synced keeps returning the last known clusters,
+// and is reported by seed name as well until its clusters can be listed and watched again.
+func (c *ClusterCache) List(projectID string) ([]*kubermaticv1.Cluster, map[string]error, error) {
+	informers, err := c.sync()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	seedErrors := map[string]error{}
+	for seedName, err := range informers.errors {
+		seedErrors[seedName] = err
+	}
+
+	// wait for the seeds that haven't been synced yet in parallel, so a single
+	// unreachable seed delays the response by the sync timeout at most
+	var wg sync.WaitGroup
+	var lock sync.Mutex
+	result := []*kubermaticv1.Cluster{}
+	for seedName, informer := range informers.informers {
+		wg.Add(1)
+		go func(seedName string, informer *seedClusterInformer) {
+			defer wg.Done()
+			clusters, err := c.listFromInformer(informer, projectID)
+
+			lock.Lock()
+			defer lock.Unlock()
+			if err != nil {
+				seedErrors[seedName] = err
+			}
+			result = append(result, clusters...)
+		}(seedName, informer)
+	}
+	wg.Wait()
+
+	return result, seedErrors, nil
+}
+
+// listFromInformer returns the cached clusters of the project. The clusters are returned along with
+// an error if the informer failed to list or watch the clusters since, as they might be outdated.
+func (c *ClusterCache) listFromInformer(informer *seedClusterInformer, projectID string) ([]*kubermaticv1.Cluster, error) {
+	if !informer.informer.HasSynced() {
+		stop := make(chan struct{})
+		timer := time.AfterFunc(c.syncTimeout, func() { close(stop) })
+		synced := cache.WaitForCacheSync(stop, informer.informer.HasSynced)
+		timer.Stop()
+		if !synced {
+			if err := informer.health.err(); err != nil {
+				return nil, err
+			}
+			return nil, fmt.Errorf("the clusters couldn't be listed within %v", c.syncTimeout)
+		}
+	}
+
+	objs, err := informer.informer.GetIndexer().ByIndex(clusterProjectIndex, projectID)
+	if err != nil {
+		return nil, err
+	}
+	clusters := make([]*kubermaticv1.Cluster, 0, len(objs))
+	for _, obj := range objs {
+		cluster, ok := obj.(*kubermaticv1.Cluster)
+		if !ok {
+			continue
+		}
+		clusters = append(clusters, cluster.DeepCopy())
+	}
+	return clusters, informer.health.err()
+}
+
+type seedClusterInformers struct {
+	informers map[string]*seedClusterInformer
+	errors    map[string]error
+}
+
+// sync starts the informers of the new and changed seeds and stops the informers of the removed seeds
+func (c *ClusterCache) sync() (*seedClusterInformers, error) {
+	seeds, err := c.seedsGetter()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the seeds: %v", err)
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for seedName, existing := range c.informers {
+		if seed, ok := seeds[seedName]; !ok || seed.Spec.Kubeconfig != existing.seedKubeconfig {
+			close(existing.stop)
+			delete(c.informers, seedName)
+		}
+	}
+
+	result := &seedClusterInformers{
+		informers: map[string]*seedClusterInformer{},
+		errors:    map[string]error{},
+	}
+	for seedName, seed := range seeds {
+		existing, ok := c.informers[seedName]
+		if !ok {
+			existing, err = c.startInformer(seed)
+			if err != nil {
+				result.errors[seedName] = err
+				continue
+			}
+			c.informers[seedName] = existing
+		}
+		result.informers[seedName] = existing
+	}
+	return result, nil
+}
+
+func (c *ClusterCache) startInformer(seed *kubermaticv1.Seed) (*seedClusterInformer, error) {
+	client, err := c.clientGetter(seed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the client: %v", err)
+	}
+
+	// the informer doesn't expose the errors of its reflector, thus they are recorded by the list and watch functions
+	health := &listWatchHealth{}
+	informer := cache.NewSharedIndexInformer(
+		&cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				list, err := client.KubermaticV1().Clusters().List(options)
+				health.observe(err, true)
+				return list, err
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				watcher, err := client.KubermaticV1().Clusters().Watch(options)
+				health.observe(err, false)
+				return watcher, err
+			},
+		},
+		&kubermaticv1.Cluster{},
+		0,
+		cache.Indexers{clusterProjectIndex: clusterProjectIndexFunc},
+	)
+	stop := make(chan struct{})
+	go informer.Run(stop)
+
+	return &seedClusterInformer{
+		seedKubeconfig: seed.Spec.Kubeconfig,
+		informer:       informer,
+		stop:           stop,
+		health:         health,
+	}, nil
+}
+
+func (c *ClusterCache) stop() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for seedName, existing := range c.informers {
+		close(existing.stop)
+		delete(c.informers, seedName)
+	}
+}
+
+func clusterProjectIndexFunc(obj interface{}) ([]string, error) {
+	cluster, ok := obj.(*kubermaticv1.Cluster)
+	if !ok {
+		return nil, fmt.Errorf("expected a cluster, got %T", obj)
+	}
+	projectID := cluster.Labels[kubermaticv1.ProjectIDLabelKey]
+	if projectID == "" {
+		return nil, nil
+	}
+	return []string{projectID}, nil
+}
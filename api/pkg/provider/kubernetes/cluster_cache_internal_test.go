@@ -0,0 +1,170 @@
+/*
+Copyright 2020 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	kubermaticclientset "github.com/kubermatic/kubermatic/api/pkg/crd/client/clientset/versioned"
+	kubermaticfakeclientset "github.com/kubermatic/kubermatic/api/pkg/crd/client/clientset/versioned/fake"
+	kubermaticv1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+func TestClusterCacheList(t *testing.T) {
+	unreachableSeedClient := kubermaticfakeclientset.NewSimpleClientset()
+	unreachableSeedClient.PrependReactor("list", "clusters", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.New("connection refused")
+	})
+	clients := map[string]kubermaticclientset.Interface{
+		"europe-west3": kubermaticfakeclientset.NewSimpleClientset(
+			genCachedCluster("cluster-a", "my-project"),
+			genCachedCluster("cluster-b", "other-project"),
+		),
+		"us-central1": kubermaticfakeclientset.NewSimpleClientset(
+			genCachedCluster("cluster-c", "my-project"),
+		),
+		"asia-east1": unreachableSeedClient,
+	}
+	seeds := map[string]*kubermaticv1.Seed{
+		"europe-west3": genCachedSeed("europe-west3"),
+		"us-central1":  genCachedSeed("us-central1"),
+		"asia-east1":   genCachedSeed("asia-east1"),
+		"africa-west1": genCachedSeed("africa-west1"),
+	}
+
+	clusterCache := NewClusterCache(
+		func() (map[string]*kubermaticv1.Seed, error) {
+			return seeds, nil
+		},
+		func(seed *kubermaticv1.Seed) (kubermaticclientset.Interface, error) {
+			client, ok := clients[seed.Name]
+			if !ok {
+				return nil, fmt.Errorf("no kubeconfig for seed %q", seed.Name)
+			}
+			return client, nil
+		},
+	)
+	clusterCache.syncTimeout = 200 * time.Millisecond
+	defer clusterCache.stop()
+
+	clusters, seedErrors, err := clusterCache.List("my-project")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clusterNames := []string{}
+	for _, cluster := range clusters {
+		clusterNames = append(clusterNames, cluster.Name)
+	}
+	sort.Strings(clusterNames)
+	if len(clusterNames) != 2 || clusterNames[0] != "cluster-a" || clusterNames[1] != "cluster-c" {
+		t.Fatalf("expected the clusters of my-project in the reachable seeds, got %v", clusterNames)
+	}
+
+	if len(seedErrors) != 2 {
+		t.Fatalf("expected errors for the two unreachable seeds, got %v", seedErrors)
+	}
+	if err := seedErrors["africa-west1"]; err == nil || err.Error() != `failed to create the client: no kubeconfig for seed "africa-west1"` {
+		t.Fatalf("unexpected error for the seed without a kubeconfig: %v", err)
+	}
+	if err := seedErrors["asia-east1"]; err == nil {
+		t.Fatal("expected an error for the seed whose clusters can't be listed")
+	}
+
+	// a seed that fails after its clusters have been listed keeps returning them, but is reported
+	clusterCache.informers["us-central1"].health.observe(errors.New("connection refused"), false)
+	clusters, seedErrors, err = clusterCache.List("my-project")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(clusters) != 2 {
+		t.Fatalf("expected the last known clusters of the failing seed, got %v", clusters)
+	}
+	if err := seedErrors["us-central1"]; err == nil || !strings.Contains(err.Error(), "the clusters might be outdated") {
+		t.Fatalf("expected an error for the seed that failed after being synced, got %v", err)
+	}
+
+	// the error is cleared once the seed can be watched again
+	clusterCache.informers["us-central1"].health.observe(nil, false)
+	_, seedErrors, err = clusterCache.List("my-project")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err, ok := seedErrors["us-central1"]; ok {
+		t.Fatalf("expected no error for the recovered seed, got %v", err)
+	}
+
+	// the informer keeps running when the seed changes without affecting the connection
+	informer := clusterCache.informers["us-central1"]
+	updatedSeed := genCachedSeed("us-central1")
+	updatedSeed.ResourceVersion = "2"
+	updatedSeed.Spec.Location = "Council Bluffs"
+	seeds["us-central1"] = updatedSeed
+	if _, _, err := clusterCache.List("my-project"); err != nil {
+		t.Fatal(err)
+	}
+	if clusterCache.informers["us-central1"] != informer {
+		t.Fatal("expected the informer to keep running after an update of the seed location")
+	}
+
+	// the informer is recreated when the kubeconfig of the seed changes
+	updatedSeed = updatedSeed.DeepCopy()
+	updatedSeed.ResourceVersion = "3"
+	updatedSeed.Spec.Kubeconfig.Name = "new-kubeconfig"
+	seeds["us-central1"] = updatedSeed
+	if _, _, err := clusterCache.List("my-project"); err != nil {
+		t.Fatal(err)
+	}
+	if clusterCache.informers["us-central1"] == informer {
+		t.Fatal("expected the informer to be recreated after the kubeconfig of the seed changed")
+	}
+
+	// the informers of the removed seeds are stopped
+	delete(seeds, "europe-west3")
+	clusters, _, err = clusterCache.List("my-project")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(clusters) != 1 || clusters[0].Name != "cluster-c" {
+		t.Fatalf("expected only the clusters of the remaining seeds, got %v", clusters)
+	}
+	if _, ok := clusterCache.informers["europe-west3"]; ok {
+		t.Fatal("expected the informer of the removed seed to be stopped")
+	}
+}
+
+func genCachedSeed(name string) *kubermaticv1.Seed {
+	return &kubermaticv1.Seed{ObjectMeta: metav1.ObjectMeta{Name: name, ResourceVersion: "1"}}
+}
+
+func genCachedCluster(name, projectID string) *kubermaticv1.Cluster {
+	return &kubermaticv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{kubermaticv1.ProjectIDLabelKey: projectID},
+		},
+	}
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2020 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kubermatic/kubermatic/api/pkg/controller/master-controller-manager/rbac"
+	kubermaticv1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
+	"github.com/kubermatic/kubermatic/api/pkg/provider"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ClusterTemplateProvider is a object to handle cluster templates
+type ClusterTemplateProvider struct {
+	client ctrlruntimeclient.Client
+	ctx    context.Context
+}
+
+var _ provider.ClusterTemplateProvider = &ClusterTemplateProvider{}
+
+// NewClusterTemplateProvider returns a cluster template provider
+func NewClusterTemplateProvider(ctx context.Context, client ctrlruntimeclient.Client) *ClusterTemplateProvider {
+	return &ClusterTemplateProvider{client: client, ctx: ctx}
+}
+
+// New creates a cluster template, only admins can create global templates
+func (p *ClusterTemplateProvider) New(userInfo *provider.UserInfo, template *kubermaticv1.ClusterTemplate) (*kubermaticv1.ClusterTemplate, error) {
+	if template == nil {
+		return nil, fmt.Errorf("the cluster template can not be nil")
+	}
+	switch template.Spec.Scope {
+	case kubermaticv1.ClusterTemplateScopeGlobal:
+		if !userInfo.IsAdmin {
+			return nil, kerrors.NewForbidden(clusterTemplateResource(), template.Name, fmt.Errorf("only admins can create global cluster templates"))
+		}
+	case kubermaticv1.ClusterTemplateScopeProject, kubermaticv1.ClusterTemplateScopeUser:
+		if !userInfo.IsAdmin && isViewer(userInfo) {
+			return nil, kerrors.NewForbidden(clusterTemplateResource(), template.Name, fmt.Errorf("viewers can not create cluster templates"))
+		}
+	default:
+		return nil, fmt.Errorf("unknown cluster template scope %q", template.Spec.Scope)
+	}
+
+	if err := p.client.Create(p.ctx, template); err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+// List returns the templates which can be used in the given project
+func (p *ClusterTemplateProvider) List(userInfo *provider.UserInfo, projectID string) ([]kubermaticv1.ClusterTemplate, error) {
+	templateList := &kubermaticv1.ClusterTemplateList{}
+	if err := p.client.List(p.ctx, templateList); err != nil {
+		return nil, fmt.Errorf("failed to list cluster templates: %v", err)
+	}
+
+	var templates []kubermaticv1.ClusterTemplate
+	for _, template := range templateList.Items {
+		if canUseClusterTemplate(userInfo, projectID, &template) {
+			templates = append(templates, template)
+		}
+	}
+	return templates, nil
+}
+
+// Get returns the template if it can be used in the given project
+func (p *ClusterTemplateProvider) Get(userInfo *provider.UserInfo, projectID, name string) (*kubermaticv1.ClusterTemplate, error) {
+	template := &kubermaticv1.ClusterTemplate{}
+	if err := p.client.Get(p.ctx, ctrlruntimeclient.ObjectKey{Name: name}, template); err != nil {
+		return nil, err
+	}
+	// templates of other users and projects are not revealed
+	if !canUseClusterTemplate(userInfo, projectID, template) {
+		return nil, kerrors.NewNotFound(clusterTemplateResource(), name)
+	}
+	return template, nil
+}
+
+// Delete deletes the template, templates can only be deleted by their owner, project templates
+// also by the editors of the project and global templates by admins
+func (p *ClusterTemplateProvider) Delete(userInfo *provider.UserInfo, projectID, name string) error {
+	template, err := p.Get(userInfo, projectID, name)
+	if err != nil {
+		return err
+	}
+
+	var allowed bool
+	switch template.Spec.Scope {
+	case kubermaticv1.ClusterTemplateScopeGlobal:
+		allowed = userInfo.IsAdmin
+	case kubermaticv1.ClusterTemplateScopeProject:
+		allowed = userInfo.IsAdmin || !isViewer(userInfo)
+	default:
+		allowed = template.Spec.Owner == userInfo.Email
+	}
+	if !allowed {
+		return kerrors.NewForbidden(clusterTemplateResource(), name, fmt.Errorf("%q is not allowed to delete the cluster template", userInfo.Email))
+	}
+
+	return p.client.Delete(p.ctx, template)
+}
+
+// canUseClusterTemplate checks if the template is visible in the given project
+func canUseClusterTemplate(userInfo *provider.UserInfo, projectID string, template *kubermaticv1.ClusterTemplate) bool {
+	switch template.Spec.Scope {
+	case kubermaticv1.ClusterTemplateScopeGlobal:
+		return true
+	case kubermaticv1.ClusterTemplateScopeProject:
+		return template.Spec.ProjectID == projectID
+	case kubermaticv1.ClusterTemplateScopeUser:
+		return template.Spec.Owner == userInfo.Email
+	}
+	return false
+}
+
+func isViewer(userInfo *provider.UserInfo) bool {
+	return rbac.ExtractGroupPrefix(userInfo.Group) == rbac.ViewerGroupNamePrefix
+}
+
+func clusterTemplateResource() schema.GroupResource {
+	return schema.GroupResource{Group: kubermaticv1.GroupName, Resource: kubermaticv1.ClusterTemplateResourceName}
+}
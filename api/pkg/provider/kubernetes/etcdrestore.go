@@ -0,0 +1,177 @@
+/*
+Copyright 2020 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"context"
+
+	kubermaticv1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
+	"github.com/kubermatic/kubermatic/api/pkg/provider"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/rand"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// EtcdRestoreProvider struct that holds required components of the EtcdRestoreProvider implementation
+type EtcdRestoreProvider struct {
+	// createSeedImpersonatedClient is used as a ground for impersonation
+	// whenever a connection to Seed API server is required
+	createSeedImpersonatedClient impersonationClient
+	// clientPrivileged is used for privileged operations
+	clientPrivileged ctrlruntimeclient.Client
+}
+
+// NewEtcdRestoreProvider returns a new etcd restore provider that respects RBAC policies
+// it uses createSeedImpersonatedClient to create a connection that uses user impersonation
+func NewEtcdRestoreProvider(clientPrivileged ctrlruntimeclient.Client, createSeedImpersonatedClient impersonationClient) *EtcdRestoreProvider {
+	return &EtcdRestoreProvider{
+		createSeedImpersonatedClient: createSeedImpersonatedClient,
+		clientPrivileged:             clientPrivileged,
+	}
+}
+
+// New creates a new restore of the given backup for the cluster
+func (p *EtcdRestoreProvider) New(userInfo *provider.UserInfo, cluster *kubermaticv1.Cluster, backupName string) (*kubermaticv1.EtcdRestore, error) {
+	seedImpersonatedClient, err := createImpersonationClientWrapperFromUserInfo(userInfo, p.createSeedImpersonatedClient)
+	if err != nil {
+		return nil, err
+	}
+
+	restore := genEtcdRestore(cluster, backupName)
+	if err := seedImpersonatedClient.Create(context.Background(), restore); err != nil {
+		return nil, err
+	}
+	return restore, nil
+}
+
+// NewUnsecured creates a new restore of the given backup for the cluster
+//
+// Note that this function:
+// is unsafe in a sense that it uses privileged account to create the resource
+func (p *EtcdRestoreProvider) NewUnsecured(cluster *kubermaticv1.Cluster, backupName string) (*kubermaticv1.EtcdRestore, error) {
+	restore := genEtcdRestore(cluster, backupName)
+	if err := p.clientPrivileged.Create(context.Background(), restore); err != nil {
+		return nil, err
+	}
+	return restore, nil
+}
+
+func genEtcdRestore(cluster *kubermaticv1.Cluster, backupName string) *kubermaticv1.EtcdRestore {
+	gv := kubermaticv1.SchemeGroupVersion
+	return &kubermaticv1.EtcdRestore{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            rand.String(10),
+			Namespace:       cluster.Status.NamespaceName,
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(cluster, gv.WithKind(kubermaticv1.ClusterKindName))},
+		},
+		Spec: kubermaticv1.EtcdRestoreSpec{
+			Cluster: corev1.ObjectReference{
+				Name:       cluster.Name,
+				UID:        cluster.UID,
+				APIVersion: cluster.APIVersion,
+				Kind:       kubermaticv1.ClusterKindName,
+			},
+			BackupName: backupName,
+		},
+		Status: kubermaticv1.EtcdRestoreStatus{
+			Phase: kubermaticv1.EtcdRestorePhaseStarted,
+		},
+	}
+}
+
+// Get returns the given restore
+func (p *EtcdRestoreProvider) Get(userInfo *provider.UserInfo, cluster *kubermaticv1.Cluster, restoreName string) (*kubermaticv1.EtcdRestore, error) {
+	seedImpersonatedClient, err := createImpersonationClientWrapperFromUserInfo(userInfo, p.createSeedImpersonatedClient)
+	if err != nil {
+		return nil, err
+	}
+
+	restore := &kubermaticv1.EtcdRestore{}
+	if err := seedImpersonatedClient.Get(context.Background(), ctrlruntimeclient.ObjectKey{Namespace: cluster.Status.NamespaceName, Name: restoreName}, restore); err != nil {
+		return nil, err
+	}
+	return restore, nil
+}
+
+// GetUnsecured returns the given restore
+//
+// Note that this function:
+// is unsafe in a sense that it uses privileged account to get the resource
+func (p *EtcdRestoreProvider) GetUnsecured(cluster *kubermaticv1.Cluster, restoreName string) (*kubermaticv1.EtcdRestore, error) {
+	restore := &kubermaticv1.EtcdRestore{}
+	if err := p.clientPrivileged.Get(context.Background(), ctrlruntimeclient.ObjectKey{Namespace: cluster.Status.NamespaceName, Name: restoreName}, restore); err != nil {
+		return nil, err
+	}
+	return restore, nil
+}
+
+// List returns all restores of the given cluster
+func (p *EtcdRestoreProvider) List(userInfo *provider.UserInfo, cluster *kubermaticv1.Cluster) ([]*kubermaticv1.EtcdRestore, error) {
+	seedImpersonatedClient, err := createImpersonationClientWrapperFromUserInfo(userInfo, p.createSeedImpersonatedClient)
+	if err != nil {
+		return nil, err
+	}
+
+	restoreList := &kubermaticv1.EtcdRestoreList{}
+	if err := seedImpersonatedClient.List(context.Background(), restoreList, ctrlruntimeclient.InNamespace(cluster.Status.NamespaceName)); err != nil {
+		return nil, err
+	}
+	return filterEtcdRestores(restoreList, cluster), nil
+}
+
+// ListUnsecured returns all restores of the given cluster
+//
+// Note that this function:
+// is unsafe in a sense that it uses privileged account to get the resources
+func (p *EtcdRestoreProvider) ListUnsecured(cluster *kubermaticv1.Cluster) ([]*kubermaticv1.EtcdRestore, error) {
+	restoreList := &kubermaticv1.EtcdRestoreList{}
+	if err := p.clientPrivileged.List(context.Background(), restoreList, ctrlruntimeclient.InNamespace(cluster.Status.NamespaceName)); err != nil {
+		return nil, err
+	}
+	return filterEtcdRestores(restoreList, cluster), nil
+}
+
+func filterEtcdRestores(restoreList *kubermaticv1.EtcdRestoreList, cluster *kubermaticv1.Cluster) []*kubermaticv1.EtcdRestore {
+	result := []*kubermaticv1.EtcdRestore{}
+	for _, restore := range restoreList.Items {
+		if restore.Spec.Cluster.Name == cluster.Name {
+			result = append(result, restore.DeepCopy())
+		}
+	}
+	return result
+}
+
+func EtcdRestoreProviderFactory(mapper meta.RESTMapper, seedKubeconfigGetter provider.SeedKubeconfigGetter) provider.EtcdRestoreProviderGetter {
+	return func(seed *kubermaticv1.Seed) (provider.EtcdRestoreProvider, error) {
+		cfg, err := seedKubeconfigGetter(seed)
+		if err != nil {
+			return nil, err
+		}
+		defaultImpersonationClientForSeed := NewImpersonationClient(cfg, mapper)
+		clientPrivileged, err := ctrlruntimeclient.New(cfg, ctrlruntimeclient.Options{Mapper: mapper})
+		if err != nil {
+			return nil, err
+		}
+		return NewEtcdRestoreProvider(
+			clientPrivileged,
+			defaultImpersonationClientForSeed.CreateImpersonatedClient,
+		), nil
+	}
+}
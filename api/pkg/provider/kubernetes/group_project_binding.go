@@ -0,0 +1,284 @@
+/*
+Copyright 2020 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/kubermatic/kubermatic/api/pkg/controller/master-controller-manager/rbac"
+	kubermaticv1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
+	kuberneteshelper "github.com/kubermatic/kubermatic/api/pkg/kubernetes"
+	"github.com/kubermatic/kubermatic/api/pkg/provider"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/cache"
+	"k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/apimachinery/pkg/util/sets"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// groupsResyncPeriod is the period after which the project memberships of a user are synchronized
+	// with the groups of the user again, even though neither the groups nor the group project bindings changed
+	groupsResyncPeriod = 5 * time.Minute
+	// syncedGroupsCacheSize is the number of users whose last synchronized groups are remembered
+	syncedGroupsCacheSize = 4096
+)
+
+// NewGroupProjectBindingProvider returns a group project bindings provider
+func NewGroupProjectBindingProvider(clientPrivileged ctrlruntimeclient.Client, isServiceAccountFunc func(string) bool) *GroupProjectBindingProvider {
+	return &GroupProjectBindingProvider{
+		clientPrivileged:     clientPrivileged,
+		isServiceAccountFunc: isServiceAccountFunc,
+		syncedGroups:         cache.NewLRUExpireCache(syncedGroupsCacheSize),
+	}
+}
+
+var _ provider.GroupProjectBindingProvider = &GroupProjectBindingProvider{}
+
+// GroupProjectBindingProvider binds groups of the identity provider with projects
+type GroupProjectBindingProvider struct {
+	// treat clientPrivileged as a privileged user and use wisely
+	clientPrivileged ctrlruntimeclient.Client
+
+	// since service account are special type of user this functions
+	// helps to determine if the given email address belongs to a service account
+	isServiceAccountFunc func(email string) bool
+
+	// syncedGroups remembers the groups the project memberships of a user were last synchronized with
+	syncedGroups *cache.LRUExpireCache
+	// generation is increased whenever the group project bindings are changed through the provider,
+	// which invalidates all synchronized groups
+	generation uint64
+}
+
+// List returns all group project bindings
+func (p *GroupProjectBindingProvider) List(userInfo *provider.UserInfo) ([]kubermaticv1.GroupProjectBinding, error) {
+	if !userInfo.IsAdmin {
+		return nil, kerrors.NewForbidden(schema.GroupResource{}, userInfo.Email, fmt.Errorf("%q doesn't have admin rights", userInfo.Email))
+	}
+	bindings := &kubermaticv1.GroupProjectBindingList{}
+	if err := p.clientPrivileged.List(context.Background(), bindings); err != nil {
+		return nil, err
+	}
+	return bindings.Items, nil
+}
+
+// Create creates a group project binding, the project must exist and the role must be either
+// one of the built-in roles or an existing ProjectRole
+func (p *GroupProjectBindingProvider) Create(userInfo *provider.UserInfo, binding *kubermaticv1.GroupProjectBinding) (*kubermaticv1.GroupProjectBinding, error) {
+	if !userInfo.IsAdmin {
+		return nil, kerrors.NewForbidden(schema.GroupResource{}, userInfo.Email, fmt.Errorf("%q doesn't have admin rights", userInfo.Email))
+	}
+	if binding == nil {
+		return nil, fmt.Errorf("the binding can not be nil")
+	}
+
+	project := &kubermaticv1.Project{}
+	if err := p.clientPrivileged.Get(context.Background(), ctrlruntimeclient.ObjectKey{Name: binding.Spec.ProjectID}, project); err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil, kerrors.NewBadRequest(fmt.Sprintf("project %s doesn't exist", binding.Spec.ProjectID))
+		}
+		return nil, err
+	}
+	if err := validateGroup(p.clientPrivileged, project.Name, rbac.GenerateActualGroupNameFor(project.Name, binding.Spec.Role)); err != nil {
+		return nil, err
+	}
+
+	if binding.Name == "" {
+		binding.Name = rand.String(10)
+	}
+	binding.OwnerReferences = []metav1.OwnerReference{
+		{
+			APIVersion: kubermaticv1.SchemeGroupVersion.String(),
+			Kind:       kubermaticv1.ProjectKindName,
+			UID:        project.GetUID(),
+			Name:       project.Name,
+		},
+	}
+	if err := p.clientPrivileged.Create(context.Background(), binding); err != nil {
+		return nil, err
+	}
+	atomic.AddUint64(&p.generation, 1)
+	return binding, nil
+}
+
+// Delete deletes the group project binding and the UserProjectBindings that were derived from it
+func (p *GroupProjectBindingProvider) Delete(userInfo *provider.UserInfo, name string) error {
+	if !userInfo.IsAdmin {
+		return kerrors.NewForbidden(schema.GroupResource{}, userInfo.Email, fmt.Errorf("%q doesn't have admin rights", userInfo.Email))
+	}
+	if err := p.clientPrivileged.Delete(context.Background(), &kubermaticv1.GroupProjectBinding{ObjectMeta: metav1.ObjectMeta{Name: name}}); err != nil {
+		return err
+	}
+	atomic.AddUint64(&p.generation, 1)
+
+	derivedBindings := &kubermaticv1.UserProjectBindingList{}
+	if err := p.clientPrivileged.List(context.Background(), derivedBindings, ctrlruntimeclient.MatchingLabels{kubermaticv1.GroupProjectBindingLabelKey: name}); err != nil {
+		return err
+	}
+	for _, derivedBinding := range derivedBindings.Items {
+		if err := p.clientPrivileged.Delete(context.Background(), &derivedBinding); err != nil && !kerrors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// SyncUnsecured reconciles the project memberships of the given user with the groups the user belongs to
+//
+// For every project bound to at least one of the groups the user gets a UserProjectBinding labelled with
+// the name of the GroupProjectBinding, when several groups grant access to the same project the most privileged role wins.
+// Labelled bindings whose groups the user no longer belongs to are removed. Bindings created manually
+// always take precedence, they are never modified and no labelled bindings are kept next to them.
+//
+// This function is unsafe in a sense that it uses privileged account to manage the memberships
+func (p *GroupProjectBindingProvider) SyncUnsecured(userEmail string, groups []string) error {
+	if p.isServiceAccountFunc(userEmail) {
+		return nil
+	}
+	ctx := context.Background()
+
+	groupBindings := &kubermaticv1.GroupProjectBindingList{}
+	if err := p.clientPrivileged.List(ctx, groupBindings); err != nil {
+		return err
+	}
+	sort.Slice(groupBindings.Items, func(i, j int) bool {
+		return groupBindings.Items[i].Name < groupBindings.Items[j].Name
+	})
+
+	userGroups := sets.NewString(groups...)
+	desiredBindings := map[string]*kubermaticv1.GroupProjectBinding{}
+	for i, groupBinding := range groupBindings.Items {
+		if !userGroups.Has(groupBinding.Spec.Group) {
+			continue
+		}
+		current, ok := desiredBindings[groupBinding.Spec.ProjectID]
+		if !ok || rolePriority(groupBinding.Spec.Role) < rolePriority(current.Spec.Role) {
+			desiredBindings[groupBinding.Spec.ProjectID] = &groupBindings.Items[i]
+		}
+	}
+
+	allMemberMappings := &kubermaticv1.UserProjectBindingList{}
+	if err := p.clientPrivileged.List(ctx, allMemberMappings); err != nil {
+		return err
+	}
+	manualProjects := sets.NewString()
+	derivedMappings := []kubermaticv1.UserProjectBinding{}
+	for _, memberMapping := range allMemberMappings.Items {
+		if !strings.EqualFold(memberMapping.Spec.UserEmail, userEmail) {
+			continue
+		}
+		if _, ok := memberMapping.Labels[kubermaticv1.GroupProjectBindingLabelKey]; ok {
+			derivedMappings = append(derivedMappings, memberMapping)
+			continue
+		}
+		manualProjects.Insert(memberMapping.Spec.ProjectID)
+	}
+
+	syncedProjects := sets.NewString()
+	for _, memberMapping := range derivedMappings {
+		projectID := memberMapping.Spec.ProjectID
+		groupBinding, ok := desiredBindings[projectID]
+		if !ok || manualProjects.Has(projectID) || syncedProjects.Has(projectID) {
+			if err := p.clientPrivileged.Delete(ctx, &memberMapping); err != nil && !kerrors.IsNotFound(err) {
+				return err
+			}
+			continue
+		}
+		syncedProjects.Insert(projectID)
+
+		group := rbac.GenerateActualGroupNameFor(projectID, groupBinding.Spec.Role)
+		if memberMapping.Spec.Group == group && memberMapping.Labels[kubermaticv1.GroupProjectBindingLabelKey] == groupBinding.Name {
+			continue
+		}
+		memberMapping.Spec.Group = group
+		memberMapping.Labels[kubermaticv1.GroupProjectBindingLabelKey] = groupBinding.Name
+		if rbac.ExtractGroupPrefix(group) == rbac.OwnerGroupNamePrefix && !kuberneteshelper.HasFinalizer(&memberMapping, rbac.CleanupFinalizerName) {
+			kuberneteshelper.AddFinalizer(&memberMapping, rbac.CleanupFinalizerName)
+		}
+		if err := p.clientPrivileged.Update(ctx, &memberMapping); err != nil {
+			return err
+		}
+	}
+
+	projectIDs := []string{}
+	for projectID := range desiredBindings {
+		if !manualProjects.Has(projectID) && !syncedProjects.Has(projectID) {
+			projectIDs = append(projectIDs, projectID)
+		}
+	}
+	sort.Strings(projectIDs)
+	for _, projectID := range projectIDs {
+		groupBinding := desiredBindings[projectID]
+		project := &kubermaticv1.Project{}
+		if err := p.clientPrivileged.Get(ctx, ctrlruntimeclient.ObjectKey{Name: projectID}, project); err != nil {
+			if kerrors.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+
+		memberMapping := genBinding(project, userEmail, rbac.GenerateActualGroupNameFor(projectID, groupBinding.Spec.Role))
+		memberMapping.Labels = map[string]string{kubermaticv1.GroupProjectBindingLabelKey: groupBinding.Name}
+		if err := p.clientPrivileged.Create(ctx, memberMapping); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SyncOnChangeUnsecured reconciles the project memberships of the given user like SyncUnsecured, but
+// only if the groups changed since the last synchronization, the group project bindings were changed
+// through this provider in the meantime or the last synchronization is older than groupsResyncPeriod.
+// It is meant to be called on every request, so that the memberships follow the groups of the user.
+//
+// This function is unsafe in a sense that it uses privileged account to manage the memberships
+func (p *GroupProjectBindingProvider) SyncOnChangeUnsecured(userEmail string, groups []string) error {
+	sortedGroups := append([]string{}, groups...)
+	sort.Strings(sortedGroups)
+	syncedKey := fmt.Sprintf("%d/%s", atomic.LoadUint64(&p.generation), strings.Join(sortedGroups, ","))
+
+	cacheKey := strings.ToLower(userEmail)
+	if synced, ok := p.syncedGroups.Get(cacheKey); ok && synced.(string) == syncedKey {
+		return nil
+	}
+	if err := p.SyncUnsecured(userEmail, groups); err != nil {
+		return err
+	}
+	p.syncedGroups.Add(cacheKey, syncedKey, groupsResyncPeriod)
+	return nil
+}
+
+// rolePriority orders the roles from the most privileged one, the built-in roles
+// come first and take precedence over the custom project roles
+func rolePriority(role string) int {
+	for i, groupPrefix := range rbac.AllGroupsPrefixes {
+		if groupPrefix == role {
+			return i
+		}
+	}
+	return len(rbac.AllGroupsPrefixes)
+}
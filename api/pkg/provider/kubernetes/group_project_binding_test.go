@@ -0,0 +1,298 @@
+/*
+Copyright 2020 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes_test
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/go-test/deep"
+
+	kubermaticv1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
+	"github.com/kubermatic/kubermatic/api/pkg/provider"
+	"github.com/kubermatic/kubermatic/api/pkg/provider/kubernetes"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestSyncGroupProjectBindings(t *testing.T) {
+	paymentsProject := genProject("payments", kubermaticv1.ProjectActive, defaultCreationTimestamp())
+	billingProject := genProject("billing", kubermaticv1.ProjectActive, defaultCreationTimestamp())
+
+	testcases := []struct {
+		name             string
+		userEmail        string
+		groups           []string
+		existingObjects  []runtime.Object
+		expectedBindings []string
+	}{
+		{
+			name:      "scenario 1: members of a group become members of the project",
+			userEmail: "bob@acme.com",
+			groups:    []string{"team-payments", "all"},
+			existingObjects: []runtime.Object{
+				paymentsProject,
+				billingProject,
+				genGroupProjectBinding("payments-editors", "team-payments", paymentsProject.Name, "editors"),
+				genGroupProjectBinding("billing-viewers", "team-billing", billingProject.Name, "viewers"),
+			},
+			expectedBindings: []string{"bob@acme.com/payments-ID/editors-payments-ID/payments-editors"},
+		},
+		{
+			name:      "scenario 2: bindings of the groups the user no longer belongs to are removed, manual bindings are kept",
+			userEmail: "bob@acme.com",
+			groups:    []string{},
+			existingObjects: []runtime.Object{
+				paymentsProject,
+				billingProject,
+				genGroupProjectBinding("payments-editors", "team-payments", paymentsProject.Name, "editors"),
+				genDerivedBinding("abcd", paymentsProject.Name, "bob@acme.com", "editors", "payments-editors"),
+				createBinding("efgh", billingProject.Name, "bob@acme.com", "viewers"),
+				genDerivedBinding("ijkl", paymentsProject.Name, "alice@acme.com", "editors", "payments-editors"),
+			},
+			expectedBindings: []string{
+				"alice@acme.com/payments-ID/editors-payments-ID/payments-editors",
+				"bob@acme.com/billing-ID/viewers-billing-ID/",
+			},
+		},
+		{
+			name:      "scenario 3: the most privileged role wins when several groups grant access to the same project",
+			userEmail: "bob@acme.com",
+			groups:    []string{"team-payments", "payments-admins", "auditors"},
+			existingObjects: []runtime.Object{
+				paymentsProject,
+				&kubermaticv1.ProjectRole{ObjectMeta: metav1.ObjectMeta{Name: "auditor"}},
+				genGroupProjectBinding("payments-auditors", "auditors", paymentsProject.Name, "auditor"),
+				genGroupProjectBinding("payments-editors", "team-payments", paymentsProject.Name, "editors"),
+				genGroupProjectBinding("payments-owners", "payments-admins", paymentsProject.Name, "owners"),
+				genDerivedBinding("abcd", paymentsProject.Name, "bob@acme.com", "editors", "payments-editors"),
+			},
+			expectedBindings: []string{"bob@acme.com/payments-ID/owners-payments-ID/payments-owners"},
+		},
+		{
+			name:      "scenario 4: manually created bindings take precedence",
+			userEmail: "bob@acme.com",
+			groups:    []string{"team-payments"},
+			existingObjects: []runtime.Object{
+				paymentsProject,
+				genGroupProjectBinding("payments-editors", "team-payments", paymentsProject.Name, "editors"),
+				createBinding("abcd", paymentsProject.Name, "bob@acme.com", "viewers"),
+				genDerivedBinding("efgh", paymentsProject.Name, "bob@acme.com", "editors", "payments-editors"),
+			},
+			expectedBindings: []string{"bob@acme.com/payments-ID/viewers-payments-ID/"},
+		},
+		{
+			name:      "scenario 5: projects that don't exist are skipped",
+			userEmail: "bob@acme.com",
+			groups:    []string{"team-payments"},
+			existingObjects: []runtime.Object{
+				genGroupProjectBinding("payments-editors", "team-payments", paymentsProject.Name, "editors"),
+			},
+			expectedBindings: []string{},
+		},
+		{
+			name:      "scenario 6: service accounts are not synchronized",
+			userEmail: "serviceaccount-1@sa.kubermatic.io",
+			groups:    []string{"team-payments"},
+			existingObjects: []runtime.Object{
+				paymentsProject,
+				genGroupProjectBinding("payments-editors", "team-payments", paymentsProject.Name, "editors"),
+			},
+			expectedBindings: []string{},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			fakeClient := fakectrlruntimeclient.NewFakeClientWithScheme(scheme.Scheme, tc.existingObjects...)
+			target := kubernetes.NewGroupProjectBindingProvider(fakeClient, kubernetes.IsServiceAccount)
+
+			if err := target.SyncUnsecured(tc.userEmail, tc.groups); err != nil {
+				t.Fatal(err)
+			}
+
+			bindings := &kubermaticv1.UserProjectBindingList{}
+			if err := fakeClient.List(context.Background(), bindings); err != nil {
+				t.Fatal(err)
+			}
+			result := []string{}
+			for _, binding := range bindings.Items {
+				result = append(result, fmt.Sprintf("%s/%s/%s/%s", binding.Spec.UserEmail, binding.Spec.ProjectID, binding.Spec.Group, binding.Labels[kubermaticv1.GroupProjectBindingLabelKey]))
+			}
+			sort.Strings(result)
+			if diff := deep.Equal(result, tc.expectedBindings); diff != nil {
+				t.Fatalf("unexpected bindings, diff = %v", diff)
+			}
+		})
+	}
+}
+
+func TestCreateGroupProjectBinding(t *testing.T) {
+	existingProject := genDefaultProject()
+
+	testcases := []struct {
+		name        string
+		userInfo    *provider.UserInfo
+		binding     *kubermaticv1.GroupProjectBinding
+		expectError func(error) bool
+	}{
+		{
+			name:     "scenario 1: an admin can bind a group to a project",
+			userInfo: &provider.UserInfo{Email: "john@acme.com", IsAdmin: true},
+			binding:  genGroupProjectBinding("", "team-payments", existingProject.Name, "editors"),
+		},
+		{
+			name:        "scenario 2: a regular user cannot bind a group to a project",
+			userInfo:    &provider.UserInfo{Email: "bob@acme.com"},
+			binding:     genGroupProjectBinding("", "team-payments", existingProject.Name, "editors"),
+			expectError: kerrors.IsForbidden,
+		},
+		{
+			name:        "scenario 3: the project must exist",
+			userInfo:    &provider.UserInfo{Email: "john@acme.com", IsAdmin: true},
+			binding:     genGroupProjectBinding("", "team-payments", "otherproject", "editors"),
+			expectError: kerrors.IsBadRequest,
+		},
+		{
+			name:        "scenario 4: the role must exist",
+			userInfo:    &provider.UserInfo{Email: "john@acme.com", IsAdmin: true},
+			binding:     genGroupProjectBinding("", "team-payments", existingProject.Name, "billingviewer"),
+			expectError: kerrors.IsBadRequest,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			fakeClient := fakectrlruntimeclient.NewFakeClientWithScheme(scheme.Scheme, existingProject)
+			target := kubernetes.NewGroupProjectBindingProvider(fakeClient, kubernetes.IsServiceAccount)
+
+			result, err := target.Create(tc.userInfo, tc.binding)
+			if tc.expectError != nil {
+				if !tc.expectError(err) {
+					t.Fatalf("unexpected error %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Name == "" {
+				t.Fatal("expected the name of the binding to be generated")
+			}
+			if len(result.OwnerReferences) != 1 || result.OwnerReferences[0].Name != existingProject.Name {
+				t.Fatalf("expected the binding to be owned by the project, got %v", result.OwnerReferences)
+			}
+		})
+	}
+}
+
+func TestDeleteGroupProjectBinding(t *testing.T) {
+	existingProject := genDefaultProject()
+	fakeClient := fakectrlruntimeclient.NewFakeClientWithScheme(scheme.Scheme,
+		existingProject,
+		genGroupProjectBinding("payments-editors", "team-payments", existingProject.Name, "editors"),
+		genDerivedBinding("abcd", existingProject.Name, "bob@acme.com", "editors", "payments-editors"),
+		createBinding("efgh", existingProject.Name, "alice@acme.com", "editors"),
+	)
+	target := kubernetes.NewGroupProjectBindingProvider(fakeClient, kubernetes.IsServiceAccount)
+
+	if err := target.Delete(&provider.UserInfo{Email: "john@acme.com", IsAdmin: true}, "payments-editors"); err != nil {
+		t.Fatal(err)
+	}
+
+	bindings := &kubermaticv1.UserProjectBindingList{}
+	if err := fakeClient.List(context.Background(), bindings); err != nil {
+		t.Fatal(err)
+	}
+	if len(bindings.Items) != 1 || bindings.Items[0].Name != "efgh" {
+		t.Fatalf("expected only the manually created binding to be left, got %v", bindings.Items)
+	}
+}
+
+func TestSyncGroupProjectBindingsOnChange(t *testing.T) {
+	existingProject := genDefaultProject()
+	fakeClient := fakectrlruntimeclient.NewFakeClientWithScheme(scheme.Scheme,
+		existingProject,
+		genGroupProjectBinding("payments-editors", "team-payments", existingProject.Name, "editors"),
+	)
+	target := kubernetes.NewGroupProjectBindingProvider(fakeClient, kubernetes.IsServiceAccount)
+
+	bindingsCount := func() int {
+		bindings := &kubermaticv1.UserProjectBindingList{}
+		if err := fakeClient.List(context.Background(), bindings); err != nil {
+			t.Fatal(err)
+		}
+		return len(bindings.Items)
+	}
+
+	if err := target.SyncOnChangeUnsecured("bob@acme.com", []string{"team-payments"}); err != nil {
+		t.Fatal(err)
+	}
+	if count := bindingsCount(); count != 1 {
+		t.Fatalf("expected the membership to be derived from the group, got %d bindings", count)
+	}
+
+	// the groups didn't change, thus the memberships are not synchronized again
+	if err := fakeClient.DeleteAllOf(context.Background(), &kubermaticv1.UserProjectBinding{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := target.SyncOnChangeUnsecured("bob@acme.com", []string{"team-payments"}); err != nil {
+		t.Fatal(err)
+	}
+	if count := bindingsCount(); count != 0 {
+		t.Fatalf("expected the memberships not to be synchronized, got %d bindings", count)
+	}
+
+	// the user joined another group, thus the memberships are synchronized
+	if err := target.SyncOnChangeUnsecured("bob@acme.com", []string{"team-payments", "team-billing"}); err != nil {
+		t.Fatal(err)
+	}
+	if count := bindingsCount(); count != 1 {
+		t.Fatalf("expected the memberships to be synchronized, got %d bindings", count)
+	}
+
+	// the user left all groups, thus the derived memberships are removed
+	if err := target.SyncOnChangeUnsecured("bob@acme.com", nil); err != nil {
+		t.Fatal(err)
+	}
+	if count := bindingsCount(); count != 0 {
+		t.Fatalf("expected the derived membership to be removed, got %d bindings", count)
+	}
+}
+
+func genGroupProjectBinding(name, group, projectID, role string) *kubermaticv1.GroupProjectBinding {
+	return &kubermaticv1.GroupProjectBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: kubermaticv1.GroupProjectBindingSpec{
+			Group:     group,
+			ProjectID: projectID,
+			Role:      role,
+		},
+	}
+}
+
+func genDerivedBinding(name, projectID, email, group, groupProjectBindingName string) *kubermaticv1.UserProjectBinding {
+	binding := createBinding(name, projectID, email, group)
+	binding.Labels = map[string]string{kubermaticv1.GroupProjectBindingLabelKey: groupProjectBindingName}
+	return binding
+}
@@ -62,6 +62,9 @@ func (p *ProjectMemberProvider) Create(userInfo *provider.UserInfo, project *kub
 	if p.isServiceAccountFunc(memberEmail) {
 		return nil, kerrors.NewBadRequest(fmt.Sprintf("cannot add the given member %s to the project %s because the email indicates a service account", memberEmail, project.Spec.Name))
 	}
+	if err := validateGroup(p.clientPrivileged, project.Name, group); err != nil {
+		return nil, err
+	}
 
 	binding := genBinding(project, memberEmail, group)
 
@@ -146,6 +149,9 @@ func (p *ProjectMemberProvider) Delete(userInfo *provider.UserInfo, bindingName
 
 // Update updates the given binding
 func (p *ProjectMemberProvider) Update(userInfo *provider.UserInfo, binding *kubermaticapiv1.UserProjectBinding) (*kubermaticapiv1.UserProjectBinding, error) {
+	if err := validateGroup(p.clientPrivileged, binding.Spec.ProjectID, binding.Spec.Group); err != nil {
+		return nil, err
+	}
 	if rbac.ExtractGroupPrefix(binding.Spec.Group) == rbac.OwnerGroupNamePrefix && !kuberneteshelper.HasFinalizer(binding, rbac.CleanupFinalizerName) {
 		kuberneteshelper.AddFinalizer(binding, rbac.CleanupFinalizerName)
 	}
@@ -176,6 +182,31 @@ func (p *ProjectMemberProvider) MapUserToGroup(userEmail string, projectID strin
 	return "", kerrors.NewForbidden(schema.GroupResource{}, projectID, fmt.Errorf("%q doesn't belong to the given project = %s", userEmail, projectID))
 }
 
+// MapGroupToProjectRole returns the custom ProjectRole of the given group of the given project,
+// nil is returned for the built-in groups and for the roles that don't exist or are invalid
+// This function is unsafe in a sense that it uses privileged account to get the role
+func (p *ProjectMemberProvider) MapGroupToProjectRole(projectID string, group string) (*kubermaticapiv1.ProjectRole, error) {
+	groupPrefix := rbac.ExtractGroupPrefixForProject(projectID, group)
+	for _, existingGroupPrefix := range rbac.AllGroupsPrefixes {
+		if existingGroupPrefix == groupPrefix {
+			return nil, nil
+		}
+	}
+
+	role := &kubermaticapiv1.ProjectRole{}
+	if err := p.clientPrivileged.Get(context.Background(), ctrlruntimeclient.ObjectKey{Name: groupPrefix}, role); err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	// the RBAC controllers skip invalid roles, thus their members have no permissions
+	if err := rbac.ValidateProjectRole(role); err != nil {
+		return nil, nil
+	}
+	return role, nil
+}
+
 // MappingsFor returns the list of projects (bindings) for the given user
 // This function is unsafe in a sense that it uses privileged account to list all members in the system
 func (p *ProjectMemberProvider) MappingsFor(userEmail string) ([]*kubermaticapiv1.UserProjectBinding, error) {
@@ -200,6 +231,9 @@ func (p *ProjectMemberProvider) CreateUnsecured(project *kubermaticapiv1.Project
 	if p.isServiceAccountFunc(memberEmail) {
 		return nil, kerrors.NewBadRequest(fmt.Sprintf("cannot add the given member %s to the project %s because the email indicates a service account", memberEmail, project.Spec.Name))
 	}
+	if err := validateGroup(p.clientPrivileged, project.Name, group); err != nil {
+		return nil, err
+	}
 
 	binding := genBinding(project, memberEmail, group)
 
@@ -220,6 +254,9 @@ func (p *ProjectMemberProvider) DeleteUnsecured(bindingName string) error {
 // UpdateUnsecured updates the given binding
 // This function is unsafe in a sense that it uses privileged account to update the resource
 func (p *ProjectMemberProvider) UpdateUnsecured(binding *kubermaticapiv1.UserProjectBinding) (*kubermaticapiv1.UserProjectBinding, error) {
+	if err := validateGroup(p.clientPrivileged, binding.Spec.ProjectID, binding.Spec.Group); err != nil {
+		return nil, err
+	}
 	if rbac.ExtractGroupPrefix(binding.Spec.Group) == rbac.OwnerGroupNamePrefix && !kuberneteshelper.HasFinalizer(binding, rbac.CleanupFinalizerName) {
 		kuberneteshelper.AddFinalizer(binding, rbac.CleanupFinalizerName)
 	}
@@ -230,6 +267,35 @@ func (p *ProjectMemberProvider) UpdateUnsecured(binding *kubermaticapiv1.UserPro
 	return binding, nil
 }
 
+// validateGroup checks that the given group belongs to the project and that the group is either
+// one of the built-in groups (owners, editors and viewers) or a custom ProjectRole
+func validateGroup(client ctrlruntimeclient.Client, projectID, group string) error {
+	groupPrefix := rbac.ExtractGroupPrefixForProject(projectID, group)
+	if group != rbac.GenerateActualGroupNameFor(projectID, groupPrefix) {
+		return kerrors.NewBadRequest(fmt.Sprintf("invalid group name %s", group))
+	}
+	for _, existingGroupPrefix := range rbac.AllGroupsPrefixes {
+		if existingGroupPrefix == groupPrefix {
+			return nil
+		}
+	}
+
+	if err := rbac.ValidateProjectRoleName(groupPrefix); err == nil {
+		role := &kubermaticapiv1.ProjectRole{}
+		err := client.Get(context.Background(), ctrlruntimeclient.ObjectKey{Name: groupPrefix}, role)
+		if err == nil {
+			if err := rbac.ValidateProjectRole(role); err != nil {
+				return kerrors.NewBadRequest(err.Error())
+			}
+			return nil
+		}
+		if !kerrors.IsNotFound(err) {
+			return err
+		}
+	}
+	return kerrors.NewBadRequest(fmt.Sprintf("invalid group name %s", groupPrefix))
+}
+
 func genBinding(project *kubermaticapiv1.Project, memberEmail, group string) *kubermaticapiv1.UserProjectBinding {
 	finalizers := []string{}
 	if rbac.ExtractGroupPrefix(group) == rbac.OwnerGroupNamePrefix {
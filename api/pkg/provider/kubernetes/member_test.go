@@ -26,6 +26,8 @@ import (
 	"github.com/kubermatic/kubermatic/api/pkg/provider"
 	"github.com/kubermatic/kubermatic/api/pkg/provider/kubernetes"
 
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes/scheme"
 	restclient "k8s.io/client-go/rest"
@@ -66,6 +68,85 @@ func TestCreateBinding(t *testing.T) {
 	}
 }
 
+func TestCreateBindingForProjectRole(t *testing.T) {
+	existingProject := genDefaultProject()
+	existingRole := &kubermaticv1.ProjectRole{
+		ObjectMeta: metav1.ObjectMeta{Name: "clusteroperator"},
+		Spec: kubermaticv1.ProjectRoleSpec{
+			Rules: []kubermaticv1.ProjectRoleRule{{Kinds: []string{kubermaticv1.ClusterKindName}, Verbs: []string{"get", "update"}}},
+		},
+	}
+	userClusterRole := &kubermaticv1.ProjectRole{
+		ObjectMeta: metav1.ObjectMeta{Name: "nodeoperator"},
+		Spec: kubermaticv1.ProjectRoleSpec{
+			Rules: []kubermaticv1.ProjectRoleRule{{Kinds: []string{"MachineDeployment"}, Verbs: []string{"create"}}},
+		},
+	}
+	nodeDeploymentRole := &kubermaticv1.ProjectRole{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-operator"},
+		Spec: kubermaticv1.ProjectRoleSpec{
+			Rules: []kubermaticv1.ProjectRoleRule{
+				{Kinds: []string{kubermaticv1.ClusterKindName}, Verbs: []string{"get"}},
+				{Kinds: []string{"NodeDeployment"}, Verbs: []string{"get", "patch"}},
+			},
+		},
+	}
+
+	testcases := []struct {
+		name        string
+		groupName   string
+		expectError bool
+	}{
+		{
+			name:      "scenario 1: a member can be added to a custom project role",
+			groupName: fmt.Sprintf("clusteroperator-%s", existingProject.Name),
+		},
+		{
+			name:        "scenario 2: a member cannot be added to a project role that doesn't exist",
+			groupName:   fmt.Sprintf("billingviewer-%s", existingProject.Name),
+			expectError: true,
+		},
+		{
+			name:        "scenario 3: a member cannot be added to a group of a different project",
+			groupName:   "clusteroperator-otherproject",
+			expectError: true,
+		},
+		{
+			name:        "scenario 4: a member cannot be added to a project role that grants verbs inside of the user clusters",
+			groupName:   fmt.Sprintf("nodeoperator-%s", existingProject.Name),
+			expectError: true,
+		},
+		{
+			name:      "scenario 5: a member can be added to a project role with a dash in its name that grants verbs for node deployments",
+			groupName: fmt.Sprintf("cluster-operator-%s", existingProject.Name),
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			fakeClient := fakectrlruntimeclient.NewFakeClientWithScheme(scheme.Scheme, existingRole, userClusterRole, nodeDeploymentRole)
+			fakeImpersonationClient := func(impCfg restclient.ImpersonationConfig) (ctrlruntimeclient.Client, error) {
+				return fakeClient, nil
+			}
+			target := kubernetes.NewProjectMemberProvider(fakeImpersonationClient, fakeClient, kubernetes.IsServiceAccount)
+
+			result, err := target.Create(&provider.UserInfo{Email: "john@acme.com", Group: fmt.Sprintf("owners-%s", existingProject.Name)}, existingProject, "bob@acme.com", tc.groupName)
+			if tc.expectError {
+				if !kerrors.IsBadRequest(err) {
+					t.Fatalf("expected a bad request error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Spec.Group != tc.groupName {
+				t.Fatalf("unexpected result returned, expected %s, got %s", tc.groupName, result.Spec.Group)
+			}
+		})
+	}
+}
+
 func TestListBinding(t *testing.T) {
 	// test data
 	testcases := []struct {
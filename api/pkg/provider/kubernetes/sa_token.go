@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	kubermaticv1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
 	"github.com/kubermatic/kubermatic/api/pkg/provider"
@@ -35,6 +36,9 @@ import (
 const (
 	labelTokenName = "token"
 	tokenPrefix    = "sa-token-"
+
+	// tokenRevocationListName is the name of the ConfigMap that holds revoked token IDs along with their expiry
+	tokenRevocationListName = "sa-token-revocations"
 )
 
 // NewServiceAccountProvider returns a service account provider
@@ -366,6 +370,68 @@ func (p *ServiceAccountTokenProvider) DeleteUnsecured(name string) error {
 	return p.kubernetesClientPrivileged.Delete(context.Background(), secret)
 }
 
+// RevokeUnsecured puts the token on the revocation list until the given expiry,
+// entries of tokens that have already expired are pruned from the list
+//
+// Note that this function:
+// is unsafe in a sense that it uses privileged account to update the resource
+func (p *ServiceAccountTokenProvider) RevokeUnsecured(tokenID string, expiry time.Time) error {
+	if len(tokenID) == 0 {
+		return kerrors.NewBadRequest("token ID cannot be empty")
+	}
+	tokenID = removeTokenPrefix(tokenID)
+
+	revocationList := &v1.ConfigMap{}
+	err := p.kubernetesClientPrivileged.Get(context.Background(), ctrlruntimeclient.ObjectKey{Namespace: resources.KubermaticNamespace, Name: tokenRevocationListName}, revocationList)
+	if err != nil && !kerrors.IsNotFound(err) {
+		return err
+	}
+	exists := err == nil
+	if !exists {
+		revocationList = &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      tokenRevocationListName,
+				Namespace: resources.KubermaticNamespace,
+			},
+		}
+	}
+
+	now := time.Now()
+	data := map[string]string{tokenID: expiry.UTC().Format(time.RFC3339)}
+	for id, rawExpiry := range revocationList.Data {
+		if id == tokenID {
+			continue
+		}
+		if entryExpiry, err := time.Parse(time.RFC3339, rawExpiry); err == nil && entryExpiry.Before(now) {
+			continue
+		}
+		data[id] = rawExpiry
+	}
+	revocationList.Data = data
+
+	if exists {
+		return p.kubernetesClientPrivileged.Update(context.Background(), revocationList)
+	}
+	return p.kubernetesClientPrivileged.Create(context.Background(), revocationList)
+}
+
+// IsRevokedUnsecured checks if the token is on the revocation list
+//
+// Note that this function:
+// is unsafe in a sense that it uses privileged account to get the resource
+// gets resources from the cache
+func (p *ServiceAccountTokenProvider) IsRevokedUnsecured(tokenID string) (bool, error) {
+	revocationList := &v1.ConfigMap{}
+	if err := p.kubernetesClientPrivileged.Get(context.Background(), ctrlruntimeclient.ObjectKey{Namespace: resources.KubermaticNamespace, Name: tokenRevocationListName}, revocationList); err != nil {
+		if kerrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	_, revoked := revocationList.Data[removeTokenPrefix(tokenID)]
+	return revoked, nil
+}
+
 // removeTokenPrefix removes "sa-token-" from a token's ID
 // for example given "sa-token-gmtzqz692d" it returns "gmtzqz692d"
 func removeTokenPrefix(id string) string {
@@ -20,6 +20,7 @@ import (
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	kubermaticv1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
 	"github.com/kubermatic/kubermatic/api/pkg/handler/test"
@@ -30,6 +31,7 @@ import (
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes/scheme"
 	restclient "k8s.io/client-go/rest"
@@ -397,6 +399,77 @@ func TestDeleteToken(t *testing.T) {
 	}
 }
 
+func TestRevokeToken(t *testing.T) {
+	// test data
+	testcases := []struct {
+		name            string
+		existingRevoked map[string]string
+		tokenToRevoke   string
+		expectedRevoked []string
+		expectedPruned  []string
+	}{
+		{
+			name:            "scenario 1, revoke a token when the revocation list doesn't exist yet",
+			tokenToRevoke:   "sa-token-1",
+			expectedRevoked: []string{"1"},
+		},
+		{
+			name: "scenario 2, revoke a token and prune already expired entries",
+			existingRevoked: map[string]string{
+				"2": time.Now().Add(time.Hour).UTC().Format(time.RFC3339),
+				"3": time.Now().Add(-time.Hour).UTC().Format(time.RFC3339),
+			},
+			tokenToRevoke:   "1",
+			expectedRevoked: []string{"1", "2"},
+			expectedPruned:  []string{"3"},
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			kubeObjects := []runtime.Object{}
+			if tc.existingRevoked != nil {
+				kubeObjects = append(kubeObjects, &v1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Name: "sa-token-revocations", Namespace: "kubermatic"},
+					Data:       tc.existingRevoked,
+				})
+			}
+
+			fakeClient := fakectrlruntimeclient.NewFakeClientWithScheme(scheme.Scheme, kubeObjects...)
+			fakeImpersonationClient := func(impCfg restclient.ImpersonationConfig) (ctrlruntimeclient.Client, error) {
+				return fakeClient, nil
+			}
+			// act
+			target, err := kubernetes.NewServiceAccountTokenProvider(fakeImpersonationClient, fakeClient)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := target.RevokeUnsecured(tc.tokenToRevoke, time.Now().Add(time.Hour)); err != nil {
+				t.Fatal(err)
+			}
+
+			// validate
+			for _, tokenID := range tc.expectedRevoked {
+				revoked, err := target.IsRevokedUnsecured(tokenID)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !revoked {
+					t.Fatalf("expected token %s to be revoked", tokenID)
+				}
+			}
+			for _, tokenID := range tc.expectedPruned {
+				revoked, err := target.IsRevokedUnsecured(tokenID)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if revoked {
+					t.Fatalf("expected expired token %s to be pruned from the revocation list", tokenID)
+				}
+			}
+		})
+	}
+}
+
 func rmTokenPrefix(token *v1.Secret) *v1.Secret {
 	token.Name = strings.TrimPrefix(token.Name, "sa-token-")
 	return token
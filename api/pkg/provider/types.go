@@ -20,6 +20,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	kubermaticv1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
 	providerconfig "github.com/kubermatic/machine-controller/pkg/providerconfig/types"
@@ -30,6 +31,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/kubernetes"
+	restclient "k8s.io/client-go/rest"
 	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
 	"k8s.io/client-go/tools/record"
 	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
@@ -154,6 +156,9 @@ type ClusterProvider interface {
 	// ListAll gets all clusters for the seed
 	ListAll() (*kubermaticv1.ClusterList, error)
 
+	// WatchAll watches all clusters for the seed
+	WatchAll() (watch.Interface, error)
+
 	// Get returns the given cluster, it uses the projectInternalName to determine the group the user belongs to
 	Get(userInfo *UserInfo, clusterName string, options *ClusterGetOptions) (*kubermaticv1.Cluster, error)
 
@@ -185,6 +190,18 @@ type ClusterProvider interface {
 	// Note that the client doesn't use admin account instead it authn/authz as userInfo(email, group)
 	GetClientForCustomerCluster(*UserInfo, *kubermaticv1.Cluster) (ctrlruntimeclient.Client, error)
 
+	// GetAdminClientConfigForCustomerCluster returns the client config for the given cluster, it is needed
+	// wherever a client is not sufficient, e.g. to watch resources
+	//
+	// Note that the client config you will get has admin privileges
+	GetAdminClientConfigForCustomerCluster(*kubermaticv1.Cluster) (*restclient.Config, error)
+
+	// GetClientConfigForCustomerCluster returns the client config for the given cluster, it is needed
+	// wherever a client is not sufficient, e.g. to watch resources
+	//
+	// Note that the client config doesn't use admin account instead it authn/authz as userInfo(email, group)
+	GetClientConfigForCustomerCluster(*UserInfo, *kubermaticv1.Cluster) (*restclient.Config, error)
+
 	// GetTokenForCustomerCluster returns a token for the given cluster with permissions granted to group that
 	// user belongs to.
 	GetTokenForCustomerCluster(userInfo *UserInfo, cluster *kubermaticv1.Cluster) (string, error)
@@ -327,6 +344,8 @@ type UserInfo struct {
 	Email   string
 	Group   string
 	IsAdmin bool
+	// ProjectRole is the custom role of the group, it is nil for the built-in groups
+	ProjectRole *kubermaticv1.ProjectRole
 }
 
 // ProjectMemberListOptions allows to set filters that will be applied to filter the result.
@@ -382,6 +401,11 @@ type ProjectMemberMapper interface {
 	// MappingsFor returns the list of projects (bindings) for the given user
 	// This function is unsafe in a sense that it uses privileged account to list all members in the system
 	MappingsFor(userEmail string) ([]*kubermaticv1.UserProjectBinding, error)
+
+	// MapGroupToProjectRole returns the custom ProjectRole of the given group of the given project,
+	// nil is returned for the built-in groups and for the roles that don't exist or are invalid
+	// This function is unsafe in a sense that it uses privileged account to get the role
+	MapGroupToProjectRole(projectID string, group string) (*kubermaticv1.ProjectRole, error)
 }
 
 // ClusterCloudProviderName returns the provider name for the given CloudSpec.
@@ -613,6 +637,19 @@ type PrivilegedServiceAccountTokenProvider interface {
 	// Note that this function:
 	// is unsafe in a sense that it uses privileged account to delete the resource
 	DeleteUnsecured(name string) error
+
+	// RevokeUnsecured puts the token on the revocation list until the given expiry
+	//
+	// Note that this function:
+	// is unsafe in a sense that it uses privileged account to update the resource
+	RevokeUnsecured(tokenID string, expiry time.Time) error
+
+	// IsRevokedUnsecured checks if the token is on the revocation list
+	//
+	// Note that this function:
+	// is unsafe in a sense that it uses privileged account to get the resource
+	// gets resources from the cache
+	IsRevokedUnsecured(tokenID string) (bool, error)
 }
 
 // EventRecorderProvider allows to record events for objects that can be read using K8S API.
@@ -674,6 +711,38 @@ type PrivilegedAddonProvider interface {
 	DeleteUnsecured(cluster *kubermaticv1.Cluster, addonName string) error
 }
 
+// EtcdRestoreProvider declares the set of methods for interacting with etcd restores
+type EtcdRestoreProvider interface {
+	// New creates a new restore of the given backup for the cluster
+	New(userInfo *UserInfo, cluster *kubermaticv1.Cluster, backupName string) (*kubermaticv1.EtcdRestore, error)
+
+	// List gets all restores that belong to the given cluster
+	List(userInfo *UserInfo, cluster *kubermaticv1.Cluster) ([]*kubermaticv1.EtcdRestore, error)
+
+	// Get returns the given restore
+	Get(userInfo *UserInfo, cluster *kubermaticv1.Cluster, restoreName string) (*kubermaticv1.EtcdRestore, error)
+}
+
+type PrivilegedEtcdRestoreProvider interface {
+	// NewUnsecured creates a new restore of the given backup for the cluster
+	//
+	// Note that this function:
+	// is unsafe in a sense that it uses privileged account to create the resource
+	NewUnsecured(cluster *kubermaticv1.Cluster, backupName string) (*kubermaticv1.EtcdRestore, error)
+
+	// ListUnsecured gets all restores that belong to the given cluster
+	//
+	// Note that this function:
+	// is unsafe in a sense that it uses privileged account to get the resources
+	ListUnsecured(cluster *kubermaticv1.Cluster) ([]*kubermaticv1.EtcdRestore, error)
+
+	// GetUnsecured returns the given restore
+	//
+	// Note that this function:
+	// is unsafe in a sense that it uses privileged account to get the resource
+	GetUnsecured(cluster *kubermaticv1.Cluster, restoreName string) (*kubermaticv1.EtcdRestore, error)
+}
+
 type AddonConfigProvider interface {
 	Get(addonName string) (*kubermaticv1.AddonConfig, error)
 	List() (*kubermaticv1.AddonConfigList, error)
@@ -707,3 +776,42 @@ type AdmissionPluginsProvider interface {
 	Update(userInfo *UserInfo, admissionPlugin *kubermaticv1.AdmissionPlugin) (*kubermaticv1.AdmissionPlugin, error)
 	ListPluginNamesFromVersion(fromVersion string) ([]string, error)
 }
+
+// ClusterTemplateProvider declares the set of methods for interacting with cluster templates
+type ClusterTemplateProvider interface {
+	// New creates a cluster template, only admins can create global templates
+	New(userInfo *UserInfo, template *kubermaticv1.ClusterTemplate) (*kubermaticv1.ClusterTemplate, error)
+	// List returns the templates which can be used in the given project
+	List(userInfo *UserInfo, projectID string) ([]kubermaticv1.ClusterTemplate, error)
+	// Get returns the template if it can be used in the given project
+	Get(userInfo *UserInfo, projectID, name string) (*kubermaticv1.ClusterTemplate, error)
+	// Delete deletes the template, templates can only be deleted by their owner, project templates
+	// also by the editors of the project and global templates by admins
+	Delete(userInfo *UserInfo, projectID, name string) error
+}
+
+// GroupProjectBindingProvider declares the set of methods for interacting with group project bindings
+type GroupProjectBindingProvider interface {
+	// List returns all group project bindings, only admins are allowed to list them
+	List(userInfo *UserInfo) ([]kubermaticv1.GroupProjectBinding, error)
+	// Create creates a group project binding, only admins are allowed to create them
+	Create(userInfo *UserInfo, binding *kubermaticv1.GroupProjectBinding) (*kubermaticv1.GroupProjectBinding, error)
+	// Delete deletes the group project binding together with the project memberships derived from it,
+	// only admins are allowed to delete them
+	Delete(userInfo *UserInfo, name string) error
+
+	// SyncUnsecured reconciles the project memberships of the given user with the groups the user belongs to
+	// This function is unsafe in a sense that it uses privileged account to manage the memberships
+	SyncUnsecured(userEmail string, groups []string) error
+	// SyncOnChangeUnsecured reconciles the project memberships like SyncUnsecured, but skips the
+	// reconciliation if the groups of the user didn't change since it was recently synchronized
+	// This function is unsafe in a sense that it uses privileged account to manage the memberships
+	SyncOnChangeUnsecured(userEmail string, groups []string) error
+}
+
+// ProjectClusterLister lists the clusters of a project in all seeds from a cache
+type ProjectClusterLister interface {
+	// List returns the clusters of the given project in all seeds. The seeds that can't be reached are
+	// returned by seed name with the reason, their clusters are left out or might be outdated
+	List(projectID string) ([]*kubermaticv1.Cluster, map[string]error, error)
+}
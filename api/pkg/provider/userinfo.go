@@ -37,14 +37,19 @@ func UserInfoGetterFactory(userProjectMapper ProjectMemberMapper) (UserInfoGette
 		}
 
 		var group string
+		var projectRole *kubermaticapiv1.ProjectRole
 		if projectID != "" {
 			var err error
 			group, err = userProjectMapper.MapUserToGroup(user.Spec.Email, projectID)
 			if err != nil {
 				return nil, err
 			}
+			projectRole, err = userProjectMapper.MapGroupToProjectRole(projectID, group)
+			if err != nil {
+				return nil, err
+			}
 		}
 
-		return &UserInfo{Email: user.Spec.Email, Group: group, IsAdmin: user.Spec.IsAdmin}, nil
+		return &UserInfo{Email: user.Spec.Email, Group: group, IsAdmin: user.Spec.IsAdmin, ProjectRole: projectRole}, nil
 	}, nil
 }
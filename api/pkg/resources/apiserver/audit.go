@@ -0,0 +1,147 @@
+/*
+Copyright 2020 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiserver
+
+import (
+	"fmt"
+	"strings"
+
+	kubermaticv1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
+	"github.com/kubermatic/kubermatic/api/pkg/resources"
+	"github.com/kubermatic/kubermatic/api/pkg/resources/reconciling"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+const auditWebhookName = "audit-webhook"
+
+var auditPolicies = map[kubermaticv1.AuditPolicyPreset]string{
+	kubermaticv1.AuditPolicyPresetMetadata: `apiVersion: audit.k8s.io/v1
+kind: Policy
+rules:
+- level: Metadata
+`,
+	kubermaticv1.AuditPolicyPresetRecommended: `apiVersion: audit.k8s.io/v1
+kind: Policy
+omitStages:
+- RequestReceived
+rules:
+- level: Metadata
+  resources:
+  - group: ""
+    resources:
+    - secrets
+    - configmaps
+  - group: authentication.k8s.io
+    resources:
+    - tokenreviews
+- level: Request
+  verbs:
+  - create
+  - update
+  - patch
+  - delete
+  - deletecollection
+- level: Metadata
+`,
+	kubermaticv1.AuditPolicyPresetAllRequests: `apiVersion: audit.k8s.io/v1
+kind: Policy
+rules:
+- level: RequestResponse
+`,
+}
+
+// AuditPolicyPreset returns the audit policy preset of the given cluster, defaulting to metadata
+func AuditPolicyPreset(cluster *kubermaticv1.Cluster) kubermaticv1.AuditPolicyPreset {
+	if cluster.Spec.AuditLogging == nil || cluster.Spec.AuditLogging.PolicyPreset == "" {
+		return kubermaticv1.AuditPolicyPresetMetadata
+	}
+	return cluster.Spec.AuditLogging.PolicyPreset
+}
+
+// AuditPolicyConfigMapName returns the name of the ConfigMap the audit policy of the given cluster is read from.
+// Custom policies are only read from ConfigMaps dedicated to them, otherwise the preset is used.
+func AuditPolicyConfigMapName(cluster *kubermaticv1.Cluster) string {
+	if cluster.Spec.AuditLogging != nil && strings.HasPrefix(cluster.Spec.AuditLogging.CustomPolicyConfigMap, resources.AuditCustomPolicyConfigMapPrefix) {
+		return cluster.Spec.AuditLogging.CustomPolicyConfigMap
+	}
+	return resources.AuditConfigMapName
+}
+
+// IsAuditWebhookEnabled returns whether the audit events of the given cluster are sent to a webhook
+func IsAuditWebhookEnabled(cluster *kubermaticv1.Cluster) bool {
+	return cluster.Spec.AuditLogging != nil && cluster.Spec.AuditLogging.Enabled && cluster.Spec.AuditLogging.Webhook != nil
+}
+
+// AuditConfigMapCreator returns a function to create the ConfigMap containing the audit policy preset of the cluster.
+// Custom policies are not copied into this ConfigMap, their ConfigMap is mounted instead. Unless a preset is selected,
+// an existing policy is kept, so changes made to the ConfigMap by administrators are not overwritten.
+func AuditConfigMapCreator(data *resources.TemplateData) reconciling.NamedConfigMapCreatorGetter {
+	return func() (string, reconciling.ConfigMapCreator) {
+		return resources.AuditConfigMapName, func(cm *corev1.ConfigMap) (*corev1.ConfigMap, error) {
+			auditLogging := data.Cluster().Spec.AuditLogging
+			presetSelected := auditLogging != nil && auditLogging.PolicyPreset != ""
+			if _, exists := cm.Data[resources.AuditPolicyConfigMapKey]; exists && !presetSelected {
+				return cm, nil
+			}
+
+			policy, ok := auditPolicies[AuditPolicyPreset(data.Cluster())]
+			if !ok {
+				return nil, fmt.Errorf("unknown audit policy preset %q", AuditPolicyPreset(data.Cluster()))
+			}
+
+			if cm.Data == nil {
+				cm.Data = map[string]string{}
+			}
+			cm.Data[resources.AuditPolicyConfigMapKey] = policy
+			return cm, nil
+		}
+	}
+}
+
+// AuditWebhookConfigSecretCreator returns a function to create the Secret containing the kubeconfig of the audit webhook
+func AuditWebhookConfigSecretCreator(data *resources.TemplateData) reconciling.NamedSecretCreatorGetter {
+	return func() (string, reconciling.SecretCreator) {
+		return resources.AuditWebhookConfigSecretName, func(se *corev1.Secret) (*corev1.Secret, error) {
+			webhook := data.Cluster().Spec.AuditLogging.Webhook
+
+			config := clientcmdapi.NewConfig()
+			config.Clusters[auditWebhookName] = &clientcmdapi.Cluster{
+				Server:                   webhook.URL,
+				CertificateAuthorityData: []byte(webhook.CABundle),
+			}
+			config.AuthInfos[auditWebhookName] = &clientcmdapi.AuthInfo{}
+			config.Contexts[auditWebhookName] = &clientcmdapi.Context{
+				Cluster:  auditWebhookName,
+				AuthInfo: auditWebhookName,
+			}
+			config.CurrentContext = auditWebhookName
+
+			b, err := clientcmd.Write(*config)
+			if err != nil {
+				return nil, fmt.Errorf("failed to encode the audit webhook kubeconfig: %v", err)
+			}
+
+			se.Data = map[string][]byte{
+				resources.AuditWebhookConfigSecretKey: b,
+			}
+			return se, nil
+		}
+	}
+}
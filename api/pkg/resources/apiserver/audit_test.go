@@ -0,0 +1,116 @@
+/*
+Copyright 2020 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiserver
+
+import (
+	"context"
+	"testing"
+
+	kubermaticv1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
+	"github.com/kubermatic/kubermatic/api/pkg/resources"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+const customPolicy = `apiVersion: audit.k8s.io/v1
+kind: Policy
+rules:
+- level: None
+`
+
+func TestAuditConfigMapCreator(t *testing.T) {
+	testCases := []struct {
+		name           string
+		settings       *kubermaticv1.AuditLoggingSettings
+		existingPolicy string
+		expectedPolicy string
+	}{
+		{
+			name:           "creates the default preset",
+			settings:       &kubermaticv1.AuditLoggingSettings{Enabled: true},
+			expectedPolicy: auditPolicies[kubermaticv1.AuditPolicyPresetMetadata],
+		},
+		{
+			name:           "keeps an existing policy if no preset is selected",
+			settings:       &kubermaticv1.AuditLoggingSettings{Enabled: true},
+			existingPolicy: customPolicy,
+			expectedPolicy: customPolicy,
+		},
+		{
+			name:           "overwrites an existing policy with the selected preset",
+			settings:       &kubermaticv1.AuditLoggingSettings{Enabled: true, PolicyPreset: kubermaticv1.AuditPolicyPresetRecommended},
+			existingPolicy: customPolicy,
+			expectedPolicy: auditPolicies[kubermaticv1.AuditPolicyPresetRecommended],
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cluster := &kubermaticv1.Cluster{}
+			cluster.Spec.AuditLogging = tc.settings
+			data := resources.NewTemplateData(context.Background(), nil, cluster, nil, nil, "", "", "", resource.Quantity{}, "", "", false, false, "", "", "", "", false, "", "", false)
+
+			cm := &corev1.ConfigMap{}
+			if tc.existingPolicy != "" {
+				cm.Data = map[string]string{resources.AuditPolicyConfigMapKey: tc.existingPolicy}
+			}
+			_, create := AuditConfigMapCreator(data)()
+			cm, err := create(cm)
+			if err != nil {
+				t.Fatalf("failed to create ConfigMap: %v", err)
+			}
+			if policy := cm.Data[resources.AuditPolicyConfigMapKey]; policy != tc.expectedPolicy {
+				t.Errorf("expected policy\n%s\ngot\n%s", tc.expectedPolicy, policy)
+			}
+		})
+	}
+}
+
+func TestAuditPolicyConfigMapName(t *testing.T) {
+	testCases := []struct {
+		name         string
+		settings     *kubermaticv1.AuditLoggingSettings
+		expectedName string
+	}{
+		{
+			name:         "preset",
+			settings:     &kubermaticv1.AuditLoggingSettings{Enabled: true},
+			expectedName: resources.AuditConfigMapName,
+		},
+		{
+			name:         "custom policy",
+			settings:     &kubermaticv1.AuditLoggingSettings{Enabled: true, CustomPolicyConfigMap: "audit-policy-strict"},
+			expectedName: "audit-policy-strict",
+		},
+		{
+			name:         "ConfigMap not dedicated to audit policies",
+			settings:     &kubermaticv1.AuditLoggingSettings{Enabled: true, CustomPolicyConfigMap: resources.CloudConfigConfigMapName},
+			expectedName: resources.AuditConfigMapName,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cluster := &kubermaticv1.Cluster{}
+			cluster.Spec.AuditLogging = tc.settings
+			if name := AuditPolicyConfigMapName(cluster); name != tc.expectedName {
+				t.Errorf("expected ConfigMap %q, got %q", tc.expectedName, name)
+			}
+		})
+	}
+}
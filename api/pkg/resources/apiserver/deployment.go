@@ -54,22 +54,6 @@ const (
 	defaultNodePortRange = "30000-32767"
 )
 
-func AuditConfigMapCreator() reconciling.NamedConfigMapCreatorGetter {
-	return func() (string, reconciling.ConfigMapCreator) {
-		return resources.AuditConfigMapName, func(cm *corev1.ConfigMap) (*corev1.ConfigMap, error) {
-			if cm.Data == nil {
-				cm.Data = map[string]string{
-					"policy.yaml": `apiVersion: audit.k8s.io/v1
-kind: Policy
-rules:
-- level: Metadata
-`}
-			}
-			return cm, nil
-		}
-	}
-}
-
 // DeploymentCreator returns the function to create and update the API server deployment
 func DeploymentCreator(data *resources.TemplateData, enableOIDCAuthentication bool) reconciling.NamedDeploymentCreatorGetter {
 	return func() (string, reconciling.DeploymentCreator) {
@@ -87,7 +71,7 @@ func DeploymentCreator(data *resources.TemplateData, enableOIDCAuthentication bo
 			}
 			dep.Spec.Template.Spec.ImagePullSecrets = []corev1.LocalObjectReference{{Name: resources.ImagePullSecretName}}
 
-			volumes := getVolumes()
+			volumes := getVolumes(data.Cluster())
 			volumeMounts := getVolumeMounts()
 
 			if enableOIDCAuthentication && len(data.OIDCCAFile()) > 0 {
@@ -99,6 +83,38 @@ func DeploymentCreator(data *resources.TemplateData, enableOIDCAuthentication bo
 				})
 			}
 
+			if IsEncryptionAtRestEnabled(data.Cluster()) {
+				volumes = append(volumes, corev1.Volume{
+					Name: resources.EncryptionConfigurationSecretName,
+					VolumeSource: corev1.VolumeSource{
+						Secret: &corev1.SecretVolumeSource{
+							SecretName: resources.EncryptionConfigurationSecretName,
+						},
+					},
+				})
+				volumeMounts = append(volumeMounts, corev1.VolumeMount{
+					Name:      resources.EncryptionConfigurationSecretName,
+					MountPath: "/etc/kubernetes/encryption-configuration",
+					ReadOnly:  true,
+				})
+			}
+
+			if IsAuditWebhookEnabled(data.Cluster()) {
+				volumes = append(volumes, corev1.Volume{
+					Name: resources.AuditWebhookConfigSecretName,
+					VolumeSource: corev1.VolumeSource{
+						Secret: &corev1.SecretVolumeSource{
+							SecretName: resources.AuditWebhookConfigSecretName,
+						},
+					},
+				})
+				volumeMounts = append(volumeMounts, corev1.VolumeMount{
+					Name:      resources.AuditWebhookConfigSecretName,
+					MountPath: "/etc/kubernetes/audit-webhook",
+					ReadOnly:  true,
+				})
+			}
+
 			podLabels, err := data.GetPodTemplateLabels(name, volumes, nil)
 			if err != nil {
 				return nil, err
@@ -113,7 +129,11 @@ func DeploymentCreator(data *resources.TemplateData, enableOIDCAuthentication bo
 				},
 			}
 
-			etcdEndpoints := etcd.GetClientEndpoints(data.Cluster().Status.NamespaceName)
+			etcdReplicas, err := data.EtcdStatefulSetReplicas()
+			if err != nil {
+				return nil, err
+			}
+			etcdEndpoints := etcd.GetClientEndpoints(data.Cluster(), etcd.CurrentClusterSize(data.Cluster(), etcdReplicas))
 
 			// Configure user cluster DNS resolver for this pod.
 			dep.Spec.Template.Spec.DNSPolicy, dep.Spec.Template.Spec.DNSConfig, err = resources.UserClusterDNSPolicyAndConfig(data)
@@ -308,7 +328,22 @@ func getApiserverFlags(data *resources.TemplateData, etcdEndpoints []string, ena
 	}
 
 	if auditLogEnabled {
-		flags = append(flags, "--audit-policy-file", "/etc/kubernetes/audit/policy.yaml")
+		flags = append(flags, "--audit-policy-file", "/etc/kubernetes/audit/"+resources.AuditPolicyConfigMapKey)
+	}
+
+	if IsAuditWebhookEnabled(data.Cluster()) {
+		webhookMode := data.Cluster().Spec.AuditLogging.Webhook.Mode
+		if webhookMode == "" {
+			webhookMode = kubermaticv1.AuditWebhookModeBatch
+		}
+		flags = append(flags,
+			"--audit-webhook-config-file", "/etc/kubernetes/audit-webhook/"+resources.AuditWebhookConfigSecretKey,
+			"--audit-webhook-mode", string(webhookMode),
+		)
+	}
+
+	if IsEncryptionAtRestEnabled(data.Cluster()) {
+		flags = append(flags, "--encryption-provider-config", "/etc/kubernetes/encryption-configuration/"+resources.EncryptionConfigurationSecretKey)
 	}
 
 	if endpointReconcilingDisabled {
@@ -415,7 +450,7 @@ func getVolumeMounts() []corev1.VolumeMount {
 	}
 }
 
-func getVolumes() []corev1.Volume {
+func getVolumes(cluster *kubermaticv1.Cluster) []corev1.Volume {
 	return []corev1.Volume{
 		{
 			Name: resources.ApiserverTLSSecretName,
@@ -518,7 +553,7 @@ func getVolumes() []corev1.Volume {
 			VolumeSource: corev1.VolumeSource{
 				ConfigMap: &corev1.ConfigMapVolumeSource{
 					LocalObjectReference: corev1.LocalObjectReference{
-						Name: resources.AuditConfigMapName,
+						Name: AuditPolicyConfigMapName(cluster),
 					},
 					Optional: new(bool),
 				},
@@ -0,0 +1,183 @@
+/*
+Copyright 2020 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiserver
+
+import (
+	cryptorand "crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"time"
+
+	kubermaticv1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
+	"github.com/kubermatic/kubermatic/api/pkg/resources"
+	"github.com/kubermatic/kubermatic/api/pkg/resources/reconciling"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// encryptionConfiguration is the apiserver.config.k8s.io/v1 EncryptionConfiguration, which is not vendored
+type encryptionConfiguration struct {
+	Kind       string                            `json:"kind"`
+	APIVersion string                            `json:"apiVersion"`
+	Resources  []encryptionResourceConfiguration `json:"resources"`
+}
+
+type encryptionResourceConfiguration struct {
+	Resources []string                          `json:"resources"`
+	Providers []encryptionProviderConfiguration `json:"providers"`
+}
+
+type encryptionProviderConfiguration struct {
+	AESCBC    *encryptionKeysConfiguration `json:"aescbc,omitempty"`
+	Secretbox *encryptionKeysConfiguration `json:"secretbox,omitempty"`
+	Identity  *struct{}                    `json:"identity,omitempty"`
+}
+
+type encryptionKeysConfiguration struct {
+	Keys []encryptionKeyConfiguration `json:"keys"`
+}
+
+type encryptionKeyConfiguration struct {
+	Name   string `json:"name"`
+	Secret string `json:"secret"`
+}
+
+// EncryptionKey is a key the apiserver uses to encrypt Secrets in etcd
+type EncryptionKey struct {
+	Name     string
+	Provider kubermaticv1.EncryptionAtRestProvider
+	// Secret is the base64 encoded key
+	Secret string
+}
+
+// NewEncryptionKey returns a new random key for the given provider
+func NewEncryptionKey(provider kubermaticv1.EncryptionAtRestProvider) (EncryptionKey, error) {
+	if provider == "" {
+		provider = kubermaticv1.EncryptionAtRestProviderAESCBC
+	}
+	// Both aescbc and secretbox use 32 byte keys
+	secret := make([]byte, 32)
+	if _, err := cryptorand.Read(secret); err != nil {
+		return EncryptionKey{}, fmt.Errorf("failed to generate key: %v", err)
+	}
+	return EncryptionKey{
+		Name:     fmt.Sprintf("key-%s", time.Now().UTC().Format("20060102150405")),
+		Provider: provider,
+		Secret:   base64.StdEncoding.EncodeToString(secret),
+	}, nil
+}
+
+// GetEncryptionKeys returns the keys of the encryption configuration stored in the given secret.
+// The first key is used for encryption, all keys are used for decryption.
+func GetEncryptionKeys(secret *corev1.Secret) ([]EncryptionKey, error) {
+	config := &encryptionConfiguration{}
+	if err := yaml.Unmarshal(secret.Data[resources.EncryptionConfigurationSecretKey], config); err != nil {
+		return nil, fmt.Errorf("failed to parse encryption configuration: %v", err)
+	}
+	if len(config.Resources) != 1 {
+		return nil, fmt.Errorf("expected the encryption configuration to contain one resource configuration, got %d", len(config.Resources))
+	}
+
+	var keys []EncryptionKey
+	for _, provider := range config.Resources[0].Providers {
+		switch {
+		case provider.AESCBC != nil:
+			for _, key := range provider.AESCBC.Keys {
+				keys = append(keys, EncryptionKey{Name: key.Name, Provider: kubermaticv1.EncryptionAtRestProviderAESCBC, Secret: key.Secret})
+			}
+		case provider.Secretbox != nil:
+			for _, key := range provider.Secretbox.Keys {
+				keys = append(keys, EncryptionKey{Name: key.Name, Provider: kubermaticv1.EncryptionAtRestProviderSecretbox, Secret: key.Secret})
+			}
+		}
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("the encryption configuration does not contain any key")
+	}
+	return keys, nil
+}
+
+// SetEncryptionKeys writes an encryption configuration with the given keys into the given secret.
+// The first key is used for encryption, all keys are used for decryption. Secrets that were not
+// encrypted yet can still be read.
+func SetEncryptionKeys(secret *corev1.Secret, keys []EncryptionKey) error {
+	if len(keys) == 0 {
+		return errors.New("at least one key is required")
+	}
+
+	resourceConfig := encryptionResourceConfiguration{Resources: []string{"secrets"}}
+	for _, key := range keys {
+		keysConfig := &encryptionKeysConfiguration{Keys: []encryptionKeyConfiguration{{Name: key.Name, Secret: key.Secret}}}
+		switch key.Provider {
+		case kubermaticv1.EncryptionAtRestProviderAESCBC:
+			resourceConfig.Providers = append(resourceConfig.Providers, encryptionProviderConfiguration{AESCBC: keysConfig})
+		case kubermaticv1.EncryptionAtRestProviderSecretbox:
+			resourceConfig.Providers = append(resourceConfig.Providers, encryptionProviderConfiguration{Secretbox: keysConfig})
+		default:
+			return fmt.Errorf("unknown encryption provider %q", key.Provider)
+		}
+	}
+	resourceConfig.Providers = append(resourceConfig.Providers, encryptionProviderConfiguration{Identity: &struct{}{}})
+
+	config, err := yaml.Marshal(&encryptionConfiguration{
+		Kind:       "EncryptionConfiguration",
+		APIVersion: "apiserver.config.k8s.io/v1",
+		Resources:  []encryptionResourceConfiguration{resourceConfig},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode encryption configuration: %v", err)
+	}
+
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+	secret.Data[resources.EncryptionConfigurationSecretKey] = config
+	return nil
+}
+
+type encryptionConfigurationCreatorData interface {
+	Cluster() *kubermaticv1.Cluster
+}
+
+// EncryptionConfigurationSecretCreator returns a function to create the secret containing the
+// encryption configuration of the apiserver. An existing configuration is never changed here,
+// its keys get rotated by the encryption at rest controller.
+func EncryptionConfigurationSecretCreator(data encryptionConfigurationCreatorData) reconciling.NamedSecretCreatorGetter {
+	return func() (string, reconciling.SecretCreator) {
+		return resources.EncryptionConfigurationSecretName, func(se *corev1.Secret) (*corev1.Secret, error) {
+			if _, exists := se.Data[resources.EncryptionConfigurationSecretKey]; exists {
+				return se, nil
+			}
+
+			key, err := NewEncryptionKey(data.Cluster().Spec.EncryptionAtRest.Provider)
+			if err != nil {
+				return nil, err
+			}
+			if err := SetEncryptionKeys(se, []EncryptionKey{key}); err != nil {
+				return nil, err
+			}
+			return se, nil
+		}
+	}
+}
+
+// IsEncryptionAtRestEnabled returns whether the Secrets in the etcd of the given cluster are encrypted
+func IsEncryptionAtRestEnabled(cluster *kubermaticv1.Cluster) bool {
+	return cluster.Spec.EncryptionAtRest != nil && cluster.Spec.EncryptionAtRest.Enabled
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2020 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiserver
+
+import (
+	"reflect"
+	"testing"
+
+	kubermaticv1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
+	"github.com/kubermatic/kubermatic/api/pkg/resources"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestEncryptionKeys(t *testing.T) {
+	keys := []EncryptionKey{
+		{Name: "key-2", Provider: kubermaticv1.EncryptionAtRestProviderSecretbox, Secret: "c2Vjb25k"},
+		{Name: "key-1", Provider: kubermaticv1.EncryptionAtRestProviderAESCBC, Secret: "Zmlyc3Q="},
+	}
+
+	secret := &corev1.Secret{}
+	if err := SetEncryptionKeys(secret, keys); err != nil {
+		t.Fatalf("failed to set keys: %v", err)
+	}
+
+	expectedConfig := `apiVersion: apiserver.config.k8s.io/v1
+kind: EncryptionConfiguration
+resources:
+- providers:
+  - secretbox:
+      keys:
+      - name: key-2
+        secret: c2Vjb25k
+  - aescbc:
+      keys:
+      - name: key-1
+        secret: Zmlyc3Q=
+  - identity: {}
+  resources:
+  - secrets
+`
+	if config := string(secret.Data[resources.EncryptionConfigurationSecretKey]); config != expectedConfig {
+		t.Errorf("expected configuration\n%s\ngot\n%s", expectedConfig, config)
+	}
+
+	parsedKeys, err := GetEncryptionKeys(secret)
+	if err != nil {
+		t.Fatalf("failed to get keys: %v", err)
+	}
+	if !reflect.DeepEqual(parsedKeys, keys) {
+		t.Errorf("expected keys %v, got %v", keys, parsedKeys)
+	}
+}
+
+func TestNewEncryptionKeyDefaultsToAESCBC(t *testing.T) {
+	key, err := NewEncryptionKey("")
+	if err != nil {
+		t.Fatalf("failed to create key: %v", err)
+	}
+	if key.Provider != kubermaticv1.EncryptionAtRestProviderAESCBC {
+		t.Errorf("expected provider %q, got %q", kubermaticv1.EncryptionAtRestProviderAESCBC, key.Provider)
+	}
+	if len(key.Secret) != 44 {
+		t.Errorf("expected a base64 encoded 32 byte secret, got %q", key.Secret)
+	}
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2020 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificates
+
+import (
+	"crypto/x509"
+	"sort"
+
+	"github.com/kubermatic/kubermatic/api/pkg/resources"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/clientcmd"
+	certutil "k8s.io/client-go/util/cert"
+)
+
+// SecretCertificate is a certificate stored in a secret
+type SecretCertificate struct {
+	// Key is the key of the secret data which contains the certificate
+	Key  string
+	Cert *x509.Certificate
+}
+
+// GetSecretCertificates returns all certificates stored in the given secret. Besides PEM-encoded
+// certificates, client certificates embedded in kubeconfigs are returned. Other data is ignored.
+func GetSecretCertificates(secret *corev1.Secret) []SecretCertificate {
+	keys := make([]string, 0, len(secret.Data))
+	for key := range secret.Data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var result []SecretCertificate
+	for _, key := range keys {
+		var certs []*x509.Certificate
+		if key == resources.KubeconfigSecretKey {
+			certs = getKubeconfigCertificates(secret.Data[key])
+		} else if parsed, err := certutil.ParseCertsPEM(secret.Data[key]); err == nil {
+			certs = parsed
+		}
+
+		for _, cert := range certs {
+			result = append(result, SecretCertificate{Key: key, Cert: cert})
+		}
+	}
+	return result
+}
+
+func getKubeconfigCertificates(data []byte) []*x509.Certificate {
+	config, err := clientcmd.Load(data)
+	if err != nil {
+		return nil
+	}
+
+	names := make([]string, 0, len(config.AuthInfos))
+	for name := range config.AuthInfos {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var result []*x509.Certificate
+	for _, name := range names {
+		if len(config.AuthInfos[name].ClientCertificateData) == 0 {
+			continue
+		}
+		certs, err := certutil.ParseCertsPEM(config.AuthInfos[name].ClientCertificateData)
+		if err != nil {
+			continue
+		}
+		result = append(result, certs...)
+	}
+	return result
+}
@@ -178,8 +178,10 @@ func ParseRSAKeyPair(certPEM, keyPEM []byte) (*KeyPair, error) {
 		return nil, fmt.Errorf("certificate is not valid PEM: %v", err)
 	}
 
-	if len(certs) != 1 {
-		return nil, fmt.Errorf("did not find exactly one but %v certificates", len(certs))
+	// Additional certificates are part of a trust bundle, the first one
+	// must belong to the key.
+	if len(certs) == 0 {
+		return nil, errors.New("did not find any certificate")
 	}
 
 	key, err := ParsePrivateKeyPEM(keyPEM)
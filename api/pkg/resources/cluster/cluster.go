@@ -42,6 +42,8 @@ func Spec(apiCluster apiv1.Cluster, dc *kubermaticv1.Datacenter, secretKeyGetter
 		AuditLogging:                        apiCluster.Spec.AuditLogging,
 		Openshift:                           apiCluster.Spec.Openshift,
 		AdmissionPlugins:                    apiCluster.Spec.AdmissionPlugins,
+		BackupPolicy:                        apiCluster.Spec.BackupPolicy,
+		EncryptionAtRest:                    apiCluster.Spec.EncryptionAtRest,
 	}
 
 	providerName, err := provider.ClusterCloudProviderName(spec.Cloud)
@@ -248,6 +248,11 @@ func (d *TemplateData) GetFrontProxyCA() (*triple.KeyPair, error) {
 	return GetClusterFrontProxyCA(d.ctx, d.cluster.Status.NamespaceName, d.client)
 }
 
+// GetEtcdPeerCA returns the CA for the etcd peer certificates
+func (d *TemplateData) GetEtcdPeerCA() (*triple.KeyPair, error) {
+	return GetClusterEtcdPeerCA(d.ctx, d.cluster.Status.NamespaceName, d.client)
+}
+
 // GetOpenVPNCA returns the root ca for the OpenVPN
 func (d *TemplateData) GetOpenVPNCA() (*ECDSAKeyPair, error) {
 	return GetOpenVPNCA(d.ctx, d.cluster.Status.NamespaceName, d.client)
@@ -273,6 +278,11 @@ func (d *TemplateData) HasEtcdOperatorService() (bool, error) {
 	return true, nil
 }
 
+// EtcdStatefulSetReplicas returns the replicas of the etcd StatefulSet or nil if it doesn't exist yet
+func (d *TemplateData) EtcdStatefulSetReplicas() (*int32, error) {
+	return EtcdStatefulSetReplicas(d.ctx, d.cluster.Status.NamespaceName, d.client)
+}
+
 // GetApiserverExternalNodePort returns the nodeport of the external apiserver service
 func (d *TemplateData) GetOpenVPNServerPort() (int32, error) {
 	service := &corev1.Service{}
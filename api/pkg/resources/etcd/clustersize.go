@@ -0,0 +1,283 @@
+/*
+Copyright 2020 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etcd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	kubermaticv1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
+	"github.com/kubermatic/kubermatic/api/pkg/resources"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// initialClusterSizeAnnotation is set on the StatefulSet and holds the number of members
+	// etcd was bootstrapped with. Members that are added later join the running cluster.
+	initialClusterSizeAnnotation = "etcd.kubermatic.io/initial-cluster-size"
+
+	memberRemovalJobName = "etcd-remove-member"
+	// memberRemovalJobMemberAnnotation is set on the member removal Job and holds the name of the removed member
+	memberRemovalJobMemberAnnotation = "etcd.kubermatic.io/member"
+)
+
+// ClusterSize returns the number of etcd members requested for the cluster. Invalid sizes
+// are ignored, they get rejected by the validation.
+func ClusterSize(cluster *kubermaticv1.Cluster) int {
+	if size := cluster.Spec.ComponentsOverride.Etcd.ClusterSize; size != nil && validClusterSize(int(*size)) {
+		return int(*size)
+	}
+	return resources.DefaultEtcdClusterSize
+}
+
+// CurrentClusterSize returns the number of members etcd currently runs with, given the replicas
+// of its StatefulSet. ReconcileClusterSize moves them one member at a time towards ClusterSize,
+// so both differ while the size changes. The requested size is returned if the StatefulSet
+// doesn't exist yet, it gets created with that size.
+func CurrentClusterSize(cluster *kubermaticv1.Cluster, replicas *int32) int {
+	if replicas == nil {
+		return ClusterSize(cluster)
+	}
+	return int(*replicas)
+}
+
+// GetCurrentClusterSize returns the CurrentClusterSize based on the etcd StatefulSet of the cluster
+func GetCurrentClusterSize(ctx context.Context, client ctrlruntimeclient.Client, cluster *kubermaticv1.Cluster) (int, error) {
+	replicas, err := resources.EtcdStatefulSetReplicas(ctx, cluster.Status.NamespaceName, client)
+	if err != nil {
+		return 0, err
+	}
+	return CurrentClusterSize(cluster, replicas), nil
+}
+
+func validClusterSize(size int) bool {
+	return size >= 1 && size <= resources.MaxEtcdClusterSize && size%2 == 1
+}
+
+// memberName returns the name of the etcd member with the given ordinal
+func memberName(member int) string {
+	return fmt.Sprintf("%s-%d", resources.EtcdStatefulSetName, member)
+}
+
+// memberPVCName returns the name of the PVC of the etcd member with the given ordinal
+func memberPVCName(member int) string {
+	return fmt.Sprintf("data-%s", memberName(member))
+}
+
+type clusterSizeData interface {
+	Cluster() *kubermaticv1.Cluster
+	ImageRegistry(string) string
+	GetClusterRef() metav1.OwnerReference
+}
+
+// ReconcileClusterSize moves the number of etcd members one step closer to the size requested
+// for the cluster. A step is only started when all members are ready, which requires etcd to
+// have quorum.
+// New members are added by increasing the replicas of the StatefulSet, their pod joins the
+// running cluster by itself. Members are removed from the cluster with a Job before the
+// replicas get decreased, afterwards the volume of the removed member is deleted so it can
+// be added again later.
+func ReconcileClusterSize(ctx context.Context, client ctrlruntimeclient.Client, data clusterSizeData) error {
+	namespace := data.Cluster().Status.NamespaceName
+	if size := data.Cluster().Spec.ComponentsOverride.Etcd.ClusterSize; size != nil && !validClusterSize(int(*size)) {
+		return fmt.Errorf("invalid etcd cluster size %d, must be one of 1, 3, 5 or 7", *size)
+	}
+
+	set := &appsv1.StatefulSet{}
+	if err := client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: resources.EtcdStatefulSetName}, set); err != nil {
+		if kerrors.IsNotFound(err) {
+			// The StatefulSet gets created with the requested size
+			return nil
+		}
+		return fmt.Errorf("failed to get etcd StatefulSet: %v", err)
+	}
+	// Members are not added or removed while their peer URLs get migrated
+	if set.Spec.Replicas == nil || PeerTLSPhase(data.Cluster()) != kubermaticv1.ReasonEtcdPeerTLSEnabled {
+		return nil
+	}
+
+	current := int(*set.Spec.Replicas)
+	desired := ClusterSize(data.Cluster())
+
+	switch {
+	case desired < current:
+		return removeMember(ctx, client, data, set)
+	case !statefulSetHealthy(set):
+		return nil
+	case desired > current:
+		return scaleStatefulSet(ctx, client, set, current+1)
+	default:
+		return cleanupRemovedMembers(ctx, client, namespace, current)
+	}
+}
+
+// statefulSetHealthy returns true if all etcd members run with the current template and are ready
+func statefulSetHealthy(set *appsv1.StatefulSet) bool {
+	return set.Status.ObservedGeneration >= set.Generation &&
+		set.Status.CurrentRevision == set.Status.UpdateRevision &&
+		set.Status.Replicas == *set.Spec.Replicas &&
+		set.Status.UpdatedReplicas == *set.Spec.Replicas &&
+		set.Status.ReadyReplicas == *set.Spec.Replicas
+}
+
+func scaleStatefulSet(ctx context.Context, client ctrlruntimeclient.Client, set *appsv1.StatefulSet, replicas int) error {
+	oldSet := set.DeepCopy()
+	set.Spec.Replicas = resources.Int32(int32(replicas))
+	if err := client.Patch(ctx, set, ctrlruntimeclient.MergeFrom(oldSet)); err != nil {
+		return fmt.Errorf("failed to scale etcd StatefulSet to %d replicas: %v", replicas, err)
+	}
+	return nil
+}
+
+// removeMember removes the member with the highest ordinal from the etcd cluster. Once the
+// removal Job succeeded, the StatefulSet gets scaled down. The removed member is not ready
+// anymore at this point, so only the creation of the Job waits for a healthy StatefulSet.
+func removeMember(ctx context.Context, client ctrlruntimeclient.Client, data clusterSizeData, set *appsv1.StatefulSet) error {
+	namespace := data.Cluster().Status.NamespaceName
+	member := int(*set.Spec.Replicas) - 1
+
+	job := &batchv1.Job{}
+	if err := client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: memberRemovalJobName}, job); err != nil {
+		if !kerrors.IsNotFound(err) {
+			return fmt.Errorf("failed to get etcd member removal Job: %v", err)
+		}
+		if !statefulSetHealthy(set) {
+			return nil
+		}
+		if err := client.Create(ctx, memberRemovalJob(data, member)); err != nil {
+			return fmt.Errorf("failed to create etcd member removal Job: %v", err)
+		}
+		return nil
+	}
+
+	// A Job left over from an earlier removal of another member
+	if job.Annotations[memberRemovalJobMemberAnnotation] != memberName(member) {
+		return deleteJob(ctx, client, job)
+	}
+	if job.Spec.BackoffLimit != nil && job.Status.Failed > *job.Spec.BackoffLimit {
+		return fmt.Errorf("failed to remove etcd member %s, see the logs of Job %s/%s", memberName(member), namespace, job.Name)
+	}
+	if job.Status.Succeeded == 0 {
+		return nil
+	}
+
+	if err := scaleStatefulSet(ctx, client, set, member); err != nil {
+		return err
+	}
+	return deleteJob(ctx, client, job)
+}
+
+func deleteJob(ctx context.Context, client ctrlruntimeclient.Client, job *batchv1.Job) error {
+	if err := client.Delete(ctx, job, ctrlruntimeclient.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil && !kerrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete Job %s: %v", job.Name, err)
+	}
+	return nil
+}
+
+// cleanupRemovedMembers deletes the volumes of all members which are not part of the cluster anymore.
+// A member that gets added again must not find the data of its previous incarnation.
+func cleanupRemovedMembers(ctx context.Context, client ctrlruntimeclient.Client, namespace string, size int) error {
+	for member := size; member < resources.MaxEtcdClusterSize; member++ {
+		pvc := &corev1.PersistentVolumeClaim{}
+		if err := client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: memberPVCName(member)}, pvc); err != nil {
+			if kerrors.IsNotFound(err) {
+				continue
+			}
+			return fmt.Errorf("failed to get PVC %s: %v", memberPVCName(member), err)
+		}
+		if pvc.DeletionTimestamp != nil {
+			continue
+		}
+		if err := client.Delete(ctx, pvc); err != nil && !kerrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete PVC %s: %v", memberPVCName(member), err)
+		}
+	}
+	return nil
+}
+
+func memberRemovalJob(data clusterSizeData, member int) *batchv1.Job {
+	name := memberName(member)
+
+	// Talk to the remaining members only
+	var endpoints []string
+	for i := 0; i < member; i++ {
+		endpoints = append(endpoints, clientEndpoint(data.Cluster().Status.NamespaceName, i))
+	}
+
+	etcdctl := fmt.Sprintf("etcdctl --cacert /etc/etcd/pki/client/ca.crt --cert /etc/etcd/pki/client/apiserver-etcd-client.crt --key /etc/etcd/pki/client/apiserver-etcd-client.key --endpoints %s", strings.Join(endpoints, ","))
+	script := fmt.Sprintf(`MEMBER_ID=$(%s member list | grep "://%s\." | cut -d, -f1)
+if [ -n "${MEMBER_ID}" ]; then
+    %s member remove ${MEMBER_ID}
+fi
+echo "removed %s"`, etcdctl, name, etcdctl, name)
+
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            memberRemovalJobName,
+			Namespace:       data.Cluster().Status.NamespaceName,
+			OwnerReferences: []metav1.OwnerReference{data.GetClusterRef()},
+			Annotations:     map[string]string{memberRemovalJobMemberAnnotation: name},
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: resources.Int32(3),
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					RestartPolicy:    corev1.RestartPolicyNever,
+					ImagePullSecrets: []corev1.LocalObjectReference{{Name: resources.ImagePullSecretName}},
+					Containers: []corev1.Container{
+						{
+							Name:    "remove-member",
+							Image:   data.ImageRegistry(resources.RegistryGCR) + "/etcd-development/etcd:" + ImageTag(data.Cluster()),
+							Command: []string{"/bin/sh", "-ec", script},
+							Env: []corev1.EnvVar{
+								{
+									Name:  "ETCDCTL_API",
+									Value: "3",
+								},
+							},
+							VolumeMounts: []corev1.VolumeMount{
+								{
+									Name:      resources.ApiserverEtcdClientCertificateSecretName,
+									MountPath: "/etc/etcd/pki/client",
+									ReadOnly:  true,
+								},
+							},
+						},
+					},
+					Volumes: []corev1.Volume{
+						{
+							Name: resources.ApiserverEtcdClientCertificateSecretName,
+							VolumeSource: corev1.VolumeSource{
+								Secret: &corev1.SecretVolumeSource{
+									SecretName: resources.ApiserverEtcdClientCertificateSecretName,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
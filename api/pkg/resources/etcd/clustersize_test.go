@@ -0,0 +1,284 @@
+/*
+Copyright 2020 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etcd
+
+import (
+	"context"
+	"testing"
+
+	kubermaticv1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
+	"github.com/kubermatic/kubermatic/api/pkg/resources"
+	"github.com/kubermatic/kubermatic/api/pkg/semver"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const testNamespace = "cluster-test"
+
+type fakeClusterSizeData struct {
+	cluster  *kubermaticv1.Cluster
+	replicas *int32
+}
+
+func (d *fakeClusterSizeData) Cluster() *kubermaticv1.Cluster {
+	return d.cluster
+}
+
+func (d *fakeClusterSizeData) ImageRegistry(registry string) string {
+	return registry
+}
+
+func (d *fakeClusterSizeData) GetClusterRef() metav1.OwnerReference {
+	return resources.GetClusterRef(d.cluster)
+}
+
+func (d *fakeClusterSizeData) EtcdStatefulSetReplicas() (*int32, error) {
+	return d.replicas, nil
+}
+
+func TestCurrentClusterSize(t *testing.T) {
+	testCases := []struct {
+		name                 string
+		desired              int32
+		replicas             *int32
+		expectedSize         int
+		expectedMinAvailable int
+	}{
+		{
+			name:                 "New cluster gets created with the requested size",
+			desired:              5,
+			expectedSize:         5,
+			expectedMinAvailable: 3,
+		},
+		{
+			name:                 "Shrinking cluster keeps quorum of the existing members",
+			desired:              3,
+			replicas:             resources.Int32(5),
+			expectedSize:         5,
+			expectedMinAvailable: 3,
+		},
+		{
+			name:                 "Growing cluster keeps quorum of the requested members",
+			desired:              5,
+			replicas:             resources.Int32(4),
+			expectedSize:         4,
+			expectedMinAvailable: 3,
+		},
+		{
+			name:                 "Cluster with the requested size",
+			desired:              1,
+			replicas:             resources.Int32(1),
+			expectedSize:         1,
+			expectedMinAvailable: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cluster := &kubermaticv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "test"}}
+			cluster.Status.NamespaceName = testNamespace
+			cluster.Spec.ComponentsOverride.Etcd.ClusterSize = &tc.desired
+
+			var objects []runtime.Object
+			if tc.replicas != nil {
+				objects = append(objects, &appsv1.StatefulSet{
+					ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: resources.EtcdStatefulSetName},
+					Spec:       appsv1.StatefulSetSpec{Replicas: tc.replicas},
+				})
+			}
+			client := fakectrlruntimeclient.NewFakeClient(objects...)
+
+			size, err := GetCurrentClusterSize(context.Background(), client, cluster)
+			if err != nil {
+				t.Fatalf("failed to get current cluster size: %v", err)
+			}
+			if size != tc.expectedSize {
+				t.Errorf("expected cluster size %d, got %d", tc.expectedSize, size)
+			}
+			if endpoints := GetClientEndpoints(cluster, size); len(endpoints) != tc.expectedSize {
+				t.Errorf("expected %d client endpoints, got %v", tc.expectedSize, endpoints)
+			}
+
+			_, creator := PodDisruptionBudgetCreator(&fakeClusterSizeData{cluster: cluster, replicas: tc.replicas})()
+			pdb, err := creator(&policyv1beta1.PodDisruptionBudget{})
+			if err != nil {
+				t.Fatalf("failed to create PodDisruptionBudget: %v", err)
+			}
+			if minAvailable := pdb.Spec.MinAvailable.IntValue(); minAvailable != tc.expectedMinAvailable {
+				t.Errorf("expected minAvailable %d, got %d", tc.expectedMinAvailable, minAvailable)
+			}
+		})
+	}
+}
+
+func TestReconcileClusterSize(t *testing.T) {
+	genCluster := func(size int32) *kubermaticv1.Cluster {
+		cluster := &kubermaticv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "test"},
+		}
+		cluster.Spec.Version = *semver.NewSemverOrDie("1.17.0")
+		cluster.Status.NamespaceName = testNamespace
+		cluster.Spec.ComponentsOverride.Etcd.ClusterSize = &size
+		SetPeerTLSCondition(cluster, kubermaticv1.ReasonEtcdPeerTLSEnabled)
+		return cluster
+	}
+	genStatefulSet := func(replicas, ready int32) *appsv1.StatefulSet {
+		return &appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: testNamespace,
+				Name:      resources.EtcdStatefulSetName,
+			},
+			Spec: appsv1.StatefulSetSpec{
+				Replicas: resources.Int32(replicas),
+			},
+			Status: appsv1.StatefulSetStatus{
+				Replicas:        replicas,
+				ReadyReplicas:   ready,
+				UpdatedReplicas: replicas,
+				CurrentRevision: "etcd-1",
+				UpdateRevision:  "etcd-1",
+			},
+		}
+	}
+	genRemovalJob := func(member int, succeeded int32) *batchv1.Job {
+		job := memberRemovalJob(&fakeClusterSizeData{cluster: genCluster(1)}, member)
+		job.Status.Succeeded = succeeded
+		return job
+	}
+	genPVC := func(member int) *corev1.PersistentVolumeClaim {
+		return &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: testNamespace,
+				Name:      memberPVCName(member),
+			},
+		}
+	}
+
+	testCases := []struct {
+		name                string
+		cluster             *kubermaticv1.Cluster
+		objects             []runtime.Object
+		expectedReplicas    int32
+		expectedRemovalJob  string
+		expectedPVCs        []string
+		expectedErrorString string
+	}{
+		{
+			name:             "adds one member at a time",
+			cluster:          genCluster(5),
+			objects:          []runtime.Object{genStatefulSet(3, 3)},
+			expectedReplicas: 4,
+		},
+		{
+			name:             "waits for all members to be ready before adding one",
+			cluster:          genCluster(5),
+			objects:          []runtime.Object{genStatefulSet(4, 3)},
+			expectedReplicas: 4,
+		},
+		{
+			name:               "removes the member with the highest ordinal",
+			cluster:            genCluster(1),
+			objects:            []runtime.Object{genStatefulSet(3, 3)},
+			expectedReplicas:   3,
+			expectedRemovalJob: "etcd-2",
+		},
+		{
+			name:               "waits for the member removal",
+			cluster:            genCluster(1),
+			objects:            []runtime.Object{genStatefulSet(3, 3), genRemovalJob(2, 0)},
+			expectedReplicas:   3,
+			expectedRemovalJob: "etcd-2",
+		},
+		{
+			name:             "scales down after the member got removed",
+			cluster:          genCluster(1),
+			objects:          []runtime.Object{genStatefulSet(3, 2), genRemovalJob(2, 1)},
+			expectedReplicas: 2,
+		},
+		{
+			name:             "deletes the volumes of removed members",
+			cluster:          genCluster(1),
+			objects:          []runtime.Object{genStatefulSet(1, 1), genPVC(0), genPVC(1), genPVC(2)},
+			expectedReplicas: 1,
+			expectedPVCs:     []string{memberPVCName(0)},
+		},
+		{
+			name:                "rejects an invalid size",
+			cluster:             genCluster(4),
+			objects:             []runtime.Object{genStatefulSet(3, 3)},
+			expectedReplicas:    3,
+			expectedErrorString: "invalid etcd cluster size 4, must be one of 1, 3, 5 or 7",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			client := fakectrlruntimeclient.NewFakeClient(tc.objects...)
+
+			err := ReconcileClusterSize(ctx, client, &fakeClusterSizeData{cluster: tc.cluster})
+			if tc.expectedErrorString != "" {
+				if err == nil || err.Error() != tc.expectedErrorString {
+					t.Fatalf("expected error %q, got %v", tc.expectedErrorString, err)
+				}
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			set := &appsv1.StatefulSet{}
+			if err := client.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: resources.EtcdStatefulSetName}, set); err != nil {
+				t.Fatalf("failed to get StatefulSet: %v", err)
+			}
+			if *set.Spec.Replicas != tc.expectedReplicas {
+				t.Errorf("expected %d replicas, got %d", tc.expectedReplicas, *set.Spec.Replicas)
+			}
+
+			job := &batchv1.Job{}
+			err = client.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: memberRemovalJobName}, job)
+			switch {
+			case err != nil && !kerrors.IsNotFound(err):
+				t.Fatalf("failed to get member removal Job: %v", err)
+			case err != nil && tc.expectedRemovalJob != "":
+				t.Errorf("expected a Job to remove %s, got none", tc.expectedRemovalJob)
+			case err == nil && job.Annotations[memberRemovalJobMemberAnnotation] != tc.expectedRemovalJob:
+				t.Errorf("expected a Job to remove %q, got one for %q", tc.expectedRemovalJob, job.Annotations[memberRemovalJobMemberAnnotation])
+			}
+
+			if tc.expectedPVCs != nil {
+				pvcs := &corev1.PersistentVolumeClaimList{}
+				if err := client.List(ctx, pvcs); err != nil {
+					t.Fatalf("failed to list PVCs: %v", err)
+				}
+				var names []string
+				for _, pvc := range pvcs.Items {
+					names = append(names, pvc.Name)
+				}
+				if len(names) != len(tc.expectedPVCs) || names[0] != tc.expectedPVCs[0] {
+					t.Errorf("expected PVCs %v, got %v", tc.expectedPVCs, names)
+				}
+			}
+		})
+	}
+}
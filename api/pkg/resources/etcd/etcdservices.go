@@ -56,8 +56,14 @@ func ServiceCreator(data serviceCreatorData) reconciling.NamedServiceCreatorGett
 				},
 				{
 					Name:       "peer",
-					Port:       2380,
-					TargetPort: intstr.FromInt(2380),
+					Port:       peerPort,
+					TargetPort: intstr.FromInt(peerPort),
+					Protocol:   corev1.ProtocolTCP,
+				},
+				{
+					Name:       "peer-tls",
+					Port:       peerTLSPort,
+					TargetPort: intstr.FromInt(peerTLSPort),
 					Protocol:   corev1.ProtocolTCP,
 				},
 			}
@@ -67,14 +73,19 @@ func ServiceCreator(data serviceCreatorData) reconciling.NamedServiceCreatorGett
 	}
 }
 
-// GetClientEndpoints returns the slice with the etcd endpoints for client communication
-func GetClientEndpoints(namespace string) []string {
+// GetClientEndpoints returns the slice with the etcd endpoints for client communication. The
+// size is the number of members etcd currently runs with, see CurrentClusterSize.
+func GetClientEndpoints(cluster *kubermaticv1.Cluster, size int) []string {
 	var endpoints []string
-	for i := 0; i < 3; i++ {
-		// Pod DNS name
-		serviceDNSName := resources.GetAbsoluteServiceDNSName(resources.EtcdServiceName, namespace)
-		absolutePodDNSName := fmt.Sprintf("https://etcd-%d.%s:2379", i, serviceDNSName)
-		endpoints = append(endpoints, absolutePodDNSName)
+	for i := 0; i < size; i++ {
+		endpoints = append(endpoints, clientEndpoint(cluster.Status.NamespaceName, i))
 	}
 	return endpoints
 }
+
+// clientEndpoint returns the URL for client communication with the given etcd member
+func clientEndpoint(namespace string, member int) string {
+	// Pod DNS name
+	serviceDNSName := resources.GetAbsoluteServiceDNSName(resources.EtcdServiceName, namespace)
+	return fmt.Sprintf("https://etcd-%d.%s:2379", member, serviceDNSName)
+}
@@ -28,13 +28,24 @@ import (
 
 type pdbData interface {
 	Cluster() *kubermaticv1.Cluster
+	EtcdStatefulSetReplicas() (*int32, error)
 }
 
 // PodDisruptionBudgetCreator returns a func to create/update the etcd PodDisruptionBudget
 func PodDisruptionBudgetCreator(data pdbData) reconciling.NamedPodDisruptionBudgetCreatorGetter {
 	return func() (string, reconciling.PodDisruptionBudgetCreator) {
 		return resources.EtcdPodDisruptionBudgetName, func(pdb *policyv1beta1.PodDisruptionBudget) (*policyv1beta1.PodDisruptionBudget, error) {
-			minAvailable := intstr.FromInt((resources.EtcdClusterSize / 2) + 1)
+			replicas, err := data.EtcdStatefulSetReplicas()
+			if err != nil {
+				return nil, err
+			}
+			// Quorum must be kept for the members that currently exist, which are more than
+			// requested while the cluster shrinks
+			size := CurrentClusterSize(data.Cluster(), replicas)
+			if desired := ClusterSize(data.Cluster()); desired > size {
+				size = desired
+			}
+			minAvailable := intstr.FromInt((size / 2) + 1)
 			pdb.Spec = policyv1beta1.PodDisruptionBudgetSpec{
 				Selector: &metav1.LabelSelector{
 					MatchLabels: getBasePodLabels(data.Cluster()),
@@ -0,0 +1,119 @@
+/*
+Copyright 2020 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etcd
+
+import (
+	"crypto/x509"
+	"fmt"
+
+	kubermaticv1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
+	"github.com/kubermatic/kubermatic/api/pkg/resources"
+	"github.com/kubermatic/kubermatic/api/pkg/resources/certificates"
+	"github.com/kubermatic/kubermatic/api/pkg/resources/certificates/triple"
+	"github.com/kubermatic/kubermatic/api/pkg/resources/reconciling"
+
+	corev1 "k8s.io/api/core/v1"
+	certutil "k8s.io/client-go/util/cert"
+)
+
+type peerTLSCertificateCreatorData interface {
+	Cluster() *kubermaticv1.Cluster
+	GetEtcdPeerCA() (*triple.KeyPair, error)
+}
+
+// PeerCACreator returns a function to create a secret with the CA for the etcd peer certificates.
+// It is separate from the cluster root CA so that only etcd members can authenticate as peers.
+func PeerCACreator() reconciling.NamedSecretCreatorGetter {
+	return func() (string, reconciling.SecretCreator) {
+		return resources.EtcdPeerCASecretName, certificates.GetCACreator("etcd-peer-ca")
+	}
+}
+
+// PeerTLSCertificateCreator returns a function to create/update the secret with the peer certificates
+// of all etcd members. Every member gets its own certificate which is used as server and client
+// certificate for the communication with the other members.
+func PeerTLSCertificateCreator(data peerTLSCertificateCreatorData) reconciling.NamedSecretCreatorGetter {
+	return func() (string, reconciling.SecretCreator) {
+		return resources.EtcdPeerTLSCertificateSecretName, func(se *corev1.Secret) (*corev1.Secret, error) {
+			ca, err := data.GetEtcdPeerCA()
+			if err != nil {
+				return nil, fmt.Errorf("failed to get etcd peer ca: %v", err)
+			}
+
+			if se.Data == nil {
+				se.Data = map[string][]byte{}
+			}
+
+			for i := 0; i < resources.MaxEtcdClusterSize; i++ {
+				memberName := fmt.Sprintf("etcd-%d", i)
+				altNames := certutil.AltNames{
+					DNSNames: []string{
+						memberName,
+						fmt.Sprintf("%s.%s.%s.svc.cluster.local", memberName, resources.EtcdServiceName, data.Cluster().Status.NamespaceName),
+					},
+				}
+
+				if b, exists := se.Data[PeerCertSecretKey(memberName)]; exists {
+					certs, err := certutil.ParseCertsPEM(b)
+					if err != nil {
+						return nil, fmt.Errorf("failed to parse certificate (key=%s) from existing secret %s: %v", PeerCertSecretKey(memberName), resources.EtcdPeerTLSCertificateSecretName, err)
+					}
+
+					if resources.IsServerCertificateValidForAllOf(certs[0], memberName, altNames, ca.Cert) {
+						continue
+					}
+				}
+
+				key, err := triple.NewPrivateKey()
+				if err != nil {
+					return nil, fmt.Errorf("failed to create private key for the peer certificate of %s: %v", memberName, err)
+				}
+
+				config := certutil.Config{
+					CommonName: memberName,
+					AltNames:   altNames,
+					Usages: []x509.ExtKeyUsage{
+						x509.ExtKeyUsageServerAuth,
+						x509.ExtKeyUsageClientAuth,
+					},
+				}
+
+				cert, err := triple.NewSignedCert(config, key, ca.Cert, ca.Key)
+				if err != nil {
+					return nil, fmt.Errorf("unable to sign the peer certificate of %s: %v", memberName, err)
+				}
+
+				se.Data[PeerKeySecretKey(memberName)] = triple.EncodePrivateKeyPEM(key)
+				se.Data[PeerCertSecretKey(memberName)] = triple.EncodeCertPEM(cert)
+			}
+
+			return se, nil
+		}
+	}
+}
+
+// PeerCertSecretKey returns the key of the peer certificate of the given member
+// inside the etcd peer certificate secret
+func PeerCertSecretKey(memberName string) string {
+	return memberName + "-peer.crt"
+}
+
+// PeerKeySecretKey returns the key of the private key of the given member
+// inside the etcd peer certificate secret
+func PeerKeySecretKey(memberName string) string {
+	return memberName + "-peer.key"
+}
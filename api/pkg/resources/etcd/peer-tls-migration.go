@@ -0,0 +1,131 @@
+/*
+Copyright 2020 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etcd
+
+import (
+	"fmt"
+
+	kubermaticv1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
+	kubermaticv1helper "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1/helper"
+	"github.com/kubermatic/kubermatic/api/pkg/resources"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Existing clusters were created with plaintext peer connections. They get migrated to
+// mutual TLS in place without losing quorum:
+//
+// 1. All members additionally listen for TLS peer connections on a separate port.
+// 2. Every member replaces its advertised plaintext peer URL with the TLS one, which is
+//    safe because all other members can already reach it using TLS.
+// 3. All members stop listening for plaintext peer connections.
+//
+// Each step is a rolling update of the StatefulSet. The current step is stored as reason of the
+// EtcdPeerTLSEnabled condition of the cluster. New clusters start with the last step.
+
+const (
+	peerPort    = 2380
+	peerTLSPort = 2381
+
+	// peerTLSPhaseAnnotation is set on the pod template to tell which migration step it belongs to
+	peerTLSPhaseAnnotation = "etcd.kubermatic.io/peer-tls-phase"
+)
+
+// PeerTLSPhase returns the peer TLS migration step the etcd of the given cluster is in. An empty
+// string means that the members only use plaintext peer connections.
+func PeerTLSPhase(cluster *kubermaticv1.Cluster) string {
+	for _, condition := range cluster.Status.Conditions {
+		if condition.Type == kubermaticv1.ClusterConditionEtcdPeerTLSEnabled {
+			return condition.Reason
+		}
+	}
+	return ""
+}
+
+// NextPeerTLSPhase returns the peer TLS migration step the etcd of the given cluster should be in.
+// set is the current etcd StatefulSet and nil if it does not exist.
+func NextPeerTLSPhase(cluster *kubermaticv1.Cluster, set *appsv1.StatefulSet) string {
+	phase := PeerTLSPhase(cluster)
+	if phase == kubermaticv1.ReasonEtcdPeerTLSEnabled {
+		return phase
+	}
+
+	if set == nil {
+		// The StatefulSet of an initialized cluster only disappears temporarily, e.g. during a restore.
+		// Its members still advertise plaintext peer URLs, so the migration needs to happen.
+		if phase == "" && !kubermaticv1helper.IsClusterInitialized(cluster) {
+			return kubermaticv1.ReasonEtcdPeerTLSEnabled
+		}
+		if phase == "" {
+			return kubermaticv1.ReasonEtcdPeerTLSListening
+		}
+		return phase
+	}
+
+	switch {
+	case phase == "":
+		return kubermaticv1.ReasonEtcdPeerTLSListening
+	case !statefulSetRolledOut(set, phase):
+		return phase
+	case phase == kubermaticv1.ReasonEtcdPeerTLSListening:
+		return kubermaticv1.ReasonEtcdPeerTLSUpdatingPeerURLs
+	default:
+		return kubermaticv1.ReasonEtcdPeerTLSEnabled
+	}
+}
+
+// statefulSetRolledOut returns true if all pods of the StatefulSet run with the template
+// of the given migration step and are ready
+func statefulSetRolledOut(set *appsv1.StatefulSet, phase string) bool {
+	if set.Spec.Template.Annotations[peerTLSPhaseAnnotation] != phase {
+		return false
+	}
+	if set.Status.ObservedGeneration < set.Generation || set.Status.CurrentRevision != set.Status.UpdateRevision {
+		return false
+	}
+	replicas := int32(1)
+	if set.Spec.Replicas != nil {
+		replicas = *set.Spec.Replicas
+	}
+	return set.Status.UpdatedReplicas == replicas && set.Status.ReadyReplicas == replicas
+}
+
+// SetPeerTLSCondition sets the EtcdPeerTLSEnabled condition of the cluster to the given migration step
+func SetPeerTLSCondition(cluster *kubermaticv1.Cluster, phase string) {
+	status := corev1.ConditionFalse
+	var message string
+	switch phase {
+	case kubermaticv1.ReasonEtcdPeerTLSListening:
+		message = "etcd members are restarted to accept TLS peer connections"
+	case kubermaticv1.ReasonEtcdPeerTLSUpdatingPeerURLs:
+		message = "etcd members are restarted to advertise their TLS peer URLs"
+	case kubermaticv1.ReasonEtcdPeerTLSEnabled:
+		status = corev1.ConditionTrue
+		message = "etcd members only accept TLS peer connections"
+	}
+	kubermaticv1helper.SetClusterCondition(cluster, kubermaticv1.ClusterConditionEtcdPeerTLSEnabled, status, phase, message)
+}
+
+// GetPeerEndpoint returns the URL the given etcd member advertises for peer communication
+func GetPeerEndpoint(cluster *kubermaticv1.Cluster, member int) string {
+	scheme, port := "http", peerPort
+	if PeerTLSPhase(cluster) == kubermaticv1.ReasonEtcdPeerTLSEnabled {
+		scheme, port = "https", peerTLSPort
+	}
+	return fmt.Sprintf("%s://%s-%d.%s.%s.svc.cluster.local:%d", scheme, resources.EtcdStatefulSetName, member, resources.EtcdServiceName, cluster.Status.NamespaceName, port)
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2020 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etcd
+
+import (
+	"testing"
+
+	kubermaticv1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
+	"github.com/kubermatic/kubermatic/api/pkg/resources"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNextPeerTLSPhase(t *testing.T) {
+	genCluster := func(initialized bool, phase string) *kubermaticv1.Cluster {
+		cluster := &kubermaticv1.Cluster{}
+		if initialized {
+			cluster.Status.Conditions = append(cluster.Status.Conditions, kubermaticv1.ClusterCondition{
+				Type:   kubermaticv1.ClusterConditionClusterInitialized,
+				Status: corev1.ConditionTrue,
+			})
+		}
+		if phase != "" {
+			SetPeerTLSCondition(cluster, phase)
+		}
+		return cluster
+	}
+	genStatefulSet := func(phase string, rolledOut bool) *appsv1.StatefulSet {
+		set := &appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Generation: 2},
+			Spec: appsv1.StatefulSetSpec{
+				Replicas: resources.Int32(3),
+			},
+			Status: appsv1.StatefulSetStatus{
+				ObservedGeneration: 2,
+				CurrentRevision:    "etcd-1",
+				UpdateRevision:     "etcd-2",
+				ReadyReplicas:      3,
+				UpdatedReplicas:    1,
+			},
+		}
+		if phase != "" {
+			set.Spec.Template.Annotations = map[string]string{peerTLSPhaseAnnotation: phase}
+		}
+		if rolledOut {
+			set.Status.CurrentRevision = "etcd-2"
+			set.Status.UpdatedReplicas = 3
+		}
+		return set
+	}
+
+	testCases := []struct {
+		name          string
+		cluster       *kubermaticv1.Cluster
+		statefulSet   *appsv1.StatefulSet
+		expectedPhase string
+	}{
+		{
+			name:          "new cluster starts with TLS",
+			cluster:       genCluster(false, ""),
+			expectedPhase: kubermaticv1.ReasonEtcdPeerTLSEnabled,
+		},
+		{
+			name:          "existing cluster without StatefulSet gets migrated",
+			cluster:       genCluster(true, ""),
+			expectedPhase: kubermaticv1.ReasonEtcdPeerTLSListening,
+		},
+		{
+			name:          "existing cluster gets migrated",
+			cluster:       genCluster(true, ""),
+			statefulSet:   genStatefulSet("", true),
+			expectedPhase: kubermaticv1.ReasonEtcdPeerTLSListening,
+		},
+		{
+			name:          "waits for the listening step to be rolled out",
+			cluster:       genCluster(true, kubermaticv1.ReasonEtcdPeerTLSListening),
+			statefulSet:   genStatefulSet(kubermaticv1.ReasonEtcdPeerTLSListening, false),
+			expectedPhase: kubermaticv1.ReasonEtcdPeerTLSListening,
+		},
+		{
+			name:          "ignores a rolled out StatefulSet of the previous step",
+			cluster:       genCluster(true, kubermaticv1.ReasonEtcdPeerTLSListening),
+			statefulSet:   genStatefulSet("", true),
+			expectedPhase: kubermaticv1.ReasonEtcdPeerTLSListening,
+		},
+		{
+			name:          "updates the peer URLs after the listening step",
+			cluster:       genCluster(true, kubermaticv1.ReasonEtcdPeerTLSListening),
+			statefulSet:   genStatefulSet(kubermaticv1.ReasonEtcdPeerTLSListening, true),
+			expectedPhase: kubermaticv1.ReasonEtcdPeerTLSUpdatingPeerURLs,
+		},
+		{
+			name:          "waits for all peer URLs to be updated",
+			cluster:       genCluster(true, kubermaticv1.ReasonEtcdPeerTLSUpdatingPeerURLs),
+			statefulSet:   genStatefulSet(kubermaticv1.ReasonEtcdPeerTLSUpdatingPeerURLs, false),
+			expectedPhase: kubermaticv1.ReasonEtcdPeerTLSUpdatingPeerURLs,
+		},
+		{
+			name:          "enables TLS after all peer URLs got updated",
+			cluster:       genCluster(true, kubermaticv1.ReasonEtcdPeerTLSUpdatingPeerURLs),
+			statefulSet:   genStatefulSet(kubermaticv1.ReasonEtcdPeerTLSUpdatingPeerURLs, true),
+			expectedPhase: kubermaticv1.ReasonEtcdPeerTLSEnabled,
+		},
+		{
+			name:          "keeps the step while the StatefulSet is recreated",
+			cluster:       genCluster(true, kubermaticv1.ReasonEtcdPeerTLSUpdatingPeerURLs),
+			expectedPhase: kubermaticv1.ReasonEtcdPeerTLSUpdatingPeerURLs,
+		},
+		{
+			name:          "TLS stays enabled",
+			cluster:       genCluster(true, kubermaticv1.ReasonEtcdPeerTLSEnabled),
+			statefulSet:   genStatefulSet(kubermaticv1.ReasonEtcdPeerTLSEnabled, false),
+			expectedPhase: kubermaticv1.ReasonEtcdPeerTLSEnabled,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if phase := NextPeerTLSPhase(tc.cluster, tc.statefulSet); phase != tc.expectedPhase {
+				t.Errorf("expected phase %q, got %q", tc.expectedPhase, phase)
+			}
+		})
+	}
+}
+
+func TestGetPeerEndpoint(t *testing.T) {
+	cluster := &kubermaticv1.Cluster{}
+	cluster.Status.NamespaceName = "cluster-test"
+
+	if endpoint := GetPeerEndpoint(cluster, 1); endpoint != "http://etcd-1.etcd.cluster-test.svc.cluster.local:2380" {
+		t.Errorf("unexpected plaintext peer endpoint %q", endpoint)
+	}
+
+	SetPeerTLSCondition(cluster, kubermaticv1.ReasonEtcdPeerTLSEnabled)
+	if endpoint := GetPeerEndpoint(cluster, 1); endpoint != "https://etcd-1.etcd.cluster-test.svc.cluster.local:2381" {
+		t.Errorf("unexpected TLS peer endpoint %q", endpoint)
+	}
+}
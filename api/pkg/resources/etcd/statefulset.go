@@ -19,6 +19,8 @@ package etcd
 import (
 	"bytes"
 	"fmt"
+	"strconv"
+	"strings"
 	"text/template"
 
 	"github.com/Masterminds/sprig"
@@ -72,7 +74,18 @@ func StatefulSetCreator(data etcdStatefulSetCreatorData, enableDataCorruptionChe
 		return resources.EtcdStatefulSetName, func(set *appsv1.StatefulSet) (*appsv1.StatefulSet, error) {
 			set.Name = resources.EtcdStatefulSetName
 
-			set.Spec.Replicas = resources.Int32(resources.EtcdClusterSize)
+			// The size of an existing etcd gets changed one member at a time by ReconcileClusterSize
+			initialClusterSize := ClusterSize(data.Cluster())
+			if set.Spec.Replicas == nil {
+				set.Spec.Replicas = resources.Int32(int32(initialClusterSize))
+			} else {
+				initialClusterSize = getInitialClusterSize(set)
+			}
+			if set.Annotations == nil {
+				set.Annotations = map[string]string{}
+			}
+			set.Annotations[initialClusterSizeAnnotation] = strconv.Itoa(initialClusterSize)
+
 			set.Spec.UpdateStrategy.Type = appsv1.RollingUpdateStatefulSetStrategyType
 			set.Spec.PodManagementPolicy = appsv1.ParallelPodManagement
 			set.Spec.ServiceName = resources.EtcdServiceName
@@ -89,10 +102,14 @@ func StatefulSetCreator(data etcdStatefulSetCreatorData, enableDataCorruptionChe
 				return nil, fmt.Errorf("failed to create pod labels: %v", err)
 			}
 
+			peerTLSPhase := PeerTLSPhase(data.Cluster())
 			set.Spec.Template.ObjectMeta = metav1.ObjectMeta{
 				Name:   name,
 				Labels: podLabels,
 			}
+			if peerTLSPhase != "" {
+				set.Spec.Template.ObjectMeta.Annotations = map[string]string{peerTLSPhaseAnnotation: peerTLSPhase}
+			}
 
 			// For migration purpose.
 			// We switched from the etcd-operator to a simple etcd-StatefulSet. Therefore we need to migrate the data.
@@ -101,7 +118,7 @@ func StatefulSetCreator(data etcdStatefulSetCreatorData, enableDataCorruptionChe
 				return nil, fmt.Errorf("failed to check if we need to include the etcd-operator migration code: %v", err)
 			}
 
-			etcdStartCmd, err := getEtcdCommand(data.Cluster().Name, data.Cluster().Status.NamespaceName, migrate, enableDataCorruptionChecks)
+			etcdStartCmd, err := getEtcdCommand(data.Cluster().Name, data.Cluster().Status.NamespaceName, migrate, enableDataCorruptionChecks, peerTLSPhase, initialClusterSize)
 			if err != nil {
 				return nil, err
 			}
@@ -135,18 +152,7 @@ func StatefulSetCreator(data etcdStatefulSetCreatorData, enableDataCorruptionChe
 							Value: "3",
 						},
 					},
-					Ports: []corev1.ContainerPort{
-						{
-							ContainerPort: 2379,
-							Protocol:      corev1.ProtocolTCP,
-							Name:          "client",
-						},
-						{
-							ContainerPort: 2380,
-							Protocol:      corev1.ProtocolTCP,
-							Name:          "peer",
-						},
-					},
+					Ports: getPorts(peerTLSPhase),
 					ReadinessProbe: &corev1.Probe{
 						TimeoutSeconds:      10,
 						PeriodSeconds:       30,
@@ -155,14 +161,7 @@ func StatefulSetCreator(data etcdStatefulSetCreatorData, enableDataCorruptionChe
 						InitialDelaySeconds: 15,
 						Handler: corev1.Handler{
 							Exec: &corev1.ExecAction{
-								Command: []string{
-									"/usr/local/bin/etcdctl",
-									"--command-timeout", "10s",
-									"--cacert", "/etc/etcd/pki/ca/ca.crt",
-									"--cert", "/etc/etcd/pki/client/apiserver-etcd-client.crt",
-									"--key", "/etc/etcd/pki/client/apiserver-etcd-client.key",
-									"--endpoints", "https://127.0.0.1:2379", "endpoint", "health",
-								},
+								Command: getReadinessCommand(peerTLSPhase),
 							},
 						},
 					},
@@ -184,6 +183,16 @@ func StatefulSetCreator(data etcdStatefulSetCreatorData, enableDataCorruptionChe
 							MountPath: "/etc/etcd/pki/client",
 							ReadOnly:  true,
 						},
+						{
+							Name:      resources.EtcdPeerTLSCertificateSecretName,
+							MountPath: "/etc/etcd/pki/peer",
+							ReadOnly:  true,
+						},
+						{
+							Name:      resources.EtcdPeerCASecretName,
+							MountPath: "/etc/etcd/pki/peer-ca",
+							ReadOnly:  true,
+						},
 					},
 				},
 			}
@@ -259,9 +268,96 @@ func getVolumes() []corev1.Volume {
 				},
 			},
 		},
+		{
+			Name: resources.EtcdPeerTLSCertificateSecretName,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: resources.EtcdPeerTLSCertificateSecretName,
+				},
+			},
+		},
+		{
+			Name: resources.EtcdPeerCASecretName,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: resources.EtcdPeerCASecretName,
+					Items: []corev1.KeyToPath{
+						{
+							Path: resources.CACertSecretKey,
+							Key:  resources.CACertSecretKey,
+						},
+					},
+				},
+			},
+		},
 	}
 }
 
+// getPorts returns the container ports of etcd. The plaintext peer port is only
+// served until the peer TLS migration is finished.
+func getPorts(peerTLSPhase string) []corev1.ContainerPort {
+	ports := []corev1.ContainerPort{
+		{
+			ContainerPort: 2379,
+			Protocol:      corev1.ProtocolTCP,
+			Name:          "client",
+		},
+	}
+	if peerTLSPhase != kubermaticv1.ReasonEtcdPeerTLSEnabled {
+		ports = append(ports, corev1.ContainerPort{
+			ContainerPort: peerPort,
+			Protocol:      corev1.ProtocolTCP,
+			Name:          "peer",
+		})
+	}
+	if peerTLSPhase != "" {
+		ports = append(ports, corev1.ContainerPort{
+			ContainerPort: peerTLSPort,
+			Protocol:      corev1.ProtocolTCP,
+			Name:          "peer-tls",
+		})
+	}
+	return ports
+}
+
+func getReadinessCommand(peerTLSPhase string) []string {
+	etcdctl := []string{
+		"/usr/local/bin/etcdctl",
+		"--command-timeout", "10s",
+		"--cacert", "/etc/etcd/pki/ca/ca.crt",
+		"--cert", "/etc/etcd/pki/client/apiserver-etcd-client.crt",
+		"--key", "/etc/etcd/pki/client/apiserver-etcd-client.key",
+		"--endpoints", "https://127.0.0.1:2379",
+	}
+	if peerTLSPhase != kubermaticv1.ReasonEtcdPeerTLSUpdatingPeerURLs {
+		return append(etcdctl, "endpoint", "health")
+	}
+
+	// While the peer URLs get updated, a member only becomes ready once it advertises its TLS peer URL.
+	// This way the rolling update of the StatefulSet only finishes after all members got updated.
+	etcdctlCmd := strings.Join(etcdctl, " ")
+	return []string{
+		"/bin/sh",
+		"-ec",
+		fmt.Sprintf(`%s endpoint health && %s member list | grep -q ", ${POD_NAME}, https://"`, etcdctlCmd, etcdctlCmd),
+	}
+}
+
+// getInitialClusterSize returns the number of members the existing etcd StatefulSet was bootstrapped with.
+// Once the cluster got smaller than that, members with a higher ordinal join the running cluster when
+// they get added again.
+func getInitialClusterSize(set *appsv1.StatefulSet) int {
+	// StatefulSets created before the cluster size became configurable always started with the default size
+	size := resources.DefaultEtcdClusterSize
+	if value, err := strconv.Atoi(set.Annotations[initialClusterSizeAnnotation]); err == nil {
+		size = value
+	}
+	if replicas := int(*set.Spec.Replicas); replicas < size {
+		size = replicas
+	}
+	return size
+}
+
 func getBasePodLabels(cluster *kubermaticv1.Cluster) map[string]string {
 	additionalLabels := map[string]string{
 		"cluster": cluster.Name,
@@ -276,9 +372,24 @@ type commandTplData struct {
 	DataDir               string
 	Migrate               bool
 	EnableCorruptionCheck bool
+	PeerScheme            string
+	PeerPort              int
+	PeerTLSPort           int
+	ListenPeerURLs        string
+	PeerTLS               bool
+	UpdatePeerURLs        bool
+	InitialClusterSize    int
+	InitialCluster        string
+	ClusterEndpoints      string
 }
 
-func getEtcdCommand(name, namespace string, migrate, enableCorruptionCheck bool) ([]string, error) {
+// GetDataDir returns the path to the data directory of the given etcd member
+// inside the "data" volume.
+func GetDataDir(memberName string) string {
+	return strings.Replace(dataDir, "${POD_NAME}", memberName, 1)
+}
+
+func getEtcdCommand(name, namespace string, migrate, enableCorruptionCheck bool, peerTLSPhase string, initialClusterSize int) ([]string, error) {
 	tpl, err := template.New("base").Funcs(sprig.TxtFuncMap()).Parse(etcdStartCommandTpl)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse etcd command template: %v", err)
@@ -291,8 +402,33 @@ func getEtcdCommand(name, namespace string, migrate, enableCorruptionCheck bool)
 		DataDir:               dataDir,
 		Migrate:               migrate,
 		EnableCorruptionCheck: enableCorruptionCheck,
+		PeerScheme:            "http",
+		PeerPort:              peerPort,
+		PeerTLSPort:           peerTLSPort,
+		ListenPeerURLs:        fmt.Sprintf("http://${POD_IP}:%d", peerPort),
+		PeerTLS:               peerTLSPhase != "",
+		UpdatePeerURLs:        peerTLSPhase == kubermaticv1.ReasonEtcdPeerTLSUpdatingPeerURLs,
+		InitialClusterSize:    initialClusterSize,
+	}
+	switch peerTLSPhase {
+	case kubermaticv1.ReasonEtcdPeerTLSListening, kubermaticv1.ReasonEtcdPeerTLSUpdatingPeerURLs:
+		tplData.ListenPeerURLs = fmt.Sprintf("http://${POD_IP}:%d,https://${POD_IP}:%d", peerPort, peerTLSPort)
+	case kubermaticv1.ReasonEtcdPeerTLSEnabled:
+		tplData.PeerScheme = "https"
+		tplData.PeerPort = peerTLSPort
+		tplData.ListenPeerURLs = fmt.Sprintf("https://${POD_IP}:%d", peerTLSPort)
 	}
 
+	var initialCluster, endpoints []string
+	for i := 0; i < resources.MaxEtcdClusterSize; i++ {
+		if i < initialClusterSize {
+			initialCluster = append(initialCluster, fmt.Sprintf("%s=%s://%s.%s.%s.svc.cluster.local:%d", memberName(i), tplData.PeerScheme, memberName(i), resources.EtcdServiceName, namespace, tplData.PeerPort))
+		}
+		endpoints = append(endpoints, clientEndpoint(namespace, i))
+	}
+	tplData.InitialCluster = strings.Join(initialCluster, ",")
+	tplData.ClusterEndpoints = strings.Join(endpoints, ",")
+
 	buf := bytes.Buffer{}
 	if err := tpl.Execute(&buf, tplData); err != nil {
 		return nil, err
@@ -314,13 +450,13 @@ if [ -d "{{ .DataDir }}" ]; then
     echo "we're already initialized"
     export INITIAL_STATE="existing"
     if [ "${POD_NAME}" = "etcd-0" ]; then
-        export INITIAL_CLUSTER="etcd-0=http://etcd-0.{{ .ServiceName }}.{{ .Namespace }}.svc.cluster.local:2380"
+        export INITIAL_CLUSTER="etcd-0={{ .PeerScheme }}://etcd-0.{{ .ServiceName }}.{{ .Namespace }}.svc.cluster.local:{{ .PeerPort }}"
     fi
     if [ "${POD_NAME}" = "etcd-1" ]; then
-        export INITIAL_CLUSTER="etcd-0=http://etcd-0.{{ .ServiceName }}.{{ .Namespace }}.svc.cluster.local:2380,etcd-1=http://etcd-1.{{ .ServiceName }}.{{ .Namespace }}.svc.cluster.local:2380"
+        export INITIAL_CLUSTER="etcd-0={{ .PeerScheme }}://etcd-0.{{ .ServiceName }}.{{ .Namespace }}.svc.cluster.local:{{ .PeerPort }},etcd-1={{ .PeerScheme }}://etcd-1.{{ .ServiceName }}.{{ .Namespace }}.svc.cluster.local:{{ .PeerPort }}"
     fi
     if [ "${POD_NAME}" = "etcd-2" ]; then
-        export INITIAL_CLUSTER="etcd-0=http://etcd-0.{{ .ServiceName }}.{{ .Namespace }}.svc.cluster.local:2380,etcd-1=http://etcd-1.{{ .ServiceName }}.{{ .Namespace }}.svc.cluster.local:2380,etcd-2=http://etcd-2.{{ .ServiceName }}.{{ .Namespace }}.svc.cluster.local:2380"
+        export INITIAL_CLUSTER="etcd-0={{ .PeerScheme }}://etcd-0.{{ .ServiceName }}.{{ .Namespace }}.svc.cluster.local:{{ .PeerPort }},etcd-1={{ .PeerScheme }}://etcd-1.{{ .ServiceName }}.{{ .Namespace }}.svc.cluster.local:{{ .PeerPort }},etcd-2={{ .PeerScheme }}://etcd-2.{{ .ServiceName }}.{{ .Namespace }}.svc.cluster.local:{{ .PeerPort }}"
     fi
 else
     if [ "${POD_NAME}" = "etcd-0" ]; then
@@ -329,40 +465,76 @@ else
         etcdctl snapshot restore snapshot.db \
             --name etcd-0 \
             --data-dir="{{ .DataDir }}" \
-            --initial-cluster="etcd-0=http://etcd-0.{{ .ServiceName }}.{{ .Namespace }}.svc.cluster.local:2380" \
+            --initial-cluster="etcd-0={{ .PeerScheme }}://etcd-0.{{ .ServiceName }}.{{ .Namespace }}.svc.cluster.local:{{ .PeerPort }}" \
             --initial-cluster-token="{{ .Token }}" \
-            --initial-advertise-peer-urls http://etcd-0.{{ .ServiceName }}.{{ .Namespace }}.svc.cluster.local:2380
+            --initial-advertise-peer-urls {{ .PeerScheme }}://etcd-0.{{ .ServiceName }}.{{ .Namespace }}.svc.cluster.local:{{ .PeerPort }}
         echo "restored from snapshot"
         export INITIAL_STATE="new"
-        export INITIAL_CLUSTER="etcd-0=http://etcd-0.{{ .ServiceName }}.{{ .Namespace }}.svc.cluster.local:2380"
+        export INITIAL_CLUSTER="etcd-0={{ .PeerScheme }}://etcd-0.{{ .ServiceName }}.{{ .Namespace }}.svc.cluster.local:{{ .PeerPort }}"
     fi
 
     export ETCD_CERT_ARGS="--cacert /etc/etcd/pki/ca/ca.crt --cert /etc/etcd/pki/client/apiserver-etcd-client.crt --key /etc/etcd/pki/client/apiserver-etcd-client.key"
     if [ "${POD_NAME}" = "etcd-1" ]; then
         echo "i'm etcd-1. I join as new member as soon as etcd-0 comes up"
-        etcdctl ${ETCD_CERT_ARGS} --endpoints ${MASTER_ENDPOINT} member add etcd-1 --peer-urls=http://etcd-1.{{ .ServiceName }}.{{ .Namespace }}.svc.cluster.local:2380
+        etcdctl ${ETCD_CERT_ARGS} --endpoints ${MASTER_ENDPOINT} member add etcd-1 --peer-urls={{ .PeerScheme }}://etcd-1.{{ .ServiceName }}.{{ .Namespace }}.svc.cluster.local:{{ .PeerPort }}
         echo "added etcd-1 to members"
         export INITIAL_STATE="existing"
-        export INITIAL_CLUSTER="etcd-0=http://etcd-0.{{ .ServiceName }}.{{ .Namespace }}.svc.cluster.local:2380,etcd-1=http://etcd-1.{{ .ServiceName }}.{{ .Namespace }}.svc.cluster.local:2380"
+        export INITIAL_CLUSTER="etcd-0={{ .PeerScheme }}://etcd-0.{{ .ServiceName }}.{{ .Namespace }}.svc.cluster.local:{{ .PeerPort }},etcd-1={{ .PeerScheme }}://etcd-1.{{ .ServiceName }}.{{ .Namespace }}.svc.cluster.local:{{ .PeerPort }}"
     fi
 
     if [ "${POD_NAME}" = "etcd-2" ]; then
         echo "i'm etcd-2. I join as new member as soon as we have 2 existing & healthy members"
         until etcdctl ${ETCD_CERT_ARGS} --endpoints ${MASTER_ENDPOINT} member list | grep -q etcd-1; do sleep 1; echo "Waiting for etcd-1"; done
-        etcdctl ${ETCD_CERT_ARGS} --endpoints ${MASTER_ENDPOINT} member add etcd-2 --peer-urls=http://etcd-2.{{ .ServiceName }}.{{ .Namespace }}.svc.cluster.local:2380
+        etcdctl ${ETCD_CERT_ARGS} --endpoints ${MASTER_ENDPOINT} member add etcd-2 --peer-urls={{ .PeerScheme }}://etcd-2.{{ .ServiceName }}.{{ .Namespace }}.svc.cluster.local:{{ .PeerPort }}
         echo "added etcd-2 to members"
         export INITIAL_STATE="existing"
-        export INITIAL_CLUSTER="etcd-0=http://etcd-0.{{ .ServiceName }}.{{ .Namespace }}.svc.cluster.local:2380,etcd-1=http://etcd-1.{{ .ServiceName }}.{{ .Namespace }}.svc.cluster.local:2380,etcd-2=http://etcd-2.{{ .ServiceName }}.{{ .Namespace }}.svc.cluster.local:2380"
+        export INITIAL_CLUSTER="etcd-0={{ .PeerScheme }}://etcd-0.{{ .ServiceName }}.{{ .Namespace }}.svc.cluster.local:{{ .PeerPort }},etcd-1={{ .PeerScheme }}://etcd-1.{{ .ServiceName }}.{{ .Namespace }}.svc.cluster.local:{{ .PeerPort }},etcd-2={{ .PeerScheme }}://etcd-2.{{ .ServiceName }}.{{ .Namespace }}.svc.cluster.local:{{ .PeerPort }}"
     fi
 fi
 
 {{ else }}
-export INITIAL_STATE="new"
-export INITIAL_CLUSTER="etcd-0=http://etcd-0.{{ .ServiceName }}.{{ .Namespace }}.svc.cluster.local:2380,etcd-1=http://etcd-1.{{ .ServiceName }}.{{ .Namespace }}.svc.cluster.local:2380,etcd-2=http://etcd-2.{{ .ServiceName }}.{{ .Namespace }}.svc.cluster.local:2380"
+if [ -d "{{ .DataDir }}" ]; then
+    echo "we're already initialized"
+    export INITIAL_STATE="existing"
+    export INITIAL_CLUSTER="${POD_NAME}={{ .PeerScheme }}://${POD_NAME}.{{ .ServiceName }}.{{ .Namespace }}.svc.cluster.local:{{ .PeerPort }}"
+elif [ "${POD_NAME##*-}" -lt {{ .InitialClusterSize }} ]; then
+    export INITIAL_STATE="new"
+    export INITIAL_CLUSTER="{{ .InitialCluster }}"
+else
+    echo "i'm ${POD_NAME}. I join the running cluster as new member"
+    export ETCD_CERT_ARGS="--cacert /etc/etcd/pki/ca/ca.crt --cert /etc/etcd/pki/client/apiserver-etcd-client.crt --key /etc/etcd/pki/client/apiserver-etcd-client.key --endpoints {{ .ClusterEndpoints }}"
+    until etcdctl ${ETCD_CERT_ARGS} member list > /tmp/members; do sleep 2; echo "Waiting for the etcd cluster"; done
+    # The member might already have been added before this pod got restarted
+    if ! grep -q "://${POD_NAME}\." /tmp/members; then
+        etcdctl ${ETCD_CERT_ARGS} member add ${POD_NAME} --peer-urls={{ .PeerScheme }}://${POD_NAME}.{{ .ServiceName }}.{{ .Namespace }}.svc.cluster.local:{{ .PeerPort }}
+        echo "added ${POD_NAME} to members"
+        etcdctl ${ETCD_CERT_ARGS} member list > /tmp/members
+    fi
+    export INITIAL_STATE="existing"
+    INITIAL_CLUSTER=""
+    while IFS=', ' read -r MEMBER_ID MEMBER_STATUS MEMBER_NAME MEMBER_PEER_URL REST; do
+        # Members which did not start yet have no name, so it is taken from the peer URL
+        MEMBER_NAME=${MEMBER_PEER_URL#*://}
+        INITIAL_CLUSTER="${INITIAL_CLUSTER:+${INITIAL_CLUSTER},}${MEMBER_NAME%%.*}=${MEMBER_PEER_URL}"
+    done < /tmp/members
+    export INITIAL_CLUSTER
+fi
 {{ end }}
 
 echo "initial-state: ${INITIAL_STATE}"
 echo "initial-cluster: ${INITIAL_CLUSTER}"
+{{- if .UpdatePeerURLs }}
+
+# All members accept TLS peer connections by now. As soon as this member is up,
+# it replaces its advertised plaintext peer URL with the TLS one.
+(
+    export ETCD_CERT_ARGS="--cacert /etc/etcd/pki/ca/ca.crt --cert /etc/etcd/pki/client/apiserver-etcd-client.crt --key /etc/etcd/pki/client/apiserver-etcd-client.key --endpoints https://127.0.0.1:2379"
+    until etcdctl ${ETCD_CERT_ARGS} endpoint health; do sleep 2; echo "Waiting for ${POD_NAME} to become healthy"; done
+    export MEMBER_ID=$(etcdctl ${ETCD_CERT_ARGS} member list | grep ", ${POD_NAME}, " | cut -d, -f1)
+    until etcdctl ${ETCD_CERT_ARGS} member update ${MEMBER_ID} --peer-urls="https://${POD_NAME}.{{ .ServiceName }}.{{ .Namespace }}.svc.cluster.local:{{ .PeerTLSPort }}"; do sleep 2; done
+    echo "updated the peer URL of ${POD_NAME}"
+) &
+{{- end }}
 
 exec /usr/local/bin/etcd \
     --name=${POD_NAME} \
@@ -372,12 +544,18 @@ exec /usr/local/bin/etcd \
     --initial-cluster-state=${INITIAL_STATE} \
     --advertise-client-urls "https://${POD_NAME}.{{ .ServiceName }}.{{ .Namespace }}.svc.cluster.local:2379,https://${POD_IP}:2379" \
     --listen-client-urls "https://${POD_IP}:2379,https://127.0.0.1:2379" \
-    --listen-peer-urls "http://${POD_IP}:2380" \
-    --initial-advertise-peer-urls "http://${POD_NAME}.{{ .ServiceName }}.{{ .Namespace }}.svc.cluster.local:2380" \
+    --listen-peer-urls "{{ .ListenPeerURLs }}" \
+    --initial-advertise-peer-urls "{{ .PeerScheme }}://${POD_NAME}.{{ .ServiceName }}.{{ .Namespace }}.svc.cluster.local:{{ .PeerPort }}" \
     --trusted-ca-file /etc/etcd/pki/ca/ca.crt \
     --client-cert-auth \
     --cert-file /etc/etcd/pki/tls/etcd-tls.crt \
     --key-file /etc/etcd/pki/tls/etcd-tls.key \
+{{- if .PeerTLS }}
+    --peer-trusted-ca-file /etc/etcd/pki/peer-ca/ca.crt \
+    --peer-client-cert-auth \
+    --peer-cert-file /etc/etcd/pki/peer/${POD_NAME}-peer.crt \
+    --peer-key-file /etc/etcd/pki/peer/${POD_NAME}-peer.key \
+{{- end }}
 {{- if .EnableCorruptionCheck }}
     --experimental-initial-corrupt-check=true \
     --experimental-corrupt-check-time=10m \
@@ -38,31 +38,64 @@ func TestGetEtcdCommand(t *testing.T) {
 		clusterNamespace      string
 		migrate               bool
 		enableCorruptionCheck bool
+		peerTLSPhase          string
+		initialClusterSize    int
 	}{
 		{
-			name:             "no-migration",
-			clusterName:      "lg69pmx8wf",
-			clusterNamespace: "cluster-lg69pmx8wf",
-			migrate:          false,
+			name:               "no-migration",
+			clusterName:        "lg69pmx8wf",
+			clusterNamespace:   "cluster-lg69pmx8wf",
+			initialClusterSize: 3,
+			migrate:            false,
 		},
 		{
-			name:             "with-migration",
-			clusterName:      "62m9k9tqlm",
-			clusterNamespace: "cluster-62m9k9tqlm",
-			migrate:          true,
+			name:               "with-migration",
+			clusterName:        "62m9k9tqlm",
+			clusterNamespace:   "cluster-62m9k9tqlm",
+			initialClusterSize: 3,
+			migrate:            true,
 		},
 		{
 			name:                  "with-corruption-flags",
 			clusterName:           "lg69pmx8wf",
 			clusterNamespace:      "cluster-lg69pmx8wf",
+			initialClusterSize:    3,
 			migrate:               false,
 			enableCorruptionCheck: true,
 		},
+		{
+			name:               "peer-tls-listening",
+			clusterName:        "lg69pmx8wf",
+			clusterNamespace:   "cluster-lg69pmx8wf",
+			initialClusterSize: 3,
+			peerTLSPhase:       kubermaticv1.ReasonEtcdPeerTLSListening,
+		},
+		{
+			name:               "peer-tls-updating-peer-urls",
+			clusterName:        "lg69pmx8wf",
+			clusterNamespace:   "cluster-lg69pmx8wf",
+			initialClusterSize: 3,
+			peerTLSPhase:       kubermaticv1.ReasonEtcdPeerTLSUpdatingPeerURLs,
+		},
+		{
+			name:               "peer-tls-enabled",
+			clusterName:        "lg69pmx8wf",
+			clusterNamespace:   "cluster-lg69pmx8wf",
+			initialClusterSize: 3,
+			peerTLSPhase:       kubermaticv1.ReasonEtcdPeerTLSEnabled,
+		},
+		{
+			name:               "single-member",
+			clusterName:        "lg69pmx8wf",
+			clusterNamespace:   "cluster-lg69pmx8wf",
+			initialClusterSize: 1,
+			peerTLSPhase:       kubermaticv1.ReasonEtcdPeerTLSEnabled,
+		},
 	}
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			args, err := getEtcdCommand(test.clusterName, test.clusterNamespace, test.migrate, test.enableCorruptionCheck)
+			args, err := getEtcdCommand(test.clusterName, test.clusterNamespace, test.migrate, test.enableCorruptionCheck, test.peerTLSPhase, test.initialClusterSize)
 			if err != nil {
 				t.Fatalf("unexpected error: %v", err)
 			}
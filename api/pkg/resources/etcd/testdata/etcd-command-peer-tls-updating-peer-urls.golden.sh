@@ -0,0 +1,63 @@
+export MASTER_ENDPOINT="https://etcd-0.etcd.cluster-lg69pmx8wf.svc.cluster.local:2379"
+
+
+if [ -d "/var/run/etcd/pod_${POD_NAME}/" ]; then
+    echo "we're already initialized"
+    export INITIAL_STATE="existing"
+    export INITIAL_CLUSTER="${POD_NAME}=http://${POD_NAME}.etcd.cluster-lg69pmx8wf.svc.cluster.local:2380"
+elif [ "${POD_NAME##*-}" -lt 3 ]; then
+    export INITIAL_STATE="new"
+    export INITIAL_CLUSTER="etcd-0=http://etcd-0.etcd.cluster-lg69pmx8wf.svc.cluster.local:2380,etcd-1=http://etcd-1.etcd.cluster-lg69pmx8wf.svc.cluster.local:2380,etcd-2=http://etcd-2.etcd.cluster-lg69pmx8wf.svc.cluster.local:2380"
+else
+    echo "i'm ${POD_NAME}. I join the running cluster as new member"
+    export ETCD_CERT_ARGS="--cacert /etc/etcd/pki/ca/ca.crt --cert /etc/etcd/pki/client/apiserver-etcd-client.crt --key /etc/etcd/pki/client/apiserver-etcd-client.key --endpoints https://etcd-0.etcd.cluster-lg69pmx8wf.svc.cluster.local.:2379,https://etcd-1.etcd.cluster-lg69pmx8wf.svc.cluster.local.:2379,https://etcd-2.etcd.cluster-lg69pmx8wf.svc.cluster.local.:2379,https://etcd-3.etcd.cluster-lg69pmx8wf.svc.cluster.local.:2379,https://etcd-4.etcd.cluster-lg69pmx8wf.svc.cluster.local.:2379,https://etcd-5.etcd.cluster-lg69pmx8wf.svc.cluster.local.:2379,https://etcd-6.etcd.cluster-lg69pmx8wf.svc.cluster.local.:2379"
+    until etcdctl ${ETCD_CERT_ARGS} member list > /tmp/members; do sleep 2; echo "Waiting for the etcd cluster"; done
+    # The member might already have been added before this pod got restarted
+    if ! grep -q "://${POD_NAME}\." /tmp/members; then
+        etcdctl ${ETCD_CERT_ARGS} member add ${POD_NAME} --peer-urls=http://${POD_NAME}.etcd.cluster-lg69pmx8wf.svc.cluster.local:2380
+        echo "added ${POD_NAME} to members"
+        etcdctl ${ETCD_CERT_ARGS} member list > /tmp/members
+    fi
+    export INITIAL_STATE="existing"
+    INITIAL_CLUSTER=""
+    while IFS=', ' read -r MEMBER_ID MEMBER_STATUS MEMBER_NAME MEMBER_PEER_URL REST; do
+        # Members which did not start yet have no name, so it is taken from the peer URL
+        MEMBER_NAME=${MEMBER_PEER_URL#*://}
+        INITIAL_CLUSTER="${INITIAL_CLUSTER:+${INITIAL_CLUSTER},}${MEMBER_NAME%%.*}=${MEMBER_PEER_URL}"
+    done < /tmp/members
+    export INITIAL_CLUSTER
+fi
+
+
+echo "initial-state: ${INITIAL_STATE}"
+echo "initial-cluster: ${INITIAL_CLUSTER}"
+
+# All members accept TLS peer connections by now. As soon as this member is up,
+# it replaces its advertised plaintext peer URL with the TLS one.
+(
+    export ETCD_CERT_ARGS="--cacert /etc/etcd/pki/ca/ca.crt --cert /etc/etcd/pki/client/apiserver-etcd-client.crt --key /etc/etcd/pki/client/apiserver-etcd-client.key --endpoints https://127.0.0.1:2379"
+    until etcdctl ${ETCD_CERT_ARGS} endpoint health; do sleep 2; echo "Waiting for ${POD_NAME} to become healthy"; done
+    export MEMBER_ID=$(etcdctl ${ETCD_CERT_ARGS} member list | grep ", ${POD_NAME}, " | cut -d, -f1)
+    until etcdctl ${ETCD_CERT_ARGS} member update ${MEMBER_ID} --peer-urls="https://${POD_NAME}.etcd.cluster-lg69pmx8wf.svc.cluster.local:2381"; do sleep 2; done
+    echo "updated the peer URL of ${POD_NAME}"
+) &
+
+exec /usr/local/bin/etcd \
+    --name=${POD_NAME} \
+    --data-dir="/var/run/etcd/pod_${POD_NAME}/" \
+    --initial-cluster=${INITIAL_CLUSTER} \
+    --initial-cluster-token="lg69pmx8wf" \
+    --initial-cluster-state=${INITIAL_STATE} \
+    --advertise-client-urls "https://${POD_NAME}.etcd.cluster-lg69pmx8wf.svc.cluster.local:2379,https://${POD_IP}:2379" \
+    --listen-client-urls "https://${POD_IP}:2379,https://127.0.0.1:2379" \
+    --listen-peer-urls "http://${POD_IP}:2380,https://${POD_IP}:2381" \
+    --initial-advertise-peer-urls "http://${POD_NAME}.etcd.cluster-lg69pmx8wf.svc.cluster.local:2380" \
+    --trusted-ca-file /etc/etcd/pki/ca/ca.crt \
+    --client-cert-auth \
+    --cert-file /etc/etcd/pki/tls/etcd-tls.crt \
+    --key-file /etc/etcd/pki/tls/etcd-tls.key \
+    --peer-trusted-ca-file /etc/etcd/pki/peer-ca/ca.crt \
+    --peer-client-cert-auth \
+    --peer-cert-file /etc/etcd/pki/peer/${POD_NAME}-peer.crt \
+    --peer-key-file /etc/etcd/pki/peer/${POD_NAME}-peer.key \
+    --auto-compaction-retention=8
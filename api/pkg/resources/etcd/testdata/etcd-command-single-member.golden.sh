@@ -0,0 +1,53 @@
+export MASTER_ENDPOINT="https://etcd-0.etcd.cluster-lg69pmx8wf.svc.cluster.local:2379"
+
+
+if [ -d "/var/run/etcd/pod_${POD_NAME}/" ]; then
+    echo "we're already initialized"
+    export INITIAL_STATE="existing"
+    export INITIAL_CLUSTER="${POD_NAME}=https://${POD_NAME}.etcd.cluster-lg69pmx8wf.svc.cluster.local:2381"
+elif [ "${POD_NAME##*-}" -lt 1 ]; then
+    export INITIAL_STATE="new"
+    export INITIAL_CLUSTER="etcd-0=https://etcd-0.etcd.cluster-lg69pmx8wf.svc.cluster.local:2381"
+else
+    echo "i'm ${POD_NAME}. I join the running cluster as new member"
+    export ETCD_CERT_ARGS="--cacert /etc/etcd/pki/ca/ca.crt --cert /etc/etcd/pki/client/apiserver-etcd-client.crt --key /etc/etcd/pki/client/apiserver-etcd-client.key --endpoints https://etcd-0.etcd.cluster-lg69pmx8wf.svc.cluster.local.:2379,https://etcd-1.etcd.cluster-lg69pmx8wf.svc.cluster.local.:2379,https://etcd-2.etcd.cluster-lg69pmx8wf.svc.cluster.local.:2379,https://etcd-3.etcd.cluster-lg69pmx8wf.svc.cluster.local.:2379,https://etcd-4.etcd.cluster-lg69pmx8wf.svc.cluster.local.:2379,https://etcd-5.etcd.cluster-lg69pmx8wf.svc.cluster.local.:2379,https://etcd-6.etcd.cluster-lg69pmx8wf.svc.cluster.local.:2379"
+    until etcdctl ${ETCD_CERT_ARGS} member list > /tmp/members; do sleep 2; echo "Waiting for the etcd cluster"; done
+    # The member might already have been added before this pod got restarted
+    if ! grep -q "://${POD_NAME}\." /tmp/members; then
+        etcdctl ${ETCD_CERT_ARGS} member add ${POD_NAME} --peer-urls=https://${POD_NAME}.etcd.cluster-lg69pmx8wf.svc.cluster.local:2381
+        echo "added ${POD_NAME} to members"
+        etcdctl ${ETCD_CERT_ARGS} member list > /tmp/members
+    fi
+    export INITIAL_STATE="existing"
+    INITIAL_CLUSTER=""
+    while IFS=', ' read -r MEMBER_ID MEMBER_STATUS MEMBER_NAME MEMBER_PEER_URL REST; do
+        # Members which did not start yet have no name, so it is taken from the peer URL
+        MEMBER_NAME=${MEMBER_PEER_URL#*://}
+        INITIAL_CLUSTER="${INITIAL_CLUSTER:+${INITIAL_CLUSTER},}${MEMBER_NAME%%.*}=${MEMBER_PEER_URL}"
+    done < /tmp/members
+    export INITIAL_CLUSTER
+fi
+
+
+echo "initial-state: ${INITIAL_STATE}"
+echo "initial-cluster: ${INITIAL_CLUSTER}"
+
+exec /usr/local/bin/etcd \
+    --name=${POD_NAME} \
+    --data-dir="/var/run/etcd/pod_${POD_NAME}/" \
+    --initial-cluster=${INITIAL_CLUSTER} \
+    --initial-cluster-token="lg69pmx8wf" \
+    --initial-cluster-state=${INITIAL_STATE} \
+    --advertise-client-urls "https://${POD_NAME}.etcd.cluster-lg69pmx8wf.svc.cluster.local:2379,https://${POD_IP}:2379" \
+    --listen-client-urls "https://${POD_IP}:2379,https://127.0.0.1:2379" \
+    --listen-peer-urls "https://${POD_IP}:2381" \
+    --initial-advertise-peer-urls "https://${POD_NAME}.etcd.cluster-lg69pmx8wf.svc.cluster.local:2381" \
+    --trusted-ca-file /etc/etcd/pki/ca/ca.crt \
+    --client-cert-auth \
+    --cert-file /etc/etcd/pki/tls/etcd-tls.crt \
+    --key-file /etc/etcd/pki/tls/etcd-tls.key \
+    --peer-trusted-ca-file /etc/etcd/pki/peer-ca/ca.crt \
+    --peer-client-cert-auth \
+    --peer-cert-file /etc/etcd/pki/peer/${POD_NAME}-peer.crt \
+    --peer-key-file /etc/etcd/pki/peer/${POD_NAME}-peer.key \
+    --auto-compaction-retention=8
@@ -50,15 +50,19 @@ func DeepEqual(a, b metav1.Object) bool {
 		return true
 	}
 
-	// For informational purpose we use deep.equal as it tells us what the difference is.
+	kubermaticlog.Logger.Debugw("Object differs from generated one", "type", fmt.Sprintf("%T", a), "namespace", a.GetNamespace(), "name", a.GetName(), "diff", diff(a, b))
+	return false
+}
+
+// diff returns the differences between both objects for informational purpose,
+// it must only be used for objects that are known to differ.
+func diff(a, b metav1.Object) []string {
 	// We need to calculate the difference in both ways as deep.equal only does a one-way comparison
-	diff := deep.Equal(a, b)
-	if diff == nil {
-		diff = deep.Equal(b, a)
+	result := deep.Equal(a, b)
+	if result == nil {
+		result = deep.Equal(b, a)
 	}
-
-	kubermaticlog.Logger.Debugw("Object differs from generated one", "type", fmt.Sprintf("%T", a), "namespace", a.GetNamespace(), "name", a.GetName(), "diff", diff)
-	return false
+	return result
 }
 
 func jsonEqual(a, b interface{}) bool {
@@ -0,0 +1,283 @@
+/*
+Copyright 2020 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconciling
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// DryRunAction is the kind of change a reconciliation would have made to an object
+type DryRunAction string
+
+const (
+	DryRunActionCreate   DryRunAction = "create"
+	DryRunActionUpdate   DryRunAction = "update"
+	DryRunActionRecreate DryRunAction = "recreate"
+	DryRunActionPatch    DryRunAction = "patch"
+	DryRunActionDelete   DryRunAction = "delete"
+)
+
+// dryRunActions is the order in which the actions are summarized
+var dryRunActions = []DryRunAction{DryRunActionCreate, DryRunActionUpdate, DryRunActionRecreate, DryRunActionPatch, DryRunActionDelete}
+
+// ObjectChange is a change a reconciliation would have made to an object
+type ObjectChange struct {
+	Action      DryRunAction `json:"action"`
+	Kind        string       `json:"kind"`
+	Namespace   string       `json:"namespace,omitempty"`
+	Name        string       `json:"name"`
+	Subresource string       `json:"subresource,omitempty"`
+	// Diff holds the differences between the live and the generated object for updates and
+	// the patch for patches
+	Diff []string `json:"diff,omitempty"`
+}
+
+// DryRunReport collects the changes of a reconciliation that has been run with a DryRunClient
+type DryRunReport struct {
+	lock    sync.Mutex
+	changes []ObjectChange
+}
+
+// NewDryRunReport returns an empty DryRunReport
+func NewDryRunReport() *DryRunReport {
+	return &DryRunReport{}
+}
+
+func (r *DryRunReport) record(action DryRunAction, obj runtime.Object, subresource string, diff []string) {
+	change := ObjectChange{
+		Action:      action,
+		Kind:        kindOf(obj),
+		Subresource: subresource,
+		Diff:        diff,
+	}
+	if metaObj, ok := obj.(metav1.Object); ok {
+		change.Namespace = metaObj.GetNamespace()
+		change.Name = metaObj.GetName()
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.changes = append(r.changes, change)
+}
+
+// Changes returns the changes in the order they would have been made
+func (r *DryRunReport) Changes() []ObjectChange {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return append([]ObjectChange{}, r.changes...)
+}
+
+// Summary returns a short description of the changes that fits into an event, e.g. "create: 1, update: 3"
+func (r *DryRunReport) Summary() string {
+	counts := map[DryRunAction]int{}
+	for _, change := range r.Changes() {
+		counts[change.Action]++
+	}
+
+	parts := []string{}
+	for _, action := range dryRunActions {
+		if counts[action] > 0 {
+			parts = append(parts, fmt.Sprintf("%s: %d", action, counts[action]))
+		}
+	}
+	if len(parts) == 0 {
+		return "no changes"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// WriteFile writes the report of the named object (e.g. a cluster or a seed) as JSON to <dir>/<name>.json,
+// replacing the report of the previous reconciliation.
+func (r *DryRunReport) WriteFile(dir, name string) error {
+	data, err := json.MarshalIndent(struct {
+		Name      string         `json:"name"`
+		Timestamp time.Time      `json:"timestamp"`
+		Changes   []ObjectChange `json:"changes"`
+	}{
+		Name:      name,
+		Timestamp: time.Now().UTC(),
+		Changes:   r.Changes(),
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal the report: %v", err)
+	}
+
+	// write to a temporary file first so readers never see a partial report
+	tmpFile, err := ioutil.TempFile(dir, name)
+	if err != nil {
+		return fmt.Errorf("failed to create the report file: %v", err)
+	}
+	defer os.Remove(tmpFile.Name())
+
+	if _, err := tmpFile.Write(data); err != nil {
+		tmpFile.Close()
+		return fmt.Errorf("failed to write the report file: %v", err)
+	}
+	if err := tmpFile.Close(); err != nil {
+		return fmt.Errorf("failed to write the report file: %v", err)
+	}
+	return os.Rename(tmpFile.Name(), filepath.Join(dir, name+".json"))
+}
+
+// DryRunClient is a client that reads from the wrapped client but records all writes in its
+// report instead of sending them. When it is passed to the Reconcile* functions, the generated
+// objects are compared with the live ones and only the differences are recorded.
+// The objects that would have been created or updated are returned by Get afterwards, so creators
+// depending on objects of earlier steps (e.g. a certificate on its CA) work as well. Lists and
+// deletions are not reflected.
+type DryRunClient struct {
+	ctrlruntimeclient.Client
+	report *DryRunReport
+
+	lock    sync.Mutex
+	written map[string]runtime.Object
+}
+
+var _ ctrlruntimeclient.Client = &DryRunClient{}
+
+// NewDryRunClient returns a client that records all writes in the given report
+func NewDryRunClient(client ctrlruntimeclient.Client, report *DryRunReport) *DryRunClient {
+	return &DryRunClient{Client: client, report: report, written: map[string]runtime.Object{}}
+}
+
+// Get returns the object as it would have been written during the dry-run or the live object
+func (c *DryRunClient) Get(ctx context.Context, key ctrlruntimeclient.ObjectKey, obj runtime.Object) error {
+	c.lock.Lock()
+	written, ok := c.written[writtenKey(obj, key.Namespace, key.Name)]
+	c.lock.Unlock()
+	if ok {
+		reflect.ValueOf(obj).Elem().Set(reflect.ValueOf(written.DeepCopyObject()).Elem())
+		return nil
+	}
+	return c.Client.Get(ctx, key, obj)
+}
+
+// Create records the creation of the object
+func (c *DryRunClient) Create(ctx context.Context, obj runtime.Object, opts ...ctrlruntimeclient.CreateOption) error {
+	c.recordWrite(DryRunActionCreate, obj, "", nil)
+	return nil
+}
+
+// Update records the differences between the live and the given object
+func (c *DryRunClient) Update(ctx context.Context, obj runtime.Object, opts ...ctrlruntimeclient.UpdateOption) error {
+	c.recordWrite(DryRunActionUpdate, obj, "", c.diffToLive(ctx, obj))
+	return nil
+}
+
+// Patch records the patch of the object
+func (c *DryRunClient) Patch(ctx context.Context, obj runtime.Object, patch ctrlruntimeclient.Patch, opts ...ctrlruntimeclient.PatchOption) error {
+	c.recordWrite(DryRunActionPatch, obj, "", patchData(obj, patch))
+	return nil
+}
+
+// Delete records the deletion of the object
+func (c *DryRunClient) Delete(ctx context.Context, obj runtime.Object, opts ...ctrlruntimeclient.DeleteOption) error {
+	c.report.record(DryRunActionDelete, obj, "", nil)
+	return nil
+}
+
+// DeleteAllOf records the deletion of the objects of the given type
+func (c *DryRunClient) DeleteAllOf(ctx context.Context, obj runtime.Object, opts ...ctrlruntimeclient.DeleteAllOfOption) error {
+	c.report.record(DryRunActionDelete, obj, "", nil)
+	return nil
+}
+
+// Status returns a writer that records the status updates
+func (c *DryRunClient) Status() ctrlruntimeclient.StatusWriter {
+	return &dryRunStatusWriter{client: c}
+}
+
+// recordWrite records the change in the report and remembers the written object for later reads
+func (c *DryRunClient) recordWrite(action DryRunAction, obj runtime.Object, subresource string, diff []string) {
+	c.report.record(action, obj, subresource, diff)
+
+	metaObj, ok := obj.(metav1.Object)
+	if !ok {
+		return
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.written[writtenKey(obj, metaObj.GetNamespace(), metaObj.GetName())] = obj.DeepCopyObject()
+}
+
+// diffToLive returns the differences between the live object and the given one, the result is empty when
+// the live object can't be retrieved.
+func (c *DryRunClient) diffToLive(ctx context.Context, obj runtime.Object) []string {
+	metaObj, ok := obj.(metav1.Object)
+	if !ok {
+		return nil
+	}
+	live := obj.DeepCopyObject()
+	if err := c.Client.Get(ctx, ctrlruntimeclient.ObjectKey{Namespace: metaObj.GetNamespace(), Name: metaObj.GetName()}, live); err != nil {
+		return nil
+	}
+	return diff(live.(metav1.Object), metaObj)
+}
+
+type dryRunStatusWriter struct {
+	client *DryRunClient
+}
+
+func (w *dryRunStatusWriter) Update(ctx context.Context, obj runtime.Object, opts ...ctrlruntimeclient.UpdateOption) error {
+	w.client.recordWrite(DryRunActionUpdate, obj, "status", w.client.diffToLive(ctx, obj))
+	return nil
+}
+
+func (w *dryRunStatusWriter) Patch(ctx context.Context, obj runtime.Object, patch ctrlruntimeclient.Patch, opts ...ctrlruntimeclient.PatchOption) error {
+	w.client.recordWrite(DryRunActionPatch, obj, "status", patchData(obj, patch))
+	return nil
+}
+
+func patchData(obj runtime.Object, patch ctrlruntimeclient.Patch) []string {
+	data, err := patch.Data(obj)
+	if err != nil {
+		return []string{fmt.Sprintf("failed to compute the patch: %v", err)}
+	}
+	return []string{string(data)}
+}
+
+// writtenKey identifies a written object, typed objects are identified by their type as the
+// objects passed to Get don't have their TypeMeta set
+func writtenKey(obj runtime.Object, namespace, name string) string {
+	if u, ok := obj.(*unstructured.Unstructured); ok {
+		return fmt.Sprintf("%s/%s/%s/%s", u.GetAPIVersion(), u.GetKind(), namespace, name)
+	}
+	return fmt.Sprintf("%T/%s/%s", obj, namespace, name)
+}
+
+// kindOf returns the kind of the object, typed objects usually don't have their TypeMeta set
+// so the name of their type is used
+func kindOf(obj runtime.Object) string {
+	if kind := obj.GetObjectKind().GroupVersionKind().Kind; kind != "" {
+		return kind
+	}
+	return reflect.Indirect(reflect.ValueOf(obj)).Type().Name()
+}
@@ -0,0 +1,152 @@
+/*
+Copyright 2020 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconciling
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-test/deep"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	controllerruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	controllerruntimefake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestDryRunReconciling(t *testing.T) {
+	const testNamespace = "default"
+
+	existing := []*corev1.ConfigMap{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "outdated", Namespace: testNamespace},
+			Data:       map[string]string{"foo": "old"},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "up-to-date", Namespace: testNamespace},
+			Data:       map[string]string{"foo": "bar"},
+		},
+	}
+	client := controllerruntimefake.NewFakeClient(existing[0].DeepCopy(), existing[1].DeepCopy())
+
+	creator := func(name string) NamedConfigMapCreatorGetter {
+		return func() (string, ConfigMapCreator) {
+			return name, func(cm *corev1.ConfigMap) (*corev1.ConfigMap, error) {
+				cm.Data = map[string]string{"foo": "bar"}
+				return cm, nil
+			}
+		}
+	}
+
+	report := NewDryRunReport()
+	dryRunClient := NewDryRunClient(client, report)
+	creators := []NamedConfigMapCreatorGetter{creator("outdated"), creator("up-to-date"), creator("missing")}
+	if err := ReconcileConfigMaps(context.Background(), creators, testNamespace, dryRunClient); err != nil {
+		t.Fatalf("failed to reconcile: %v", err)
+	}
+
+	// the writes outside of the Reconcile* functions are recorded as well
+	cm := existing[1].DeepCopy()
+	oldCM := cm.DeepCopy()
+	cm.Labels = map[string]string{"app": "test"}
+	if err := dryRunClient.Patch(context.Background(), cm, controllerruntimeclient.MergeFrom(oldCM)); err != nil {
+		t.Fatalf("failed to patch: %v", err)
+	}
+
+	expectedChanges := []ObjectChange{
+		{
+			Action:    DryRunActionUpdate,
+			Kind:      "ConfigMap",
+			Namespace: testNamespace,
+			Name:      "outdated",
+			Diff:      []string{"Data.map[foo]: old != bar"},
+		},
+		{
+			Action:    DryRunActionCreate,
+			Kind:      "ConfigMap",
+			Namespace: testNamespace,
+			Name:      "missing",
+		},
+		{
+			Action:    DryRunActionPatch,
+			Kind:      "ConfigMap",
+			Namespace: testNamespace,
+			Name:      "up-to-date",
+			Diff:      []string{`{"metadata":{"labels":{"app":"test"}}}`},
+		},
+	}
+	if diff := deep.Equal(report.Changes(), expectedChanges); diff != nil {
+		t.Fatalf("unexpected changes, diff: %v", diff)
+	}
+	if summary := report.Summary(); summary != "create: 1, update: 1, patch: 1" {
+		t.Fatalf("unexpected summary %q", summary)
+	}
+
+	// nothing has been written
+	for _, expected := range existing {
+		got := &corev1.ConfigMap{}
+		if err := client.Get(context.Background(), types.NamespacedName{Namespace: testNamespace, Name: expected.Name}, got); err != nil {
+			t.Fatalf("failed to get ConfigMap: %v", err)
+		}
+		if diff := deep.Equal(got.Data, expected.Data); diff != nil || len(got.Labels) > 0 {
+			t.Fatalf("the ConfigMap %s has been changed", expected.Name)
+		}
+	}
+	if err := client.Get(context.Background(), types.NamespacedName{Namespace: testNamespace, Name: "missing"}, &corev1.ConfigMap{}); err == nil {
+		t.Fatal("the missing ConfigMap has been created")
+	}
+
+	reportDir, err := ioutil.TempDir("", "dry-run-report")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(reportDir)
+
+	if err := report.WriteFile(reportDir, "my-cluster"); err != nil {
+		t.Fatalf("failed to write the report: %v", err)
+	}
+	data, err := ioutil.ReadFile(filepath.Join(reportDir, "my-cluster.json"))
+	if err != nil {
+		t.Fatalf("failed to read the report: %v", err)
+	}
+	written := struct {
+		Name    string         `json:"name"`
+		Changes []ObjectChange `json:"changes"`
+	}{}
+	if err := json.Unmarshal(data, &written); err != nil {
+		t.Fatalf("failed to unmarshal the report: %v", err)
+	}
+	if written.Name != "my-cluster" || len(written.Changes) != len(expectedChanges) {
+		t.Fatalf("unexpected report %s", data)
+	}
+
+	files, err := ioutil.ReadDir(reportDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range files {
+		if !strings.HasSuffix(file.Name(), ".json") {
+			t.Fatalf("the temporary file %s has been left over", file.Name())
+		}
+	}
+}
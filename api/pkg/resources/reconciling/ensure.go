@@ -62,7 +62,10 @@ func createWithName(rawcreate ObjectCreator, name string) ObjectCreator {
 }
 
 // EnsureNamedObject will generate the Object with the passed create function & create or update it in Kubernetes if necessary.
+// When the client is a DryRunClient, the changes are only recorded in its report.
 func EnsureNamedObject(ctx context.Context, namespacedName types.NamespacedName, rawcreate ObjectCreator, client ctrlruntimeclient.Client, emptyObject runtime.Object, requiresRecreate bool) error {
+	dryRunClient, dryRun := client.(*DryRunClient)
+
 	// A wrapper to ensure we always set the Namespace and Name. This is useful as we call create twice
 	create := createWithNamespace(rawcreate, namespacedName.Namespace)
 	create = createWithName(create, namespacedName.Name)
@@ -82,6 +85,10 @@ func EnsureNamedObject(ctx context.Context, namespacedName types.NamespacedName,
 		if err != nil {
 			return fmt.Errorf("failed to generate object: %v", err)
 		}
+		if dryRun {
+			dryRunClient.recordWrite(DryRunActionCreate, obj, "", nil)
+			return nil
+		}
 		if err := client.Create(ctx, obj); err != nil {
 			return fmt.Errorf("failed to create %T '%s': %v", obj, namespacedName.String(), err)
 		}
@@ -107,6 +114,15 @@ func EnsureNamedObject(ctx context.Context, namespacedName types.NamespacedName,
 		return nil
 	}
 
+	if dryRun {
+		action := DryRunActionUpdate
+		if requiresRecreate {
+			action = DryRunActionRecreate
+		}
+		dryRunClient.recordWrite(action, obj, "", diff(existingObject.(metav1.Object), obj.(metav1.Object)))
+		return nil
+	}
+
 	if !requiresRecreate {
 		// We keep resetting the status here to avoid working on any outdated object
 		// and all objects are up-to-date once a reconcile process starts.
@@ -33,8 +33,10 @@ import (
 	kubermaticv1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
 	"github.com/kubermatic/kubermatic/api/pkg/resources/certificates/triple"
 
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
@@ -171,6 +173,10 @@ const (
 	KubeletClientCertificatesSecretName = "kubelet-client-certificates"
 	//ServiceAccountKeySecretName is the name for the secret containing the service account key
 	ServiceAccountKeySecretName = "service-account-key"
+	//EncryptionConfigurationSecretName is the name for the secret containing the encryption configuration of the apiserver
+	EncryptionConfigurationSecretName = "apiserver-encryption-configuration"
+	//AuditWebhookConfigSecretName is the name for the secret containing the kubeconfig of the audit webhook backend of the apiserver
+	AuditWebhookConfigSecretName = "audit-webhook-config"
 	//TokensSecretName is the name for the secret containing the user tokens
 	TokensSecretName = "tokens"
 	//ViewerTokenSecretName is the name for the secret containing the viewer token
@@ -185,6 +191,10 @@ const (
 	CloudConfigSecretName = "cloud-config"
 	//EtcdTLSCertificateSecretName is the name for the secret containing the etcd tls certificate used for transport security
 	EtcdTLSCertificateSecretName = "etcd-tls-certificate"
+	//EtcdPeerCASecretName is the name for the secret containing the CA which signs the etcd peer certificates
+	EtcdPeerCASecretName = "etcd-peer-ca"
+	//EtcdPeerTLSCertificateSecretName is the name for the secret containing the certificates the etcd members use for peer communication
+	EtcdPeerTLSCertificateSecretName = "etcd-peer-tls-certificate"
 	//ApiserverEtcdClientCertificateSecretName is the name for the secret containing the client certificate used by the apiserver for authenticating against etcd
 	ApiserverEtcdClientCertificateSecretName = "apiserver-etcd-client-certificate"
 	//ApiserverFrontProxyClientCertificateSecretName is the name for the secret containing the apiserver's client certificate for proxy auth
@@ -220,6 +230,8 @@ const (
 	PrometheusConfigConfigMapName = "prometheus"
 	//AuditConfigMapName is the name for the configmap that contains the content of the file that will be passed to the apiserver with the flag "--audit-policy-file".
 	AuditConfigMapName = "audit-config"
+	//AuditCustomPolicyConfigMapPrefix is the prefix of the names of configmaps containing a custom audit policy
+	AuditCustomPolicyConfigMapPrefix = "audit-policy-"
 
 	//PrometheusServiceAccountName is the name for the Prometheus serviceaccount
 	PrometheusServiceAccountName = "prometheus"
@@ -321,8 +333,10 @@ const (
 	// ClusterLabelKey defines the label key for the cluster name
 	ClusterLabelKey = "cluster"
 
-	// EtcdClusterSize defines the size of the etcd to use
-	EtcdClusterSize = 3
+	// DefaultEtcdClusterSize defines the size of the etcd to use if the cluster does not specify one
+	DefaultEtcdClusterSize = 3
+	// MaxEtcdClusterSize defines the maximum size of the etcd of a cluster
+	MaxEtcdClusterSize = 7
 
 	// RegistryGCR defines the kubernetes docker registry at google
 	RegistryGCR = "gcr.io"
@@ -366,6 +380,8 @@ const (
 	CAKeySecretKey = "ca.key"
 	// CACertSecretKey ca.crt
 	CACertSecretKey = "ca.crt"
+	// NextCAKeySecretKey next-ca.key holds the key of the new CA during a root CA rotation
+	NextCAKeySecretKey = "next-ca.key"
 	// ApiserverTLSKeySecretKey apiserver-tls.key
 	ApiserverTLSKeySecretKey = "apiserver-tls.key"
 	// ApiserverTLSCertSecretKey apiserver-tls.crt
@@ -378,6 +394,12 @@ const (
 	ServiceAccountKeySecretKey = "sa.key"
 	// ServiceAccountKeyPublicKey is the public key for the service account signer key
 	ServiceAccountKeyPublicKey = "sa.pub"
+	// EncryptionConfigurationSecretKey encryption-configuration.yaml
+	EncryptionConfigurationSecretKey = "encryption-configuration.yaml"
+	// AuditWebhookConfigSecretKey webhook-kubeconfig
+	AuditWebhookConfigSecretKey = "webhook-kubeconfig"
+	// AuditPolicyConfigMapKey policy.yaml
+	AuditPolicyConfigMapKey = "policy.yaml"
 	// KubeconfigSecretKey kubeconfig
 	KubeconfigSecretKey = "kubeconfig"
 	// TokensSecretKey tokens.csv
@@ -753,8 +775,10 @@ func getClusterCAFromLister(ctx context.Context, namespace, name string, client
 		return nil, nil, fmt.Errorf("got an invalid cert from the CA secret %s: %v", caSecretKey, err)
 	}
 
-	if len(certs) != 1 {
-		return nil, nil, fmt.Errorf("did not find exactly one but %v certificates in the CA secret", len(certs))
+	// During a root CA rotation the secret contains a trust bundle. The first
+	// certificate is always the one that belongs to the key.
+	if len(certs) == 0 {
+		return nil, nil, fmt.Errorf("did not find any certificate in the CA secret %s", caSecretKey)
 	}
 
 	key, err := triple.ParsePrivateKeyPEM(caSecret.Data[CAKeySecretKey])
@@ -790,6 +814,11 @@ func GetClusterFrontProxyCA(ctx context.Context, namespace string, client ctrlru
 	return getRSAClusterCAFromLister(ctx, namespace, FrontProxyCASecretName, client)
 }
 
+// GetClusterEtcdPeerCA returns the etcd peer CA of the cluster from the lister
+func GetClusterEtcdPeerCA(ctx context.Context, namespace string, client ctrlruntimeclient.Client) (*triple.KeyPair, error) {
+	return getRSAClusterCAFromLister(ctx, namespace, EtcdPeerCASecretName, client)
+}
+
 // GetOpenVPNCA returns the OpenVPN CA of the cluster from the lister
 func GetOpenVPNCA(ctx context.Context, namespace string, client ctrlruntimeclient.Client) (*ECDSAKeyPair, error) {
 	return getECDSAClusterCAFromLister(ctx, namespace, OpenVPNCASecretName, client)
@@ -837,6 +866,20 @@ func ConfigMapRevision(ctx context.Context, key types.NamespacedName, client ctr
 	return cm.ResourceVersion, nil
 }
 
+// EtcdStatefulSetReplicas returns the replicas of the etcd StatefulSet in the given namespace,
+// which is the number of members etcd currently runs with. Nil is returned if the StatefulSet
+// doesn't exist yet.
+func EtcdStatefulSetReplicas(ctx context.Context, namespace string, client ctrlruntimeclient.Client) (*int32, error) {
+	set := &appsv1.StatefulSet{}
+	if err := client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: EtcdStatefulSetName}, set); err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("could not get etcd StatefulSet: %v", err)
+	}
+	return set.Spec.Replicas, nil
+}
+
 // VolumeRevisionLabels returns a set of labels for the given volumes, with one label per
 // ConfigMap or Secret, containing the objects' revisions.
 // When used for pod template labels, this will force pods being restarted as soon as one
@@ -42,6 +42,9 @@ type TokenGenerator interface {
 type TokenAuthenticator interface {
 	// Authenticate checks given token and transform it to custom claim object
 	Authenticate(tokenData string) (*jwt.Claims, *CustomTokenClaim, error)
+	// Parse checks the signature of the given token and transforms it to custom claim object,
+	// unlike Authenticate it doesn't reject tokens that have already expired
+	Parse(tokenData string) (*jwt.Claims, *CustomTokenClaim, error)
 }
 
 // CustomTokenClaim represents authenticated user
@@ -49,19 +52,50 @@ type CustomTokenClaim struct {
 	Email     string `json:"email,omitempty"`
 	ProjectID string `json:"project_id,omitempty"`
 	TokenID   string `json:"token_id,omitempty"`
+	// Clusters restricts the token to the given clusters, empty means all clusters of the project
+	Clusters []string `json:"clusters,omitempty"`
+	// ReadOnly restricts the token to read-only requests
+	ReadOnly bool `json:"read_only,omitempty"`
 }
 
+// TokenOptions holds the lifetime and the scope of a token
+type TokenOptions struct {
+	// Expiry is the time when the token expires, zero means MaxExpiry
+	Expiry time.Time
+	// Clusters restricts the token to the given clusters
+	Clusters []string
+	// ReadOnly restricts the token to read-only requests
+	ReadOnly bool
+}
+
+// MaxExpiry returns the latest expiry a token issued now can have
+func MaxExpiry() time.Time {
+	return Now().AddDate(3, 0, 0)
+}
+
+// Claims returns claims for an unrestricted token that expires at MaxExpiry
 func Claims(email, projectID, tokenID string) (*jwt.Claims, *CustomTokenClaim) {
+	return ScopedClaims(email, projectID, tokenID, TokenOptions{})
+}
+
+// ScopedClaims returns claims for a token with the given lifetime and scope
+func ScopedClaims(email, projectID, tokenID string, options TokenOptions) (*jwt.Claims, *CustomTokenClaim) {
+	expiry := options.Expiry
+	if expiry.IsZero() {
+		expiry = MaxExpiry()
+	}
 
 	sc := &jwt.Claims{
 		IssuedAt:  jwt.NewNumericDate(Now()),
 		NotBefore: jwt.NewNumericDate(Now()),
-		Expiry:    jwt.NewNumericDate(Now().AddDate(3, 0, 0)),
+		Expiry:    jwt.NewNumericDate(expiry),
 	}
 	pc := &CustomTokenClaim{
 		Email:     email,
 		ProjectID: projectID,
 		TokenID:   tokenID,
+		Clusters:  options.Clusters,
+		ReadOnly:  options.ReadOnly,
 	}
 
 	return sc, pc
@@ -107,19 +141,11 @@ func JWTTokenAuthenticator(privateKey []byte) TokenAuthenticator {
 
 // Authenticate decrypts signed token data to CustomTokenClaim object and checks if token expired
 func (a *jwtTokenAuthenticator) Authenticate(tokenData string) (*jwt.Claims, *CustomTokenClaim, error) {
-
-	tok, err := jwt.ParseSigned(tokenData)
+	public, customClaims, err := a.Parse(tokenData)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	public := &jwt.Claims{}
-	customClaims := &CustomTokenClaim{}
-
-	if err := tok.Claims(a.key, customClaims, public); err != nil {
-		return nil, nil, err
-	}
-
 	err = public.Validate(jwt.Expected{
 		Time: Now(),
 	})
@@ -134,6 +160,23 @@ func (a *jwtTokenAuthenticator) Authenticate(tokenData string) (*jwt.Claims, *Cu
 	return public, customClaims, nil
 }
 
+// Parse decrypts signed token data to CustomTokenClaim object without checking if token expired
+func (a *jwtTokenAuthenticator) Parse(tokenData string) (*jwt.Claims, *CustomTokenClaim, error) {
+	tok, err := jwt.ParseSigned(tokenData)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	public := &jwt.Claims{}
+	customClaims := &CustomTokenClaim{}
+
+	if err := tok.Claims(a.key, customClaims, public); err != nil {
+		return nil, nil, err
+	}
+
+	return public, customClaims, nil
+}
+
 func ValidateKey(privateKey []byte) error {
 	if len(privateKey) == 0 {
 		return fmt.Errorf("the signing key can not be empty")
@@ -85,3 +85,54 @@ func formatTime(t time.Time) string {
 	return fmt.Sprintf("%d-%02d-%02d",
 		t.Year(), t.Month(), t.Day())
 }
+
+func TestScopedServiceAccountToken(t *testing.T) {
+	tokenGenerator, err := serviceaccount.JWTTokenGenerator([]byte(test.TestServiceAccountHashKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tokenAuthenticator := serviceaccount.JWTTokenAuthenticator([]byte(test.TestServiceAccountHashKey))
+
+	testcases := []struct {
+		name            string
+		options         serviceaccount.TokenOptions
+		expectedExpired bool
+	}{
+		{
+			name:    "scenario 1, token restricted to clusters and read-only requests",
+			options: serviceaccount.TokenOptions{Expiry: serviceaccount.Now().Add(time.Hour), Clusters: []string{"abcd", "efgh"}, ReadOnly: true},
+		},
+		{
+			name:            "scenario 2, expired token can be parsed but not authenticated",
+			options:         serviceaccount.TokenOptions{Expiry: serviceaccount.Now().Add(-time.Hour)},
+			expectedExpired: true,
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			token, err := tokenGenerator.Generate(serviceaccount.ScopedClaims("test@example.com", "testProject", "testToken", tc.options))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, _, err = tokenAuthenticator.Authenticate(token)
+			if tc.expectedExpired != (err != nil) {
+				t.Fatalf("expected token to be expired = %v, got error %v", tc.expectedExpired, err)
+			}
+
+			public, custom, err := tokenAuthenticator.Parse(token)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if public.Expiry.Time().Unix() != tc.options.Expiry.Unix() {
+				t.Fatalf("expected expiry %v got %v", tc.options.Expiry, public.Expiry.Time())
+			}
+			if fmt.Sprint(custom.Clusters) != fmt.Sprint(tc.options.Clusters) {
+				t.Fatalf("expected clusters %v got %v", tc.options.Clusters, custom.Clusters)
+			}
+			if custom.ReadOnly != tc.options.ReadOnly {
+				t.Fatalf("expected read-only %v got %v", tc.options.ReadOnly, custom.ReadOnly)
+			}
+		})
+	}
+}
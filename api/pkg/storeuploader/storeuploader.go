@@ -17,6 +17,7 @@ limitations under the License.
 package storeuploader
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
@@ -88,6 +89,53 @@ func (u *StoreUploader) Store(file, bucket, prefix string, createBucket bool) er
 	return err
 }
 
+// Download fetches the given object from S3 and writes it to file
+func (u *StoreUploader) Download(bucket, objectName, file string) error {
+	if len(objectName) == 0 {
+		return errors.New("object name cannot be empty")
+	}
+
+	logger := u.logger.With("bucket", bucket)
+	logger.Infow("Downloading file", "src", objectName, "dst", file)
+
+	return u.client.FGetObject(bucket, objectName, file, minio.GetObjectOptions{})
+}
+
+// ListBackups returns all revisions of all files of the given prefix, sorted from the oldest to the newest.
+// It gives up once the context is done.
+func (u *StoreUploader) ListBackups(ctx context.Context, bucket, prefix string) ([]minio.ObjectInfo, error) {
+	if len(prefix) == 0 {
+		return nil, errors.New("prefix cannot be empty")
+	}
+
+	doneCh := make(chan struct{})
+	defer close(doneCh)
+
+	var objects []minio.ObjectInfo
+	objectCh := u.client.ListObjects(bucket, fmt.Sprintf("%s-%s", prefix, prefixSeparator), true, doneCh)
+listing:
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("failed to list backups: %v", ctx.Err())
+		case object, ok := <-objectCh:
+			if !ok {
+				break listing
+			}
+			if object.Err != nil {
+				return nil, object.Err
+			}
+			objects = append(objects, object)
+		}
+	}
+
+	sort.Slice(objects, func(i, j int) bool {
+		return objects[i].LastModified.Before(objects[j].LastModified)
+	})
+
+	return objects, nil
+}
+
 // DeleteOldBackups deletes revisions of all files of the given prefix which are older than max-revisions
 func (u *StoreUploader) DeleteOldBackups(bucket, prefix string, revisionsToKeep int) error {
 	if len(prefix) == 0 {
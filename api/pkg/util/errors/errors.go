@@ -19,6 +19,8 @@ package errors
 import (
 	"fmt"
 	"net/http"
+	"strconv"
+	"time"
 )
 
 // HTTPError represents an HTTP server error.
@@ -26,6 +28,7 @@ type HTTPError struct {
 	code    int
 	msg     string
 	details []string
+	headers http.Header
 }
 
 // New creates a brand new HTTPError object
@@ -60,37 +63,53 @@ func (err HTTPError) Details() []string {
 	return err.details
 }
 
+// Headers returns the headers that must be sent along with the error
+func (err HTTPError) Headers() http.Header {
+	return err.headers
+}
+
 // NewNotFound creates a HTTP 404 error for a kind.
 func NewNotFound(kind, name string) error {
-	return HTTPError{http.StatusNotFound, fmt.Sprintf("%s %q not found", kind, name), nil}
+	return HTTPError{http.StatusNotFound, fmt.Sprintf("%s %q not found", kind, name), nil, nil}
 }
 
 // NewWrongRequest creates a HTTP 400 error, if we got a wrong request type.
 func NewWrongRequest(got, want interface{}) error {
-	return HTTPError{http.StatusBadRequest, fmt.Sprintf("Got a '%T' request - expected a '%T' request", got, want), nil}
+	return HTTPError{http.StatusBadRequest, fmt.Sprintf("Got a '%T' request - expected a '%T' request", got, want), nil, nil}
 }
 
 // NewBadRequest creates a HTTP 400 error.
 func NewBadRequest(msg string, options ...interface{}) error {
-	return HTTPError{http.StatusBadRequest, fmt.Sprintf(msg, options...), nil}
+	return HTTPError{http.StatusBadRequest, fmt.Sprintf(msg, options...), nil, nil}
 }
 
 // NewConflict creates a HTTP 409 error for a kind in a datacenter.
 func NewConflict(kind, dc, name string) error {
-	return HTTPError{http.StatusConflict, fmt.Sprintf("%s %q in dc %q already exists", kind, name, dc), nil}
+	return HTTPError{http.StatusConflict, fmt.Sprintf("%s %q in dc %q already exists", kind, name, dc), nil, nil}
 }
 
 // NewNotAuthorized creates a HTTP 401 error.
 func NewNotAuthorized() error {
-	return HTTPError{http.StatusUnauthorized, "not authorized", nil}
+	return HTTPError{http.StatusUnauthorized, "not authorized", nil, nil}
 }
 
 // NewNotImplemented creates a HTTP 501 'not implemented' error.
 func NewNotImplemented() error {
-	return HTTPError{http.StatusNotImplemented, "not implemented", nil}
+	return HTTPError{http.StatusNotImplemented, "not implemented", nil, nil}
 }
 
 // NewAlreadyExists creates a HTTP 409 already exists error
 func NewAlreadyExists(kind, name string) error {
-	return HTTPError{http.StatusConflict, fmt.Sprintf("%s %q already exists", kind, name), nil}
+	return HTTPError{http.StatusConflict, fmt.Sprintf("%s %q already exists", kind, name), nil, nil}
+}
+
+// NewTooManyRequests creates a HTTP 429 error that tells the client to retry after the given duration.
+func NewTooManyRequests(msg string, retryAfter time.Duration) error {
+	seconds := int64((retryAfter + time.Second - 1) / time.Second)
+	if seconds < 1 {
+		seconds = 1
+	}
+	headers := http.Header{}
+	headers.Set("Retry-After", strconv.FormatInt(seconds, 10))
+	return HTTPError{http.StatusTooManyRequests, msg, nil, headers}
 }
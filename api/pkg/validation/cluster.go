@@ -18,9 +18,12 @@ package validation
 
 import (
 	"context"
+	"crypto/x509"
 	"errors"
 	"fmt"
 	"net"
+	"net/url"
+	"strings"
 
 	kubermaticv1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
 	kuberneteshelper "github.com/kubermatic/kubermatic/api/pkg/kubernetes"
@@ -30,8 +33,10 @@ import (
 	"github.com/kubermatic/kubermatic/api/pkg/resources"
 
 	"github.com/coreos/locksmith/pkg/timeutil"
+	"github.com/robfig/cron"
 	"k8s.io/apimachinery/pkg/api/equality"
 	utilerror "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/validation"
 )
 
 var (
@@ -61,6 +66,18 @@ func ValidateCreateClusterSpec(spec *kubermaticv1.ClusterSpec, dc *kubermaticv1.
 		return fmt.Errorf("machine network validation failed, see: %v", err)
 	}
 
+	if err := ValidateEtcdClusterSize(spec.ComponentsOverride.Etcd.ClusterSize); err != nil {
+		return err
+	}
+
+	if err := ValidateEncryptionAtRest(spec.EncryptionAtRest, spec.Openshift != nil); err != nil {
+		return err
+	}
+
+	if err := ValidateAuditLogging(spec.AuditLogging); err != nil {
+		return fmt.Errorf("invalid audit logging settings: %v", err)
+	}
+
 	return nil
 }
 
@@ -213,6 +230,23 @@ func ValidateUpdateCluster(ctx context.Context, newCluster, oldCluster *kubermat
 		return fmt.Errorf("invalid cloud spec modification: %v", err)
 	}
 
+	if err := ValidateEtcdClusterSize(newCluster.Spec.ComponentsOverride.Etcd.ClusterSize); err != nil {
+		return err
+	}
+
+	if err := ValidateEncryptionAtRest(newCluster.Spec.EncryptionAtRest, newCluster.IsOpenshift()); err != nil {
+		return err
+	}
+
+	if err := ValidateAuditLogging(newCluster.Spec.AuditLogging); err != nil {
+		return fmt.Errorf("invalid audit logging settings: %v", err)
+	}
+
+	wasEncrypted := oldCluster.Spec.EncryptionAtRest != nil && oldCluster.Spec.EncryptionAtRest.Enabled
+	if wasEncrypted && (newCluster.Spec.EncryptionAtRest == nil || !newCluster.Spec.EncryptionAtRest.Enabled) {
+		return errors.New("disabling the encryption at rest is not allowed")
+	}
+
 	return nil
 }
 
@@ -464,3 +498,106 @@ func ValidateUpdateWindow(updateWindow *kubermaticv1.UpdateWindow) error {
 	}
 	return nil
 }
+
+// ValidateBackupPolicy validates the etcd backup policy of a cluster or datacenter. The
+// destination must be configured in the given seed.
+func ValidateBackupPolicy(policy *kubermaticv1.EtcdBackupPolicy, seed *kubermaticv1.Seed) error {
+	if policy == nil {
+		return nil
+	}
+	if policy.Schedule != "" {
+		if _, err := cron.ParseStandard(policy.Schedule); err != nil {
+			return fmt.Errorf("invalid schedule %q: %v", policy.Schedule, err)
+		}
+	}
+	if policy.Keep != nil && *policy.Keep < 1 {
+		return fmt.Errorf("keep must be at least 1, got %d", *policy.Keep)
+	}
+	if policy.Destination != "" {
+		if _, found := seed.Spec.BackupDestinations[policy.Destination]; !found {
+			return fmt.Errorf("backup destination %q does not exist", policy.Destination)
+		}
+	}
+	return nil
+}
+
+// ValidateEtcdClusterSize validates the number of etcd members requested for a cluster.
+// etcd needs an odd number of members to tolerate failures efficiently.
+func ValidateEtcdClusterSize(size *int32) error {
+	if size == nil {
+		return nil
+	}
+	if *size < 1 || *size > resources.MaxEtcdClusterSize || *size%2 == 0 {
+		return fmt.Errorf("invalid etcd cluster size %d, must be one of 1, 3, 5 or 7", *size)
+	}
+	return nil
+}
+
+// ValidateEncryptionAtRest validates the encryption at rest settings of a cluster. The
+// encryption is only supported for Kubernetes clusters.
+func ValidateEncryptionAtRest(settings *kubermaticv1.EncryptionAtRestSettings, openshift bool) error {
+	if settings == nil || !settings.Enabled {
+		return nil
+	}
+	if openshift {
+		return errors.New("encryption at rest is not supported for Openshift clusters")
+	}
+	switch settings.Provider {
+	case "", kubermaticv1.EncryptionAtRestProviderAESCBC, kubermaticv1.EncryptionAtRestProviderSecretbox:
+		return nil
+	default:
+		return fmt.Errorf("invalid encryption at rest provider %q, must be one of aescbc or secretbox", settings.Provider)
+	}
+}
+
+// ValidateAuditLogging validates the audit policy and the webhook backend of a cluster
+func ValidateAuditLogging(settings *kubermaticv1.AuditLoggingSettings) error {
+	if settings == nil {
+		return nil
+	}
+
+	if settings.PolicyPreset != "" {
+		known := false
+		for _, preset := range kubermaticv1.AuditPolicyPresets {
+			if settings.PolicyPreset == preset {
+				known = true
+			}
+		}
+		if !known {
+			return fmt.Errorf("unknown audit policy preset %q", settings.PolicyPreset)
+		}
+	}
+
+	if settings.CustomPolicyConfigMap != "" {
+		if errs := validation.IsDNS1123Subdomain(settings.CustomPolicyConfigMap); len(errs) > 0 {
+			return fmt.Errorf("invalid custom policy ConfigMap name %q: %s", settings.CustomPolicyConfigMap, strings.Join(errs, ", "))
+		}
+		// only dedicated ConfigMaps may be mounted into the apiserver, not the ones of the control plane
+		if !strings.HasPrefix(settings.CustomPolicyConfigMap, resources.AuditCustomPolicyConfigMapPrefix) {
+			return fmt.Errorf("invalid custom policy ConfigMap name %q, must start with %q", settings.CustomPolicyConfigMap, resources.AuditCustomPolicyConfigMapPrefix)
+		}
+	}
+
+	if settings.Webhook == nil {
+		return nil
+	}
+
+	webhookURL, err := url.Parse(settings.Webhook.URL)
+	if err != nil {
+		return fmt.Errorf("invalid webhook URL: %v", err)
+	}
+	if webhookURL.Scheme != "http" && webhookURL.Scheme != "https" || webhookURL.Host == "" {
+		return fmt.Errorf("invalid webhook URL %q, must be an absolute http or https URL", settings.Webhook.URL)
+	}
+
+	if settings.Webhook.CABundle != "" && !x509.NewCertPool().AppendCertsFromPEM([]byte(settings.Webhook.CABundle)) {
+		return errors.New("the webhook CA bundle does not contain any PEM encoded certificate")
+	}
+
+	switch settings.Webhook.Mode {
+	case "", kubermaticv1.AuditWebhookModeBatch, kubermaticv1.AuditWebhookModeBlocking:
+		return nil
+	default:
+		return fmt.Errorf("invalid webhook mode %q, must be one of batch or blocking", settings.Webhook.Mode)
+	}
+}
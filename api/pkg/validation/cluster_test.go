@@ -22,6 +22,8 @@ import (
 	"testing"
 
 	kubermaticv1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
+
+	utilpointer "k8s.io/utils/pointer"
 )
 
 var (
@@ -152,3 +154,199 @@ func TestValidateUpdateWindow(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateBackupPolicy(t *testing.T) {
+	seed := &kubermaticv1.Seed{
+		Spec: kubermaticv1.SeedSpec{
+			BackupDestinations: map[string]kubermaticv1.BackupDestination{
+				"offsite": {Endpoint: "s3.example.com", BucketName: "etcd-backups"},
+			},
+		},
+	}
+	tests := []struct {
+		name   string
+		policy *kubermaticv1.EtcdBackupPolicy
+		err    error
+	}{
+		{
+			name: "no backup policy",
+		},
+		{
+			name: "valid backup policy",
+			policy: &kubermaticv1.EtcdBackupPolicy{
+				Schedule:    "0 */6 * * *",
+				Keep:        utilpointer.Int32Ptr(5),
+				Destination: "offsite",
+			},
+		},
+		{
+			name: "invalid schedule",
+			policy: &kubermaticv1.EtcdBackupPolicy{
+				Schedule: "every hour",
+			},
+			err: errors.New(`invalid schedule "every hour": Expected exactly 5 fields, found 2: every hour`),
+		},
+		{
+			name: "invalid number of backups to keep",
+			policy: &kubermaticv1.EtcdBackupPolicy{
+				Keep: utilpointer.Int32Ptr(0),
+			},
+			err: errors.New("keep must be at least 1, got 0"),
+		},
+		{
+			name: "unknown destination",
+			policy: &kubermaticv1.EtcdBackupPolicy{
+				Destination: "onsite",
+			},
+			err: errors.New(`backup destination "onsite" does not exist`),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := ValidateBackupPolicy(test.policy, seed)
+			if fmt.Sprint(err) != fmt.Sprint(test.err) {
+				t.Errorf("Extected err to be %v, got %v", test.err, err)
+			}
+		})
+	}
+}
+
+func TestValidateEtcdClusterSize(t *testing.T) {
+	tests := []struct {
+		name string
+		size *int32
+		err  error
+	}{
+		{
+			name: "default size",
+		},
+		{
+			name: "single member",
+			size: utilpointer.Int32Ptr(1),
+		},
+		{
+			name: "maximum size",
+			size: utilpointer.Int32Ptr(7),
+		},
+		{
+			name: "even size",
+			size: utilpointer.Int32Ptr(4),
+			err:  errors.New("invalid etcd cluster size 4, must be one of 1, 3, 5 or 7"),
+		},
+		{
+			name: "too large",
+			size: utilpointer.Int32Ptr(9),
+			err:  errors.New("invalid etcd cluster size 9, must be one of 1, 3, 5 or 7"),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := ValidateEtcdClusterSize(test.size)
+			if fmt.Sprint(err) != fmt.Sprint(test.err) {
+				t.Errorf("Expected err to be %v, got %v", test.err, err)
+			}
+		})
+	}
+}
+
+func TestValidateEncryptionAtRest(t *testing.T) {
+	tests := []struct {
+		name      string
+		settings  *kubermaticv1.EncryptionAtRestSettings
+		openshift bool
+		err       error
+	}{
+		{
+			name: "not configured",
+		},
+		{
+			name:     "default provider",
+			settings: &kubermaticv1.EncryptionAtRestSettings{Enabled: true},
+		},
+		{
+			name:     "secretbox",
+			settings: &kubermaticv1.EncryptionAtRestSettings{Enabled: true, Provider: kubermaticv1.EncryptionAtRestProviderSecretbox},
+		},
+		{
+			name:     "unknown provider",
+			settings: &kubermaticv1.EncryptionAtRestSettings{Enabled: true, Provider: "kms"},
+			err:      errors.New(`invalid encryption at rest provider "kms", must be one of aescbc or secretbox`),
+		},
+		{
+			name:      "openshift",
+			settings:  &kubermaticv1.EncryptionAtRestSettings{Enabled: true},
+			openshift: true,
+			err:       errors.New("encryption at rest is not supported for Openshift clusters"),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := ValidateEncryptionAtRest(test.settings, test.openshift)
+			if fmt.Sprint(err) != fmt.Sprint(test.err) {
+				t.Errorf("Expected err to be %v, got %v", test.err, err)
+			}
+		})
+	}
+}
+
+func TestValidateAuditLogging(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings *kubermaticv1.AuditLoggingSettings
+		err      error
+	}{
+		{
+			name: "not configured",
+		},
+		{
+			name:     "default preset",
+			settings: &kubermaticv1.AuditLoggingSettings{Enabled: true},
+		},
+		{
+			name:     "recommended preset with webhook",
+			settings: &kubermaticv1.AuditLoggingSettings{Enabled: true, PolicyPreset: kubermaticv1.AuditPolicyPresetRecommended, Webhook: &kubermaticv1.AuditWebhookBackendSettings{URL: "https://audit.example.com/events", Mode: kubermaticv1.AuditWebhookModeBlocking}},
+		},
+		{
+			name:     "unknown preset",
+			settings: &kubermaticv1.AuditLoggingSettings{Enabled: true, PolicyPreset: "everything"},
+			err:      errors.New(`unknown audit policy preset "everything"`),
+		},
+		{
+			name:     "invalid custom policy ConfigMap",
+			settings: &kubermaticv1.AuditLoggingSettings{Enabled: true, CustomPolicyConfigMap: "Audit_Policy"},
+			err:      errors.New(`invalid custom policy ConfigMap name "Audit_Policy": a DNS-1123 subdomain must consist of lower case alphanumeric characters, '-' or '.', and must start and end with an alphanumeric character (e.g. 'example.com', regex used for validation is '[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*')`),
+		},
+		{
+			name:     "custom policy ConfigMap",
+			settings: &kubermaticv1.AuditLoggingSettings{Enabled: true, CustomPolicyConfigMap: "audit-policy-strict"},
+		},
+		{
+			name:     "custom policy ConfigMap without prefix",
+			settings: &kubermaticv1.AuditLoggingSettings{Enabled: true, CustomPolicyConfigMap: "cloud-config"},
+			err:      errors.New(`invalid custom policy ConfigMap name "cloud-config", must start with "audit-policy-"`),
+		},
+		{
+			name:     "relative webhook URL",
+			settings: &kubermaticv1.AuditLoggingSettings{Enabled: true, Webhook: &kubermaticv1.AuditWebhookBackendSettings{URL: "/events"}},
+			err:      errors.New(`invalid webhook URL "/events", must be an absolute http or https URL`),
+		},
+		{
+			name:     "invalid webhook CA bundle",
+			settings: &kubermaticv1.AuditLoggingSettings{Enabled: true, Webhook: &kubermaticv1.AuditWebhookBackendSettings{URL: "https://audit.example.com", CABundle: "not a certificate"}},
+			err:      errors.New("the webhook CA bundle does not contain any PEM encoded certificate"),
+		},
+		{
+			name:     "unknown webhook mode",
+			settings: &kubermaticv1.AuditLoggingSettings{Enabled: true, Webhook: &kubermaticv1.AuditWebhookBackendSettings{URL: "https://audit.example.com", Mode: "async"}},
+			err:      errors.New(`invalid webhook mode "async", must be one of batch or blocking`),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := ValidateAuditLogging(test.settings)
+			if fmt.Sprint(err) != fmt.Sprint(test.err) {
+				t.Errorf("Expected err to be %v, got %v", test.err, err)
+			}
+		})
+	}
+}
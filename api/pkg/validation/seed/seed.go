@@ -21,10 +21,13 @@ import (
 	"fmt"
 	"sync"
 
+	"github.com/kubermatic/kubermatic/api/pkg/addon"
 	kubermaticv1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
 	"github.com/kubermatic/kubermatic/api/pkg/provider"
+	"github.com/kubermatic/kubermatic/api/pkg/validation"
 
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -109,6 +112,9 @@ func (sv *seedValidator) validate(subject *kubermaticv1.Seed, seedClient ctrlrun
 		if providerName == "" {
 			return fmt.Errorf("datacenter %q has no provider defined", dcName)
 		}
+		if err := validation.ValidateBackupPolicy(dc.Spec.BackupPolicy, subject); err != nil {
+			return fmt.Errorf("datacenter %q has an invalid backup policy: %v", dcName, err)
+		}
 
 		if existingSeed == nil {
 			continue
@@ -125,6 +131,15 @@ func (sv *seedValidator) validate(subject *kubermaticv1.Seed, seedClient ctrlrun
 		}
 	}
 
+	// check if the pinned addon sources can be pulled
+	if !isDelete {
+		for addonName, source := range subject.Spec.AddonSources {
+			if errs := addon.ValidateSource(source, field.NewPath("spec", "addon_sources").Key(addonName)); len(errs) > 0 {
+				return fmt.Errorf("addon source %q is invalid: %v", addonName, errs.ToAggregate())
+			}
+		}
+	}
+
 	// check if there are still clusters using DCs not defined anymore
 	clusters := &kubermaticv1.ClusterList{}
 	if err := seedClient.List(sv.ctx, clusters, sv.listOpts); err != nil {
@@ -17,6 +17,7 @@ limitations under the License.
 package seed
 
 import (
+	"strings"
 	"testing"
 
 	kubermaticv1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
@@ -406,6 +407,95 @@ func TestValidate(t *testing.T) {
 			isDelete:    true,
 			errExpected: true,
 		},
+		{
+			name: "Addon sources are valid",
+			seedToValidate: &kubermaticv1.Seed{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "myseed",
+				},
+				Spec: kubermaticv1.SeedSpec{
+					AddonSources: map[string]kubermaticv1.AddonSource{
+						"csi": {
+							OCI:      &kubermaticv1.OCIAddonSource{Repository: "quay.io/kubermatic/addons/csi", Tag: "v1.0.0"},
+							Checksum: "sha256:" + strings.Repeat("a", 64),
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "Addon sources must set exactly one source",
+			seedToValidate: &kubermaticv1.Seed{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "myseed",
+				},
+				Spec: kubermaticv1.SeedSpec{
+					AddonSources: map[string]kubermaticv1.AddonSource{
+						"csi": {},
+					},
+				},
+			},
+			errExpected: true,
+		},
+		{
+			name: "Datacenter backup policies can use the backup destinations of the seed",
+			seedToValidate: &kubermaticv1.Seed{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "myseed",
+				},
+				Spec: kubermaticv1.SeedSpec{
+					Datacenters: map[string]kubermaticv1.Datacenter{
+						"dc1": {
+							Spec: kubermaticv1.DatacenterSpec{
+								Fake:         &kubermaticv1.DatacenterSpecFake{},
+								BackupPolicy: &kubermaticv1.EtcdBackupPolicy{Schedule: "@every 1h", Destination: "offsite"},
+							},
+						},
+					},
+					BackupDestinations: map[string]kubermaticv1.BackupDestination{
+						"offsite": {Endpoint: "s3.example.com", BucketName: "etcd-backups"},
+					},
+				},
+			},
+		},
+		{
+			name: "Datacenter backup policies must use an existing backup destination",
+			seedToValidate: &kubermaticv1.Seed{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "myseed",
+				},
+				Spec: kubermaticv1.SeedSpec{
+					Datacenters: map[string]kubermaticv1.Datacenter{
+						"dc1": {
+							Spec: kubermaticv1.DatacenterSpec{
+								Fake:         &kubermaticv1.DatacenterSpecFake{},
+								BackupPolicy: &kubermaticv1.EtcdBackupPolicy{Destination: "does-not-exist"},
+							},
+						},
+					},
+				},
+			},
+			errExpected: true,
+		},
+		{
+			name: "Datacenter backup policies must have a valid schedule",
+			seedToValidate: &kubermaticv1.Seed{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "myseed",
+				},
+				Spec: kubermaticv1.SeedSpec{
+					Datacenters: map[string]kubermaticv1.Datacenter{
+						"dc1": {
+							Spec: kubermaticv1.DatacenterSpec{
+								Fake:         &kubermaticv1.DatacenterSpecFake{},
+								BackupPolicy: &kubermaticv1.EtcdBackupPolicy{Schedule: "every hour"},
+							},
+						},
+					},
+				},
+			},
+			errExpected: true,
+		},
 	}
 
 	for _, tc := range testCases {
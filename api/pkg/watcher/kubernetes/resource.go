@@ -0,0 +1,141 @@
+/*
+Copyright 2020 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"time"
+
+	v1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
+	"github.com/kubermatic/kubermatic/api/pkg/log"
+	"github.com/kubermatic/kubermatic/api/pkg/provider"
+
+	"code.cloudfoundry.org/go-pubsub"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// restartPeriod is the time to wait before a failed or closed watch gets restarted
+const restartPeriod = time.Second
+
+// WatchFunc starts a watch on the resources published by a ResourceWatcher.
+type WatchFunc func() (watch.Interface, error)
+
+// ProjectFunc returns the ID of the project the given object belongs to.
+type ProjectFunc func(obj runtime.Object) string
+
+// ResourceWatcher watches resources and notifies its subscribers about any changes. Every subscription
+// belongs to a user and only receives the objects of projects the user has access to. The same rules as
+// in the REST API apply: admins can access all projects, everybody else only the projects they are a member of.
+type ResourceWatcher struct {
+	watchFunc         WatchFunc
+	projectFunc       ProjectFunc
+	userProjectMapper provider.ProjectMemberMapper
+	publisher         *pubsub.PubSub
+}
+
+// NewResourceWatcher returns a new resource watcher. The watch is started by Run.
+func NewResourceWatcher(watchFunc WatchFunc, projectFunc ProjectFunc, userProjectMapper provider.ProjectMemberMapper) *ResourceWatcher {
+	return &ResourceWatcher{
+		watchFunc:         watchFunc,
+		projectFunc:       projectFunc,
+		userProjectMapper: userProjectMapper,
+		publisher:         pubsub.New(),
+	}
+}
+
+// Run publishes the changes of the watched resources until the stop channel is closed. The watch will
+// restart itself if any error occurs.
+func (watcher *ResourceWatcher) Run(stopCh <-chan struct{}) {
+	wait.Until(func() {
+		watcher.run(stopCh)
+	}, restartPeriod, stopCh)
+}
+
+func (watcher *ResourceWatcher) run(stopCh <-chan struct{}) {
+	w, err := watcher.watchFunc()
+	if err != nil {
+		log.Logger.Debugf("could not create resource watcher: %v", err)
+		return
+	}
+	defer w.Stop()
+
+	for {
+		select {
+		case <-stopCh:
+			return
+		case event, ok := <-w.ResultChan():
+			if !ok {
+				log.Logger.Debug("restarting resource watcher")
+				return
+			}
+
+			switch event.Type {
+			case watch.Added, watch.Modified, watch.Deleted:
+				watcher.publisher.Publish(event, pubsub.LinearTreeTraverser([]uint64{}))
+			case watch.Error:
+				log.Logger.Debugf("restarting resource watcher after error: %v", event.Object)
+				return
+			}
+		}
+	}
+}
+
+// Subscribe allows to register a subscription handler which will be invoked with the watch.Event of each change
+// of an object the given user has access to.
+func (watcher *ResourceWatcher) Subscribe(user *v1.User, subscription pubsub.Subscription) pubsub.Unsubscriber {
+	return watcher.publisher.Subscribe(func(data interface{}) {
+		event, ok := data.(watch.Event)
+		if !ok || !watcher.hasAccess(user, event.Object) {
+			return
+		}
+
+		subscription(event)
+	})
+}
+
+func (watcher *ResourceWatcher) hasAccess(user *v1.User, obj runtime.Object) bool {
+	if user.Spec.IsAdmin {
+		return true
+	}
+
+	projectID := watcher.projectFunc(obj)
+	if projectID == "" {
+		return false
+	}
+
+	_, err := watcher.userProjectMapper.MapUserToGroup(user.Spec.Email, projectID)
+	return err == nil
+}
+
+// ProjectFromLabel is a ProjectFunc for resources which are labeled with the ID of their project, like clusters.
+func ProjectFromLabel(obj runtime.Object) string {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return ""
+	}
+
+	return accessor.GetLabels()[v1.ProjectIDLabelKey]
+}
+
+// FixedProject returns a ProjectFunc for resources which all belong to the given project, like the events of a cluster.
+func FixedProject(projectID string) ProjectFunc {
+	return func(runtime.Object) string {
+		return projectID
+	}
+}
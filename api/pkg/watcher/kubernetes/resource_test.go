@@ -0,0 +1,142 @@
+/*
+Copyright 2020 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"testing"
+	"time"
+
+	v1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
+	"github.com/kubermatic/kubermatic/api/pkg/provider/kubernetes"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes/scheme"
+	restclient "k8s.io/client-go/rest"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestResourceWatcher(t *testing.T) {
+	genCluster := func(name, projectID string) *v1.Cluster {
+		return &v1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   name,
+				Labels: map[string]string{v1.ProjectIDLabelKey: projectID},
+			},
+		}
+	}
+	genUser := func(email string, isAdmin bool) *v1.User {
+		user := &v1.User{}
+		user.Spec.Email = email
+		user.Spec.IsAdmin = isAdmin
+		return user
+	}
+
+	testCases := []struct {
+		name             string
+		user             *v1.User
+		projectFunc      ProjectFunc
+		expectedClusters []string
+	}{
+		{
+			name:             "member only receives the clusters of its projects",
+			user:             genUser("bob@acme.com", false),
+			projectFunc:      ProjectFromLabel,
+			expectedClusters: []string{"bob-cluster"},
+		},
+		{
+			name:             "admin receives all clusters",
+			user:             genUser("admin@acme.com", true),
+			projectFunc:      ProjectFromLabel,
+			expectedClusters: []string{"alice-cluster", "bob-cluster"},
+		},
+		{
+			name:             "user without projects receives no clusters",
+			user:             genUser("john@acme.com", false),
+			projectFunc:      ProjectFromLabel,
+			expectedClusters: nil,
+		},
+		{
+			name:             "member receives all resources of a fixed project",
+			user:             genUser("bob@acme.com", false),
+			projectFunc:      FixedProject("bob-project"),
+			expectedClusters: []string{"alice-cluster", "bob-cluster"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			binding := &v1.UserProjectBinding{
+				ObjectMeta: metav1.ObjectMeta{Name: "bob-binding"},
+				Spec: v1.UserProjectBindingSpec{
+					UserEmail: "bob@acme.com",
+					ProjectID: "bob-project",
+					Group:     "owners-bob-project",
+				},
+			}
+			fakeClient := fakectrlruntimeclient.NewFakeClientWithScheme(scheme.Scheme, []runtime.Object{binding}...)
+			fakeImpersonationClient := func(impCfg restclient.ImpersonationConfig) (ctrlruntimeclient.Client, error) {
+				return fakeClient, nil
+			}
+			userProjectMapper := kubernetes.NewProjectMemberProvider(fakeImpersonationClient, fakeClient, kubernetes.IsServiceAccount)
+
+			fakeWatcher := watch.NewFakeWithChanSize(3, false)
+			fakeWatcher.Add(genCluster("alice-cluster", "alice-project"))
+			fakeWatcher.Add(genCluster("bob-cluster", "bob-project"))
+			// the events are published in order, so once the last one got published all others did too
+			fakeWatcher.Add(genCluster("last-cluster", ""))
+
+			resourceWatcher := NewResourceWatcher(func() (watch.Interface, error) {
+				return fakeWatcher, nil
+			}, tc.projectFunc, userProjectMapper)
+
+			var clusters []string
+			resourceWatcher.Subscribe(tc.user, func(data interface{}) {
+				if name := data.(watch.Event).Object.(*v1.Cluster).Name; name != "last-cluster" {
+					clusters = append(clusters, name)
+				}
+			})
+			published := make(chan struct{})
+			resourceWatcher.Subscribe(genUser("admin@acme.com", true), func(data interface{}) {
+				if data.(watch.Event).Object.(*v1.Cluster).Name == "last-cluster" {
+					close(published)
+				}
+			})
+
+			stopCh := make(chan struct{})
+			defer close(stopCh)
+			go resourceWatcher.Run(stopCh)
+
+			select {
+			case <-published:
+			case <-time.After(10 * time.Second):
+				t.Fatal("timed out waiting for the clusters to be published")
+			}
+
+			if len(clusters) != len(tc.expectedClusters) {
+				t.Fatalf("expected clusters %v, got %v", tc.expectedClusters, clusters)
+			}
+			for i := range clusters {
+				if clusters[i] != tc.expectedClusters[i] {
+					t.Fatalf("expected clusters %v, got %v", tc.expectedClusters, clusters)
+				}
+			}
+		})
+	}
+}
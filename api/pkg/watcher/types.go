@@ -17,6 +17,7 @@ limitations under the License.
 package watcher
 
 import (
+	v1 "github.com/kubermatic/kubermatic/api/pkg/crd/kubermatic/v1"
 	"github.com/kubermatic/kubermatic/api/pkg/provider"
 
 	"code.cloudfoundry.org/go-pubsub"
@@ -30,3 +31,9 @@ type Providers struct {
 type SettingsWatcher interface {
 	Subscribe(subscription pubsub.Subscription)
 }
+
+// ResourceWatcher notifies its subscribers about changes of the resources the subscribing user has access to.
+type ResourceWatcher interface {
+	Run(stopCh <-chan struct{})
+	Subscribe(user *v1.User, subscription pubsub.Subscription) pubsub.Unsubscriber
+}